@@ -10,19 +10,58 @@ import (
 	"time"
 
 	httpHandler "hotel-booking-system/internal/booking/delivery/http"
+	"hotel-booking-system/internal/booking/domain"
 	"hotel-booking-system/internal/booking/repository"
 	"hotel-booking-system/internal/booking/usecase"
+	"hotel-booking-system/internal/booking/worker"
 	"hotel-booking-system/pkg/database"
 	"hotel-booking-system/pkg/hotelclient"
 	"hotel-booking-system/pkg/httpclient"
+	"hotel-booking-system/pkg/idempotency"
 	"hotel-booking-system/pkg/kafka"
 	"hotel-booking-system/pkg/logger"
 	"hotel-booking-system/pkg/tracing"
+	"hotel-booking-system/pkg/webhook"
 
 	"github.com/joho/godotenv"
 	"github.com/prometheus/client_golang/prometheus/promhttp"
 )
 
+const (
+	outboxPollInterval    = time.Second
+	outboxBatchSize       = 100
+	sagaResumeInterval    = time.Minute
+	sagaStaleAfter        = time.Minute
+	idempotencyTimeout    = time.Minute
+	idempotencyTTL        = 24 * time.Hour
+	idempotencyCleanup    = time.Hour
+	defaultHoldTTL        = 15 * time.Minute
+	holdReapInterval      = 10 * time.Second
+	holdReapBatchSize     = 100
+	bookingReapInterval   = time.Minute
+	bookingReapBatchSize  = 100
+	defaultPaymentTimeout = 30 * time.Minute
+	noShowInterval        = 24 * time.Hour
+	defaultNoShowAfter    = 30 * time.Hour
+	defaultOfferTTL       = 2 * time.Hour
+	webhookTolerance      = 5 * time.Minute
+)
+
+// topicEnv names the variable that configures the Kafka topic of each event
+// type the booking service publishes.
+var topicEnv = map[string]string{
+	domain.EventBookingCreated:     "KAFKA_TOPIC_BOOKING_CREATED",
+	domain.EventBookingCancelled:   "KAFKA_TOPIC_BOOKING_CANCELLED",
+	domain.EventBookingModified:    "KAFKA_TOPIC_BOOKING_MODIFIED",
+	domain.EventBookingHoldExpired: "KAFKA_TOPIC_BOOKING_HOLD_EXPIRED",
+	domain.EventBookingCheckedIn:   "KAFKA_TOPIC_BOOKING_CHECKED_IN",
+	domain.EventBookingCheckedOut:  "KAFKA_TOPIC_BOOKING_CHECKED_OUT",
+	domain.EventBookingNoShow:      "KAFKA_TOPIC_BOOKING_NO_SHOW",
+	domain.EventBookingExpired:     "KAFKA_TOPIC_BOOKING_EXPIRED",
+	domain.EventReservationCreated: "KAFKA_TOPIC_RESERVATION_CREATED",
+	domain.EventWaitlistOffered:    "KAFKA_TOPIC_WAITLIST_OFFERED",
+}
+
 func main() {
 	godotenv.Load()
 
@@ -61,21 +100,104 @@ func main() {
 	producer := kafka.NewProducer(brokers, os.Getenv("KAFKA_TOPIC_BOOKING_CREATED"))
 	defer producer.Close()
 
+	topics := domain.Topics{}
+	for eventType, env := range topicEnv {
+		topic := os.Getenv(env)
+		if topic == "" {
+			log.Fatalf("%s must be set", env)
+		}
+		topics[eventType] = topic
+	}
+
+	captureMode := os.Getenv("PAYMENT_CAPTURE_MODE")
+	switch captureMode {
+	case "":
+		captureMode = "automatic"
+	case "automatic", "manual":
+	default:
+		log.Fatalf("unknown PAYMENT_CAPTURE_MODE %q", captureMode)
+	}
+
 	var paymentClient usecase.PaymentClient
 	paymentServiceURL := os.Getenv("PAYMENT_SERVICE_URL")
 	if paymentServiceURL != "" {
 		paymentHTTPClient := httpclient.NewPaymentClient(paymentServiceURL)
 		var paymentClientInterface usecase.PaymentClientInterface = paymentHTTPClient
-		paymentClient = usecase.NewPaymentClientAdapter(paymentClientInterface)
+		paymentClient = usecase.NewPaymentClientAdapter(paymentClientInterface, captureMode)
+	}
+
+	holdTTL := defaultHoldTTL
+	if value := os.Getenv("BOOKING_HOLD_TTL"); value != "" {
+		holdTTL, err = time.ParseDuration(value)
+		if err != nil {
+			log.WithError(err).Fatal("invalid BOOKING_HOLD_TTL")
+		}
 	}
 
-	bookingUseCase := usecase.NewBookingUseCase(bookingRepo, hotelClient, producer, paymentClient)
+	paymentTimeout := defaultPaymentTimeout
+	if value := os.Getenv("BOOKING_PAYMENT_TIMEOUT"); value != "" {
+		paymentTimeout, err = time.ParseDuration(value)
+		if err != nil {
+			log.WithError(err).Fatal("invalid BOOKING_PAYMENT_TIMEOUT")
+		}
+	}
+
+	noShowAfter := defaultNoShowAfter
+	if value := os.Getenv("BOOKING_NO_SHOW_AFTER"); value != "" {
+		noShowAfter, err = time.ParseDuration(value)
+		if err != nil {
+			log.WithError(err).Fatal("invalid BOOKING_NO_SHOW_AFTER")
+		}
+	}
+
+	offerTTL := defaultOfferTTL
+	if value := os.Getenv("BOOKING_WAITLIST_OFFER_TTL"); value != "" {
+		offerTTL, err = time.ParseDuration(value)
+		if err != nil {
+			log.WithError(err).Fatal("invalid BOOKING_WAITLIST_OFFER_TTL")
+		}
+	}
+
+	bookingUseCase := usecase.NewBookingUseCase(bookingRepo, repository.NewPostgresSagaRepository(db),
+		repository.NewPostgresHoldRepository(db), hotelClient, paymentClient, repository.NewPostgresRateRepository(db),
+		repository.NewPostgresPromotionRepository(db), repository.NewPostgresWaitlistRepository(db), holdTTL, offerTTL, topics)
+
+	waitlistTopics := []string{
+		topics.Topic(domain.EventBookingCancelled),
+		topics.Topic(domain.EventBookingHoldExpired),
+		topics.Topic(domain.EventBookingExpired),
+	}
+	waitlistReader := kafka.NewGroupConsumer(brokers, waitlistTopics, os.Getenv("KAFKA_WAITLIST_GROUP_ID"))
+	defer waitlistReader.Close()
+
+	workerCtx, stopWorkers := context.WithCancel(context.Background())
+	defer stopWorkers()
+	outboxRelay := worker.NewOutboxRelay(repository.NewPostgresOutboxRepository(db), producer, outboxPollInterval, outboxBatchSize)
+	go outboxRelay.Run(workerCtx)
+	sagaResumer := worker.NewSagaResumer(bookingUseCase, sagaResumeInterval, sagaStaleAfter)
+	go sagaResumer.Run(workerCtx)
+	holdReaper := worker.NewHoldReaper(bookingUseCase, holdReapInterval, holdReapBatchSize)
+	go holdReaper.Run(workerCtx)
+	bookingReaper := worker.NewBookingReaper(bookingUseCase, bookingReapInterval, paymentTimeout, bookingReapBatchSize)
+	go bookingReaper.Run(workerCtx)
+	noShowMarker := worker.NewNoShowMarker(bookingUseCase, noShowInterval, noShowAfter)
+	go noShowMarker.Run(workerCtx)
+	waitlistConsumer := worker.NewWaitlistConsumer(waitlistReader, bookingUseCase)
+	go waitlistConsumer.Run(workerCtx)
+	idempotencyStore := idempotency.NewPostgresStore(db, idempotencyTimeout)
+	go idempotency.Cleanup(workerCtx, idempotencyStore, idempotencyCleanup, idempotencyTTL)
+
+	webhookSecrets := strings.Split(os.Getenv("WEBHOOK_SECRETS"), ",")
+	if strings.TrimSpace(webhookSecrets[0]) == "" {
+		log.Fatal("WEBHOOK_SECRETS must be set")
+	}
+	webhookVerifier := webhook.NewVerifier(webhookSecrets, webhookTolerance)
 
 	httpPort := os.Getenv("BOOKING_SERVICE_PORT")
 
 	go func() {
 		handler := httpHandler.NewBookingHandler(bookingUseCase)
-		router := httpHandler.SetupRoutes(handler)
+		router := httpHandler.SetupRoutes(handler, idempotencyStore, webhookVerifier)
 
 		log.Infof("starting HTTP server on port %s", httpPort)
 		if err := http.ListenAndServe(":"+httpPort, router); err != nil {
@@ -95,6 +217,7 @@ func main() {
 	<-quit
 
 	log.Info("shutting down booking service")
+	stopWorkers()
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
 	_ = ctx
@@ -12,6 +12,7 @@ import (
 	"hotel-booking-system/internal/hotel/repository"
 	"hotel-booking-system/internal/hotel/usecase"
 	"hotel-booking-system/pkg/database"
+	"hotel-booking-system/pkg/httpclient"
 	"hotel-booking-system/pkg/logger"
 	"hotel-booking-system/pkg/tracing"
 
@@ -47,7 +48,17 @@ func main() {
 
 	hotelRepo := repository.NewPostgresHotelRepository(db)
 	roomRepo := repository.NewPostgresRoomRepository(db)
-	hotelUseCase := usecase.NewHotelUseCase(hotelRepo, roomRepo)
+	ratePlanRepo := repository.NewPostgresRatePlanRepository(db)
+	feeRuleRepo := repository.NewPostgresFeeRuleRepository(db)
+	cancellationPolicyRepo := repository.NewPostgresCancellationPolicyRepository(db)
+
+	bookingServiceURL := os.Getenv("BOOKING_SERVICE_URL")
+	if bookingServiceURL == "" {
+		bookingServiceURL = "http://booking-service:8082"
+	}
+	bookingClient := httpclient.NewBookingHTTPClient(bookingServiceURL)
+
+	hotelUseCase := usecase.NewHotelUseCase(hotelRepo, roomRepo, ratePlanRepo, feeRuleRepo, cancellationPolicyRepo, bookingClient)
 
 	httpPort := os.Getenv("HOTEL_SERVICE_PORT")
 
@@ -51,11 +51,14 @@ func main() {
 	notificationService := service.NewNotificationService(deliveryClientInterface, hotelClientInterface)
 
 	brokers := strings.Split(os.Getenv("KAFKA_BROKERS"), ",")
-	consumer := kafka.NewConsumer(
-		brokers,
+	topics := []string{
 		os.Getenv("KAFKA_TOPIC_BOOKING_CREATED"),
-		os.Getenv("KAFKA_GROUP_ID"),
-	)
+		os.Getenv("KAFKA_TOPIC_BOOKING_CANCELLED"),
+		os.Getenv("KAFKA_TOPIC_BOOKING_MODIFIED"),
+		os.Getenv("KAFKA_TOPIC_RESERVATION_CREATED"),
+		os.Getenv("KAFKA_TOPIC_WAITLIST_OFFERED"),
+	}
+	consumer := kafka.NewGroupConsumer(brokers, topics, os.Getenv("KAFKA_GROUP_ID"))
 	defer consumer.Close()
 
 	go func() {
@@ -77,7 +80,42 @@ func main() {
 				return err
 			}
 
-			log.WithField("booking_id", event.BookingID).Info("received booking event")
+			if event.EventType == domain.EventReservationCreated {
+				var reservationEvent domain.ReservationEvent
+				if err := kafka.UnmarshalMessage(data, &reservationEvent); err != nil {
+					log.WithError(err).Error("failed to unmarshal reservation event")
+					return err
+				}
+
+				log.WithField("reservation_id", reservationEvent.ReservationID).Info("received reservation event")
+
+				if err := notificationService.ProcessReservationEvent(ctx, reservationEvent); err != nil {
+					log.WithError(err).Error("failed to process reservation event")
+				}
+
+				return nil
+			}
+
+			if event.EventType == domain.EventWaitlistOffered {
+				var waitlistEvent domain.WaitlistEvent
+				if err := kafka.UnmarshalMessage(data, &waitlistEvent); err != nil {
+					log.WithError(err).Error("failed to unmarshal waitlist event")
+					return err
+				}
+
+				log.WithField("entry_id", waitlistEvent.EntryID).Info("received waitlist event")
+
+				if err := notificationService.ProcessWaitlistEvent(ctx, waitlistEvent); err != nil {
+					log.WithError(err).Error("failed to process waitlist event")
+				}
+
+				return nil
+			}
+
+			log.WithFields(map[string]interface{}{
+				"booking_id": event.BookingID,
+				"event_type": event.EventType,
+			}).Info("received booking event")
 
 			if err := notificationService.ProcessBookingEvent(ctx, event); err != nil {
 				log.WithError(err).Error("failed to process booking event")
@@ -9,14 +9,43 @@ import (
 	"time"
 
 	httpHandler "hotel-booking-system/internal/payment/delivery/http"
+	"hotel-booking-system/internal/payment/domain"
+	"hotel-booking-system/internal/payment/gateway"
+	"hotel-booking-system/internal/payment/repository"
 	"hotel-booking-system/internal/payment/service"
+	"hotel-booking-system/internal/payment/worker"
+	"hotel-booking-system/pkg/database"
+	"hotel-booking-system/pkg/idempotency"
 	"hotel-booking-system/pkg/logger"
 	"hotel-booking-system/pkg/tracing"
+	"hotel-booking-system/pkg/webhook"
 
 	"github.com/joho/godotenv"
 	"github.com/prometheus/client_golang/prometheus/promhttp"
 )
 
+const (
+	idempotencyTimeout     = time.Minute
+	idempotencyTTL         = 24 * time.Hour
+	idempotencyCleanup     = time.Hour
+	webhookTimeout         = 10 * time.Second
+	webhookPollInterval    = time.Second
+	webhookBatchSize       = 50
+	webhookMaxAttempts     = 10
+	webhookBaseDelay       = 5 * time.Second
+	webhookMaxDelay        = 30 * time.Minute
+	webhookClaimTimeout    = 15 * time.Minute
+	fakeGatewayLatency     = 2 * time.Second
+	fakeGatewayTimeout     = 10 * time.Second
+	fakeGatewaySettle      = 5 * time.Second
+	defaultAuthTTL         = 7 * 24 * time.Hour
+	authReapInterval       = time.Minute
+	authReapBatchSize      = 100
+	paymentResumeInterval  = time.Minute
+	paymentStaleAfter      = 5 * time.Minute
+	paymentResumeBatchSize = 50
+)
+
 func main() {
 	godotenv.Load()
 
@@ -29,14 +58,68 @@ func main() {
 	}
 	defer tracing.Shutdown(context.Background(), tp)
 
+	dbCfg := database.Config{
+		Host:     os.Getenv("PAYMENT_DB_HOST"),
+		Port:     os.Getenv("PAYMENT_DB_PORT"),
+		User:     os.Getenv("PAYMENT_DB_USER"),
+		Password: os.Getenv("PAYMENT_DB_PASSWORD"),
+		DBName:   os.Getenv("PAYMENT_DB_NAME"),
+	}
+
+	db, err := database.NewPostgresConnection(dbCfg)
+	if err != nil {
+		log.WithError(err).Fatal("failed to connect to database")
+	}
+	defer db.Close()
+
 	webhookURL := os.Getenv("BOOKING_WEBHOOK_URL")
 	if webhookURL == "" {
 		webhookURL = "http://booking-service:8082/api/webhooks/payment"
 	}
 
-	paymentService := service.NewPaymentService(webhookURL)
+	webhookSecret := os.Getenv("WEBHOOK_SECRET")
+	if webhookSecret == "" {
+		log.Fatal("WEBHOOK_SECRET must be set")
+	}
+
+	var paymentGateway domain.Gateway
+	switch provider := os.Getenv("PAYMENT_GATEWAY"); provider {
+	case "", "fake":
+		paymentGateway = gateway.NewFakeGateway(gateway.FakeConfig{
+			Latency:         fakeGatewayLatency,
+			Timeout:         fakeGatewayTimeout,
+			SettlementDelay: fakeGatewaySettle,
+		})
+	default:
+		log.Fatalf("unknown PAYMENT_GATEWAY %q", provider)
+	}
+
+	authorizationTTL := defaultAuthTTL
+	if value := os.Getenv("PAYMENT_AUTHORIZATION_TTL"); value != "" {
+		authorizationTTL, err = time.ParseDuration(value)
+		if err != nil {
+			log.WithError(err).Fatal("invalid PAYMENT_AUTHORIZATION_TTL")
+		}
+	}
+
+	webhookRepo := repository.NewPostgresWebhookRepository(db)
+	paymentService := service.NewPaymentService(repository.NewPostgresPaymentRepository(db), webhookRepo, paymentGateway, authorizationTTL)
 	handler := httpHandler.NewPaymentHandler(paymentService)
-	router := httpHandler.SetupRoutes(handler)
+	idempotencyStore := idempotency.NewPostgresStore(db, idempotencyTimeout)
+	router := httpHandler.SetupRoutes(handler, idempotencyStore)
+
+	workerCtx, stopWorkers := context.WithCancel(context.Background())
+	defer stopWorkers()
+	dispatcher := worker.NewWebhookDispatcher(webhookRepo,
+		webhook.NewSender(webhookURL, webhook.NewSigner(webhookSecret), webhookTimeout),
+		worker.RetryPolicy{MaxAttempts: webhookMaxAttempts, BaseDelay: webhookBaseDelay, MaxDelay: webhookMaxDelay, ClaimTimeout: webhookClaimTimeout},
+		webhookPollInterval, webhookBatchSize)
+	go dispatcher.Run(workerCtx)
+	authorizationReaper := worker.NewAuthorizationReaper(paymentService, authReapInterval, authReapBatchSize)
+	go authorizationReaper.Run(workerCtx)
+	paymentResumer := worker.NewPaymentResumer(paymentService, paymentResumeInterval, paymentStaleAfter, paymentResumeBatchSize)
+	go paymentResumer.Run(workerCtx)
+	go idempotency.Cleanup(workerCtx, idempotencyStore, idempotencyCleanup, idempotencyTTL)
 
 	httpPort := os.Getenv("PAYMENT_SERVICE_PORT")
 	if httpPort == "" {
@@ -67,6 +150,7 @@ func main() {
 	<-quit
 
 	log.Info("shutting down payment service")
+	stopWorkers()
 
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
@@ -11,6 +11,7 @@ import (
 	"hotel-booking-system/internal/hotel/repository"
 	"hotel-booking-system/pkg/database"
 	"hotel-booking-system/pkg/logger"
+	"hotel-booking-system/pkg/money"
 
 	"github.com/google/uuid"
 	"github.com/joho/godotenv"
@@ -70,9 +71,15 @@ func main() {
 	}
 
 	roomTypes := []string{"Стандарт", "Улучшенный", "Люкс", "Делюкс", "Президентский люкс"}
-	basePrices := []float64{3000, 5000, 8000, 12000, 25000}
+	basePrices := []money.Money{
+		money.New(300000, "RUB"), money.New(500000, "RUB"), money.New(800000, "RUB"),
+		money.New(1200000, "RUB"), money.New(2500000, "RUB"),
+	}
+	extraAdultPrice := money.New(150000, "RUB")
+	extraChildPrice := money.New(75000, "RUB")
 
 	for _, hotel := range hotels {
+		hotel.Currency = money.DefaultCurrency
 		if err := hotelRepo.CreateHotel(ctx, &hotel); err != nil {
 			log.WithError(err).Errorf("failed to create hotel %s", hotel.Name)
 			continue
@@ -91,6 +98,11 @@ func main() {
 					Description:   fmt.Sprintf("Номер типа %s на %d этаже", roomType, i),
 					IsAvailable:   true,
 				}
+				if room.Capacity > 2 {
+					room.BaseOccupancy = 2
+					room.ExtraAdultPrice = &extraAdultPrice
+					room.ExtraChildPrice = &extraChildPrice
+				}
 
 				if err := roomRepo.CreateRoom(ctx, &room); err != nil {
 					log.WithError(err).Errorf("failed to create room %s", room.RoomNumber)
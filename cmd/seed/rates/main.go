@@ -0,0 +1,102 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"encoding/csv"
+	"os"
+	"time"
+
+	"hotel-booking-system/internal/booking/repository"
+	"hotel-booking-system/pkg/database"
+	"hotel-booking-system/pkg/logger"
+	"hotel-booking-system/pkg/money"
+
+	"github.com/joho/godotenv"
+)
+
+const defaultRatesFile = "cmd/seed/rates/rates.csv"
+
+// Loads exchange rates into the booking database from a CSV file with
+// base_currency,quote_currency,rate,effective_from rows. The file is taken
+// from the first argument, EXCHANGE_RATES_FILE or defaultRatesFile.
+func main() {
+	godotenv.Load()
+	logger.Init("info")
+	log := logger.GetLogger()
+
+	path := os.Getenv("EXCHANGE_RATES_FILE")
+	if len(os.Args) > 1 {
+		path = os.Args[1]
+	}
+	if path == "" {
+		path = defaultRatesFile
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		log.WithError(err).Fatal("failed to open rates file")
+	}
+	defer file.Close()
+
+	reader := csv.NewReader(file)
+	reader.Comment = '#'
+	records, err := reader.ReadAll()
+	if err != nil {
+		log.WithError(err).Fatal("failed to read rates file")
+	}
+
+	dbCfg := database.Config{
+		Host:     os.Getenv("BOOKING_DB_HOST"),
+		Port:     os.Getenv("BOOKING_DB_PORT"),
+		User:     os.Getenv("BOOKING_DB_USER"),
+		Password: os.Getenv("BOOKING_DB_PASSWORD"),
+		DBName:   os.Getenv("BOOKING_DB_NAME"),
+	}
+
+	log.Infof("connecting to database: %s:%s", dbCfg.Host, dbCfg.Port)
+
+	var db *sql.DB
+	for i := 0; i < 10; i++ {
+		db, err = database.NewPostgresConnection(dbCfg)
+		if err == nil {
+			break
+		}
+		log.WithError(err).Warnf("failed to connect to database, retry %d/10", i+1)
+		time.Sleep(2 * time.Second)
+	}
+
+	if err != nil {
+		log.WithError(err).Fatal("failed to connect to database after retries")
+	}
+	defer db.Close()
+
+	rateRepo := repository.NewPostgresRateRepository(db)
+	ctx := context.Background()
+
+	loaded := 0
+	for i, record := range records {
+		if len(record) != 4 {
+			log.Errorf("line %d: expected 4 fields, got %d", i+1, len(record))
+			continue
+		}
+		rate, err := money.ParseRate(record[0], record[1], record[2])
+		if err != nil {
+			log.WithError(err).Errorf("line %d: invalid rate", i+1)
+			continue
+		}
+		effectiveFrom, err := time.Parse("2006-01-02", record[3])
+		if err != nil {
+			log.WithError(err).Errorf("line %d: invalid effective date", i+1)
+			continue
+		}
+
+		if err := rateRepo.SaveRate(ctx, rate, effectiveFrom); err != nil {
+			log.WithError(err).Errorf("failed to save rate %s", rate)
+			continue
+		}
+		loaded++
+	}
+
+	log.Infof("loaded %d exchange rates", loaded)
+}
@@ -1,17 +1,23 @@
 package http
 
 import (
+	"database/sql"
 	"encoding/json"
+	"errors"
 	"net/http"
+	"strconv"
 	"time"
 
 	"hotel-booking-system/internal/booking/domain"
 	"hotel-booking-system/pkg/logger"
 	"hotel-booking-system/pkg/metrics"
+	"hotel-booking-system/pkg/money"
 
 	"github.com/go-chi/chi/v5"
 )
 
+const dateLayout = "2006-01-02"
+
 type BookingHandler struct {
 	useCase domain.BookingUseCase
 }
@@ -36,8 +42,9 @@ func (h *BookingHandler) CreateBooking(w http.ResponseWriter, r *http.Request) {
 
 	if err := h.useCase.CreateBooking(r.Context(), &booking); err != nil {
 		logger.GetLogger().WithError(err).Error("failed to create booking")
-		metrics.HTTPRequestsTotal.WithLabelValues(r.Method, "/api/bookings", "500").Inc()
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		status := errorStatus(err)
+		metrics.HTTPRequestsTotal.WithLabelValues(r.Method, "/api/bookings", strconv.Itoa(status)).Inc()
+		http.Error(w, err.Error(), status)
 		return
 	}
 
@@ -47,6 +54,104 @@ func (h *BookingHandler) CreateBooking(w http.ResponseWriter, r *http.Request) {
 	json.NewEncoder(w).Encode(booking)
 }
 
+func (h *BookingHandler) CreateHold(w http.ResponseWriter, r *http.Request) {
+	start := time.Now()
+	defer func() {
+		metrics.HTTPRequestDuration.WithLabelValues(r.Method, "/api/bookings/holds").Observe(time.Since(start).Seconds())
+	}()
+
+	var hold domain.RoomHold
+	if err := json.NewDecoder(r.Body).Decode(&hold); err != nil {
+		logger.GetLogger().WithError(err).Error("failed to decode request")
+		metrics.HTTPRequestsTotal.WithLabelValues(r.Method, "/api/bookings/holds", "400").Inc()
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if err := h.useCase.CreateHold(r.Context(), &hold); err != nil {
+		logger.GetLogger().WithError(err).Error("failed to create room hold")
+		status := errorStatus(err)
+		metrics.HTTPRequestsTotal.WithLabelValues(r.Method, "/api/bookings/holds", strconv.Itoa(status)).Inc()
+		http.Error(w, err.Error(), status)
+		return
+	}
+
+	metrics.HTTPRequestsTotal.WithLabelValues(r.Method, "/api/bookings/holds", "201").Inc()
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(hold)
+}
+
+func (h *BookingHandler) GetHold(w http.ResponseWriter, r *http.Request) {
+	start := time.Now()
+	defer func() {
+		metrics.HTTPRequestDuration.WithLabelValues(r.Method, "/api/bookings/holds/{id}").Observe(time.Since(start).Seconds())
+	}()
+
+	id := chi.URLParam(r, "id")
+	hold, err := h.useCase.GetHold(r.Context(), id)
+	if err != nil {
+		logger.GetLogger().WithError(err).Error("failed to get room hold")
+		status := errorStatus(err)
+		metrics.HTTPRequestsTotal.WithLabelValues(r.Method, "/api/bookings/holds/{id}", strconv.Itoa(status)).Inc()
+		http.Error(w, err.Error(), status)
+		return
+	}
+
+	metrics.HTTPRequestsTotal.WithLabelValues(r.Method, "/api/bookings/holds/{id}", "200").Inc()
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(hold)
+}
+
+func (h *BookingHandler) CreateReservation(w http.ResponseWriter, r *http.Request) {
+	start := time.Now()
+	defer func() {
+		metrics.HTTPRequestDuration.WithLabelValues(r.Method, "/api/reservations").Observe(time.Since(start).Seconds())
+	}()
+
+	var reservation domain.Reservation
+	if err := json.NewDecoder(r.Body).Decode(&reservation); err != nil {
+		logger.GetLogger().WithError(err).Error("failed to decode request")
+		metrics.HTTPRequestsTotal.WithLabelValues(r.Method, "/api/reservations", "400").Inc()
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if err := h.useCase.CreateReservation(r.Context(), &reservation); err != nil {
+		logger.GetLogger().WithError(err).Error("failed to create reservation")
+		status := errorStatus(err)
+		metrics.HTTPRequestsTotal.WithLabelValues(r.Method, "/api/reservations", strconv.Itoa(status)).Inc()
+		http.Error(w, err.Error(), status)
+		return
+	}
+
+	metrics.HTTPRequestsTotal.WithLabelValues(r.Method, "/api/reservations", "201").Inc()
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(reservation)
+}
+
+func (h *BookingHandler) GetReservation(w http.ResponseWriter, r *http.Request) {
+	start := time.Now()
+	defer func() {
+		metrics.HTTPRequestDuration.WithLabelValues(r.Method, "/api/reservations/{id}").Observe(time.Since(start).Seconds())
+	}()
+
+	id := chi.URLParam(r, "id")
+	reservation, err := h.useCase.GetReservation(r.Context(), id)
+	if err != nil {
+		logger.GetLogger().WithError(err).Error("failed to get reservation")
+		status := errorStatus(err)
+		metrics.HTTPRequestsTotal.WithLabelValues(r.Method, "/api/reservations/{id}", strconv.Itoa(status)).Inc()
+		http.Error(w, err.Error(), status)
+		return
+	}
+
+	metrics.HTTPRequestsTotal.WithLabelValues(r.Method, "/api/reservations/{id}", "200").Inc()
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(reservation)
+}
+
 func (h *BookingHandler) GetBooking(w http.ResponseWriter, r *http.Request) {
 	start := time.Now()
 	defer func() {
@@ -67,6 +172,170 @@ func (h *BookingHandler) GetBooking(w http.ResponseWriter, r *http.Request) {
 	json.NewEncoder(w).Encode(booking)
 }
 
+func (h *BookingHandler) CancelBooking(w http.ResponseWriter, r *http.Request) {
+	start := time.Now()
+	defer func() {
+		metrics.HTTPRequestDuration.WithLabelValues(r.Method, "/api/bookings/{id}/cancel").Observe(time.Since(start).Seconds())
+	}()
+
+	id := chi.URLParam(r, "id")
+	booking, err := h.useCase.CancelBooking(r.Context(), id)
+	if err != nil {
+		logger.GetLogger().WithError(err).Error("failed to cancel booking")
+		status := errorStatus(err)
+		metrics.HTTPRequestsTotal.WithLabelValues(r.Method, "/api/bookings/{id}/cancel", strconv.Itoa(status)).Inc()
+		http.Error(w, err.Error(), status)
+		return
+	}
+
+	metrics.HTTPRequestsTotal.WithLabelValues(r.Method, "/api/bookings/{id}/cancel", "200").Inc()
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(booking)
+}
+
+func (h *BookingHandler) ModifyBooking(w http.ResponseWriter, r *http.Request) {
+	start := time.Now()
+	defer func() {
+		metrics.HTTPRequestDuration.WithLabelValues(r.Method, "/api/bookings/{id}").Observe(time.Since(start).Seconds())
+	}()
+
+	var change domain.BookingChange
+	if err := json.NewDecoder(r.Body).Decode(&change); err != nil {
+		logger.GetLogger().WithError(err).Error("failed to decode request")
+		metrics.HTTPRequestsTotal.WithLabelValues(r.Method, "/api/bookings/{id}", "400").Inc()
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	id := chi.URLParam(r, "id")
+	booking, err := h.useCase.ModifyBooking(r.Context(), id, change)
+	if err != nil {
+		logger.GetLogger().WithError(err).Error("failed to modify booking")
+		status := errorStatus(err)
+		metrics.HTTPRequestsTotal.WithLabelValues(r.Method, "/api/bookings/{id}", strconv.Itoa(status)).Inc()
+		http.Error(w, err.Error(), status)
+		return
+	}
+
+	metrics.HTTPRequestsTotal.WithLabelValues(r.Method, "/api/bookings/{id}", "200").Inc()
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(booking)
+}
+
+// stayRequest identifies the staff member who checks a guest in or out or
+// marks a no-show.
+type stayRequest struct {
+	StaffID string `json:"staff_id"`
+}
+
+func (h *BookingHandler) CheckIn(w http.ResponseWriter, r *http.Request) {
+	start := time.Now()
+	defer func() {
+		metrics.HTTPRequestDuration.WithLabelValues(r.Method, "/api/bookings/{id}/check-in").Observe(time.Since(start).Seconds())
+	}()
+
+	var req stayRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		logger.GetLogger().WithError(err).Error("failed to decode request")
+		metrics.HTTPRequestsTotal.WithLabelValues(r.Method, "/api/bookings/{id}/check-in", "400").Inc()
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	id := chi.URLParam(r, "id")
+	booking, err := h.useCase.CheckIn(r.Context(), id, req.StaffID)
+	if err != nil {
+		logger.GetLogger().WithError(err).Error("failed to check in booking")
+		status := errorStatus(err)
+		metrics.HTTPRequestsTotal.WithLabelValues(r.Method, "/api/bookings/{id}/check-in", strconv.Itoa(status)).Inc()
+		http.Error(w, err.Error(), status)
+		return
+	}
+
+	metrics.HTTPRequestsTotal.WithLabelValues(r.Method, "/api/bookings/{id}/check-in", "200").Inc()
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(booking)
+}
+
+func (h *BookingHandler) CheckOut(w http.ResponseWriter, r *http.Request) {
+	start := time.Now()
+	defer func() {
+		metrics.HTTPRequestDuration.WithLabelValues(r.Method, "/api/bookings/{id}/check-out").Observe(time.Since(start).Seconds())
+	}()
+
+	var req stayRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		logger.GetLogger().WithError(err).Error("failed to decode request")
+		metrics.HTTPRequestsTotal.WithLabelValues(r.Method, "/api/bookings/{id}/check-out", "400").Inc()
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	id := chi.URLParam(r, "id")
+	booking, err := h.useCase.CheckOut(r.Context(), id, req.StaffID)
+	if err != nil {
+		logger.GetLogger().WithError(err).Error("failed to check out booking")
+		status := errorStatus(err)
+		metrics.HTTPRequestsTotal.WithLabelValues(r.Method, "/api/bookings/{id}/check-out", strconv.Itoa(status)).Inc()
+		http.Error(w, err.Error(), status)
+		return
+	}
+
+	metrics.HTTPRequestsTotal.WithLabelValues(r.Method, "/api/bookings/{id}/check-out", "200").Inc()
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(booking)
+}
+
+func (h *BookingHandler) MarkNoShow(w http.ResponseWriter, r *http.Request) {
+	start := time.Now()
+	defer func() {
+		metrics.HTTPRequestDuration.WithLabelValues(r.Method, "/api/bookings/{id}/no-show").Observe(time.Since(start).Seconds())
+	}()
+
+	var req stayRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		logger.GetLogger().WithError(err).Error("failed to decode request")
+		metrics.HTTPRequestsTotal.WithLabelValues(r.Method, "/api/bookings/{id}/no-show", "400").Inc()
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	id := chi.URLParam(r, "id")
+	booking, err := h.useCase.MarkNoShow(r.Context(), id, req.StaffID)
+	if err != nil {
+		logger.GetLogger().WithError(err).Error("failed to mark booking as no-show")
+		status := errorStatus(err)
+		metrics.HTTPRequestsTotal.WithLabelValues(r.Method, "/api/bookings/{id}/no-show", strconv.Itoa(status)).Inc()
+		http.Error(w, err.Error(), status)
+		return
+	}
+
+	metrics.HTTPRequestsTotal.WithLabelValues(r.Method, "/api/bookings/{id}/no-show", "200").Inc()
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(booking)
+}
+
+func (h *BookingHandler) GetStatusHistory(w http.ResponseWriter, r *http.Request) {
+	start := time.Now()
+	defer func() {
+		metrics.HTTPRequestDuration.WithLabelValues(r.Method, "/api/bookings/{id}/history").Observe(time.Since(start).Seconds())
+	}()
+
+	id := chi.URLParam(r, "id")
+	history, err := h.useCase.GetStatusHistory(r.Context(), id)
+	if err != nil {
+		logger.GetLogger().WithError(err).Error("failed to get booking status history")
+		status := errorStatus(err)
+		metrics.HTTPRequestsTotal.WithLabelValues(r.Method, "/api/bookings/{id}/history", strconv.Itoa(status)).Inc()
+		http.Error(w, err.Error(), status)
+		return
+	}
+
+	metrics.HTTPRequestsTotal.WithLabelValues(r.Method, "/api/bookings/{id}/history", "200").Inc()
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(history)
+}
+
 func (h *BookingHandler) GetBookingsByUser(w http.ResponseWriter, r *http.Request) {
 	start := time.Now()
 	defer func() {
@@ -107,11 +376,181 @@ func (h *BookingHandler) GetBookingsByHotel(w http.ResponseWriter, r *http.Reque
 	json.NewEncoder(w).Encode(bookings)
 }
 
+type BookedRoomsResponse struct {
+	RoomIDs []string `json:"room_ids"`
+}
+
+func (h *BookingHandler) GetBookedRooms(w http.ResponseWriter, r *http.Request) {
+	start := time.Now()
+	defer func() {
+		metrics.HTTPRequestDuration.WithLabelValues(r.Method, "/api/bookings/hotel/{hotelId}/booked-rooms").Observe(time.Since(start).Seconds())
+	}()
+
+	hotelID := chi.URLParam(r, "hotelId")
+	checkIn, errIn := time.Parse(dateLayout, r.URL.Query().Get("check_in"))
+	checkOut, errOut := time.Parse(dateLayout, r.URL.Query().Get("check_out"))
+	if errIn != nil || errOut != nil {
+		metrics.HTTPRequestsTotal.WithLabelValues(r.Method, "/api/bookings/hotel/{hotelId}/booked-rooms", "400").Inc()
+		http.Error(w, "check_in and check_out must be dates in YYYY-MM-DD format", http.StatusBadRequest)
+		return
+	}
+
+	roomIDs, err := h.useCase.GetBookedRoomIDs(r.Context(), hotelID, checkIn, checkOut)
+	if err != nil {
+		logger.GetLogger().WithError(err).Error("failed to get booked rooms")
+		status := errorStatus(err)
+		metrics.HTTPRequestsTotal.WithLabelValues(r.Method, "/api/bookings/hotel/{hotelId}/booked-rooms", strconv.Itoa(status)).Inc()
+		http.Error(w, err.Error(), status)
+		return
+	}
+
+	metrics.HTTPRequestsTotal.WithLabelValues(r.Method, "/api/bookings/hotel/{hotelId}/booked-rooms", "200").Inc()
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(BookedRoomsResponse{RoomIDs: roomIDs})
+}
+
+func (h *BookingHandler) CreatePromotion(w http.ResponseWriter, r *http.Request) {
+	start := time.Now()
+	defer func() {
+		metrics.HTTPRequestDuration.WithLabelValues(r.Method, "/api/promotions").Observe(time.Since(start).Seconds())
+	}()
+
+	var promotion domain.Promotion
+	if err := json.NewDecoder(r.Body).Decode(&promotion); err != nil {
+		logger.GetLogger().WithError(err).Error("failed to decode request")
+		metrics.HTTPRequestsTotal.WithLabelValues(r.Method, "/api/promotions", "400").Inc()
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if err := h.useCase.CreatePromotion(r.Context(), &promotion); err != nil {
+		logger.GetLogger().WithError(err).Error("failed to create promotion")
+		status := errorStatus(err)
+		metrics.HTTPRequestsTotal.WithLabelValues(r.Method, "/api/promotions", strconv.Itoa(status)).Inc()
+		http.Error(w, err.Error(), status)
+		return
+	}
+
+	metrics.HTTPRequestsTotal.WithLabelValues(r.Method, "/api/promotions", "201").Inc()
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(promotion)
+}
+
+func (h *BookingHandler) GetPromotions(w http.ResponseWriter, r *http.Request) {
+	start := time.Now()
+	defer func() {
+		metrics.HTTPRequestDuration.WithLabelValues(r.Method, "/api/promotions").Observe(time.Since(start).Seconds())
+	}()
+
+	promotions, err := h.useCase.GetPromotions(r.Context())
+	if err != nil {
+		logger.GetLogger().WithError(err).Error("failed to get promotions")
+		metrics.HTTPRequestsTotal.WithLabelValues(r.Method, "/api/promotions", "500").Inc()
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	metrics.HTTPRequestsTotal.WithLabelValues(r.Method, "/api/promotions", "200").Inc()
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(promotions)
+}
+
+func (h *BookingHandler) GetPromotion(w http.ResponseWriter, r *http.Request) {
+	start := time.Now()
+	defer func() {
+		metrics.HTTPRequestDuration.WithLabelValues(r.Method, "/api/promotions/{code}").Observe(time.Since(start).Seconds())
+	}()
+
+	promotion, err := h.useCase.GetPromotion(r.Context(), chi.URLParam(r, "code"))
+	if err != nil {
+		logger.GetLogger().WithError(err).Error("failed to get promotion")
+		status := errorStatus(err)
+		metrics.HTTPRequestsTotal.WithLabelValues(r.Method, "/api/promotions/{code}", strconv.Itoa(status)).Inc()
+		http.Error(w, err.Error(), status)
+		return
+	}
+
+	metrics.HTTPRequestsTotal.WithLabelValues(r.Method, "/api/promotions/{code}", "200").Inc()
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(promotion)
+}
+
+func (h *BookingHandler) JoinWaitlist(w http.ResponseWriter, r *http.Request) {
+	start := time.Now()
+	defer func() {
+		metrics.HTTPRequestDuration.WithLabelValues(r.Method, "/api/waitlist").Observe(time.Since(start).Seconds())
+	}()
+
+	var entry domain.WaitlistEntry
+	if err := json.NewDecoder(r.Body).Decode(&entry); err != nil {
+		logger.GetLogger().WithError(err).Error("failed to decode request")
+		metrics.HTTPRequestsTotal.WithLabelValues(r.Method, "/api/waitlist", "400").Inc()
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if err := h.useCase.JoinWaitlist(r.Context(), &entry); err != nil {
+		logger.GetLogger().WithError(err).Error("failed to join waitlist")
+		status := errorStatus(err)
+		metrics.HTTPRequestsTotal.WithLabelValues(r.Method, "/api/waitlist", strconv.Itoa(status)).Inc()
+		http.Error(w, err.Error(), status)
+		return
+	}
+
+	metrics.HTTPRequestsTotal.WithLabelValues(r.Method, "/api/waitlist", "201").Inc()
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(entry)
+}
+
+func (h *BookingHandler) GetWaitlistEntry(w http.ResponseWriter, r *http.Request) {
+	start := time.Now()
+	defer func() {
+		metrics.HTTPRequestDuration.WithLabelValues(r.Method, "/api/waitlist/{id}").Observe(time.Since(start).Seconds())
+	}()
+
+	id := chi.URLParam(r, "id")
+	entry, err := h.useCase.GetWaitlistEntry(r.Context(), id)
+	if err != nil {
+		logger.GetLogger().WithError(err).Error("failed to get waitlist entry")
+		status := errorStatus(err)
+		metrics.HTTPRequestsTotal.WithLabelValues(r.Method, "/api/waitlist/{id}", strconv.Itoa(status)).Inc()
+		http.Error(w, err.Error(), status)
+		return
+	}
+
+	metrics.HTTPRequestsTotal.WithLabelValues(r.Method, "/api/waitlist/{id}", "200").Inc()
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(entry)
+}
+
+func (h *BookingHandler) LeaveWaitlist(w http.ResponseWriter, r *http.Request) {
+	start := time.Now()
+	defer func() {
+		metrics.HTTPRequestDuration.WithLabelValues(r.Method, "/api/waitlist/{id}/cancel").Observe(time.Since(start).Seconds())
+	}()
+
+	id := chi.URLParam(r, "id")
+	entry, err := h.useCase.LeaveWaitlist(r.Context(), id)
+	if err != nil {
+		logger.GetLogger().WithError(err).Error("failed to leave waitlist")
+		status := errorStatus(err)
+		metrics.HTTPRequestsTotal.WithLabelValues(r.Method, "/api/waitlist/{id}/cancel", strconv.Itoa(status)).Inc()
+		http.Error(w, err.Error(), status)
+		return
+	}
+
+	metrics.HTTPRequestsTotal.WithLabelValues(r.Method, "/api/waitlist/{id}/cancel", "200").Inc()
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(entry)
+}
+
 type PaymentWebhookRequest struct {
-	PaymentID string  `json:"payment_id"`
-	BookingID string  `json:"booking_id"`
-	Status    string  `json:"status"`
-	Amount    float64 `json:"amount,omitempty"`
+	PaymentID string       `json:"payment_id"`
+	BookingID string       `json:"booking_id"`
+	Status    string       `json:"status"`
+	Amount    *money.Money `json:"amount,omitempty"`
 }
 
 func (h *BookingHandler) PaymentWebhook(w http.ResponseWriter, r *http.Request) {
@@ -128,13 +567,39 @@ func (h *BookingHandler) PaymentWebhook(w http.ResponseWriter, r *http.Request)
 		return
 	}
 
-	if err := h.useCase.UpdatePaymentStatus(r.Context(), req.BookingID, req.Status); err != nil {
+	if err := h.useCase.UpdatePaymentStatus(r.Context(), req.BookingID, req.PaymentID, req.Status); err != nil {
 		logger.GetLogger().WithError(err).Error("failed to update payment status")
-		metrics.HTTPRequestsTotal.WithLabelValues(r.Method, "/api/webhooks/payment", "500").Inc()
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		status := errorStatus(err)
+		metrics.HTTPRequestsTotal.WithLabelValues(r.Method, "/api/webhooks/payment", strconv.Itoa(status)).Inc()
+		http.Error(w, err.Error(), status)
 		return
 	}
 
 	metrics.HTTPRequestsTotal.WithLabelValues(r.Method, "/api/webhooks/payment", "200").Inc()
 	w.WriteHeader(http.StatusOK)
 }
+
+func errorStatus(err error) int {
+	switch {
+	case errors.Is(err, domain.ErrInvalidDates), errors.Is(err, domain.ErrInvalidPaymentStatus),
+		errors.Is(err, domain.ErrRateNotFound), errors.Is(err, domain.ErrInvalidPromotion),
+		errors.Is(err, domain.ErrPromoCodeInvalid), errors.Is(err, domain.ErrEmptyBookingChange),
+		errors.Is(err, domain.ErrEmptyReservation), errors.Is(err, domain.ErrInvalidGuests),
+		errors.Is(err, domain.ErrStaffIDRequired), errors.Is(err, domain.ErrInvalidWaitlistEntry):
+		return http.StatusBadRequest
+	case errors.Is(err, domain.ErrCapacityExceeded):
+		return http.StatusUnprocessableEntity
+	case errors.Is(err, sql.ErrNoRows):
+		return http.StatusNotFound
+	case errors.Is(err, domain.ErrRoomNotAvailable), errors.Is(err, domain.ErrBookingNotCancellable),
+		errors.Is(err, domain.ErrInvalidTransition), errors.Is(err, domain.ErrStatusChanged),
+		errors.Is(err, domain.ErrHoldNotActive), errors.Is(err, domain.ErrPromoCodeExhausted),
+		errors.Is(err, domain.ErrPromotionExists), errors.Is(err, domain.ErrBookingNotModifiable),
+		errors.Is(err, domain.ErrWaitlistEntryClosed), errors.Is(err, domain.ErrRoomTypeAvailable):
+		return http.StatusConflict
+	case errors.Is(err, domain.ErrPaymentFailed), errors.Is(err, domain.ErrPaymentCaptureFailed):
+		return http.StatusBadGateway
+	default:
+		return http.StatusInternalServerError
+	}
+}
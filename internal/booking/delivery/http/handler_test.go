@@ -3,10 +3,14 @@ package http
 import (
 	"bytes"
 	"context"
+	"database/sql"
 	"encoding/json"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
+	"time"
 
 	"hotel-booking-system/internal/booking/domain"
 
@@ -42,11 +46,135 @@ func (m *MockBookingUseCase) GetBookingsByHotel(ctx context.Context, hotelID str
 	return args.Get(0).([]domain.Booking), args.Error(1)
 }
 
-func (m *MockBookingUseCase) UpdatePaymentStatus(ctx context.Context, id, status string) error {
-	args := m.Called(ctx, id, status)
+func (m *MockBookingUseCase) UpdatePaymentStatus(ctx context.Context, id, paymentID, status string) error {
+	args := m.Called(ctx, id, paymentID, status)
 	return args.Error(0)
 }
 
+func (m *MockBookingUseCase) CancelBooking(ctx context.Context, id string) (*domain.Booking, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.Booking), args.Error(1)
+}
+
+func (m *MockBookingUseCase) ModifyBooking(ctx context.Context, id string, change domain.BookingChange) (*domain.Booking, error) {
+	args := m.Called(ctx, id, change)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.Booking), args.Error(1)
+}
+
+func (m *MockBookingUseCase) CheckIn(ctx context.Context, id, staffID string) (*domain.Booking, error) {
+	args := m.Called(ctx, id, staffID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.Booking), args.Error(1)
+}
+
+func (m *MockBookingUseCase) CheckOut(ctx context.Context, id, staffID string) (*domain.Booking, error) {
+	args := m.Called(ctx, id, staffID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.Booking), args.Error(1)
+}
+
+func (m *MockBookingUseCase) MarkNoShow(ctx context.Context, id, staffID string) (*domain.Booking, error) {
+	args := m.Called(ctx, id, staffID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.Booking), args.Error(1)
+}
+
+func (m *MockBookingUseCase) GetStatusHistory(ctx context.Context, id string) ([]domain.StatusChange, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]domain.StatusChange), args.Error(1)
+}
+
+func (m *MockBookingUseCase) GetBookedRoomIDs(ctx context.Context, hotelID string, checkIn, checkOut time.Time) ([]string, error) {
+	args := m.Called(ctx, hotelID, checkIn, checkOut)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]string), args.Error(1)
+}
+
+func (m *MockBookingUseCase) CreateHold(ctx context.Context, hold *domain.RoomHold) error {
+	args := m.Called(ctx, hold)
+	return args.Error(0)
+}
+
+func (m *MockBookingUseCase) GetHold(ctx context.Context, id string) (*domain.RoomHold, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.RoomHold), args.Error(1)
+}
+
+func (m *MockBookingUseCase) CreateReservation(ctx context.Context, reservation *domain.Reservation) error {
+	args := m.Called(ctx, reservation)
+	return args.Error(0)
+}
+
+func (m *MockBookingUseCase) GetReservation(ctx context.Context, id string) (*domain.Reservation, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.Reservation), args.Error(1)
+}
+
+func (m *MockBookingUseCase) CreatePromotion(ctx context.Context, promotion *domain.Promotion) error {
+	args := m.Called(ctx, promotion)
+	return args.Error(0)
+}
+
+func (m *MockBookingUseCase) GetPromotion(ctx context.Context, code string) (*domain.Promotion, error) {
+	args := m.Called(ctx, code)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.Promotion), args.Error(1)
+}
+
+func (m *MockBookingUseCase) GetPromotions(ctx context.Context) ([]domain.Promotion, error) {
+	args := m.Called(ctx)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]domain.Promotion), args.Error(1)
+}
+
+func (m *MockBookingUseCase) JoinWaitlist(ctx context.Context, entry *domain.WaitlistEntry) error {
+	args := m.Called(ctx, entry)
+	return args.Error(0)
+}
+
+func (m *MockBookingUseCase) GetWaitlistEntry(ctx context.Context, id string) (*domain.WaitlistEntry, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.WaitlistEntry), args.Error(1)
+}
+
+func (m *MockBookingUseCase) LeaveWaitlist(ctx context.Context, id string) (*domain.WaitlistEntry, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.WaitlistEntry), args.Error(1)
+}
+
 func TestCreateBooking_Success(t *testing.T) {
 	mockUC := new(MockBookingUseCase)
 	handler := NewBookingHandler(mockUC)
@@ -70,6 +198,205 @@ func TestCreateBooking_Success(t *testing.T) {
 	mockUC.AssertExpectations(t)
 }
 
+func TestCreateBooking_Conflict(t *testing.T) {
+	mockUC := new(MockBookingUseCase)
+	handler := NewBookingHandler(mockUC)
+
+	mockUC.On("CreateBooking", mock.Anything, mock.Anything).Return(domain.ErrRoomNotAvailable)
+
+	body, _ := json.Marshal(domain.Booking{UserID: "user123", HotelID: "hotel123", RoomID: "room123"})
+	req := httptest.NewRequest("POST", "/api/bookings", bytes.NewBuffer(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+
+	handler.CreateBooking(w, req)
+
+	assert.Equal(t, http.StatusConflict, w.Code)
+	mockUC.AssertExpectations(t)
+}
+
+func TestCreateBooking_InvalidDates(t *testing.T) {
+	mockUC := new(MockBookingUseCase)
+	handler := NewBookingHandler(mockUC)
+
+	mockUC.On("CreateBooking", mock.Anything, mock.Anything).Return(domain.ErrInvalidDates)
+
+	body, _ := json.Marshal(domain.Booking{UserID: "user123", HotelID: "hotel123", RoomID: "room123"})
+	req := httptest.NewRequest("POST", "/api/bookings", bytes.NewBuffer(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+
+	handler.CreateBooking(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	mockUC.AssertExpectations(t)
+}
+
+func TestCreateBooking_PaymentFailed(t *testing.T) {
+	mockUC := new(MockBookingUseCase)
+	handler := NewBookingHandler(mockUC)
+
+	mockUC.On("CreateBooking", mock.Anything, mock.Anything).Return(fmt.Errorf("%w: timeout", domain.ErrPaymentFailed))
+
+	body, _ := json.Marshal(domain.Booking{UserID: "user123", HotelID: "hotel123", RoomID: "room123"})
+	req := httptest.NewRequest("POST", "/api/bookings", bytes.NewBuffer(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+
+	handler.CreateBooking(w, req)
+
+	assert.Equal(t, http.StatusBadGateway, w.Code)
+	mockUC.AssertExpectations(t)
+}
+
+func TestCreateHold(t *testing.T) {
+	t.Run("created", func(t *testing.T) {
+		mockUC := new(MockBookingUseCase)
+		handler := NewBookingHandler(mockUC)
+
+		mockUC.On("CreateHold", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+			hold := args.Get(1).(*domain.RoomHold)
+			hold.ID = "hold123"
+			hold.Status = domain.HoldActive
+		}).Return(nil)
+
+		body, _ := json.Marshal(domain.RoomHold{UserID: "user123", HotelID: "hotel123", RoomID: "room123"})
+		req := httptest.NewRequest("POST", "/api/bookings/holds", bytes.NewBuffer(body))
+		w := httptest.NewRecorder()
+
+		handler.CreateHold(w, req)
+
+		assert.Equal(t, http.StatusCreated, w.Code)
+		var response domain.RoomHold
+		json.NewDecoder(w.Body).Decode(&response)
+		assert.Equal(t, "hold123", response.ID)
+		assert.Equal(t, domain.HoldActive, response.Status)
+	})
+
+	t.Run("room not available", func(t *testing.T) {
+		mockUC := new(MockBookingUseCase)
+		handler := NewBookingHandler(mockUC)
+
+		mockUC.On("CreateHold", mock.Anything, mock.Anything).Return(domain.ErrRoomNotAvailable)
+
+		body, _ := json.Marshal(domain.RoomHold{UserID: "user123", HotelID: "hotel123", RoomID: "room123"})
+		req := httptest.NewRequest("POST", "/api/bookings/holds", bytes.NewBuffer(body))
+		w := httptest.NewRecorder()
+
+		handler.CreateHold(w, req)
+
+		assert.Equal(t, http.StatusConflict, w.Code)
+	})
+}
+
+func TestJoinWaitlist(t *testing.T) {
+	t.Run("created", func(t *testing.T) {
+		mockUC := new(MockBookingUseCase)
+		handler := NewBookingHandler(mockUC)
+
+		mockUC.On("JoinWaitlist", mock.Anything, mock.MatchedBy(func(entry *domain.WaitlistEntry) bool {
+			return entry.RoomType == "double"
+		})).Run(func(args mock.Arguments) {
+			entry := args.Get(1).(*domain.WaitlistEntry)
+			entry.ID = "entry123"
+			entry.Status = domain.WaitlistWaiting
+		}).Return(nil)
+
+		body, _ := json.Marshal(domain.WaitlistEntry{UserID: "user123", HotelID: "hotel123", RoomType: "double"})
+		req := httptest.NewRequest("POST", "/api/waitlist", bytes.NewBuffer(body))
+		w := httptest.NewRecorder()
+
+		handler.JoinWaitlist(w, req)
+
+		assert.Equal(t, http.StatusCreated, w.Code)
+		var response domain.WaitlistEntry
+		json.NewDecoder(w.Body).Decode(&response)
+		assert.Equal(t, "entry123", response.ID)
+		assert.Equal(t, domain.WaitlistWaiting, response.Status)
+	})
+
+	t.Run("invalid entry", func(t *testing.T) {
+		mockUC := new(MockBookingUseCase)
+		handler := NewBookingHandler(mockUC)
+
+		mockUC.On("JoinWaitlist", mock.Anything, mock.Anything).Return(domain.ErrInvalidWaitlistEntry)
+
+		body, _ := json.Marshal(domain.WaitlistEntry{UserID: "user123", HotelID: "hotel123"})
+		req := httptest.NewRequest("POST", "/api/waitlist", bytes.NewBuffer(body))
+		w := httptest.NewRecorder()
+
+		handler.JoinWaitlist(w, req)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("room type not sold out", func(t *testing.T) {
+		mockUC := new(MockBookingUseCase)
+		handler := NewBookingHandler(mockUC)
+
+		mockUC.On("JoinWaitlist", mock.Anything, mock.Anything).Return(domain.ErrRoomTypeAvailable)
+
+		body, _ := json.Marshal(domain.WaitlistEntry{UserID: "user123", HotelID: "hotel123", RoomType: "double"})
+		req := httptest.NewRequest("POST", "/api/waitlist", bytes.NewBuffer(body))
+		w := httptest.NewRecorder()
+
+		handler.JoinWaitlist(w, req)
+
+		assert.Equal(t, http.StatusConflict, w.Code)
+	})
+}
+
+func TestLeaveWaitlist(t *testing.T) {
+	newRequest := func() *http.Request {
+		req := httptest.NewRequest("POST", "/api/waitlist/entry123/cancel", nil)
+		rctx := chi.NewRouteContext()
+		rctx.URLParams.Add("id", "entry123")
+		return req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, rctx))
+	}
+
+	t.Run("success", func(t *testing.T) {
+		mockUC := new(MockBookingUseCase)
+		handler := NewBookingHandler(mockUC)
+
+		mockUC.On("LeaveWaitlist", mock.Anything, "entry123").Return(&domain.WaitlistEntry{ID: "entry123", Status: domain.WaitlistCancelled}, nil)
+
+		w := httptest.NewRecorder()
+		handler.LeaveWaitlist(w, newRequest())
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		var response domain.WaitlistEntry
+		json.Unmarshal(w.Body.Bytes(), &response)
+		assert.Equal(t, domain.WaitlistCancelled, response.Status)
+	})
+
+	t.Run("already offered", func(t *testing.T) {
+		mockUC := new(MockBookingUseCase)
+		handler := NewBookingHandler(mockUC)
+
+		mockUC.On("LeaveWaitlist", mock.Anything, "entry123").Return(nil, domain.ErrWaitlistEntryClosed)
+
+		w := httptest.NewRecorder()
+		handler.LeaveWaitlist(w, newRequest())
+
+		assert.Equal(t, http.StatusConflict, w.Code)
+	})
+}
+
+func TestCreateBooking_HoldNotActive(t *testing.T) {
+	mockUC := new(MockBookingUseCase)
+	handler := NewBookingHandler(mockUC)
+
+	mockUC.On("CreateBooking", mock.Anything, mock.Anything).Return(domain.ErrHoldNotActive)
+
+	body, _ := json.Marshal(domain.Booking{HoldID: "hold123"})
+	req := httptest.NewRequest("POST", "/api/bookings", bytes.NewBuffer(body))
+	w := httptest.NewRecorder()
+
+	handler.CreateBooking(w, req)
+
+	assert.Equal(t, http.StatusConflict, w.Code)
+}
+
 func TestGetBooking_Success(t *testing.T) {
 	mockUC := new(MockBookingUseCase)
 	handler := NewBookingHandler(mockUC)
@@ -139,3 +466,536 @@ func TestGetBookingsByHotel_Success(t *testing.T) {
 	assert.Equal(t, http.StatusOK, w.Code)
 	mockUC.AssertExpectations(t)
 }
+
+func TestGetBookedRooms(t *testing.T) {
+	checkIn := time.Date(2024, 12, 20, 0, 0, 0, 0, time.UTC)
+	checkOut := time.Date(2024, 12, 25, 0, 0, 0, 0, time.UTC)
+
+	t.Run("success", func(t *testing.T) {
+		mockUC := new(MockBookingUseCase)
+		handler := NewBookingHandler(mockUC)
+
+		mockUC.On("GetBookedRoomIDs", mock.Anything, "hotel123", checkIn, checkOut).Return([]string{"room1"}, nil)
+
+		req := httptest.NewRequest("GET", "/api/bookings/hotel/hotel123/booked-rooms?check_in=2024-12-20&check_out=2024-12-25", nil)
+		rctx := chi.NewRouteContext()
+		rctx.URLParams.Add("hotelId", "hotel123")
+		req = req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, rctx))
+		w := httptest.NewRecorder()
+
+		handler.GetBookedRooms(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		var response BookedRoomsResponse
+		json.Unmarshal(w.Body.Bytes(), &response)
+		assert.Equal(t, []string{"room1"}, response.RoomIDs)
+		mockUC.AssertExpectations(t)
+	})
+
+	t.Run("invalid dates", func(t *testing.T) {
+		mockUC := new(MockBookingUseCase)
+		handler := NewBookingHandler(mockUC)
+
+		req := httptest.NewRequest("GET", "/api/bookings/hotel/hotel123/booked-rooms?check_in=tomorrow", nil)
+		w := httptest.NewRecorder()
+
+		handler.GetBookedRooms(w, req)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		mockUC.AssertNotCalled(t, "GetBookedRoomIDs")
+	})
+}
+
+func TestCancelBooking(t *testing.T) {
+	newRequest := func() *http.Request {
+		req := httptest.NewRequest("POST", "/api/bookings/booking123/cancel", nil)
+		rctx := chi.NewRouteContext()
+		rctx.URLParams.Add("id", "booking123")
+		return req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, rctx))
+	}
+
+	t.Run("success", func(t *testing.T) {
+		mockUC := new(MockBookingUseCase)
+		handler := NewBookingHandler(mockUC)
+
+		mockUC.On("CancelBooking", mock.Anything, "booking123").Return(&domain.Booking{ID: "booking123", Status: domain.StatusCancelled}, nil)
+
+		w := httptest.NewRecorder()
+		handler.CancelBooking(w, newRequest())
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		var response domain.Booking
+		json.Unmarshal(w.Body.Bytes(), &response)
+		assert.Equal(t, domain.StatusCancelled, response.Status)
+		mockUC.AssertExpectations(t)
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		mockUC := new(MockBookingUseCase)
+		handler := NewBookingHandler(mockUC)
+
+		mockUC.On("CancelBooking", mock.Anything, "booking123").Return(nil, sql.ErrNoRows)
+
+		w := httptest.NewRecorder()
+		handler.CancelBooking(w, newRequest())
+
+		assert.Equal(t, http.StatusNotFound, w.Code)
+	})
+
+	t.Run("not cancellable", func(t *testing.T) {
+		mockUC := new(MockBookingUseCase)
+		handler := NewBookingHandler(mockUC)
+
+		mockUC.On("CancelBooking", mock.Anything, "booking123").Return(nil, domain.ErrBookingNotCancellable)
+
+		w := httptest.NewRecorder()
+		handler.CancelBooking(w, newRequest())
+
+		assert.Equal(t, http.StatusConflict, w.Code)
+	})
+}
+
+func TestModifyBooking(t *testing.T) {
+	newRequest := func(body string) *http.Request {
+		req := httptest.NewRequest("PATCH", "/api/bookings/booking123", strings.NewReader(body))
+		rctx := chi.NewRouteContext()
+		rctx.URLParams.Add("id", "booking123")
+		return req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, rctx))
+	}
+	checkOut := time.Date(2030, 12, 25, 0, 0, 0, 0, time.UTC)
+
+	t.Run("success", func(t *testing.T) {
+		mockUC := new(MockBookingUseCase)
+		handler := NewBookingHandler(mockUC)
+
+		mockUC.On("ModifyBooking", mock.Anything, "booking123", domain.BookingChange{CheckOutDate: checkOut}).
+			Return(&domain.Booking{ID: "booking123", CheckOutDate: checkOut, Status: domain.StatusConfirmed}, nil)
+
+		w := httptest.NewRecorder()
+		handler.ModifyBooking(w, newRequest(`{"check_out_date":"2030-12-25T00:00:00Z"}`))
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		var response domain.Booking
+		json.Unmarshal(w.Body.Bytes(), &response)
+		assert.True(t, checkOut.Equal(response.CheckOutDate))
+		mockUC.AssertExpectations(t)
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		tests := []struct {
+			err  error
+			want int
+		}{
+			{err: domain.ErrEmptyBookingChange, want: http.StatusBadRequest},
+			{err: domain.ErrBookingNotModifiable, want: http.StatusConflict},
+			{err: domain.ErrRoomNotAvailable, want: http.StatusConflict},
+			{err: domain.ErrPaymentFailed, want: http.StatusBadGateway},
+		}
+		for _, tt := range tests {
+			mockUC := new(MockBookingUseCase)
+			handler := NewBookingHandler(mockUC)
+			mockUC.On("ModifyBooking", mock.Anything, "booking123", mock.Anything).Return(nil, tt.err)
+
+			w := httptest.NewRecorder()
+			handler.ModifyBooking(w, newRequest(`{"room_id":"room456"}`))
+
+			assert.Equal(t, tt.want, w.Code, tt.err.Error())
+		}
+	})
+}
+
+func TestCheckIn(t *testing.T) {
+	newRequest := func() *http.Request {
+		req := httptest.NewRequest("POST", "/api/bookings/booking123/check-in", bytes.NewBufferString(`{"staff_id":"staff-7"}`))
+		rctx := chi.NewRouteContext()
+		rctx.URLParams.Add("id", "booking123")
+		return req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, rctx))
+	}
+
+	t.Run("success", func(t *testing.T) {
+		mockUC := new(MockBookingUseCase)
+		handler := NewBookingHandler(mockUC)
+
+		mockUC.On("CheckIn", mock.Anything, "booking123", "staff-7").Return(&domain.Booking{ID: "booking123", Status: domain.StatusCheckedIn}, nil)
+
+		w := httptest.NewRecorder()
+		handler.CheckIn(w, newRequest())
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		var response domain.Booking
+		json.Unmarshal(w.Body.Bytes(), &response)
+		assert.Equal(t, domain.StatusCheckedIn, response.Status)
+		mockUC.AssertExpectations(t)
+	})
+
+	t.Run("invalid transition", func(t *testing.T) {
+		mockUC := new(MockBookingUseCase)
+		handler := NewBookingHandler(mockUC)
+
+		mockUC.On("CheckIn", mock.Anything, "booking123", "staff-7").Return(nil, domain.ErrInvalidTransition)
+
+		w := httptest.NewRecorder()
+		handler.CheckIn(w, newRequest())
+
+		assert.Equal(t, http.StatusConflict, w.Code)
+	})
+
+	t.Run("capture failed", func(t *testing.T) {
+		mockUC := new(MockBookingUseCase)
+		handler := NewBookingHandler(mockUC)
+
+		mockUC.On("CheckIn", mock.Anything, "booking123", "staff-7").Return(nil, domain.ErrPaymentCaptureFailed)
+
+		w := httptest.NewRecorder()
+		handler.CheckIn(w, newRequest())
+
+		assert.Equal(t, http.StatusBadGateway, w.Code)
+	})
+}
+
+func TestCheckOut(t *testing.T) {
+	newRequest := func(body string) *http.Request {
+		req := httptest.NewRequest("POST", "/api/bookings/booking123/check-out", bytes.NewBufferString(body))
+		rctx := chi.NewRouteContext()
+		rctx.URLParams.Add("id", "booking123")
+		return req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, rctx))
+	}
+
+	t.Run("success", func(t *testing.T) {
+		mockUC := new(MockBookingUseCase)
+		handler := NewBookingHandler(mockUC)
+
+		mockUC.On("CheckOut", mock.Anything, "booking123", "staff-7").Return(&domain.Booking{ID: "booking123", Status: domain.StatusCompleted, CheckedOutBy: "staff-7"}, nil)
+
+		w := httptest.NewRecorder()
+		handler.CheckOut(w, newRequest(`{"staff_id":"staff-7"}`))
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		var response domain.Booking
+		json.Unmarshal(w.Body.Bytes(), &response)
+		assert.Equal(t, domain.StatusCompleted, response.Status)
+		assert.Equal(t, "staff-7", response.CheckedOutBy)
+		mockUC.AssertExpectations(t)
+	})
+
+	t.Run("no staff member", func(t *testing.T) {
+		mockUC := new(MockBookingUseCase)
+		handler := NewBookingHandler(mockUC)
+
+		mockUC.On("CheckOut", mock.Anything, "booking123", "").Return(nil, domain.ErrStaffIDRequired)
+
+		w := httptest.NewRecorder()
+		handler.CheckOut(w, newRequest(`{}`))
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("no body", func(t *testing.T) {
+		mockUC := new(MockBookingUseCase)
+		handler := NewBookingHandler(mockUC)
+
+		w := httptest.NewRecorder()
+		handler.CheckOut(w, newRequest(""))
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		mockUC.AssertNotCalled(t, "CheckOut", mock.Anything, mock.Anything, mock.Anything)
+	})
+}
+
+func TestMarkNoShow(t *testing.T) {
+	newRequest := func() *http.Request {
+		req := httptest.NewRequest("POST", "/api/bookings/booking123/no-show", bytes.NewBufferString(`{"staff_id":"staff-7"}`))
+		rctx := chi.NewRouteContext()
+		rctx.URLParams.Add("id", "booking123")
+		return req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, rctx))
+	}
+
+	t.Run("success", func(t *testing.T) {
+		mockUC := new(MockBookingUseCase)
+		handler := NewBookingHandler(mockUC)
+
+		mockUC.On("MarkNoShow", mock.Anything, "booking123", "staff-7").Return(&domain.Booking{ID: "booking123", Status: domain.StatusNoShow}, nil)
+
+		w := httptest.NewRecorder()
+		handler.MarkNoShow(w, newRequest())
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		var response domain.Booking
+		json.Unmarshal(w.Body.Bytes(), &response)
+		assert.Equal(t, domain.StatusNoShow, response.Status)
+		mockUC.AssertExpectations(t)
+	})
+
+	t.Run("already checked in", func(t *testing.T) {
+		mockUC := new(MockBookingUseCase)
+		handler := NewBookingHandler(mockUC)
+
+		mockUC.On("MarkNoShow", mock.Anything, "booking123", "staff-7").Return(nil, domain.ErrInvalidTransition)
+
+		w := httptest.NewRecorder()
+		handler.MarkNoShow(w, newRequest())
+
+		assert.Equal(t, http.StatusConflict, w.Code)
+	})
+}
+
+func TestCreateBooking_GuestErrors(t *testing.T) {
+	tests := []struct {
+		err  error
+		want int
+	}{
+		{err: domain.ErrInvalidGuests, want: http.StatusBadRequest},
+		{err: domain.ErrCapacityExceeded, want: http.StatusUnprocessableEntity},
+	}
+
+	for _, tt := range tests {
+		mockUC := new(MockBookingUseCase)
+		handler := NewBookingHandler(mockUC)
+
+		mockUC.On("CreateBooking", mock.Anything, mock.MatchedBy(func(b *domain.Booking) bool {
+			return b.Adults == 6 && b.Children == 1
+		})).Return(tt.err)
+
+		req := httptest.NewRequest("POST", "/api/bookings", bytes.NewBufferString(`{"user_id":"user123","adults":6,"children":1}`))
+		w := httptest.NewRecorder()
+		handler.CreateBooking(w, req)
+
+		assert.Equal(t, tt.want, w.Code)
+		mockUC.AssertExpectations(t)
+	}
+}
+
+func TestCreateBooking_PromoCodeErrors(t *testing.T) {
+	tests := []struct {
+		err  error
+		want int
+	}{
+		{err: domain.ErrPromoCodeInvalid, want: http.StatusBadRequest},
+		{err: domain.ErrPromoCodeExhausted, want: http.StatusConflict},
+	}
+
+	for _, tt := range tests {
+		mockUC := new(MockBookingUseCase)
+		handler := NewBookingHandler(mockUC)
+
+		mockUC.On("CreateBooking", mock.Anything, mock.MatchedBy(func(b *domain.Booking) bool {
+			return b.PromoCode == "SUMMER10"
+		})).Return(tt.err)
+
+		req := httptest.NewRequest("POST", "/api/bookings", bytes.NewBufferString(`{"user_id":"user123","promo_code":"SUMMER10"}`))
+		w := httptest.NewRecorder()
+		handler.CreateBooking(w, req)
+
+		assert.Equal(t, tt.want, w.Code)
+		mockUC.AssertExpectations(t)
+	}
+}
+
+func TestCreatePromotion(t *testing.T) {
+	body := `{"code":"SUMMER10","type":"percentage","basis_points":1000,"max_uses":100,"max_uses_per_user":1}`
+
+	t.Run("success", func(t *testing.T) {
+		mockUC := new(MockBookingUseCase)
+		handler := NewBookingHandler(mockUC)
+
+		mockUC.On("CreatePromotion", mock.Anything, mock.MatchedBy(func(p *domain.Promotion) bool {
+			return p.Code == "SUMMER10" && p.BasisPoints == 1000 && p.MaxUsesPerUser == 1
+		})).Return(nil)
+
+		w := httptest.NewRecorder()
+		handler.CreatePromotion(w, httptest.NewRequest("POST", "/api/promotions", bytes.NewBufferString(body)))
+
+		assert.Equal(t, http.StatusCreated, w.Code)
+		mockUC.AssertExpectations(t)
+	})
+
+	t.Run("duplicate code", func(t *testing.T) {
+		mockUC := new(MockBookingUseCase)
+		handler := NewBookingHandler(mockUC)
+
+		mockUC.On("CreatePromotion", mock.Anything, mock.Anything).Return(domain.ErrPromotionExists)
+
+		w := httptest.NewRecorder()
+		handler.CreatePromotion(w, httptest.NewRequest("POST", "/api/promotions", bytes.NewBufferString(body)))
+
+		assert.Equal(t, http.StatusConflict, w.Code)
+	})
+}
+
+func TestGetPromotion_NotFound(t *testing.T) {
+	mockUC := new(MockBookingUseCase)
+	handler := NewBookingHandler(mockUC)
+
+	mockUC.On("GetPromotion", mock.Anything, "SUMMER10").Return(nil, sql.ErrNoRows)
+
+	req := httptest.NewRequest("GET", "/api/promotions/SUMMER10", nil)
+	rctx := chi.NewRouteContext()
+	rctx.URLParams.Add("code", "SUMMER10")
+	w := httptest.NewRecorder()
+	handler.GetPromotion(w, req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, rctx)))
+
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
+
+func TestGetStatusHistory(t *testing.T) {
+	newRequest := func() *http.Request {
+		req := httptest.NewRequest("GET", "/api/bookings/booking123/history", nil)
+		rctx := chi.NewRouteContext()
+		rctx.URLParams.Add("id", "booking123")
+		return req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, rctx))
+	}
+
+	t.Run("success", func(t *testing.T) {
+		mockUC := new(MockBookingUseCase)
+		handler := NewBookingHandler(mockUC)
+
+		history := []domain.StatusChange{
+			{ID: "1", BookingID: "booking123", Field: "status", FromStatus: "pending", ToStatus: "confirmed"},
+		}
+		mockUC.On("GetStatusHistory", mock.Anything, "booking123").Return(history, nil)
+
+		w := httptest.NewRecorder()
+		handler.GetStatusHistory(w, newRequest())
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		var response []domain.StatusChange
+		json.Unmarshal(w.Body.Bytes(), &response)
+		assert.Len(t, response, 1)
+		assert.Equal(t, "confirmed", response[0].ToStatus)
+		mockUC.AssertExpectations(t)
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		mockUC := new(MockBookingUseCase)
+		handler := NewBookingHandler(mockUC)
+
+		mockUC.On("GetStatusHistory", mock.Anything, "booking123").Return(nil, sql.ErrNoRows)
+
+		w := httptest.NewRecorder()
+		handler.GetStatusHistory(w, newRequest())
+
+		assert.Equal(t, http.StatusNotFound, w.Code)
+	})
+}
+
+func TestPaymentWebhook(t *testing.T) {
+	newRequest := func(status string) *http.Request {
+		body, _ := json.Marshal(PaymentWebhookRequest{PaymentID: "payment123", BookingID: "booking123", Status: status})
+		return httptest.NewRequest("POST", "/api/webhooks/payment", bytes.NewBuffer(body))
+	}
+
+	tests := []struct {
+		name       string
+		err        error
+		statusCode int
+	}{
+		{"success", nil, http.StatusOK},
+		{"invalid status", domain.ErrInvalidPaymentStatus, http.StatusBadRequest},
+		{"invalid transition", domain.ErrInvalidTransition, http.StatusConflict},
+		{"concurrent change", domain.ErrStatusChanged, http.StatusConflict},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockUC := new(MockBookingUseCase)
+			handler := NewBookingHandler(mockUC)
+
+			mockUC.On("UpdatePaymentStatus", mock.Anything, "booking123", "payment123", "paid").Return(tt.err)
+
+			w := httptest.NewRecorder()
+			handler.PaymentWebhook(w, newRequest("paid"))
+
+			assert.Equal(t, tt.statusCode, w.Code)
+		})
+	}
+}
+
+func TestCreateReservation(t *testing.T) {
+	t.Run("created", func(t *testing.T) {
+		mockUC := new(MockBookingUseCase)
+		handler := NewBookingHandler(mockUC)
+
+		mockUC.On("CreateReservation", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+			reservation := args.Get(1).(*domain.Reservation)
+			reservation.ID = "reservation123"
+		}).Return(nil)
+
+		body, _ := json.Marshal(domain.Reservation{
+			UserID:  "user123",
+			HotelID: "hotel123",
+			Bookings: []domain.Booking{
+				{RoomID: "room123", GuestName: "Иван Петров"},
+				{RoomID: "room456", GuestName: "Мария Петрова"},
+			},
+		})
+		req := httptest.NewRequest("POST", "/api/reservations", bytes.NewBuffer(body))
+		w := httptest.NewRecorder()
+
+		handler.CreateReservation(w, req)
+
+		assert.Equal(t, http.StatusCreated, w.Code)
+		var response domain.Reservation
+		json.NewDecoder(w.Body).Decode(&response)
+		assert.Equal(t, "reservation123", response.ID)
+		assert.Len(t, response.Bookings, 2)
+	})
+
+	t.Run("no bookings", func(t *testing.T) {
+		mockUC := new(MockBookingUseCase)
+		handler := NewBookingHandler(mockUC)
+
+		mockUC.On("CreateReservation", mock.Anything, mock.Anything).Return(domain.ErrEmptyReservation)
+
+		body, _ := json.Marshal(domain.Reservation{UserID: "user123", HotelID: "hotel123"})
+		req := httptest.NewRequest("POST", "/api/reservations", bytes.NewBuffer(body))
+		w := httptest.NewRecorder()
+
+		handler.CreateReservation(w, req)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("room not available", func(t *testing.T) {
+		mockUC := new(MockBookingUseCase)
+		handler := NewBookingHandler(mockUC)
+
+		mockUC.On("CreateReservation", mock.Anything, mock.Anything).Return(domain.ErrRoomNotAvailable)
+
+		body, _ := json.Marshal(domain.Reservation{UserID: "user123", HotelID: "hotel123", Bookings: []domain.Booking{{RoomID: "room123"}}})
+		req := httptest.NewRequest("POST", "/api/reservations", bytes.NewBuffer(body))
+		w := httptest.NewRecorder()
+
+		handler.CreateReservation(w, req)
+
+		assert.Equal(t, http.StatusConflict, w.Code)
+	})
+}
+
+func TestGetReservation(t *testing.T) {
+	mockUC := new(MockBookingUseCase)
+	handler := NewBookingHandler(mockUC)
+
+	mockUC.On("GetReservation", mock.Anything, "reservation123").Return(&domain.Reservation{
+		ID:       "reservation123",
+		Bookings: []domain.Booking{{ID: "booking1", ReservationID: "reservation123"}},
+	}, nil)
+	mockUC.On("GetReservation", mock.Anything, "missing").Return(nil, sql.ErrNoRows)
+
+	r := chi.NewRouter()
+	r.Get("/api/reservations/{id}", handler.GetReservation)
+
+	req := httptest.NewRequest("GET", "/api/reservations/reservation123", nil)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	var response domain.Reservation
+	json.NewDecoder(w.Body).Decode(&response)
+	assert.Equal(t, "booking1", response.Bookings[0].ID)
+
+	req = httptest.NewRequest("GET", "/api/reservations/missing", nil)
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
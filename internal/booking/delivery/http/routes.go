@@ -1,26 +1,60 @@
 package http
 
 import (
+	"hotel-booking-system/pkg/idempotency"
+	"hotel-booking-system/pkg/webhook"
+
 	"github.com/go-chi/chi/v5"
 	"github.com/go-chi/chi/v5/middleware"
 )
 
-func SetupRoutes(handler *BookingHandler) *chi.Mux {
+func SetupRoutes(handler *BookingHandler, idempotencyStore idempotency.Store, webhookVerifier *webhook.Verifier) *chi.Mux {
 	r := chi.NewRouter()
 
 	r.Use(middleware.Logger)
 	r.Use(middleware.Recoverer)
 	r.Use(middleware.RequestID)
 
+	// Requests that create something or move money are replayed from the
+	// idempotency store; status transitions reject a repeat by themselves.
+	idempotent := idempotency.Middleware(idempotencyStore)
+
 	r.Route("/api", func(r chi.Router) {
 		r.Route("/bookings", func(r chi.Router) {
-			r.Post("/", handler.CreateBooking)
+			r.With(idempotent).Post("/", handler.CreateBooking)
+			r.With(idempotent).Post("/holds", handler.CreateHold)
+			r.Get("/holds/{id}", handler.GetHold)
 			r.Get("/{id}", handler.GetBooking)
+			r.With(idempotent).Patch("/{id}", handler.ModifyBooking)
+			r.Get("/{id}/history", handler.GetStatusHistory)
+			r.Post("/{id}/cancel", handler.CancelBooking)
+			r.Post("/{id}/check-in", handler.CheckIn)
+			r.Post("/{id}/check-out", handler.CheckOut)
+			r.Post("/{id}/no-show", handler.MarkNoShow)
 			r.Get("/user/{userId}", handler.GetBookingsByUser)
 			r.Get("/hotel/{hotelId}", handler.GetBookingsByHotel)
+			r.Get("/hotel/{hotelId}/booked-rooms", handler.GetBookedRooms)
+		})
+
+		r.Route("/reservations", func(r chi.Router) {
+			r.With(idempotent).Post("/", handler.CreateReservation)
+			r.Get("/{id}", handler.GetReservation)
+		})
+
+		r.Route("/waitlist", func(r chi.Router) {
+			r.With(idempotent).Post("/", handler.JoinWaitlist)
+			r.Get("/{id}", handler.GetWaitlistEntry)
+			r.Post("/{id}/cancel", handler.LeaveWaitlist)
+		})
+
+		r.Route("/promotions", func(r chi.Router) {
+			r.With(idempotent).Post("/", handler.CreatePromotion)
+			r.Get("/", handler.GetPromotions)
+			r.Get("/{code}", handler.GetPromotion)
 		})
 
 		r.Route("/webhooks", func(r chi.Router) {
+			r.Use(webhook.Middleware(webhookVerifier))
 			r.Post("/payment", handler.PaymentWebhook)
 		})
 	})
@@ -1,15 +1,135 @@
 package http
 
 import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
 	"testing"
+	"time"
+
+	"hotel-booking-system/internal/booking/domain"
+	"hotel-booking-system/pkg/idempotency"
+	"hotel-booking-system/pkg/webhook"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
 )
 
+var testVerifier = webhook.NewVerifier([]string{"test-secret"}, 5*time.Minute)
+
 func TestSetupRoutes(t *testing.T) {
 	mockUC := new(MockBookingUseCase)
 	handler := NewBookingHandler(mockUC)
 
-	r := SetupRoutes(handler)
+	r := SetupRoutes(handler, idempotency.NewMemoryStore(time.Minute), testVerifier)
 	assert.NotNil(t, r)
 }
+
+func TestSetupRoutes_IdempotentCreateBooking(t *testing.T) {
+	mockUC := new(MockBookingUseCase)
+	handler := NewBookingHandler(mockUC)
+
+	mockUC.On("CreateBooking", mock.Anything, mock.Anything).Return(nil).Once()
+
+	r := SetupRoutes(handler, idempotency.NewMemoryStore(time.Minute), testVerifier)
+	body, _ := json.Marshal(domain.Booking{UserID: "user123", HotelID: "hotel123", RoomID: "room123"})
+
+	for i := 0; i < 2; i++ {
+		req := httptest.NewRequest("POST", "/api/bookings", bytes.NewBuffer(body))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set(idempotency.HeaderKey, "retry-key")
+		w := httptest.NewRecorder()
+
+		r.ServeHTTP(w, req)
+		assert.Equal(t, http.StatusCreated, w.Code)
+	}
+
+	mockUC.AssertNumberOfCalls(t, "CreateBooking", 1)
+}
+
+func TestSetupRoutes_IdempotentCreateRoutes(t *testing.T) {
+	tests := []struct {
+		method string
+		path   string
+		body   string
+	}{
+		{method: "CreateHold", path: "/api/bookings/holds", body: `{"user_id":"user123","hotel_id":"hotel123","room_id":"room123"}`},
+		{method: "CreateReservation", path: "/api/reservations", body: `{"user_id":"user123","hotel_id":"hotel123"}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			mockUC := new(MockBookingUseCase)
+			mockUC.On(tt.method, mock.Anything, mock.Anything).Return(nil).Once()
+			r := SetupRoutes(NewBookingHandler(mockUC), idempotency.NewMemoryStore(time.Minute), testVerifier)
+
+			for i := 0; i < 2; i++ {
+				req := httptest.NewRequest("POST", tt.path, bytes.NewBufferString(tt.body))
+				req.Header.Set(idempotency.HeaderKey, "retry-key")
+				w := httptest.NewRecorder()
+
+				r.ServeHTTP(w, req)
+				assert.Equal(t, http.StatusCreated, w.Code)
+			}
+
+			mockUC.AssertNumberOfCalls(t, tt.method, 1)
+		})
+	}
+}
+
+func TestSetupRoutes_PaymentWebhookRequiresSignature(t *testing.T) {
+	mockUC := new(MockBookingUseCase)
+	handler := NewBookingHandler(mockUC)
+	mockUC.On("UpdatePaymentStatus", mock.Anything, "booking123", "payment123", "paid").Return(nil)
+
+	r := SetupRoutes(handler, idempotency.NewMemoryStore(time.Minute), testVerifier)
+	body := []byte(`{"payment_id":"payment123","booking_id":"booking123","status":"paid"}`)
+
+	t.Run("unsigned", func(t *testing.T) {
+		req := httptest.NewRequest("POST", "/api/webhooks/payment", bytes.NewReader(body))
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		assert.Equal(t, http.StatusUnauthorized, w.Code)
+		mockUC.AssertNotCalled(t, "UpdatePaymentStatus", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("signed with unknown secret", func(t *testing.T) {
+		req := httptest.NewRequest("POST", "/api/webhooks/payment", bytes.NewReader(body))
+		webhook.NewSigner("other-secret").SignRequest(req.Header, body)
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		assert.Equal(t, http.StatusUnauthorized, w.Code)
+	})
+
+	t.Run("signed", func(t *testing.T) {
+		req := httptest.NewRequest("POST", "/api/webhooks/payment", bytes.NewReader(body))
+		webhook.NewSigner("test-secret").SignRequest(req.Header, body)
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		assert.Equal(t, http.StatusOK, w.Code)
+		mockUC.AssertExpectations(t)
+	})
+}
+
+func TestSetupRoutes_WebhookIgnoresIdempotencyKey(t *testing.T) {
+	mockUC := new(MockBookingUseCase)
+	handler := NewBookingHandler(mockUC)
+	mockUC.On("UpdatePaymentStatus", mock.Anything, "booking123", "payment123", "paid").Return(nil)
+
+	r := SetupRoutes(handler, idempotency.NewMemoryStore(time.Minute), testVerifier)
+	body := []byte(`{"payment_id":"payment123","booking_id":"booking123","status":"paid"}`)
+
+	for i := 0; i < 2; i++ {
+		req := httptest.NewRequest("POST", "/api/webhooks/payment", bytes.NewReader(body))
+		req.Header.Set(idempotency.HeaderKey, "retry-key")
+		webhook.NewSigner("test-secret").SignRequest(req.Header, body)
+		w := httptest.NewRecorder()
+
+		r.ServeHTTP(w, req)
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Empty(t, w.Header().Get(idempotency.HeaderReplayed))
+	}
+
+	mockUC.AssertNumberOfCalls(t, "UpdatePaymentStatus", 2)
+}
@@ -0,0 +1,50 @@
+package domain
+
+import (
+	"time"
+
+	"hotel-booking-system/pkg/money"
+)
+
+// CancellationPolicy is the hotel's cancellation policy as it was when the
+// booking was made, so later changes to the policy do not affect it.
+type CancellationPolicy struct {
+	ID    string             `json:"id"`
+	Name  string             `json:"name"`
+	Rules []CancellationRule `json:"rules"`
+}
+
+// CancellationRule refunds RefundBasisPoints of the price (10000 is 100%) to
+// cancellations made at least HoursBeforeCheckIn hours before check-in.
+type CancellationRule struct {
+	HoursBeforeCheckIn int   `json:"hours_before_check_in"`
+	RefundBasisPoints  int64 `json:"refund_basis_points"`
+}
+
+// RefundBasisPoints returns the share of the price refunded for a cancellation
+// at the given time: that of the rule with the largest threshold the
+// cancellation still meets, or nothing when it meets none. A booking without a
+// policy is fully refundable.
+func (p *CancellationPolicy) RefundBasisPoints(checkIn, at time.Time) int64 {
+	if p == nil {
+		return 10000
+	}
+	hoursLeft := checkIn.Sub(at).Hours()
+	var best *CancellationRule
+	for i, rule := range p.Rules {
+		if hoursLeft >= float64(rule.HoursBeforeCheckIn) &&
+			(best == nil || rule.HoursBeforeCheckIn > best.HoursBeforeCheckIn) {
+			best = &p.Rules[i]
+		}
+	}
+	if best == nil {
+		return 0
+	}
+	return best.RefundBasisPoints
+}
+
+// RefundableAmount is the part of the booking's price, in the hotel's
+// currency, its cancellation policy refunds at the given time.
+func (b *Booking) RefundableAmount(at time.Time) money.Money {
+	return b.TotalPrice.Percent(b.CancellationPolicy.RefundBasisPoints(b.CheckInDate, at))
+}
@@ -0,0 +1,29 @@
+package domain
+
+import "errors"
+
+var (
+	ErrInvalidDates          = errors.New("check-in date must be before check-out date")
+	ErrRoomNotAvailable      = errors.New("room is already booked for the selected dates")
+	ErrBookingNotCancellable = errors.New("booking cannot be cancelled in its current status")
+	ErrInvalidTransition     = errors.New("invalid status transition")
+	ErrInvalidPaymentStatus  = errors.New("invalid payment status")
+	ErrStatusChanged         = errors.New("booking status was changed concurrently")
+	ErrPaymentFailed         = errors.New("payment could not be initiated")
+	ErrHoldNotActive         = errors.New("room hold has expired or was already used")
+	ErrPaymentCaptureFailed  = errors.New("payment could not be captured")
+	ErrRateNotFound          = errors.New("no exchange rate for the requested currency")
+	ErrInvalidPromotion      = errors.New("promotion must have a code, a valid discount and a valid validity window")
+	ErrPromoCodeInvalid      = errors.New("promo code does not exist, has expired or does not apply to this booking")
+	ErrPromoCodeExhausted    = errors.New("promo code usage limit has been reached")
+	ErrPromotionExists       = errors.New("promotion with this code already exists")
+	ErrBookingNotModifiable  = errors.New("booking cannot be modified in its current status")
+	ErrEmptyBookingChange    = errors.New("booking change must set other dates or another room")
+	ErrEmptyReservation      = errors.New("reservation must have at least one booking")
+	ErrInvalidGuests         = errors.New("booking must have at least one adult and no negative number of children")
+	ErrCapacityExceeded      = errors.New("number of guests exceeds room capacity")
+	ErrStaffIDRequired       = errors.New("staff member ID is required")
+	ErrInvalidWaitlistEntry  = errors.New("waitlist entry must have a user, a hotel and a room type")
+	ErrWaitlistEntryClosed   = errors.New("waitlist entry is no longer waiting for an offer")
+	ErrRoomTypeAvailable     = errors.New("rooms of this type are available for the selected dates")
+)
@@ -0,0 +1,36 @@
+package domain
+
+import "time"
+
+type HoldStatus string
+
+const (
+	HoldActive    HoldStatus = "active"
+	HoldConverted HoldStatus = "converted"
+	HoldExpired   HoldStatus = "expired"
+)
+
+type RoomHold struct {
+	ID           string     `json:"id"`
+	UserID       string     `json:"user_id"`
+	HotelID      string     `json:"hotel_id"`
+	RoomID       string     `json:"room_id"`
+	CheckInDate  time.Time  `json:"check_in_date"`
+	CheckOutDate time.Time  `json:"check_out_date"`
+	Status       HoldStatus `json:"status"`
+	BookingID    string     `json:"booking_id,omitempty"`
+	ExpiresAt    time.Time  `json:"expires_at"`
+	CreatedAt    time.Time  `json:"created_at"`
+}
+
+type HoldEvent struct {
+	HoldID       string    `json:"hold_id"`
+	UserID       string    `json:"user_id"`
+	HotelID      string    `json:"hotel_id"`
+	RoomID       string    `json:"room_id"`
+	CheckInDate  time.Time `json:"check_in_date"`
+	CheckOutDate time.Time `json:"check_out_date"`
+	ExpiresAt    time.Time `json:"expires_at"`
+	EventType    string    `json:"event_type"`
+	Timestamp    time.Time `json:"timestamp"`
+}
@@ -2,30 +2,111 @@ package domain
 
 import (
 	"time"
+
+	"hotel-booking-system/pkg/money"
+)
+
+const (
+	EventBookingCreated     = "booking.created"
+	EventBookingCancelled   = "booking.cancelled"
+	EventBookingHoldExpired = "booking.hold_expired"
+	EventBookingCheckedIn   = "booking.checked_in"
+	EventBookingModified    = "booking.modified"
+	EventBookingCheckedOut  = "booking.checked_out"
+	EventBookingNoShow      = "booking.no_show"
+	EventBookingExpired     = "booking.expired"
+	EventWaitlistOffered    = "waitlist.offered"
 )
 
+type PriceItemKind string
+
+const (
+	PriceItemAccommodation PriceItemKind = "accommodation"
+	PriceItemTax           PriceItemKind = "tax"
+	PriceItemFee           PriceItemKind = "fee"
+	PriceItemDiscount      PriceItemKind = "discount"
+)
+
+// PriceItem is a line of a booking's price: the accommodation itself, one of
+// the hotel's taxes and fees, or a promo code discount with a negative amount.
+type PriceItem struct {
+	Kind   PriceItemKind `json:"kind"`
+	Name   string        `json:"name,omitempty"`
+	Amount money.Money   `json:"amount"`
+}
+
+// Booking is a stay in one room, priced in the hotel's currency.
 type Booking struct {
-	ID            string    `json:"id"`
-	UserID        string    `json:"user_id"`
-	HotelID       string    `json:"hotel_id"`
-	RoomID        string    `json:"room_id"`
-	CheckInDate   time.Time `json:"check_in_date"`
-	CheckOutDate  time.Time `json:"check_out_date"`
-	TotalPrice    float64   `json:"total_price"`
-	Status        string    `json:"status"`
-	PaymentStatus string    `json:"payment_status"`
-	CreatedAt     time.Time `json:"created_at"`
-	UpdatedAt     time.Time `json:"updated_at"`
+	ID                 string              `json:"id"`
+	UserID             string              `json:"user_id"`
+	HotelID            string              `json:"hotel_id"`
+	RoomID             string              `json:"room_id"`
+	RoomType           string              `json:"room_type,omitempty"`
+	ReservationID      string              `json:"reservation_id,omitempty"`
+	GuestName          string              `json:"guest_name,omitempty"`
+	Adults             int                 `json:"adults"`
+	Children           int                 `json:"children"`
+	CheckInDate        time.Time           `json:"check_in_date"`
+	CheckOutDate       time.Time           `json:"check_out_date"`
+	TotalPrice         money.Money         `json:"total_price"`
+	PriceBreakdown     []PriceItem         `json:"price_breakdown"`
+	DisplayCurrency    string              `json:"display_currency,omitempty"`
+	DisplayPrice       money.Money         `json:"display_price"`
+	ExchangeRate       money.Rate          `json:"exchange_rate"`
+	Status             BookingStatus       `json:"status"`
+	PaymentStatus      PaymentStatus       `json:"payment_status"`
+	HoldID             string              `json:"hold_id,omitempty"`
+	PromoCode          string              `json:"promo_code,omitempty"`
+	CancellationPolicy *CancellationPolicy `json:"cancellation_policy,omitempty"`
+	CheckedInAt        *time.Time          `json:"checked_in_at,omitempty"`
+	CheckedInBy        string              `json:"checked_in_by,omitempty"`
+	CheckedOutAt       *time.Time          `json:"checked_out_at,omitempty"`
+	CheckedOutBy       string              `json:"checked_out_by,omitempty"`
+	NoShowAt           *time.Time          `json:"no_show_at,omitempty"`
+	NoShowBy           string              `json:"no_show_by,omitempty"`
+	CreatedAt          time.Time           `json:"created_at"`
+	UpdatedAt          time.Time           `json:"updated_at"`
 }
 
-type BookingEvent struct {
-	BookingID    string    `json:"booking_id"`
-	UserID       string    `json:"user_id"`
-	HotelID      string    `json:"hotel_id"`
-	RoomID       string    `json:"room_id"`
+// ChargeAmount converts an amount in the hotel's currency into the guest's
+// display currency at the booking's rate, so that refunds are converted the
+// same way the payment was.
+func (b *Booking) ChargeAmount(amount money.Money) (money.Money, error) {
+	if b.ExchangeRate.IsZero() {
+		return amount, nil
+	}
+	return amount.Convert(b.ExchangeRate)
+}
+
+// BookingChange moves a booking to other dates or another room of the same
+// hotel. Empty fields keep the booking's current value.
+type BookingChange struct {
+	RoomID       string    `json:"room_id,omitempty"`
 	CheckInDate  time.Time `json:"check_in_date"`
 	CheckOutDate time.Time `json:"check_out_date"`
-	TotalPrice   float64   `json:"total_price"`
-	EventType    string    `json:"event_type"`
-	Timestamp    time.Time `json:"timestamp"`
+}
+
+type BookingEvent struct {
+	BookingID        string       `json:"booking_id"`
+	UserID           string       `json:"user_id"`
+	HotelID          string       `json:"hotel_id"`
+	RoomID           string       `json:"room_id"`
+	RoomType         string       `json:"room_type,omitempty"`
+	ReservationID    string       `json:"reservation_id,omitempty"`
+	GuestName        string       `json:"guest_name,omitempty"`
+	Adults           int          `json:"adults"`
+	Children         int          `json:"children"`
+	CheckInDate      time.Time    `json:"check_in_date"`
+	CheckOutDate     time.Time    `json:"check_out_date"`
+	TotalPrice       money.Money  `json:"total_price"`
+	PriceBreakdown   []PriceItem  `json:"price_breakdown,omitempty"`
+	DisplayPrice     money.Money  `json:"display_price"`
+	RefundAmount     *money.Money `json:"refund_amount,omitempty"`
+	AdditionalCharge *money.Money `json:"additional_charge,omitempty"`
+	StaffID          string       `json:"staff_id,omitempty"`
+	// Status is the booking's status after booking.created: awaiting_payment
+	// or confirmed. Other events leave it empty.
+	Status    BookingStatus `json:"status,omitempty"`
+	EventType string        `json:"event_type"`
+	Timestamp time.Time     `json:"timestamp"`
 }
@@ -4,6 +4,8 @@ import (
 	"testing"
 	"time"
 
+	"hotel-booking-system/pkg/money"
+
 	"github.com/stretchr/testify/assert"
 )
 
@@ -15,7 +17,7 @@ func TestBooking(t *testing.T) {
 		RoomID:        "room123",
 		CheckInDate:   time.Now(),
 		CheckOutDate:  time.Now().AddDate(0, 0, 2),
-		TotalPrice:    10000.0,
+		TotalPrice:    money.New(1000000, "RUB"),
 		Status:        "confirmed",
 		PaymentStatus: "paid",
 		CreatedAt:     time.Now(),
@@ -26,9 +28,24 @@ func TestBooking(t *testing.T) {
 	assert.Equal(t, "user123", booking.UserID)
 	assert.Equal(t, "hotel123", booking.HotelID)
 	assert.Equal(t, "room123", booking.RoomID)
-	assert.Equal(t, 10000.0, booking.TotalPrice)
-	assert.Equal(t, "confirmed", booking.Status)
-	assert.Equal(t, "paid", booking.PaymentStatus)
+	assert.Equal(t, money.New(1000000, "RUB"), booking.TotalPrice)
+	assert.Equal(t, StatusConfirmed, booking.Status)
+	assert.Equal(t, PaymentPaid, booking.PaymentStatus)
+}
+
+func TestBooking_ChargeAmount(t *testing.T) {
+	rate, err := money.ParseRate("RUB", "USD", "0.0125")
+	assert.NoError(t, err)
+	booking := Booking{TotalPrice: money.New(1000000, "RUB"), ExchangeRate: rate}
+
+	charged, err := booking.ChargeAmount(money.New(250050, "RUB"))
+	assert.NoError(t, err)
+	assert.Equal(t, money.New(3126, "USD"), charged)
+
+	legacy := Booking{TotalPrice: money.New(1000000, "RUB")}
+	charged, err = legacy.ChargeAmount(legacy.TotalPrice)
+	assert.NoError(t, err)
+	assert.Equal(t, legacy.TotalPrice, charged)
 }
 
 func TestBookingEvent(t *testing.T) {
@@ -39,7 +56,7 @@ func TestBookingEvent(t *testing.T) {
 		RoomID:       "room123",
 		CheckInDate:  time.Now(),
 		CheckOutDate: time.Now().AddDate(0, 0, 2),
-		TotalPrice:   10000.0,
+		TotalPrice:   money.New(1000000, "RUB"),
 		EventType:    "booking.created",
 		Timestamp:    time.Now(),
 	}
@@ -48,5 +65,41 @@ func TestBookingEvent(t *testing.T) {
 	assert.Equal(t, "user123", event.UserID)
 	assert.Equal(t, "hotel123", event.HotelID)
 	assert.Equal(t, "booking.created", event.EventType)
-	assert.Equal(t, 10000.0, event.TotalPrice)
+	assert.Equal(t, money.New(1000000, "RUB"), event.TotalPrice)
+}
+
+func TestBooking_RefundableAmount(t *testing.T) {
+	checkIn := time.Date(2030, 12, 20, 14, 0, 0, 0, time.UTC)
+	flexible := &CancellationPolicy{Rules: []CancellationRule{
+		{HoursBeforeCheckIn: 0, RefundBasisPoints: 5000},
+		{HoursBeforeCheckIn: 48, RefundBasisPoints: 10000},
+	}}
+
+	tests := []struct {
+		name   string
+		policy *CancellationPolicy
+		at     time.Time
+		want   money.Money
+	}{
+		{name: "no policy", at: checkIn.Add(time.Hour), want: money.New(1000000, "RUB")},
+		{name: "before the free deadline", policy: flexible, at: checkIn.Add(-48 * time.Hour), want: money.New(1000000, "RUB")},
+		{name: "after the free deadline", policy: flexible, at: checkIn.Add(-47 * time.Hour), want: money.New(500000, "RUB")},
+		{name: "after check-in", policy: flexible, at: checkIn.Add(time.Minute), want: money.New(0, "RUB")},
+		{name: "non-refundable", policy: &CancellationPolicy{}, at: checkIn.AddDate(0, -1, 0), want: money.New(0, "RUB")},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			booking := Booking{CheckInDate: checkIn, TotalPrice: money.New(1000000, "RUB"), CancellationPolicy: tt.policy}
+			assert.Equal(t, tt.want, booking.RefundableAmount(tt.at))
+		})
+	}
+}
+
+func TestTopics_Topic(t *testing.T) {
+	topics := Topics{EventBookingCreated: "hotel.booking.created"}
+
+	assert.Equal(t, "hotel.booking.created", topics.Topic(EventBookingCreated))
+	assert.Equal(t, EventBookingCancelled, topics.Topic(EventBookingCancelled))
+	assert.Equal(t, EventBookingCancelled, Topics(nil).Topic(EventBookingCancelled))
 }
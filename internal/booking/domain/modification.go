@@ -0,0 +1,37 @@
+package domain
+
+import (
+	"time"
+
+	"hotel-booking-system/pkg/money"
+)
+
+type ModificationStatus string
+
+const (
+	ModificationPending  ModificationStatus = "pending"
+	ModificationSettled  ModificationStatus = "settled"
+	ModificationReverted ModificationStatus = "reverted"
+	// ModificationUnsettled is a modification whose price difference could
+	// not be settled and whose previous stay could not be restored either, as
+	// its room was taken or the booking moved on; the hotel settles it with
+	// the guest.
+	ModificationUnsettled ModificationStatus = "unsettled"
+)
+
+// BookingModification records a change of a booking whose price difference
+// is being settled. The booking is changed first, and Previous keeps the stay
+// it replaced so that the change can be reverted if the difference cannot be
+// charged or refunded. PaymentID is the payment of the additional charge,
+// whose outcome the payment webhook reports.
+type BookingModification struct {
+	ID               string             `json:"id"`
+	BookingID        string             `json:"booking_id"`
+	Previous         Booking            `json:"previous"`
+	AdditionalCharge *money.Money       `json:"additional_charge,omitempty"`
+	RefundAmount     *money.Money       `json:"refund_amount,omitempty"`
+	PaymentID        string             `json:"payment_id,omitempty"`
+	Status           ModificationStatus `json:"status"`
+	CreatedAt        time.Time          `json:"created_at"`
+	UpdatedAt        time.Time          `json:"updated_at"`
+}
@@ -0,0 +1,34 @@
+package domain
+
+import (
+	"encoding/json"
+	"time"
+)
+
+type OutboxEvent struct {
+	ID        int64
+	Topic     string
+	Key       string
+	Payload   []byte
+	Attempts  int
+	CreatedAt time.Time
+}
+
+func NewOutboxEvent(topic, key string, value interface{}) (*OutboxEvent, error) {
+	payload, err := json.Marshal(value)
+	if err != nil {
+		return nil, err
+	}
+	return &OutboxEvent{Topic: topic, Key: key, Payload: payload}, nil
+}
+
+// Topics names the Kafka topic each event type is published to. An event type
+// it does not name is published to the topic of the same name.
+type Topics map[string]string
+
+func (t Topics) Topic(eventType string) string {
+	if topic := t[eventType]; topic != "" {
+		return topic
+	}
+	return eventType
+}
@@ -0,0 +1,132 @@
+package domain
+
+import (
+	"sort"
+	"strings"
+	"time"
+
+	"hotel-booking-system/pkg/money"
+)
+
+type DiscountType string
+
+const (
+	DiscountPercentage DiscountType = "percentage"
+	DiscountFixed      DiscountType = "fixed"
+	DiscountFreeNights DiscountType = "free_nights"
+)
+
+// Promotion is a promo code that discounts the accommodation part of a booking
+// price: by BasisPoints of it (250 is 2.5%), by a fixed Amount, or by making
+// the cheapest nights free ("stay StayNights, pay PayNights", applied once per
+// every StayNights nights). HotelID and RoomTypes limit the rooms it applies
+// to when set; ValidFrom and ValidUntil limit when bookings can use it.
+// MaxUses and MaxUsesPerUser of zero mean unlimited.
+type Promotion struct {
+	ID             string       `json:"id"`
+	Code           string       `json:"code"`
+	Description    string       `json:"description,omitempty"`
+	Type           DiscountType `json:"type"`
+	BasisPoints    int64        `json:"basis_points,omitempty"`
+	Amount         *money.Money `json:"amount,omitempty"`
+	StayNights     int          `json:"stay_nights,omitempty"`
+	PayNights      int          `json:"pay_nights,omitempty"`
+	HotelID        string       `json:"hotel_id,omitempty"`
+	RoomTypes      []string     `json:"room_types,omitempty"`
+	ValidFrom      *time.Time   `json:"valid_from,omitempty"`
+	ValidUntil     *time.Time   `json:"valid_until,omitempty"`
+	MaxUses        int          `json:"max_uses"`
+	MaxUsesPerUser int          `json:"max_uses_per_user"`
+	CreatedAt      time.Time    `json:"created_at"`
+}
+
+// NormalizePromoCode makes codes case-insensitive.
+func NormalizePromoCode(code string) string {
+	return strings.ToUpper(strings.TrimSpace(code))
+}
+
+func (p *Promotion) Validate() error {
+	if p.Code == "" || p.MaxUses < 0 || p.MaxUsesPerUser < 0 ||
+		(p.ValidFrom != nil && p.ValidUntil != nil && p.ValidUntil.Before(*p.ValidFrom)) {
+		return ErrInvalidPromotion
+	}
+	switch p.Type {
+	case DiscountPercentage:
+		if p.BasisPoints <= 0 || p.BasisPoints > 10000 {
+			return ErrInvalidPromotion
+		}
+	case DiscountFixed:
+		if p.Amount == nil || !p.Amount.IsPositive() {
+			return ErrInvalidPromotion
+		}
+	case DiscountFreeNights:
+		if p.PayNights < 1 || p.StayNights <= p.PayNights {
+			return ErrInvalidPromotion
+		}
+	default:
+		return ErrInvalidPromotion
+	}
+	return nil
+}
+
+// AppliesTo reports whether a booking made at the given time for a room of the
+// given hotel and type can use the promotion.
+func (p *Promotion) AppliesTo(hotelID, roomType string, at time.Time) bool {
+	if p.ValidFrom != nil && at.Before(*p.ValidFrom) {
+		return false
+	}
+	if p.ValidUntil != nil && at.After(*p.ValidUntil) {
+		return false
+	}
+	if p.HotelID != "" && p.HotelID != hotelID {
+		return false
+	}
+	if len(p.RoomTypes) == 0 {
+		return true
+	}
+	for _, t := range p.RoomTypes {
+		if strings.EqualFold(t, roomType) {
+			return true
+		}
+	}
+	return false
+}
+
+// Discount returns how much the promotion takes off a stay with the given
+// nightly prices, never more than the stay costs. A fixed discount in another
+// currency than the stay does not apply.
+func (p *Promotion) Discount(nights []money.Money) (money.Money, error) {
+	var accommodation money.Money
+	for _, night := range nights {
+		var err error
+		if accommodation, err = accommodation.Add(night); err != nil {
+			return money.Money{}, err
+		}
+	}
+
+	var discount money.Money
+	switch p.Type {
+	case DiscountPercentage:
+		discount = accommodation.Percent(p.BasisPoints)
+	case DiscountFixed:
+		if p.Amount.Currency != accommodation.Currency {
+			return money.Money{}, ErrPromoCodeInvalid
+		}
+		discount = *p.Amount
+	case DiscountFreeNights:
+		sorted := append([]money.Money(nil), nights...)
+		sort.Slice(sorted, func(i, j int) bool { return sorted[i].Amount < sorted[j].Amount })
+		free := len(nights) / p.StayNights * (p.StayNights - p.PayNights)
+		for _, night := range sorted[:free] {
+			var err error
+			if discount, err = discount.Add(night); err != nil {
+				return money.Money{}, err
+			}
+		}
+	}
+
+	if discount.Amount > accommodation.Amount {
+		discount = accommodation
+	}
+	return discount, nil
+}
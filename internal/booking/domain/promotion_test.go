@@ -0,0 +1,76 @@
+package domain
+
+import (
+	"testing"
+	"time"
+
+	"hotel-booking-system/pkg/money"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPromotion_Validate(t *testing.T) {
+	amount := money.New(100000, "RUB")
+	from := time.Date(2030, 6, 1, 0, 0, 0, 0, time.UTC)
+	until := time.Date(2030, 5, 1, 0, 0, 0, 0, time.UTC)
+
+	assert.NoError(t, (&Promotion{Code: "A", Type: DiscountPercentage, BasisPoints: 1000}).Validate())
+	assert.NoError(t, (&Promotion{Code: "A", Type: DiscountFixed, Amount: &amount}).Validate())
+	assert.NoError(t, (&Promotion{Code: "A", Type: DiscountFreeNights, StayNights: 3, PayNights: 2}).Validate())
+
+	for _, p := range []Promotion{
+		{Type: DiscountPercentage, BasisPoints: 1000},
+		{Code: "A", Type: DiscountPercentage, BasisPoints: 10001},
+		{Code: "A", Type: DiscountFixed},
+		{Code: "A", Type: DiscountFreeNights, StayNights: 2, PayNights: 2},
+		{Code: "A", Type: "bogus"},
+		{Code: "A", Type: DiscountPercentage, BasisPoints: 1000, ValidFrom: &from, ValidUntil: &until},
+		{Code: "A", Type: DiscountPercentage, BasisPoints: 1000, MaxUses: -1},
+	} {
+		assert.ErrorIs(t, p.Validate(), ErrInvalidPromotion)
+	}
+}
+
+func TestPromotion_AppliesTo(t *testing.T) {
+	until := time.Date(2030, 9, 1, 0, 0, 0, 0, time.UTC)
+	p := Promotion{HotelID: "hotel-1", RoomTypes: []string{"Deluxe"}, ValidUntil: &until}
+	at := time.Date(2030, 8, 1, 0, 0, 0, 0, time.UTC)
+
+	assert.True(t, p.AppliesTo("hotel-1", "deluxe", at))
+	assert.False(t, p.AppliesTo("hotel-2", "Deluxe", at))
+	assert.False(t, p.AppliesTo("hotel-1", "Standard", at))
+	assert.False(t, p.AppliesTo("hotel-1", "Deluxe", until.Add(time.Hour)))
+}
+
+func TestPromotion_Discount(t *testing.T) {
+	nights := []money.Money{
+		money.New(700000, "RUB"), money.New(500000, "RUB"), money.New(600000, "RUB"),
+		money.New(700000, "RUB"), money.New(400000, "RUB"), money.New(500000, "RUB"), money.New(300000, "RUB"),
+	}
+	large := money.New(10000000, "RUB")
+	usd := money.New(1000, "USD")
+
+	tests := []struct {
+		name      string
+		promotion Promotion
+		want      money.Money
+		wantErr   error
+	}{
+		{name: "percentage", promotion: Promotion{Type: DiscountPercentage, BasisPoints: 1000}, want: money.New(370000, "RUB")},
+		{name: "fixed capped at the stay price", promotion: Promotion{Type: DiscountFixed, Amount: &large}, want: money.New(3700000, "RUB")},
+		{name: "fixed in another currency", promotion: Promotion{Type: DiscountFixed, Amount: &usd}, wantErr: ErrPromoCodeInvalid},
+		{name: "stay 3 pay 2 frees the cheapest night of each 3", promotion: Promotion{Type: DiscountFreeNights, StayNights: 3, PayNights: 2}, want: money.New(700000, "RUB")},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			discount, err := tt.promotion.Discount(nights)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, discount)
+		})
+	}
+}
@@ -1,14 +1,78 @@
 package domain
 
-import "context"
+import (
+	"context"
+	"time"
+
+	"hotel-booking-system/pkg/money"
+)
 
 type BookingRepository interface {
 	CreateBooking(ctx context.Context, booking *Booking) error
 	GetBookingByID(ctx context.Context, id string) (*Booking, error)
 	GetBookingsByUser(ctx context.Context, userID string) ([]Booking, error)
 	GetBookingsByHotel(ctx context.Context, hotelID string) ([]Booking, error)
-	UpdateBookingStatus(ctx context.Context, id, status string) error
-	UpdatePaymentStatus(ctx context.Context, id, paymentStatus string) error
+	UpdateBookingStatus(ctx context.Context, id string, from, to BookingStatus, event *OutboxEvent) error
+	UpdatePaymentStatus(ctx context.Context, id string, from, to PaymentStatus) error
+	GetStatusHistory(ctx context.Context, bookingID string) ([]StatusChange, error)
+	ModifyBooking(ctx context.Context, booking *Booking, modification *BookingModification, event *OutboxEvent) error
+	RevertModification(ctx context.Context, modification *BookingModification) error
+	UpdateModificationStatus(ctx context.Context, id string, from, to ModificationStatus, event *OutboxEvent) error
+	SetModificationPayment(ctx context.Context, id, paymentID string) error
+	GetPendingChargeModification(ctx context.Context, paymentReference, paymentID string) (*BookingModification, error)
+	UpdateStayStatus(ctx context.Context, booking *Booking, from BookingStatus, event *OutboxEvent) error
+	GetUnattendedBookings(ctx context.Context, checkInBefore time.Time) ([]Booking, error)
+	GetUnpaidBookings(ctx context.Context, updatedBefore time.Time, limit int) ([]Booking, error)
+	CreateReservation(ctx context.Context, reservation *Reservation) error
+	GetReservationByID(ctx context.Context, id string) (*Reservation, error)
+	HasOverlappingBooking(ctx context.Context, roomID string, checkIn, checkOut time.Time, excludeBookingID string) (bool, error)
+	GetBookedRoomIDs(ctx context.Context, hotelID string, checkIn, checkOut time.Time) ([]string, error)
+}
+
+type OutboxRepository interface {
+	GetPendingEvents(ctx context.Context, limit int) ([]OutboxEvent, error)
+	MarkEventSent(ctx context.Context, id int64) error
+	MarkEventFailed(ctx context.Context, id int64, reason string) error
+}
+
+type SagaRepository interface {
+	CreateSaga(ctx context.Context, saga *BookingSaga) error
+	UpdateSaga(ctx context.Context, saga *BookingSaga) error
+	GetUnfinishedSagas(ctx context.Context, updatedBefore time.Time) ([]BookingSaga, error)
+}
+
+type HoldRepository interface {
+	CreateHold(ctx context.Context, hold *RoomHold, ttl time.Duration) error
+	GetHoldByID(ctx context.Context, id string) (*RoomHold, error)
+	GetExpiredHolds(ctx context.Context, limit int) ([]RoomHold, error)
+	GetExpiredRoomHolds(ctx context.Context, roomID string, checkIn, checkOut time.Time) ([]RoomHold, error)
+	ExpireHold(ctx context.Context, id string, event *OutboxEvent) error
+}
+
+// WaitlistRepository stores the waitlist. OfferWaitlistEntry creates the hold
+// of an offer together with the offer, so a room is never held for an entry
+// that is no longer waiting.
+type WaitlistRepository interface {
+	CreateWaitlistEntry(ctx context.Context, entry *WaitlistEntry) error
+	GetWaitlistEntryByID(ctx context.Context, id string) (*WaitlistEntry, error)
+	GetWaitlistEntryByHoldID(ctx context.Context, holdID string) (*WaitlistEntry, error)
+	GetWaitingEntries(ctx context.Context, hotelID, roomType string, checkIn, checkOut time.Time) ([]WaitlistEntry, error)
+	OfferWaitlistEntry(ctx context.Context, entry *WaitlistEntry, hold *RoomHold, event *OutboxEvent) error
+	UpdateWaitlistStatus(ctx context.Context, id string, from, to WaitlistStatus) error
+}
+
+// PromotionRepository stores promo codes. Their usage limits are enforced by
+// BookingRepository.CreateBooking, which counts the bookings that use a code.
+type PromotionRepository interface {
+	CreatePromotion(ctx context.Context, promotion *Promotion) error
+	GetPromotionByCode(ctx context.Context, code string) (*Promotion, error)
+	GetPromotions(ctx context.Context) ([]Promotion, error)
+}
+
+// RateProvider returns the exchange rate between two currencies that was in
+// effect at the given time.
+type RateProvider interface {
+	GetRate(ctx context.Context, from, to string, at time.Time) (money.Rate, error)
 }
 
 type BookingUseCase interface {
@@ -16,5 +80,22 @@ type BookingUseCase interface {
 	GetBooking(ctx context.Context, id string) (*Booking, error)
 	GetBookingsByUser(ctx context.Context, userID string) ([]Booking, error)
 	GetBookingsByHotel(ctx context.Context, hotelID string) ([]Booking, error)
-	UpdatePaymentStatus(ctx context.Context, id, status string) error
+	UpdatePaymentStatus(ctx context.Context, id, paymentID, status string) error
+	GetStatusHistory(ctx context.Context, id string) ([]StatusChange, error)
+	CancelBooking(ctx context.Context, id string) (*Booking, error)
+	ModifyBooking(ctx context.Context, id string, change BookingChange) (*Booking, error)
+	CheckIn(ctx context.Context, id, staffID string) (*Booking, error)
+	CheckOut(ctx context.Context, id, staffID string) (*Booking, error)
+	MarkNoShow(ctx context.Context, id, staffID string) (*Booking, error)
+	GetBookedRoomIDs(ctx context.Context, hotelID string, checkIn, checkOut time.Time) ([]string, error)
+	CreateReservation(ctx context.Context, reservation *Reservation) error
+	GetReservation(ctx context.Context, id string) (*Reservation, error)
+	CreateHold(ctx context.Context, hold *RoomHold) error
+	GetHold(ctx context.Context, id string) (*RoomHold, error)
+	CreatePromotion(ctx context.Context, promotion *Promotion) error
+	GetPromotion(ctx context.Context, code string) (*Promotion, error)
+	GetPromotions(ctx context.Context) ([]Promotion, error)
+	JoinWaitlist(ctx context.Context, entry *WaitlistEntry) error
+	GetWaitlistEntry(ctx context.Context, id string) (*WaitlistEntry, error)
+	LeaveWaitlist(ctx context.Context, id string) (*WaitlistEntry, error)
 }
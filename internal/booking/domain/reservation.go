@@ -0,0 +1,45 @@
+package domain
+
+import (
+	"time"
+
+	"hotel-booking-system/pkg/money"
+)
+
+const EventReservationCreated = "reservation.created"
+
+// Reservation groups the bookings of several rooms of one hotel made
+// together. Its rooms are reserved atomically, it is paid with one payment
+// under its own ID and confirmed with one notification; each booking keeps its
+// own dates, guest and status. TotalPrice and DisplayPrice are the sums of
+// those of its bookings.
+type Reservation struct {
+	ID              string      `json:"id"`
+	UserID          string      `json:"user_id"`
+	HotelID         string      `json:"hotel_id"`
+	DisplayCurrency string      `json:"display_currency,omitempty"`
+	TotalPrice      money.Money `json:"total_price"`
+	DisplayPrice    money.Money `json:"display_price"`
+	Bookings        []Booking   `json:"bookings"`
+	CreatedAt       time.Time   `json:"created_at"`
+}
+
+type ReservationEvent struct {
+	ReservationID string         `json:"reservation_id"`
+	UserID        string         `json:"user_id"`
+	HotelID       string         `json:"hotel_id"`
+	Bookings      []BookingEvent `json:"bookings"`
+	TotalPrice    money.Money    `json:"total_price"`
+	DisplayPrice  money.Money    `json:"display_price"`
+	EventType     string         `json:"event_type"`
+	Timestamp     time.Time      `json:"timestamp"`
+}
+
+// PaymentReference is the ID the booking is paid under: that of its
+// reservation, which is paid as a whole, or its own.
+func (b *Booking) PaymentReference() string {
+	if b.ReservationID != "" {
+		return b.ReservationID
+	}
+	return b.ID
+}
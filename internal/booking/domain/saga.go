@@ -0,0 +1,30 @@
+package domain
+
+import "time"
+
+type SagaStep string
+
+const (
+	SagaStepReserve SagaStep = "reserve"
+	SagaStepPay     SagaStep = "pay"
+	SagaStepPublish SagaStep = "publish"
+)
+
+type SagaStatus string
+
+const (
+	SagaRunning     SagaStatus = "running"
+	SagaCompleted   SagaStatus = "completed"
+	SagaCompensated SagaStatus = "compensated"
+)
+
+// BookingSaga tracks the creation of a booking, or of all the bookings of a
+// reservation, in which case BookingID is the reservation's ID.
+type BookingSaga struct {
+	BookingID string
+	Step      SagaStep
+	Status    SagaStatus
+	LastError string
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
@@ -0,0 +1,99 @@
+package domain
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+type BookingStatus string
+
+const (
+	StatusPending         BookingStatus = "pending"
+	StatusAwaitingPayment BookingStatus = "awaiting_payment"
+	StatusConfirmed       BookingStatus = "confirmed"
+	StatusCheckedIn       BookingStatus = "checked_in"
+	StatusCompleted       BookingStatus = "completed"
+	StatusNoShow          BookingStatus = "no_show"
+	StatusCancelled       BookingStatus = "cancelled"
+	StatusExpired         BookingStatus = "expired"
+)
+
+var bookingTransitions = map[BookingStatus][]BookingStatus{
+	StatusPending:         {StatusAwaitingPayment, StatusConfirmed, StatusCancelled, StatusExpired},
+	StatusAwaitingPayment: {StatusConfirmed, StatusCancelled, StatusExpired},
+	StatusConfirmed:       {StatusCheckedIn, StatusCancelled, StatusNoShow},
+	StatusCheckedIn:       {StatusCompleted},
+}
+
+func (s BookingStatus) CanTransitionTo(next BookingStatus) bool {
+	for _, allowed := range bookingTransitions[s] {
+		if allowed == next {
+			return true
+		}
+	}
+	return false
+}
+
+func (s BookingStatus) ValidateTransition(next BookingStatus) error {
+	if !s.CanTransitionTo(next) {
+		return fmt.Errorf("%w: booking status %s -> %s", ErrInvalidTransition, s, next)
+	}
+	return nil
+}
+
+type PaymentStatus string
+
+const (
+	PaymentPending           PaymentStatus = "pending"
+	PaymentPaid              PaymentStatus = "paid"
+	PaymentFailed            PaymentStatus = "failed"
+	PaymentPartiallyRefunded PaymentStatus = "partially_refunded"
+	PaymentRefunded          PaymentStatus = "refunded"
+	PaymentAuthorized        PaymentStatus = "authorized"
+	PaymentVoided            PaymentStatus = "voided"
+	PaymentExpired           PaymentStatus = "expired"
+)
+
+var paymentTransitions = map[PaymentStatus][]PaymentStatus{
+	PaymentPending:           {PaymentPaid, PaymentFailed, PaymentAuthorized},
+	PaymentAuthorized:        {PaymentPaid, PaymentVoided, PaymentExpired},
+	PaymentPaid:              {PaymentPartiallyRefunded, PaymentRefunded},
+	PaymentPartiallyRefunded: {PaymentRefunded},
+}
+
+func ParsePaymentStatus(status string) (PaymentStatus, error) {
+	parsed := PaymentStatus(strings.ToLower(status))
+	switch parsed {
+	case PaymentPending, PaymentPaid, PaymentFailed, PaymentPartiallyRefunded, PaymentRefunded,
+		PaymentAuthorized, PaymentVoided, PaymentExpired:
+		return parsed, nil
+	default:
+		return "", fmt.Errorf("%w: %s", ErrInvalidPaymentStatus, status)
+	}
+}
+
+func (s PaymentStatus) CanTransitionTo(next PaymentStatus) bool {
+	for _, allowed := range paymentTransitions[s] {
+		if allowed == next {
+			return true
+		}
+	}
+	return false
+}
+
+func (s PaymentStatus) ValidateTransition(next PaymentStatus) error {
+	if !s.CanTransitionTo(next) {
+		return fmt.Errorf("%w: payment status %s -> %s", ErrInvalidTransition, s, next)
+	}
+	return nil
+}
+
+type StatusChange struct {
+	ID         string    `json:"id"`
+	BookingID  string    `json:"booking_id"`
+	Field      string    `json:"field"`
+	FromStatus string    `json:"from_status"`
+	ToStatus   string    `json:"to_status"`
+	CreatedAt  time.Time `json:"created_at"`
+}
@@ -0,0 +1,73 @@
+package domain
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBookingStatus_CanTransitionTo(t *testing.T) {
+	tests := []struct {
+		from    BookingStatus
+		to      BookingStatus
+		allowed bool
+	}{
+		{StatusPending, StatusAwaitingPayment, true},
+		{StatusPending, StatusConfirmed, true},
+		{StatusAwaitingPayment, StatusConfirmed, true},
+		{StatusAwaitingPayment, StatusExpired, true},
+		{StatusConfirmed, StatusCheckedIn, true},
+		{StatusConfirmed, StatusCancelled, true},
+		{StatusCheckedIn, StatusCompleted, true},
+		{StatusConfirmed, StatusNoShow, true},
+		{StatusConfirmed, StatusPending, false},
+		{StatusCheckedIn, StatusCancelled, false},
+		{StatusCancelled, StatusConfirmed, false},
+		{StatusCompleted, StatusCancelled, false},
+		{StatusExpired, StatusConfirmed, false},
+		{StatusAwaitingPayment, StatusNoShow, false},
+		{StatusCheckedIn, StatusNoShow, false},
+		{StatusNoShow, StatusCheckedIn, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.from)+"->"+string(tt.to), func(t *testing.T) {
+			assert.Equal(t, tt.allowed, tt.from.CanTransitionTo(tt.to))
+		})
+	}
+}
+
+func TestBookingStatus_ValidateTransition(t *testing.T) {
+	assert.NoError(t, StatusPending.ValidateTransition(StatusConfirmed))
+
+	err := StatusCancelled.ValidateTransition(StatusConfirmed)
+	assert.ErrorIs(t, err, ErrInvalidTransition)
+	assert.Contains(t, err.Error(), "cancelled -> confirmed")
+}
+
+func TestParsePaymentStatus(t *testing.T) {
+	status, err := ParsePaymentStatus("PAID")
+	assert.NoError(t, err)
+	assert.Equal(t, PaymentPaid, status)
+
+	_, err = ParsePaymentStatus("unknown")
+	assert.ErrorIs(t, err, ErrInvalidPaymentStatus)
+}
+
+func TestPaymentStatus_ValidateTransition(t *testing.T) {
+	assert.NoError(t, PaymentPending.ValidateTransition(PaymentPaid))
+	assert.NoError(t, PaymentPending.ValidateTransition(PaymentFailed))
+	assert.NoError(t, PaymentPaid.ValidateTransition(PaymentRefunded))
+	assert.NoError(t, PaymentPaid.ValidateTransition(PaymentPartiallyRefunded))
+	assert.NoError(t, PaymentPartiallyRefunded.ValidateTransition(PaymentRefunded))
+	assert.ErrorIs(t, PaymentPartiallyRefunded.ValidateTransition(PaymentPaid), ErrInvalidTransition)
+	assert.ErrorIs(t, PaymentRefunded.ValidateTransition(PaymentPaid), ErrInvalidTransition)
+	assert.ErrorIs(t, PaymentFailed.ValidateTransition(PaymentPaid), ErrInvalidTransition)
+	assert.NoError(t, PaymentPending.ValidateTransition(PaymentAuthorized))
+	assert.NoError(t, PaymentAuthorized.ValidateTransition(PaymentPaid))
+	assert.NoError(t, PaymentAuthorized.ValidateTransition(PaymentVoided))
+	assert.NoError(t, PaymentAuthorized.ValidateTransition(PaymentExpired))
+	assert.ErrorIs(t, PaymentVoided.ValidateTransition(PaymentPaid), ErrInvalidTransition)
+	assert.ErrorIs(t, PaymentExpired.ValidateTransition(PaymentPaid), ErrInvalidTransition)
+	assert.ErrorIs(t, PaymentAuthorized.ValidateTransition(PaymentRefunded), ErrInvalidTransition)
+}
@@ -0,0 +1,44 @@
+package domain
+
+import "time"
+
+type WaitlistStatus string
+
+const (
+	WaitlistWaiting   WaitlistStatus = "waiting"
+	WaitlistOffered   WaitlistStatus = "offered"
+	WaitlistBooked    WaitlistStatus = "booked"
+	WaitlistExpired   WaitlistStatus = "expired"
+	WaitlistCancelled WaitlistStatus = "cancelled"
+)
+
+// WaitlistEntry is a guest waiting for a room of a type to free up for their
+// dates. An offer holds the freed room for the guest until OfferExpiresAt;
+// the guest takes it by booking with HoldID.
+type WaitlistEntry struct {
+	ID             string         `json:"id"`
+	UserID         string         `json:"user_id"`
+	HotelID        string         `json:"hotel_id"`
+	RoomType       string         `json:"room_type"`
+	CheckInDate    time.Time      `json:"check_in_date"`
+	CheckOutDate   time.Time      `json:"check_out_date"`
+	Status         WaitlistStatus `json:"status"`
+	RoomID         string         `json:"room_id,omitempty"`
+	HoldID         string         `json:"hold_id,omitempty"`
+	OfferExpiresAt *time.Time     `json:"offer_expires_at,omitempty"`
+	CreatedAt      time.Time      `json:"created_at"`
+}
+
+type WaitlistEvent struct {
+	EntryID      string    `json:"entry_id"`
+	UserID       string    `json:"user_id"`
+	HotelID      string    `json:"hotel_id"`
+	RoomType     string    `json:"room_type"`
+	RoomID       string    `json:"room_id"`
+	HoldID       string    `json:"hold_id"`
+	CheckInDate  time.Time `json:"check_in_date"`
+	CheckOutDate time.Time `json:"check_out_date"`
+	ExpiresAt    time.Time `json:"expires_at"`
+	EventType    string    `json:"event_type"`
+	Timestamp    time.Time `json:"timestamp"`
+}
@@ -3,10 +3,18 @@ package repository
 import (
 	"context"
 	"database/sql"
+	"encoding/json"
+	"errors"
+	"time"
 
 	"hotel-booking-system/internal/booking/domain"
+	"hotel-booking-system/pkg/money"
+
+	"github.com/lib/pq"
 )
 
+const exclusionViolation = "23P01"
+
 type PostgresBookingRepository struct {
 	db *sql.DB
 }
@@ -15,39 +23,278 @@ func NewPostgresBookingRepository(db *sql.DB) *PostgresBookingRepository {
 	return &PostgresBookingRepository{db: db}
 }
 
+// CreateBooking inserts the booking together with the redemption of its promo
+// code, if any, so that a code's usage limits hold under concurrent bookings,
+// and with the conversion of the hold it is made from, so that a hold is never
+// used up without its booking.
 func (r *PostgresBookingRepository) CreateBooking(ctx context.Context, booking *domain.Booking) error {
-	query := `INSERT INTO bookings (id, user_id, hotel_id, room_id, check_in_date, check_out_date, 
-			  total_price, status, payment_status) 
-			  VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9) 
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if booking.PromoCode != "" {
+		if err := redeemPromoCode(ctx, tx, booking.PromoCode, booking.UserID); err != nil {
+			return err
+		}
+	}
+	if booking.HoldID != "" {
+		if err := convertHold(ctx, tx, booking.HoldID, booking.ID); err != nil {
+			return err
+		}
+	}
+
+	if err := insertBooking(ctx, tx, booking); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// convertHold hands the room held by a hold that has not expired over to the
+// booking made from it and marks the waitlist offer made with the hold, if
+// any, as booked.
+func convertHold(ctx context.Context, tx *sql.Tx, holdID, bookingID string) error {
+	query := `UPDATE room_holds SET status = $3, booking_id = $2 
+			  WHERE id = $1 AND status = $4 AND expires_at > CURRENT_TIMESTAMP`
+	result, err := tx.ExecContext(ctx, query, holdID, bookingID, domain.HoldConverted, domain.HoldActive)
+	if err != nil {
+		return err
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return domain.ErrHoldNotActive
+	}
+
+	offerQuery := `UPDATE waitlist_entries SET status = $2 WHERE hold_id = $1 AND status = $3`
+	_, err = tx.ExecContext(ctx, offerQuery, holdID, domain.WaitlistBooked, domain.WaitlistOffered)
+	return err
+}
+
+// CreateReservation inserts the reservation and all its bookings in one
+// transaction, so either every room is reserved or none is.
+func (r *PostgresBookingRepository) CreateReservation(ctx context.Context, reservation *domain.Reservation) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	query := `INSERT INTO reservations (id, user_id, hotel_id, total_price, currency, display_price, display_currency) 
+			  VALUES ($1, $2, $3, $4, $5, $6, $7) 
+			  RETURNING created_at`
+	if err := tx.QueryRowContext(ctx, query,
+		reservation.ID, reservation.UserID, reservation.HotelID, reservation.TotalPrice,
+		reservation.TotalPrice.Currency, reservation.DisplayPrice, reservation.DisplayCurrency,
+	).Scan(&reservation.CreatedAt); err != nil {
+		return err
+	}
+
+	for i := range reservation.Bookings {
+		if err := insertBooking(ctx, tx, &reservation.Bookings[i]); err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+func insertBooking(ctx context.Context, tx *sql.Tx, booking *domain.Booking) error {
+	if err := lockRoomAgainstHolds(ctx, tx, booking.RoomID, booking.CheckInDate, booking.CheckOutDate); err != nil {
+		return err
+	}
+
+	breakdown, err := marshalBreakdown(booking.PriceBreakdown)
+	if err != nil {
+		return err
+	}
+	policy, err := marshalPolicy(booking.CancellationPolicy)
+	if err != nil {
+		return err
+	}
+
+	query := `INSERT INTO bookings (id, user_id, hotel_id, room_id, room_type, reservation_id, guest_name, adults, children, 
+			  check_in_date, check_out_date, total_price, currency, price_breakdown, display_price, display_currency, 
+			  exchange_rate, promo_code, cancellation_policy, status, payment_status) 
+			  VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19, $20, $21) 
 			  RETURNING created_at, updated_at`
-	return r.db.QueryRowContext(ctx, query,
-		booking.ID, booking.UserID, booking.HotelID, booking.RoomID,
-		booking.CheckInDate, booking.CheckOutDate, booking.TotalPrice,
-		booking.Status, booking.PaymentStatus,
+	err = tx.QueryRowContext(ctx, query,
+		booking.ID, booking.UserID, booking.HotelID, booking.RoomID, booking.RoomType, nullable(booking.ReservationID), booking.GuestName,
+		booking.Adults, booking.Children, booking.CheckInDate, booking.CheckOutDate, booking.TotalPrice, booking.TotalPrice.Currency,
+		breakdown, booking.DisplayPrice, booking.DisplayCurrency, booking.ExchangeRate, nullable(booking.PromoCode),
+		policy, booking.Status, booking.PaymentStatus,
 	).Scan(&booking.CreatedAt, &booking.UpdatedAt)
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) && pqErr.Code == exclusionViolation {
+		return domain.ErrRoomNotAvailable
+	}
+	return err
+}
+
+// nullable stores an empty string as NULL.
+func nullable(s string) interface{} {
+	if s == "" {
+		return nil
+	}
+	return s
+}
+
+// redeemPromoCode checks the code's usage limits against the bookings that use
+// it and are not cancelled or expired. Locking the promotion row makes
+// concurrent bookings with the same code take turns, so each one counts the
+// bookings committed before it.
+func redeemPromoCode(ctx context.Context, tx *sql.Tx, code, userID string) error {
+	var maxUses, maxUsesPerUser int
+	query := `SELECT max_uses, max_uses_per_user FROM promotions WHERE code = $1 FOR UPDATE`
+	err := tx.QueryRowContext(ctx, query, code).Scan(&maxUses, &maxUsesPerUser)
+	if errors.Is(err, sql.ErrNoRows) {
+		return domain.ErrPromoCodeInvalid
+	}
+	if err != nil {
+		return err
+	}
+
+	var uses, userUses int
+	query = `SELECT COUNT(*), COUNT(*) FILTER (WHERE user_id = $2) FROM bookings 
+			  WHERE promo_code = $1 AND status NOT IN ('cancelled', 'expired')`
+	if err := tx.QueryRowContext(ctx, query, code, userID).Scan(&uses, &userUses); err != nil {
+		return err
+	}
+	if (maxUses > 0 && uses >= maxUses) || (maxUsesPerUser > 0 && userUses >= maxUsesPerUser) {
+		return domain.ErrPromoCodeExhausted
+	}
+	return nil
+}
+
+func marshalBreakdown(items []domain.PriceItem) (string, error) {
+	if items == nil {
+		items = []domain.PriceItem{}
+	}
+	data, err := json.Marshal(items)
+	return string(data), err
+}
+
+// marshalPolicy stores a booking without a cancellation policy as NULL.
+func marshalPolicy(policy *domain.CancellationPolicy) (interface{}, error) {
+	if policy == nil {
+		return nil, nil
+	}
+	data, err := json.Marshal(policy)
+	if err != nil {
+		return nil, err
+	}
+	return string(data), nil
+}
+
+const bookingColumns = `id, user_id, hotel_id, room_id, room_type, COALESCE(reservation_id::text, ''), guest_name, 
+			  adults, children, check_in_date, check_out_date, 
+			  total_price, currency, price_breakdown, display_price, display_currency, exchange_rate, 
+			  COALESCE(promo_code, ''), cancellation_policy, checked_in_at, COALESCE(checked_in_by, ''), 
+			  checked_out_at, COALESCE(checked_out_by, ''), no_show_at, COALESCE(no_show_by, ''), 
+			  status, payment_status, created_at, updated_at`
+
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+func scanBooking(row rowScanner, booking *domain.Booking) error {
+	var totalPrice, currency, displayPrice, rate string
+	var breakdown, policy []byte
+	if err := row.Scan(
+		&booking.ID, &booking.UserID, &booking.HotelID, &booking.RoomID, &booking.RoomType, &booking.ReservationID, &booking.GuestName,
+		&booking.Adults, &booking.Children, &booking.CheckInDate, &booking.CheckOutDate, &totalPrice, &currency,
+		&breakdown, &displayPrice, &booking.DisplayCurrency, &rate, &booking.PromoCode,
+		&policy, &booking.CheckedInAt, &booking.CheckedInBy, &booking.CheckedOutAt, &booking.CheckedOutBy,
+		&booking.NoShowAt, &booking.NoShowBy, &booking.Status, &booking.PaymentStatus, &booking.CreatedAt, &booking.UpdatedAt,
+	); err != nil {
+		return err
+	}
+	var err error
+	if booking.TotalPrice, err = money.Parse(totalPrice, currency); err != nil {
+		return err
+	}
+	if err := json.Unmarshal(breakdown, &booking.PriceBreakdown); err != nil {
+		return err
+	}
+	if policy != nil {
+		if err := json.Unmarshal(policy, &booking.CancellationPolicy); err != nil {
+			return err
+		}
+	}
+	if booking.DisplayPrice, err = money.Parse(displayPrice, booking.DisplayCurrency); err != nil {
+		return err
+	}
+	booking.ExchangeRate, err = money.ParseRate(currency, booking.DisplayCurrency, rate)
+	return err
 }
 
 func (r *PostgresBookingRepository) GetBookingByID(ctx context.Context, id string) (*domain.Booking, error) {
 	booking := &domain.Booking{}
-	query := `SELECT id, user_id, hotel_id, room_id, check_in_date, check_out_date, 
-			  total_price, status, payment_status, created_at, updated_at 
-			  FROM bookings WHERE id = $1`
-	err := r.db.QueryRowContext(ctx, query, id).Scan(
-		&booking.ID, &booking.UserID, &booking.HotelID, &booking.RoomID,
-		&booking.CheckInDate, &booking.CheckOutDate, &booking.TotalPrice,
-		&booking.Status, &booking.PaymentStatus, &booking.CreatedAt, &booking.UpdatedAt,
-	)
-	if err != nil {
+	query := `SELECT ` + bookingColumns + ` FROM bookings WHERE id = $1`
+	if err := scanBooking(r.db.QueryRowContext(ctx, query, id), booking); err != nil {
 		return nil, err
 	}
 	return booking, nil
 }
 
+// GetReservationByID returns the reservation with its bookings, earliest
+// check-in first.
+func (r *PostgresBookingRepository) GetReservationByID(ctx context.Context, id string) (*domain.Reservation, error) {
+	reservation := &domain.Reservation{}
+	var totalPrice, currency, displayPrice string
+	query := `SELECT id, user_id, hotel_id, total_price, currency, display_price, display_currency, created_at 
+			  FROM reservations WHERE id = $1`
+	if err := r.db.QueryRowContext(ctx, query, id).Scan(
+		&reservation.ID, &reservation.UserID, &reservation.HotelID, &totalPrice, &currency,
+		&displayPrice, &reservation.DisplayCurrency, &reservation.CreatedAt,
+	); err != nil {
+		return nil, err
+	}
+	var err error
+	if reservation.TotalPrice, err = money.Parse(totalPrice, currency); err != nil {
+		return nil, err
+	}
+	if reservation.DisplayPrice, err = money.Parse(displayPrice, reservation.DisplayCurrency); err != nil {
+		return nil, err
+	}
+
+	query = `SELECT ` + bookingColumns + ` FROM bookings WHERE reservation_id = $1 ORDER BY check_in_date, id`
+	if reservation.Bookings, err = r.queryBookings(ctx, query, id); err != nil {
+		return nil, err
+	}
+	return reservation, nil
+}
+
 func (r *PostgresBookingRepository) GetBookingsByUser(ctx context.Context, userID string) ([]domain.Booking, error) {
-	query := `SELECT id, user_id, hotel_id, room_id, check_in_date, check_out_date, 
-			  total_price, status, payment_status, created_at, updated_at 
-			  FROM bookings WHERE user_id = $1 ORDER BY created_at DESC`
-	rows, err := r.db.QueryContext(ctx, query, userID)
+	query := `SELECT ` + bookingColumns + ` FROM bookings WHERE user_id = $1 ORDER BY created_at DESC`
+	return r.queryBookings(ctx, query, userID)
+}
+
+func (r *PostgresBookingRepository) GetBookingsByHotel(ctx context.Context, hotelID string) ([]domain.Booking, error) {
+	query := `SELECT ` + bookingColumns + ` FROM bookings WHERE hotel_id = $1 ORDER BY created_at DESC`
+	return r.queryBookings(ctx, query, hotelID)
+}
+
+// GetUnattendedBookings returns the confirmed bookings whose check-in date
+// is not after the given time, so their guests should have arrived by then.
+func (r *PostgresBookingRepository) GetUnattendedBookings(ctx context.Context, checkInBefore time.Time) ([]domain.Booking, error) {
+	query := `SELECT ` + bookingColumns + ` FROM bookings 
+			  WHERE status = 'confirmed' AND check_in_date <= $1 ORDER BY check_in_date, id`
+	return r.queryBookings(ctx, query, checkInBefore)
+}
+
+// GetUnpaidBookings returns the bookings that have been awaiting payment
+// since before the given time, oldest first.
+func (r *PostgresBookingRepository) GetUnpaidBookings(ctx context.Context, updatedBefore time.Time, limit int) ([]domain.Booking, error) {
+	query := `SELECT ` + bookingColumns + ` FROM bookings 
+			  WHERE status = 'awaiting_payment' AND updated_at <= $1 ORDER BY updated_at, id LIMIT $2`
+	return r.queryBookings(ctx, query, updatedBefore, limit)
+}
+
+func (r *PostgresBookingRepository) queryBookings(ctx context.Context, query string, args ...interface{}) ([]domain.Booking, error) {
+	rows, err := r.db.QueryContext(ctx, query, args...)
 	if err != nil {
 		return nil, err
 	}
@@ -56,11 +303,7 @@ func (r *PostgresBookingRepository) GetBookingsByUser(ctx context.Context, userI
 	var bookings []domain.Booking
 	for rows.Next() {
 		var booking domain.Booking
-		if err := rows.Scan(
-			&booking.ID, &booking.UserID, &booking.HotelID, &booking.RoomID,
-			&booking.CheckInDate, &booking.CheckOutDate, &booking.TotalPrice,
-			&booking.Status, &booking.PaymentStatus, &booking.CreatedAt, &booking.UpdatedAt,
-		); err != nil {
+		if err := scanBooking(rows, &booking); err != nil {
 			return nil, err
 		}
 		bookings = append(bookings, booking)
@@ -68,39 +311,186 @@ func (r *PostgresBookingRepository) GetBookingsByUser(ctx context.Context, userI
 	return bookings, rows.Err()
 }
 
-func (r *PostgresBookingRepository) GetBookingsByHotel(ctx context.Context, hotelID string) ([]domain.Booking, error) {
-	query := `SELECT id, user_id, hotel_id, room_id, check_in_date, check_out_date, 
-			  total_price, status, payment_status, created_at, updated_at 
-			  FROM bookings WHERE hotel_id = $1 ORDER BY created_at DESC`
-	rows, err := r.db.QueryContext(ctx, query, hotelID)
+func (r *PostgresBookingRepository) UpdateBookingStatus(ctx context.Context, id string, from, to domain.BookingStatus, event *domain.OutboxEvent) error {
+	query := `UPDATE bookings SET status = $3, updated_at = CURRENT_TIMESTAMP WHERE id = $1 AND status = $2`
+	return r.transition(ctx, query, id, "status", string(from), string(to), event)
+}
+
+func (r *PostgresBookingRepository) UpdatePaymentStatus(ctx context.Context, id string, from, to domain.PaymentStatus) error {
+	query := `UPDATE bookings SET payment_status = $3, updated_at = CURRENT_TIMESTAMP WHERE id = $1 AND payment_status = $2`
+	return r.transition(ctx, query, id, "payment_status", string(from), string(to), nil)
+}
+
+// UpdateStayStatus moves the booking on from the given status together with
+// its check-in, check-out and no-show marks.
+func (r *PostgresBookingRepository) UpdateStayStatus(ctx context.Context, booking *domain.Booking, from domain.BookingStatus, event *domain.OutboxEvent) error {
+	query := `UPDATE bookings SET status = $3, checked_in_at = $4, checked_in_by = $5, checked_out_at = $6, 
+			  checked_out_by = $7, no_show_at = $8, no_show_by = $9, updated_at = CURRENT_TIMESTAMP 
+			  WHERE id = $1 AND status = $2`
+	return r.transition(ctx, query, booking.ID, "status", string(from), string(booking.Status), event,
+		booking.CheckedInAt, nullable(booking.CheckedInBy), booking.CheckedOutAt, nullable(booking.CheckedOutBy),
+		booking.NoShowAt, nullable(booking.NoShowBy))
+}
+
+// transition runs an update of the given field conditional on its current
+// value; the query takes the ID, the current and the new value followed by
+// args.
+func (r *PostgresBookingRepository) transition(ctx context.Context, query, id, field, from, to string, event *domain.OutboxEvent, args ...interface{}) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	result, err := tx.ExecContext(ctx, query, append([]interface{}{id, from, to}, args...)...)
+	if err != nil {
+		return err
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return domain.ErrStatusChanged
+	}
+
+	historyQuery := `INSERT INTO booking_status_history (booking_id, field, from_status, to_status) 
+			  VALUES ($1, $2, $3, $4)`
+	if _, err := tx.ExecContext(ctx, historyQuery, id, field, from, to); err != nil {
+		return err
+	}
+
+	if err := insertOutboxEvent(ctx, tx, event); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// ModifyBooking stores the booking's new room, dates and price together with
+// the event that reports the change. A modification whose price difference is
+// still to be settled is recorded in the same transaction; a booking has at
+// most one such modification at a time. It fails with ErrStatusChanged if the
+// booking's status changed since it was read.
+func (r *PostgresBookingRepository) ModifyBooking(ctx context.Context, booking *domain.Booking, modification *domain.BookingModification, event *domain.OutboxEvent) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err := updateStay(ctx, tx, booking); err != nil {
+		return err
+	}
+
+	if modification != nil {
+		if err := insertModification(ctx, tx, modification); err != nil {
+			return err
+		}
+	}
+
+	if err := insertOutboxEvent(ctx, tx, event); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// updateStay moves the booking to its room and dates, provided it is still
+// in the status it was read in.
+func updateStay(ctx context.Context, tx *sql.Tx, booking *domain.Booking) error {
+	breakdown, err := marshalBreakdown(booking.PriceBreakdown)
+	if err != nil {
+		return err
+	}
+	policy, err := marshalPolicy(booking.CancellationPolicy)
+	if err != nil {
+		return err
+	}
+
+	if err := lockRoomAgainstHolds(ctx, tx, booking.RoomID, booking.CheckInDate, booking.CheckOutDate); err != nil {
+		return err
+	}
+
+	query := `UPDATE bookings SET room_id = $3, room_type = $4, check_in_date = $5, check_out_date = $6, total_price = $7, 
+			  price_breakdown = $8, display_price = $9, cancellation_policy = $10, updated_at = CURRENT_TIMESTAMP 
+			  WHERE id = $1 AND status = $2 
+			  RETURNING updated_at`
+	err = tx.QueryRowContext(ctx, query,
+		booking.ID, booking.Status, booking.RoomID, booking.RoomType, booking.CheckInDate, booking.CheckOutDate,
+		booking.TotalPrice, breakdown, booking.DisplayPrice, policy,
+	).Scan(&booking.UpdatedAt)
+	var pqErr *pq.Error
+	switch {
+	case errors.As(err, &pqErr) && pqErr.Code == exclusionViolation:
+		return domain.ErrRoomNotAvailable
+	case errors.Is(err, sql.ErrNoRows):
+		return domain.ErrStatusChanged
+	}
+	return err
+}
+
+func (r *PostgresBookingRepository) GetStatusHistory(ctx context.Context, bookingID string) ([]domain.StatusChange, error) {
+	query := `SELECT id, booking_id, field, from_status, to_status, created_at 
+			  FROM booking_status_history WHERE booking_id = $1 ORDER BY created_at, id`
+	rows, err := r.db.QueryContext(ctx, query, bookingID)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
 
-	var bookings []domain.Booking
+	history := []domain.StatusChange{}
 	for rows.Next() {
-		var booking domain.Booking
+		var change domain.StatusChange
 		if err := rows.Scan(
-			&booking.ID, &booking.UserID, &booking.HotelID, &booking.RoomID,
-			&booking.CheckInDate, &booking.CheckOutDate, &booking.TotalPrice,
-			&booking.Status, &booking.PaymentStatus, &booking.CreatedAt, &booking.UpdatedAt,
+			&change.ID, &change.BookingID, &change.Field,
+			&change.FromStatus, &change.ToStatus, &change.CreatedAt,
 		); err != nil {
 			return nil, err
 		}
-		bookings = append(bookings, booking)
+		history = append(history, change)
 	}
-	return bookings, rows.Err()
+	return history, rows.Err()
 }
 
-func (r *PostgresBookingRepository) UpdateBookingStatus(ctx context.Context, id, status string) error {
-	query := `UPDATE bookings SET status = $2, updated_at = CURRENT_TIMESTAMP WHERE id = $1`
-	_, err := r.db.ExecContext(ctx, query, id, status)
-	return err
+// HasOverlappingBooking reports whether the room is booked or held for any
+// of the dates, ignoring the booking with excludeBookingID so that a booking
+// can be moved to dates overlapping its own.
+func (r *PostgresBookingRepository) HasOverlappingBooking(ctx context.Context, roomID string, checkIn, checkOut time.Time, excludeBookingID string) (bool, error) {
+	var exists bool
+	query := `SELECT EXISTS (
+			  SELECT 1 FROM bookings 
+			  WHERE room_id = $1 AND status NOT IN ('cancelled', 'expired') AND id::text <> $4 
+			  AND daterange(check_in_date, check_out_date) && daterange($2::date, $3::date)
+			  UNION ALL
+			  SELECT 1 FROM room_holds 
+			  WHERE room_id = $1 AND status = 'active' AND expires_at > CURRENT_TIMESTAMP 
+			  AND daterange(check_in_date, check_out_date) && daterange($2::date, $3::date))`
+	err := r.db.QueryRowContext(ctx, query, roomID, checkIn, checkOut, excludeBookingID).Scan(&exists)
+	return exists, err
 }
 
-func (r *PostgresBookingRepository) UpdatePaymentStatus(ctx context.Context, id, paymentStatus string) error {
-	query := `UPDATE bookings SET payment_status = $2, updated_at = CURRENT_TIMESTAMP WHERE id = $1`
-	_, err := r.db.ExecContext(ctx, query, id, paymentStatus)
-	return err
+func (r *PostgresBookingRepository) GetBookedRoomIDs(ctx context.Context, hotelID string, checkIn, checkOut time.Time) ([]string, error) {
+	query := `SELECT room_id FROM bookings 
+			  WHERE hotel_id = $1 AND status NOT IN ('cancelled', 'expired') 
+			  AND daterange(check_in_date, check_out_date) && daterange($2::date, $3::date)
+			  UNION
+			  SELECT room_id FROM room_holds 
+			  WHERE hotel_id = $1 AND status = 'active' AND expires_at > CURRENT_TIMESTAMP 
+			  AND daterange(check_in_date, check_out_date) && daterange($2::date, $3::date)`
+	rows, err := r.db.QueryContext(ctx, query, hotelID, checkIn, checkOut)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	roomIDs := []string{}
+	for rows.Next() {
+		var roomID string
+		if err := rows.Scan(&roomID); err != nil {
+			return nil, err
+		}
+		roomIDs = append(roomIDs, roomID)
+	}
+	return roomIDs, rows.Err()
 }
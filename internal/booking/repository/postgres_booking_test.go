@@ -8,8 +8,10 @@ import (
 	"time"
 
 	"hotel-booking-system/internal/booking/domain"
+	"hotel-booking-system/pkg/money"
 
 	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/lib/pq"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -21,6 +23,15 @@ func setupMockDB(t *testing.T) (*sql.DB, sqlmock.Sqlmock) {
 	return db, mock
 }
 
+// expectRoomFree expects the room lock and the check of the other table of
+// holds and bookings that precede an insert into one of them.
+func expectRoomFree(mock sqlmock.Sqlmock, table string) {
+	mock.ExpectExec(`SELECT pg_advisory_xact_lock\(hashtext\(\$1\)\)`).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectQuery(`SELECT EXISTS \(\s*SELECT 1 FROM ` + table + ` `).
+		WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(false))
+}
+
 func TestNewPostgresBookingRepository(t *testing.T) {
 	db, _ := setupMockDB(t)
 	defer db.Close()
@@ -35,14 +46,28 @@ func TestCreateBooking_Success(t *testing.T) {
 	defer db.Close()
 
 	repo := NewPostgresBookingRepository(db)
+	rate, err := money.ParseRate("RUB", "USD", "0.01081081")
+	assert.NoError(t, err)
 	booking := &domain.Booking{
-		ID:            "booking-123",
-		UserID:        "user-123",
-		HotelID:       "hotel-123",
-		RoomID:        "room-123",
-		CheckInDate:   time.Now(),
-		CheckOutDate:  time.Now().Add(24 * time.Hour),
-		TotalPrice:    5000.0,
+		ID:           "booking-123",
+		UserID:       "user-123",
+		HotelID:      "hotel-123",
+		RoomID:       "room-123",
+		CheckInDate:  time.Now(),
+		CheckOutDate: time.Now().Add(24 * time.Hour),
+		TotalPrice:   money.New(500000, "RUB"),
+		PriceBreakdown: []domain.PriceItem{
+			{Kind: domain.PriceItemAccommodation, Amount: money.New(490000, "RUB")},
+			{Kind: domain.PriceItemTax, Name: "Туристический налог", Amount: money.New(10000, "RUB")},
+		},
+		DisplayCurrency: "USD",
+		DisplayPrice:    money.New(5405, "USD"),
+		ExchangeRate:    rate,
+		CancellationPolicy: &domain.CancellationPolicy{
+			ID:    "policy-123",
+			Name:  "Гибкий",
+			Rules: []domain.CancellationRule{{HoursBeforeCheckIn: 48, RefundBasisPoints: 10000}},
+		},
 		Status:        "pending",
 		PaymentStatus: "pending",
 	}
@@ -50,22 +75,113 @@ func TestCreateBooking_Success(t *testing.T) {
 	createdAt := time.Now()
 	updatedAt := time.Now()
 
+	mock.ExpectBegin()
+	expectRoomFree(mock, "room_holds")
 	mock.ExpectQuery(`INSERT INTO bookings`).
 		WithArgs(
-			booking.ID, booking.UserID, booking.HotelID, booking.RoomID,
-			booking.CheckInDate, booking.CheckOutDate, booking.TotalPrice,
+			booking.ID, booking.UserID, booking.HotelID, booking.RoomID, booking.RoomType, nil, "",
+			booking.Adults, booking.Children, booking.CheckInDate, booking.CheckOutDate, booking.TotalPrice, booking.TotalPrice.Currency,
+			`[{"kind":"accommodation","amount":{"amount":"4900.00","currency":"RUB"}},`+
+				`{"kind":"tax","name":"Туристический налог","amount":{"amount":"100.00","currency":"RUB"}}]`,
+			booking.DisplayPrice, booking.DisplayCurrency, booking.ExchangeRate, nil,
+			`{"id":"policy-123","name":"Гибкий","rules":[{"hours_before_check_in":48,"refund_basis_points":10000}]}`,
 			booking.Status, booking.PaymentStatus,
 		).
 		WillReturnRows(sqlmock.NewRows([]string{"created_at", "updated_at"}).
 			AddRow(createdAt, updatedAt))
+	mock.ExpectCommit()
 
-	err := repo.CreateBooking(context.Background(), booking)
+	err = repo.CreateBooking(context.Background(), booking)
 	assert.NoError(t, err)
 	assert.Equal(t, createdAt, booking.CreatedAt)
 	assert.Equal(t, updatedAt, booking.UpdatedAt)
 	assert.NoError(t, mock.ExpectationsWereMet())
 }
 
+func TestCreateBooking_FromHold(t *testing.T) {
+	newHoldBooking := func() *domain.Booking {
+		return &domain.Booking{
+			ID:            "booking-123",
+			UserID:        "user-123",
+			HotelID:       "hotel-123",
+			RoomID:        "room-123",
+			HoldID:        "hold-123",
+			CheckInDate:   time.Now(),
+			CheckOutDate:  time.Now().Add(24 * time.Hour),
+			TotalPrice:    money.New(500000, "RUB"),
+			Status:        "pending",
+			PaymentStatus: "pending",
+		}
+	}
+
+	t.Run("converts the hold with the insert", func(t *testing.T) {
+		db, mock := setupMockDB(t)
+		defer db.Close()
+
+		repo := NewPostgresBookingRepository(db)
+
+		mock.ExpectBegin()
+		mock.ExpectExec(`UPDATE room_holds SET status = \$3, booking_id = \$2 .*expires_at > CURRENT_TIMESTAMP`).
+			WithArgs("hold-123", "booking-123", domain.HoldConverted, domain.HoldActive).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectExec(`UPDATE waitlist_entries SET status = \$2 WHERE hold_id = \$1 AND status = \$3`).
+			WithArgs("hold-123", domain.WaitlistBooked, domain.WaitlistOffered).
+			WillReturnResult(sqlmock.NewResult(0, 0))
+		expectRoomFree(mock, "room_holds")
+		mock.ExpectQuery(`INSERT INTO bookings`).
+			WillReturnRows(sqlmock.NewRows([]string{"created_at", "updated_at"}).AddRow(time.Now(), time.Now()))
+		mock.ExpectCommit()
+
+		err := repo.CreateBooking(context.Background(), newHoldBooking())
+		assert.NoError(t, err)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("hold expired or used", func(t *testing.T) {
+		db, mock := setupMockDB(t)
+		defer db.Close()
+
+		repo := NewPostgresBookingRepository(db)
+
+		mock.ExpectBegin()
+		mock.ExpectExec(`UPDATE room_holds SET status = \$3`).
+			WithArgs("hold-123", "booking-123", domain.HoldConverted, domain.HoldActive).
+			WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectRollback()
+
+		err := repo.CreateBooking(context.Background(), newHoldBooking())
+		assert.ErrorIs(t, err, domain.ErrHoldNotActive)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestCreateBooking_RoomHeld(t *testing.T) {
+	db, mock := setupMockDB(t)
+	defer db.Close()
+
+	repo := NewPostgresBookingRepository(db)
+	booking := &domain.Booking{
+		ID:           "booking-123",
+		RoomID:       "room-123",
+		CheckInDate:  time.Now(),
+		CheckOutDate: time.Now().Add(24 * time.Hour),
+		TotalPrice:   money.New(500000, "RUB"),
+	}
+
+	mock.ExpectBegin()
+	mock.ExpectExec(`SELECT pg_advisory_xact_lock\(hashtext\(\$1\)\)`).
+		WithArgs("room-123").
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectQuery(`SELECT EXISTS \(\s*SELECT 1 FROM room_holds .*expires_at > CURRENT_TIMESTAMP`).
+		WithArgs("room-123", booking.CheckInDate, booking.CheckOutDate).
+		WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(true))
+	mock.ExpectRollback()
+
+	err := repo.CreateBooking(context.Background(), booking)
+	assert.ErrorIs(t, err, domain.ErrRoomNotAvailable)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
 func TestCreateBooking_DatabaseError(t *testing.T) {
 	db, mock := setupMockDB(t)
 	defer db.Close()
@@ -78,18 +194,22 @@ func TestCreateBooking_DatabaseError(t *testing.T) {
 		RoomID:        "room-123",
 		CheckInDate:   time.Now(),
 		CheckOutDate:  time.Now().Add(24 * time.Hour),
-		TotalPrice:    5000.0,
+		TotalPrice:    money.New(500000, "RUB"),
 		Status:        "pending",
 		PaymentStatus: "pending",
 	}
 
+	mock.ExpectBegin()
+	expectRoomFree(mock, "room_holds")
 	mock.ExpectQuery(`INSERT INTO bookings`).
 		WithArgs(
-			booking.ID, booking.UserID, booking.HotelID, booking.RoomID,
-			booking.CheckInDate, booking.CheckOutDate, booking.TotalPrice,
+			booking.ID, booking.UserID, booking.HotelID, booking.RoomID, booking.RoomType, nil, "",
+			booking.Adults, booking.Children, booking.CheckInDate, booking.CheckOutDate, booking.TotalPrice, booking.TotalPrice.Currency,
+			"[]", booking.DisplayPrice, booking.DisplayCurrency, booking.ExchangeRate, nil, nil,
 			booking.Status, booking.PaymentStatus,
 		).
 		WillReturnError(errors.New("database error"))
+	mock.ExpectRollback()
 
 	err := repo.CreateBooking(context.Background(), booking)
 	assert.Error(t, err)
@@ -97,6 +217,107 @@ func TestCreateBooking_DatabaseError(t *testing.T) {
 	assert.NoError(t, mock.ExpectationsWereMet())
 }
 
+func TestCreateBooking_OverlapViolation(t *testing.T) {
+	db, mock := setupMockDB(t)
+	defer db.Close()
+
+	repo := NewPostgresBookingRepository(db)
+	booking := &domain.Booking{
+		ID:            "booking-123",
+		UserID:        "user-123",
+		HotelID:       "hotel-123",
+		RoomID:        "room-123",
+		CheckInDate:   time.Now(),
+		CheckOutDate:  time.Now().Add(24 * time.Hour),
+		TotalPrice:    money.New(500000, "RUB"),
+		Status:        "confirmed",
+		PaymentStatus: "pending",
+	}
+
+	mock.ExpectBegin()
+	expectRoomFree(mock, "room_holds")
+	mock.ExpectQuery(`INSERT INTO bookings`).
+		WillReturnError(&pq.Error{Code: "23P01", Constraint: "bookings_no_overlap"})
+	mock.ExpectRollback()
+
+	err := repo.CreateBooking(context.Background(), booking)
+	assert.ErrorIs(t, err, domain.ErrRoomNotAvailable)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestCreateBooking_RedeemsPromoCode(t *testing.T) {
+	newBooking := func() *domain.Booking {
+		return &domain.Booking{
+			ID:            "booking-123",
+			UserID:        "user-123",
+			HotelID:       "hotel-123",
+			RoomID:        "room-123",
+			CheckInDate:   time.Now(),
+			CheckOutDate:  time.Now().Add(24 * time.Hour),
+			TotalPrice:    money.New(450000, "RUB"),
+			PromoCode:     "SUMMER10",
+			Status:        "pending",
+			PaymentStatus: "pending",
+		}
+	}
+
+	t.Run("within limits", func(t *testing.T) {
+		db, mock := setupMockDB(t)
+		defer db.Close()
+		repo := NewPostgresBookingRepository(db)
+
+		mock.ExpectBegin()
+		mock.ExpectQuery(`SELECT max_uses, max_uses_per_user FROM promotions WHERE code = \$1 FOR UPDATE`).
+			WithArgs("SUMMER10").
+			WillReturnRows(sqlmock.NewRows([]string{"max_uses", "max_uses_per_user"}).AddRow(100, 1))
+		mock.ExpectQuery(`SELECT COUNT\(\*\), COUNT\(\*\) FILTER .* FROM bookings`).
+			WithArgs("SUMMER10", "user-123").
+			WillReturnRows(sqlmock.NewRows([]string{"count", "count"}).AddRow(99, 0))
+		expectRoomFree(mock, "room_holds")
+		mock.ExpectQuery(`INSERT INTO bookings`).
+			WillReturnRows(sqlmock.NewRows([]string{"created_at", "updated_at"}).AddRow(time.Now(), time.Now()))
+		mock.ExpectCommit()
+
+		assert.NoError(t, repo.CreateBooking(context.Background(), newBooking()))
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("limit reached", func(t *testing.T) {
+		db, mock := setupMockDB(t)
+		defer db.Close()
+		repo := NewPostgresBookingRepository(db)
+
+		mock.ExpectBegin()
+		mock.ExpectQuery(`SELECT max_uses, max_uses_per_user FROM promotions`).
+			WithArgs("SUMMER10").
+			WillReturnRows(sqlmock.NewRows([]string{"max_uses", "max_uses_per_user"}).AddRow(0, 1))
+		mock.ExpectQuery(`SELECT COUNT`).
+			WithArgs("SUMMER10", "user-123").
+			WillReturnRows(sqlmock.NewRows([]string{"count", "count"}).AddRow(5, 1))
+		mock.ExpectRollback()
+
+		err := repo.CreateBooking(context.Background(), newBooking())
+		assert.ErrorIs(t, err, domain.ErrPromoCodeExhausted)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("unknown code", func(t *testing.T) {
+		db, mock := setupMockDB(t)
+		defer db.Close()
+		repo := NewPostgresBookingRepository(db)
+
+		mock.ExpectBegin()
+		mock.ExpectQuery(`SELECT max_uses, max_uses_per_user FROM promotions`).
+			WithArgs("SUMMER10").
+			WillReturnError(sql.ErrNoRows)
+		mock.ExpectRollback()
+
+		err := repo.CreateBooking(context.Background(), newBooking())
+		assert.ErrorIs(t, err, domain.ErrPromoCodeInvalid)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
 func TestGetBookingByID_Success(t *testing.T) {
 	db, mock := setupMockDB(t)
 	defer db.Close()
@@ -107,15 +328,18 @@ func TestGetBookingByID_Success(t *testing.T) {
 	updatedAt := time.Now()
 	checkIn := time.Now()
 	checkOut := time.Now().Add(24 * time.Hour)
+	breakdown := []byte(`[{"kind":"accommodation","amount":{"amount":"5000.00","currency":"RUB"}}]`)
 
 	mock.ExpectQuery(`SELECT.*FROM bookings WHERE id`).
 		WithArgs(bookingID).
 		WillReturnRows(sqlmock.NewRows([]string{
-			"id", "user_id", "hotel_id", "room_id", "check_in_date", "check_out_date",
-			"total_price", "status", "payment_status", "created_at", "updated_at",
+			"id", "user_id", "hotel_id", "room_id", "room_type", "reservation_id", "guest_name", "adults", "children", "check_in_date", "check_out_date",
+			"total_price", "currency", "price_breakdown", "display_price", "display_currency", "exchange_rate", "promo_code", "cancellation_policy", "checked_in_at", "checked_in_by", "checked_out_at", "checked_out_by", "no_show_at", "no_show_by", "status", "payment_status", "created_at", "updated_at",
 		}).AddRow(
-			bookingID, "user-123", "hotel-123", "room-123",
-			checkIn, checkOut, 5000.0, "pending", "pending",
+			bookingID, "user-123", "hotel-123", "room-123", "double", "reservation-123", "Анна Смирнова", 2, 1,
+			checkIn, checkOut, "5000.00", "RUB", breakdown, "54.05", "USD", "0.01081081", "SUMMER10",
+			[]byte(`{"id":"policy-123","name":"Невозвратный","rules":[]}`), checkIn, "staff-7", nil, "", nil, "",
+			"checked_in", "pending",
 			createdAt, updatedAt,
 		))
 
@@ -124,7 +348,23 @@ func TestGetBookingByID_Success(t *testing.T) {
 	assert.NotNil(t, booking)
 	assert.Equal(t, bookingID, booking.ID)
 	assert.Equal(t, "user-123", booking.UserID)
-	assert.Equal(t, 5000.0, booking.TotalPrice)
+	assert.Equal(t, money.New(500000, "RUB"), booking.TotalPrice)
+	assert.Equal(t, []domain.PriceItem{
+		{Kind: domain.PriceItemAccommodation, Amount: money.New(500000, "RUB")},
+	}, booking.PriceBreakdown)
+	assert.Equal(t, "USD", booking.DisplayCurrency)
+	assert.Equal(t, &domain.CancellationPolicy{ID: "policy-123", Name: "Невозвратный", Rules: []domain.CancellationRule{}}, booking.CancellationPolicy)
+	assert.Equal(t, "SUMMER10", booking.PromoCode)
+	assert.Equal(t, "reservation-123", booking.ReservationID)
+	assert.Equal(t, "Анна Смирнова", booking.GuestName)
+	assert.Equal(t, 2, booking.Adults)
+	assert.Equal(t, 1, booking.Children)
+	assert.Equal(t, domain.StatusCheckedIn, booking.Status)
+	assert.Equal(t, &checkIn, booking.CheckedInAt)
+	assert.Equal(t, "staff-7", booking.CheckedInBy)
+	assert.Nil(t, booking.CheckedOutAt)
+	assert.Equal(t, money.New(5405, "USD"), booking.DisplayPrice)
+	assert.Equal(t, "1 RUB = 0.01081081 USD", booking.ExchangeRate.String())
 	assert.NoError(t, mock.ExpectationsWereMet())
 }
 
@@ -176,11 +416,11 @@ func TestGetBookingsByUser_Success(t *testing.T) {
 	checkOut := time.Now().Add(24 * time.Hour)
 
 	rows := sqlmock.NewRows([]string{
-		"id", "user_id", "hotel_id", "room_id", "check_in_date", "check_out_date",
-		"total_price", "status", "payment_status", "created_at", "updated_at",
+		"id", "user_id", "hotel_id", "room_id", "room_type", "reservation_id", "guest_name", "adults", "children", "check_in_date", "check_out_date",
+		"total_price", "currency", "price_breakdown", "display_price", "display_currency", "exchange_rate", "promo_code", "cancellation_policy", "checked_in_at", "checked_in_by", "checked_out_at", "checked_out_by", "no_show_at", "no_show_by", "status", "payment_status", "created_at", "updated_at",
 	}).
-		AddRow("booking-1", userID, "hotel-1", "room-1", checkIn, checkOut, 5000.0, "pending", "pending", createdAt, updatedAt).
-		AddRow("booking-2", userID, "hotel-2", "room-2", checkIn, checkOut, 6000.0, "confirmed", "paid", createdAt, updatedAt)
+		AddRow("booking-1", userID, "hotel-1", "room-1", "double", "", "", 1, 0, checkIn, checkOut, "5000.00", "RUB", "[]", "5000.00", "RUB", "1.00000000", "", nil, nil, "", nil, "", nil, "", "pending", "pending", createdAt, updatedAt).
+		AddRow("booking-2", userID, "hotel-2", "room-2", "double", "", "", 1, 0, checkIn, checkOut, "6000.00", "RUB", "[]", "6000.00", "RUB", "1.00000000", "", nil, nil, "", nil, "", nil, "", "confirmed", "paid", createdAt, updatedAt)
 
 	mock.ExpectQuery(`SELECT.*FROM bookings WHERE user_id`).
 		WithArgs(userID).
@@ -204,8 +444,8 @@ func TestGetBookingsByUser_EmptyResult(t *testing.T) {
 	mock.ExpectQuery(`SELECT.*FROM bookings WHERE user_id`).
 		WithArgs(userID).
 		WillReturnRows(sqlmock.NewRows([]string{
-			"id", "user_id", "hotel_id", "room_id", "check_in_date", "check_out_date",
-			"total_price", "status", "payment_status", "created_at", "updated_at",
+			"id", "user_id", "hotel_id", "room_id", "room_type", "reservation_id", "guest_name", "adults", "children", "check_in_date", "check_out_date",
+			"total_price", "currency", "price_breakdown", "display_price", "display_currency", "exchange_rate", "promo_code", "cancellation_policy", "checked_in_at", "checked_in_by", "checked_out_at", "checked_out_by", "no_show_at", "no_show_by", "status", "payment_status", "created_at", "updated_at",
 		}))
 
 	bookings, err := repo.GetBookingsByUser(context.Background(), userID)
@@ -239,9 +479,9 @@ func TestGetBookingsByUser_ScanError(t *testing.T) {
 	userID := "user-123"
 
 	rows := sqlmock.NewRows([]string{
-		"id", "user_id", "hotel_id", "room_id", "check_in_date", "check_out_date",
-		"total_price", "status", "payment_status", "created_at", "updated_at",
-	}).AddRow("invalid", nil, nil, nil, nil, nil, nil, nil, nil, nil, nil)
+		"id", "user_id", "hotel_id", "room_id", "room_type", "reservation_id", "guest_name", "adults", "children", "check_in_date", "check_out_date",
+		"total_price", "currency", "price_breakdown", "display_price", "display_currency", "exchange_rate", "promo_code", "cancellation_policy", "checked_in_at", "checked_in_by", "checked_out_at", "checked_out_by", "no_show_at", "no_show_by", "status", "payment_status", "created_at", "updated_at",
+	}).AddRow("invalid", nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil)
 
 	mock.ExpectQuery(`SELECT.*FROM bookings WHERE user_id`).
 		WithArgs(userID).
@@ -265,10 +505,10 @@ func TestGetBookingsByHotel_Success(t *testing.T) {
 	checkOut := time.Now().Add(24 * time.Hour)
 
 	rows := sqlmock.NewRows([]string{
-		"id", "user_id", "hotel_id", "room_id", "check_in_date", "check_out_date",
-		"total_price", "status", "payment_status", "created_at", "updated_at",
+		"id", "user_id", "hotel_id", "room_id", "room_type", "reservation_id", "guest_name", "adults", "children", "check_in_date", "check_out_date",
+		"total_price", "currency", "price_breakdown", "display_price", "display_currency", "exchange_rate", "promo_code", "cancellation_policy", "checked_in_at", "checked_in_by", "checked_out_at", "checked_out_by", "no_show_at", "no_show_by", "status", "payment_status", "created_at", "updated_at",
 	}).
-		AddRow("booking-1", "user-1", hotelID, "room-1", checkIn, checkOut, 5000.0, "pending", "pending", createdAt, updatedAt)
+		AddRow("booking-1", "user-1", hotelID, "room-1", "double", "", "", 1, 0, checkIn, checkOut, "5000.00", "RUB", "[]", "5000.00", "RUB", "1.00000000", "", nil, nil, "", nil, "", nil, "", "pending", "pending", createdAt, updatedAt)
 
 	mock.ExpectQuery(`SELECT.*FROM bookings WHERE hotel_id`).
 		WithArgs(hotelID).
@@ -310,9 +550,9 @@ func TestGetBookingsByHotel_RowsError(t *testing.T) {
 	checkOut := time.Now().Add(24 * time.Hour)
 
 	rows := sqlmock.NewRows([]string{
-		"id", "user_id", "hotel_id", "room_id", "check_in_date", "check_out_date",
-		"total_price", "status", "payment_status", "created_at", "updated_at",
-	}).AddRow("booking-1", "user-1", hotelID, "room-1", checkIn, checkOut, 5000.0, "pending", "pending", createdAt, updatedAt).
+		"id", "user_id", "hotel_id", "room_id", "room_type", "reservation_id", "guest_name", "adults", "children", "check_in_date", "check_out_date",
+		"total_price", "currency", "price_breakdown", "display_price", "display_currency", "exchange_rate", "promo_code", "cancellation_policy", "checked_in_at", "checked_in_by", "checked_out_at", "checked_out_by", "no_show_at", "no_show_by", "status", "payment_status", "created_at", "updated_at",
+	}).AddRow("booking-1", "user-1", hotelID, "room-1", "double", "", "", 1, 0, checkIn, checkOut, "5000.00", "RUB", "[]", "5000.00", "RUB", "1.00000000", "", nil, nil, "", nil, "", nil, "", "pending", "pending", createdAt, updatedAt).
 		RowError(0, errors.New("row error"))
 
 	mock.ExpectQuery(`SELECT.*FROM bookings WHERE hotel_id`).
@@ -331,31 +571,36 @@ func TestUpdateBookingStatus_Success(t *testing.T) {
 
 	repo := NewPostgresBookingRepository(db)
 	bookingID := "booking-123"
-	status := "confirmed"
 
+	mock.ExpectBegin()
 	mock.ExpectExec(`UPDATE bookings SET status`).
-		WithArgs(bookingID, status).
+		WithArgs(bookingID, "pending", "confirmed").
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectExec(`INSERT INTO booking_status_history`).
+		WithArgs(bookingID, "status", "pending", "confirmed").
 		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectCommit()
 
-	err := repo.UpdateBookingStatus(context.Background(), bookingID, status)
+	err := repo.UpdateBookingStatus(context.Background(), bookingID, domain.StatusPending, domain.StatusConfirmed, nil)
 	assert.NoError(t, err)
 	assert.NoError(t, mock.ExpectationsWereMet())
 }
 
-func TestUpdateBookingStatus_NotFound(t *testing.T) {
+func TestUpdateBookingStatus_StatusChanged(t *testing.T) {
 	db, mock := setupMockDB(t)
 	defer db.Close()
 
 	repo := NewPostgresBookingRepository(db)
-	bookingID := "non-existent"
-	status := "confirmed"
+	bookingID := "booking-123"
 
+	mock.ExpectBegin()
 	mock.ExpectExec(`UPDATE bookings SET status`).
-		WithArgs(bookingID, status).
+		WithArgs(bookingID, "pending", "confirmed").
 		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectRollback()
 
-	err := repo.UpdateBookingStatus(context.Background(), bookingID, status)
-	assert.NoError(t, err)
+	err := repo.UpdateBookingStatus(context.Background(), bookingID, domain.StatusPending, domain.StatusConfirmed, nil)
+	assert.ErrorIs(t, err, domain.ErrStatusChanged)
 	assert.NoError(t, mock.ExpectationsWereMet())
 }
 
@@ -365,31 +610,313 @@ func TestUpdateBookingStatus_DatabaseError(t *testing.T) {
 
 	repo := NewPostgresBookingRepository(db)
 	bookingID := "booking-123"
-	status := "confirmed"
 
+	mock.ExpectBegin()
 	mock.ExpectExec(`UPDATE bookings SET status`).
-		WithArgs(bookingID, status).
+		WithArgs(bookingID, "pending", "confirmed").
 		WillReturnError(errors.New("update error"))
+	mock.ExpectRollback()
 
-	err := repo.UpdateBookingStatus(context.Background(), bookingID, status)
+	err := repo.UpdateBookingStatus(context.Background(), bookingID, domain.StatusPending, domain.StatusConfirmed, nil)
 	assert.Error(t, err)
 	assert.Contains(t, err.Error(), "update error")
 	assert.NoError(t, mock.ExpectationsWereMet())
 }
 
+func TestUpdateBookingStatus_HistoryError(t *testing.T) {
+	db, mock := setupMockDB(t)
+	defer db.Close()
+
+	repo := NewPostgresBookingRepository(db)
+	bookingID := "booking-123"
+
+	mock.ExpectBegin()
+	mock.ExpectExec(`UPDATE bookings SET status`).
+		WithArgs(bookingID, "confirmed", "cancelled").
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectExec(`INSERT INTO booking_status_history`).
+		WillReturnError(errors.New("insert error"))
+	mock.ExpectRollback()
+
+	err := repo.UpdateBookingStatus(context.Background(), bookingID, domain.StatusConfirmed, domain.StatusCancelled, nil)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "insert error")
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestUpdateBookingStatus_WithEvent(t *testing.T) {
+	db, mock := setupMockDB(t)
+	defer db.Close()
+
+	repo := NewPostgresBookingRepository(db)
+	bookingID := "booking-123"
+	event := &domain.OutboxEvent{Topic: domain.EventBookingCancelled, Key: bookingID, Payload: []byte(`{}`)}
+
+	mock.ExpectBegin()
+	mock.ExpectExec(`UPDATE bookings SET status`).
+		WithArgs(bookingID, "confirmed", "cancelled").
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectExec(`INSERT INTO booking_status_history`).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectQuery(`INSERT INTO booking_outbox`).
+		WithArgs(domain.EventBookingCancelled, bookingID, `{}`).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "created_at"}).AddRow(int64(1), time.Now()))
+	mock.ExpectCommit()
+
+	err := repo.UpdateBookingStatus(context.Background(), bookingID, domain.StatusConfirmed, domain.StatusCancelled, event)
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestUpdateStayStatus(t *testing.T) {
+	checkedInAt := time.Date(2030, 12, 20, 15, 0, 0, 0, time.UTC)
+	booking := &domain.Booking{
+		ID:          "booking-123",
+		Status:      domain.StatusCheckedIn,
+		CheckedInAt: &checkedInAt,
+		CheckedInBy: "staff-7",
+	}
+
+	t.Run("success", func(t *testing.T) {
+		db, mock := setupMockDB(t)
+		defer db.Close()
+
+		repo := NewPostgresBookingRepository(db)
+
+		mock.ExpectBegin()
+		mock.ExpectExec(`UPDATE bookings SET status = \$3, checked_in_at = \$4`).
+			WithArgs("booking-123", "confirmed", "checked_in", checkedInAt, "staff-7", nil, nil, nil, nil).
+			WillReturnResult(sqlmock.NewResult(1, 1))
+		mock.ExpectExec(`INSERT INTO booking_status_history`).
+			WithArgs("booking-123", "status", "confirmed", "checked_in").
+			WillReturnResult(sqlmock.NewResult(1, 1))
+		mock.ExpectQuery(`INSERT INTO booking_outbox`).
+			WithArgs(domain.EventBookingCheckedIn, "booking-123", `{}`).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "created_at"}).AddRow(int64(1), time.Now()))
+		mock.ExpectCommit()
+
+		event := &domain.OutboxEvent{Topic: domain.EventBookingCheckedIn, Key: "booking-123", Payload: []byte(`{}`)}
+		err := repo.UpdateStayStatus(context.Background(), booking, domain.StatusConfirmed, event)
+		assert.NoError(t, err)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("status changed", func(t *testing.T) {
+		db, mock := setupMockDB(t)
+		defer db.Close()
+
+		repo := NewPostgresBookingRepository(db)
+
+		mock.ExpectBegin()
+		mock.ExpectExec(`UPDATE bookings SET status`).
+			WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectRollback()
+
+		err := repo.UpdateStayStatus(context.Background(), booking, domain.StatusConfirmed, nil)
+		assert.ErrorIs(t, err, domain.ErrStatusChanged)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestGetUnattendedBookings(t *testing.T) {
+	db, mock := setupMockDB(t)
+	defer db.Close()
+
+	repo := NewPostgresBookingRepository(db)
+	checkInBefore := time.Date(2030, 12, 20, 0, 0, 0, 0, time.UTC)
+	checkIn := checkInBefore.AddDate(0, 0, -1)
+
+	mock.ExpectQuery(`SELECT.*FROM bookings\s+WHERE status = 'confirmed' AND check_in_date <= \$1`).
+		WithArgs(checkInBefore).
+		WillReturnRows(sqlmock.NewRows([]string{
+			"id", "user_id", "hotel_id", "room_id", "room_type", "reservation_id", "guest_name", "adults", "children", "check_in_date", "check_out_date",
+			"total_price", "currency", "price_breakdown", "display_price", "display_currency", "exchange_rate", "promo_code", "cancellation_policy", "checked_in_at", "checked_in_by", "checked_out_at", "checked_out_by", "no_show_at", "no_show_by", "status", "payment_status", "created_at", "updated_at",
+		}).AddRow("booking-1", "user-1", "hotel-1", "room-1", "double", "", "", 1, 0, checkIn, checkInBefore.AddDate(0, 0, 2), "5000.00", "RUB", "[]", "5000.00", "RUB", "1.00000000", "", nil, nil, "", nil, "", nil, "", "confirmed", "paid", checkIn, checkIn))
+
+	bookings, err := repo.GetUnattendedBookings(context.Background(), checkInBefore)
+	assert.NoError(t, err)
+	assert.Len(t, bookings, 1)
+	assert.Equal(t, "booking-1", bookings[0].ID)
+	assert.Nil(t, bookings[0].CheckedInAt)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetUnpaidBookings(t *testing.T) {
+	db, mock := setupMockDB(t)
+	defer db.Close()
+
+	repo := NewPostgresBookingRepository(db)
+	updatedBefore := time.Date(2030, 12, 20, 12, 0, 0, 0, time.UTC)
+	checkIn := time.Date(2030, 12, 24, 0, 0, 0, 0, time.UTC)
+	updatedAt := updatedBefore.Add(-time.Hour)
+
+	mock.ExpectQuery(`SELECT.*FROM bookings\s+WHERE status = 'awaiting_payment' AND updated_at <= \$1 ORDER BY updated_at, id LIMIT \$2`).
+		WithArgs(updatedBefore, 50).
+		WillReturnRows(sqlmock.NewRows([]string{
+			"id", "user_id", "hotel_id", "room_id", "room_type", "reservation_id", "guest_name", "adults", "children", "check_in_date", "check_out_date",
+			"total_price", "currency", "price_breakdown", "display_price", "display_currency", "exchange_rate", "promo_code", "cancellation_policy", "checked_in_at", "checked_in_by", "checked_out_at", "checked_out_by", "no_show_at", "no_show_by", "status", "payment_status", "created_at", "updated_at",
+		}).AddRow("booking-1", "user-1", "hotel-1", "room-1", "double", "", "", 1, 0, checkIn, checkIn.AddDate(0, 0, 2), "5000.00", "RUB", "[]", "5000.00", "RUB", "1.00000000", "", nil, nil, "", nil, "", nil, "", "awaiting_payment", "pending", updatedAt, updatedAt))
+
+	bookings, err := repo.GetUnpaidBookings(context.Background(), updatedBefore, 50)
+	assert.NoError(t, err)
+	assert.Len(t, bookings, 1)
+	assert.Equal(t, "booking-1", bookings[0].ID)
+	assert.Equal(t, domain.StatusAwaitingPayment, bookings[0].Status)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestModifyBooking(t *testing.T) {
+	checkIn := time.Date(2030, 12, 20, 0, 0, 0, 0, time.UTC)
+	newBooking := func() *domain.Booking {
+		return &domain.Booking{
+			ID:           "booking-123",
+			RoomID:       "room-456",
+			CheckInDate:  checkIn,
+			CheckOutDate: checkIn.AddDate(0, 0, 3),
+			TotalPrice:   money.New(750000, "RUB"),
+			PriceBreakdown: []domain.PriceItem{
+				{Kind: domain.PriceItemAccommodation, Amount: money.New(750000, "RUB")},
+			},
+			DisplayPrice: money.New(750000, "RUB"),
+			Status:       domain.StatusConfirmed,
+		}
+	}
+	breakdown := `[{"kind":"accommodation","amount":{"amount":"7500.00","currency":"RUB"}}]`
+
+	t.Run("success", func(t *testing.T) {
+		db, mock := setupMockDB(t)
+		defer db.Close()
+
+		repo := NewPostgresBookingRepository(db)
+		booking := newBooking()
+		event := &domain.OutboxEvent{Topic: domain.EventBookingModified, Key: booking.ID, Payload: []byte(`{}`)}
+		updatedAt := time.Now()
+
+		mock.ExpectBegin()
+		expectRoomFree(mock, "room_holds")
+		mock.ExpectQuery(`UPDATE bookings SET room_id = \$3.*WHERE id = \$1 AND status = \$2`).
+			WithArgs("booking-123", domain.StatusConfirmed, "room-456", "", checkIn, checkIn.AddDate(0, 0, 3),
+				booking.TotalPrice, breakdown, booking.DisplayPrice, nil).
+			WillReturnRows(sqlmock.NewRows([]string{"updated_at"}).AddRow(updatedAt))
+		mock.ExpectQuery(`INSERT INTO booking_outbox`).
+			WithArgs(domain.EventBookingModified, "booking-123", `{}`).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "created_at"}).AddRow(int64(1), time.Now()))
+		mock.ExpectCommit()
+
+		err := repo.ModifyBooking(context.Background(), booking, nil, event)
+		assert.NoError(t, err)
+		assert.Equal(t, updatedAt, booking.UpdatedAt)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("records a pending modification", func(t *testing.T) {
+		db, mock := setupMockDB(t)
+		defer db.Close()
+
+		repo := NewPostgresBookingRepository(db)
+		charge := money.New(250000, "RUB")
+		modification := &domain.BookingModification{
+			ID:               "modification-1",
+			BookingID:        "booking-123",
+			Previous:         domain.Booking{ID: "booking-123", DisplayPrice: money.New(500000, "RUB")},
+			AdditionalCharge: &charge,
+			Status:           domain.ModificationPending,
+		}
+		createdAt := time.Now()
+
+		mock.ExpectBegin()
+		expectRoomFree(mock, "room_holds")
+		mock.ExpectQuery(`UPDATE bookings SET room_id`).
+			WillReturnRows(sqlmock.NewRows([]string{"updated_at"}).AddRow(time.Now()))
+		mock.ExpectQuery(`INSERT INTO booking_modifications`).
+			WithArgs("modification-1", "booking-123", sqlmock.AnyArg(), charge, nil, "RUB", domain.ModificationPending).
+			WillReturnRows(sqlmock.NewRows([]string{"created_at", "updated_at"}).AddRow(createdAt, createdAt))
+		mock.ExpectCommit()
+
+		err := repo.ModifyBooking(context.Background(), newBooking(), modification, nil)
+		assert.NoError(t, err)
+		assert.Equal(t, createdAt, modification.CreatedAt)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("modification already pending", func(t *testing.T) {
+		db, mock := setupMockDB(t)
+		defer db.Close()
+
+		repo := NewPostgresBookingRepository(db)
+		refund := money.New(100000, "RUB")
+		modification := &domain.BookingModification{
+			ID:           "modification-2",
+			BookingID:    "booking-123",
+			RefundAmount: &refund,
+			Status:       domain.ModificationPending,
+		}
+
+		mock.ExpectBegin()
+		expectRoomFree(mock, "room_holds")
+		mock.ExpectQuery(`UPDATE bookings SET room_id`).
+			WillReturnRows(sqlmock.NewRows([]string{"updated_at"}).AddRow(time.Now()))
+		mock.ExpectQuery(`INSERT INTO booking_modifications`).
+			WillReturnError(&pq.Error{Code: "23505", Constraint: "idx_booking_modifications_pending"})
+		mock.ExpectRollback()
+
+		err := repo.ModifyBooking(context.Background(), newBooking(), modification, nil)
+		assert.ErrorIs(t, err, domain.ErrBookingNotModifiable)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("status changed", func(t *testing.T) {
+		db, mock := setupMockDB(t)
+		defer db.Close()
+
+		repo := NewPostgresBookingRepository(db)
+
+		mock.ExpectBegin()
+		expectRoomFree(mock, "room_holds")
+		mock.ExpectQuery(`UPDATE bookings SET room_id`).
+			WillReturnRows(sqlmock.NewRows([]string{"updated_at"}))
+		mock.ExpectRollback()
+
+		err := repo.ModifyBooking(context.Background(), newBooking(), nil, nil)
+		assert.ErrorIs(t, err, domain.ErrStatusChanged)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("overlap violation", func(t *testing.T) {
+		db, mock := setupMockDB(t)
+		defer db.Close()
+
+		repo := NewPostgresBookingRepository(db)
+
+		mock.ExpectBegin()
+		expectRoomFree(mock, "room_holds")
+		mock.ExpectQuery(`UPDATE bookings SET room_id`).
+			WillReturnError(&pq.Error{Code: "23P01", Constraint: "bookings_no_overlap"})
+		mock.ExpectRollback()
+
+		err := repo.ModifyBooking(context.Background(), newBooking(), nil, nil)
+		assert.ErrorIs(t, err, domain.ErrRoomNotAvailable)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
 func TestUpdatePaymentStatus_Success(t *testing.T) {
 	db, mock := setupMockDB(t)
 	defer db.Close()
 
 	repo := NewPostgresBookingRepository(db)
 	bookingID := "booking-123"
-	paymentStatus := "paid"
 
+	mock.ExpectBegin()
 	mock.ExpectExec(`UPDATE bookings SET payment_status`).
-		WithArgs(bookingID, paymentStatus).
+		WithArgs(bookingID, "pending", "paid").
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectExec(`INSERT INTO booking_status_history`).
+		WithArgs(bookingID, "payment_status", "pending", "paid").
 		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectCommit()
 
-	err := repo.UpdatePaymentStatus(context.Background(), bookingID, paymentStatus)
+	err := repo.UpdatePaymentStatus(context.Background(), bookingID, domain.PaymentPending, domain.PaymentPaid)
 	assert.NoError(t, err)
 	assert.NoError(t, mock.ExpectationsWereMet())
 }
@@ -400,34 +927,277 @@ func TestUpdatePaymentStatus_DatabaseError(t *testing.T) {
 
 	repo := NewPostgresBookingRepository(db)
 	bookingID := "booking-123"
-	paymentStatus := "paid"
 
+	mock.ExpectBegin()
 	mock.ExpectExec(`UPDATE bookings SET payment_status`).
-		WithArgs(bookingID, paymentStatus).
+		WithArgs(bookingID, "pending", "paid").
 		WillReturnError(errors.New("update error"))
+	mock.ExpectRollback()
 
-	err := repo.UpdatePaymentStatus(context.Background(), bookingID, paymentStatus)
+	err := repo.UpdatePaymentStatus(context.Background(), bookingID, domain.PaymentPending, domain.PaymentPaid)
 	assert.Error(t, err)
 	assert.Contains(t, err.Error(), "update error")
 	assert.NoError(t, mock.ExpectationsWereMet())
 }
 
-func TestUpdatePaymentStatus_AllStatuses(t *testing.T) {
+func TestGetStatusHistory_Success(t *testing.T) {
 	db, mock := setupMockDB(t)
 	defer db.Close()
 
 	repo := NewPostgresBookingRepository(db)
 	bookingID := "booking-123"
-	statuses := []string{"pending", "paid", "failed", "refunded"}
+	now := time.Now()
 
-	for _, status := range statuses {
-		mock.ExpectExec(`UPDATE bookings SET payment_status`).
-			WithArgs(bookingID, status).
-			WillReturnResult(sqlmock.NewResult(1, 1))
+	rows := sqlmock.NewRows([]string{"id", "booking_id", "field", "from_status", "to_status", "created_at"}).
+		AddRow("1", bookingID, "status", "pending", "awaiting_payment", now).
+		AddRow("2", bookingID, "payment_status", "pending", "paid", now)
+
+	mock.ExpectQuery(`SELECT id, booking_id, field, from_status, to_status, created_at FROM booking_status_history`).
+		WithArgs(bookingID).
+		WillReturnRows(rows)
+
+	history, err := repo.GetStatusHistory(context.Background(), bookingID)
+	assert.NoError(t, err)
+	assert.Len(t, history, 2)
+	assert.Equal(t, "awaiting_payment", history[0].ToStatus)
+	assert.Equal(t, "payment_status", history[1].Field)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetStatusHistory_Empty(t *testing.T) {
+	db, mock := setupMockDB(t)
+	defer db.Close()
+
+	repo := NewPostgresBookingRepository(db)
+
+	mock.ExpectQuery(`SELECT id, booking_id, field, from_status, to_status, created_at FROM booking_status_history`).
+		WithArgs("booking-123").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "booking_id", "field", "from_status", "to_status", "created_at"}))
 
-		err := repo.UpdatePaymentStatus(context.Background(), bookingID, status)
+	history, err := repo.GetStatusHistory(context.Background(), "booking-123")
+	assert.NoError(t, err)
+	assert.NotNil(t, history)
+	assert.Empty(t, history)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestHasOverlappingBooking(t *testing.T) {
+	checkIn := time.Now()
+	checkOut := checkIn.Add(48 * time.Hour)
+
+	t.Run("overlap found", func(t *testing.T) {
+		db, mock := setupMockDB(t)
+		defer db.Close()
+
+		repo := NewPostgresBookingRepository(db)
+
+		mock.ExpectQuery(`SELECT EXISTS .*FROM bookings.*daterange.*FROM room_holds.*expires_at > CURRENT_TIMESTAMP`).
+			WithArgs("room-123", checkIn, checkOut, "").
+			WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(true))
+
+		overlapping, err := repo.HasOverlappingBooking(context.Background(), "room-123", checkIn, checkOut, "")
 		assert.NoError(t, err)
+		assert.True(t, overlapping)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("no overlap", func(t *testing.T) {
+		db, mock := setupMockDB(t)
+		defer db.Close()
+
+		repo := NewPostgresBookingRepository(db)
+
+		mock.ExpectQuery(`SELECT EXISTS .*FROM bookings.*daterange`).
+			WithArgs("room-123", checkIn, checkOut, "").
+			WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(false))
+
+		overlapping, err := repo.HasOverlappingBooking(context.Background(), "room-123", checkIn, checkOut, "")
+		assert.NoError(t, err)
+		assert.False(t, overlapping)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("database error", func(t *testing.T) {
+		db, mock := setupMockDB(t)
+		defer db.Close()
+
+		repo := NewPostgresBookingRepository(db)
+
+		mock.ExpectQuery(`SELECT EXISTS .*FROM bookings.*daterange`).
+			WithArgs("room-123", checkIn, checkOut, "").
+			WillReturnError(errors.New("query error"))
+
+		_, err := repo.HasOverlappingBooking(context.Background(), "room-123", checkIn, checkOut, "")
+		assert.Error(t, err)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestGetBookedRoomIDs(t *testing.T) {
+	checkIn := time.Now()
+	checkOut := checkIn.Add(48 * time.Hour)
+
+	t.Run("success", func(t *testing.T) {
+		db, mock := setupMockDB(t)
+		defer db.Close()
+
+		repo := NewPostgresBookingRepository(db)
+
+		mock.ExpectQuery(`SELECT room_id FROM bookings.*UNION.*FROM room_holds`).
+			WithArgs("hotel-123", checkIn, checkOut).
+			WillReturnRows(sqlmock.NewRows([]string{"room_id"}).AddRow("room-1").AddRow("room-2"))
+
+		roomIDs, err := repo.GetBookedRoomIDs(context.Background(), "hotel-123", checkIn, checkOut)
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"room-1", "room-2"}, roomIDs)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("empty result", func(t *testing.T) {
+		db, mock := setupMockDB(t)
+		defer db.Close()
+
+		repo := NewPostgresBookingRepository(db)
+
+		mock.ExpectQuery(`SELECT room_id FROM bookings.*UNION.*FROM room_holds`).
+			WithArgs("hotel-123", checkIn, checkOut).
+			WillReturnRows(sqlmock.NewRows([]string{"room_id"}))
+
+		roomIDs, err := repo.GetBookedRoomIDs(context.Background(), "hotel-123", checkIn, checkOut)
+		assert.NoError(t, err)
+		assert.NotNil(t, roomIDs)
+		assert.Empty(t, roomIDs)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("database error", func(t *testing.T) {
+		db, mock := setupMockDB(t)
+		defer db.Close()
+
+		repo := NewPostgresBookingRepository(db)
+
+		mock.ExpectQuery(`SELECT room_id FROM bookings.*UNION.*FROM room_holds`).
+			WithArgs("hotel-123", checkIn, checkOut).
+			WillReturnError(errors.New("query error"))
+
+		roomIDs, err := repo.GetBookedRoomIDs(context.Background(), "hotel-123", checkIn, checkOut)
+		assert.Error(t, err)
+		assert.Nil(t, roomIDs)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestCreateReservation(t *testing.T) {
+	checkIn := time.Date(2030, 12, 20, 0, 0, 0, 0, time.UTC)
+	newReservation := func() *domain.Reservation {
+		reservation := &domain.Reservation{
+			ID:              "reservation-123",
+			UserID:          "user-123",
+			HotelID:         "hotel-123",
+			DisplayCurrency: "RUB",
+			TotalPrice:      money.New(1000000, "RUB"),
+			DisplayPrice:    money.New(1000000, "RUB"),
+		}
+		for _, roomID := range []string{"room-1", "room-2"} {
+			reservation.Bookings = append(reservation.Bookings, domain.Booking{
+				ID:              "booking-" + roomID,
+				UserID:          "user-123",
+				HotelID:         "hotel-123",
+				RoomID:          roomID,
+				ReservationID:   "reservation-123",
+				GuestName:       "Гость " + roomID,
+				Adults:          1,
+				CheckInDate:     checkIn,
+				CheckOutDate:    checkIn.AddDate(0, 0, 1),
+				TotalPrice:      money.New(500000, "RUB"),
+				DisplayCurrency: "RUB",
+				DisplayPrice:    money.New(500000, "RUB"),
+				Status:          domain.StatusPending,
+				PaymentStatus:   domain.PaymentPending,
+			})
+		}
+		return reservation
 	}
 
+	t.Run("success", func(t *testing.T) {
+		db, mock := setupMockDB(t)
+		defer db.Close()
+
+		repo := NewPostgresBookingRepository(db)
+		reservation := newReservation()
+		createdAt := time.Now()
+
+		mock.ExpectBegin()
+		mock.ExpectQuery(`INSERT INTO reservations`).
+			WithArgs("reservation-123", "user-123", "hotel-123", reservation.TotalPrice, "RUB", reservation.DisplayPrice, "RUB").
+			WillReturnRows(sqlmock.NewRows([]string{"created_at"}).AddRow(createdAt))
+		for _, roomID := range []string{"room-1", "room-2"} {
+			expectRoomFree(mock, "room_holds")
+			mock.ExpectQuery(`INSERT INTO bookings`).
+				WithArgs("booking-"+roomID, "user-123", "hotel-123", roomID, "", "reservation-123", "Гость "+roomID, 1, 0,
+					sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(),
+					sqlmock.AnyArg(), sqlmock.AnyArg(), nil, nil, domain.StatusPending, domain.PaymentPending).
+				WillReturnRows(sqlmock.NewRows([]string{"created_at", "updated_at"}).AddRow(createdAt, createdAt))
+		}
+		mock.ExpectCommit()
+
+		err := repo.CreateReservation(context.Background(), reservation)
+		assert.NoError(t, err)
+		assert.Equal(t, createdAt, reservation.CreatedAt)
+		assert.Equal(t, createdAt, reservation.Bookings[1].CreatedAt)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("one room taken", func(t *testing.T) {
+		db, mock := setupMockDB(t)
+		defer db.Close()
+
+		repo := NewPostgresBookingRepository(db)
+
+		mock.ExpectBegin()
+		mock.ExpectQuery(`INSERT INTO reservations`).
+			WillReturnRows(sqlmock.NewRows([]string{"created_at"}).AddRow(time.Now()))
+		expectRoomFree(mock, "room_holds")
+		mock.ExpectQuery(`INSERT INTO bookings`).
+			WillReturnRows(sqlmock.NewRows([]string{"created_at", "updated_at"}).AddRow(time.Now(), time.Now()))
+		expectRoomFree(mock, "room_holds")
+		mock.ExpectQuery(`INSERT INTO bookings`).
+			WillReturnError(&pq.Error{Code: "23P01", Constraint: "bookings_no_overlap"})
+		mock.ExpectRollback()
+
+		err := repo.CreateReservation(context.Background(), newReservation())
+		assert.ErrorIs(t, err, domain.ErrRoomNotAvailable)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestGetReservationByID(t *testing.T) {
+	db, mock := setupMockDB(t)
+	defer db.Close()
+
+	repo := NewPostgresBookingRepository(db)
+	createdAt := time.Now()
+	checkIn := time.Now()
+	checkOut := checkIn.Add(24 * time.Hour)
+
+	mock.ExpectQuery(`SELECT .* FROM reservations WHERE id = \$1`).
+		WithArgs("reservation-123").
+		WillReturnRows(sqlmock.NewRows([]string{
+			"id", "user_id", "hotel_id", "total_price", "currency", "display_price", "display_currency", "created_at",
+		}).AddRow("reservation-123", "user-123", "hotel-123", "10000.00", "RUB", "10000.00", "RUB", createdAt))
+	mock.ExpectQuery(`SELECT.*FROM bookings WHERE reservation_id = \$1`).
+		WithArgs("reservation-123").
+		WillReturnRows(sqlmock.NewRows([]string{
+			"id", "user_id", "hotel_id", "room_id", "room_type", "reservation_id", "guest_name", "adults", "children", "check_in_date", "check_out_date",
+			"total_price", "currency", "price_breakdown", "display_price", "display_currency", "exchange_rate", "promo_code", "cancellation_policy", "checked_in_at", "checked_in_by", "checked_out_at", "checked_out_by", "no_show_at", "no_show_by", "status", "payment_status", "created_at", "updated_at",
+		}).
+			AddRow("booking-1", "user-123", "hotel-123", "room-1", "double", "reservation-123", "Анна", 1, 0, checkIn, checkOut, "5000.00", "RUB", "[]", "5000.00", "RUB", "1.00000000", "", nil, nil, "", nil, "", nil, "", "pending", "pending", createdAt, createdAt).
+			AddRow("booking-2", "user-123", "hotel-123", "room-2", "double", "reservation-123", "Борис", 1, 0, checkIn, checkOut, "5000.00", "RUB", "[]", "5000.00", "RUB", "1.00000000", "", nil, nil, "", nil, "", nil, "", "pending", "pending", createdAt, createdAt))
+
+	reservation, err := repo.GetReservationByID(context.Background(), "reservation-123")
+	assert.NoError(t, err)
+	assert.Equal(t, money.New(1000000, "RUB"), reservation.TotalPrice)
+	assert.Len(t, reservation.Bookings, 2)
+	assert.Equal(t, "Борис", reservation.Bookings[1].GuestName)
 	assert.NoError(t, mock.ExpectationsWereMet())
 }
@@ -0,0 +1,168 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"hotel-booking-system/internal/booking/domain"
+
+	"github.com/lib/pq"
+)
+
+type PostgresHoldRepository struct {
+	db *sql.DB
+}
+
+func NewPostgresHoldRepository(db *sql.DB) *PostgresHoldRepository {
+	return &PostgresHoldRepository{db: db}
+}
+
+func (r *PostgresHoldRepository) CreateHold(ctx context.Context, hold *domain.RoomHold, ttl time.Duration) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err := lockRoomAgainstBookings(ctx, tx, hold.RoomID, hold.CheckInDate, hold.CheckOutDate); err != nil {
+		return err
+	}
+
+	query := `INSERT INTO room_holds (id, user_id, hotel_id, room_id, check_in_date, check_out_date, status, expires_at) 
+			  VALUES ($1, $2, $3, $4, $5, $6, $7, CURRENT_TIMESTAMP + make_interval(secs => $8)) 
+			  RETURNING expires_at, created_at`
+	err = tx.QueryRowContext(ctx, query,
+		hold.ID, hold.UserID, hold.HotelID, hold.RoomID,
+		hold.CheckInDate, hold.CheckOutDate, hold.Status, ttl.Seconds(),
+	).Scan(&hold.ExpiresAt, &hold.CreatedAt)
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) && pqErr.Code == exclusionViolation {
+		return domain.ErrRoomNotAvailable
+	}
+	if err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+func (r *PostgresHoldRepository) GetHoldByID(ctx context.Context, id string) (*domain.RoomHold, error) {
+	hold := &domain.RoomHold{}
+	query := `SELECT id, user_id, hotel_id, room_id, check_in_date, check_out_date, 
+			  status, COALESCE(booking_id::text, ''), expires_at, created_at 
+			  FROM room_holds WHERE id = $1`
+	err := r.db.QueryRowContext(ctx, query, id).Scan(
+		&hold.ID, &hold.UserID, &hold.HotelID, &hold.RoomID,
+		&hold.CheckInDate, &hold.CheckOutDate, &hold.Status,
+		&hold.BookingID, &hold.ExpiresAt, &hold.CreatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return hold, nil
+}
+
+func (r *PostgresHoldRepository) GetExpiredHolds(ctx context.Context, limit int) ([]domain.RoomHold, error) {
+	query := `SELECT id, user_id, hotel_id, room_id, check_in_date, check_out_date, 
+			  status, COALESCE(booking_id::text, ''), expires_at, created_at 
+			  FROM room_holds WHERE status = $1 AND expires_at <= CURRENT_TIMESTAMP 
+			  ORDER BY expires_at LIMIT $2`
+	return r.queryHolds(ctx, query, domain.HoldActive, limit)
+}
+
+// GetExpiredRoomHolds returns the holds of the room for any of the dates that
+// have expired but are still active.
+func (r *PostgresHoldRepository) GetExpiredRoomHolds(ctx context.Context, roomID string, checkIn, checkOut time.Time) ([]domain.RoomHold, error) {
+	query := `SELECT id, user_id, hotel_id, room_id, check_in_date, check_out_date, 
+			  status, COALESCE(booking_id::text, ''), expires_at, created_at 
+			  FROM room_holds WHERE room_id = $1 AND status = $2 AND expires_at <= CURRENT_TIMESTAMP 
+			  AND daterange(check_in_date, check_out_date) && daterange($3::date, $4::date)`
+	return r.queryHolds(ctx, query, roomID, domain.HoldActive, checkIn, checkOut)
+}
+
+func (r *PostgresHoldRepository) queryHolds(ctx context.Context, query string, args ...interface{}) ([]domain.RoomHold, error) {
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var holds []domain.RoomHold
+	for rows.Next() {
+		var hold domain.RoomHold
+		if err := rows.Scan(
+			&hold.ID, &hold.UserID, &hold.HotelID, &hold.RoomID,
+			&hold.CheckInDate, &hold.CheckOutDate, &hold.Status,
+			&hold.BookingID, &hold.ExpiresAt, &hold.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		holds = append(holds, hold)
+	}
+	return holds, rows.Err()
+}
+
+func (r *PostgresHoldRepository) ExpireHold(ctx context.Context, id string, event *domain.OutboxEvent) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	query := `UPDATE room_holds SET status = $2 WHERE id = $1 AND status = $3`
+	result, err := tx.ExecContext(ctx, query, id, domain.HoldExpired, domain.HoldActive)
+	if err != nil {
+		return err
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return domain.ErrHoldNotActive
+	}
+
+	if err := insertOutboxEvent(ctx, tx, event); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// Bookings and holds are kept in separate tables, and the exclusion constraint
+// of each sees only its own table. Every insert into either table therefore
+// locks the room for the rest of its transaction and checks the other table
+// under the lock, so a room cannot be booked and held at once.
+
+// lockRoomAgainstHolds locks the room and fails if an unexpired hold covers
+// any of the dates.
+func lockRoomAgainstHolds(ctx context.Context, tx *sql.Tx, roomID string, checkIn, checkOut time.Time) error {
+	return lockRoomAgainst(ctx, tx, roomID, checkIn, checkOut, `SELECT EXISTS (
+			  SELECT 1 FROM room_holds 
+			  WHERE room_id = $1 AND status = 'active' AND expires_at > CURRENT_TIMESTAMP 
+			  AND daterange(check_in_date, check_out_date) && daterange($2::date, $3::date))`)
+}
+
+// lockRoomAgainstBookings locks the room and fails if a booking that is not
+// cancelled or expired covers any of the dates.
+func lockRoomAgainstBookings(ctx context.Context, tx *sql.Tx, roomID string, checkIn, checkOut time.Time) error {
+	return lockRoomAgainst(ctx, tx, roomID, checkIn, checkOut, `SELECT EXISTS (
+			  SELECT 1 FROM bookings 
+			  WHERE room_id = $1 AND status NOT IN ('cancelled', 'expired') 
+			  AND daterange(check_in_date, check_out_date) && daterange($2::date, $3::date))`)
+}
+
+func lockRoomAgainst(ctx context.Context, tx *sql.Tx, roomID string, checkIn, checkOut time.Time, query string) error {
+	if _, err := tx.ExecContext(ctx, `SELECT pg_advisory_xact_lock(hashtext($1))`, roomID); err != nil {
+		return err
+	}
+	var taken bool
+	if err := tx.QueryRowContext(ctx, query, roomID, checkIn, checkOut).Scan(&taken); err != nil {
+		return err
+	}
+	if taken {
+		return domain.ErrRoomNotAvailable
+	}
+	return nil
+}
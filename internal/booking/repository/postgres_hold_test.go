@@ -0,0 +1,215 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"hotel-booking-system/internal/booking/domain"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+)
+
+var holdColumns = []string{
+	"id", "user_id", "hotel_id", "room_id", "check_in_date", "check_out_date",
+	"status", "booking_id", "expires_at", "created_at",
+}
+
+func newTestHold() *domain.RoomHold {
+	checkIn := time.Date(2024, 12, 20, 0, 0, 0, 0, time.UTC)
+	return &domain.RoomHold{
+		ID:           "hold-123",
+		UserID:       "user-123",
+		HotelID:      "hotel-123",
+		RoomID:       "room-123",
+		CheckInDate:  checkIn,
+		CheckOutDate: checkIn.Add(48 * time.Hour),
+		Status:       domain.HoldActive,
+	}
+}
+
+func TestCreateHold(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		db, mock := setupMockDB(t)
+		defer db.Close()
+
+		repo := NewPostgresHoldRepository(db)
+		hold := newTestHold()
+		now := time.Now()
+
+		mock.ExpectBegin()
+		expectRoomFree(mock, "bookings")
+		mock.ExpectQuery(`INSERT INTO room_holds .*make_interval\(secs => \$8\)`).
+			WithArgs(hold.ID, hold.UserID, hold.HotelID, hold.RoomID, hold.CheckInDate, hold.CheckOutDate, domain.HoldActive, float64(900)).
+			WillReturnRows(sqlmock.NewRows([]string{"expires_at", "created_at"}).AddRow(now.Add(15*time.Minute), now))
+		mock.ExpectCommit()
+
+		err := repo.CreateHold(context.Background(), hold, 15*time.Minute)
+		assert.NoError(t, err)
+		assert.Equal(t, now.Add(15*time.Minute), hold.ExpiresAt)
+		assert.Equal(t, now, hold.CreatedAt)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("overlapping hold", func(t *testing.T) {
+		db, mock := setupMockDB(t)
+		defer db.Close()
+
+		repo := NewPostgresHoldRepository(db)
+
+		mock.ExpectBegin()
+		expectRoomFree(mock, "bookings")
+		mock.ExpectQuery(`INSERT INTO room_holds`).
+			WillReturnError(&pq.Error{Code: exclusionViolation})
+		mock.ExpectRollback()
+
+		err := repo.CreateHold(context.Background(), newTestHold(), 15*time.Minute)
+		assert.ErrorIs(t, err, domain.ErrRoomNotAvailable)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("room booked", func(t *testing.T) {
+		db, mock := setupMockDB(t)
+		defer db.Close()
+
+		repo := NewPostgresHoldRepository(db)
+		hold := newTestHold()
+
+		mock.ExpectBegin()
+		mock.ExpectExec(`SELECT pg_advisory_xact_lock\(hashtext\(\$1\)\)`).
+			WithArgs("room-123").
+			WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectQuery(`SELECT EXISTS \(\s*SELECT 1 FROM bookings .*status NOT IN \('cancelled', 'expired'\)`).
+			WithArgs("room-123", hold.CheckInDate, hold.CheckOutDate).
+			WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(true))
+		mock.ExpectRollback()
+
+		err := repo.CreateHold(context.Background(), hold, 15*time.Minute)
+		assert.ErrorIs(t, err, domain.ErrRoomNotAvailable)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestGetHoldByID(t *testing.T) {
+	db, mock := setupMockDB(t)
+	defer db.Close()
+
+	repo := NewPostgresHoldRepository(db)
+	hold := newTestHold()
+	now := time.Now()
+
+	mock.ExpectQuery(`SELECT .* FROM room_holds WHERE id = \$1`).
+		WithArgs("hold-123").
+		WillReturnRows(sqlmock.NewRows(holdColumns).AddRow(
+			hold.ID, hold.UserID, hold.HotelID, hold.RoomID, hold.CheckInDate, hold.CheckOutDate,
+			"converted", "booking-123", now, now,
+		))
+
+	result, err := repo.GetHoldByID(context.Background(), "hold-123")
+	assert.NoError(t, err)
+	assert.Equal(t, domain.HoldConverted, result.Status)
+	assert.Equal(t, "booking-123", result.BookingID)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetExpiredRoomHolds(t *testing.T) {
+	db, mock := setupMockDB(t)
+	defer db.Close()
+
+	repo := NewPostgresHoldRepository(db)
+	hold := newTestHold()
+	now := time.Now()
+
+	mock.ExpectQuery(`SELECT .* FROM room_holds WHERE room_id = \$1 AND status = \$2 AND expires_at <= CURRENT_TIMESTAMP`).
+		WithArgs("room-123", domain.HoldActive, hold.CheckInDate, hold.CheckOutDate).
+		WillReturnRows(sqlmock.NewRows(holdColumns).AddRow(
+			hold.ID, hold.UserID, hold.HotelID, hold.RoomID, hold.CheckInDate, hold.CheckOutDate,
+			"active", "", now, now,
+		))
+
+	holds, err := repo.GetExpiredRoomHolds(context.Background(), "room-123", hold.CheckInDate, hold.CheckOutDate)
+	assert.NoError(t, err)
+	assert.Len(t, holds, 1)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetExpiredHolds(t *testing.T) {
+	db, mock := setupMockDB(t)
+	defer db.Close()
+
+	repo := NewPostgresHoldRepository(db)
+	hold := newTestHold()
+	now := time.Now()
+
+	mock.ExpectQuery(`SELECT .* FROM room_holds WHERE status = \$1 AND expires_at <= CURRENT_TIMESTAMP`).
+		WithArgs(domain.HoldActive, 100).
+		WillReturnRows(sqlmock.NewRows(holdColumns).AddRow(
+			hold.ID, hold.UserID, hold.HotelID, hold.RoomID, hold.CheckInDate, hold.CheckOutDate,
+			"active", "", now, now,
+		))
+
+	holds, err := repo.GetExpiredHolds(context.Background(), 100)
+	assert.NoError(t, err)
+	assert.Len(t, holds, 1)
+	assert.Equal(t, "hold-123", holds[0].ID)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestExpireHold(t *testing.T) {
+	event := &domain.OutboxEvent{Topic: domain.EventBookingHoldExpired, Key: "hold-123", Payload: []byte(`{"hold_id":"hold-123"}`)}
+
+	t.Run("writes outbox event", func(t *testing.T) {
+		db, mock := setupMockDB(t)
+		defer db.Close()
+
+		repo := NewPostgresHoldRepository(db)
+
+		mock.ExpectBegin()
+		mock.ExpectExec(`UPDATE room_holds SET status = \$2 WHERE id = \$1 AND status = \$3`).
+			WithArgs("hold-123", domain.HoldExpired, domain.HoldActive).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectQuery(`INSERT INTO booking_outbox`).
+			WithArgs(domain.EventBookingHoldExpired, "hold-123", `{"hold_id":"hold-123"}`).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "created_at"}).AddRow(int64(1), time.Now()))
+		mock.ExpectCommit()
+
+		err := repo.ExpireHold(context.Background(), "hold-123", event)
+		assert.NoError(t, err)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("already converted", func(t *testing.T) {
+		db, mock := setupMockDB(t)
+		defer db.Close()
+
+		repo := NewPostgresHoldRepository(db)
+
+		mock.ExpectBegin()
+		mock.ExpectExec(`UPDATE room_holds SET status = \$2`).
+			WithArgs("hold-123", domain.HoldExpired, domain.HoldActive).
+			WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectRollback()
+
+		err := repo.ExpireHold(context.Background(), "hold-123", event)
+		assert.ErrorIs(t, err, domain.ErrHoldNotActive)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("database error", func(t *testing.T) {
+		db, mock := setupMockDB(t)
+		defer db.Close()
+
+		repo := NewPostgresHoldRepository(db)
+
+		mock.ExpectBegin()
+		mock.ExpectExec(`UPDATE room_holds`).WillReturnError(errors.New("update error"))
+		mock.ExpectRollback()
+
+		err := repo.ExpireHold(context.Background(), "hold-123", event)
+		assert.Error(t, err)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
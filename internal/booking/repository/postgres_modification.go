@@ -0,0 +1,162 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+
+	"hotel-booking-system/internal/booking/domain"
+	"hotel-booking-system/pkg/money"
+
+	"github.com/lib/pq"
+)
+
+func insertModification(ctx context.Context, tx *sql.Tx, modification *domain.BookingModification) error {
+	previous, err := json.Marshal(modification.Previous)
+	if err != nil {
+		return err
+	}
+
+	currency := modification.Previous.DisplayPrice.Currency
+	query := `INSERT INTO booking_modifications (id, booking_id, previous, additional_charge, refund_amount, currency, status) 
+			  VALUES ($1, $2, $3, $4, $5, $6, $7) 
+			  RETURNING created_at, updated_at`
+	err = tx.QueryRowContext(ctx, query,
+		modification.ID, modification.BookingID, string(previous),
+		nullableAmount(modification.AdditionalCharge), nullableAmount(modification.RefundAmount), currency,
+		modification.Status,
+	).Scan(&modification.CreatedAt, &modification.UpdatedAt)
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) && pqErr.Code == uniqueViolation {
+		return domain.ErrBookingNotModifiable
+	}
+	return err
+}
+
+// nullableAmount stores a missing amount as NULL.
+func nullableAmount(amount *money.Money) interface{} {
+	if amount == nil {
+		return nil
+	}
+	return *amount
+}
+
+// RevertModification restores the stay a pending modification replaced and
+// marks it reverted. It fails with ErrStatusChanged if the modification is no
+// longer pending, with ErrRoomNotAvailable if the previous room has been taken
+// since and with ErrBookingNotModifiable if the booking has left the status it
+// was modified in.
+func (r *PostgresBookingRepository) RevertModification(ctx context.Context, modification *domain.BookingModification) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err := setModificationStatus(ctx, tx, modification.ID, domain.ModificationPending, domain.ModificationReverted); err != nil {
+		return err
+	}
+
+	previous := modification.Previous
+	err = updateStay(ctx, tx, &previous)
+	if errors.Is(err, domain.ErrStatusChanged) {
+		return domain.ErrBookingNotModifiable
+	}
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// UpdateModificationStatus settles or flags a modification and writes the
+// event, if any, in the same transaction.
+func (r *PostgresBookingRepository) UpdateModificationStatus(ctx context.Context, id string, from, to domain.ModificationStatus, event *domain.OutboxEvent) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err := setModificationStatus(ctx, tx, id, from, to); err != nil {
+		return err
+	}
+
+	if err := insertOutboxEvent(ctx, tx, event); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func setModificationStatus(ctx context.Context, tx *sql.Tx, id string, from, to domain.ModificationStatus) error {
+	query := `UPDATE booking_modifications SET status = $3, updated_at = CURRENT_TIMESTAMP WHERE id = $1 AND status = $2`
+	result, err := tx.ExecContext(ctx, query, id, from, to)
+	if err != nil {
+		return err
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return domain.ErrStatusChanged
+	}
+	return nil
+}
+
+// SetModificationPayment records the payment of a pending modification's
+// additional charge. A modification settled before its payment was recorded
+// is left as it is.
+func (r *PostgresBookingRepository) SetModificationPayment(ctx context.Context, id, paymentID string) error {
+	query := `UPDATE booking_modifications SET payment_id = $3, updated_at = CURRENT_TIMESTAMP WHERE id = $1 AND status = $2`
+	_, err := r.db.ExecContext(ctx, query, id, domain.ModificationPending, paymentID)
+	return err
+}
+
+// GetPendingChargeModification returns the pending modification whose
+// additional charge is the given payment. Until the payment of a charge is
+// recorded, the pending charge of a booking paid under the reference matches
+// any payment: the booking's own payment is captured by then, so the payment
+// can only be that charge.
+func (r *PostgresBookingRepository) GetPendingChargeModification(ctx context.Context, paymentReference, paymentID string) (*domain.BookingModification, error) {
+	query := `SELECT m.id, m.booking_id, m.previous, m.additional_charge, m.refund_amount, m.currency, 
+			  COALESCE(m.payment_id, ''), m.status, m.created_at, m.updated_at 
+			  FROM booking_modifications m JOIN bookings b ON b.id = m.booking_id 
+			  WHERE m.status = $1 AND m.additional_charge IS NOT NULL 
+			  AND (m.payment_id = $2 OR (m.payment_id IS NULL AND (b.id::text = $3 OR b.reservation_id::text = $3))) 
+			  ORDER BY m.payment_id NULLS LAST LIMIT 1`
+	modification := &domain.BookingModification{}
+	var previous []byte
+	var additionalCharge, refundAmount sql.NullString
+	var currency string
+	if err := r.db.QueryRowContext(ctx, query, domain.ModificationPending, paymentID, paymentReference).Scan(
+		&modification.ID, &modification.BookingID, &previous, &additionalCharge, &refundAmount, &currency,
+		&modification.PaymentID, &modification.Status, &modification.CreatedAt, &modification.UpdatedAt,
+	); err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(previous, &modification.Previous); err != nil {
+		return nil, err
+	}
+	var err error
+	if modification.AdditionalCharge, err = parseNullableAmount(additionalCharge, currency); err != nil {
+		return nil, err
+	}
+	if modification.RefundAmount, err = parseNullableAmount(refundAmount, currency); err != nil {
+		return nil, err
+	}
+	return modification, nil
+}
+
+func parseNullableAmount(amount sql.NullString, currency string) (*money.Money, error) {
+	if !amount.Valid {
+		return nil, nil
+	}
+	parsed, err := money.Parse(amount.String, currency)
+	if err != nil {
+		return nil, err
+	}
+	return &parsed, nil
+}
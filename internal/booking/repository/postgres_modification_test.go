@@ -0,0 +1,212 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"hotel-booking-system/internal/booking/domain"
+	"hotel-booking-system/pkg/money"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRevertModification(t *testing.T) {
+	checkIn := time.Date(2030, 12, 20, 0, 0, 0, 0, time.UTC)
+	newModification := func() *domain.BookingModification {
+		charge := money.New(250000, "RUB")
+		return &domain.BookingModification{
+			ID:        "modification-1",
+			BookingID: "booking-123",
+			Previous: domain.Booking{
+				ID:           "booking-123",
+				RoomID:       "room-123",
+				CheckInDate:  checkIn,
+				CheckOutDate: checkIn.AddDate(0, 0, 2),
+				TotalPrice:   money.New(500000, "RUB"),
+				DisplayPrice: money.New(500000, "RUB"),
+				Status:       domain.StatusConfirmed,
+			},
+			AdditionalCharge: &charge,
+			Status:           domain.ModificationPending,
+		}
+	}
+	markReverted := `UPDATE booking_modifications SET status = \$3, updated_at = CURRENT_TIMESTAMP WHERE id = \$1 AND status = \$2`
+
+	t.Run("restores the previous stay", func(t *testing.T) {
+		db, mock := setupMockDB(t)
+		defer db.Close()
+
+		repo := NewPostgresBookingRepository(db)
+
+		mock.ExpectBegin()
+		mock.ExpectExec(markReverted).
+			WithArgs("modification-1", domain.ModificationPending, domain.ModificationReverted).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		expectRoomFree(mock, "room_holds")
+		mock.ExpectQuery(`UPDATE bookings SET room_id = \$3.*WHERE id = \$1 AND status = \$2`).
+			WithArgs("booking-123", domain.StatusConfirmed, "room-123", "", checkIn, checkIn.AddDate(0, 0, 2),
+				money.New(500000, "RUB"), "[]", money.New(500000, "RUB"), nil).
+			WillReturnRows(sqlmock.NewRows([]string{"updated_at"}).AddRow(time.Now()))
+		mock.ExpectCommit()
+
+		err := repo.RevertModification(context.Background(), newModification())
+		assert.NoError(t, err)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("no longer pending", func(t *testing.T) {
+		db, mock := setupMockDB(t)
+		defer db.Close()
+
+		repo := NewPostgresBookingRepository(db)
+
+		mock.ExpectBegin()
+		mock.ExpectExec(markReverted).WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectRollback()
+
+		err := repo.RevertModification(context.Background(), newModification())
+		assert.ErrorIs(t, err, domain.ErrStatusChanged)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("previous room taken", func(t *testing.T) {
+		db, mock := setupMockDB(t)
+		defer db.Close()
+
+		repo := NewPostgresBookingRepository(db)
+
+		mock.ExpectBegin()
+		mock.ExpectExec(markReverted).WillReturnResult(sqlmock.NewResult(0, 1))
+		expectRoomFree(mock, "room_holds")
+		mock.ExpectQuery(`UPDATE bookings SET room_id`).
+			WillReturnError(&pq.Error{Code: "23P01", Constraint: "bookings_no_overlap"})
+		mock.ExpectRollback()
+
+		err := repo.RevertModification(context.Background(), newModification())
+		assert.ErrorIs(t, err, domain.ErrRoomNotAvailable)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("booking moved on", func(t *testing.T) {
+		db, mock := setupMockDB(t)
+		defer db.Close()
+
+		repo := NewPostgresBookingRepository(db)
+
+		mock.ExpectBegin()
+		mock.ExpectExec(markReverted).WillReturnResult(sqlmock.NewResult(0, 1))
+		expectRoomFree(mock, "room_holds")
+		mock.ExpectQuery(`UPDATE bookings SET room_id`).
+			WillReturnRows(sqlmock.NewRows([]string{"updated_at"}))
+		mock.ExpectRollback()
+
+		err := repo.RevertModification(context.Background(), newModification())
+		assert.ErrorIs(t, err, domain.ErrBookingNotModifiable)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestUpdateModificationStatus(t *testing.T) {
+	t.Run("settles with the event", func(t *testing.T) {
+		db, mock := setupMockDB(t)
+		defer db.Close()
+
+		repo := NewPostgresBookingRepository(db)
+		event := &domain.OutboxEvent{Topic: domain.EventBookingModified, Key: "booking-123", Payload: []byte(`{}`)}
+
+		mock.ExpectBegin()
+		mock.ExpectExec(`UPDATE booking_modifications SET status = \$3`).
+			WithArgs("modification-1", domain.ModificationPending, domain.ModificationSettled).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectQuery(`INSERT INTO booking_outbox`).
+			WithArgs(domain.EventBookingModified, "booking-123", `{}`).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "created_at"}).AddRow(int64(1), time.Now()))
+		mock.ExpectCommit()
+
+		err := repo.UpdateModificationStatus(context.Background(), "modification-1", domain.ModificationPending, domain.ModificationSettled, event)
+		assert.NoError(t, err)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("status changed", func(t *testing.T) {
+		db, mock := setupMockDB(t)
+		defer db.Close()
+
+		repo := NewPostgresBookingRepository(db)
+
+		mock.ExpectBegin()
+		mock.ExpectExec(`UPDATE booking_modifications SET status = \$3`).
+			WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectRollback()
+
+		err := repo.UpdateModificationStatus(context.Background(), "modification-1", domain.ModificationPending, domain.ModificationUnsettled, nil)
+		assert.ErrorIs(t, err, domain.ErrStatusChanged)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestSetModificationPayment(t *testing.T) {
+	db, mock := setupMockDB(t)
+	defer db.Close()
+
+	repo := NewPostgresBookingRepository(db)
+
+	mock.ExpectExec(`UPDATE booking_modifications SET payment_id = \$3.*WHERE id = \$1 AND status = \$2`).
+		WithArgs("modification-1", domain.ModificationPending, "payment-456").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	err := repo.SetModificationPayment(context.Background(), "modification-1", "payment-456")
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetPendingChargeModification(t *testing.T) {
+	columns := []string{"id", "booking_id", "previous", "additional_charge", "refund_amount", "currency",
+		"payment_id", "status", "created_at", "updated_at"}
+	query := `SELECT m.id, m.booking_id, m.previous, .* FROM booking_modifications m JOIN bookings b ON b.id = m.booking_id`
+
+	t.Run("found", func(t *testing.T) {
+		db, mock := setupMockDB(t)
+		defer db.Close()
+
+		repo := NewPostgresBookingRepository(db)
+		now := time.Now()
+
+		mock.ExpectQuery(query).
+			WithArgs(domain.ModificationPending, "payment-456", "booking-123").
+			WillReturnRows(sqlmock.NewRows(columns).AddRow(
+				"modification-1", "booking-123",
+				[]byte(`{"id":"booking-123","room_id":"room-123","status":"confirmed"}`),
+				"2500.00", nil, "RUB", "payment-456", "pending", now, now,
+			))
+
+		modification, err := repo.GetPendingChargeModification(context.Background(), "booking-123", "payment-456")
+		assert.NoError(t, err)
+		assert.Equal(t, "modification-1", modification.ID)
+		assert.Equal(t, "room-123", modification.Previous.RoomID)
+		assert.Equal(t, domain.StatusConfirmed, modification.Previous.Status)
+		if assert.NotNil(t, modification.AdditionalCharge) {
+			assert.Equal(t, money.New(250000, "RUB"), *modification.AdditionalCharge)
+		}
+		assert.Nil(t, modification.RefundAmount)
+		assert.Equal(t, "payment-456", modification.PaymentID)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		db, mock := setupMockDB(t)
+		defer db.Close()
+
+		repo := NewPostgresBookingRepository(db)
+
+		mock.ExpectQuery(query).WillReturnRows(sqlmock.NewRows(columns))
+
+		_, err := repo.GetPendingChargeModification(context.Background(), "booking-123", "payment-456")
+		assert.ErrorIs(t, err, sql.ErrNoRows)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
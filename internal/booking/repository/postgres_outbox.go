@@ -0,0 +1,60 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+
+	"hotel-booking-system/internal/booking/domain"
+)
+
+type PostgresOutboxRepository struct {
+	db *sql.DB
+}
+
+func NewPostgresOutboxRepository(db *sql.DB) *PostgresOutboxRepository {
+	return &PostgresOutboxRepository{db: db}
+}
+
+func (r *PostgresOutboxRepository) GetPendingEvents(ctx context.Context, limit int) ([]domain.OutboxEvent, error) {
+	query := `SELECT id, topic, key, payload, attempts, created_at 
+			  FROM booking_outbox WHERE sent_at IS NULL ORDER BY id LIMIT $1`
+	rows, err := r.db.QueryContext(ctx, query, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var events []domain.OutboxEvent
+	for rows.Next() {
+		var event domain.OutboxEvent
+		if err := rows.Scan(
+			&event.ID, &event.Topic, &event.Key, &event.Payload, &event.Attempts, &event.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		events = append(events, event)
+	}
+	return events, rows.Err()
+}
+
+func (r *PostgresOutboxRepository) MarkEventSent(ctx context.Context, id int64) error {
+	query := `UPDATE booking_outbox SET sent_at = CURRENT_TIMESTAMP, attempts = attempts + 1, last_error = NULL 
+			  WHERE id = $1`
+	_, err := r.db.ExecContext(ctx, query, id)
+	return err
+}
+
+func (r *PostgresOutboxRepository) MarkEventFailed(ctx context.Context, id int64, reason string) error {
+	query := `UPDATE booking_outbox SET attempts = attempts + 1, last_error = $2 WHERE id = $1`
+	_, err := r.db.ExecContext(ctx, query, id, reason)
+	return err
+}
+
+func insertOutboxEvent(ctx context.Context, tx *sql.Tx, event *domain.OutboxEvent) error {
+	if event == nil {
+		return nil
+	}
+	query := `INSERT INTO booking_outbox (topic, key, payload) VALUES ($1, $2, $3) 
+			  RETURNING id, created_at`
+	return tx.QueryRowContext(ctx, query, event.Topic, event.Key, string(event.Payload)).Scan(&event.ID, &event.CreatedAt)
+}
@@ -0,0 +1,81 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetPendingEvents_Success(t *testing.T) {
+	db, mock := setupMockDB(t)
+	defer db.Close()
+
+	repo := NewPostgresOutboxRepository(db)
+	now := time.Now()
+
+	rows := sqlmock.NewRows([]string{"id", "topic", "key", "payload", "attempts", "created_at"}).
+		AddRow(int64(1), "booking.created", "booking-1", []byte(`{"booking_id":"booking-1"}`), 0, now).
+		AddRow(int64(2), "booking.cancelled", "booking-2", []byte(`{"booking_id":"booking-2"}`), 2, now)
+
+	mock.ExpectQuery(`SELECT id, topic, key, payload, attempts, created_at FROM booking_outbox WHERE sent_at IS NULL`).
+		WithArgs(100).
+		WillReturnRows(rows)
+
+	events, err := repo.GetPendingEvents(context.Background(), 100)
+	assert.NoError(t, err)
+	assert.Len(t, events, 2)
+	assert.Equal(t, int64(1), events[0].ID)
+	assert.Equal(t, "booking.cancelled", events[1].Topic)
+	assert.Equal(t, 2, events[1].Attempts)
+	assert.JSONEq(t, `{"booking_id":"booking-2"}`, string(events[1].Payload))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetPendingEvents_DatabaseError(t *testing.T) {
+	db, mock := setupMockDB(t)
+	defer db.Close()
+
+	repo := NewPostgresOutboxRepository(db)
+
+	mock.ExpectQuery(`SELECT id, topic, key, payload, attempts, created_at FROM booking_outbox`).
+		WillReturnError(errors.New("query error"))
+
+	events, err := repo.GetPendingEvents(context.Background(), 100)
+	assert.Error(t, err)
+	assert.Nil(t, events)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestMarkEventSent(t *testing.T) {
+	db, mock := setupMockDB(t)
+	defer db.Close()
+
+	repo := NewPostgresOutboxRepository(db)
+
+	mock.ExpectExec(`UPDATE booking_outbox SET sent_at = CURRENT_TIMESTAMP`).
+		WithArgs(int64(1)).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	err := repo.MarkEventSent(context.Background(), 1)
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestMarkEventFailed(t *testing.T) {
+	db, mock := setupMockDB(t)
+	defer db.Close()
+
+	repo := NewPostgresOutboxRepository(db)
+
+	mock.ExpectExec(`UPDATE booking_outbox SET attempts = attempts \+ 1, last_error`).
+		WithArgs(int64(1), "broker unavailable").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	err := repo.MarkEventFailed(context.Background(), 1, "broker unavailable")
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,112 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"hotel-booking-system/internal/booking/domain"
+	"hotel-booking-system/pkg/money"
+
+	"github.com/lib/pq"
+)
+
+const uniqueViolation = "23505"
+
+type PostgresPromotionRepository struct {
+	db *sql.DB
+}
+
+func NewPostgresPromotionRepository(db *sql.DB) *PostgresPromotionRepository {
+	return &PostgresPromotionRepository{db: db}
+}
+
+func (r *PostgresPromotionRepository) CreatePromotion(ctx context.Context, promotion *domain.Promotion) error {
+	query := `INSERT INTO promotions (id, code, description, type, basis_points, amount, currency,
+			  stay_nights, pay_nights, hotel_id, room_types, valid_from, valid_until, max_uses, max_uses_per_user)
+			  VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15)
+			  RETURNING created_at`
+	var amount, currency, hotelID interface{}
+	if promotion.Amount != nil {
+		amount, currency = *promotion.Amount, promotion.Amount.Currency
+	}
+	if promotion.HotelID != "" {
+		hotelID = promotion.HotelID
+	}
+	roomTypes := pq.StringArray(promotion.RoomTypes)
+	if roomTypes == nil {
+		roomTypes = pq.StringArray{}
+	}
+	err := r.db.QueryRowContext(ctx, query,
+		promotion.ID, promotion.Code, promotion.Description, promotion.Type, promotion.BasisPoints,
+		amount, currency, promotion.StayNights, promotion.PayNights, hotelID, roomTypes,
+		promotion.ValidFrom, promotion.ValidUntil, promotion.MaxUses, promotion.MaxUsesPerUser,
+	).Scan(&promotion.CreatedAt)
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) && pqErr.Code == uniqueViolation {
+		return domain.ErrPromotionExists
+	}
+	return err
+}
+
+const promotionColumns = `id, code, description, type, basis_points, amount, currency,
+			  stay_nights, pay_nights, COALESCE(hotel_id::text, ''), room_types, valid_from, valid_until,
+			  max_uses, max_uses_per_user, created_at`
+
+func scanPromotion(row rowScanner, promotion *domain.Promotion) error {
+	var amount, currency sql.NullString
+	var validFrom, validUntil sql.NullTime
+	var roomTypes pq.StringArray
+	if err := row.Scan(
+		&promotion.ID, &promotion.Code, &promotion.Description, &promotion.Type, &promotion.BasisPoints,
+		&amount, &currency, &promotion.StayNights, &promotion.PayNights, &promotion.HotelID, &roomTypes,
+		&validFrom, &validUntil, &promotion.MaxUses, &promotion.MaxUsesPerUser, &promotion.CreatedAt,
+	); err != nil {
+		return err
+	}
+	if amount.Valid {
+		parsed, err := money.Parse(amount.String, currency.String)
+		if err != nil {
+			return err
+		}
+		promotion.Amount = &parsed
+	}
+	if len(roomTypes) > 0 {
+		promotion.RoomTypes = roomTypes
+	}
+	if validFrom.Valid {
+		promotion.ValidFrom = &validFrom.Time
+	}
+	if validUntil.Valid {
+		promotion.ValidUntil = &validUntil.Time
+	}
+	return nil
+}
+
+func (r *PostgresPromotionRepository) GetPromotionByCode(ctx context.Context, code string) (*domain.Promotion, error) {
+	promotion := &domain.Promotion{}
+	query := `SELECT ` + promotionColumns + ` FROM promotions WHERE code = $1`
+	if err := scanPromotion(r.db.QueryRowContext(ctx, query, code), promotion); err != nil {
+		return nil, err
+	}
+	return promotion, nil
+}
+
+func (r *PostgresPromotionRepository) GetPromotions(ctx context.Context) ([]domain.Promotion, error) {
+	query := `SELECT ` + promotionColumns + ` FROM promotions ORDER BY created_at DESC`
+	rows, err := r.db.QueryContext(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	promotions := []domain.Promotion{}
+	for rows.Next() {
+		var promotion domain.Promotion
+		if err := scanPromotion(rows, &promotion); err != nil {
+			return nil, err
+		}
+		promotions = append(promotions, promotion)
+	}
+	return promotions, rows.Err()
+}
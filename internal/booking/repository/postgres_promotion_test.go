@@ -0,0 +1,81 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"hotel-booking-system/internal/booking/domain"
+	"hotel-booking-system/pkg/money"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCreatePromotion(t *testing.T) {
+	db, mock := setupMockDB(t)
+	defer db.Close()
+
+	repo := NewPostgresPromotionRepository(db)
+	promotion := &domain.Promotion{
+		ID:         "promo-123",
+		Code:       "STAY3PAY2",
+		Type:       domain.DiscountFreeNights,
+		StayNights: 3,
+		PayNights:  2,
+		RoomTypes:  []string{"Deluxe"},
+		MaxUses:    100,
+	}
+	createdAt := time.Now()
+
+	mock.ExpectQuery(`INSERT INTO promotions`).
+		WithArgs(promotion.ID, "STAY3PAY2", "", "free_nights", int64(0), nil, nil, 3, 2, nil,
+			pq.StringArray{"Deluxe"}, promotion.ValidFrom, promotion.ValidUntil, 100, 0).
+		WillReturnRows(sqlmock.NewRows([]string{"created_at"}).AddRow(createdAt))
+
+	err := repo.CreatePromotion(context.Background(), promotion)
+	assert.NoError(t, err)
+	assert.Equal(t, createdAt, promotion.CreatedAt)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestCreatePromotion_DuplicateCode(t *testing.T) {
+	db, mock := setupMockDB(t)
+	defer db.Close()
+
+	repo := NewPostgresPromotionRepository(db)
+	mock.ExpectQuery(`INSERT INTO promotions`).
+		WillReturnError(&pq.Error{Code: "23505"})
+
+	err := repo.CreatePromotion(context.Background(), &domain.Promotion{Code: "SUMMER10", Type: domain.DiscountPercentage})
+	assert.ErrorIs(t, err, domain.ErrPromotionExists)
+}
+
+func TestGetPromotionByCode(t *testing.T) {
+	db, mock := setupMockDB(t)
+	defer db.Close()
+
+	repo := NewPostgresPromotionRepository(db)
+	validUntil := time.Date(2030, 9, 1, 0, 0, 0, 0, time.UTC)
+
+	mock.ExpectQuery(`SELECT .* FROM promotions WHERE code = \$1`).
+		WithArgs("SUMMER10").
+		WillReturnRows(sqlmock.NewRows([]string{
+			"id", "code", "description", "type", "basis_points", "amount", "currency",
+			"stay_nights", "pay_nights", "hotel_id", "room_types", "valid_from", "valid_until",
+			"max_uses", "max_uses_per_user", "created_at",
+		}).AddRow("promo-123", "SUMMER10", "Летняя скидка", "fixed", 0, "1000.00", "RUB",
+			0, 0, "hotel-123", "{}", nil, validUntil, 0, 1, time.Now()))
+
+	promotion, err := repo.GetPromotionByCode(context.Background(), "SUMMER10")
+	assert.NoError(t, err)
+	assert.Equal(t, domain.DiscountFixed, promotion.Type)
+	assert.Equal(t, money.New(100000, "RUB"), *promotion.Amount)
+	assert.Equal(t, "hotel-123", promotion.HotelID)
+	assert.Empty(t, promotion.RoomTypes)
+	assert.Nil(t, promotion.ValidFrom)
+	assert.Equal(t, &validUntil, promotion.ValidUntil)
+	assert.Equal(t, 1, promotion.MaxUsesPerUser)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,57 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"hotel-booking-system/internal/booking/domain"
+	"hotel-booking-system/pkg/money"
+)
+
+type PostgresRateRepository struct {
+	db *sql.DB
+}
+
+func NewPostgresRateRepository(db *sql.DB) *PostgresRateRepository {
+	return &PostgresRateRepository{db: db}
+}
+
+// GetRate returns the latest rate effective at the given time. A rate stored
+// only in the opposite direction is inverted.
+func (r *PostgresRateRepository) GetRate(ctx context.Context, from, to string, at time.Time) (money.Rate, error) {
+	if from == to {
+		return money.IdentityRate(from), nil
+	}
+
+	query := `SELECT base_currency, rate FROM exchange_rates
+			  WHERE ((base_currency = $1 AND quote_currency = $2) OR (base_currency = $2 AND quote_currency = $1))
+			  AND effective_from <= $3
+			  ORDER BY effective_from DESC, base_currency = $1 DESC LIMIT 1`
+	var base, value string
+	err := r.db.QueryRowContext(ctx, query, from, to, at).Scan(&base, &value)
+	if errors.Is(err, sql.ErrNoRows) {
+		return money.Rate{}, domain.ErrRateNotFound
+	}
+	if err != nil {
+		return money.Rate{}, err
+	}
+
+	if base == from {
+		return money.ParseRate(from, to, value)
+	}
+	rate, err := money.ParseRate(to, from, value)
+	if err != nil {
+		return money.Rate{}, err
+	}
+	return rate.Inverse(), nil
+}
+
+func (r *PostgresRateRepository) SaveRate(ctx context.Context, rate money.Rate, effectiveFrom time.Time) error {
+	query := `INSERT INTO exchange_rates (base_currency, quote_currency, rate, effective_from)
+			  VALUES ($1, $2, $3, $4)
+			  ON CONFLICT (base_currency, quote_currency, effective_from) DO UPDATE SET rate = EXCLUDED.rate`
+	_, err := r.db.ExecContext(ctx, query, rate.From, rate.To, rate, effectiveFrom)
+	return err
+}
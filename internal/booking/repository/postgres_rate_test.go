@@ -0,0 +1,87 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"hotel-booking-system/internal/booking/domain"
+	"hotel-booking-system/pkg/money"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetRate(t *testing.T) {
+	at := time.Date(2024, 12, 20, 0, 0, 0, 0, time.UTC)
+
+	t.Run("same currency", func(t *testing.T) {
+		db, mock := setupMockDB(t)
+		defer db.Close()
+
+		rate, err := NewPostgresRateRepository(db).GetRate(context.Background(), "RUB", "RUB", at)
+		require.NoError(t, err)
+		assert.Equal(t, money.IdentityRate("RUB"), rate)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("stored direction", func(t *testing.T) {
+		db, mock := setupMockDB(t)
+		defer db.Close()
+
+		mock.ExpectQuery(`SELECT base_currency, rate FROM exchange_rates .*effective_from <= \$3`).
+			WithArgs("USD", "RUB", at).
+			WillReturnRows(sqlmock.NewRows([]string{"base_currency", "rate"}).AddRow("USD", "92.50000000"))
+
+		rate, err := NewPostgresRateRepository(db).GetRate(context.Background(), "USD", "RUB", at)
+		require.NoError(t, err)
+		assert.Equal(t, "1 USD = 92.5 RUB", rate.String())
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("inverted", func(t *testing.T) {
+		db, mock := setupMockDB(t)
+		defer db.Close()
+
+		mock.ExpectQuery(`SELECT base_currency, rate FROM exchange_rates`).
+			WithArgs("RUB", "USD", at).
+			WillReturnRows(sqlmock.NewRows([]string{"base_currency", "rate"}).AddRow("USD", "80"))
+
+		rate, err := NewPostgresRateRepository(db).GetRate(context.Background(), "RUB", "USD", at)
+		require.NoError(t, err)
+		assert.Equal(t, "1 RUB = 0.0125 USD", rate.String())
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		db, mock := setupMockDB(t)
+		defer db.Close()
+
+		mock.ExpectQuery(`SELECT base_currency, rate FROM exchange_rates`).
+			WithArgs("RUB", "CHF", at).
+			WillReturnError(sql.ErrNoRows)
+
+		_, err := NewPostgresRateRepository(db).GetRate(context.Background(), "RUB", "CHF", at)
+		assert.ErrorIs(t, err, domain.ErrRateNotFound)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestSaveRate(t *testing.T) {
+	db, mock := setupMockDB(t)
+	defer db.Close()
+
+	rate, err := money.ParseRate("EUR", "RUB", "100.25")
+	require.NoError(t, err)
+	effectiveFrom := time.Date(2024, 12, 1, 0, 0, 0, 0, time.UTC)
+
+	mock.ExpectExec(`INSERT INTO exchange_rates .*ON CONFLICT`).
+		WithArgs("EUR", "RUB", "100.25", effectiveFrom).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	err = NewPostgresRateRepository(db).SaveRate(context.Background(), rate, effectiveFrom)
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,53 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"hotel-booking-system/internal/booking/domain"
+)
+
+type PostgresSagaRepository struct {
+	db *sql.DB
+}
+
+func NewPostgresSagaRepository(db *sql.DB) *PostgresSagaRepository {
+	return &PostgresSagaRepository{db: db}
+}
+
+func (r *PostgresSagaRepository) CreateSaga(ctx context.Context, saga *domain.BookingSaga) error {
+	query := `INSERT INTO booking_sagas (booking_id, step, status) VALUES ($1, $2, $3) 
+			  RETURNING created_at, updated_at`
+	return r.db.QueryRowContext(ctx, query, saga.BookingID, saga.Step, saga.Status).
+		Scan(&saga.CreatedAt, &saga.UpdatedAt)
+}
+
+func (r *PostgresSagaRepository) UpdateSaga(ctx context.Context, saga *domain.BookingSaga) error {
+	query := `UPDATE booking_sagas SET step = $2, status = $3, last_error = $4, updated_at = CURRENT_TIMESTAMP 
+			  WHERE booking_id = $1 RETURNING updated_at`
+	return r.db.QueryRowContext(ctx, query, saga.BookingID, saga.Step, saga.Status, saga.LastError).
+		Scan(&saga.UpdatedAt)
+}
+
+func (r *PostgresSagaRepository) GetUnfinishedSagas(ctx context.Context, updatedBefore time.Time) ([]domain.BookingSaga, error) {
+	query := `SELECT booking_id, step, status, last_error, created_at, updated_at 
+			  FROM booking_sagas WHERE status = $1 AND updated_at < $2 ORDER BY created_at`
+	rows, err := r.db.QueryContext(ctx, query, domain.SagaRunning, updatedBefore)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var sagas []domain.BookingSaga
+	for rows.Next() {
+		var saga domain.BookingSaga
+		if err := rows.Scan(
+			&saga.BookingID, &saga.Step, &saga.Status, &saga.LastError, &saga.CreatedAt, &saga.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		sagas = append(sagas, saga)
+	}
+	return sagas, rows.Err()
+}
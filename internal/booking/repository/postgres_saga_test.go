@@ -0,0 +1,93 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"hotel-booking-system/internal/booking/domain"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCreateSaga(t *testing.T) {
+	db, mock := setupMockDB(t)
+	defer db.Close()
+
+	repo := NewPostgresSagaRepository(db)
+	saga := &domain.BookingSaga{BookingID: "booking-123", Step: domain.SagaStepReserve, Status: domain.SagaRunning}
+	now := time.Now()
+
+	mock.ExpectQuery(`INSERT INTO booking_sagas`).
+		WithArgs("booking-123", domain.SagaStepReserve, domain.SagaRunning).
+		WillReturnRows(sqlmock.NewRows([]string{"created_at", "updated_at"}).AddRow(now, now))
+
+	err := repo.CreateSaga(context.Background(), saga)
+	assert.NoError(t, err)
+	assert.Equal(t, now, saga.CreatedAt)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestUpdateSaga(t *testing.T) {
+	db, mock := setupMockDB(t)
+	defer db.Close()
+
+	repo := NewPostgresSagaRepository(db)
+	saga := &domain.BookingSaga{
+		BookingID: "booking-123",
+		Step:      domain.SagaStepPay,
+		Status:    domain.SagaCompensated,
+		LastError: "payment failed",
+	}
+	now := time.Now()
+
+	mock.ExpectQuery(`UPDATE booking_sagas SET step = \$2, status = \$3, last_error = \$4`).
+		WithArgs("booking-123", domain.SagaStepPay, domain.SagaCompensated, "payment failed").
+		WillReturnRows(sqlmock.NewRows([]string{"updated_at"}).AddRow(now))
+
+	err := repo.UpdateSaga(context.Background(), saga)
+	assert.NoError(t, err)
+	assert.Equal(t, now, saga.UpdatedAt)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetUnfinishedSagas(t *testing.T) {
+	db, mock := setupMockDB(t)
+	defer db.Close()
+
+	repo := NewPostgresSagaRepository(db)
+	now := time.Now()
+	before := now.Add(-time.Minute)
+
+	rows := sqlmock.NewRows([]string{"booking_id", "step", "status", "last_error", "created_at", "updated_at"}).
+		AddRow("booking-1", "pay", "running", "", now, now).
+		AddRow("booking-2", "publish", "running", "database error", now, now)
+
+	mock.ExpectQuery(`SELECT booking_id, step, status, last_error, created_at, updated_at FROM booking_sagas`).
+		WithArgs(domain.SagaRunning, before).
+		WillReturnRows(rows)
+
+	sagas, err := repo.GetUnfinishedSagas(context.Background(), before)
+	assert.NoError(t, err)
+	assert.Len(t, sagas, 2)
+	assert.Equal(t, domain.SagaStepPay, sagas[0].Step)
+	assert.Equal(t, "database error", sagas[1].LastError)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetUnfinishedSagas_DatabaseError(t *testing.T) {
+	db, mock := setupMockDB(t)
+	defer db.Close()
+
+	repo := NewPostgresSagaRepository(db)
+
+	mock.ExpectQuery(`SELECT booking_id, step, status, last_error, created_at, updated_at FROM booking_sagas`).
+		WillReturnError(errors.New("query error"))
+
+	sagas, err := repo.GetUnfinishedSagas(context.Background(), time.Now())
+	assert.Error(t, err)
+	assert.Nil(t, sagas)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,154 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"hotel-booking-system/internal/booking/domain"
+
+	"github.com/lib/pq"
+)
+
+type PostgresWaitlistRepository struct {
+	db *sql.DB
+}
+
+func NewPostgresWaitlistRepository(db *sql.DB) *PostgresWaitlistRepository {
+	return &PostgresWaitlistRepository{db: db}
+}
+
+func (r *PostgresWaitlistRepository) CreateWaitlistEntry(ctx context.Context, entry *domain.WaitlistEntry) error {
+	query := `INSERT INTO waitlist_entries (id, user_id, hotel_id, room_type, check_in_date, check_out_date, status)
+			  VALUES ($1, $2, $3, $4, $5, $6, $7)
+			  RETURNING created_at`
+	return r.db.QueryRowContext(ctx, query,
+		entry.ID, entry.UserID, entry.HotelID, entry.RoomType,
+		entry.CheckInDate, entry.CheckOutDate, entry.Status,
+	).Scan(&entry.CreatedAt)
+}
+
+const waitlistColumns = `id, user_id, hotel_id, room_type, check_in_date, check_out_date, status,
+			  COALESCE(room_id::text, ''), COALESCE(hold_id::text, ''), offer_expires_at, created_at`
+
+func scanWaitlistEntry(row rowScanner, entry *domain.WaitlistEntry) error {
+	return row.Scan(
+		&entry.ID, &entry.UserID, &entry.HotelID, &entry.RoomType,
+		&entry.CheckInDate, &entry.CheckOutDate, &entry.Status,
+		&entry.RoomID, &entry.HoldID, &entry.OfferExpiresAt, &entry.CreatedAt,
+	)
+}
+
+func (r *PostgresWaitlistRepository) GetWaitlistEntryByID(ctx context.Context, id string) (*domain.WaitlistEntry, error) {
+	entry := &domain.WaitlistEntry{}
+	query := `SELECT ` + waitlistColumns + ` FROM waitlist_entries WHERE id = $1`
+	if err := scanWaitlistEntry(r.db.QueryRowContext(ctx, query, id), entry); err != nil {
+		return nil, err
+	}
+	return entry, nil
+}
+
+func (r *PostgresWaitlistRepository) GetWaitlistEntryByHoldID(ctx context.Context, holdID string) (*domain.WaitlistEntry, error) {
+	entry := &domain.WaitlistEntry{}
+	query := `SELECT ` + waitlistColumns + ` FROM waitlist_entries WHERE hold_id = $1`
+	if err := scanWaitlistEntry(r.db.QueryRowContext(ctx, query, holdID), entry); err != nil {
+		return nil, err
+	}
+	return entry, nil
+}
+
+// GetWaitingEntries returns the entries still waiting for a room of the type
+// whose stay overlaps the dates, first come first.
+func (r *PostgresWaitlistRepository) GetWaitingEntries(ctx context.Context, hotelID, roomType string, checkIn, checkOut time.Time) ([]domain.WaitlistEntry, error) {
+	query := `SELECT ` + waitlistColumns + ` FROM waitlist_entries
+			  WHERE hotel_id = $1 AND room_type = $2 AND status = $3
+			  AND daterange(check_in_date, check_out_date) && daterange($4::date, $5::date)
+			  ORDER BY created_at, id`
+	rows, err := r.db.QueryContext(ctx, query, hotelID, roomType, domain.WaitlistWaiting, checkIn, checkOut)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var entries []domain.WaitlistEntry
+	for rows.Next() {
+		var entry domain.WaitlistEntry
+		if err := scanWaitlistEntry(rows, &entry); err != nil {
+			return nil, err
+		}
+		entries = append(entries, entry)
+	}
+	return entries, rows.Err()
+}
+
+func (r *PostgresWaitlistRepository) OfferWaitlistEntry(ctx context.Context, entry *domain.WaitlistEntry, hold *domain.RoomHold, event *domain.OutboxEvent) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err := lockRoomAgainstBookings(ctx, tx, hold.RoomID, hold.CheckInDate, hold.CheckOutDate); err != nil {
+		return err
+	}
+
+	query := `INSERT INTO room_holds (id, user_id, hotel_id, room_id, check_in_date, check_out_date, status, expires_at)
+			  VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+			  RETURNING created_at`
+	err = tx.QueryRowContext(ctx, query,
+		hold.ID, hold.UserID, hold.HotelID, hold.RoomID,
+		hold.CheckInDate, hold.CheckOutDate, hold.Status, hold.ExpiresAt,
+	).Scan(&hold.CreatedAt)
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) && pqErr.Code == exclusionViolation {
+		return domain.ErrRoomNotAvailable
+	}
+	if err != nil {
+		return err
+	}
+
+	query = `UPDATE waitlist_entries SET status = $2, room_id = $3, hold_id = $4, offer_expires_at = $5
+			  WHERE id = $1 AND status = $6`
+	result, err := tx.ExecContext(ctx, query,
+		entry.ID, domain.WaitlistOffered, hold.RoomID, hold.ID, hold.ExpiresAt, domain.WaitlistWaiting)
+	if err != nil {
+		return err
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return domain.ErrWaitlistEntryClosed
+	}
+
+	if err := insertOutboxEvent(ctx, tx, event); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+	entry.Status = domain.WaitlistOffered
+	entry.RoomID = hold.RoomID
+	entry.HoldID = hold.ID
+	entry.OfferExpiresAt = &hold.ExpiresAt
+	return nil
+}
+
+func (r *PostgresWaitlistRepository) UpdateWaitlistStatus(ctx context.Context, id string, from, to domain.WaitlistStatus) error {
+	query := `UPDATE waitlist_entries SET status = $2 WHERE id = $1 AND status = $3`
+	result, err := r.db.ExecContext(ctx, query, id, to, from)
+	if err != nil {
+		return err
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return domain.ErrWaitlistEntryClosed
+	}
+	return nil
+}
@@ -0,0 +1,200 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"hotel-booking-system/internal/booking/domain"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+)
+
+var waitlistColumnNames = []string{
+	"id", "user_id", "hotel_id", "room_type", "check_in_date", "check_out_date", "status",
+	"room_id", "hold_id", "offer_expires_at", "created_at",
+}
+
+func newTestWaitlistEntry() *domain.WaitlistEntry {
+	checkIn := time.Date(2024, 12, 20, 0, 0, 0, 0, time.UTC)
+	return &domain.WaitlistEntry{
+		ID:           "entry-123",
+		UserID:       "user-123",
+		HotelID:      "hotel-123",
+		RoomType:     "double",
+		CheckInDate:  checkIn,
+		CheckOutDate: checkIn.Add(48 * time.Hour),
+		Status:       domain.WaitlistWaiting,
+	}
+}
+
+func TestCreateWaitlistEntry(t *testing.T) {
+	db, mock := setupMockDB(t)
+	defer db.Close()
+
+	repo := NewPostgresWaitlistRepository(db)
+	entry := newTestWaitlistEntry()
+	now := time.Now()
+
+	mock.ExpectQuery(`INSERT INTO waitlist_entries`).
+		WithArgs(entry.ID, entry.UserID, entry.HotelID, entry.RoomType, entry.CheckInDate, entry.CheckOutDate, domain.WaitlistWaiting).
+		WillReturnRows(sqlmock.NewRows([]string{"created_at"}).AddRow(now))
+
+	err := repo.CreateWaitlistEntry(context.Background(), entry)
+	assert.NoError(t, err)
+	assert.Equal(t, now, entry.CreatedAt)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetWaitlistEntryByHoldID(t *testing.T) {
+	db, mock := setupMockDB(t)
+	defer db.Close()
+
+	repo := NewPostgresWaitlistRepository(db)
+	entry := newTestWaitlistEntry()
+	expiresAt := time.Now()
+
+	mock.ExpectQuery(`SELECT .* FROM waitlist_entries WHERE hold_id = \$1`).
+		WithArgs("hold-123").
+		WillReturnRows(sqlmock.NewRows(waitlistColumnNames).AddRow(
+			entry.ID, entry.UserID, entry.HotelID, entry.RoomType, entry.CheckInDate, entry.CheckOutDate,
+			domain.WaitlistOffered, "room-123", "hold-123", expiresAt, time.Now(),
+		))
+
+	result, err := repo.GetWaitlistEntryByHoldID(context.Background(), "hold-123")
+	assert.NoError(t, err)
+	assert.Equal(t, domain.WaitlistOffered, result.Status)
+	assert.Equal(t, "room-123", result.RoomID)
+	assert.Equal(t, expiresAt, *result.OfferExpiresAt)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetWaitingEntries(t *testing.T) {
+	db, mock := setupMockDB(t)
+	defer db.Close()
+
+	repo := NewPostgresWaitlistRepository(db)
+	entry := newTestWaitlistEntry()
+
+	mock.ExpectQuery(`SELECT .* FROM waitlist_entries .*status = \$3 .*ORDER BY created_at, id`).
+		WithArgs("hotel-123", "double", domain.WaitlistWaiting, entry.CheckInDate, entry.CheckOutDate).
+		WillReturnRows(sqlmock.NewRows(waitlistColumnNames).AddRow(
+			entry.ID, entry.UserID, entry.HotelID, entry.RoomType, entry.CheckInDate, entry.CheckOutDate,
+			domain.WaitlistWaiting, "", "", nil, time.Now(),
+		))
+
+	entries, err := repo.GetWaitingEntries(context.Background(), "hotel-123", "double", entry.CheckInDate, entry.CheckOutDate)
+	assert.NoError(t, err)
+	assert.Len(t, entries, 1)
+	assert.Nil(t, entries[0].OfferExpiresAt)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestOfferWaitlistEntry(t *testing.T) {
+	event := &domain.OutboxEvent{Topic: domain.EventWaitlistOffered, Key: "entry-123", Payload: []byte(`{"entry_id":"entry-123"}`)}
+	newOfferHold := func() *domain.RoomHold {
+		hold := newTestHold()
+		hold.ExpiresAt = time.Date(2024, 12, 1, 12, 0, 0, 0, time.UTC)
+		return hold
+	}
+
+	t.Run("holds the room and writes outbox event", func(t *testing.T) {
+		db, mock := setupMockDB(t)
+		defer db.Close()
+
+		repo := NewPostgresWaitlistRepository(db)
+		entry := newTestWaitlistEntry()
+		hold := newOfferHold()
+
+		mock.ExpectBegin()
+		expectRoomFree(mock, "bookings")
+		mock.ExpectQuery(`INSERT INTO room_holds`).
+			WithArgs(hold.ID, hold.UserID, hold.HotelID, hold.RoomID, hold.CheckInDate, hold.CheckOutDate, domain.HoldActive, hold.ExpiresAt).
+			WillReturnRows(sqlmock.NewRows([]string{"created_at"}).AddRow(time.Now()))
+		mock.ExpectExec(`UPDATE waitlist_entries SET status = \$2, room_id = \$3, hold_id = \$4, offer_expires_at = \$5`).
+			WithArgs("entry-123", domain.WaitlistOffered, "room-123", "hold-123", hold.ExpiresAt, domain.WaitlistWaiting).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectQuery(`INSERT INTO booking_outbox`).
+			WithArgs(domain.EventWaitlistOffered, "entry-123", `{"entry_id":"entry-123"}`).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "created_at"}).AddRow(int64(1), time.Now()))
+		mock.ExpectCommit()
+
+		err := repo.OfferWaitlistEntry(context.Background(), entry, hold, event)
+		assert.NoError(t, err)
+		assert.Equal(t, domain.WaitlistOffered, entry.Status)
+		assert.Equal(t, "hold-123", entry.HoldID)
+		assert.Equal(t, hold.ExpiresAt, *entry.OfferExpiresAt)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("room taken", func(t *testing.T) {
+		db, mock := setupMockDB(t)
+		defer db.Close()
+
+		repo := NewPostgresWaitlistRepository(db)
+
+		mock.ExpectBegin()
+		expectRoomFree(mock, "bookings")
+		mock.ExpectQuery(`INSERT INTO room_holds`).
+			WillReturnError(&pq.Error{Code: exclusionViolation})
+		mock.ExpectRollback()
+
+		err := repo.OfferWaitlistEntry(context.Background(), newTestWaitlistEntry(), newOfferHold(), event)
+		assert.ErrorIs(t, err, domain.ErrRoomNotAvailable)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("entry no longer waiting", func(t *testing.T) {
+		db, mock := setupMockDB(t)
+		defer db.Close()
+
+		repo := NewPostgresWaitlistRepository(db)
+		entry := newTestWaitlistEntry()
+
+		mock.ExpectBegin()
+		expectRoomFree(mock, "bookings")
+		mock.ExpectQuery(`INSERT INTO room_holds`).
+			WillReturnRows(sqlmock.NewRows([]string{"created_at"}).AddRow(time.Now()))
+		mock.ExpectExec(`UPDATE waitlist_entries`).
+			WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectRollback()
+
+		err := repo.OfferWaitlistEntry(context.Background(), entry, newOfferHold(), event)
+		assert.ErrorIs(t, err, domain.ErrWaitlistEntryClosed)
+		assert.Equal(t, domain.WaitlistWaiting, entry.Status)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestUpdateWaitlistStatus(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		db, mock := setupMockDB(t)
+		defer db.Close()
+
+		repo := NewPostgresWaitlistRepository(db)
+
+		mock.ExpectExec(`UPDATE waitlist_entries SET status = \$2 WHERE id = \$1 AND status = \$3`).
+			WithArgs("entry-123", domain.WaitlistCancelled, domain.WaitlistWaiting).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+
+		err := repo.UpdateWaitlistStatus(context.Background(), "entry-123", domain.WaitlistWaiting, domain.WaitlistCancelled)
+		assert.NoError(t, err)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("status changed", func(t *testing.T) {
+		db, mock := setupMockDB(t)
+		defer db.Close()
+
+		repo := NewPostgresWaitlistRepository(db)
+
+		mock.ExpectExec(`UPDATE waitlist_entries SET status = \$2`).
+			WillReturnResult(sqlmock.NewResult(0, 0))
+
+		err := repo.UpdateWaitlistStatus(context.Background(), "entry-123", domain.WaitlistWaiting, domain.WaitlistCancelled)
+		assert.ErrorIs(t, err, domain.ErrWaitlistEntryClosed)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
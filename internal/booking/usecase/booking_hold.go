@@ -0,0 +1,120 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"hotel-booking-system/internal/booking/domain"
+
+	"github.com/google/uuid"
+)
+
+// CreateHold holds a room of the hotel for the dates. The hold blocks the
+// room until the booking made from it is stored, which converts the hold; the
+// booking blocks the room from then on, while it awaits payment too.
+func (uc *BookingUseCase) CreateHold(ctx context.Context, hold *domain.RoomHold) error {
+	if !hold.CheckInDate.Before(hold.CheckOutDate) {
+		return domain.ErrInvalidDates
+	}
+
+	// The hotel service quotes only rooms of the hotel it has, so the quote
+	// checks the hotel and the room as it does when a booking is created.
+	if _, err := uc.hotelClient.GetQuote(ctx, hold.HotelID, hold.RoomID, hold.CheckInDate, hold.CheckOutDate, 1, 0); err != nil {
+		return err
+	}
+
+	overlapping, err := uc.repo.HasOverlappingBooking(ctx, hold.RoomID, hold.CheckInDate, hold.CheckOutDate, "")
+	if err != nil {
+		return err
+	}
+	if overlapping {
+		return domain.ErrRoomNotAvailable
+	}
+
+	if err := uc.expireStaleHolds(ctx, hold.RoomID, hold.CheckInDate, hold.CheckOutDate); err != nil {
+		return err
+	}
+
+	hold.ID = uuid.New().String()
+	hold.Status = domain.HoldActive
+
+	return uc.holds.CreateHold(ctx, hold, uc.holdTTL)
+}
+
+func (uc *BookingUseCase) GetHold(ctx context.Context, id string) (*domain.RoomHold, error) {
+	return uc.holds.GetHoldByID(ctx, id)
+}
+
+// applyHold takes the room and dates of a booking from the hold it converts.
+// The overlap check is skipped: the hold itself is what blocks the room.
+func (uc *BookingUseCase) applyHold(ctx context.Context, booking *domain.Booking) error {
+	hold, err := uc.holds.GetHoldByID(ctx, booking.HoldID)
+	if err != nil {
+		return err
+	}
+	if hold.Status != domain.HoldActive {
+		return domain.ErrHoldNotActive
+	}
+
+	booking.UserID = hold.UserID
+	booking.HotelID = hold.HotelID
+	booking.RoomID = hold.RoomID
+	booking.CheckInDate = hold.CheckInDate
+	booking.CheckOutDate = hold.CheckOutDate
+	return nil
+}
+
+func (uc *BookingUseCase) ExpireHolds(ctx context.Context, limit int) (int, error) {
+	holds, err := uc.holds.GetExpiredHolds(ctx, limit)
+	if err != nil {
+		return 0, err
+	}
+
+	expired := 0
+	for i := range holds {
+		err := uc.expireHold(ctx, &holds[i])
+		if errors.Is(err, domain.ErrHoldNotActive) {
+			continue
+		}
+		if err != nil {
+			return expired, err
+		}
+		expired++
+	}
+	return expired, nil
+}
+
+// expireStaleHolds expires the holds of the room for the dates that ran out
+// but have not been reaped yet, since the database still counts them against
+// new holds of the room.
+func (uc *BookingUseCase) expireStaleHolds(ctx context.Context, roomID string, checkIn, checkOut time.Time) error {
+	holds, err := uc.holds.GetExpiredRoomHolds(ctx, roomID, checkIn, checkOut)
+	if err != nil {
+		return err
+	}
+	for i := range holds {
+		if err := uc.expireHold(ctx, &holds[i]); err != nil && !errors.Is(err, domain.ErrHoldNotActive) {
+			return err
+		}
+	}
+	return nil
+}
+
+func (uc *BookingUseCase) expireHold(ctx context.Context, hold *domain.RoomHold) error {
+	event, err := uc.newOutboxEvent(domain.EventBookingHoldExpired, hold.ID, domain.HoldEvent{
+		HoldID:       hold.ID,
+		UserID:       hold.UserID,
+		HotelID:      hold.HotelID,
+		RoomID:       hold.RoomID,
+		CheckInDate:  hold.CheckInDate,
+		CheckOutDate: hold.CheckOutDate,
+		ExpiresAt:    hold.ExpiresAt,
+		EventType:    domain.EventBookingHoldExpired,
+		Timestamp:    time.Now(),
+	})
+	if err != nil {
+		return err
+	}
+	return uc.holds.ExpireHold(ctx, hold.ID, event)
+}
@@ -0,0 +1,207 @@
+package usecase
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"hotel-booking-system/internal/booking/domain"
+	"hotel-booking-system/pkg/hotelclient"
+	"hotel-booking-system/pkg/money"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func newTestHold() *domain.RoomHold {
+	checkIn := time.Now().Add(24 * time.Hour)
+	return &domain.RoomHold{
+		ID:           "hold-123",
+		UserID:       "user-123",
+		HotelID:      "hotel-123",
+		RoomID:       "room-123",
+		CheckInDate:  checkIn,
+		CheckOutDate: checkIn.Add(48 * time.Hour),
+		Status:       domain.HoldActive,
+	}
+}
+
+func TestCreateHold_Success(t *testing.T) {
+	mockRepo := new(MockBookingRepository)
+	mockHolds := new(MockHoldRepository)
+	hold := newTestHold()
+	hold.ID = ""
+
+	mockRepo.On("HasOverlappingBooking", mock.Anything, "room-123", hold.CheckInDate, hold.CheckOutDate, "").Return(false, nil)
+	mockHolds.On("GetExpiredRoomHolds", mock.Anything, "room-123", hold.CheckInDate, hold.CheckOutDate).Return(nil, nil)
+	mockHolds.On("CreateHold", mock.Anything, hold, 15*time.Minute).Return(nil)
+
+	uc := NewBookingUseCase(mockRepo, nil, mockHolds, &MockHotelClient{}, nil, nil, nil, nil, 15*time.Minute, 0, nil)
+	err := uc.CreateHold(context.Background(), hold)
+
+	assert.NoError(t, err)
+	assert.NotEmpty(t, hold.ID)
+	assert.Equal(t, domain.HoldActive, hold.Status)
+	mockHolds.AssertExpectations(t)
+}
+
+func TestCreateHold_ExpiresStaleHoldsFirst(t *testing.T) {
+	mockRepo := new(MockBookingRepository)
+	mockHolds := new(MockHoldRepository)
+	hold := newTestHold()
+	hold.ID = ""
+	stale := newTestHold()
+	stale.ID = "hold-456"
+	stale.ExpiresAt = time.Now().Add(-time.Minute)
+
+	var calls []string
+	mockRepo.On("HasOverlappingBooking", mock.Anything, "room-123", hold.CheckInDate, hold.CheckOutDate, "").Return(false, nil)
+	mockHolds.On("GetExpiredRoomHolds", mock.Anything, "room-123", hold.CheckInDate, hold.CheckOutDate).Return([]domain.RoomHold{*stale}, nil)
+	mockHolds.On("ExpireHold", mock.Anything, "hold-456", mock.MatchedBy(func(event *domain.OutboxEvent) bool {
+		return event.Topic == domain.EventBookingHoldExpired && event.Key == "hold-456"
+	})).Run(func(args mock.Arguments) { calls = append(calls, "expire") }).Return(nil)
+	mockHolds.On("CreateHold", mock.Anything, hold, 15*time.Minute).
+		Run(func(args mock.Arguments) { calls = append(calls, "create") }).Return(nil)
+
+	uc := NewBookingUseCase(mockRepo, nil, mockHolds, &MockHotelClient{}, nil, nil, nil, nil, 15*time.Minute, 0, nil)
+	err := uc.CreateHold(context.Background(), hold)
+
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"expire", "create"}, calls)
+	mockHolds.AssertExpectations(t)
+}
+
+func TestCreateHold_RoomNotAvailable(t *testing.T) {
+	mockRepo := new(MockBookingRepository)
+	mockHolds := new(MockHoldRepository)
+	hold := newTestHold()
+
+	mockRepo.On("HasOverlappingBooking", mock.Anything, "room-123", hold.CheckInDate, hold.CheckOutDate, "").Return(true, nil)
+
+	uc := NewBookingUseCase(mockRepo, nil, mockHolds, &MockHotelClient{}, nil, nil, nil, nil, 15*time.Minute, 0, nil)
+	err := uc.CreateHold(context.Background(), hold)
+
+	assert.ErrorIs(t, err, domain.ErrRoomNotAvailable)
+	mockHolds.AssertNotCalled(t, "CreateHold", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestCreateHold_UnknownRoom(t *testing.T) {
+	mockHolds := new(MockHoldRepository)
+	hold := newTestHold()
+	hotelClient := &MockHotelClient{GetQuoteFunc: func(ctx context.Context, hotelID, roomID string, checkIn, checkOut time.Time, adults, children int) (*hotelclient.Quote, error) {
+		assert.Equal(t, "hotel-123", hotelID)
+		assert.Equal(t, "room-123", roomID)
+		return nil, errors.New("hotel service returned status 404: room not found")
+	}}
+
+	uc := NewBookingUseCase(new(MockBookingRepository), nil, mockHolds, hotelClient, nil, nil, nil, nil, 15*time.Minute, 0, nil)
+	err := uc.CreateHold(context.Background(), hold)
+
+	assert.Error(t, err)
+	mockHolds.AssertNotCalled(t, "CreateHold", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestCreateHold_InvalidDates(t *testing.T) {
+	hold := newTestHold()
+	hold.CheckOutDate = hold.CheckInDate
+
+	uc := NewBookingUseCase(new(MockBookingRepository), nil, new(MockHoldRepository), &MockHotelClient{}, nil, nil, nil, nil, 15*time.Minute, 0, nil)
+	err := uc.CreateHold(context.Background(), hold)
+
+	assert.ErrorIs(t, err, domain.ErrInvalidDates)
+}
+
+func TestCreateBooking_FromHold(t *testing.T) {
+	mockRepo := new(MockBookingRepository)
+	mockSagas := new(MockSagaRepository)
+	mockHolds := new(MockHoldRepository)
+	hold := newTestHold()
+
+	mockHolds.On("GetHoldByID", mock.Anything, "hold-123").Return(hold, nil)
+	expectReservation(mockRepo, mockSagas)
+	mockRepo.On("UpdateBookingStatus", mock.Anything, mock.Anything, domain.StatusPending, domain.StatusConfirmed, mock.Anything).Return(nil)
+
+	mockClient := &MockHotelClient{
+		GetQuoteFunc: flatQuote(money.New(500000, "RUB")),
+	}
+
+	uc := NewBookingUseCase(mockRepo, mockSagas, mockHolds, mockClient, nil, nil, nil, nil, 15*time.Minute, 0, nil)
+	booking := &domain.Booking{HoldID: "hold-123"}
+	err := uc.CreateBooking(context.Background(), booking)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "room-123", booking.RoomID)
+	assert.Equal(t, "user-123", booking.UserID)
+	assert.Equal(t, money.New(1000000, "RUB"), booking.TotalPrice)
+	assert.Equal(t, domain.StatusConfirmed, booking.Status)
+	mockRepo.AssertCalled(t, "CreateBooking", mock.Anything, mock.MatchedBy(func(stored *domain.Booking) bool {
+		return stored.HoldID == "hold-123"
+	}))
+	mockRepo.AssertNotCalled(t, "HasOverlappingBooking", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	mockHolds.AssertExpectations(t)
+}
+
+func TestCreateBooking_FromInactiveHold(t *testing.T) {
+	mockRepo := new(MockBookingRepository)
+	mockHolds := new(MockHoldRepository)
+	hold := newTestHold()
+	hold.Status = domain.HoldExpired
+
+	mockHolds.On("GetHoldByID", mock.Anything, "hold-123").Return(hold, nil)
+
+	uc := NewBookingUseCase(mockRepo, nil, mockHolds, &MockHotelClient{}, nil, nil, nil, nil, 15*time.Minute, 0, nil)
+	err := uc.CreateBooking(context.Background(), &domain.Booking{HoldID: "hold-123"})
+
+	assert.ErrorIs(t, err, domain.ErrHoldNotActive)
+	mockRepo.AssertNotCalled(t, "CreateBooking", mock.Anything, mock.Anything)
+}
+
+func TestCreateBooking_HoldExpiredBeforeConversion(t *testing.T) {
+	mockRepo := new(MockBookingRepository)
+	mockSagas := new(MockSagaRepository)
+	mockHolds := new(MockHoldRepository)
+
+	mockHolds.On("GetHoldByID", mock.Anything, "hold-123").Return(newTestHold(), nil)
+	mockSagas.On("CreateSaga", mock.Anything, mock.Anything).Return(nil)
+	mockRepo.On("CreateBooking", mock.Anything, mock.Anything).Return(domain.ErrHoldNotActive)
+	mockSagas.On("UpdateSaga", mock.Anything, mock.MatchedBy(func(saga domain.BookingSaga) bool {
+		return saga.Status == domain.SagaCompensated
+	})).Return(nil)
+
+	mockClient := &MockHotelClient{
+		GetQuoteFunc: flatQuote(money.New(500000, "RUB")),
+	}
+
+	uc := NewBookingUseCase(mockRepo, mockSagas, mockHolds, mockClient, nil, nil, nil, nil, 15*time.Minute, 0, nil)
+	err := uc.CreateBooking(context.Background(), &domain.Booking{HoldID: "hold-123"})
+
+	assert.ErrorIs(t, err, domain.ErrHoldNotActive)
+	mockSagas.AssertExpectations(t)
+}
+
+func TestExpireHolds(t *testing.T) {
+	mockHolds := new(MockHoldRepository)
+	first := newTestHold()
+	second := newTestHold()
+	second.ID = "hold-456"
+
+	mockHolds.On("GetExpiredHolds", mock.Anything, 100).Return([]domain.RoomHold{*first, *second}, nil)
+	mockHolds.On("ExpireHold", mock.Anything, "hold-123", mock.Anything).Return(nil)
+	mockHolds.On("ExpireHold", mock.Anything, "hold-456", mock.Anything).Return(domain.ErrHoldNotActive)
+
+	uc := NewBookingUseCase(new(MockBookingRepository), nil, mockHolds, &MockHotelClient{}, nil, nil, nil, nil, 15*time.Minute, 0, nil)
+	expired, err := uc.ExpireHolds(context.Background(), 100)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 1, expired)
+
+	event := mockHolds.Calls[1].Arguments.Get(2).(*domain.OutboxEvent)
+	assert.Equal(t, domain.EventBookingHoldExpired, event.Topic)
+	assert.Equal(t, "hold-123", event.Key)
+	var payload domain.HoldEvent
+	assert.NoError(t, json.Unmarshal(event.Payload, &payload))
+	assert.Equal(t, "room-123", payload.RoomID)
+	assert.Equal(t, domain.EventBookingHoldExpired, payload.EventType)
+}
@@ -0,0 +1,215 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"hotel-booking-system/internal/booking/domain"
+	"hotel-booking-system/pkg/logger"
+
+	"github.com/google/uuid"
+)
+
+// ModifyBooking moves a confirmed booking to other dates or another room of
+// the same hotel and reprices it. The promo code is applied as of the time the
+// booking was made and the difference is converted at the booking's exchange
+// rate; the cancellation policy becomes that of the new stay. A higher price
+// is charged and a lower one partially refunded; an authorized payment is
+// captured first, as it could only be captured whole. The change is stored
+// before any money moves, together with a pending modification, and is
+// reverted if the difference cannot be settled.
+func (uc *BookingUseCase) ModifyBooking(ctx context.Context, id string, change domain.BookingChange) (*domain.Booking, error) {
+	booking, err := uc.repo.GetBookingByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if booking.Status != domain.StatusConfirmed {
+		return nil, domain.ErrBookingNotModifiable
+	}
+
+	modified := *booking
+	if change.RoomID != "" {
+		modified.RoomID = change.RoomID
+	}
+	if !change.CheckInDate.IsZero() {
+		modified.CheckInDate = change.CheckInDate
+	}
+	if !change.CheckOutDate.IsZero() {
+		modified.CheckOutDate = change.CheckOutDate
+	}
+	if modified.RoomID == booking.RoomID && modified.CheckInDate.Equal(booking.CheckInDate) &&
+		modified.CheckOutDate.Equal(booking.CheckOutDate) {
+		return nil, domain.ErrEmptyBookingChange
+	}
+	if !modified.CheckInDate.Before(modified.CheckOutDate) {
+		return nil, domain.ErrInvalidDates
+	}
+
+	overlapping, err := uc.repo.HasOverlappingBooking(ctx, modified.RoomID, modified.CheckInDate, modified.CheckOutDate, booking.ID)
+	if err != nil {
+		return nil, err
+	}
+	if overlapping {
+		return nil, domain.ErrRoomNotAvailable
+	}
+
+	if err := uc.priceStay(ctx, &modified, booking.CreatedAt); err != nil {
+		return nil, err
+	}
+	if modified.DisplayPrice, err = modified.ChargeAmount(modified.TotalPrice); err != nil {
+		return nil, err
+	}
+	difference, err := modified.DisplayPrice.Sub(booking.DisplayPrice)
+	if err != nil {
+		return nil, err
+	}
+
+	if uc.paymentClient == nil || difference.IsZero() {
+		event, err := uc.newOutboxEvent(domain.EventBookingModified, booking.ID, newBookingEvent(&modified, domain.EventBookingModified))
+		if err != nil {
+			return nil, err
+		}
+		if err := uc.repo.ModifyBooking(ctx, &modified, nil, event); err != nil {
+			return nil, err
+		}
+		return &modified, nil
+	}
+
+	if err := uc.capturePaymentForModification(ctx, booking); err != nil {
+		return nil, err
+	}
+
+	modification := &domain.BookingModification{
+		ID:        uuid.New().String(),
+		BookingID: booking.ID,
+		Previous:  *booking,
+		Status:    domain.ModificationPending,
+	}
+	if difference.IsPositive() {
+		modification.AdditionalCharge = &difference
+	} else {
+		refund := difference.Neg()
+		modification.RefundAmount = &refund
+	}
+	if err := uc.repo.ModifyBooking(ctx, &modified, modification, nil); err != nil {
+		return nil, err
+	}
+
+	if modification.AdditionalCharge != nil {
+		paymentID, err := uc.paymentClient.ChargePayment(ctx, booking.PaymentReference(), modificationChargeID(booking, &modified), *modification.AdditionalCharge)
+		if err != nil {
+			uc.abandonModification(ctx, modification)
+			return nil, fmt.Errorf("%w: %v", domain.ErrPaymentFailed, err)
+		}
+		// The charge is processed asynchronously; the payment webhook settles
+		// or reverts the modification once it reports the charge's outcome.
+		if err := uc.repo.SetModificationPayment(ctx, modification.ID, paymentID); err != nil {
+			logger.GetLogger().WithError(err).WithField("booking_id", booking.ID).Warn("failed to record the payment of an extra charge")
+		}
+		return &modified, nil
+	}
+
+	if err := uc.paymentClient.RefundPayment(ctx, booking.PaymentReference(), *modification.RefundAmount); err != nil {
+		uc.abandonModification(ctx, modification)
+		return nil, fmt.Errorf("%w: %v", domain.ErrPaymentFailed, err)
+	}
+	if err := uc.settleModification(ctx, &modified, modification); err != nil {
+		return nil, err
+	}
+
+	return &modified, nil
+}
+
+// capturePaymentForModification makes sure the booking's payment is captured
+// before its price changes.
+func (uc *BookingUseCase) capturePaymentForModification(ctx context.Context, booking *domain.Booking) error {
+	switch booking.PaymentStatus {
+	case domain.PaymentPaid, domain.PaymentPartiallyRefunded:
+		return nil
+	case domain.PaymentAuthorized:
+		if err := uc.paymentClient.CapturePayment(ctx, booking.PaymentReference()); err != nil {
+			return fmt.Errorf("%w: %v", domain.ErrPaymentCaptureFailed, err)
+		}
+		return nil
+	default:
+		return fmt.Errorf("%w: payment is %s", domain.ErrPaymentFailed, booking.PaymentStatus)
+	}
+}
+
+// settleModification marks the modification of the booking settled and writes
+// its booking.modified event.
+func (uc *BookingUseCase) settleModification(ctx context.Context, booking *domain.Booking, modification *domain.BookingModification) error {
+	bookingEvent := newBookingEvent(booking, domain.EventBookingModified)
+	bookingEvent.AdditionalCharge = modification.AdditionalCharge
+	bookingEvent.RefundAmount = modification.RefundAmount
+	event, err := uc.newOutboxEvent(domain.EventBookingModified, booking.ID, bookingEvent)
+	if err != nil {
+		return err
+	}
+	return uc.repo.UpdateModificationStatus(ctx, modification.ID, domain.ModificationPending, domain.ModificationSettled, event)
+}
+
+// settleModificationCharge completes a modification once the payment webhook
+// reports the outcome of its additional charge: a paid charge settles it and a
+// failed one reverts it. The booking's own payment status is left as it is.
+// A modification that is no longer pending was completed by an earlier
+// delivery of the webhook.
+func (uc *BookingUseCase) settleModificationCharge(ctx context.Context, modification *domain.BookingModification, paymentStatus domain.PaymentStatus) error {
+	if paymentStatus == domain.PaymentFailed {
+		return uc.revertModification(ctx, modification)
+	}
+
+	booking, err := uc.repo.GetBookingByID(ctx, modification.BookingID)
+	if err != nil {
+		return err
+	}
+	err = uc.settleModification(ctx, booking, modification)
+	if errors.Is(err, domain.ErrStatusChanged) {
+		return nil
+	}
+	return err
+}
+
+// abandonModification reverts a modification whose difference could not be
+// settled while the guest waits for the answer, which already reports the
+// failure.
+func (uc *BookingUseCase) abandonModification(ctx context.Context, modification *domain.BookingModification) {
+	if err := uc.revertModification(ctx, modification); err != nil {
+		logger.GetLogger().WithError(err).WithField("booking_id", modification.BookingID).Error("failed to revert a booking modification")
+	}
+}
+
+// revertModification restores the stay a modification replaced once its
+// price difference could not be settled. If the previous stay cannot be
+// restored any more, the modification is flagged as unsettled for the hotel
+// to settle with the guest. A modification that is no longer pending is left
+// as it is.
+func (uc *BookingUseCase) revertModification(ctx context.Context, modification *domain.BookingModification) error {
+	err := uc.repo.RevertModification(ctx, modification)
+	switch {
+	case err == nil, errors.Is(err, domain.ErrStatusChanged):
+		return nil
+	case errors.Is(err, domain.ErrRoomNotAvailable), errors.Is(err, domain.ErrBookingNotModifiable):
+		logger.GetLogger().WithError(err).WithField("booking_id", modification.BookingID).
+			WithField("modification_id", modification.ID).Warn("could not restore the stay of an unsettled booking modification")
+		err := uc.repo.UpdateModificationStatus(ctx, modification.ID, domain.ModificationPending, domain.ModificationUnsettled, nil)
+		if errors.Is(err, domain.ErrStatusChanged) {
+			return nil
+		}
+		return err
+	default:
+		return err
+	}
+}
+
+// modificationChargeID identifies the extra charge of a modification by the
+// booking, its new stay and the version of the booking it modifies, so that a
+// retried request is charged once while a later modification to the same stay
+// is charged again.
+func modificationChargeID(booking, modified *domain.Booking) string {
+	return fmt.Sprintf("%s-%s-%s-%s-%d", booking.ID, modified.RoomID,
+		modified.CheckInDate.Format("2006-01-02"), modified.CheckOutDate.Format("2006-01-02"),
+		booking.UpdatedAt.UnixNano())
+}
@@ -0,0 +1,443 @@
+package usecase
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"hotel-booking-system/internal/booking/domain"
+	"hotel-booking-system/pkg/money"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func confirmedBooking(checkIn time.Time, nights int) *domain.Booking {
+	price := money.New(500000*int64(nights), "RUB")
+	return &domain.Booking{
+		ID:              "booking123",
+		UserID:          "user123",
+		HotelID:         "hotel123",
+		RoomID:          "room123",
+		CheckInDate:     checkIn,
+		CheckOutDate:    checkIn.AddDate(0, 0, nights),
+		TotalPrice:      price,
+		PriceBreakdown:  []domain.PriceItem{{Kind: domain.PriceItemAccommodation, Amount: price}},
+		DisplayCurrency: "RUB",
+		DisplayPrice:    price,
+		ExchangeRate:    money.IdentityRate("RUB"),
+		Status:          domain.StatusConfirmed,
+		PaymentStatus:   domain.PaymentPaid,
+		CreatedAt:       time.Now().AddDate(0, 0, -7),
+		UpdatedAt:       time.Date(2030, 1, 2, 3, 4, 5, 0, time.UTC),
+	}
+}
+
+func TestModifyBooking_ExtendingTheStayChargesTheDifference(t *testing.T) {
+	checkIn := time.Date(2030, 12, 20, 0, 0, 0, 0, time.UTC)
+	mockRepo := new(MockBookingRepository)
+	mockRepo.On("GetBookingByID", mock.Anything, "booking123").Return(confirmedBooking(checkIn, 2), nil)
+	mockRepo.On("HasOverlappingBooking", mock.Anything, "room123", checkIn, checkIn.AddDate(0, 0, 3), "booking123").Return(false, nil)
+	var modification *domain.BookingModification
+	var calls []string
+	mockRepo.On("ModifyBooking", mock.Anything, mock.Anything, mock.Anything, (*domain.OutboxEvent)(nil)).
+		Run(func(args mock.Arguments) {
+			calls = append(calls, "store")
+			modification = args.Get(2).(*domain.BookingModification)
+		}).
+		Return(nil)
+	mockRepo.On("SetModificationPayment", mock.Anything, mock.Anything, "payment456").Return(nil)
+
+	var charged money.Money
+	mockPayment := &MockPaymentService{
+		ChargePaymentFunc: func(ctx context.Context, bookingID, chargeID string, amount money.Money) (string, error) {
+			calls = append(calls, "charge")
+			assert.Equal(t, "booking123-room123-2030-12-20-2030-12-23-1893553445000000000", chargeID)
+			charged = amount
+			return "payment456", nil
+		},
+		RefundPaymentFunc: func(ctx context.Context, bookingID string, amount money.Money) error {
+			t.Fatal("unexpected refund")
+			return nil
+		},
+	}
+
+	uc := NewBookingUseCase(mockRepo, nil, nil, &MockHotelClient{GetQuoteFunc: flatQuote(money.New(500000, "RUB"))}, mockPayment, nil, nil, nil, 0, 0, nil)
+
+	booking, err := uc.ModifyBooking(context.Background(), "booking123", domain.BookingChange{CheckOutDate: checkIn.AddDate(0, 0, 3)})
+	require.NoError(t, err)
+	assert.Equal(t, money.New(1500000, "RUB"), booking.TotalPrice)
+	assert.Equal(t, money.New(1500000, "RUB"), booking.DisplayPrice)
+	assert.Equal(t, money.New(500000, "RUB"), charged)
+	assert.Equal(t, []string{"store", "charge"}, calls)
+
+	require.NotNil(t, modification)
+	assert.Equal(t, domain.ModificationPending, modification.Status)
+	assert.Equal(t, checkIn.AddDate(0, 0, 2), modification.Previous.CheckOutDate)
+	require.NotNil(t, modification.AdditionalCharge)
+	assert.Equal(t, money.New(500000, "RUB"), *modification.AdditionalCharge)
+
+	mockRepo.AssertCalled(t, "SetModificationPayment", mock.Anything, modification.ID, "payment456")
+	mockRepo.AssertNotCalled(t, "UpdateModificationStatus", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestModifyBooking_CheaperRoomRefundsTheDifferenceAtTheBookingRate(t *testing.T) {
+	checkIn := time.Date(2030, 12, 20, 0, 0, 0, 0, time.UTC)
+	rate, err := money.ParseRate("RUB", "USD", "0.0125")
+	require.NoError(t, err)
+	original := confirmedBooking(checkIn, 2)
+	original.DisplayCurrency = "USD"
+	original.ExchangeRate = rate
+	original.DisplayPrice = money.New(12500, "USD")
+
+	mockRepo := new(MockBookingRepository)
+	mockRepo.On("GetBookingByID", mock.Anything, "booking123").Return(original, nil)
+	mockRepo.On("HasOverlappingBooking", mock.Anything, "room456", checkIn, checkIn.AddDate(0, 0, 2), "booking123").Return(false, nil)
+	mockRepo.On("ModifyBooking", mock.Anything, mock.Anything, mock.Anything, (*domain.OutboxEvent)(nil)).Return(nil)
+	var outboxEvent *domain.OutboxEvent
+	mockRepo.On("UpdateModificationStatus", mock.Anything, mock.Anything, domain.ModificationPending, domain.ModificationSettled, mock.Anything).
+		Run(func(args mock.Arguments) { outboxEvent = args.Get(4).(*domain.OutboxEvent) }).
+		Return(nil)
+
+	var refunded money.Money
+	mockPayment := &MockPaymentService{
+		RefundPaymentFunc: func(ctx context.Context, bookingID string, amount money.Money) error {
+			refunded = amount
+			return nil
+		},
+	}
+
+	uc := NewBookingUseCase(mockRepo, nil, nil, &MockHotelClient{GetQuoteFunc: flatQuote(money.New(400000, "RUB"))}, mockPayment, nil, nil, nil, 0, 0, nil)
+
+	booking, err := uc.ModifyBooking(context.Background(), "booking123", domain.BookingChange{RoomID: "room456"})
+	require.NoError(t, err)
+	assert.Equal(t, "room456", booking.RoomID)
+	assert.Equal(t, money.New(10000, "USD"), booking.DisplayPrice)
+	assert.Equal(t, money.New(2500, "USD"), refunded)
+	mockRepo.AssertExpectations(t)
+
+	require.NotNil(t, outboxEvent)
+	assert.Equal(t, domain.EventBookingModified, outboxEvent.Topic)
+	var publishedEvent domain.BookingEvent
+	require.NoError(t, json.Unmarshal(outboxEvent.Payload, &publishedEvent))
+	require.NotNil(t, publishedEvent.RefundAmount)
+	assert.Equal(t, money.New(2500, "USD"), *publishedEvent.RefundAmount)
+	assert.Nil(t, publishedEvent.AdditionalCharge)
+}
+
+func TestModifyBooking_SamePriceIsStoredWithItsEvent(t *testing.T) {
+	checkIn := time.Date(2030, 12, 20, 0, 0, 0, 0, time.UTC)
+	mockRepo := new(MockBookingRepository)
+	mockRepo.On("GetBookingByID", mock.Anything, "booking123").Return(confirmedBooking(checkIn, 2), nil)
+	mockRepo.On("HasOverlappingBooking", mock.Anything, "room456", checkIn, checkIn.AddDate(0, 0, 2), "booking123").Return(false, nil)
+	mockRepo.On("ModifyBooking", mock.Anything, mock.Anything, (*domain.BookingModification)(nil), mock.AnythingOfType("*domain.OutboxEvent")).Return(nil)
+
+	uc := NewBookingUseCase(mockRepo, nil, nil, &MockHotelClient{GetQuoteFunc: flatQuote(money.New(500000, "RUB"))}, &MockPaymentService{}, nil, nil, nil, 0, 0, nil)
+
+	booking, err := uc.ModifyBooking(context.Background(), "booking123", domain.BookingChange{RoomID: "room456"})
+	require.NoError(t, err)
+	assert.Equal(t, "room456", booking.RoomID)
+	mockRepo.AssertExpectations(t)
+	mockRepo.AssertNotCalled(t, "UpdateModificationStatus", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestModifyBooking_PartiallyRefundedBookingIsCharged(t *testing.T) {
+	checkIn := time.Date(2030, 12, 20, 0, 0, 0, 0, time.UTC)
+	original := confirmedBooking(checkIn, 2)
+	original.PaymentStatus = domain.PaymentPartiallyRefunded
+
+	mockRepo := new(MockBookingRepository)
+	mockRepo.On("GetBookingByID", mock.Anything, "booking123").Return(original, nil)
+	mockRepo.On("HasOverlappingBooking", mock.Anything, "room123", checkIn, checkIn.AddDate(0, 0, 3), "booking123").Return(false, nil)
+	mockRepo.On("ModifyBooking", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil)
+	mockRepo.On("SetModificationPayment", mock.Anything, mock.Anything, mock.Anything).Return(nil)
+
+	var charged money.Money
+	mockPayment := &MockPaymentService{
+		ChargePaymentFunc: func(ctx context.Context, bookingID, chargeID string, amount money.Money) (string, error) {
+			charged = amount
+			return "payment456", nil
+		},
+	}
+
+	uc := NewBookingUseCase(mockRepo, nil, nil, &MockHotelClient{GetQuoteFunc: flatQuote(money.New(500000, "RUB"))}, mockPayment, nil, nil, nil, 0, 0, nil)
+
+	_, err := uc.ModifyBooking(context.Background(), "booking123", domain.BookingChange{CheckOutDate: checkIn.AddDate(0, 0, 3)})
+	require.NoError(t, err)
+	assert.Equal(t, money.New(500000, "RUB"), charged)
+}
+
+func TestModifyBooking_AuthorizedPaymentIsCapturedFirst(t *testing.T) {
+	checkIn := time.Date(2030, 12, 20, 0, 0, 0, 0, time.UTC)
+	original := confirmedBooking(checkIn, 2)
+	original.PaymentStatus = domain.PaymentAuthorized
+
+	var calls []string
+	mockRepo := new(MockBookingRepository)
+	mockRepo.On("GetBookingByID", mock.Anything, "booking123").Return(original, nil)
+	mockRepo.On("HasOverlappingBooking", mock.Anything, "room123", mock.Anything, mock.Anything, "booking123").Return(false, nil)
+	mockRepo.On("ModifyBooking", mock.Anything, mock.Anything, mock.Anything, mock.Anything).
+		Run(func(args mock.Arguments) { calls = append(calls, "store") }).
+		Return(nil)
+	mockRepo.On("SetModificationPayment", mock.Anything, mock.Anything, mock.Anything).Return(nil)
+
+	mockPayment := &MockPaymentService{
+		CapturePaymentFunc: func(ctx context.Context, bookingID string) error {
+			calls = append(calls, "capture")
+			return nil
+		},
+		ChargePaymentFunc: func(ctx context.Context, bookingID, chargeID string, amount money.Money) (string, error) {
+			calls = append(calls, "charge")
+			return "payment456", nil
+		},
+	}
+
+	uc := NewBookingUseCase(mockRepo, nil, nil, &MockHotelClient{GetQuoteFunc: flatQuote(money.New(500000, "RUB"))}, mockPayment, nil, nil, nil, 0, 0, nil)
+
+	_, err := uc.ModifyBooking(context.Background(), "booking123", domain.BookingChange{CheckInDate: checkIn.AddDate(0, 0, -1)})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"capture", "store", "charge"}, calls)
+}
+
+func TestModificationChargeID(t *testing.T) {
+	checkIn := time.Date(2030, 12, 20, 0, 0, 0, 0, time.UTC)
+	booking := confirmedBooking(checkIn, 2)
+	modified := *booking
+	modified.CheckOutDate = checkIn.AddDate(0, 0, 3)
+
+	assert.Equal(t, modificationChargeID(booking, &modified), modificationChargeID(booking, &modified))
+
+	otherRoom := modified
+	otherRoom.RoomID = "room456"
+	assert.NotEqual(t, modificationChargeID(booking, &modified), modificationChargeID(booking, &otherRoom))
+
+	updated := *booking
+	updated.UpdatedAt = booking.UpdatedAt.Add(time.Second)
+	assert.NotEqual(t, modificationChargeID(booking, &modified), modificationChargeID(&updated, &modified))
+}
+
+func TestModifyBooking_FailedStoreMovesNoMoney(t *testing.T) {
+	checkIn := time.Date(2030, 12, 20, 0, 0, 0, 0, time.UTC)
+	mockRepo := new(MockBookingRepository)
+	mockRepo.On("GetBookingByID", mock.Anything, "booking123").Return(confirmedBooking(checkIn, 2), nil)
+	mockRepo.On("HasOverlappingBooking", mock.Anything, "room123", mock.Anything, mock.Anything, "booking123").Return(false, nil)
+	mockRepo.On("ModifyBooking", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(domain.ErrRoomNotAvailable)
+
+	mockPayment := &MockPaymentService{
+		ChargePaymentFunc: func(ctx context.Context, bookingID, chargeID string, amount money.Money) (string, error) {
+			t.Fatal("unexpected charge")
+			return "", nil
+		},
+	}
+
+	uc := NewBookingUseCase(mockRepo, nil, nil, &MockHotelClient{GetQuoteFunc: flatQuote(money.New(500000, "RUB"))}, mockPayment, nil, nil, nil, 0, 0, nil)
+
+	_, err := uc.ModifyBooking(context.Background(), "booking123", domain.BookingChange{CheckOutDate: checkIn.AddDate(0, 0, 3)})
+	assert.ErrorIs(t, err, domain.ErrRoomNotAvailable)
+	mockRepo.AssertNotCalled(t, "RevertModification", mock.Anything, mock.Anything)
+}
+
+func TestModifyBooking_UnsettledDifferenceRevertsTheChange(t *testing.T) {
+	checkIn := time.Date(2030, 12, 20, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name      string
+		quote     money.Money
+		change    domain.BookingChange
+		chargeErr error
+		refundErr error
+		want      error
+	}{
+		{name: "charge failed", quote: money.New(500000, "RUB"), change: domain.BookingChange{CheckOutDate: checkIn.AddDate(0, 0, 3)}, chargeErr: errors.New("payment service returned status 500"), want: domain.ErrPaymentFailed},
+		{name: "refund failed", quote: money.New(400000, "RUB"), change: domain.BookingChange{RoomID: "room456"}, refundErr: errors.New("payment service returned status 500"), want: domain.ErrPaymentFailed},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := new(MockBookingRepository)
+			mockRepo.On("GetBookingByID", mock.Anything, "booking123").Return(confirmedBooking(checkIn, 2), nil)
+			mockRepo.On("HasOverlappingBooking", mock.Anything, mock.Anything, mock.Anything, mock.Anything, "booking123").Return(false, nil)
+			var stored *domain.BookingModification
+			mockRepo.On("ModifyBooking", mock.Anything, mock.Anything, mock.Anything, mock.Anything).
+				Run(func(args mock.Arguments) { stored = args.Get(2).(*domain.BookingModification) }).
+				Return(nil)
+			mockRepo.On("RevertModification", mock.Anything, mock.Anything).Return(nil)
+			mockPayment := &MockPaymentService{
+				ChargePaymentFunc: func(ctx context.Context, bookingID, chargeID string, amount money.Money) (string, error) {
+					return "", tt.chargeErr
+				},
+				RefundPaymentFunc: func(ctx context.Context, bookingID string, amount money.Money) error {
+					return tt.refundErr
+				},
+			}
+
+			uc := NewBookingUseCase(mockRepo, nil, nil, &MockHotelClient{GetQuoteFunc: flatQuote(tt.quote)}, mockPayment, nil, nil, nil, 0, 0, nil)
+
+			_, err := uc.ModifyBooking(context.Background(), "booking123", tt.change)
+			assert.ErrorIs(t, err, tt.want)
+			mockRepo.AssertCalled(t, "RevertModification", mock.Anything, stored)
+			mockRepo.AssertNotCalled(t, "UpdateModificationStatus", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+		})
+	}
+}
+
+func TestModifyBooking_UnrestorableStayFlagsTheModification(t *testing.T) {
+	checkIn := time.Date(2030, 12, 20, 0, 0, 0, 0, time.UTC)
+	mockRepo := new(MockBookingRepository)
+	mockRepo.On("GetBookingByID", mock.Anything, "booking123").Return(confirmedBooking(checkIn, 2), nil)
+	mockRepo.On("HasOverlappingBooking", mock.Anything, mock.Anything, mock.Anything, mock.Anything, "booking123").Return(false, nil)
+	mockRepo.On("ModifyBooking", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil)
+	mockRepo.On("RevertModification", mock.Anything, mock.Anything).Return(domain.ErrRoomNotAvailable)
+	mockRepo.On("UpdateModificationStatus", mock.Anything, mock.Anything, domain.ModificationPending, domain.ModificationUnsettled, (*domain.OutboxEvent)(nil)).Return(nil)
+	mockPayment := &MockPaymentService{
+		RefundPaymentFunc: func(ctx context.Context, bookingID string, amount money.Money) error {
+			return errors.New("payment service returned status 500")
+		},
+	}
+
+	uc := NewBookingUseCase(mockRepo, nil, nil, &MockHotelClient{GetQuoteFunc: flatQuote(money.New(400000, "RUB"))}, mockPayment, nil, nil, nil, 0, 0, nil)
+
+	_, err := uc.ModifyBooking(context.Background(), "booking123", domain.BookingChange{RoomID: "room456"})
+	assert.Error(t, err)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestModifyBooking_Rejected(t *testing.T) {
+	checkIn := time.Date(2030, 12, 20, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name          string
+		status        domain.BookingStatus
+		paymentStatus domain.PaymentStatus
+		change        domain.BookingChange
+		overlapping   bool
+		want          error
+	}{
+		{name: "not confirmed", status: domain.StatusCheckedIn, change: domain.BookingChange{RoomID: "room456"}, want: domain.ErrBookingNotModifiable},
+		{name: "nothing changes", status: domain.StatusConfirmed, change: domain.BookingChange{RoomID: "room123"}, want: domain.ErrEmptyBookingChange},
+		{name: "invalid dates", status: domain.StatusConfirmed, change: domain.BookingChange{CheckOutDate: checkIn}, want: domain.ErrInvalidDates},
+		{name: "room taken", status: domain.StatusConfirmed, change: domain.BookingChange{RoomID: "room456"}, overlapping: true, want: domain.ErrRoomNotAvailable},
+		{name: "payment not captured", status: domain.StatusConfirmed, paymentStatus: domain.PaymentPending, change: domain.BookingChange{CheckOutDate: checkIn.AddDate(0, 0, 3)}, want: domain.ErrPaymentFailed},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			original := confirmedBooking(checkIn, 2)
+			original.Status = tt.status
+			if tt.paymentStatus != "" {
+				original.PaymentStatus = tt.paymentStatus
+			}
+
+			mockRepo := new(MockBookingRepository)
+			mockRepo.On("GetBookingByID", mock.Anything, "booking123").Return(original, nil)
+			mockRepo.On("HasOverlappingBooking", mock.Anything, mock.Anything, mock.Anything, mock.Anything, "booking123").Return(tt.overlapping, nil).Maybe()
+
+			uc := NewBookingUseCase(mockRepo, nil, nil, &MockHotelClient{GetQuoteFunc: flatQuote(money.New(500000, "RUB"))}, &MockPaymentService{}, nil, nil, nil, 0, 0, nil)
+
+			_, err := uc.ModifyBooking(context.Background(), "booking123", tt.change)
+			assert.ErrorIs(t, err, tt.want)
+			mockRepo.AssertNotCalled(t, "ModifyBooking", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+		})
+	}
+}
+
+func pendingCharge(checkIn time.Time) *domain.BookingModification {
+	charge := money.New(500000, "RUB")
+	return &domain.BookingModification{
+		ID:               "modification123",
+		BookingID:        "booking123",
+		Previous:         *confirmedBooking(checkIn, 2),
+		AdditionalCharge: &charge,
+		PaymentID:        "payment456",
+		Status:           domain.ModificationPending,
+	}
+}
+
+func TestUpdatePaymentStatus_PaidExtraChargeSettlesTheModification(t *testing.T) {
+	checkIn := time.Date(2030, 12, 20, 0, 0, 0, 0, time.UTC)
+	modified := confirmedBooking(checkIn, 3)
+
+	mockRepo := new(MockBookingRepository)
+	mockRepo.On("GetPendingChargeModification", mock.Anything, "booking123", "payment456").Return(pendingCharge(checkIn), nil)
+	mockRepo.On("GetBookingByID", mock.Anything, "booking123").Return(modified, nil)
+	var outboxEvent *domain.OutboxEvent
+	mockRepo.On("UpdateModificationStatus", mock.Anything, "modification123", domain.ModificationPending, domain.ModificationSettled, mock.Anything).
+		Run(func(args mock.Arguments) { outboxEvent = args.Get(4).(*domain.OutboxEvent) }).
+		Return(nil)
+
+	uc := NewBookingUseCase(mockRepo, nil, nil, nil, nil, nil, nil, nil, 0, 0, nil)
+
+	err := uc.UpdatePaymentStatus(context.Background(), "booking123", "payment456", "paid")
+	require.NoError(t, err)
+	mockRepo.AssertNotCalled(t, "UpdatePaymentStatus", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+
+	require.NotNil(t, outboxEvent)
+	var publishedEvent domain.BookingEvent
+	require.NoError(t, json.Unmarshal(outboxEvent.Payload, &publishedEvent))
+	assert.Equal(t, domain.EventBookingModified, publishedEvent.EventType)
+	assert.Equal(t, modified.CheckOutDate, publishedEvent.CheckOutDate)
+	require.NotNil(t, publishedEvent.AdditionalCharge)
+	assert.Equal(t, money.New(500000, "RUB"), *publishedEvent.AdditionalCharge)
+}
+
+func TestUpdatePaymentStatus_FailedExtraChargeRevertsTheModification(t *testing.T) {
+	checkIn := time.Date(2030, 12, 20, 0, 0, 0, 0, time.UTC)
+	modification := pendingCharge(checkIn)
+
+	t.Run("previous stay restored", func(t *testing.T) {
+		mockRepo := new(MockBookingRepository)
+		mockRepo.On("GetPendingChargeModification", mock.Anything, "booking123", "payment456").Return(modification, nil)
+		mockRepo.On("RevertModification", mock.Anything, modification).Return(nil)
+
+		uc := NewBookingUseCase(mockRepo, nil, nil, nil, nil, nil, nil, nil, 0, 0, nil)
+
+		err := uc.UpdatePaymentStatus(context.Background(), "booking123", "payment456", "failed")
+		require.NoError(t, err)
+		mockRepo.AssertExpectations(t)
+		mockRepo.AssertNotCalled(t, "UpdatePaymentStatus", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("previous room taken", func(t *testing.T) {
+		mockRepo := new(MockBookingRepository)
+		mockRepo.On("GetPendingChargeModification", mock.Anything, "booking123", "payment456").Return(modification, nil)
+		mockRepo.On("RevertModification", mock.Anything, modification).Return(domain.ErrRoomNotAvailable)
+		mockRepo.On("UpdateModificationStatus", mock.Anything, "modification123", domain.ModificationPending, domain.ModificationUnsettled, (*domain.OutboxEvent)(nil)).Return(nil)
+
+		uc := NewBookingUseCase(mockRepo, nil, nil, nil, nil, nil, nil, nil, 0, 0, nil)
+
+		err := uc.UpdatePaymentStatus(context.Background(), "booking123", "payment456", "failed")
+		require.NoError(t, err)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("already reverted", func(t *testing.T) {
+		mockRepo := new(MockBookingRepository)
+		mockRepo.On("GetPendingChargeModification", mock.Anything, "booking123", "payment456").Return(modification, nil)
+		mockRepo.On("RevertModification", mock.Anything, modification).Return(domain.ErrStatusChanged)
+
+		uc := NewBookingUseCase(mockRepo, nil, nil, nil, nil, nil, nil, nil, 0, 0, nil)
+
+		err := uc.UpdatePaymentStatus(context.Background(), "booking123", "payment456", "failed")
+		require.NoError(t, err)
+	})
+}
+
+func TestUpdatePaymentStatus_PaymentWithoutPendingChargeUpdatesTheBooking(t *testing.T) {
+	booking := &domain.Booking{ID: "booking123", Status: domain.StatusAwaitingPayment, PaymentStatus: domain.PaymentPending}
+
+	mockRepo := new(MockBookingRepository)
+	mockRepo.On("GetPendingChargeModification", mock.Anything, "booking123", "payment123").Return(nil, sql.ErrNoRows)
+	mockRepo.On("GetBookingByID", mock.Anything, "booking123").Return(booking, nil)
+	mockRepo.On("UpdatePaymentStatus", mock.Anything, "booking123", domain.PaymentPending, domain.PaymentPaid).Return(nil)
+	mockRepo.On("UpdateBookingStatus", mock.Anything, "booking123", domain.StatusAwaitingPayment, domain.StatusConfirmed, (*domain.OutboxEvent)(nil)).Return(nil)
+
+	uc := NewBookingUseCase(mockRepo, nil, nil, nil, nil, nil, nil, nil, 0, 0, nil)
+
+	err := uc.UpdatePaymentStatus(context.Background(), "booking123", "payment123", "paid")
+	require.NoError(t, err)
+	mockRepo.AssertExpectations(t)
+}
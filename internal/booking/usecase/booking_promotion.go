@@ -0,0 +1,73 @@
+package usecase
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"hotel-booking-system/internal/booking/domain"
+	"hotel-booking-system/pkg/hotelclient"
+	"hotel-booking-system/pkg/money"
+
+	"github.com/google/uuid"
+)
+
+func (uc *BookingUseCase) CreatePromotion(ctx context.Context, promotion *domain.Promotion) error {
+	promotion.Code = domain.NormalizePromoCode(promotion.Code)
+	if err := promotion.Validate(); err != nil {
+		return err
+	}
+	promotion.ID = uuid.New().String()
+	return uc.promotions.CreatePromotion(ctx, promotion)
+}
+
+func (uc *BookingUseCase) GetPromotion(ctx context.Context, code string) (*domain.Promotion, error) {
+	return uc.promotions.GetPromotionByCode(ctx, domain.NormalizePromoCode(code))
+}
+
+func (uc *BookingUseCase) GetPromotions(ctx context.Context) ([]domain.Promotion, error) {
+	return uc.promotions.GetPromotions(ctx)
+}
+
+// promotionDiscount is the discount line of the booking's promo code, if it
+// applies to a booking made at the given time, or nil without a code. The
+// code's usage limits are checked when the booking is stored.
+func (uc *BookingUseCase) promotionDiscount(ctx context.Context, booking *domain.Booking, quote *hotelclient.Quote, at time.Time) (*domain.PriceItem, error) {
+	booking.PromoCode = domain.NormalizePromoCode(booking.PromoCode)
+	if booking.PromoCode == "" {
+		return nil, nil
+	}
+	if uc.promotions == nil {
+		return nil, domain.ErrPromoCodeInvalid
+	}
+
+	promotion, err := uc.promotions.GetPromotionByCode(ctx, booking.PromoCode)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, domain.ErrPromoCodeInvalid
+	}
+	if err != nil {
+		return nil, err
+	}
+	if !promotion.AppliesTo(booking.HotelID, quote.RoomType, at) {
+		return nil, domain.ErrPromoCodeInvalid
+	}
+
+	nights := make([]money.Money, 0, len(quote.Nights))
+	for _, night := range quote.Nights {
+		nights = append(nights, night.Price)
+	}
+	discount, err := promotion.Discount(nights)
+	if err != nil {
+		return nil, err
+	}
+	if !discount.IsPositive() {
+		return nil, domain.ErrPromoCodeInvalid
+	}
+
+	return &domain.PriceItem{
+		Kind:   domain.PriceItemDiscount,
+		Name:   promotion.Code,
+		Amount: discount.Neg(),
+	}, nil
+}
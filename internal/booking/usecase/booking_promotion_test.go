@@ -0,0 +1,173 @@
+package usecase
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"hotel-booking-system/internal/booking/domain"
+	"hotel-booking-system/pkg/hotelclient"
+	"hotel-booking-system/pkg/money"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockPromotionRepository struct {
+	mock.Mock
+}
+
+func (m *MockPromotionRepository) CreatePromotion(ctx context.Context, promotion *domain.Promotion) error {
+	args := m.Called(ctx, promotion)
+	return args.Error(0)
+}
+
+func (m *MockPromotionRepository) GetPromotionByCode(ctx context.Context, code string) (*domain.Promotion, error) {
+	args := m.Called(ctx, code)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.Promotion), args.Error(1)
+}
+
+func (m *MockPromotionRepository) GetPromotions(ctx context.Context) ([]domain.Promotion, error) {
+	args := m.Called(ctx)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]domain.Promotion), args.Error(1)
+}
+
+func TestCreatePromotion(t *testing.T) {
+	t.Run("normalizes the code", func(t *testing.T) {
+		mockPromotions := new(MockPromotionRepository)
+		uc := NewBookingUseCase(nil, nil, nil, nil, nil, nil, mockPromotions, nil, 0, 0, nil)
+
+		mockPromotions.On("CreatePromotion", mock.Anything, mock.MatchedBy(func(p *domain.Promotion) bool {
+			return p.Code == "SUMMER10" && p.ID != ""
+		})).Return(nil)
+
+		err := uc.CreatePromotion(context.Background(), &domain.Promotion{Code: " summer10 ", Type: domain.DiscountPercentage, BasisPoints: 1000})
+		assert.NoError(t, err)
+		mockPromotions.AssertExpectations(t)
+	})
+
+	t.Run("invalid promotion", func(t *testing.T) {
+		mockPromotions := new(MockPromotionRepository)
+		uc := NewBookingUseCase(nil, nil, nil, nil, nil, nil, mockPromotions, nil, 0, 0, nil)
+
+		err := uc.CreatePromotion(context.Background(), &domain.Promotion{Code: "SUMMER10", Type: domain.DiscountPercentage})
+		assert.ErrorIs(t, err, domain.ErrInvalidPromotion)
+		mockPromotions.AssertNotCalled(t, "CreatePromotion", mock.Anything, mock.Anything)
+	})
+}
+
+func TestCreateBooking_AppliesPromoCode(t *testing.T) {
+	checkIn := time.Date(2030, 12, 20, 14, 0, 0, 0, time.UTC)
+	checkOut := time.Date(2030, 12, 23, 12, 0, 0, 0, time.UTC)
+	quote := func(ctx context.Context, hotelID, roomID string, checkIn, checkOut time.Time, adults, children int) (*hotelclient.Quote, error) {
+		return &hotelclient.Quote{
+			RoomType: "Deluxe",
+			Nights: []hotelclient.NightlyRate{
+				{Date: time.Date(2030, 12, 20, 0, 0, 0, 0, time.UTC), Price: money.New(700000, "RUB")},
+				{Date: time.Date(2030, 12, 21, 0, 0, 0, 0, time.UTC), Price: money.New(700000, "RUB")},
+				{Date: time.Date(2030, 12, 22, 0, 0, 0, 0, time.UTC), Price: money.New(500000, "RUB")},
+			},
+			Fees: []hotelclient.Fee{{Name: "Уборка", Kind: "fee", Amount: money.New(100000, "RUB")}},
+		}, nil
+	}
+	newBooking := func() *domain.Booking {
+		return &domain.Booking{
+			UserID:       "user123",
+			HotelID:      "hotel123",
+			RoomID:       "room123",
+			CheckInDate:  checkIn,
+			CheckOutDate: checkOut,
+			PromoCode:    "stay3pay2",
+		}
+	}
+
+	t.Run("discounts the accommodation", func(t *testing.T) {
+		mockRepo := new(MockBookingRepository)
+		mockSagas := new(MockSagaRepository)
+		mockPromotions := new(MockPromotionRepository)
+		uc := NewBookingUseCase(mockRepo, mockSagas, nil, &MockHotelClient{GetQuoteFunc: quote}, nil, nil, mockPromotions, nil, 0, 0, nil)
+
+		mockPromotions.On("GetPromotionByCode", mock.Anything, "STAY3PAY2").Return(&domain.Promotion{
+			Code: "STAY3PAY2", Type: domain.DiscountFreeNights, StayNights: 3, PayNights: 2, RoomTypes: []string{"Deluxe"},
+		}, nil)
+		mockRepo.On("HasOverlappingBooking", mock.Anything, "room123", checkIn, checkOut, "").Return(false, nil)
+		expectReservation(mockRepo, mockSagas)
+		mockRepo.On("UpdateBookingStatus", mock.Anything, mock.Anything, domain.StatusPending, domain.StatusConfirmed, mock.Anything).Return(nil)
+
+		booking := newBooking()
+		err := uc.CreateBooking(context.Background(), booking)
+		assert.NoError(t, err)
+		assert.Equal(t, "STAY3PAY2", booking.PromoCode)
+		assert.Equal(t, "Deluxe", booking.RoomType)
+		assert.Equal(t, domain.PriceItem{Kind: domain.PriceItemDiscount, Name: "STAY3PAY2", Amount: money.New(-500000, "RUB")},
+			booking.PriceBreakdown[1])
+		assert.Equal(t, money.New(1500000, "RUB"), booking.TotalPrice)
+		mockRepo.AssertCalled(t, "CreateBooking", mock.Anything, mock.MatchedBy(func(b *domain.Booking) bool {
+			return b.PromoCode == "STAY3PAY2"
+		}))
+	})
+
+	t.Run("charges percentage fees on the discounted accommodation", func(t *testing.T) {
+		mockRepo := new(MockBookingRepository)
+		mockSagas := new(MockSagaRepository)
+		mockPromotions := new(MockPromotionRepository)
+		withTax := func(ctx context.Context, hotelID, roomID string, checkIn, checkOut time.Time, adults, children int) (*hotelclient.Quote, error) {
+			q, _ := quote(ctx, hotelID, roomID, checkIn, checkOut, adults, children)
+			q.Fees = append(q.Fees, hotelclient.Fee{Name: "НДС", Kind: "tax", BasisPoints: 2000, Amount: money.New(380000, "RUB")})
+			return q, nil
+		}
+		uc := NewBookingUseCase(mockRepo, mockSagas, nil, &MockHotelClient{GetQuoteFunc: withTax}, nil, nil, mockPromotions, nil, 0, 0, nil)
+
+		mockPromotions.On("GetPromotionByCode", mock.Anything, "STAY3PAY2").Return(&domain.Promotion{
+			Code: "STAY3PAY2", Type: domain.DiscountPercentage, BasisPoints: 1000,
+		}, nil)
+		mockRepo.On("HasOverlappingBooking", mock.Anything, "room123", checkIn, checkOut, "").Return(false, nil)
+		expectReservation(mockRepo, mockSagas)
+		mockRepo.On("UpdateBookingStatus", mock.Anything, mock.Anything, domain.StatusPending, domain.StatusConfirmed, mock.Anything).Return(nil)
+
+		booking := newBooking()
+		err := uc.CreateBooking(context.Background(), booking)
+		assert.NoError(t, err)
+		assert.Equal(t, []domain.PriceItem{
+			{Kind: domain.PriceItemAccommodation, Amount: money.New(1900000, "RUB")},
+			{Kind: domain.PriceItemDiscount, Name: "STAY3PAY2", Amount: money.New(-190000, "RUB")},
+			{Kind: domain.PriceItemFee, Name: "Уборка", Amount: money.New(100000, "RUB")},
+			{Kind: domain.PriceItemTax, Name: "НДС", Amount: money.New(342000, "RUB")},
+		}, booking.PriceBreakdown)
+		assert.Equal(t, money.New(2152000, "RUB"), booking.TotalPrice)
+	})
+
+	t.Run("unknown code", func(t *testing.T) {
+		mockRepo := new(MockBookingRepository)
+		mockPromotions := new(MockPromotionRepository)
+		uc := NewBookingUseCase(mockRepo, nil, nil, &MockHotelClient{GetQuoteFunc: quote}, nil, nil, mockPromotions, nil, 0, 0, nil)
+
+		mockPromotions.On("GetPromotionByCode", mock.Anything, "STAY3PAY2").Return(nil, sql.ErrNoRows)
+		mockRepo.On("HasOverlappingBooking", mock.Anything, "room123", checkIn, checkOut, "").Return(false, nil)
+
+		err := uc.CreateBooking(context.Background(), newBooking())
+		assert.ErrorIs(t, err, domain.ErrPromoCodeInvalid)
+		mockRepo.AssertNotCalled(t, "CreateBooking", mock.Anything, mock.Anything)
+	})
+
+	t.Run("other room type", func(t *testing.T) {
+		mockRepo := new(MockBookingRepository)
+		mockPromotions := new(MockPromotionRepository)
+		uc := NewBookingUseCase(mockRepo, nil, nil, &MockHotelClient{GetQuoteFunc: quote}, nil, nil, mockPromotions, nil, 0, 0, nil)
+
+		mockPromotions.On("GetPromotionByCode", mock.Anything, "STAY3PAY2").Return(&domain.Promotion{
+			Code: "STAY3PAY2", Type: domain.DiscountPercentage, BasisPoints: 1000, RoomTypes: []string{"Standard"},
+		}, nil)
+		mockRepo.On("HasOverlappingBooking", mock.Anything, "room123", checkIn, checkOut, "").Return(false, nil)
+
+		err := uc.CreateBooking(context.Background(), newBooking())
+		assert.ErrorIs(t, err, domain.ErrPromoCodeInvalid)
+	})
+}
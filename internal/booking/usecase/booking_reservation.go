@@ -0,0 +1,240 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"hotel-booking-system/internal/booking/domain"
+
+	"github.com/google/uuid"
+)
+
+// CreateReservation books several rooms of one hotel at once. Each booking is
+// priced like a single booking, all of them at one exchange rate; promo codes
+// do not apply to reservations.
+func (uc *BookingUseCase) CreateReservation(ctx context.Context, reservation *domain.Reservation) error {
+	if len(reservation.Bookings) == 0 {
+		return domain.ErrEmptyReservation
+	}
+
+	reservation.ID = uuid.New().String()
+	bookings := make([]domain.Booking, 0, len(reservation.Bookings))
+	for i, line := range reservation.Bookings {
+		if line.PromoCode != "" {
+			return domain.ErrPromoCodeInvalid
+		}
+		booking := domain.Booking{
+			ID:              uuid.New().String(),
+			UserID:          reservation.UserID,
+			HotelID:         reservation.HotelID,
+			RoomID:          line.RoomID,
+			ReservationID:   reservation.ID,
+			GuestName:       line.GuestName,
+			Adults:          line.Adults,
+			Children:        line.Children,
+			CheckInDate:     line.CheckInDate,
+			CheckOutDate:    line.CheckOutDate,
+			DisplayCurrency: reservation.DisplayCurrency,
+			Status:          domain.StatusPending,
+			PaymentStatus:   domain.PaymentPending,
+		}
+		if !booking.CheckInDate.Before(booking.CheckOutDate) {
+			return domain.ErrInvalidDates
+		}
+		if err := validateGuests(&booking); err != nil {
+			return err
+		}
+
+		overlapping, err := uc.repo.HasOverlappingBooking(ctx, booking.RoomID, booking.CheckInDate, booking.CheckOutDate, "")
+		if err != nil {
+			return err
+		}
+		if overlapping {
+			return domain.ErrRoomNotAvailable
+		}
+
+		if err := uc.priceStay(ctx, &booking, time.Now()); err != nil {
+			return err
+		}
+		if i == 0 {
+			if err := uc.convertPrice(ctx, &booking); err != nil {
+				return err
+			}
+			reservation.DisplayCurrency = booking.DisplayCurrency
+		} else {
+			booking.ExchangeRate = bookings[0].ExchangeRate
+			if booking.DisplayPrice, err = booking.ChargeAmount(booking.TotalPrice); err != nil {
+				return err
+			}
+		}
+
+		if reservation.TotalPrice, err = reservation.TotalPrice.Add(booking.TotalPrice); err != nil {
+			return err
+		}
+		if reservation.DisplayPrice, err = reservation.DisplayPrice.Add(booking.DisplayPrice); err != nil {
+			return err
+		}
+		bookings = append(bookings, booking)
+	}
+	reservation.Bookings = bookings
+
+	return uc.startReservationSaga(ctx, reservation)
+}
+
+func (uc *BookingUseCase) GetReservation(ctx context.Context, id string) (*domain.Reservation, error) {
+	return uc.repo.GetReservationByID(ctx, id)
+}
+
+// updateReservationPaymentStatus applies the status of the reservation's
+// payment to each of its bookings. A refund of the payment is made for one of
+// the bookings, so its status is not copied onto the others: they stay paid
+// and are refunded from what remains captured when they are cancelled.
+func (uc *BookingUseCase) updateReservationPaymentStatus(ctx context.Context, id string, paymentStatus domain.PaymentStatus) error {
+	if paymentStatus == domain.PaymentPartiallyRefunded || paymentStatus == domain.PaymentRefunded {
+		return nil
+	}
+	reservation, err := uc.repo.GetReservationByID(ctx, id)
+	if err != nil {
+		return err
+	}
+	for i := range reservation.Bookings {
+		if err := uc.updatePaymentStatus(ctx, &reservation.Bookings[i], paymentStatus); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// The create-reservation saga runs the steps of the create-booking saga for
+// all the bookings of the reservation at once, with one payment for the
+// reservation and one reservation.created event instead of one per booking.
+func (uc *BookingUseCase) startReservationSaga(ctx context.Context, reservation *domain.Reservation) error {
+	saga := &domain.BookingSaga{
+		BookingID: reservation.ID,
+		Step:      domain.SagaStepReserve,
+		Status:    domain.SagaRunning,
+	}
+	if err := uc.sagas.CreateSaga(ctx, saga); err != nil {
+		return err
+	}
+
+	if err := uc.repo.CreateReservation(ctx, reservation); err != nil {
+		saga.Status = domain.SagaCompensated
+		saga.LastError = err.Error()
+		uc.saveSaga(ctx, saga)
+		return err
+	}
+
+	return uc.runReservationSaga(ctx, saga, reservation)
+}
+
+func (uc *BookingUseCase) runReservationSaga(ctx context.Context, saga *domain.BookingSaga, reservation *domain.Reservation) error {
+	for {
+		switch saga.Step {
+		case domain.SagaStepReserve:
+			if err := uc.advanceSaga(ctx, saga, domain.SagaStepPay); err != nil {
+				return err
+			}
+		case domain.SagaStepPay:
+			if uc.paymentClient != nil {
+				if err := uc.paymentClient.CreatePayment(ctx, reservation.ID, reservation.DisplayPrice); err != nil {
+					return uc.compensateReservation(ctx, saga, reservation, fmt.Errorf("%w: %v", domain.ErrPaymentFailed, err))
+				}
+			}
+			if err := uc.advanceSaga(ctx, saga, domain.SagaStepPublish); err != nil {
+				return err
+			}
+		case domain.SagaStepPublish:
+			return uc.publishReservation(ctx, saga, reservation)
+		default:
+			return fmt.Errorf("unknown saga step %q", saga.Step)
+		}
+	}
+}
+
+// publishReservation moves the pending bookings on like publishBooking does.
+// The event is stored with the last of them, so a replay after a partial
+// publish still stores it exactly once.
+func (uc *BookingUseCase) publishReservation(ctx context.Context, saga *domain.BookingSaga, reservation *domain.Reservation) error {
+	current, err := uc.repo.GetReservationByID(ctx, reservation.ID)
+	if err != nil {
+		return err
+	}
+	*reservation = *current
+
+	var pending []*domain.Booking
+	for i := range reservation.Bookings {
+		if reservation.Bookings[i].Status == domain.StatusPending {
+			pending = append(pending, &reservation.Bookings[i])
+		}
+	}
+
+	for i, booking := range pending {
+		target := domain.StatusConfirmed
+		if uc.paymentClient != nil {
+			switch booking.PaymentStatus {
+			case domain.PaymentFailed:
+				return uc.compensateReservation(ctx, saga, reservation, domain.ErrPaymentFailed)
+			case domain.PaymentPending:
+				target = domain.StatusAwaitingPayment
+			}
+		}
+
+		var event *domain.OutboxEvent
+		if i == len(pending)-1 {
+			if event, err = uc.newOutboxEvent(domain.EventReservationCreated, reservation.ID, newReservationEvent(reservation, target)); err != nil {
+				return err
+			}
+		}
+		if err := uc.transition(ctx, booking, target, event); err != nil {
+			saga.LastError = err.Error()
+			uc.saveSaga(ctx, saga)
+			return err
+		}
+	}
+
+	saga.Status = domain.SagaCompleted
+	saga.LastError = ""
+	uc.saveSaga(ctx, saga)
+	return nil
+}
+
+func (uc *BookingUseCase) compensateReservation(ctx context.Context, saga *domain.BookingSaga, reservation *domain.Reservation, cause error) error {
+	for i := range reservation.Bookings {
+		booking := &reservation.Bookings[i]
+		if !booking.Status.CanTransitionTo(domain.StatusCancelled) {
+			continue
+		}
+		if err := uc.transition(ctx, booking, domain.StatusCancelled, nil); err != nil {
+			saga.LastError = err.Error()
+			uc.saveSaga(ctx, saga)
+			return err
+		}
+	}
+
+	saga.Status = domain.SagaCompensated
+	saga.LastError = cause.Error()
+	uc.saveSaga(ctx, saga)
+	return cause
+}
+
+// newReservationEvent describes a reservation whose bookings all moved to
+// status.
+func newReservationEvent(reservation *domain.Reservation, status domain.BookingStatus) domain.ReservationEvent {
+	event := domain.ReservationEvent{
+		ReservationID: reservation.ID,
+		UserID:        reservation.UserID,
+		HotelID:       reservation.HotelID,
+		TotalPrice:    reservation.TotalPrice,
+		DisplayPrice:  reservation.DisplayPrice,
+		EventType:     domain.EventReservationCreated,
+		Timestamp:     time.Now(),
+	}
+	for i := range reservation.Bookings {
+		bookingEvent := newBookingEvent(&reservation.Bookings[i], domain.EventReservationCreated)
+		bookingEvent.Status = status
+		event.Bookings = append(event.Bookings, bookingEvent)
+	}
+	return event
+}
@@ -0,0 +1,206 @@
+package usecase
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"hotel-booking-system/internal/booking/domain"
+	"hotel-booking-system/pkg/money"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestReservation(checkIn time.Time) *domain.Reservation {
+	return &domain.Reservation{
+		UserID:  "user123",
+		HotelID: "hotel123",
+		Bookings: []domain.Booking{
+			{RoomID: "room123", GuestName: "Иван Петров", CheckInDate: checkIn, CheckOutDate: checkIn.AddDate(0, 0, 2)},
+			{RoomID: "room456", GuestName: "Мария Петрова", CheckInDate: checkIn, CheckOutDate: checkIn.AddDate(0, 0, 3)},
+		},
+	}
+}
+
+func TestCreateReservation_OnePaymentAndOneEvent(t *testing.T) {
+	checkIn := time.Date(2030, 12, 20, 0, 0, 0, 0, time.UTC)
+	mockRepo := new(MockBookingRepository)
+	mockSagas := new(MockSagaRepository)
+
+	mockRepo.On("HasOverlappingBooking", mock.Anything, mock.Anything, mock.Anything, mock.Anything, "").Return(false, nil)
+	stored := &domain.Reservation{}
+	mockRepo.On("CreateReservation", mock.Anything, mock.Anything).
+		Run(func(args mock.Arguments) { *stored = *args.Get(1).(*domain.Reservation) }).
+		Return(nil)
+	mockRepo.On("GetReservationByID", mock.Anything, mock.Anything).Return(stored, nil)
+	var events []*domain.OutboxEvent
+	mockRepo.On("UpdateBookingStatus", mock.Anything, mock.Anything, domain.StatusPending, domain.StatusAwaitingPayment, mock.Anything).
+		Run(func(args mock.Arguments) { events = append(events, args.Get(4).(*domain.OutboxEvent)) }).
+		Return(nil)
+	mockSagas.On("CreateSaga", mock.Anything, mock.Anything).Return(nil)
+	mockSagas.On("UpdateSaga", mock.Anything, mock.Anything).Return(nil)
+
+	var payments []string
+	var paid money.Money
+	mockPayment := &MockPaymentService{
+		CreatePaymentFunc: func(ctx context.Context, bookingID string, amount money.Money) error {
+			payments = append(payments, bookingID)
+			paid = amount
+			return nil
+		},
+	}
+
+	uc := NewBookingUseCase(mockRepo, mockSagas, nil, &MockHotelClient{GetQuoteFunc: flatQuote(money.New(500000, "RUB"))}, mockPayment, nil, nil, nil, 0, 0, nil)
+
+	reservation := newTestReservation(checkIn)
+	err := uc.CreateReservation(context.Background(), reservation)
+	require.NoError(t, err)
+	require.NotEmpty(t, reservation.ID)
+	assert.Equal(t, money.New(2500000, "RUB"), reservation.TotalPrice)
+	assert.Equal(t, []string{reservation.ID}, payments)
+	assert.Equal(t, money.New(2500000, "RUB"), paid)
+
+	require.Len(t, reservation.Bookings, 2)
+	for _, booking := range reservation.Bookings {
+		assert.Equal(t, reservation.ID, booking.ReservationID)
+		assert.Equal(t, "user123", booking.UserID)
+		assert.Equal(t, domain.StatusAwaitingPayment, booking.Status)
+	}
+
+	require.Len(t, events, 2)
+	assert.Nil(t, events[0])
+	require.NotNil(t, events[1])
+	assert.Equal(t, domain.EventReservationCreated, events[1].Topic)
+	var publishedEvent domain.ReservationEvent
+	require.NoError(t, json.Unmarshal(events[1].Payload, &publishedEvent))
+	assert.Equal(t, reservation.ID, publishedEvent.ReservationID)
+	assert.Len(t, publishedEvent.Bookings, 2)
+	assert.Equal(t, "Мария Петрова", publishedEvent.Bookings[1].GuestName)
+	assert.Equal(t, domain.StatusAwaitingPayment, publishedEvent.Bookings[1].Status)
+}
+
+func TestCreateReservation_Rejected(t *testing.T) {
+	checkIn := time.Date(2030, 12, 20, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name        string
+		reservation func() *domain.Reservation
+		overlapping bool
+		want        error
+	}{
+		{
+			name:        "no bookings",
+			reservation: func() *domain.Reservation { return &domain.Reservation{UserID: "user123", HotelID: "hotel123"} },
+			want:        domain.ErrEmptyReservation,
+		},
+		{
+			name: "invalid dates",
+			reservation: func() *domain.Reservation {
+				reservation := newTestReservation(checkIn)
+				reservation.Bookings[1].CheckOutDate = checkIn
+				return reservation
+			},
+			want: domain.ErrInvalidDates,
+		},
+		{
+			name:        "room taken",
+			reservation: func() *domain.Reservation { return newTestReservation(checkIn) },
+			overlapping: true,
+			want:        domain.ErrRoomNotAvailable,
+		},
+		{
+			name: "promo code",
+			reservation: func() *domain.Reservation {
+				reservation := newTestReservation(checkIn)
+				reservation.Bookings[0].PromoCode = "WINTER"
+				return reservation
+			},
+			want: domain.ErrPromoCodeInvalid,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := new(MockBookingRepository)
+			mockRepo.On("HasOverlappingBooking", mock.Anything, "room123", mock.Anything, mock.Anything, "").Return(false, nil).Maybe()
+			mockRepo.On("HasOverlappingBooking", mock.Anything, "room456", mock.Anything, mock.Anything, "").Return(tt.overlapping, nil).Maybe()
+
+			uc := NewBookingUseCase(mockRepo, nil, nil, &MockHotelClient{GetQuoteFunc: flatQuote(money.New(500000, "RUB"))}, nil, nil, nil, nil, 0, 0, nil)
+
+			err := uc.CreateReservation(context.Background(), tt.reservation())
+			assert.ErrorIs(t, err, tt.want)
+			mockRepo.AssertNotCalled(t, "CreateReservation", mock.Anything, mock.Anything)
+		})
+	}
+}
+
+func TestUpdatePaymentStatus_Reservation(t *testing.T) {
+	mockRepo := new(MockBookingRepository)
+	mockRepo.On("GetBookingByID", mock.Anything, "reservation123").Return(nil, sql.ErrNoRows)
+	mockRepo.On("GetReservationByID", mock.Anything, "reservation123").Return(&domain.Reservation{
+		ID: "reservation123",
+		Bookings: []domain.Booking{
+			{ID: "booking1", ReservationID: "reservation123", Status: domain.StatusAwaitingPayment, PaymentStatus: domain.PaymentPending},
+			{ID: "booking2", ReservationID: "reservation123", Status: domain.StatusAwaitingPayment, PaymentStatus: domain.PaymentPending},
+		},
+	}, nil)
+	mockRepo.On("UpdatePaymentStatus", mock.Anything, mock.Anything, domain.PaymentPending, domain.PaymentPaid).Return(nil)
+	mockRepo.On("UpdateBookingStatus", mock.Anything, mock.Anything, domain.StatusAwaitingPayment, domain.StatusConfirmed, mock.Anything).Return(nil)
+
+	uc := NewBookingUseCase(mockRepo, nil, nil, &MockHotelClient{}, nil, nil, nil, nil, 0, 0, nil)
+
+	err := uc.UpdatePaymentStatus(context.Background(), "reservation123", "", "paid")
+	require.NoError(t, err)
+	mockRepo.AssertCalled(t, "UpdateBookingStatus", mock.Anything, "booking1", domain.StatusAwaitingPayment, domain.StatusConfirmed, mock.Anything)
+	mockRepo.AssertCalled(t, "UpdateBookingStatus", mock.Anything, "booking2", domain.StatusAwaitingPayment, domain.StatusConfirmed, mock.Anything)
+}
+
+func TestUpdatePaymentStatus_ReservationRefundIsNotCopiedToBookings(t *testing.T) {
+	mockRepo := new(MockBookingRepository)
+	mockRepo.On("GetBookingByID", mock.Anything, "reservation123").Return(nil, sql.ErrNoRows)
+
+	uc := NewBookingUseCase(mockRepo, nil, nil, &MockHotelClient{}, nil, nil, nil, nil, 0, 0, nil)
+
+	err := uc.UpdatePaymentStatus(context.Background(), "reservation123", "", "partially_refunded")
+	require.NoError(t, err)
+	mockRepo.AssertNotCalled(t, "GetReservationByID", mock.Anything, mock.Anything)
+	mockRepo.AssertNotCalled(t, "UpdatePaymentStatus", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestCancelBooking_ReservationAuthorizationIsNotVoided(t *testing.T) {
+	mockRepo := new(MockBookingRepository)
+	mockRepo.On("GetBookingByID", mock.Anything, "booking1").Return(&domain.Booking{
+		ID:            "booking1",
+		ReservationID: "reservation123",
+		TotalPrice:    money.New(1000000, "RUB"),
+		Status:        domain.StatusConfirmed,
+		PaymentStatus: domain.PaymentAuthorized,
+	}, nil)
+	mockRepo.On("UpdateBookingStatus", mock.Anything, "booking1", domain.StatusConfirmed, domain.StatusCancelled, mock.Anything).Return(nil)
+
+	var calls []string
+	mockPayment := &MockPaymentService{
+		VoidPaymentFunc: func(ctx context.Context, bookingID string) error {
+			calls = append(calls, "void "+bookingID)
+			return nil
+		},
+		CapturePaymentFunc: func(ctx context.Context, bookingID string) error {
+			calls = append(calls, "capture "+bookingID)
+			return nil
+		},
+		RefundPaymentFunc: func(ctx context.Context, bookingID string, amount money.Money) error {
+			calls = append(calls, "refund "+bookingID+" "+amount.String())
+			return nil
+		},
+	}
+
+	uc := NewBookingUseCase(mockRepo, nil, nil, &MockHotelClient{}, mockPayment, nil, nil, nil, 0, 0, nil)
+
+	_, err := uc.CancelBooking(context.Background(), "booking1")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"capture reservation123", "refund reservation123 10000.00 RUB"}, calls)
+}
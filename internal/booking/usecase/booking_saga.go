@@ -0,0 +1,171 @@
+package usecase
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"hotel-booking-system/internal/booking/domain"
+	"hotel-booking-system/pkg/logger"
+)
+
+// The create-booking saga runs reserve -> pay -> publish. Reserve is undone by
+// cancelling the booking when payment cannot be initiated; publish only touches
+// the booking database, so it is retried on resume instead of compensated.
+func (uc *BookingUseCase) startSaga(ctx context.Context, booking *domain.Booking) error {
+	saga := &domain.BookingSaga{
+		BookingID: booking.ID,
+		Step:      domain.SagaStepReserve,
+		Status:    domain.SagaRunning,
+	}
+	if err := uc.sagas.CreateSaga(ctx, saga); err != nil {
+		return err
+	}
+
+	if err := uc.repo.CreateBooking(ctx, booking); err != nil {
+		saga.Status = domain.SagaCompensated
+		saga.LastError = err.Error()
+		uc.saveSaga(ctx, saga)
+		return err
+	}
+
+	return uc.runSaga(ctx, saga, booking)
+}
+
+func (uc *BookingUseCase) runSaga(ctx context.Context, saga *domain.BookingSaga, booking *domain.Booking) error {
+	for {
+		switch saga.Step {
+		case domain.SagaStepReserve:
+			if err := uc.advanceSaga(ctx, saga, domain.SagaStepPay); err != nil {
+				return err
+			}
+		case domain.SagaStepPay:
+			if uc.paymentClient != nil {
+				amount, err := booking.ChargeAmount(booking.TotalPrice)
+				if err != nil {
+					return uc.compensate(ctx, saga, booking, err)
+				}
+				if err := uc.paymentClient.CreatePayment(ctx, booking.ID, amount); err != nil {
+					return uc.compensate(ctx, saga, booking, fmt.Errorf("%w: %v", domain.ErrPaymentFailed, err))
+				}
+			}
+			if err := uc.advanceSaga(ctx, saga, domain.SagaStepPublish); err != nil {
+				return err
+			}
+		case domain.SagaStepPublish:
+			return uc.publishBooking(ctx, saga, booking)
+		default:
+			return fmt.Errorf("unknown saga step %q", saga.Step)
+		}
+	}
+}
+
+func (uc *BookingUseCase) publishBooking(ctx context.Context, saga *domain.BookingSaga, booking *domain.Booking) error {
+	current, err := uc.repo.GetBookingByID(ctx, booking.ID)
+	if err != nil {
+		return err
+	}
+	*booking = *current
+
+	if booking.Status == domain.StatusPending {
+		target := domain.StatusConfirmed
+		if uc.paymentClient != nil {
+			switch booking.PaymentStatus {
+			case domain.PaymentFailed:
+				return uc.compensate(ctx, saga, booking, domain.ErrPaymentFailed)
+			case domain.PaymentPending:
+				target = domain.StatusAwaitingPayment
+			}
+		}
+
+		bookingEvent := newBookingEvent(booking, domain.EventBookingCreated)
+		bookingEvent.Status = target
+		event, err := uc.newOutboxEvent(domain.EventBookingCreated, booking.ID, bookingEvent)
+		if err != nil {
+			return err
+		}
+		if err := uc.transition(ctx, booking, target, event); err != nil {
+			saga.LastError = err.Error()
+			uc.saveSaga(ctx, saga)
+			return err
+		}
+	}
+
+	saga.Status = domain.SagaCompleted
+	saga.LastError = ""
+	uc.saveSaga(ctx, saga)
+	return nil
+}
+
+func (uc *BookingUseCase) compensate(ctx context.Context, saga *domain.BookingSaga, booking *domain.Booking, cause error) error {
+	if booking.Status.CanTransitionTo(domain.StatusCancelled) {
+		if err := uc.transition(ctx, booking, domain.StatusCancelled, nil); err != nil {
+			saga.LastError = err.Error()
+			uc.saveSaga(ctx, saga)
+			return err
+		}
+	}
+
+	saga.Status = domain.SagaCompensated
+	saga.LastError = cause.Error()
+	uc.saveSaga(ctx, saga)
+	return cause
+}
+
+func (uc *BookingUseCase) advanceSaga(ctx context.Context, saga *domain.BookingSaga, step domain.SagaStep) error {
+	saga.Step = step
+	return uc.sagas.UpdateSaga(ctx, saga)
+}
+
+// saveSaga is best effort: a saga left in the running state is picked up again
+// by ResumeSagas, and every step tolerates being replayed.
+func (uc *BookingUseCase) saveSaga(ctx context.Context, saga *domain.BookingSaga) {
+	if err := uc.sagas.UpdateSaga(ctx, saga); err != nil {
+		logger.GetLogger().WithError(err).WithField("booking_id", saga.BookingID).Error("failed to save booking saga")
+	}
+}
+
+func (uc *BookingUseCase) ResumeSagas(ctx context.Context, staleAfter time.Duration) (int, error) {
+	sagas, err := uc.sagas.GetUnfinishedSagas(ctx, time.Now().Add(-staleAfter))
+	if err != nil {
+		return 0, err
+	}
+
+	resumed := 0
+	for i := range sagas {
+		saga := &sagas[i]
+		if err := uc.resumeSaga(ctx, saga); err != nil {
+			logger.GetLogger().WithError(err).WithField("booking_id", saga.BookingID).Error("failed to resume booking saga")
+			continue
+		}
+		resumed++
+	}
+	return resumed, nil
+}
+
+// resumeSaga tells the saga of a reservation from that of a booking by which
+// of the two its ID refers to.
+func (uc *BookingUseCase) resumeSaga(ctx context.Context, saga *domain.BookingSaga) error {
+	booking, err := uc.repo.GetBookingByID(ctx, saga.BookingID)
+	if errors.Is(err, sql.ErrNoRows) {
+		reservation, resErr := uc.repo.GetReservationByID(ctx, saga.BookingID)
+		if resErr == nil {
+			return uc.runReservationSaga(ctx, saga, reservation)
+		}
+		if !errors.Is(resErr, sql.ErrNoRows) {
+			return resErr
+		}
+		saga.Status = domain.SagaCompensated
+		saga.LastError = "booking was not reserved"
+		if saga.Step != domain.SagaStepReserve {
+			saga.LastError = "booking no longer exists"
+		}
+		return uc.sagas.UpdateSaga(ctx, saga)
+	}
+	if err != nil {
+		return err
+	}
+	return uc.runSaga(ctx, saga, booking)
+}
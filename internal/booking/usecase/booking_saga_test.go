@@ -0,0 +1,284 @@
+package usecase
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+	"time"
+
+	"hotel-booking-system/internal/booking/domain"
+	"hotel-booking-system/pkg/logger"
+	"hotel-booking-system/pkg/money"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func newSagaTestBooking() *domain.Booking {
+	return &domain.Booking{
+		UserID:       "user123",
+		HotelID:      "hotel123",
+		RoomID:       "room123",
+		CheckInDate:  time.Now().AddDate(0, 0, 1),
+		CheckOutDate: time.Now().AddDate(0, 0, 3),
+	}
+}
+
+func savedSagas(m *MockSagaRepository) []domain.BookingSaga {
+	var sagas []domain.BookingSaga
+	for _, call := range m.Calls {
+		if call.Method == "UpdateSaga" {
+			sagas = append(sagas, call.Arguments.Get(1).(domain.BookingSaga))
+		}
+	}
+	return sagas
+}
+
+func TestCreateBookingSaga_PaymentFailureCancelsBooking(t *testing.T) {
+	logger.Init("info")
+
+	mockRepo := new(MockBookingRepository)
+	mockSagas := new(MockSagaRepository)
+	mockClient := &MockHotelClient{
+		GetQuoteFunc: flatQuote(money.New(500000, "RUB")),
+	}
+	mockPayment := &MockPaymentService{
+		CreatePaymentFunc: func(ctx context.Context, bookingID string, amount money.Money) error {
+			return errors.New("payment service unavailable")
+		},
+	}
+
+	booking := newSagaTestBooking()
+	mockRepo.On("HasOverlappingBooking", mock.Anything, "room123", booking.CheckInDate, booking.CheckOutDate, "").Return(false, nil)
+	expectReservation(mockRepo, mockSagas)
+	mockRepo.On("UpdateBookingStatus", mock.Anything, mock.Anything, domain.StatusPending, domain.StatusCancelled, (*domain.OutboxEvent)(nil)).Return(nil)
+
+	uc := NewBookingUseCase(mockRepo, mockSagas, nil, mockClient, mockPayment, nil, nil, nil, 0, 0, nil)
+
+	err := uc.CreateBooking(context.Background(), booking)
+	assert.ErrorIs(t, err, domain.ErrPaymentFailed)
+	assert.Contains(t, err.Error(), "payment service unavailable")
+	assert.Equal(t, domain.StatusCancelled, booking.Status)
+
+	saved := savedSagas(mockSagas)
+	final := saved[len(saved)-1]
+	assert.Equal(t, domain.SagaStepPay, final.Step)
+	assert.Equal(t, domain.SagaCompensated, final.Status)
+	assert.Contains(t, final.LastError, "payment service unavailable")
+	mockRepo.AssertExpectations(t)
+}
+
+func TestCreateBookingSaga_RecordsEachStep(t *testing.T) {
+	mockRepo := new(MockBookingRepository)
+	mockSagas := new(MockSagaRepository)
+	mockClient := &MockHotelClient{
+		GetQuoteFunc: flatQuote(money.New(500000, "RUB")),
+	}
+	mockPayment := &MockPaymentService{}
+
+	booking := newSagaTestBooking()
+	mockRepo.On("HasOverlappingBooking", mock.Anything, "room123", booking.CheckInDate, booking.CheckOutDate, "").Return(false, nil)
+	expectReservation(mockRepo, mockSagas)
+	mockRepo.On("UpdateBookingStatus", mock.Anything, mock.Anything, domain.StatusPending, domain.StatusAwaitingPayment, mock.Anything).Return(nil)
+
+	uc := NewBookingUseCase(mockRepo, mockSagas, nil, mockClient, mockPayment, nil, nil, nil, 0, 0, nil)
+
+	err := uc.CreateBooking(context.Background(), booking)
+	assert.NoError(t, err)
+
+	mockSagas.AssertCalled(t, "CreateSaga", mock.Anything, mock.MatchedBy(func(saga domain.BookingSaga) bool {
+		return saga.Step == domain.SagaStepReserve && saga.Status == domain.SagaRunning
+	}))
+	saved := savedSagas(mockSagas)
+	assert.Len(t, saved, 3)
+	assert.Equal(t, domain.SagaStepPay, saved[0].Step)
+	assert.Equal(t, domain.SagaStepPublish, saved[1].Step)
+	assert.Equal(t, domain.SagaCompleted, saved[2].Status)
+}
+
+func TestCreateBookingSaga_PublishFailureKeepsSagaRunning(t *testing.T) {
+	logger.Init("info")
+
+	mockRepo := new(MockBookingRepository)
+	mockSagas := new(MockSagaRepository)
+	mockClient := &MockHotelClient{
+		GetQuoteFunc: flatQuote(money.New(500000, "RUB")),
+	}
+
+	booking := newSagaTestBooking()
+	mockRepo.On("HasOverlappingBooking", mock.Anything, "room123", booking.CheckInDate, booking.CheckOutDate, "").Return(false, nil)
+	expectReservation(mockRepo, mockSagas)
+	mockRepo.On("UpdateBookingStatus", mock.Anything, mock.Anything, domain.StatusPending, domain.StatusConfirmed, mock.Anything).Return(errors.New("database error"))
+
+	uc := NewBookingUseCase(mockRepo, mockSagas, nil, mockClient, nil, nil, nil, nil, 0, 0, nil)
+
+	err := uc.CreateBooking(context.Background(), booking)
+	assert.Error(t, err)
+
+	saved := savedSagas(mockSagas)
+	final := saved[len(saved)-1]
+	assert.Equal(t, domain.SagaStepPublish, final.Step)
+	assert.Equal(t, domain.SagaRunning, final.Status)
+	assert.Equal(t, "database error", final.LastError)
+}
+
+func TestResumeSagas(t *testing.T) {
+	logger.Init("info")
+
+	t.Run("compensates saga whose booking was never reserved", func(t *testing.T) {
+		mockRepo := new(MockBookingRepository)
+		mockSagas := new(MockSagaRepository)
+
+		mockSagas.On("GetUnfinishedSagas", mock.Anything, mock.Anything).Return([]domain.BookingSaga{
+			{BookingID: "booking123", Step: domain.SagaStepReserve, Status: domain.SagaRunning},
+		}, nil)
+		mockSagas.On("UpdateSaga", mock.Anything, mock.MatchedBy(func(saga domain.BookingSaga) bool {
+			return saga.Status == domain.SagaCompensated
+		})).Return(nil)
+		mockRepo.On("GetBookingByID", mock.Anything, "booking123").Return(nil, sql.ErrNoRows)
+		mockRepo.On("GetReservationByID", mock.Anything, "booking123").Return(nil, sql.ErrNoRows)
+
+		uc := NewBookingUseCase(mockRepo, mockSagas, nil, &MockHotelClient{}, nil, nil, nil, nil, 0, 0, nil)
+
+		resumed, err := uc.ResumeSagas(context.Background(), time.Minute)
+		assert.NoError(t, err)
+		assert.Equal(t, 1, resumed)
+		mockSagas.AssertExpectations(t)
+	})
+
+	t.Run("compensates saga whose booking no longer exists", func(t *testing.T) {
+		mockRepo := new(MockBookingRepository)
+		mockSagas := new(MockSagaRepository)
+
+		mockSagas.On("GetUnfinishedSagas", mock.Anything, mock.Anything).Return([]domain.BookingSaga{
+			{BookingID: "booking123", Step: domain.SagaStepPublish, Status: domain.SagaRunning},
+		}, nil)
+		mockSagas.On("UpdateSaga", mock.Anything, mock.MatchedBy(func(saga domain.BookingSaga) bool {
+			return saga.Status == domain.SagaCompensated && saga.LastError == "booking no longer exists"
+		})).Return(nil)
+		mockRepo.On("GetBookingByID", mock.Anything, "booking123").Return(nil, sql.ErrNoRows)
+		mockRepo.On("GetReservationByID", mock.Anything, "booking123").Return(nil, sql.ErrNoRows)
+
+		uc := NewBookingUseCase(mockRepo, mockSagas, nil, &MockHotelClient{}, nil, nil, nil, nil, 0, 0, nil)
+
+		resumed, err := uc.ResumeSagas(context.Background(), time.Minute)
+		assert.NoError(t, err)
+		assert.Equal(t, 1, resumed)
+		mockSagas.AssertExpectations(t)
+	})
+
+	t.Run("retries payment and publishes booking", func(t *testing.T) {
+		mockRepo := new(MockBookingRepository)
+		mockSagas := new(MockSagaRepository)
+
+		paymentCreated := false
+		mockPayment := &MockPaymentService{
+			CreatePaymentFunc: func(ctx context.Context, bookingID string, amount money.Money) error {
+				paymentCreated = true
+				return nil
+			},
+		}
+
+		mockSagas.On("GetUnfinishedSagas", mock.Anything, mock.Anything).Return([]domain.BookingSaga{
+			{BookingID: "booking123", Step: domain.SagaStepPay, Status: domain.SagaRunning},
+		}, nil)
+		mockSagas.On("UpdateSaga", mock.Anything, mock.Anything).Return(nil)
+		mockRepo.On("GetBookingByID", mock.Anything, "booking123").Return(&domain.Booking{
+			ID:            "booking123",
+			TotalPrice:    money.New(1000000, "RUB"),
+			Status:        domain.StatusPending,
+			PaymentStatus: domain.PaymentPending,
+		}, nil)
+		mockRepo.On("UpdateBookingStatus", mock.Anything, "booking123", domain.StatusPending, domain.StatusAwaitingPayment, mock.Anything).Return(nil)
+
+		uc := NewBookingUseCase(mockRepo, mockSagas, nil, &MockHotelClient{}, mockPayment, nil, nil, nil, 0, 0, nil)
+
+		resumed, err := uc.ResumeSagas(context.Background(), time.Minute)
+		assert.NoError(t, err)
+		assert.Equal(t, 1, resumed)
+		assert.True(t, paymentCreated)
+		saved := savedSagas(mockSagas)
+		assert.Equal(t, domain.SagaCompleted, saved[len(saved)-1].Status)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("confirms booking already paid before publish", func(t *testing.T) {
+		mockRepo := new(MockBookingRepository)
+		mockSagas := new(MockSagaRepository)
+
+		mockSagas.On("GetUnfinishedSagas", mock.Anything, mock.Anything).Return([]domain.BookingSaga{
+			{BookingID: "booking123", Step: domain.SagaStepPublish, Status: domain.SagaRunning},
+		}, nil)
+		mockSagas.On("UpdateSaga", mock.Anything, mock.Anything).Return(nil)
+		mockRepo.On("GetBookingByID", mock.Anything, "booking123").Return(&domain.Booking{
+			ID:            "booking123",
+			Status:        domain.StatusPending,
+			PaymentStatus: domain.PaymentPaid,
+		}, nil)
+		mockRepo.On("UpdateBookingStatus", mock.Anything, "booking123", domain.StatusPending, domain.StatusConfirmed, mock.Anything).Return(nil)
+
+		uc := NewBookingUseCase(mockRepo, mockSagas, nil, &MockHotelClient{}, &MockPaymentService{}, nil, nil, nil, 0, 0, nil)
+
+		resumed, err := uc.ResumeSagas(context.Background(), time.Minute)
+		assert.NoError(t, err)
+		assert.Equal(t, 1, resumed)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("completes saga already published", func(t *testing.T) {
+		mockRepo := new(MockBookingRepository)
+		mockSagas := new(MockSagaRepository)
+
+		mockSagas.On("GetUnfinishedSagas", mock.Anything, mock.Anything).Return([]domain.BookingSaga{
+			{BookingID: "booking123", Step: domain.SagaStepPublish, Status: domain.SagaRunning},
+		}, nil)
+		mockSagas.On("UpdateSaga", mock.Anything, mock.MatchedBy(func(saga domain.BookingSaga) bool {
+			return saga.Status == domain.SagaCompleted
+		})).Return(nil)
+		mockRepo.On("GetBookingByID", mock.Anything, "booking123").Return(&domain.Booking{
+			ID:     "booking123",
+			Status: domain.StatusAwaitingPayment,
+		}, nil)
+
+		uc := NewBookingUseCase(mockRepo, mockSagas, nil, &MockHotelClient{}, &MockPaymentService{}, nil, nil, nil, 0, 0, nil)
+
+		resumed, err := uc.ResumeSagas(context.Background(), time.Minute)
+		assert.NoError(t, err)
+		assert.Equal(t, 1, resumed)
+		mockRepo.AssertNotCalled(t, "UpdateBookingStatus", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+		mockSagas.AssertExpectations(t)
+	})
+
+	t.Run("continues after a failing saga", func(t *testing.T) {
+		mockRepo := new(MockBookingRepository)
+		mockSagas := new(MockSagaRepository)
+
+		mockSagas.On("GetUnfinishedSagas", mock.Anything, mock.Anything).Return([]domain.BookingSaga{
+			{BookingID: "booking1", Step: domain.SagaStepPublish, Status: domain.SagaRunning},
+			{BookingID: "booking2", Step: domain.SagaStepPublish, Status: domain.SagaRunning},
+		}, nil)
+		mockSagas.On("UpdateSaga", mock.Anything, mock.Anything).Return(nil)
+		mockRepo.On("GetBookingByID", mock.Anything, "booking1").Return(nil, errors.New("database error"))
+		mockRepo.On("GetBookingByID", mock.Anything, "booking2").Return(&domain.Booking{
+			ID:     "booking2",
+			Status: domain.StatusConfirmed,
+		}, nil)
+
+		uc := NewBookingUseCase(mockRepo, mockSagas, nil, &MockHotelClient{}, nil, nil, nil, nil, 0, 0, nil)
+
+		resumed, err := uc.ResumeSagas(context.Background(), time.Minute)
+		assert.NoError(t, err)
+		assert.Equal(t, 1, resumed)
+	})
+
+	t.Run("repository error", func(t *testing.T) {
+		mockSagas := new(MockSagaRepository)
+		mockSagas.On("GetUnfinishedSagas", mock.Anything, mock.Anything).Return(nil, errors.New("database error"))
+
+		uc := NewBookingUseCase(new(MockBookingRepository), mockSagas, nil, &MockHotelClient{}, nil, nil, nil, nil, 0, 0, nil)
+
+		_, err := uc.ResumeSagas(context.Background(), time.Minute)
+		assert.Error(t, err)
+	})
+}
@@ -0,0 +1,156 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"hotel-booking-system/internal/booking/domain"
+	"hotel-booking-system/pkg/logger"
+)
+
+// CheckIn checks the guest in, capturing the payment first if it was only
+// authorized at booking time. A failed capture leaves the booking confirmed.
+func (uc *BookingUseCase) CheckIn(ctx context.Context, id, staffID string) (*domain.Booking, error) {
+	if staffID == "" {
+		return nil, domain.ErrStaffIDRequired
+	}
+
+	booking, err := uc.repo.GetBookingByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := booking.Status.ValidateTransition(domain.StatusCheckedIn); err != nil {
+		return nil, err
+	}
+	if err := uc.capturePayment(ctx, booking); err != nil {
+		return nil, err
+	}
+
+	if err := uc.recordStay(ctx, booking, domain.StatusCheckedIn, staffID); err != nil {
+		return nil, err
+	}
+	return booking, nil
+}
+
+// CheckOut checks the guest out, completing the booking.
+func (uc *BookingUseCase) CheckOut(ctx context.Context, id, staffID string) (*domain.Booking, error) {
+	if staffID == "" {
+		return nil, domain.ErrStaffIDRequired
+	}
+
+	booking, err := uc.repo.GetBookingByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := uc.recordStay(ctx, booking, domain.StatusCompleted, staffID); err != nil {
+		return nil, err
+	}
+	return booking, nil
+}
+
+// MarkNoShow marks a confirmed booking whose guest did not arrive. The guest
+// is not refunded: a payment that was only authorized is captured like at
+// check-in.
+func (uc *BookingUseCase) MarkNoShow(ctx context.Context, id, staffID string) (*domain.Booking, error) {
+	if staffID == "" {
+		return nil, domain.ErrStaffIDRequired
+	}
+
+	booking, err := uc.repo.GetBookingByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := uc.markNoShow(ctx, booking, staffID); err != nil {
+		return nil, err
+	}
+	return booking, nil
+}
+
+// MarkNoShows marks the confirmed bookings whose guests have not arrived
+// within cutoff of the start of their check-in date. A booking that cannot
+// be marked is left confirmed and retried on the next run.
+func (uc *BookingUseCase) MarkNoShows(ctx context.Context, cutoff time.Duration) (int, error) {
+	bookings, err := uc.repo.GetUnattendedBookings(ctx, time.Now().Add(-cutoff))
+	if err != nil {
+		return 0, err
+	}
+
+	marked := 0
+	for i := range bookings {
+		booking := &bookings[i]
+		if err := uc.markNoShow(ctx, booking, ""); err != nil {
+			logger.GetLogger().WithError(err).WithField("booking_id", booking.ID).Error("failed to mark booking as no-show")
+			continue
+		}
+		marked++
+	}
+	return marked, nil
+}
+
+func (uc *BookingUseCase) markNoShow(ctx context.Context, booking *domain.Booking, staffID string) error {
+	if err := booking.Status.ValidateTransition(domain.StatusNoShow); err != nil {
+		return err
+	}
+	if err := uc.capturePayment(ctx, booking); err != nil {
+		return err
+	}
+	return uc.recordStay(ctx, booking, domain.StatusNoShow, staffID)
+}
+
+// capturePayment captures a payment that was only authorized at booking time.
+func (uc *BookingUseCase) capturePayment(ctx context.Context, booking *domain.Booking) error {
+	switch booking.PaymentStatus {
+	case domain.PaymentAuthorized:
+		if uc.paymentClient != nil {
+			if err := uc.paymentClient.CapturePayment(ctx, booking.PaymentReference()); err != nil {
+				return fmt.Errorf("%w: %v", domain.ErrPaymentCaptureFailed, err)
+			}
+		}
+	case domain.PaymentVoided, domain.PaymentExpired:
+		return fmt.Errorf("%w: authorization is %s", domain.ErrPaymentCaptureFailed, booking.PaymentStatus)
+	}
+	return nil
+}
+
+var stayEvents = map[domain.BookingStatus]string{
+	domain.StatusCheckedIn: domain.EventBookingCheckedIn,
+	domain.StatusCompleted: domain.EventBookingCheckedOut,
+	domain.StatusNoShow:    domain.EventBookingNoShow,
+}
+
+// recordStay moves the booking on to checked in, completed or no-show,
+// recording the time and the staff member, and stores the matching event.
+func (uc *BookingUseCase) recordStay(ctx context.Context, booking *domain.Booking, to domain.BookingStatus, staffID string) error {
+	if err := booking.Status.ValidateTransition(to); err != nil {
+		return err
+	}
+
+	at := time.Now()
+	updated := *booking
+	updated.Status = to
+	switch to {
+	case domain.StatusCheckedIn:
+		updated.CheckedInAt, updated.CheckedInBy = &at, staffID
+	case domain.StatusCompleted:
+		updated.CheckedOutAt, updated.CheckedOutBy = &at, staffID
+	case domain.StatusNoShow:
+		updated.NoShowAt, updated.NoShowBy = &at, staffID
+	}
+
+	bookingEvent := newBookingEvent(&updated, stayEvents[to])
+	bookingEvent.StaffID = staffID
+	event, err := uc.newOutboxEvent(stayEvents[to], booking.ID, bookingEvent)
+	if err != nil {
+		return err
+	}
+
+	if err := uc.repo.UpdateStayStatus(ctx, &updated, booking.Status, event); err != nil {
+		return err
+	}
+	*booking = updated
+	return nil
+}
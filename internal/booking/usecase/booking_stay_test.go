@@ -0,0 +1,274 @@
+package usecase
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"hotel-booking-system/internal/booking/domain"
+	"hotel-booking-system/pkg/logger"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCheckIn_CapturesAuthorizedPayment(t *testing.T) {
+	mockRepo := new(MockBookingRepository)
+
+	captured := false
+	mockPayment := &MockPaymentService{
+		CapturePaymentFunc: func(ctx context.Context, bookingID string) error {
+			captured = bookingID == "booking123"
+			return nil
+		},
+	}
+
+	mockRepo.On("GetBookingByID", mock.Anything, "booking123").Return(&domain.Booking{
+		ID:            "booking123",
+		Status:        domain.StatusConfirmed,
+		PaymentStatus: domain.PaymentAuthorized,
+	}, nil)
+	var stored *domain.Booking
+	var outboxEvent *domain.OutboxEvent
+	mockRepo.On("UpdateStayStatus", mock.Anything, mock.Anything, domain.StatusConfirmed, mock.Anything).
+		Run(func(args mock.Arguments) {
+			stored = args.Get(1).(*domain.Booking)
+			outboxEvent = args.Get(3).(*domain.OutboxEvent)
+		}).
+		Return(nil)
+
+	uc := NewBookingUseCase(mockRepo, nil, nil, &MockHotelClient{}, mockPayment, nil, nil, nil, 0, 0, nil)
+
+	booking, err := uc.CheckIn(context.Background(), "booking123", "staff-7")
+	require.NoError(t, err)
+	assert.Equal(t, domain.StatusCheckedIn, booking.Status)
+	require.NotNil(t, booking.CheckedInAt)
+	assert.Equal(t, "staff-7", booking.CheckedInBy)
+	assert.True(t, captured)
+	assert.Equal(t, domain.StatusCheckedIn, stored.Status)
+
+	require.NotNil(t, outboxEvent)
+	assert.Equal(t, domain.EventBookingCheckedIn, outboxEvent.Topic)
+	var event domain.BookingEvent
+	require.NoError(t, json.Unmarshal(outboxEvent.Payload, &event))
+	assert.Equal(t, "staff-7", event.StaffID)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestCheckIn_PaidBookingIsNotCaptured(t *testing.T) {
+	mockRepo := new(MockBookingRepository)
+
+	mockPayment := &MockPaymentService{
+		CapturePaymentFunc: func(ctx context.Context, bookingID string) error {
+			t.Fatal("paid booking must not be captured again")
+			return nil
+		},
+	}
+
+	mockRepo.On("GetBookingByID", mock.Anything, "booking123").Return(&domain.Booking{
+		ID:            "booking123",
+		Status:        domain.StatusConfirmed,
+		PaymentStatus: domain.PaymentPaid,
+	}, nil)
+	mockRepo.On("UpdateStayStatus", mock.Anything, mock.Anything, domain.StatusConfirmed, mock.Anything).Return(nil)
+
+	uc := NewBookingUseCase(mockRepo, nil, nil, &MockHotelClient{}, mockPayment, nil, nil, nil, 0, 0, nil)
+
+	booking, err := uc.CheckIn(context.Background(), "booking123", "staff-7")
+	assert.NoError(t, err)
+	assert.Equal(t, domain.StatusCheckedIn, booking.Status)
+}
+
+func TestCheckIn_CaptureFails(t *testing.T) {
+	mockRepo := new(MockBookingRepository)
+
+	mockPayment := &MockPaymentService{
+		CapturePaymentFunc: func(ctx context.Context, bookingID string) error {
+			return errors.New("payment service returned status 409")
+		},
+	}
+
+	mockRepo.On("GetBookingByID", mock.Anything, "booking123").Return(&domain.Booking{
+		ID:            "booking123",
+		Status:        domain.StatusConfirmed,
+		PaymentStatus: domain.PaymentAuthorized,
+	}, nil)
+
+	uc := NewBookingUseCase(mockRepo, nil, nil, &MockHotelClient{}, mockPayment, nil, nil, nil, 0, 0, nil)
+
+	booking, err := uc.CheckIn(context.Background(), "booking123", "staff-7")
+	assert.ErrorIs(t, err, domain.ErrPaymentCaptureFailed)
+	assert.Nil(t, booking)
+	mockRepo.AssertNotCalled(t, "UpdateStayStatus", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestCheckIn_ExpiredAuthorization(t *testing.T) {
+	mockRepo := new(MockBookingRepository)
+
+	mockRepo.On("GetBookingByID", mock.Anything, "booking123").Return(&domain.Booking{
+		ID:            "booking123",
+		Status:        domain.StatusConfirmed,
+		PaymentStatus: domain.PaymentExpired,
+	}, nil)
+
+	uc := NewBookingUseCase(mockRepo, nil, nil, &MockHotelClient{}, &MockPaymentService{}, nil, nil, nil, 0, 0, nil)
+
+	booking, err := uc.CheckIn(context.Background(), "booking123", "staff-7")
+	assert.ErrorIs(t, err, domain.ErrPaymentCaptureFailed)
+	assert.Nil(t, booking)
+}
+
+func TestCheckIn_NotConfirmed(t *testing.T) {
+	mockRepo := new(MockBookingRepository)
+
+	mockRepo.On("GetBookingByID", mock.Anything, "booking123").Return(&domain.Booking{
+		ID:            "booking123",
+		Status:        domain.StatusAwaitingPayment,
+		PaymentStatus: domain.PaymentPending,
+	}, nil)
+
+	uc := NewBookingUseCase(mockRepo, nil, nil, &MockHotelClient{}, nil, nil, nil, nil, 0, 0, nil)
+
+	booking, err := uc.CheckIn(context.Background(), "booking123", "staff-7")
+	assert.ErrorIs(t, err, domain.ErrInvalidTransition)
+	assert.Nil(t, booking)
+}
+
+func TestCheckIn_StaffIDRequired(t *testing.T) {
+	mockRepo := new(MockBookingRepository)
+
+	uc := NewBookingUseCase(mockRepo, nil, nil, &MockHotelClient{}, nil, nil, nil, nil, 0, 0, nil)
+
+	booking, err := uc.CheckIn(context.Background(), "booking123", "")
+	assert.ErrorIs(t, err, domain.ErrStaffIDRequired)
+	assert.Nil(t, booking)
+	mockRepo.AssertNotCalled(t, "GetBookingByID", mock.Anything, mock.Anything)
+}
+
+func TestCheckOut(t *testing.T) {
+	checkedInAt := time.Date(2030, 12, 20, 15, 0, 0, 0, time.UTC)
+
+	t.Run("success", func(t *testing.T) {
+		mockRepo := new(MockBookingRepository)
+		mockRepo.On("GetBookingByID", mock.Anything, "booking123").Return(&domain.Booking{
+			ID:          "booking123",
+			Status:      domain.StatusCheckedIn,
+			CheckedInAt: &checkedInAt,
+			CheckedInBy: "staff-7",
+		}, nil)
+		var outboxEvent *domain.OutboxEvent
+		mockRepo.On("UpdateStayStatus", mock.Anything, mock.Anything, domain.StatusCheckedIn, mock.Anything).
+			Run(func(args mock.Arguments) { outboxEvent = args.Get(3).(*domain.OutboxEvent) }).
+			Return(nil)
+
+		uc := NewBookingUseCase(mockRepo, nil, nil, &MockHotelClient{}, nil, nil, nil, nil, 0, 0, nil)
+
+		booking, err := uc.CheckOut(context.Background(), "booking123", "staff-9")
+		require.NoError(t, err)
+		assert.Equal(t, domain.StatusCompleted, booking.Status)
+		assert.Equal(t, &checkedInAt, booking.CheckedInAt)
+		assert.Equal(t, "staff-7", booking.CheckedInBy)
+		require.NotNil(t, booking.CheckedOutAt)
+		assert.Equal(t, "staff-9", booking.CheckedOutBy)
+		require.NotNil(t, outboxEvent)
+		assert.Equal(t, domain.EventBookingCheckedOut, outboxEvent.Topic)
+	})
+
+	t.Run("not checked in", func(t *testing.T) {
+		mockRepo := new(MockBookingRepository)
+		mockRepo.On("GetBookingByID", mock.Anything, "booking123").Return(&domain.Booking{
+			ID:     "booking123",
+			Status: domain.StatusConfirmed,
+		}, nil)
+
+		uc := NewBookingUseCase(mockRepo, nil, nil, &MockHotelClient{}, nil, nil, nil, nil, 0, 0, nil)
+
+		booking, err := uc.CheckOut(context.Background(), "booking123", "staff-9")
+		assert.ErrorIs(t, err, domain.ErrInvalidTransition)
+		assert.Nil(t, booking)
+		mockRepo.AssertNotCalled(t, "UpdateStayStatus", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+}
+
+func TestMarkNoShow(t *testing.T) {
+	t.Run("captures authorized payment", func(t *testing.T) {
+		mockRepo := new(MockBookingRepository)
+		mockRepo.On("GetBookingByID", mock.Anything, "booking123").Return(&domain.Booking{
+			ID:            "booking123",
+			Status:        domain.StatusConfirmed,
+			PaymentStatus: domain.PaymentAuthorized,
+		}, nil)
+		var outboxEvent *domain.OutboxEvent
+		mockRepo.On("UpdateStayStatus", mock.Anything, mock.Anything, domain.StatusConfirmed, mock.Anything).
+			Run(func(args mock.Arguments) { outboxEvent = args.Get(3).(*domain.OutboxEvent) }).
+			Return(nil)
+
+		var captured []string
+		mockPayment := &MockPaymentService{
+			CapturePaymentFunc: func(ctx context.Context, bookingID string) error {
+				captured = append(captured, bookingID)
+				return nil
+			},
+		}
+
+		uc := NewBookingUseCase(mockRepo, nil, nil, &MockHotelClient{}, mockPayment, nil, nil, nil, 0, 0, nil)
+
+		booking, err := uc.MarkNoShow(context.Background(), "booking123", "staff-7")
+		require.NoError(t, err)
+		assert.Equal(t, domain.StatusNoShow, booking.Status)
+		require.NotNil(t, booking.NoShowAt)
+		assert.Equal(t, "staff-7", booking.NoShowBy)
+		assert.Equal(t, []string{"booking123"}, captured)
+		require.NotNil(t, outboxEvent)
+		assert.Equal(t, domain.EventBookingNoShow, outboxEvent.Topic)
+	})
+
+	t.Run("checked in", func(t *testing.T) {
+		mockRepo := new(MockBookingRepository)
+		mockRepo.On("GetBookingByID", mock.Anything, "booking123").Return(&domain.Booking{
+			ID:     "booking123",
+			Status: domain.StatusCheckedIn,
+		}, nil)
+
+		uc := NewBookingUseCase(mockRepo, nil, nil, &MockHotelClient{}, nil, nil, nil, nil, 0, 0, nil)
+
+		_, err := uc.MarkNoShow(context.Background(), "booking123", "staff-7")
+		assert.ErrorIs(t, err, domain.ErrInvalidTransition)
+	})
+}
+
+func TestMarkNoShows(t *testing.T) {
+	logger.Init("info")
+
+	mockRepo := new(MockBookingRepository)
+	var checkInBefore time.Time
+	mockRepo.On("GetUnattendedBookings", mock.Anything, mock.Anything).
+		Run(func(args mock.Arguments) { checkInBefore = args.Get(1).(time.Time) }).
+		Return([]domain.Booking{
+			{ID: "booking1", Status: domain.StatusConfirmed, PaymentStatus: domain.PaymentPaid},
+			{ID: "booking2", Status: domain.StatusConfirmed, PaymentStatus: domain.PaymentVoided},
+			{ID: "booking3", Status: domain.StatusConfirmed, PaymentStatus: domain.PaymentPaid},
+		}, nil)
+	var marked []*domain.Booking
+	mockRepo.On("UpdateStayStatus", mock.Anything, mock.Anything, domain.StatusConfirmed, mock.Anything).
+		Run(func(args mock.Arguments) { marked = append(marked, args.Get(1).(*domain.Booking)) }).
+		Return(nil)
+
+	uc := NewBookingUseCase(mockRepo, nil, nil, &MockHotelClient{}, &MockPaymentService{}, nil, nil, nil, 0, 0, nil)
+
+	count, err := uc.MarkNoShows(context.Background(), 30*time.Hour)
+	require.NoError(t, err)
+	assert.Equal(t, 2, count)
+	assert.WithinDuration(t, time.Now().Add(-30*time.Hour), checkInBefore, time.Minute)
+	require.Len(t, marked, 2)
+	assert.Equal(t, "booking1", marked[0].ID)
+	assert.Equal(t, "booking3", marked[1].ID)
+	for _, booking := range marked {
+		assert.Equal(t, domain.StatusNoShow, booking.Status)
+		assert.Empty(t, booking.NoShowBy)
+		assert.NotNil(t, booking.NoShowAt)
+	}
+}
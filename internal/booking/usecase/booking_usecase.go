@@ -2,89 +2,298 @@ package usecase
 
 import (
 	"context"
+	"database/sql"
 	"errors"
-	"strings"
+	"fmt"
 	"time"
 
 	"hotel-booking-system/internal/booking/domain"
+	"hotel-booking-system/pkg/hotelclient"
+	"hotel-booking-system/pkg/logger"
+	"hotel-booking-system/pkg/money"
 
 	"github.com/google/uuid"
 )
 
 type HotelClient interface {
-	GetRoomPrice(ctx context.Context, hotelID, roomID string) (float64, error)
-}
-
-type MessageProducer interface {
-	SendMessage(ctx context.Context, key string, value interface{}) error
+	GetQuote(ctx context.Context, hotelID, roomID string, checkIn, checkOut time.Time, adults, children int) (*hotelclient.Quote, error)
+	GetRooms(ctx context.Context, hotelID string) ([]hotelclient.Room, error)
 }
 
 type PaymentClient interface {
-	CreatePayment(ctx context.Context, bookingID string, amount float64) error
+	CreatePayment(ctx context.Context, bookingID string, amount money.Money) error
+	ChargePayment(ctx context.Context, bookingID, chargeID string, amount money.Money) (paymentID string, err error)
+	RefundPayment(ctx context.Context, bookingID string, amount money.Money) error
+	CapturePayment(ctx context.Context, bookingID string) error
+	VoidPayment(ctx context.Context, bookingID string) error
 }
 
 type BookingUseCase struct {
 	repo          domain.BookingRepository
+	sagas         domain.SagaRepository
+	holds         domain.HoldRepository
 	hotelClient   HotelClient
-	producer      MessageProducer
 	paymentClient PaymentClient
+	rates         domain.RateProvider
+	promotions    domain.PromotionRepository
+	waitlist      domain.WaitlistRepository
+	holdTTL       time.Duration
+	offerTTL      time.Duration
+	topics        domain.Topics
 }
 
-func NewBookingUseCase(repo domain.BookingRepository, hotelClient HotelClient, producer MessageProducer, paymentClient PaymentClient) *BookingUseCase {
+func NewBookingUseCase(repo domain.BookingRepository, sagas domain.SagaRepository, holds domain.HoldRepository, hotelClient HotelClient, paymentClient PaymentClient, rates domain.RateProvider, promotions domain.PromotionRepository, waitlist domain.WaitlistRepository, holdTTL, offerTTL time.Duration, topics domain.Topics) *BookingUseCase {
 	return &BookingUseCase{
 		repo:          repo,
+		sagas:         sagas,
+		holds:         holds,
 		hotelClient:   hotelClient,
-		producer:      producer,
 		paymentClient: paymentClient,
+		rates:         rates,
+		promotions:    promotions,
+		waitlist:      waitlist,
+		holdTTL:       holdTTL,
+		offerTTL:      offerTTL,
+		topics:        topics,
 	}
 }
 
 func (uc *BookingUseCase) CreateBooking(ctx context.Context, booking *domain.Booking) error {
-	if booking.CheckInDate.After(booking.CheckOutDate) {
-		return errors.New("check-in date must be before check-out date")
+	if err := validateGuests(booking); err != nil {
+		return err
+	}
+	if booking.HoldID != "" {
+		if err := uc.applyHold(ctx, booking); err != nil {
+			return err
+		}
+	} else {
+		if !booking.CheckInDate.Before(booking.CheckOutDate) {
+			return domain.ErrInvalidDates
+		}
+
+		overlapping, err := uc.repo.HasOverlappingBooking(ctx, booking.RoomID, booking.CheckInDate, booking.CheckOutDate, "")
+		if err != nil {
+			return err
+		}
+		if overlapping {
+			return domain.ErrRoomNotAvailable
+		}
+	}
+
+	if err := uc.priceStay(ctx, booking, time.Now()); err != nil {
+		return err
 	}
+	if err := uc.convertPrice(ctx, booking); err != nil {
+		return err
+	}
+
+	booking.ID = uuid.New().String()
+	booking.Status = domain.StatusPending
+	booking.PaymentStatus = domain.PaymentPending
 
-	pricePerNight, err := uc.hotelClient.GetRoomPrice(ctx, booking.HotelID, booking.RoomID)
+	return uc.startSaga(ctx, booking)
+}
+
+// validateGuests checks the booking's guest counts, booking the room for one
+// adult when none are given.
+func validateGuests(booking *domain.Booking) error {
+	if booking.Adults == 0 && booking.Children == 0 {
+		booking.Adults = 1
+	}
+	if booking.Adults < 1 || booking.Children < 0 {
+		return domain.ErrInvalidGuests
+	}
+	return nil
+}
+
+// priceStay prices the booking's room, dates and guests in the hotel's
+// currency from the hotel's quote, applying its promo code as of the given
+// time, and fixes the room type and the cancellation policy of the stay. The hotel service
+// rejects more guests than the room holds.
+func (uc *BookingUseCase) priceStay(ctx context.Context, booking *domain.Booking, promotionAt time.Time) error {
+	quote, err := uc.hotelClient.GetQuote(ctx, booking.HotelID, booking.RoomID, booking.CheckInDate, booking.CheckOutDate, booking.Adults, booking.Children)
+	if errors.Is(err, hotelclient.ErrCapacityExceeded) {
+		return domain.ErrCapacityExceeded
+	}
 	if err != nil {
 		return err
 	}
+	if quote.Capacity > 0 && booking.Adults+booking.Children > quote.Capacity {
+		return domain.ErrCapacityExceeded
+	}
+	discount, err := uc.promotionDiscount(ctx, booking, quote, promotionAt)
+	if err != nil {
+		return err
+	}
+	if booking.PriceBreakdown, booking.TotalPrice, err = priceBreakdown(quote, discount); err != nil {
+		return err
+	}
+	booking.RoomType = quote.RoomType
+	booking.CancellationPolicy = cancellationPolicy(quote)
+	return nil
+}
 
-	nights := int(booking.CheckOutDate.Sub(booking.CheckInDate).Hours() / 24)
-	if nights < 1 {
-		nights = 1
+// priceBreakdown itemises a quote into the accommodation, the sum of its
+// nightly prices, less the promo code discount if any, followed by the hotel's
+// taxes and fees, and adds them up. Percentage fees are charged on the
+// discounted accommodation.
+func priceBreakdown(quote *hotelclient.Quote, discount *domain.PriceItem) ([]domain.PriceItem, money.Money, error) {
+	if len(quote.Nights) == 0 {
+		return nil, money.Money{}, fmt.Errorf("hotel service returned a quote without nights")
+	}
+	var accommodation money.Money
+	for _, night := range quote.Nights {
+		var err error
+		if accommodation, err = accommodation.Add(night.Price); err != nil {
+			return nil, money.Money{}, err
+		}
 	}
-	booking.TotalPrice = pricePerNight * float64(nights)
 
-	booking.ID = uuid.New().String()
-	booking.Status = "confirmed"
-	booking.PaymentStatus = "pending"
+	items := []domain.PriceItem{{Kind: domain.PriceItemAccommodation, Amount: accommodation}}
+	total := accommodation
+	if discount != nil {
+		var err error
+		if total, err = total.Add(discount.Amount); err != nil {
+			return nil, money.Money{}, err
+		}
+		items = append(items, *discount)
+	}
+	subtotal := total
+	for _, fee := range quote.Fees {
+		amount := fee.Amount
+		if discount != nil && fee.BasisPoints > 0 {
+			amount = subtotal.Percent(fee.BasisPoints)
+		}
+		var err error
+		if total, err = total.Add(amount); err != nil {
+			return nil, money.Money{}, err
+		}
+		items = append(items, domain.PriceItem{Kind: domain.PriceItemKind(fee.Kind), Name: fee.Name, Amount: amount})
+	}
+	return items, total, nil
+}
+
+// cancellationPolicy copies the quote's policy onto the booking.
+func cancellationPolicy(quote *hotelclient.Quote) *domain.CancellationPolicy {
+	if quote.CancellationPolicy == nil {
+		return nil
+	}
+	policy := &domain.CancellationPolicy{
+		ID:    quote.CancellationPolicy.ID,
+		Name:  quote.CancellationPolicy.Name,
+		Rules: []domain.CancellationRule{},
+	}
+	for _, rule := range quote.CancellationPolicy.Rules {
+		policy.Rules = append(policy.Rules, domain.CancellationRule{
+			HoursBeforeCheckIn: rule.HoursBeforeCheckIn,
+			RefundBasisPoints:  rule.RefundBasisPoints,
+		})
+	}
+	return policy
+}
+
+// convertPrice fixes the rate from the hotel's currency into the currency the
+// guest asked to pay in and prices the booking in it.
+func (uc *BookingUseCase) convertPrice(ctx context.Context, booking *domain.Booking) error {
+	if booking.DisplayCurrency == "" {
+		booking.DisplayCurrency = booking.TotalPrice.Currency
+	}
+
+	rate := money.IdentityRate(booking.TotalPrice.Currency)
+	if booking.DisplayCurrency != booking.TotalPrice.Currency {
+		if uc.rates == nil {
+			return domain.ErrRateNotFound
+		}
+		var err error
+		rate, err = uc.rates.GetRate(ctx, booking.TotalPrice.Currency, booking.DisplayCurrency, time.Now())
+		if err != nil {
+			return err
+		}
+	}
 
-	if err := uc.repo.CreateBooking(ctx, booking); err != nil {
+	displayPrice, err := booking.TotalPrice.Convert(rate)
+	if err != nil {
 		return err
 	}
+	booking.ExchangeRate = rate
+	booking.DisplayPrice = displayPrice
+	return nil
+}
+
+// CancelBooking refunds what the booking's cancellation policy allows at the
+// time of cancellation. An authorized payment is voided when fully
+// refundable; otherwise it is captured and the refundable part refunded, so
+// the guest pays the cancellation fee. The authorization of a reservation
+// covers its other bookings too, so it is never voided for one of them. The
+// payment is settled only once the cancellation is stored, so that concurrent
+// cancellations cannot both refund it.
+func (uc *BookingUseCase) CancelBooking(ctx context.Context, id string) (*domain.Booking, error) {
+	booking, err := uc.repo.GetBookingByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
 
+	if !booking.Status.CanTransitionTo(domain.StatusCancelled) {
+		return nil, domain.ErrBookingNotCancellable
+	}
+
+	var refundAmount *money.Money
 	if uc.paymentClient != nil {
-		if err := uc.paymentClient.CreatePayment(ctx, booking.ID, booking.TotalPrice); err != nil {
-			return err
+		refundable := booking.RefundableAmount(time.Now())
+		switch booking.PaymentStatus {
+		case domain.PaymentPaid, domain.PaymentPartiallyRefunded, domain.PaymentAuthorized:
+			if booking.PaymentStatus == domain.PaymentAuthorized && refundable == booking.TotalPrice && booking.ReservationID == "" {
+				break
+			}
+			charged, err := booking.ChargeAmount(refundable)
+			if err != nil {
+				return nil, err
+			}
+			refundAmount = &charged
 		}
 	}
 
-	event := domain.BookingEvent{
-		BookingID:    booking.ID,
-		UserID:       booking.UserID,
-		HotelID:      booking.HotelID,
-		RoomID:       booking.RoomID,
-		CheckInDate:  booking.CheckInDate,
-		CheckOutDate: booking.CheckOutDate,
-		TotalPrice:   booking.TotalPrice,
-		EventType:    "booking.created",
-		Timestamp:    time.Now(),
+	bookingEvent := newBookingEvent(booking, domain.EventBookingCancelled)
+	bookingEvent.RefundAmount = refundAmount
+	event, err := uc.newOutboxEvent(domain.EventBookingCancelled, booking.ID, bookingEvent)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := uc.transition(ctx, booking, domain.StatusCancelled, event); err != nil {
+		return nil, err
 	}
 
-	if err := uc.producer.SendMessage(ctx, booking.ID, event); err != nil {
-		return err
+	if uc.paymentClient != nil {
+		if err := uc.settleCancellation(ctx, booking, refundAmount); err != nil {
+			logger.GetLogger().WithError(err).WithField("booking_id", booking.ID).Error("failed to settle the payment of a cancelled booking")
+			return nil, err
+		}
 	}
 
+	return booking, nil
+}
+
+// settleCancellation voids the authorized payment of a cancelled booking when
+// nothing is to be kept, or captures it, and refunds the refund amount in the
+// currency the guest paid in.
+func (uc *BookingUseCase) settleCancellation(ctx context.Context, booking *domain.Booking, refundAmount *money.Money) error {
+	switch booking.PaymentStatus {
+	case domain.PaymentAuthorized:
+		if refundAmount == nil {
+			return uc.paymentClient.VoidPayment(ctx, booking.ID)
+		}
+		if err := uc.paymentClient.CapturePayment(ctx, booking.PaymentReference()); err != nil {
+			return fmt.Errorf("%w: %v", domain.ErrPaymentCaptureFailed, err)
+		}
+	case domain.PaymentPaid, domain.PaymentPartiallyRefunded:
+	default:
+		return nil
+	}
+
+	if refundAmount.IsPositive() {
+		return uc.paymentClient.RefundPayment(ctx, booking.PaymentReference(), *refundAmount)
+	}
 	return nil
 }
 
@@ -100,17 +309,177 @@ func (uc *BookingUseCase) GetBookingsByHotel(ctx context.Context, hotelID string
 	return uc.repo.GetBookingsByHotel(ctx, hotelID)
 }
 
-func (uc *BookingUseCase) UpdatePaymentStatus(ctx context.Context, id, status string) error {
-	validStatuses := []string{"pending", "paid", "failed", "refunded"}
-	found := false
-	for _, s := range validStatuses {
-		if strings.EqualFold(s, status) {
-			found = true
-			break
+// UpdatePaymentStatus applies a payment webhook to the booking or reservation
+// paid under the ID. The outcome of an extra charge for a modification goes to
+// the modification instead, so a declined charge reverts the modification
+// rather than failing the booking's payment.
+func (uc *BookingUseCase) UpdatePaymentStatus(ctx context.Context, id, paymentID, status string) error {
+	paymentStatus, err := domain.ParsePaymentStatus(status)
+	if err != nil {
+		return err
+	}
+
+	if paymentID != "" && (paymentStatus == domain.PaymentPaid || paymentStatus == domain.PaymentFailed) {
+		modification, err := uc.repo.GetPendingChargeModification(ctx, id, paymentID)
+		switch {
+		case err == nil:
+			return uc.settleModificationCharge(ctx, modification, paymentStatus)
+		case !errors.Is(err, sql.ErrNoRows):
+			return err
 		}
 	}
-	if !found {
-		return errors.New("invalid payment status")
+
+	booking, err := uc.repo.GetBookingByID(ctx, id)
+	if errors.Is(err, sql.ErrNoRows) {
+		return uc.updateReservationPaymentStatus(ctx, id, paymentStatus)
+	}
+	if err != nil {
+		return err
+	}
+	return uc.updatePaymentStatus(ctx, booking, paymentStatus)
+}
+
+func (uc *BookingUseCase) updatePaymentStatus(ctx context.Context, booking *domain.Booking, paymentStatus domain.PaymentStatus) error {
+	if booking.PaymentStatus == paymentStatus {
+		return nil
+	}
+	// An extra charge of a partially refunded booking does not undo the
+	// refund.
+	if booking.PaymentStatus == domain.PaymentPartiallyRefunded && paymentStatus == domain.PaymentPaid {
+		return nil
+	}
+	if err := booking.PaymentStatus.ValidateTransition(paymentStatus); err != nil {
+		return err
+	}
+	if booking.Status == domain.StatusExpired {
+		if err := uc.settleExpiredPayment(ctx, booking, paymentStatus); err != nil {
+			return err
+		}
+	}
+	if err := uc.repo.UpdatePaymentStatus(ctx, booking.ID, booking.PaymentStatus, paymentStatus); err != nil {
+		return err
+	}
+	booking.PaymentStatus = paymentStatus
+
+	if booking.Status != domain.StatusAwaitingPayment {
+		return nil
+	}
+	switch paymentStatus {
+	case domain.PaymentPaid, domain.PaymentAuthorized:
+		return uc.transition(ctx, booking, domain.StatusConfirmed, nil)
+	case domain.PaymentFailed:
+		event, err := uc.newOutboxEvent(domain.EventBookingCancelled, booking.ID, newBookingEvent(booking, domain.EventBookingCancelled))
+		if err != nil {
+			return err
+		}
+		return uc.transition(ctx, booking, domain.StatusCancelled, event)
+	}
+	return nil
+}
+
+// ExpireBookings expires the bookings that have been awaiting payment for
+// longer than timeout, which frees their rooms. A booking whose payment
+// arrives meanwhile is left alone, and one that cannot be expired is retried
+// on the next run.
+func (uc *BookingUseCase) ExpireBookings(ctx context.Context, timeout time.Duration, limit int) (int, error) {
+	bookings, err := uc.repo.GetUnpaidBookings(ctx, time.Now().Add(-timeout), limit)
+	if err != nil {
+		return 0, err
+	}
+
+	expired := 0
+	for i := range bookings {
+		booking := &bookings[i]
+		event, err := uc.newOutboxEvent(domain.EventBookingExpired, booking.ID, newBookingEvent(booking, domain.EventBookingExpired))
+		if err != nil {
+			return expired, err
+		}
+		err = uc.transition(ctx, booking, domain.StatusExpired, event)
+		if errors.Is(err, domain.ErrStatusChanged) {
+			continue
+		}
+		if err != nil {
+			logger.GetLogger().WithError(err).WithField("booking_id", booking.ID).Error("failed to expire unpaid booking")
+			continue
+		}
+		expired++
+	}
+	return expired, nil
+}
+
+// settleExpiredPayment gives back a payment that went through after its
+// booking had expired: the authorization of a booking is voided and anything
+// else refunded in full. It runs before the payment status is recorded, so a
+// failure is retried with the webhook.
+func (uc *BookingUseCase) settleExpiredPayment(ctx context.Context, booking *domain.Booking, paymentStatus domain.PaymentStatus) error {
+	if uc.paymentClient == nil {
+		return nil
+	}
+	if paymentStatus != domain.PaymentPaid && paymentStatus != domain.PaymentAuthorized {
+		return nil
+	}
+
+	paid := *booking
+	paid.PaymentStatus = paymentStatus
+	var refundAmount *money.Money
+	if paymentStatus == domain.PaymentPaid || booking.ReservationID != "" {
+		charged, err := booking.ChargeAmount(booking.TotalPrice)
+		if err != nil {
+			return err
+		}
+		refundAmount = &charged
+	}
+	return uc.settleCancellation(ctx, &paid, refundAmount)
+}
+
+func (uc *BookingUseCase) GetStatusHistory(ctx context.Context, id string) ([]domain.StatusChange, error) {
+	if _, err := uc.repo.GetBookingByID(ctx, id); err != nil {
+		return nil, err
+	}
+	return uc.repo.GetStatusHistory(ctx, id)
+}
+
+func (uc *BookingUseCase) GetBookedRoomIDs(ctx context.Context, hotelID string, checkIn, checkOut time.Time) ([]string, error) {
+	if !checkIn.Before(checkOut) {
+		return nil, domain.ErrInvalidDates
+	}
+	return uc.repo.GetBookedRoomIDs(ctx, hotelID, checkIn, checkOut)
+}
+
+func (uc *BookingUseCase) transition(ctx context.Context, booking *domain.Booking, to domain.BookingStatus, event *domain.OutboxEvent) error {
+	if err := booking.Status.ValidateTransition(to); err != nil {
+		return err
+	}
+	if err := uc.repo.UpdateBookingStatus(ctx, booking.ID, booking.Status, to, event); err != nil {
+		return err
+	}
+	booking.Status = to
+	return nil
+}
+
+// newOutboxEvent writes the event to the outbox under the Kafka topic
+// configured for its type.
+func (uc *BookingUseCase) newOutboxEvent(eventType, key string, value interface{}) (*domain.OutboxEvent, error) {
+	return domain.NewOutboxEvent(uc.topics.Topic(eventType), key, value)
+}
+
+func newBookingEvent(booking *domain.Booking, eventType string) domain.BookingEvent {
+	return domain.BookingEvent{
+		BookingID:      booking.ID,
+		UserID:         booking.UserID,
+		HotelID:        booking.HotelID,
+		RoomID:         booking.RoomID,
+		RoomType:       booking.RoomType,
+		ReservationID:  booking.ReservationID,
+		GuestName:      booking.GuestName,
+		Adults:         booking.Adults,
+		Children:       booking.Children,
+		CheckInDate:    booking.CheckInDate,
+		CheckOutDate:   booking.CheckOutDate,
+		TotalPrice:     booking.TotalPrice,
+		PriceBreakdown: booking.PriceBreakdown,
+		DisplayPrice:   booking.DisplayPrice,
+		EventType:      eventType,
+		Timestamp:      time.Now(),
 	}
-	return uc.repo.UpdatePaymentStatus(ctx, id, status)
 }
@@ -2,14 +2,20 @@ package usecase
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
+	"fmt"
 	"testing"
 	"time"
 
 	"hotel-booking-system/internal/booking/domain"
+	"hotel-booking-system/pkg/hotelclient"
+	"hotel-booking-system/pkg/logger"
+	"hotel-booking-system/pkg/money"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
 )
 
 type MockBookingRepository struct {
@@ -39,56 +45,619 @@ func (m *MockBookingRepository) GetBookingsByHotel(ctx context.Context, hotelID
 	return args.Get(0).([]domain.Booking), args.Error(1)
 }
 
-func (m *MockBookingRepository) UpdateBookingStatus(ctx context.Context, id, status string) error {
-	args := m.Called(ctx, id, status)
+func (m *MockBookingRepository) UpdateBookingStatus(ctx context.Context, id string, from, to domain.BookingStatus, event *domain.OutboxEvent) error {
+	args := m.Called(ctx, id, from, to, event)
 	return args.Error(0)
 }
 
-func (m *MockBookingRepository) UpdatePaymentStatus(ctx context.Context, id, paymentStatus string) error {
-	args := m.Called(ctx, id, paymentStatus)
+func (m *MockBookingRepository) UpdatePaymentStatus(ctx context.Context, id string, from, to domain.PaymentStatus) error {
+	args := m.Called(ctx, id, from, to)
+	return args.Error(0)
+}
+
+func (m *MockBookingRepository) GetStatusHistory(ctx context.Context, bookingID string) ([]domain.StatusChange, error) {
+	args := m.Called(ctx, bookingID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]domain.StatusChange), args.Error(1)
+}
+
+func (m *MockBookingRepository) ModifyBooking(ctx context.Context, booking *domain.Booking, modification *domain.BookingModification, event *domain.OutboxEvent) error {
+	args := m.Called(ctx, booking, modification, event)
+	return args.Error(0)
+}
+
+func (m *MockBookingRepository) RevertModification(ctx context.Context, modification *domain.BookingModification) error {
+	args := m.Called(ctx, modification)
+	return args.Error(0)
+}
+
+func (m *MockBookingRepository) SetModificationPayment(ctx context.Context, id, paymentID string) error {
+	args := m.Called(ctx, id, paymentID)
+	return args.Error(0)
+}
+
+func (m *MockBookingRepository) GetPendingChargeModification(ctx context.Context, paymentReference, paymentID string) (*domain.BookingModification, error) {
+	args := m.Called(ctx, paymentReference, paymentID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.BookingModification), args.Error(1)
+}
+
+func (m *MockBookingRepository) UpdateModificationStatus(ctx context.Context, id string, from, to domain.ModificationStatus, event *domain.OutboxEvent) error {
+	args := m.Called(ctx, id, from, to, event)
+	return args.Error(0)
+}
+
+func (m *MockBookingRepository) UpdateStayStatus(ctx context.Context, booking *domain.Booking, from domain.BookingStatus, event *domain.OutboxEvent) error {
+	args := m.Called(ctx, booking, from, event)
+	return args.Error(0)
+}
+
+func (m *MockBookingRepository) GetUnattendedBookings(ctx context.Context, checkInBefore time.Time) ([]domain.Booking, error) {
+	args := m.Called(ctx, checkInBefore)
+	return args.Get(0).([]domain.Booking), args.Error(1)
+}
+
+func (m *MockBookingRepository) GetUnpaidBookings(ctx context.Context, updatedBefore time.Time, limit int) ([]domain.Booking, error) {
+	args := m.Called(ctx, updatedBefore, limit)
+	return args.Get(0).([]domain.Booking), args.Error(1)
+}
+
+func (m *MockBookingRepository) CreateReservation(ctx context.Context, reservation *domain.Reservation) error {
+	args := m.Called(ctx, reservation)
+	return args.Error(0)
+}
+
+func (m *MockBookingRepository) GetReservationByID(ctx context.Context, id string) (*domain.Reservation, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.Reservation), args.Error(1)
+}
+
+func (m *MockBookingRepository) HasOverlappingBooking(ctx context.Context, roomID string, checkIn, checkOut time.Time, excludeBookingID string) (bool, error) {
+	args := m.Called(ctx, roomID, checkIn, checkOut, excludeBookingID)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockBookingRepository) GetBookedRoomIDs(ctx context.Context, hotelID string, checkIn, checkOut time.Time) ([]string, error) {
+	args := m.Called(ctx, hotelID, checkIn, checkOut)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]string), args.Error(1)
+}
+
+type MockSagaRepository struct {
+	mock.Mock
+}
+
+func (m *MockSagaRepository) CreateSaga(ctx context.Context, saga *domain.BookingSaga) error {
+	args := m.Called(ctx, *saga)
+	return args.Error(0)
+}
+
+func (m *MockSagaRepository) UpdateSaga(ctx context.Context, saga *domain.BookingSaga) error {
+	args := m.Called(ctx, *saga)
+	return args.Error(0)
+}
+
+func (m *MockSagaRepository) GetUnfinishedSagas(ctx context.Context, updatedBefore time.Time) ([]domain.BookingSaga, error) {
+	args := m.Called(ctx, updatedBefore)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]domain.BookingSaga), args.Error(1)
+}
+
+type MockHoldRepository struct {
+	mock.Mock
+}
+
+func (m *MockHoldRepository) CreateHold(ctx context.Context, hold *domain.RoomHold, ttl time.Duration) error {
+	args := m.Called(ctx, hold, ttl)
+	return args.Error(0)
+}
+
+func (m *MockHoldRepository) GetHoldByID(ctx context.Context, id string) (*domain.RoomHold, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.RoomHold), args.Error(1)
+}
+
+func (m *MockHoldRepository) GetExpiredHolds(ctx context.Context, limit int) ([]domain.RoomHold, error) {
+	args := m.Called(ctx, limit)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]domain.RoomHold), args.Error(1)
+}
+
+func (m *MockHoldRepository) GetExpiredRoomHolds(ctx context.Context, roomID string, checkIn, checkOut time.Time) ([]domain.RoomHold, error) {
+	args := m.Called(ctx, roomID, checkIn, checkOut)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]domain.RoomHold), args.Error(1)
+}
+
+func (m *MockHoldRepository) ExpireHold(ctx context.Context, id string, event *domain.OutboxEvent) error {
+	args := m.Called(ctx, id, event)
 	return args.Error(0)
 }
 
 type MockHotelClient struct {
-	GetRoomPriceFunc func(ctx context.Context, hotelID, roomID string) (float64, error)
+	GetQuoteFunc func(ctx context.Context, hotelID, roomID string, checkIn, checkOut time.Time, adults, children int) (*hotelclient.Quote, error)
+	Rooms        []hotelclient.Room
+}
+
+func (m *MockHotelClient) GetQuote(ctx context.Context, hotelID, roomID string, checkIn, checkOut time.Time, adults, children int) (*hotelclient.Quote, error) {
+	if m.GetQuoteFunc != nil {
+		return m.GetQuoteFunc(ctx, hotelID, roomID, checkIn, checkOut, adults, children)
+	}
+	return flatQuote(money.Money{})(ctx, hotelID, roomID, checkIn, checkOut, adults, children)
+}
+
+// flatQuote prices every night of the stay the same, counting nights by
+// calendar date like the hotel service does.
+func flatQuote(price money.Money) func(ctx context.Context, hotelID, roomID string, checkIn, checkOut time.Time, adults, children int) (*hotelclient.Quote, error) {
+	return func(ctx context.Context, hotelID, roomID string, checkIn, checkOut time.Time, adults, children int) (*hotelclient.Quote, error) {
+		quote := &hotelclient.Quote{}
+		first, last := checkIn.Truncate(24*time.Hour), checkOut.Truncate(24*time.Hour)
+		for night := first; night.Before(last) || len(quote.Nights) == 0; night = night.AddDate(0, 0, 1) {
+			quote.Nights = append(quote.Nights, hotelclient.NightlyRate{Date: night, Price: price})
+			quote.Subtotal = money.New(price.Amount*int64(len(quote.Nights)), price.Currency)
+			quote.Total = quote.Subtotal
+		}
+		return quote, nil
+	}
+}
+
+func (m *MockHotelClient) GetRooms(ctx context.Context, hotelID string) ([]hotelclient.Room, error) {
+	return m.Rooms, nil
+}
+
+func (m *MockHotelClient) Close() error {
+	return nil
+}
+
+type MockPaymentService struct {
+	CreatePaymentFunc  func(ctx context.Context, bookingID string, amount money.Money) error
+	ChargePaymentFunc  func(ctx context.Context, bookingID, chargeID string, amount money.Money) (string, error)
+	RefundPaymentFunc  func(ctx context.Context, bookingID string, amount money.Money) error
+	CapturePaymentFunc func(ctx context.Context, bookingID string) error
+	VoidPaymentFunc    func(ctx context.Context, bookingID string) error
+}
+
+func (m *MockPaymentService) CreatePayment(ctx context.Context, bookingID string, amount money.Money) error {
+	if m.CreatePaymentFunc != nil {
+		return m.CreatePaymentFunc(ctx, bookingID, amount)
+	}
+	return nil
+}
+
+func (m *MockPaymentService) ChargePayment(ctx context.Context, bookingID, chargeID string, amount money.Money) (string, error) {
+	if m.ChargePaymentFunc != nil {
+		return m.ChargePaymentFunc(ctx, bookingID, chargeID, amount)
+	}
+	return "charge-payment", nil
+}
+
+func (m *MockPaymentService) RefundPayment(ctx context.Context, bookingID string, amount money.Money) error {
+	if m.RefundPaymentFunc != nil {
+		return m.RefundPaymentFunc(ctx, bookingID, amount)
+	}
+	return nil
+}
+
+func (m *MockPaymentService) CapturePayment(ctx context.Context, bookingID string) error {
+	if m.CapturePaymentFunc != nil {
+		return m.CapturePaymentFunc(ctx, bookingID)
+	}
+	return nil
+}
+
+func (m *MockPaymentService) VoidPayment(ctx context.Context, bookingID string) error {
+	if m.VoidPaymentFunc != nil {
+		return m.VoidPaymentFunc(ctx, bookingID)
+	}
+	return nil
+}
+
+type MockRateProvider struct {
+	mock.Mock
+}
+
+func (m *MockRateProvider) GetRate(ctx context.Context, from, to string, at time.Time) (money.Rate, error) {
+	args := m.Called(ctx, from, to, at)
+	return args.Get(0).(money.Rate), args.Error(1)
+}
+
+func expectReservation(mockRepo *MockBookingRepository, mockSagas *MockSagaRepository) {
+	stored := &domain.Booking{}
+	mockRepo.On("CreateBooking", mock.Anything, mock.Anything).
+		Run(func(args mock.Arguments) { *stored = *args.Get(1).(*domain.Booking) }).
+		Return(nil)
+	mockRepo.On("GetBookingByID", mock.Anything, mock.Anything).Return(stored, nil).Maybe()
+	mockSagas.On("CreateSaga", mock.Anything, mock.Anything).Return(nil)
+	mockSagas.On("UpdateSaga", mock.Anything, mock.Anything).Return(nil)
+}
+
+func TestCreateBooking_Success(t *testing.T) {
+	mockRepo := new(MockBookingRepository)
+	mockClient := &MockHotelClient{
+		GetQuoteFunc: flatQuote(money.New(500000, "RUB")),
+	}
+
+	booking := &domain.Booking{
+		UserID:       "user123",
+		HotelID:      "hotel123",
+		RoomID:       "room123",
+		CheckInDate:  time.Now().AddDate(0, 0, 1),
+		CheckOutDate: time.Now().AddDate(0, 0, 3),
+	}
+
+	mockSagas := new(MockSagaRepository)
+	mockRepo.On("HasOverlappingBooking", mock.Anything, "room123", booking.CheckInDate, booking.CheckOutDate, "").Return(false, nil)
+	expectReservation(mockRepo, mockSagas)
+	var outboxEvent *domain.OutboxEvent
+	mockRepo.On("UpdateBookingStatus", mock.Anything, mock.Anything, domain.StatusPending, domain.StatusConfirmed, mock.Anything).
+		Run(func(args mock.Arguments) { outboxEvent = args.Get(4).(*domain.OutboxEvent) }).
+		Return(nil)
+
+	uc := &BookingUseCase{
+		repo:        mockRepo,
+		sagas:       mockSagas,
+		hotelClient: mockClient,
+	}
+
+	err := uc.CreateBooking(context.Background(), booking)
+	assert.NoError(t, err)
+	assert.NotEmpty(t, booking.ID)
+	assert.Equal(t, domain.StatusConfirmed, booking.Status)
+	assert.Equal(t, domain.PaymentPending, booking.PaymentStatus)
+	assert.True(t, booking.TotalPrice.IsPositive())
+
+	require.NotNil(t, outboxEvent)
+	assert.Equal(t, domain.EventBookingCreated, outboxEvent.Topic)
+	assert.Equal(t, booking.ID, outboxEvent.Key)
+	var event domain.BookingEvent
+	require.NoError(t, json.Unmarshal(outboxEvent.Payload, &event))
+	assert.Equal(t, domain.EventBookingCreated, event.EventType)
+	assert.Equal(t, domain.StatusConfirmed, event.Status)
+	assert.Equal(t, booking.TotalPrice, event.TotalPrice)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestCreateBooking_SumsNightlyQuote(t *testing.T) {
+	mockRepo := new(MockBookingRepository)
+	booking := &domain.Booking{
+		UserID:       "user123",
+		HotelID:      "hotel123",
+		RoomID:       "room123",
+		CheckInDate:  time.Date(2030, 12, 20, 14, 0, 0, 0, time.UTC),
+		CheckOutDate: time.Date(2030, 12, 22, 12, 0, 0, 0, time.UTC),
+	}
+	mockClient := &MockHotelClient{
+		GetQuoteFunc: func(ctx context.Context, hotelID, roomID string, checkIn, checkOut time.Time, adults, children int) (*hotelclient.Quote, error) {
+			assert.Equal(t, booking.CheckInDate, checkIn)
+			assert.Equal(t, booking.CheckOutDate, checkOut)
+			return &hotelclient.Quote{Nights: []hotelclient.NightlyRate{
+				{Date: time.Date(2030, 12, 20, 0, 0, 0, 0, time.UTC), Price: money.New(700000, "RUB"), RatePlanID: "weekend"},
+				{Date: time.Date(2030, 12, 21, 0, 0, 0, 0, time.UTC), Price: money.New(500000, "RUB")},
+			}}, nil
+		},
+	}
+
+	mockSagas := new(MockSagaRepository)
+	mockRepo.On("HasOverlappingBooking", mock.Anything, "room123", booking.CheckInDate, booking.CheckOutDate, "").Return(false, nil)
+	expectReservation(mockRepo, mockSagas)
+	mockRepo.On("UpdateBookingStatus", mock.Anything, mock.Anything, domain.StatusPending, domain.StatusConfirmed, mock.Anything).Return(nil)
+
+	uc := NewBookingUseCase(mockRepo, mockSagas, nil, mockClient, nil, nil, nil, nil, 0, 0, nil)
+
+	err := uc.CreateBooking(context.Background(), booking)
+	assert.NoError(t, err)
+	assert.Equal(t, money.New(1200000, "RUB"), booking.TotalPrice)
+	assert.Equal(t, []domain.PriceItem{
+		{Kind: domain.PriceItemAccommodation, Amount: money.New(1200000, "RUB")},
+	}, booking.PriceBreakdown)
 }
 
-func (m *MockHotelClient) GetRoomPrice(ctx context.Context, hotelID, roomID string) (float64, error) {
-	if m.GetRoomPriceFunc != nil {
-		return m.GetRoomPriceFunc(ctx, hotelID, roomID)
+func TestCreateBooking_ChargesTaxesAndFees(t *testing.T) {
+	mockRepo := new(MockBookingRepository)
+	mockClient := &MockHotelClient{
+		GetQuoteFunc: func(ctx context.Context, hotelID, roomID string, checkIn, checkOut time.Time, adults, children int) (*hotelclient.Quote, error) {
+			return &hotelclient.Quote{
+				Nights: []hotelclient.NightlyRate{
+					{Date: time.Date(2030, 12, 20, 0, 0, 0, 0, time.UTC), Price: money.New(500000, "RUB")},
+					{Date: time.Date(2030, 12, 21, 0, 0, 0, 0, time.UTC), Price: money.New(500000, "RUB")},
+				},
+				Subtotal: money.New(1000000, "RUB"),
+				Fees: []hotelclient.Fee{
+					{Name: "Туристический налог", Kind: "tax", Amount: money.New(20000, "RUB")},
+					{Name: "Уборка", Kind: "fee", Amount: money.New(100000, "RUB")},
+				},
+				Total: money.New(1120000, "RUB"),
+			}, nil
+		},
+	}
+	var charged money.Money
+	mockPayment := &MockPaymentService{
+		CreatePaymentFunc: func(ctx context.Context, bookingID string, amount money.Money) error {
+			charged = amount
+			return nil
+		},
 	}
-	return 0, nil
+	booking := &domain.Booking{
+		UserID:       "user123",
+		HotelID:      "hotel123",
+		RoomID:       "room123",
+		CheckInDate:  time.Date(2030, 12, 20, 14, 0, 0, 0, time.UTC),
+		CheckOutDate: time.Date(2030, 12, 22, 12, 0, 0, 0, time.UTC),
+	}
+
+	mockSagas := new(MockSagaRepository)
+	mockRepo.On("HasOverlappingBooking", mock.Anything, "room123", booking.CheckInDate, booking.CheckOutDate, "").Return(false, nil)
+	expectReservation(mockRepo, mockSagas)
+	mockRepo.On("UpdateBookingStatus", mock.Anything, mock.Anything, domain.StatusPending, domain.StatusAwaitingPayment, mock.Anything).Return(nil)
+
+	uc := NewBookingUseCase(mockRepo, mockSagas, nil, mockClient, mockPayment, nil, nil, nil, 0, 0, nil)
+
+	err := uc.CreateBooking(context.Background(), booking)
+	assert.NoError(t, err)
+	assert.Equal(t, []domain.PriceItem{
+		{Kind: domain.PriceItemAccommodation, Amount: money.New(1000000, "RUB")},
+		{Kind: domain.PriceItemTax, Name: "Туристический налог", Amount: money.New(20000, "RUB")},
+		{Kind: domain.PriceItemFee, Name: "Уборка", Amount: money.New(100000, "RUB")},
+	}, booking.PriceBreakdown)
+	assert.Equal(t, money.New(1120000, "RUB"), booking.TotalPrice)
+	assert.Equal(t, money.New(1120000, "RUB"), charged)
 }
 
-func (m *MockHotelClient) Close() error {
-	return nil
+func TestCreateBooking_SnapshotsCancellationPolicy(t *testing.T) {
+	mockRepo := new(MockBookingRepository)
+	mockClient := &MockHotelClient{
+		GetQuoteFunc: func(ctx context.Context, hotelID, roomID string, checkIn, checkOut time.Time, adults, children int) (*hotelclient.Quote, error) {
+			quote, err := flatQuote(money.New(500000, "RUB"))(ctx, hotelID, roomID, checkIn, checkOut, adults, children)
+			quote.CancellationPolicy = &hotelclient.CancellationPolicy{
+				ID:    "policy123",
+				Name:  "Гибкий",
+				Rules: []hotelclient.CancellationRule{{HoursBeforeCheckIn: 48, RefundBasisPoints: 10000}},
+			}
+			return quote, err
+		},
+	}
+	booking := &domain.Booking{
+		UserID:       "user123",
+		HotelID:      "hotel123",
+		RoomID:       "room123",
+		CheckInDate:  time.Date(2030, 12, 20, 14, 0, 0, 0, time.UTC),
+		CheckOutDate: time.Date(2030, 12, 22, 12, 0, 0, 0, time.UTC),
+	}
+
+	mockSagas := new(MockSagaRepository)
+	mockRepo.On("HasOverlappingBooking", mock.Anything, "room123", booking.CheckInDate, booking.CheckOutDate, "").Return(false, nil)
+	expectReservation(mockRepo, mockSagas)
+	mockRepo.On("UpdateBookingStatus", mock.Anything, mock.Anything, domain.StatusPending, domain.StatusConfirmed, mock.Anything).Return(nil)
+
+	uc := NewBookingUseCase(mockRepo, mockSagas, nil, mockClient, nil, nil, nil, nil, 0, 0, nil)
+
+	err := uc.CreateBooking(context.Background(), booking)
+	assert.NoError(t, err)
+	assert.Equal(t, &domain.CancellationPolicy{
+		ID:    "policy123",
+		Name:  "Гибкий",
+		Rules: []domain.CancellationRule{{HoursBeforeCheckIn: 48, RefundBasisPoints: 10000}},
+	}, booking.CancellationPolicy)
+}
+
+func TestCreateBooking_QuoteFails(t *testing.T) {
+	mockRepo := new(MockBookingRepository)
+	mockClient := &MockHotelClient{
+		GetQuoteFunc: func(ctx context.Context, hotelID, roomID string, checkIn, checkOut time.Time, adults, children int) (*hotelclient.Quote, error) {
+			return nil, errors.New("hotel service returned status 404")
+		},
+	}
+	booking := &domain.Booking{
+		UserID:       "user123",
+		HotelID:      "hotel123",
+		RoomID:       "room123",
+		CheckInDate:  time.Now().AddDate(0, 0, 1),
+		CheckOutDate: time.Now().AddDate(0, 0, 2),
+	}
+	mockRepo.On("HasOverlappingBooking", mock.Anything, "room123", booking.CheckInDate, booking.CheckOutDate, "").Return(false, nil)
+
+	uc := NewBookingUseCase(mockRepo, nil, nil, mockClient, nil, nil, nil, nil, 0, 0, nil)
+
+	err := uc.CreateBooking(context.Background(), booking)
+	assert.Error(t, err)
+	mockRepo.AssertNotCalled(t, "CreateBooking", mock.Anything, mock.Anything)
+}
+
+func TestCreateBooking_WithPaymentAwaitsPayment(t *testing.T) {
+	mockRepo := new(MockBookingRepository)
+	mockClient := &MockHotelClient{
+		GetQuoteFunc: flatQuote(money.New(500000, "RUB")),
+	}
+	mockPayment := &MockPaymentService{
+		CreatePaymentFunc: func(ctx context.Context, bookingID string, amount money.Money) error {
+			return nil
+		},
+	}
+
+	booking := &domain.Booking{
+		UserID:       "user123",
+		HotelID:      "hotel123",
+		RoomID:       "room123",
+		CheckInDate:  time.Now().AddDate(0, 0, 1),
+		CheckOutDate: time.Now().AddDate(0, 0, 3),
+	}
+
+	mockSagas := new(MockSagaRepository)
+	mockRepo.On("HasOverlappingBooking", mock.Anything, "room123", booking.CheckInDate, booking.CheckOutDate, "").Return(false, nil)
+	expectReservation(mockRepo, mockSagas)
+	mockRepo.On("UpdateBookingStatus", mock.Anything, mock.Anything, domain.StatusPending, domain.StatusAwaitingPayment, mock.Anything).Return(nil)
+
+	uc := NewBookingUseCase(mockRepo, mockSagas, nil, mockClient, mockPayment, nil, nil, nil, 0, 0, nil)
+
+	err := uc.CreateBooking(context.Background(), booking)
+	assert.NoError(t, err)
+	assert.Equal(t, domain.StatusAwaitingPayment, booking.Status)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestCreateBooking_ChargesInDisplayCurrency(t *testing.T) {
+	mockRepo := new(MockBookingRepository)
+	mockClient := &MockHotelClient{
+		GetQuoteFunc: flatQuote(money.New(500000, "RUB")),
+	}
+	var charged money.Money
+	mockPayment := &MockPaymentService{
+		CreatePaymentFunc: func(ctx context.Context, bookingID string, amount money.Money) error {
+			charged = amount
+			return nil
+		},
+	}
+	rate, err := money.ParseRate("RUB", "USD", "0.01081081")
+	require.NoError(t, err)
+	mockRates := new(MockRateProvider)
+	mockRates.On("GetRate", mock.Anything, "RUB", "USD", mock.Anything).Return(rate, nil)
+
+	booking := &domain.Booking{
+		UserID:          "user123",
+		HotelID:         "hotel123",
+		RoomID:          "room123",
+		CheckInDate:     time.Now().AddDate(0, 0, 1),
+		CheckOutDate:    time.Now().AddDate(0, 0, 3),
+		DisplayCurrency: "USD",
+	}
+
+	mockSagas := new(MockSagaRepository)
+	mockRepo.On("HasOverlappingBooking", mock.Anything, "room123", booking.CheckInDate, booking.CheckOutDate, "").Return(false, nil)
+	expectReservation(mockRepo, mockSagas)
+	mockRepo.On("UpdateBookingStatus", mock.Anything, mock.Anything, domain.StatusPending, domain.StatusAwaitingPayment, mock.Anything).Return(nil)
+
+	uc := NewBookingUseCase(mockRepo, mockSagas, nil, mockClient, mockPayment, mockRates, nil, nil, 0, 0, nil)
+
+	err = uc.CreateBooking(context.Background(), booking)
+	assert.NoError(t, err)
+	assert.Equal(t, money.New(1000000, "RUB"), booking.TotalPrice)
+	assert.Equal(t, money.New(10811, "USD"), booking.DisplayPrice)
+	assert.Equal(t, rate, booking.ExchangeRate)
+	assert.Equal(t, money.New(10811, "USD"), charged)
+	mockRates.AssertExpectations(t)
+}
+
+func TestCreateBooking_SameCurrencySkipsRateLookup(t *testing.T) {
+	mockRepo := new(MockBookingRepository)
+	mockClient := &MockHotelClient{
+		GetQuoteFunc: flatQuote(money.New(500000, "RUB")),
+	}
+
+	booking := &domain.Booking{
+		UserID:       "user123",
+		HotelID:      "hotel123",
+		RoomID:       "room123",
+		CheckInDate:  time.Now().AddDate(0, 0, 1),
+		CheckOutDate: time.Now().AddDate(0, 0, 2),
+	}
+
+	mockSagas := new(MockSagaRepository)
+	mockRepo.On("HasOverlappingBooking", mock.Anything, "room123", booking.CheckInDate, booking.CheckOutDate, "").Return(false, nil)
+	expectReservation(mockRepo, mockSagas)
+	mockRepo.On("UpdateBookingStatus", mock.Anything, mock.Anything, domain.StatusPending, domain.StatusConfirmed, mock.Anything).Return(nil)
+
+	uc := NewBookingUseCase(mockRepo, mockSagas, nil, mockClient, nil, new(MockRateProvider), nil, nil, 0, 0, nil)
+
+	err := uc.CreateBooking(context.Background(), booking)
+	assert.NoError(t, err)
+	assert.Equal(t, "RUB", booking.DisplayCurrency)
+	assert.Equal(t, booking.TotalPrice, booking.DisplayPrice)
+	assert.Equal(t, money.IdentityRate("RUB"), booking.ExchangeRate)
+}
+
+func TestCreateBooking_RateNotFound(t *testing.T) {
+	mockRepo := new(MockBookingRepository)
+	mockClient := &MockHotelClient{
+		GetQuoteFunc: flatQuote(money.New(500000, "RUB")),
+	}
+	mockRates := new(MockRateProvider)
+	mockRates.On("GetRate", mock.Anything, "RUB", "CHF", mock.Anything).Return(money.Rate{}, domain.ErrRateNotFound)
+
+	booking := &domain.Booking{
+		UserID:          "user123",
+		HotelID:         "hotel123",
+		RoomID:          "room123",
+		CheckInDate:     time.Now().AddDate(0, 0, 1),
+		CheckOutDate:    time.Now().AddDate(0, 0, 2),
+		DisplayCurrency: "CHF",
+	}
+	mockRepo.On("HasOverlappingBooking", mock.Anything, "room123", booking.CheckInDate, booking.CheckOutDate, "").Return(false, nil)
+
+	uc := NewBookingUseCase(mockRepo, nil, nil, mockClient, nil, mockRates, nil, nil, 0, 0, nil)
+
+	err := uc.CreateBooking(context.Background(), booking)
+	assert.ErrorIs(t, err, domain.ErrRateNotFound)
+	mockRepo.AssertNotCalled(t, "CreateBooking", mock.Anything, mock.Anything)
+}
+
+func TestCreateBooking_InvalidDates(t *testing.T) {
+	mockRepo := new(MockBookingRepository)
+	mockClient := &MockHotelClient{}
+
+	booking := &domain.Booking{
+		UserID:       "user123",
+		HotelID:      "hotel123",
+		RoomID:       "room123",
+		CheckInDate:  time.Now().AddDate(0, 0, 3),
+		CheckOutDate: time.Now().AddDate(0, 0, 1),
+	}
+
+	uc := &BookingUseCase{
+		repo:        mockRepo,
+		hotelClient: mockClient,
+	}
+
+	err := uc.CreateBooking(context.Background(), booking)
+	assert.Error(t, err)
 }
 
-type MockProducer struct {
-	SendMessageFunc func(ctx context.Context, key string, value interface{}) error
-}
+func TestCreateBooking_SameDayDates(t *testing.T) {
+	mockRepo := new(MockBookingRepository)
+	checkIn := time.Now().AddDate(0, 0, 1)
 
-func (m *MockProducer) SendMessage(ctx context.Context, key string, value interface{}) error {
-	if m.SendMessageFunc != nil {
-		return m.SendMessageFunc(ctx, key, value)
+	booking := &domain.Booking{
+		UserID:       "user123",
+		HotelID:      "hotel123",
+		RoomID:       "room123",
+		CheckInDate:  checkIn,
+		CheckOutDate: checkIn,
 	}
-	return nil
-}
 
-func (m *MockProducer) Close() error {
-	return nil
+	uc := &BookingUseCase{
+		repo:        mockRepo,
+		hotelClient: &MockHotelClient{},
+	}
+
+	err := uc.CreateBooking(context.Background(), booking)
+	assert.ErrorIs(t, err, domain.ErrInvalidDates)
+	mockRepo.AssertNotCalled(t, "HasOverlappingBooking")
 }
 
-func TestCreateBooking_Success(t *testing.T) {
+func TestCreateBooking_RoomAlreadyBooked(t *testing.T) {
 	mockRepo := new(MockBookingRepository)
+	priceRequested := false
 	mockClient := &MockHotelClient{
-		GetRoomPriceFunc: func(ctx context.Context, hotelID, roomID string) (float64, error) {
-			return 5000.0, nil
-		},
-	}
-	mockProducer := &MockProducer{
-		SendMessageFunc: func(ctx context.Context, key string, value interface{}) error {
-			return nil
+		GetQuoteFunc: func(ctx context.Context, hotelID, roomID string, checkIn, checkOut time.Time, adults, children int) (*hotelclient.Quote, error) {
+			priceRequested = true
+			return flatQuote(money.New(500000, "RUB"))(ctx, hotelID, roomID, checkIn, checkOut, adults, children)
 		},
 	}
 
@@ -100,50 +669,126 @@ func TestCreateBooking_Success(t *testing.T) {
 		CheckOutDate: time.Now().AddDate(0, 0, 3),
 	}
 
-	mockRepo.On("CreateBooking", mock.Anything, mock.Anything).Return(nil)
+	mockRepo.On("HasOverlappingBooking", mock.Anything, "room123", booking.CheckInDate, booking.CheckOutDate, "").Return(true, nil)
 
 	uc := &BookingUseCase{
 		repo:        mockRepo,
 		hotelClient: mockClient,
-		producer:    mockProducer,
 	}
 
 	err := uc.CreateBooking(context.Background(), booking)
-	assert.NoError(t, err)
-	assert.NotEmpty(t, booking.ID)
-	assert.Equal(t, "confirmed", booking.Status)
-	assert.Equal(t, "pending", booking.PaymentStatus)
-	assert.Greater(t, booking.TotalPrice, 0.0)
+	assert.ErrorIs(t, err, domain.ErrRoomNotAvailable)
+	assert.False(t, priceRequested)
+	mockRepo.AssertNotCalled(t, "CreateBooking", mock.Anything, mock.Anything)
 	mockRepo.AssertExpectations(t)
 }
 
-func TestCreateBooking_InvalidDates(t *testing.T) {
+func TestCreateBooking_Guests(t *testing.T) {
+	tests := []struct {
+		name         string
+		adults       int
+		children     int
+		quoteErr     error
+		wantAdults   int
+		wantChildren int
+		want         error
+	}{
+		{name: "one adult by default", wantAdults: 1},
+		{name: "adults and children", adults: 2, children: 1, wantAdults: 2, wantChildren: 1},
+		{name: "children without adults", children: 2, want: domain.ErrInvalidGuests},
+		{name: "negative children", adults: 1, children: -1, want: domain.ErrInvalidGuests},
+		{name: "more guests than the room holds", adults: 6, wantAdults: 6, quoteErr: hotelclient.ErrCapacityExceeded, want: domain.ErrCapacityExceeded},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := new(MockBookingRepository)
+			mockSagas := new(MockSagaRepository)
+			mockRepo.On("HasOverlappingBooking", mock.Anything, "room123", mock.Anything, mock.Anything, "").Return(false, nil).Maybe()
+			expectReservation(mockRepo, mockSagas)
+			mockRepo.On("UpdateBookingStatus", mock.Anything, mock.Anything, domain.StatusPending, domain.StatusConfirmed, mock.Anything).Return(nil).Maybe()
+
+			var quotedAdults, quotedChildren int
+			mockClient := &MockHotelClient{
+				GetQuoteFunc: func(ctx context.Context, hotelID, roomID string, checkIn, checkOut time.Time, adults, children int) (*hotelclient.Quote, error) {
+					quotedAdults, quotedChildren = adults, children
+					if tt.quoteErr != nil {
+						return nil, fmt.Errorf("%w: hotel service returned status 422", tt.quoteErr)
+					}
+					return flatQuote(money.New(500000, "RUB"))(ctx, hotelID, roomID, checkIn, checkOut, adults, children)
+				},
+			}
+
+			booking := &domain.Booking{
+				UserID:       "user123",
+				HotelID:      "hotel123",
+				RoomID:       "room123",
+				Adults:       tt.adults,
+				Children:     tt.children,
+				CheckInDate:  time.Now().AddDate(0, 0, 1),
+				CheckOutDate: time.Now().AddDate(0, 0, 3),
+			}
+
+			uc := &BookingUseCase{
+				repo:        mockRepo,
+				sagas:       mockSagas,
+				hotelClient: mockClient,
+			}
+
+			err := uc.CreateBooking(context.Background(), booking)
+			if tt.want != nil {
+				assert.ErrorIs(t, err, tt.want)
+				mockRepo.AssertNotCalled(t, "CreateBooking", mock.Anything, mock.Anything)
+			} else {
+				require.NoError(t, err)
+			}
+			assert.Equal(t, tt.wantAdults, quotedAdults)
+			assert.Equal(t, tt.wantChildren, quotedChildren)
+		})
+	}
+}
+
+func TestCreateBooking_ConcurrentConflict(t *testing.T) {
 	mockRepo := new(MockBookingRepository)
-	mockClient := &MockHotelClient{}
-	mockProducer := &MockProducer{}
+	mockClient := &MockHotelClient{
+		GetQuoteFunc: flatQuote(money.New(500000, "RUB")),
+	}
+	paymentCreated := false
+	mockPayment := &MockPaymentService{
+		CreatePaymentFunc: func(ctx context.Context, bookingID string, amount money.Money) error {
+			paymentCreated = true
+			return nil
+		},
+	}
 
 	booking := &domain.Booking{
 		UserID:       "user123",
 		HotelID:      "hotel123",
 		RoomID:       "room123",
-		CheckInDate:  time.Now().AddDate(0, 0, 3),
-		CheckOutDate: time.Now().AddDate(0, 0, 1),
+		CheckInDate:  time.Now().AddDate(0, 0, 1),
+		CheckOutDate: time.Now().AddDate(0, 0, 3),
 	}
 
-	uc := &BookingUseCase{
-		repo:        mockRepo,
-		hotelClient: mockClient,
-		producer:    mockProducer,
-	}
+	mockSagas := new(MockSagaRepository)
+	mockRepo.On("HasOverlappingBooking", mock.Anything, "room123", booking.CheckInDate, booking.CheckOutDate, "").Return(false, nil)
+	mockRepo.On("CreateBooking", mock.Anything, mock.Anything).Return(domain.ErrRoomNotAvailable)
+	mockSagas.On("CreateSaga", mock.Anything, mock.Anything).Return(nil)
+	mockSagas.On("UpdateSaga", mock.Anything, mock.MatchedBy(func(saga domain.BookingSaga) bool {
+		return saga.Step == domain.SagaStepReserve && saga.Status == domain.SagaCompensated
+	})).Return(nil)
+
+	uc := NewBookingUseCase(mockRepo, mockSagas, nil, mockClient, mockPayment, nil, nil, nil, 0, 0, nil)
 
 	err := uc.CreateBooking(context.Background(), booking)
-	assert.Error(t, err)
+	assert.ErrorIs(t, err, domain.ErrRoomNotAvailable)
+	assert.False(t, paymentCreated)
+	mockRepo.AssertExpectations(t)
+	mockSagas.AssertExpectations(t)
 }
 
 func TestGetBooking_Success(t *testing.T) {
 	mockRepo := new(MockBookingRepository)
 	mockClient := &MockHotelClient{}
-	mockProducer := &MockProducer{}
 
 	expectedBooking := &domain.Booking{
 		ID:      "booking123",
@@ -156,7 +801,6 @@ func TestGetBooking_Success(t *testing.T) {
 	uc := &BookingUseCase{
 		repo:        mockRepo,
 		hotelClient: mockClient,
-		producer:    mockProducer,
 	}
 
 	booking, err := uc.GetBooking(context.Background(), "booking123")
@@ -168,7 +812,6 @@ func TestGetBooking_Success(t *testing.T) {
 func TestGetBookingsByUser_Success(t *testing.T) {
 	mockRepo := new(MockBookingRepository)
 	mockClient := &MockHotelClient{}
-	mockProducer := &MockProducer{}
 
 	expectedBookings := []domain.Booking{
 		{ID: "booking1", UserID: "user123"},
@@ -180,7 +823,6 @@ func TestGetBookingsByUser_Success(t *testing.T) {
 	uc := &BookingUseCase{
 		repo:        mockRepo,
 		hotelClient: mockClient,
-		producer:    mockProducer,
 	}
 
 	bookings, err := uc.GetBookingsByUser(context.Background(), "user123")
@@ -192,7 +834,6 @@ func TestGetBookingsByUser_Success(t *testing.T) {
 func TestGetBookingsByHotel_Success(t *testing.T) {
 	mockRepo := new(MockBookingRepository)
 	mockClient := &MockHotelClient{}
-	mockProducer := &MockProducer{}
 
 	expectedBookings := []domain.Booking{
 		{ID: "booking1", HotelID: "hotel123"},
@@ -204,7 +845,6 @@ func TestGetBookingsByHotel_Success(t *testing.T) {
 	uc := &BookingUseCase{
 		repo:        mockRepo,
 		hotelClient: mockClient,
-		producer:    mockProducer,
 	}
 
 	bookings, err := uc.GetBookingsByHotel(context.Background(), "hotel123")
@@ -216,47 +856,265 @@ func TestGetBookingsByHotel_Success(t *testing.T) {
 func TestUpdatePaymentStatus_Success(t *testing.T) {
 	mockRepo := new(MockBookingRepository)
 	mockClient := &MockHotelClient{}
-	mockProducer := &MockProducer{}
 
-	mockRepo.On("UpdatePaymentStatus", mock.Anything, "booking123", "paid").Return(nil)
+	mockRepo.On("GetBookingByID", mock.Anything, "booking123").Return(&domain.Booking{
+		ID:            "booking123",
+		Status:        domain.StatusAwaitingPayment,
+		PaymentStatus: domain.PaymentPending,
+	}, nil)
+	mockRepo.On("UpdatePaymentStatus", mock.Anything, "booking123", domain.PaymentPending, domain.PaymentPaid).Return(nil)
+	mockRepo.On("UpdateBookingStatus", mock.Anything, "booking123", domain.StatusAwaitingPayment, domain.StatusConfirmed, mock.Anything).Return(nil)
 
 	uc := &BookingUseCase{
 		repo:        mockRepo,
 		hotelClient: mockClient,
-		producer:    mockProducer,
 	}
 
-	err := uc.UpdatePaymentStatus(context.Background(), "booking123", "paid")
+	err := uc.UpdatePaymentStatus(context.Background(), "booking123", "", "paid")
+	assert.NoError(t, err)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestUpdatePaymentStatus_AuthorizedConfirmsBooking(t *testing.T) {
+	mockRepo := new(MockBookingRepository)
+
+	mockRepo.On("GetBookingByID", mock.Anything, "booking123").Return(&domain.Booking{
+		ID:            "booking123",
+		Status:        domain.StatusAwaitingPayment,
+		PaymentStatus: domain.PaymentPending,
+	}, nil)
+	mockRepo.On("UpdatePaymentStatus", mock.Anything, "booking123", domain.PaymentPending, domain.PaymentAuthorized).Return(nil)
+	mockRepo.On("UpdateBookingStatus", mock.Anything, "booking123", domain.StatusAwaitingPayment, domain.StatusConfirmed, mock.Anything).Return(nil)
+
+	uc := NewBookingUseCase(mockRepo, nil, nil, &MockHotelClient{}, nil, nil, nil, nil, 0, 0, nil)
+
+	err := uc.UpdatePaymentStatus(context.Background(), "booking123", "", "authorized")
 	assert.NoError(t, err)
 	mockRepo.AssertExpectations(t)
 }
 
+func TestUpdatePaymentStatus_ExtraChargeKeepsPartialRefund(t *testing.T) {
+	mockRepo := new(MockBookingRepository)
+
+	mockRepo.On("GetBookingByID", mock.Anything, "booking123").Return(&domain.Booking{
+		ID:            "booking123",
+		Status:        domain.StatusConfirmed,
+		PaymentStatus: domain.PaymentPartiallyRefunded,
+	}, nil)
+
+	uc := NewBookingUseCase(mockRepo, nil, nil, &MockHotelClient{}, nil, nil, nil, nil, 0, 0, nil)
+
+	err := uc.UpdatePaymentStatus(context.Background(), "booking123", "", "paid")
+	assert.NoError(t, err)
+	mockRepo.AssertNotCalled(t, "UpdatePaymentStatus", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
 func TestUpdatePaymentStatus_InvalidStatus(t *testing.T) {
 	mockRepo := new(MockBookingRepository)
 	mockClient := &MockHotelClient{}
-	mockProducer := &MockProducer{}
 
 	uc := &BookingUseCase{
 		repo:        mockRepo,
 		hotelClient: mockClient,
-		producer:    mockProducer,
 	}
 
-	err := uc.UpdatePaymentStatus(context.Background(), "booking123", "invalid")
-	assert.Error(t, err)
+	err := uc.UpdatePaymentStatus(context.Background(), "booking123", "", "invalid")
+	assert.ErrorIs(t, err, domain.ErrInvalidPaymentStatus)
+}
+
+func TestUpdatePaymentStatus_FailedCancelsBooking(t *testing.T) {
+	mockRepo := new(MockBookingRepository)
+
+	mockRepo.On("GetBookingByID", mock.Anything, "booking123").Return(&domain.Booking{
+		ID:            "booking123",
+		Status:        domain.StatusAwaitingPayment,
+		PaymentStatus: domain.PaymentPending,
+	}, nil)
+	mockRepo.On("UpdatePaymentStatus", mock.Anything, "booking123", domain.PaymentPending, domain.PaymentFailed).Return(nil)
+	mockRepo.On("UpdateBookingStatus", mock.Anything, "booking123", domain.StatusAwaitingPayment, domain.StatusCancelled, mock.MatchedBy(func(event *domain.OutboxEvent) bool {
+		return event != nil && event.Topic == "hotel.booking.cancelled" && event.Key == "booking123"
+	})).Return(nil)
+
+	uc := &BookingUseCase{repo: mockRepo, topics: domain.Topics{domain.EventBookingCancelled: "hotel.booking.cancelled"}}
+
+	err := uc.UpdatePaymentStatus(context.Background(), "booking123", "", "failed")
+	assert.NoError(t, err)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestUpdatePaymentStatus_SameStatusIsNoop(t *testing.T) {
+	mockRepo := new(MockBookingRepository)
+
+	mockRepo.On("GetBookingByID", mock.Anything, "booking123").Return(&domain.Booking{
+		ID:            "booking123",
+		Status:        domain.StatusConfirmed,
+		PaymentStatus: domain.PaymentPaid,
+	}, nil)
+
+	uc := &BookingUseCase{repo: mockRepo}
+
+	err := uc.UpdatePaymentStatus(context.Background(), "booking123", "", "paid")
+	assert.NoError(t, err)
+	mockRepo.AssertNotCalled(t, "UpdatePaymentStatus", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestUpdatePaymentStatus_InvalidTransition(t *testing.T) {
+	mockRepo := new(MockBookingRepository)
+
+	mockRepo.On("GetBookingByID", mock.Anything, "booking123").Return(&domain.Booking{
+		ID:            "booking123",
+		Status:        domain.StatusCancelled,
+		PaymentStatus: domain.PaymentRefunded,
+	}, nil)
+
+	uc := &BookingUseCase{repo: mockRepo}
+
+	err := uc.UpdatePaymentStatus(context.Background(), "booking123", "", "paid")
+	assert.ErrorIs(t, err, domain.ErrInvalidTransition)
+	mockRepo.AssertNotCalled(t, "UpdatePaymentStatus", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestUpdatePaymentStatus_ConcurrentChange(t *testing.T) {
+	mockRepo := new(MockBookingRepository)
+
+	mockRepo.On("GetBookingByID", mock.Anything, "booking123").Return(&domain.Booking{
+		ID:            "booking123",
+		Status:        domain.StatusAwaitingPayment,
+		PaymentStatus: domain.PaymentPending,
+	}, nil)
+	mockRepo.On("UpdatePaymentStatus", mock.Anything, "booking123", domain.PaymentPending, domain.PaymentPaid).Return(domain.ErrStatusChanged)
+
+	uc := &BookingUseCase{repo: mockRepo}
+
+	err := uc.UpdatePaymentStatus(context.Background(), "booking123", "", "paid")
+	assert.ErrorIs(t, err, domain.ErrStatusChanged)
+	mockRepo.AssertNotCalled(t, "UpdateBookingStatus", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestUpdatePaymentStatus_LatePaymentOfExpiredBookingIsReturned(t *testing.T) {
+	newBooking := func() *domain.Booking {
+		return &domain.Booking{
+			ID:            "booking123",
+			TotalPrice:    money.New(500000, "RUB"),
+			Status:        domain.StatusExpired,
+			PaymentStatus: domain.PaymentPending,
+		}
+	}
+
+	t.Run("paid is refunded", func(t *testing.T) {
+		mockRepo := new(MockBookingRepository)
+		mockRepo.On("GetBookingByID", mock.Anything, "booking123").Return(newBooking(), nil)
+		mockRepo.On("UpdatePaymentStatus", mock.Anything, "booking123", domain.PaymentPending, domain.PaymentPaid).Return(nil)
+		var refunded *money.Money
+		payments := &MockPaymentService{RefundPaymentFunc: func(ctx context.Context, bookingID string, amount money.Money) error {
+			refunded = &amount
+			return nil
+		}}
+
+		uc := &BookingUseCase{repo: mockRepo, paymentClient: payments}
+
+		err := uc.UpdatePaymentStatus(context.Background(), "booking123", "", "paid")
+		require.NoError(t, err)
+		require.NotNil(t, refunded)
+		assert.Equal(t, money.New(500000, "RUB"), *refunded)
+		mockRepo.AssertNotCalled(t, "UpdateBookingStatus", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("authorized is voided", func(t *testing.T) {
+		mockRepo := new(MockBookingRepository)
+		mockRepo.On("GetBookingByID", mock.Anything, "booking123").Return(newBooking(), nil)
+		mockRepo.On("UpdatePaymentStatus", mock.Anything, "booking123", domain.PaymentPending, domain.PaymentAuthorized).Return(nil)
+		voided := false
+		payments := &MockPaymentService{VoidPaymentFunc: func(ctx context.Context, bookingID string) error {
+			voided = true
+			return nil
+		}}
+
+		uc := &BookingUseCase{repo: mockRepo, paymentClient: payments}
+
+		err := uc.UpdatePaymentStatus(context.Background(), "booking123", "", "authorized")
+		require.NoError(t, err)
+		assert.True(t, voided)
+	})
+
+	t.Run("refund fails", func(t *testing.T) {
+		mockRepo := new(MockBookingRepository)
+		mockRepo.On("GetBookingByID", mock.Anything, "booking123").Return(newBooking(), nil)
+		payments := &MockPaymentService{RefundPaymentFunc: func(ctx context.Context, bookingID string, amount money.Money) error {
+			return errors.New("gateway unavailable")
+		}}
+
+		uc := &BookingUseCase{repo: mockRepo, paymentClient: payments}
+
+		err := uc.UpdatePaymentStatus(context.Background(), "booking123", "", "paid")
+		assert.Error(t, err)
+		mockRepo.AssertNotCalled(t, "UpdatePaymentStatus", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+}
+
+func TestExpireBookings(t *testing.T) {
+	logger.Init("info")
+
+	mockRepo := new(MockBookingRepository)
+	var updatedBefore time.Time
+	mockRepo.On("GetUnpaidBookings", mock.Anything, mock.Anything, 100).
+		Run(func(args mock.Arguments) { updatedBefore = args.Get(1).(time.Time) }).
+		Return([]domain.Booking{
+			{ID: "booking1", Status: domain.StatusAwaitingPayment},
+			{ID: "booking2", Status: domain.StatusAwaitingPayment},
+			{ID: "booking3", Status: domain.StatusAwaitingPayment},
+		}, nil)
+	var events []*domain.OutboxEvent
+	mockRepo.On("UpdateBookingStatus", mock.Anything, "booking1", domain.StatusAwaitingPayment, domain.StatusExpired, mock.Anything).
+		Run(func(args mock.Arguments) { events = append(events, args.Get(4).(*domain.OutboxEvent)) }).
+		Return(nil)
+	mockRepo.On("UpdateBookingStatus", mock.Anything, "booking2", domain.StatusAwaitingPayment, domain.StatusExpired, mock.Anything).
+		Return(domain.ErrStatusChanged)
+	mockRepo.On("UpdateBookingStatus", mock.Anything, "booking3", domain.StatusAwaitingPayment, domain.StatusExpired, mock.Anything).
+		Return(errors.New("connection reset"))
+
+	uc := &BookingUseCase{repo: mockRepo, topics: domain.Topics{domain.EventBookingExpired: "hotel.booking.expired"}}
+
+	count, err := uc.ExpireBookings(context.Background(), 30*time.Minute, 100)
+	require.NoError(t, err)
+	assert.Equal(t, 1, count)
+	assert.WithinDuration(t, time.Now().Add(-30*time.Minute), updatedBefore, time.Minute)
+	mockRepo.AssertExpectations(t)
+
+	require.Len(t, events, 1)
+	assert.Equal(t, "hotel.booking.expired", events[0].Topic)
+	assert.Equal(t, "booking1", events[0].Key)
+	var published domain.BookingEvent
+	require.NoError(t, json.Unmarshal(events[0].Payload, &published))
+	assert.Equal(t, domain.EventBookingExpired, published.EventType)
+}
+
+func TestGetStatusHistory(t *testing.T) {
+	mockRepo := new(MockBookingRepository)
+
+	history := []domain.StatusChange{
+		{ID: "1", BookingID: "booking123", Field: "status", FromStatus: "pending", ToStatus: "awaiting_payment"},
+	}
+	mockRepo.On("GetBookingByID", mock.Anything, "booking123").Return(&domain.Booking{ID: "booking123"}, nil)
+	mockRepo.On("GetStatusHistory", mock.Anything, "booking123").Return(history, nil)
+
+	uc := &BookingUseCase{repo: mockRepo}
+
+	result, err := uc.GetStatusHistory(context.Background(), "booking123")
+	assert.NoError(t, err)
+	assert.Equal(t, history, result)
+	mockRepo.AssertExpectations(t)
 }
 
 func TestGetBooking_NotFound(t *testing.T) {
 	mockRepo := new(MockBookingRepository)
 	mockClient := &MockHotelClient{}
-	mockProducer := &MockProducer{}
 
 	mockRepo.On("GetBookingByID", mock.Anything, "booking123").Return(nil, errors.New("not found"))
 
 	uc := &BookingUseCase{
 		repo:        mockRepo,
 		hotelClient: mockClient,
-		producer:    mockProducer,
 	}
 
 	booking, err := uc.GetBooking(context.Background(), "booking123")
@@ -264,3 +1122,340 @@ func TestGetBooking_NotFound(t *testing.T) {
 	assert.Nil(t, booking)
 	mockRepo.AssertExpectations(t)
 }
+
+func TestGetBookedRoomIDs(t *testing.T) {
+	checkIn := time.Date(2024, 12, 20, 0, 0, 0, 0, time.UTC)
+	checkOut := checkIn.AddDate(0, 0, 3)
+
+	t.Run("success", func(t *testing.T) {
+		mockRepo := new(MockBookingRepository)
+		mockRepo.On("GetBookedRoomIDs", mock.Anything, "hotel123", checkIn, checkOut).Return([]string{"room1", "room2"}, nil)
+
+		uc := &BookingUseCase{repo: mockRepo}
+
+		roomIDs, err := uc.GetBookedRoomIDs(context.Background(), "hotel123", checkIn, checkOut)
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"room1", "room2"}, roomIDs)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("invalid range", func(t *testing.T) {
+		mockRepo := new(MockBookingRepository)
+		uc := &BookingUseCase{repo: mockRepo}
+
+		_, err := uc.GetBookedRoomIDs(context.Background(), "hotel123", checkOut, checkIn)
+		assert.ErrorIs(t, err, domain.ErrInvalidDates)
+		mockRepo.AssertNotCalled(t, "GetBookedRoomIDs")
+	})
+}
+
+func TestCancelBooking_PaidBookingIsRefunded(t *testing.T) {
+	mockRepo := new(MockBookingRepository)
+
+	var refundedAmount money.Money
+	mockPayment := &MockPaymentService{
+		RefundPaymentFunc: func(ctx context.Context, bookingID string, amount money.Money) error {
+			refundedAmount = amount
+			return nil
+		},
+	}
+
+	mockRepo.On("GetBookingByID", mock.Anything, "booking123").Return(&domain.Booking{
+		ID:            "booking123",
+		UserID:        "user123",
+		HotelID:       "hotel123",
+		TotalPrice:    money.New(1000000, "RUB"),
+		Status:        domain.StatusConfirmed,
+		PaymentStatus: domain.PaymentPaid,
+	}, nil)
+	var outboxEvent *domain.OutboxEvent
+	mockRepo.On("UpdateBookingStatus", mock.Anything, "booking123", domain.StatusConfirmed, domain.StatusCancelled, mock.Anything).
+		Run(func(args mock.Arguments) { outboxEvent = args.Get(4).(*domain.OutboxEvent) }).
+		Return(nil)
+
+	uc := NewBookingUseCase(mockRepo, nil, nil, &MockHotelClient{}, mockPayment, nil, nil, nil, 0, 0, nil)
+
+	booking, err := uc.CancelBooking(context.Background(), "booking123")
+	assert.NoError(t, err)
+	assert.Equal(t, domain.StatusCancelled, booking.Status)
+	assert.Equal(t, money.New(1000000, "RUB"), refundedAmount)
+
+	require.NotNil(t, outboxEvent)
+	assert.Equal(t, domain.EventBookingCancelled, outboxEvent.Topic)
+	var publishedEvent domain.BookingEvent
+	require.NoError(t, json.Unmarshal(outboxEvent.Payload, &publishedEvent))
+	assert.Equal(t, domain.EventBookingCancelled, publishedEvent.EventType)
+	require.NotNil(t, publishedEvent.RefundAmount)
+	assert.Equal(t, money.New(1000000, "RUB"), *publishedEvent.RefundAmount)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestCancelBooking_PartiallyRefundedBookingIsRefunded(t *testing.T) {
+	mockRepo := new(MockBookingRepository)
+
+	var refundedAmount money.Money
+	mockPayment := &MockPaymentService{
+		RefundPaymentFunc: func(ctx context.Context, bookingID string, amount money.Money) error {
+			refundedAmount = amount
+			return nil
+		},
+	}
+
+	mockRepo.On("GetBookingByID", mock.Anything, "booking123").Return(&domain.Booking{
+		ID:            "booking123",
+		TotalPrice:    money.New(800000, "RUB"),
+		Status:        domain.StatusConfirmed,
+		PaymentStatus: domain.PaymentPartiallyRefunded,
+	}, nil)
+	mockRepo.On("UpdateBookingStatus", mock.Anything, "booking123", domain.StatusConfirmed, domain.StatusCancelled, mock.Anything).Return(nil)
+
+	uc := NewBookingUseCase(mockRepo, nil, nil, &MockHotelClient{}, mockPayment, nil, nil, nil, 0, 0, nil)
+
+	_, err := uc.CancelBooking(context.Background(), "booking123")
+	assert.NoError(t, err)
+	assert.Equal(t, money.New(800000, "RUB"), refundedAmount)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestCancelBooking_RefundsInDisplayCurrency(t *testing.T) {
+	mockRepo := new(MockBookingRepository)
+
+	var refundedAmount money.Money
+	mockPayment := &MockPaymentService{
+		RefundPaymentFunc: func(ctx context.Context, bookingID string, amount money.Money) error {
+			refundedAmount = amount
+			return nil
+		},
+	}
+	rate, err := money.ParseRate("RUB", "EUR", "0.01")
+	require.NoError(t, err)
+
+	mockRepo.On("GetBookingByID", mock.Anything, "booking123").Return(&domain.Booking{
+		ID:              "booking123",
+		TotalPrice:      money.New(1000000, "RUB"),
+		DisplayCurrency: "EUR",
+		DisplayPrice:    money.New(10000, "EUR"),
+		ExchangeRate:    rate,
+		Status:          domain.StatusConfirmed,
+		PaymentStatus:   domain.PaymentPaid,
+	}, nil)
+	mockRepo.On("UpdateBookingStatus", mock.Anything, "booking123", domain.StatusConfirmed, domain.StatusCancelled, mock.Anything).Return(nil)
+
+	uc := NewBookingUseCase(mockRepo, nil, nil, &MockHotelClient{}, mockPayment, nil, nil, nil, 0, 0, nil)
+
+	_, err = uc.CancelBooking(context.Background(), "booking123")
+	assert.NoError(t, err)
+	assert.Equal(t, money.New(10000, "EUR"), refundedAmount)
+}
+
+func TestCancelBooking_UnpaidBookingIsNotRefunded(t *testing.T) {
+	mockRepo := new(MockBookingRepository)
+
+	refunded := false
+	mockPayment := &MockPaymentService{
+		RefundPaymentFunc: func(ctx context.Context, bookingID string, amount money.Money) error {
+			refunded = true
+			return nil
+		},
+	}
+
+	mockRepo.On("GetBookingByID", mock.Anything, "booking123").Return(&domain.Booking{
+		ID:            "booking123",
+		Status:        domain.StatusConfirmed,
+		PaymentStatus: domain.PaymentPending,
+	}, nil)
+	mockRepo.On("UpdateBookingStatus", mock.Anything, "booking123", domain.StatusConfirmed, domain.StatusCancelled, mock.Anything).Return(nil)
+
+	uc := NewBookingUseCase(mockRepo, nil, nil, &MockHotelClient{}, mockPayment, nil, nil, nil, 0, 0, nil)
+
+	booking, err := uc.CancelBooking(context.Background(), "booking123")
+	assert.NoError(t, err)
+	assert.Equal(t, domain.StatusCancelled, booking.Status)
+	assert.False(t, refunded)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestCancelBooking_AuthorizedBookingIsVoided(t *testing.T) {
+	mockRepo := new(MockBookingRepository)
+
+	voided, refunded := false, false
+	mockPayment := &MockPaymentService{
+		VoidPaymentFunc: func(ctx context.Context, bookingID string) error {
+			voided = bookingID == "booking123"
+			return nil
+		},
+		RefundPaymentFunc: func(ctx context.Context, bookingID string, amount money.Money) error {
+			refunded = true
+			return nil
+		},
+	}
+
+	mockRepo.On("GetBookingByID", mock.Anything, "booking123").Return(&domain.Booking{
+		ID:            "booking123",
+		TotalPrice:    money.New(1000000, "RUB"),
+		Status:        domain.StatusConfirmed,
+		PaymentStatus: domain.PaymentAuthorized,
+	}, nil)
+	mockRepo.On("UpdateBookingStatus", mock.Anything, "booking123", domain.StatusConfirmed, domain.StatusCancelled, mock.Anything).Return(nil)
+
+	uc := NewBookingUseCase(mockRepo, nil, nil, &MockHotelClient{}, mockPayment, nil, nil, nil, 0, 0, nil)
+
+	booking, err := uc.CancelBooking(context.Background(), "booking123")
+	assert.NoError(t, err)
+	assert.Equal(t, domain.StatusCancelled, booking.Status)
+	assert.True(t, voided)
+	assert.False(t, refunded)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestCancelBooking_RefundsWhatThePolicyAllows(t *testing.T) {
+	mockRepo := new(MockBookingRepository)
+
+	var refundedAmount money.Money
+	mockPayment := &MockPaymentService{
+		RefundPaymentFunc: func(ctx context.Context, bookingID string, amount money.Money) error {
+			refundedAmount = amount
+			return nil
+		},
+	}
+
+	mockRepo.On("GetBookingByID", mock.Anything, "booking123").Return(&domain.Booking{
+		ID:          "booking123",
+		CheckInDate: time.Now().Add(24 * time.Hour),
+		TotalPrice:  money.New(1000000, "RUB"),
+		CancellationPolicy: &domain.CancellationPolicy{Rules: []domain.CancellationRule{
+			{HoursBeforeCheckIn: 48, RefundBasisPoints: 10000},
+			{HoursBeforeCheckIn: 0, RefundBasisPoints: 5000},
+		}},
+		Status:        domain.StatusConfirmed,
+		PaymentStatus: domain.PaymentPaid,
+	}, nil)
+	mockRepo.On("UpdateBookingStatus", mock.Anything, "booking123", domain.StatusConfirmed, domain.StatusCancelled, mock.Anything).Return(nil)
+
+	uc := NewBookingUseCase(mockRepo, nil, nil, &MockHotelClient{}, mockPayment, nil, nil, nil, 0, 0, nil)
+
+	_, err := uc.CancelBooking(context.Background(), "booking123")
+	assert.NoError(t, err)
+	assert.Equal(t, money.New(500000, "RUB"), refundedAmount)
+}
+
+func TestCancelBooking_NonRefundableAuthorizedBookingIsCaptured(t *testing.T) {
+	mockRepo := new(MockBookingRepository)
+
+	captured, voided, refunded := false, false, false
+	mockPayment := &MockPaymentService{
+		CapturePaymentFunc: func(ctx context.Context, bookingID string) error {
+			captured = true
+			return nil
+		},
+		VoidPaymentFunc: func(ctx context.Context, bookingID string) error {
+			voided = true
+			return nil
+		},
+		RefundPaymentFunc: func(ctx context.Context, bookingID string, amount money.Money) error {
+			refunded = true
+			return nil
+		},
+	}
+
+	mockRepo.On("GetBookingByID", mock.Anything, "booking123").Return(&domain.Booking{
+		ID:                 "booking123",
+		CheckInDate:        time.Now().AddDate(0, 1, 0),
+		TotalPrice:         money.New(1000000, "RUB"),
+		CancellationPolicy: &domain.CancellationPolicy{Name: "Невозвратный"},
+		Status:             domain.StatusConfirmed,
+		PaymentStatus:      domain.PaymentAuthorized,
+	}, nil)
+	var outboxEvent *domain.OutboxEvent
+	mockRepo.On("UpdateBookingStatus", mock.Anything, "booking123", domain.StatusConfirmed, domain.StatusCancelled, mock.Anything).
+		Run(func(args mock.Arguments) { outboxEvent = args.Get(4).(*domain.OutboxEvent) }).
+		Return(nil)
+
+	uc := NewBookingUseCase(mockRepo, nil, nil, &MockHotelClient{}, mockPayment, nil, nil, nil, 0, 0, nil)
+
+	_, err := uc.CancelBooking(context.Background(), "booking123")
+	assert.NoError(t, err)
+	assert.True(t, captured)
+	assert.False(t, voided)
+	assert.False(t, refunded)
+
+	require.NotNil(t, outboxEvent)
+	var publishedEvent domain.BookingEvent
+	require.NoError(t, json.Unmarshal(outboxEvent.Payload, &publishedEvent))
+	require.NotNil(t, publishedEvent.RefundAmount)
+	assert.True(t, publishedEvent.RefundAmount.IsZero())
+}
+
+func TestCancelBooking_NotConfirmed(t *testing.T) {
+	mockRepo := new(MockBookingRepository)
+
+	mockRepo.On("GetBookingByID", mock.Anything, "booking123").Return(&domain.Booking{
+		ID:     "booking123",
+		Status: domain.StatusCancelled,
+	}, nil)
+
+	uc := NewBookingUseCase(mockRepo, nil, nil, &MockHotelClient{}, nil, nil, nil, nil, 0, 0, nil)
+
+	booking, err := uc.CancelBooking(context.Background(), "booking123")
+	assert.ErrorIs(t, err, domain.ErrBookingNotCancellable)
+	assert.Nil(t, booking)
+	mockRepo.AssertNotCalled(t, "UpdateBookingStatus", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestCancelBooking_RefundFails(t *testing.T) {
+	mockRepo := new(MockBookingRepository)
+	mockPayment := &MockPaymentService{
+		RefundPaymentFunc: func(ctx context.Context, bookingID string, amount money.Money) error {
+			return errors.New("payment service unavailable")
+		},
+	}
+
+	mockRepo.On("GetBookingByID", mock.Anything, "booking123").Return(&domain.Booking{
+		ID:            "booking123",
+		TotalPrice:    money.New(1000000, "RUB"),
+		Status:        domain.StatusConfirmed,
+		PaymentStatus: domain.PaymentPaid,
+	}, nil)
+
+	mockRepo.On("UpdateBookingStatus", mock.Anything, "booking123", domain.StatusConfirmed, domain.StatusCancelled, mock.Anything).Return(nil)
+
+	uc := NewBookingUseCase(mockRepo, nil, nil, &MockHotelClient{}, mockPayment, nil, nil, nil, 0, 0, nil)
+
+	_, err := uc.CancelBooking(context.Background(), "booking123")
+	assert.Error(t, err)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestCancelBooking_ConcurrentCancellationIsNotRefundedTwice(t *testing.T) {
+	mockRepo := new(MockBookingRepository)
+	mockPayment := &MockPaymentService{
+		RefundPaymentFunc: func(ctx context.Context, bookingID string, amount money.Money) error {
+			t.Fatal("unexpected refund")
+			return nil
+		},
+	}
+
+	mockRepo.On("GetBookingByID", mock.Anything, "booking123").Return(&domain.Booking{
+		ID:            "booking123",
+		TotalPrice:    money.New(1000000, "RUB"),
+		Status:        domain.StatusConfirmed,
+		PaymentStatus: domain.PaymentPaid,
+	}, nil)
+	mockRepo.On("UpdateBookingStatus", mock.Anything, "booking123", domain.StatusConfirmed, domain.StatusCancelled, mock.Anything).Return(domain.ErrStatusChanged)
+
+	uc := NewBookingUseCase(mockRepo, nil, nil, &MockHotelClient{}, mockPayment, nil, nil, nil, 0, 0, nil)
+
+	_, err := uc.CancelBooking(context.Background(), "booking123")
+	assert.ErrorIs(t, err, domain.ErrStatusChanged)
+}
+
+func TestCancelBooking_NotFound(t *testing.T) {
+	mockRepo := new(MockBookingRepository)
+	mockRepo.On("GetBookingByID", mock.Anything, "booking123").Return(nil, errors.New("not found"))
+
+	uc := NewBookingUseCase(mockRepo, nil, nil, &MockHotelClient{}, nil, nil, nil, nil, 0, 0, nil)
+
+	booking, err := uc.CancelBooking(context.Background(), "booking123")
+	assert.Error(t, err)
+	assert.Nil(t, booking)
+}
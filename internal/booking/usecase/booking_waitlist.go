@@ -0,0 +1,176 @@
+package usecase
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"hotel-booking-system/internal/booking/domain"
+
+	"github.com/google/uuid"
+)
+
+// JoinWaitlist puts the guest in line for a room of the type, for when the
+// type is sold out for their dates. Guests are offered freed rooms in the
+// order they joined.
+func (uc *BookingUseCase) JoinWaitlist(ctx context.Context, entry *domain.WaitlistEntry) error {
+	if entry.UserID == "" || entry.HotelID == "" || entry.RoomType == "" {
+		return domain.ErrInvalidWaitlistEntry
+	}
+	if !entry.CheckInDate.Before(entry.CheckOutDate) {
+		return domain.ErrInvalidDates
+	}
+
+	soldOut, err := uc.roomTypeSoldOut(ctx, entry.HotelID, entry.RoomType, entry.CheckInDate, entry.CheckOutDate)
+	if err != nil {
+		return err
+	}
+	if !soldOut {
+		return domain.ErrRoomTypeAvailable
+	}
+
+	entry.ID = uuid.New().String()
+	entry.Status = domain.WaitlistWaiting
+
+	return uc.waitlist.CreateWaitlistEntry(ctx, entry)
+}
+
+// roomTypeSoldOut tells whether every room of the type the hotel lets is
+// booked or held for some of the dates.
+func (uc *BookingUseCase) roomTypeSoldOut(ctx context.Context, hotelID, roomType string, checkIn, checkOut time.Time) (bool, error) {
+	rooms, err := uc.hotelClient.GetRooms(ctx, hotelID)
+	if err != nil {
+		return false, err
+	}
+	bookedRoomIDs, err := uc.repo.GetBookedRoomIDs(ctx, hotelID, checkIn, checkOut)
+	if err != nil {
+		return false, err
+	}
+
+	booked := make(map[string]bool, len(bookedRoomIDs))
+	for _, id := range bookedRoomIDs {
+		booked[id] = true
+	}
+	for _, room := range rooms {
+		if room.RoomType == roomType && room.IsAvailable && !booked[room.ID] {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+func (uc *BookingUseCase) GetWaitlistEntry(ctx context.Context, id string) (*domain.WaitlistEntry, error) {
+	return uc.waitlist.GetWaitlistEntryByID(ctx, id)
+}
+
+// LeaveWaitlist takes a guest who is still waiting off the waitlist. An offer
+// already made is left to expire.
+func (uc *BookingUseCase) LeaveWaitlist(ctx context.Context, id string) (*domain.WaitlistEntry, error) {
+	entry, err := uc.waitlist.GetWaitlistEntryByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if entry.Status != domain.WaitlistWaiting {
+		return nil, domain.ErrWaitlistEntryClosed
+	}
+
+	if err := uc.waitlist.UpdateWaitlistStatus(ctx, id, domain.WaitlistWaiting, domain.WaitlistCancelled); err != nil {
+		return nil, err
+	}
+	entry.Status = domain.WaitlistCancelled
+	return entry, nil
+}
+
+// ProcessBookingCancelled offers the room of a cancelled booking to the
+// waitlist. Bookings made before their room type was recorded are skipped.
+func (uc *BookingUseCase) ProcessBookingCancelled(ctx context.Context, event domain.BookingEvent) error {
+	if event.RoomType == "" {
+		return nil
+	}
+	return uc.offerRoom(ctx, event.HotelID, event.RoomType, event.RoomID, event.CheckInDate, event.CheckOutDate)
+}
+
+// ProcessHoldExpired closes the offer the expired hold was made for and
+// offers the room to the next guest in line. Holds guests made themselves are
+// ignored.
+func (uc *BookingUseCase) ProcessHoldExpired(ctx context.Context, event domain.HoldEvent) error {
+	entry, err := uc.waitlist.GetWaitlistEntryByHoldID(ctx, event.HoldID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	err = uc.waitlist.UpdateWaitlistStatus(ctx, entry.ID, domain.WaitlistOffered, domain.WaitlistExpired)
+	if err != nil && !errors.Is(err, domain.ErrWaitlistEntryClosed) {
+		return err
+	}
+	return uc.offerRoom(ctx, entry.HotelID, entry.RoomType, event.RoomID, event.CheckInDate, event.CheckOutDate)
+}
+
+// offerRoom offers a room freed for the given dates to the first guest
+// waiting for its type whose stay overlaps them and who finds the room free
+// for the whole stay. The offer is a hold on the room for the guest's dates
+// that lasts offerTTL.
+func (uc *BookingUseCase) offerRoom(ctx context.Context, hotelID, roomType, roomID string, checkIn, checkOut time.Time) error {
+	entries, err := uc.waitlist.GetWaitingEntries(ctx, hotelID, roomType, checkIn, checkOut)
+	if err != nil {
+		return err
+	}
+
+	for i := range entries {
+		entry := &entries[i]
+		overlapping, err := uc.repo.HasOverlappingBooking(ctx, roomID, entry.CheckInDate, entry.CheckOutDate, "")
+		if err != nil {
+			return err
+		}
+		if overlapping {
+			continue
+		}
+
+		err = uc.offer(ctx, entry, roomID)
+		if errors.Is(err, domain.ErrRoomNotAvailable) || errors.Is(err, domain.ErrWaitlistEntryClosed) {
+			continue
+		}
+		return err
+	}
+	return nil
+}
+
+func (uc *BookingUseCase) offer(ctx context.Context, entry *domain.WaitlistEntry, roomID string) error {
+	if err := uc.expireStaleHolds(ctx, roomID, entry.CheckInDate, entry.CheckOutDate); err != nil {
+		return err
+	}
+
+	hold := &domain.RoomHold{
+		ID:           uuid.New().String(),
+		UserID:       entry.UserID,
+		HotelID:      entry.HotelID,
+		RoomID:       roomID,
+		CheckInDate:  entry.CheckInDate,
+		CheckOutDate: entry.CheckOutDate,
+		Status:       domain.HoldActive,
+		ExpiresAt:    time.Now().Add(uc.offerTTL),
+	}
+
+	event, err := uc.newOutboxEvent(domain.EventWaitlistOffered, entry.ID, domain.WaitlistEvent{
+		EntryID:      entry.ID,
+		UserID:       entry.UserID,
+		HotelID:      entry.HotelID,
+		RoomType:     entry.RoomType,
+		RoomID:       roomID,
+		HoldID:       hold.ID,
+		CheckInDate:  entry.CheckInDate,
+		CheckOutDate: entry.CheckOutDate,
+		ExpiresAt:    hold.ExpiresAt,
+		EventType:    domain.EventWaitlistOffered,
+		Timestamp:    time.Now(),
+	})
+	if err != nil {
+		return err
+	}
+
+	return uc.waitlist.OfferWaitlistEntry(ctx, entry, hold, event)
+}
@@ -0,0 +1,297 @@
+package usecase
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"hotel-booking-system/internal/booking/domain"
+	"hotel-booking-system/pkg/hotelclient"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockWaitlistRepository struct {
+	mock.Mock
+}
+
+func (m *MockWaitlistRepository) CreateWaitlistEntry(ctx context.Context, entry *domain.WaitlistEntry) error {
+	args := m.Called(ctx, entry)
+	return args.Error(0)
+}
+
+func (m *MockWaitlistRepository) GetWaitlistEntryByID(ctx context.Context, id string) (*domain.WaitlistEntry, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.WaitlistEntry), args.Error(1)
+}
+
+func (m *MockWaitlistRepository) GetWaitlistEntryByHoldID(ctx context.Context, holdID string) (*domain.WaitlistEntry, error) {
+	args := m.Called(ctx, holdID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.WaitlistEntry), args.Error(1)
+}
+
+func (m *MockWaitlistRepository) GetWaitingEntries(ctx context.Context, hotelID, roomType string, checkIn, checkOut time.Time) ([]domain.WaitlistEntry, error) {
+	args := m.Called(ctx, hotelID, roomType, checkIn, checkOut)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]domain.WaitlistEntry), args.Error(1)
+}
+
+func (m *MockWaitlistRepository) OfferWaitlistEntry(ctx context.Context, entry *domain.WaitlistEntry, hold *domain.RoomHold, event *domain.OutboxEvent) error {
+	args := m.Called(ctx, entry, hold, event)
+	return args.Error(0)
+}
+
+func (m *MockWaitlistRepository) UpdateWaitlistStatus(ctx context.Context, id string, from, to domain.WaitlistStatus) error {
+	args := m.Called(ctx, id, from, to)
+	return args.Error(0)
+}
+
+func newTestWaitlistEntry(id string, checkIn time.Time) domain.WaitlistEntry {
+	return domain.WaitlistEntry{
+		ID:           id,
+		UserID:       "user-" + id,
+		HotelID:      "hotel-123",
+		RoomType:     "double",
+		CheckInDate:  checkIn,
+		CheckOutDate: checkIn.AddDate(0, 0, 2),
+		Status:       domain.WaitlistWaiting,
+	}
+}
+
+func TestJoinWaitlist(t *testing.T) {
+	checkIn := time.Now().AddDate(0, 0, 7)
+	hotelClient := &MockHotelClient{Rooms: []hotelclient.Room{
+		{ID: "room-1", RoomType: "double", IsAvailable: true},
+		{ID: "room-2", RoomType: "double", IsAvailable: false},
+		{ID: "room-3", RoomType: "single", IsAvailable: true},
+	}}
+
+	t.Run("success", func(t *testing.T) {
+		mockRepo := new(MockBookingRepository)
+		mockRepo.On("GetBookedRoomIDs", mock.Anything, "hotel-123", checkIn, checkIn.AddDate(0, 0, 2)).Return([]string{"room-1"}, nil)
+		mockWaitlist := new(MockWaitlistRepository)
+		mockWaitlist.On("CreateWaitlistEntry", mock.Anything, mock.Anything).Return(nil)
+
+		uc := NewBookingUseCase(mockRepo, nil, nil, hotelClient, nil, nil, nil, mockWaitlist, 0, 2*time.Hour, nil)
+		entry := newTestWaitlistEntry("", checkIn)
+		entry.Status = ""
+		err := uc.JoinWaitlist(context.Background(), &entry)
+
+		assert.NoError(t, err)
+		assert.NotEmpty(t, entry.ID)
+		assert.Equal(t, domain.WaitlistWaiting, entry.Status)
+		mockWaitlist.AssertExpectations(t)
+	})
+
+	t.Run("room of the type is free", func(t *testing.T) {
+		mockRepo := new(MockBookingRepository)
+		mockRepo.On("GetBookedRoomIDs", mock.Anything, "hotel-123", checkIn, checkIn.AddDate(0, 0, 2)).Return([]string{"room-3"}, nil)
+		mockWaitlist := new(MockWaitlistRepository)
+
+		uc := NewBookingUseCase(mockRepo, nil, nil, hotelClient, nil, nil, nil, mockWaitlist, 0, 2*time.Hour, nil)
+		entry := newTestWaitlistEntry("", checkIn)
+		err := uc.JoinWaitlist(context.Background(), &entry)
+
+		assert.ErrorIs(t, err, domain.ErrRoomTypeAvailable)
+		mockWaitlist.AssertNotCalled(t, "CreateWaitlistEntry", mock.Anything, mock.Anything)
+	})
+
+	t.Run("missing room type", func(t *testing.T) {
+		mockWaitlist := new(MockWaitlistRepository)
+
+		uc := NewBookingUseCase(nil, nil, nil, nil, nil, nil, nil, mockWaitlist, 0, 2*time.Hour, nil)
+		entry := newTestWaitlistEntry("", checkIn)
+		entry.RoomType = ""
+		err := uc.JoinWaitlist(context.Background(), &entry)
+
+		assert.ErrorIs(t, err, domain.ErrInvalidWaitlistEntry)
+		mockWaitlist.AssertNotCalled(t, "CreateWaitlistEntry", mock.Anything, mock.Anything)
+	})
+
+	t.Run("invalid dates", func(t *testing.T) {
+		mockWaitlist := new(MockWaitlistRepository)
+
+		uc := NewBookingUseCase(nil, nil, nil, nil, nil, nil, nil, mockWaitlist, 0, 2*time.Hour, nil)
+		entry := newTestWaitlistEntry("", checkIn)
+		entry.CheckOutDate = checkIn
+		err := uc.JoinWaitlist(context.Background(), &entry)
+
+		assert.ErrorIs(t, err, domain.ErrInvalidDates)
+	})
+}
+
+func TestLeaveWaitlist(t *testing.T) {
+	checkIn := time.Now().AddDate(0, 0, 7)
+
+	t.Run("success", func(t *testing.T) {
+		mockWaitlist := new(MockWaitlistRepository)
+		entry := newTestWaitlistEntry("entry-1", checkIn)
+		mockWaitlist.On("GetWaitlistEntryByID", mock.Anything, "entry-1").Return(&entry, nil)
+		mockWaitlist.On("UpdateWaitlistStatus", mock.Anything, "entry-1", domain.WaitlistWaiting, domain.WaitlistCancelled).Return(nil)
+
+		uc := NewBookingUseCase(nil, nil, nil, nil, nil, nil, nil, mockWaitlist, 0, 2*time.Hour, nil)
+		result, err := uc.LeaveWaitlist(context.Background(), "entry-1")
+
+		assert.NoError(t, err)
+		assert.Equal(t, domain.WaitlistCancelled, result.Status)
+		mockWaitlist.AssertExpectations(t)
+	})
+
+	t.Run("already offered", func(t *testing.T) {
+		mockWaitlist := new(MockWaitlistRepository)
+		entry := newTestWaitlistEntry("entry-1", checkIn)
+		entry.Status = domain.WaitlistOffered
+		mockWaitlist.On("GetWaitlistEntryByID", mock.Anything, "entry-1").Return(&entry, nil)
+
+		uc := NewBookingUseCase(nil, nil, nil, nil, nil, nil, nil, mockWaitlist, 0, 2*time.Hour, nil)
+		_, err := uc.LeaveWaitlist(context.Background(), "entry-1")
+
+		assert.ErrorIs(t, err, domain.ErrWaitlistEntryClosed)
+		mockWaitlist.AssertNotCalled(t, "UpdateWaitlistStatus", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+}
+
+// noExpiredRoomHolds stubs the holds with no expired hold left to reap.
+func noExpiredRoomHolds() *MockHoldRepository {
+	holds := new(MockHoldRepository)
+	holds.On("GetExpiredRoomHolds", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil, nil)
+	return holds
+}
+
+func TestProcessBookingCancelled(t *testing.T) {
+	checkIn := time.Now().AddDate(0, 0, 7).Truncate(24 * time.Hour)
+	cancelled := domain.BookingEvent{
+		BookingID:    "booking-123",
+		HotelID:      "hotel-123",
+		RoomID:       "room-123",
+		RoomType:     "double",
+		CheckInDate:  checkIn,
+		CheckOutDate: checkIn.AddDate(0, 0, 3),
+		EventType:    domain.EventBookingCancelled,
+	}
+
+	t.Run("offers the room to the first guest it is free for", func(t *testing.T) {
+		mockRepo := new(MockBookingRepository)
+		mockWaitlist := new(MockWaitlistRepository)
+		first := newTestWaitlistEntry("entry-1", checkIn.AddDate(0, 0, 2))
+		second := newTestWaitlistEntry("entry-2", checkIn)
+
+		mockWaitlist.On("GetWaitingEntries", mock.Anything, "hotel-123", "double", cancelled.CheckInDate, cancelled.CheckOutDate).
+			Return([]domain.WaitlistEntry{first, second}, nil)
+		mockRepo.On("HasOverlappingBooking", mock.Anything, "room-123", first.CheckInDate, first.CheckOutDate, "").Return(true, nil)
+		mockRepo.On("HasOverlappingBooking", mock.Anything, "room-123", second.CheckInDate, second.CheckOutDate, "").Return(false, nil)
+		mockWaitlist.On("OfferWaitlistEntry", mock.Anything, mock.MatchedBy(func(entry *domain.WaitlistEntry) bool {
+			return entry.ID == "entry-2"
+		}), mock.MatchedBy(func(hold *domain.RoomHold) bool {
+			return hold.ID != "" && hold.UserID == "user-entry-2" && hold.RoomID == "room-123" &&
+				hold.CheckInDate.Equal(second.CheckInDate) && hold.Status == domain.HoldActive &&
+				time.Until(hold.ExpiresAt) > time.Hour
+		}), mock.MatchedBy(func(event *domain.OutboxEvent) bool {
+			var payload domain.WaitlistEvent
+			json.Unmarshal(event.Payload, &payload)
+			return event.Topic == domain.EventWaitlistOffered && event.Key == "entry-2" &&
+				payload.UserID == "user-entry-2" && payload.RoomID == "room-123" && payload.HoldID != ""
+		})).Return(nil)
+
+		uc := NewBookingUseCase(mockRepo, nil, noExpiredRoomHolds(), nil, nil, nil, nil, mockWaitlist, 0, 2*time.Hour, nil)
+		err := uc.ProcessBookingCancelled(context.Background(), cancelled)
+
+		assert.NoError(t, err)
+		mockWaitlist.AssertExpectations(t)
+		mockWaitlist.AssertNumberOfCalls(t, "OfferWaitlistEntry", 1)
+	})
+
+	t.Run("moves on when an entry was cancelled meanwhile", func(t *testing.T) {
+		mockRepo := new(MockBookingRepository)
+		mockWaitlist := new(MockWaitlistRepository)
+		first := newTestWaitlistEntry("entry-1", checkIn)
+		second := newTestWaitlistEntry("entry-2", checkIn)
+
+		mockWaitlist.On("GetWaitingEntries", mock.Anything, "hotel-123", "double", mock.Anything, mock.Anything).
+			Return([]domain.WaitlistEntry{first, second}, nil)
+		mockRepo.On("HasOverlappingBooking", mock.Anything, "room-123", mock.Anything, mock.Anything, "").Return(false, nil)
+		mockWaitlist.On("OfferWaitlistEntry", mock.Anything, mock.MatchedBy(func(entry *domain.WaitlistEntry) bool {
+			return entry.ID == "entry-1"
+		}), mock.Anything, mock.Anything).Return(domain.ErrWaitlistEntryClosed)
+		mockWaitlist.On("OfferWaitlistEntry", mock.Anything, mock.MatchedBy(func(entry *domain.WaitlistEntry) bool {
+			return entry.ID == "entry-2"
+		}), mock.Anything, mock.Anything).Return(nil)
+
+		uc := NewBookingUseCase(mockRepo, nil, noExpiredRoomHolds(), nil, nil, nil, nil, mockWaitlist, 0, 2*time.Hour, nil)
+		err := uc.ProcessBookingCancelled(context.Background(), cancelled)
+
+		assert.NoError(t, err)
+		mockWaitlist.AssertNumberOfCalls(t, "OfferWaitlistEntry", 2)
+	})
+
+	t.Run("booking without room type", func(t *testing.T) {
+		mockWaitlist := new(MockWaitlistRepository)
+		event := cancelled
+		event.RoomType = ""
+
+		uc := NewBookingUseCase(nil, nil, nil, nil, nil, nil, nil, mockWaitlist, 0, 2*time.Hour, nil)
+		err := uc.ProcessBookingCancelled(context.Background(), event)
+
+		assert.NoError(t, err)
+		mockWaitlist.AssertNotCalled(t, "GetWaitingEntries", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+}
+
+func TestProcessHoldExpired(t *testing.T) {
+	checkIn := time.Now().AddDate(0, 0, 7).Truncate(24 * time.Hour)
+	expired := domain.HoldEvent{
+		HoldID:       "hold-123",
+		HotelID:      "hotel-123",
+		RoomID:       "room-123",
+		CheckInDate:  checkIn,
+		CheckOutDate: checkIn.AddDate(0, 0, 2),
+		EventType:    domain.EventBookingHoldExpired,
+	}
+
+	t.Run("offers the room to the next guest", func(t *testing.T) {
+		mockRepo := new(MockBookingRepository)
+		mockWaitlist := new(MockWaitlistRepository)
+		offered := newTestWaitlistEntry("entry-1", checkIn)
+		offered.Status = domain.WaitlistOffered
+		offered.HoldID = "hold-123"
+		next := newTestWaitlistEntry("entry-2", checkIn)
+
+		mockWaitlist.On("GetWaitlistEntryByHoldID", mock.Anything, "hold-123").Return(&offered, nil)
+		mockWaitlist.On("UpdateWaitlistStatus", mock.Anything, "entry-1", domain.WaitlistOffered, domain.WaitlistExpired).Return(nil)
+		mockWaitlist.On("GetWaitingEntries", mock.Anything, "hotel-123", "double", expired.CheckInDate, expired.CheckOutDate).
+			Return([]domain.WaitlistEntry{next}, nil)
+		mockRepo.On("HasOverlappingBooking", mock.Anything, "room-123", next.CheckInDate, next.CheckOutDate, "").Return(false, nil)
+		mockWaitlist.On("OfferWaitlistEntry", mock.Anything, mock.MatchedBy(func(entry *domain.WaitlistEntry) bool {
+			return entry.ID == "entry-2"
+		}), mock.Anything, mock.Anything).Return(nil)
+
+		uc := NewBookingUseCase(mockRepo, nil, noExpiredRoomHolds(), nil, nil, nil, nil, mockWaitlist, 0, 2*time.Hour, nil)
+		err := uc.ProcessHoldExpired(context.Background(), expired)
+
+		assert.NoError(t, err)
+		mockWaitlist.AssertExpectations(t)
+	})
+
+	t.Run("hold not made for an offer", func(t *testing.T) {
+		mockWaitlist := new(MockWaitlistRepository)
+		mockWaitlist.On("GetWaitlistEntryByHoldID", mock.Anything, "hold-123").Return(nil, sql.ErrNoRows)
+
+		uc := NewBookingUseCase(nil, nil, nil, nil, nil, nil, nil, mockWaitlist, 0, 2*time.Hour, nil)
+		err := uc.ProcessHoldExpired(context.Background(), expired)
+
+		assert.NoError(t, err)
+		mockWaitlist.AssertNotCalled(t, "UpdateWaitlistStatus", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+		mockWaitlist.AssertNotCalled(t, "GetWaitingEntries", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+}
@@ -3,25 +3,67 @@ package usecase
 import (
 	"context"
 	"hotel-booking-system/pkg/httpclient"
+	"hotel-booking-system/pkg/money"
 )
 
 type PaymentClientInterface interface {
 	CreatePayment(ctx context.Context, req *httpclient.PaymentRequest) (*httpclient.PaymentResponse, error)
+	RefundPayment(ctx context.Context, req *httpclient.RefundRequest) (*httpclient.RefundResponse, error)
+	CapturePayment(ctx context.Context, bookingID string) error
+	VoidPayment(ctx context.Context, bookingID string) error
 }
 
 type paymentClientAdapter struct {
-	client PaymentClientInterface
+	client      PaymentClientInterface
+	captureMode string
 }
 
-func NewPaymentClientAdapter(client PaymentClientInterface) PaymentClient {
-	return &paymentClientAdapter{client: client}
+// NewPaymentClientAdapter creates payments in the given capture mode:
+// "automatic" charges at booking time, "manual" only authorizes the card and
+// leaves the capture to check-in.
+func NewPaymentClientAdapter(client PaymentClientInterface, captureMode string) PaymentClient {
+	return &paymentClientAdapter{client: client, captureMode: captureMode}
 }
 
-func (a *paymentClientAdapter) CreatePayment(ctx context.Context, bookingID string, amount float64) error {
+func (a *paymentClientAdapter) CreatePayment(ctx context.Context, bookingID string, amount money.Money) error {
 	_, err := a.client.CreatePayment(ctx, &httpclient.PaymentRequest{
+		BookingID:   bookingID,
+		Amount:      amount,
+		CaptureMode: a.captureMode,
+	})
+	return err
+}
+
+// ChargePayment captures right away whatever the adapter's capture mode, for
+// extra charges that must not be left waiting for check-in. Each charge is
+// sent under its own idempotency key, so it is not mistaken for a retry of the
+// booking's first payment. It returns the ID of the payment, which the
+// payment webhook reports the charge under.
+func (a *paymentClientAdapter) ChargePayment(ctx context.Context, bookingID, chargeID string, amount money.Money) (string, error) {
+	resp, err := a.client.CreatePayment(ctx, &httpclient.PaymentRequest{
+		BookingID:      bookingID,
+		Amount:         amount,
+		CaptureMode:    "automatic",
+		IdempotencyKey: "charge-" + bookingID + "-" + chargeID,
+	})
+	if err != nil {
+		return "", err
+	}
+	return resp.PaymentID, nil
+}
+
+func (a *paymentClientAdapter) RefundPayment(ctx context.Context, bookingID string, amount money.Money) error {
+	_, err := a.client.RefundPayment(ctx, &httpclient.RefundRequest{
 		BookingID: bookingID,
 		Amount:    amount,
-		Currency:  "RUB",
 	})
 	return err
 }
+
+func (a *paymentClientAdapter) CapturePayment(ctx context.Context, bookingID string) error {
+	return a.client.CapturePayment(ctx, bookingID)
+}
+
+func (a *paymentClientAdapter) VoidPayment(ctx context.Context, bookingID string) error {
+	return a.client.VoidPayment(ctx, bookingID)
+}
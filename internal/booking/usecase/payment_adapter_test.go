@@ -2,10 +2,17 @@ package usecase
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
+	"net/http"
+	"net/http/httptest"
 	"testing"
+	"time"
 
 	"hotel-booking-system/pkg/httpclient"
+	"hotel-booking-system/pkg/idempotency"
+	"hotel-booking-system/pkg/logger"
+	"hotel-booking-system/pkg/money"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
@@ -23,9 +30,25 @@ func (m *MockPaymentClient) CreatePayment(ctx context.Context, req *httpclient.P
 	return args.Get(0).(*httpclient.PaymentResponse), args.Error(1)
 }
 
+func (m *MockPaymentClient) RefundPayment(ctx context.Context, req *httpclient.RefundRequest) (*httpclient.RefundResponse, error) {
+	args := m.Called(ctx, req)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*httpclient.RefundResponse), args.Error(1)
+}
+
+func (m *MockPaymentClient) CapturePayment(ctx context.Context, bookingID string) error {
+	return m.Called(ctx, bookingID).Error(0)
+}
+
+func (m *MockPaymentClient) VoidPayment(ctx context.Context, bookingID string) error {
+	return m.Called(ctx, bookingID).Error(0)
+}
+
 func TestNewPaymentClientAdapter(t *testing.T) {
 	mockClient := new(MockPaymentClient)
-	adapter := NewPaymentClientAdapter(mockClient)
+	adapter := NewPaymentClientAdapter(mockClient, "automatic")
 
 	assert.NotNil(t, adapter)
 	assert.IsType(t, &paymentClientAdapter{}, adapter)
@@ -36,8 +59,7 @@ func TestPaymentClientAdapter_CreatePayment(t *testing.T) {
 		mockClient := new(MockPaymentClient)
 		mockClient.On("CreatePayment", mock.Anything, mock.MatchedBy(func(req *httpclient.PaymentRequest) bool {
 			return req.BookingID == "booking-123" &&
-				req.Amount == 1000.0 &&
-				req.Currency == "RUB"
+				req.Amount == money.New(100000, "RUB")
 		})).Return(
 			&httpclient.PaymentResponse{
 				PaymentID: "payment-123",
@@ -46,9 +68,9 @@ func TestPaymentClientAdapter_CreatePayment(t *testing.T) {
 			nil,
 		)
 
-		adapter := NewPaymentClientAdapter(mockClient)
+		adapter := NewPaymentClientAdapter(mockClient, "automatic")
 
-		err := adapter.CreatePayment(context.Background(), "booking-123", 1000.0)
+		err := adapter.CreatePayment(context.Background(), "booking-123", money.New(100000, "RUB"))
 		assert.NoError(t, err)
 		mockClient.AssertExpectations(t)
 	})
@@ -60,9 +82,9 @@ func TestPaymentClientAdapter_CreatePayment(t *testing.T) {
 			errors.New("payment service error"),
 		)
 
-		adapter := NewPaymentClientAdapter(mockClient)
+		adapter := NewPaymentClientAdapter(mockClient, "automatic")
 
-		err := adapter.CreatePayment(context.Background(), "booking-123", 1000.0)
+		err := adapter.CreatePayment(context.Background(), "booking-123", money.New(100000, "RUB"))
 		assert.Error(t, err)
 		assert.Contains(t, err.Error(), "payment service error")
 		mockClient.AssertExpectations(t)
@@ -71,7 +93,7 @@ func TestPaymentClientAdapter_CreatePayment(t *testing.T) {
 	t.Run("zero amount", func(t *testing.T) {
 		mockClient := new(MockPaymentClient)
 		mockClient.On("CreatePayment", mock.Anything, mock.MatchedBy(func(req *httpclient.PaymentRequest) bool {
-			return req.Amount == 0.0
+			return req.Amount == money.New(0, "RUB")
 		})).Return(
 			&httpclient.PaymentResponse{
 				PaymentID: "payment-456",
@@ -80,9 +102,9 @@ func TestPaymentClientAdapter_CreatePayment(t *testing.T) {
 			nil,
 		)
 
-		adapter := NewPaymentClientAdapter(mockClient)
+		adapter := NewPaymentClientAdapter(mockClient, "automatic")
 
-		err := adapter.CreatePayment(context.Background(), "booking-456", 0.0)
+		err := adapter.CreatePayment(context.Background(), "booking-456", money.New(0, "RUB"))
 		assert.NoError(t, err)
 		mockClient.AssertExpectations(t)
 	})
@@ -90,7 +112,7 @@ func TestPaymentClientAdapter_CreatePayment(t *testing.T) {
 	t.Run("large amount", func(t *testing.T) {
 		mockClient := new(MockPaymentClient)
 		mockClient.On("CreatePayment", mock.Anything, mock.MatchedBy(func(req *httpclient.PaymentRequest) bool {
-			return req.Amount == 999999.99
+			return req.Amount == money.New(99999999, "RUB")
 		})).Return(
 			&httpclient.PaymentResponse{
 				PaymentID: "payment-789",
@@ -99,10 +121,110 @@ func TestPaymentClientAdapter_CreatePayment(t *testing.T) {
 			nil,
 		)
 
-		adapter := NewPaymentClientAdapter(mockClient)
+		adapter := NewPaymentClientAdapter(mockClient, "automatic")
 
-		err := adapter.CreatePayment(context.Background(), "booking-789", 999999.99)
+		err := adapter.CreatePayment(context.Background(), "booking-789", money.New(99999999, "RUB"))
 		assert.NoError(t, err)
 		mockClient.AssertExpectations(t)
 	})
 }
+
+func TestPaymentClientAdapter_RefundPayment(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		mockClient := new(MockPaymentClient)
+		mockClient.On("RefundPayment", mock.Anything, mock.MatchedBy(func(req *httpclient.RefundRequest) bool {
+			return req.BookingID == "booking-123" &&
+				req.Amount == money.New(100000, "RUB")
+		})).Return(
+			&httpclient.RefundResponse{
+				RefundID: "refund-123",
+				Status:   "processing",
+			},
+			nil,
+		)
+
+		adapter := NewPaymentClientAdapter(mockClient, "automatic")
+
+		err := adapter.RefundPayment(context.Background(), "booking-123", money.New(100000, "RUB"))
+		assert.NoError(t, err)
+		mockClient.AssertExpectations(t)
+	})
+
+	t.Run("client error", func(t *testing.T) {
+		mockClient := new(MockPaymentClient)
+		mockClient.On("RefundPayment", mock.Anything, mock.Anything).Return(
+			nil,
+			errors.New("payment service error"),
+		)
+
+		adapter := NewPaymentClientAdapter(mockClient, "automatic")
+
+		err := adapter.RefundPayment(context.Background(), "booking-123", money.New(100000, "RUB"))
+		assert.Error(t, err)
+		mockClient.AssertExpectations(t)
+	})
+}
+
+func TestPaymentClientAdapter_ManualCapture(t *testing.T) {
+	mockClient := new(MockPaymentClient)
+	mockClient.On("CreatePayment", mock.Anything, mock.MatchedBy(func(req *httpclient.PaymentRequest) bool {
+		return req.BookingID == "booking-123" && req.CaptureMode == "manual"
+	})).Return(&httpclient.PaymentResponse{PaymentID: "payment-123", Status: "processing"}, nil)
+	mockClient.On("CapturePayment", mock.Anything, "booking-123").Return(nil)
+	mockClient.On("VoidPayment", mock.Anything, "booking-123").Return(errors.New("payment service returned status 409"))
+
+	adapter := NewPaymentClientAdapter(mockClient, "manual")
+
+	assert.NoError(t, adapter.CreatePayment(context.Background(), "booking-123", money.New(100000, "RUB")))
+	assert.NoError(t, adapter.CapturePayment(context.Background(), "booking-123"))
+	assert.Error(t, adapter.VoidPayment(context.Background(), "booking-123"))
+	mockClient.AssertExpectations(t)
+}
+
+func TestPaymentClientAdapter_ChargePayment(t *testing.T) {
+	mockClient := new(MockPaymentClient)
+	mockClient.On("CreatePayment", mock.Anything, mock.MatchedBy(func(req *httpclient.PaymentRequest) bool {
+		return req.BookingID == "booking-123" && req.Amount == money.New(50000, "RUB") && req.CaptureMode == "automatic" &&
+			req.IdempotencyKey == "charge-booking-123-charge-1"
+	})).Return(&httpclient.PaymentResponse{PaymentID: "payment-456", Status: "processing"}, nil)
+
+	adapter := NewPaymentClientAdapter(mockClient, "manual")
+
+	paymentID, err := adapter.ChargePayment(context.Background(), "booking-123", "charge-1", money.New(50000, "RUB"))
+	assert.NoError(t, err)
+	assert.Equal(t, "payment-456", paymentID)
+	mockClient.AssertExpectations(t)
+}
+
+func TestPaymentClientAdapter_ChargePaymentThroughIdempotency(t *testing.T) {
+	logger.Init("info")
+
+	var amounts []money.Money
+	server := httptest.NewServer(idempotency.Middleware(idempotency.NewMemoryStore(time.Minute))(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			var req httpclient.PaymentRequest
+			json.NewDecoder(r.Body).Decode(&req)
+			amounts = append(amounts, req.Amount)
+
+			w.WriteHeader(http.StatusAccepted)
+			json.NewEncoder(w).Encode(httpclient.PaymentResponse{PaymentID: "payment-123", Status: "processing"})
+		}),
+	))
+	defer server.Close()
+
+	adapter := NewPaymentClientAdapter(httpclient.NewPaymentClient(server.URL), "automatic")
+	ctx := context.Background()
+
+	assert.NoError(t, adapter.CreatePayment(ctx, "booking-123", money.New(100000, "RUB")))
+	_, err := adapter.ChargePayment(ctx, "booking-123", "charge-1", money.New(20000, "RUB"))
+	assert.NoError(t, err)
+	_, err = adapter.ChargePayment(ctx, "booking-123", "charge-2", money.New(20000, "RUB"))
+	assert.NoError(t, err)
+	assert.NoError(t, adapter.CreatePayment(ctx, "booking-123", money.New(100000, "RUB")))
+
+	assert.Equal(t, []money.Money{
+		money.New(100000, "RUB"),
+		money.New(20000, "RUB"),
+		money.New(20000, "RUB"),
+	}, amounts)
+}
@@ -0,0 +1,44 @@
+package worker
+
+import (
+	"context"
+	"time"
+
+	"hotel-booking-system/pkg/logger"
+	"hotel-booking-system/pkg/periodic"
+)
+
+type BookingExpirer interface {
+	ExpireBookings(ctx context.Context, timeout time.Duration, limit int) (int, error)
+}
+
+// BookingReaper expires the bookings that have been awaiting payment for
+// longer than the payment timeout.
+type BookingReaper struct {
+	expirer   BookingExpirer
+	interval  time.Duration
+	timeout   time.Duration
+	batchSize int
+}
+
+func NewBookingReaper(expirer BookingExpirer, interval, timeout time.Duration, batchSize int) *BookingReaper {
+	return &BookingReaper{
+		expirer:   expirer,
+		interval:  interval,
+		timeout:   timeout,
+		batchSize: batchSize,
+	}
+}
+
+func (r *BookingReaper) Run(ctx context.Context) {
+	periodic.Run(ctx, r.interval, "expire unpaid bookings", func(ctx context.Context) error {
+		expired, err := r.expirer.ExpireBookings(ctx, r.timeout, r.batchSize)
+		if err != nil {
+			return err
+		}
+		if expired > 0 {
+			logger.GetLogger().Infof("expired %d unpaid bookings", expired)
+		}
+		return nil
+	})
+}
@@ -0,0 +1,40 @@
+package worker
+
+import (
+	"context"
+	"time"
+
+	"hotel-booking-system/pkg/logger"
+	"hotel-booking-system/pkg/periodic"
+)
+
+type HoldExpirer interface {
+	ExpireHolds(ctx context.Context, limit int) (int, error)
+}
+
+type HoldReaper struct {
+	expirer   HoldExpirer
+	interval  time.Duration
+	batchSize int
+}
+
+func NewHoldReaper(expirer HoldExpirer, interval time.Duration, batchSize int) *HoldReaper {
+	return &HoldReaper{
+		expirer:   expirer,
+		interval:  interval,
+		batchSize: batchSize,
+	}
+}
+
+func (r *HoldReaper) Run(ctx context.Context) {
+	periodic.Run(ctx, r.interval, "expire room holds", func(ctx context.Context) error {
+		expired, err := r.expirer.ExpireHolds(ctx, r.batchSize)
+		if err != nil {
+			return err
+		}
+		if expired > 0 {
+			logger.GetLogger().Infof("released %d expired room holds", expired)
+		}
+		return nil
+	})
+}
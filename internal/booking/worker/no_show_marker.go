@@ -0,0 +1,42 @@
+package worker
+
+import (
+	"context"
+	"time"
+
+	"hotel-booking-system/pkg/logger"
+	"hotel-booking-system/pkg/periodic"
+)
+
+type NoShowRunner interface {
+	MarkNoShows(ctx context.Context, cutoff time.Duration) (int, error)
+}
+
+// NoShowMarker marks the bookings whose guests have not arrived within cutoff
+// of the start of their check-in date as no-shows.
+type NoShowMarker struct {
+	runner   NoShowRunner
+	interval time.Duration
+	cutoff   time.Duration
+}
+
+func NewNoShowMarker(runner NoShowRunner, interval, cutoff time.Duration) *NoShowMarker {
+	return &NoShowMarker{
+		runner:   runner,
+		interval: interval,
+		cutoff:   cutoff,
+	}
+}
+
+func (m *NoShowMarker) Run(ctx context.Context) {
+	periodic.Run(ctx, m.interval, "mark no-show bookings", func(ctx context.Context) error {
+		marked, err := m.runner.MarkNoShows(ctx, m.cutoff)
+		if err != nil {
+			return err
+		}
+		if marked > 0 {
+			logger.GetLogger().Infof("marked %d bookings as no-show", marked)
+		}
+		return nil
+	})
+}
@@ -0,0 +1,63 @@
+package worker
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"hotel-booking-system/internal/booking/domain"
+	"hotel-booking-system/pkg/logger"
+	"hotel-booking-system/pkg/periodic"
+)
+
+type MessageProducer interface {
+	SendMessageToTopic(ctx context.Context, topic, key string, value interface{}) error
+}
+
+type OutboxRelay struct {
+	repo      domain.OutboxRepository
+	producer  MessageProducer
+	interval  time.Duration
+	batchSize int
+}
+
+func NewOutboxRelay(repo domain.OutboxRepository, producer MessageProducer, interval time.Duration, batchSize int) *OutboxRelay {
+	return &OutboxRelay{
+		repo:      repo,
+		producer:  producer,
+		interval:  interval,
+		batchSize: batchSize,
+	}
+}
+
+func (r *OutboxRelay) Run(ctx context.Context) {
+	periodic.Run(ctx, r.interval, "relay outbox events", func(ctx context.Context) error {
+		_, err := r.ProcessBatch(ctx)
+		return err
+	})
+}
+
+// ProcessBatch publishes pending events in insertion order. An event is marked
+// sent only after Kafka acknowledged it, so a crash in between leads to a
+// redelivery rather than a lost event.
+func (r *OutboxRelay) ProcessBatch(ctx context.Context) (int, error) {
+	events, err := r.repo.GetPendingEvents(ctx, r.batchSize)
+	if err != nil {
+		return 0, err
+	}
+
+	sent := 0
+	for _, event := range events {
+		if err := r.producer.SendMessageToTopic(ctx, event.Topic, event.Key, json.RawMessage(event.Payload)); err != nil {
+			if markErr := r.repo.MarkEventFailed(ctx, event.ID, err.Error()); markErr != nil {
+				logger.GetLogger().WithError(markErr).Error("failed to record outbox event failure")
+			}
+			return sent, err
+		}
+		if err := r.repo.MarkEventSent(ctx, event.ID); err != nil {
+			return sent, err
+		}
+		sent++
+	}
+	return sent, nil
+}
@@ -0,0 +1,112 @@
+package worker
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"hotel-booking-system/internal/booking/domain"
+	"hotel-booking-system/pkg/logger"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockOutboxRepository struct {
+	mock.Mock
+}
+
+func (m *MockOutboxRepository) GetPendingEvents(ctx context.Context, limit int) ([]domain.OutboxEvent, error) {
+	args := m.Called(ctx, limit)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]domain.OutboxEvent), args.Error(1)
+}
+
+func (m *MockOutboxRepository) MarkEventSent(ctx context.Context, id int64) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func (m *MockOutboxRepository) MarkEventFailed(ctx context.Context, id int64, reason string) error {
+	args := m.Called(ctx, id, reason)
+	return args.Error(0)
+}
+
+type sentMessage struct {
+	topic string
+	key   string
+	value interface{}
+}
+
+type MockProducer struct {
+	sent    []sentMessage
+	failKey string
+}
+
+func (m *MockProducer) SendMessageToTopic(ctx context.Context, topic, key string, value interface{}) error {
+	if key == m.failKey {
+		return errors.New("broker unavailable")
+	}
+	m.sent = append(m.sent, sentMessage{topic: topic, key: key, value: value})
+	return nil
+}
+
+func TestOutboxRelay_ProcessBatch(t *testing.T) {
+	logger.Init("info")
+
+	events := []domain.OutboxEvent{
+		{ID: 1, Topic: domain.EventBookingCreated, Key: "booking-1", Payload: []byte(`{"booking_id":"booking-1"}`)},
+		{ID: 2, Topic: domain.EventBookingCancelled, Key: "booking-2", Payload: []byte(`{"booking_id":"booking-2"}`)},
+	}
+
+	t.Run("publishes and marks all events", func(t *testing.T) {
+		repo := new(MockOutboxRepository)
+		producer := &MockProducer{}
+		repo.On("GetPendingEvents", mock.Anything, 10).Return(events, nil)
+		repo.On("MarkEventSent", mock.Anything, int64(1)).Return(nil)
+		repo.On("MarkEventSent", mock.Anything, int64(2)).Return(nil)
+
+		relay := NewOutboxRelay(repo, producer, time.Second, 10)
+		sent, err := relay.ProcessBatch(context.Background())
+
+		assert.NoError(t, err)
+		assert.Equal(t, 2, sent)
+		assert.Len(t, producer.sent, 2)
+		assert.Equal(t, domain.EventBookingCreated, producer.sent[0].topic)
+		assert.Equal(t, "booking-1", producer.sent[0].key)
+		assert.Equal(t, json.RawMessage(`{"booking_id":"booking-1"}`), producer.sent[0].value)
+		assert.Equal(t, domain.EventBookingCancelled, producer.sent[1].topic)
+		repo.AssertExpectations(t)
+	})
+
+	t.Run("stops on publish failure and keeps event pending", func(t *testing.T) {
+		repo := new(MockOutboxRepository)
+		producer := &MockProducer{failKey: "booking-1"}
+		repo.On("GetPendingEvents", mock.Anything, 10).Return(events, nil)
+		repo.On("MarkEventFailed", mock.Anything, int64(1), "broker unavailable").Return(nil)
+
+		relay := NewOutboxRelay(repo, producer, time.Second, 10)
+		sent, err := relay.ProcessBatch(context.Background())
+
+		assert.Error(t, err)
+		assert.Equal(t, 0, sent)
+		assert.Empty(t, producer.sent)
+		repo.AssertNotCalled(t, "MarkEventSent", mock.Anything, mock.Anything)
+		repo.AssertExpectations(t)
+	})
+
+	t.Run("repository error", func(t *testing.T) {
+		repo := new(MockOutboxRepository)
+		repo.On("GetPendingEvents", mock.Anything, 10).Return(nil, errors.New("database error"))
+
+		relay := NewOutboxRelay(repo, &MockProducer{}, time.Second, 10)
+		sent, err := relay.ProcessBatch(context.Background())
+
+		assert.Error(t, err)
+		assert.Equal(t, 0, sent)
+	})
+}
@@ -0,0 +1,40 @@
+package worker
+
+import (
+	"context"
+	"time"
+
+	"hotel-booking-system/pkg/logger"
+	"hotel-booking-system/pkg/periodic"
+)
+
+type SagaRunner interface {
+	ResumeSagas(ctx context.Context, staleAfter time.Duration) (int, error)
+}
+
+type SagaResumer struct {
+	runner     SagaRunner
+	interval   time.Duration
+	staleAfter time.Duration
+}
+
+func NewSagaResumer(runner SagaRunner, interval, staleAfter time.Duration) *SagaResumer {
+	return &SagaResumer{
+		runner:     runner,
+		interval:   interval,
+		staleAfter: staleAfter,
+	}
+}
+
+func (r *SagaResumer) Run(ctx context.Context) {
+	periodic.Run(ctx, r.interval, "resume booking sagas", func(ctx context.Context) error {
+		resumed, err := r.runner.ResumeSagas(ctx, r.staleAfter)
+		if err != nil {
+			return err
+		}
+		if resumed > 0 {
+			logger.GetLogger().Infof("resumed %d unfinished booking sagas", resumed)
+		}
+		return nil
+	})
+}
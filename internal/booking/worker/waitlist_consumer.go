@@ -0,0 +1,67 @@
+package worker
+
+import (
+	"context"
+	"encoding/json"
+
+	"hotel-booking-system/internal/booking/domain"
+	"hotel-booking-system/pkg/logger"
+)
+
+type MessageReader interface {
+	ReadMessage(ctx context.Context, handler func([]byte) error) error
+}
+
+type WaitlistProcessor interface {
+	ProcessBookingCancelled(ctx context.Context, event domain.BookingEvent) error
+	ProcessHoldExpired(ctx context.Context, event domain.HoldEvent) error
+}
+
+// WaitlistConsumer offers the rooms freed by cancelled and expired bookings
+// and by expired waitlist offers to the guests on the waitlist.
+type WaitlistConsumer struct {
+	reader    MessageReader
+	processor WaitlistProcessor
+}
+
+func NewWaitlistConsumer(reader MessageReader, processor WaitlistProcessor) *WaitlistConsumer {
+	return &WaitlistConsumer{
+		reader:    reader,
+		processor: processor,
+	}
+}
+
+func (c *WaitlistConsumer) Run(ctx context.Context) {
+	logger.GetLogger().Info("starting waitlist consumer")
+	if err := c.reader.ReadMessage(ctx, func(data []byte) error {
+		return c.HandleMessage(ctx, data)
+	}); err != nil && ctx.Err() == nil {
+		logger.GetLogger().WithError(err).Error("waitlist consumer stopped")
+	}
+}
+
+func (c *WaitlistConsumer) HandleMessage(ctx context.Context, data []byte) error {
+	var header struct {
+		EventType string `json:"event_type"`
+	}
+	if err := json.Unmarshal(data, &header); err != nil {
+		return err
+	}
+
+	switch header.EventType {
+	case domain.EventBookingCancelled, domain.EventBookingExpired:
+		var event domain.BookingEvent
+		if err := json.Unmarshal(data, &event); err != nil {
+			return err
+		}
+		return c.processor.ProcessBookingCancelled(ctx, event)
+	case domain.EventBookingHoldExpired:
+		var event domain.HoldEvent
+		if err := json.Unmarshal(data, &event); err != nil {
+			return err
+		}
+		return c.processor.ProcessHoldExpired(ctx, event)
+	default:
+		return nil
+	}
+}
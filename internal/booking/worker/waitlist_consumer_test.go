@@ -0,0 +1,111 @@
+package worker
+
+import (
+	"context"
+	"testing"
+
+	"hotel-booking-system/internal/booking/domain"
+	"hotel-booking-system/pkg/logger"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockWaitlistProcessor struct {
+	mock.Mock
+}
+
+func (m *MockWaitlistProcessor) ProcessBookingCancelled(ctx context.Context, event domain.BookingEvent) error {
+	args := m.Called(ctx, event)
+	return args.Error(0)
+}
+
+func (m *MockWaitlistProcessor) ProcessHoldExpired(ctx context.Context, event domain.HoldEvent) error {
+	args := m.Called(ctx, event)
+	return args.Error(0)
+}
+
+type MockMessageReader struct {
+	messages []string
+	errs     []error
+}
+
+func (m *MockMessageReader) ReadMessage(ctx context.Context, handler func([]byte) error) error {
+	for _, message := range m.messages {
+		m.errs = append(m.errs, handler([]byte(message)))
+	}
+	<-ctx.Done()
+	return ctx.Err()
+}
+
+func TestWaitlistConsumer_HandleMessage(t *testing.T) {
+	logger.Init("info")
+	ctx := context.Background()
+
+	t.Run("booking cancelled", func(t *testing.T) {
+		processor := new(MockWaitlistProcessor)
+		processor.On("ProcessBookingCancelled", ctx, mock.MatchedBy(func(event domain.BookingEvent) bool {
+			return event.BookingID == "booking-123" && event.RoomType == "double"
+		})).Return(nil)
+
+		consumer := NewWaitlistConsumer(&MockMessageReader{}, processor)
+		err := consumer.HandleMessage(ctx, []byte(`{"booking_id":"booking-123","room_type":"double","event_type":"booking.cancelled"}`))
+		assert.NoError(t, err)
+		processor.AssertExpectations(t)
+	})
+
+	t.Run("booking expired", func(t *testing.T) {
+		processor := new(MockWaitlistProcessor)
+		processor.On("ProcessBookingCancelled", ctx, mock.MatchedBy(func(event domain.BookingEvent) bool {
+			return event.BookingID == "booking-123" && event.RoomType == "double"
+		})).Return(nil)
+
+		consumer := NewWaitlistConsumer(&MockMessageReader{}, processor)
+		err := consumer.HandleMessage(ctx, []byte(`{"booking_id":"booking-123","room_type":"double","event_type":"booking.expired"}`))
+		assert.NoError(t, err)
+		processor.AssertExpectations(t)
+	})
+
+	t.Run("hold expired", func(t *testing.T) {
+		processor := new(MockWaitlistProcessor)
+		processor.On("ProcessHoldExpired", ctx, mock.MatchedBy(func(event domain.HoldEvent) bool {
+			return event.HoldID == "hold-123"
+		})).Return(nil)
+
+		consumer := NewWaitlistConsumer(&MockMessageReader{}, processor)
+		err := consumer.HandleMessage(ctx, []byte(`{"hold_id":"hold-123","event_type":"booking.hold_expired"}`))
+		assert.NoError(t, err)
+		processor.AssertExpectations(t)
+	})
+
+	t.Run("other events are ignored", func(t *testing.T) {
+		processor := new(MockWaitlistProcessor)
+
+		consumer := NewWaitlistConsumer(&MockMessageReader{}, processor)
+		err := consumer.HandleMessage(ctx, []byte(`{"booking_id":"booking-123","event_type":"booking.created"}`))
+		assert.NoError(t, err)
+		processor.AssertNotCalled(t, "ProcessBookingCancelled", mock.Anything, mock.Anything)
+	})
+
+	t.Run("invalid message", func(t *testing.T) {
+		consumer := NewWaitlistConsumer(&MockMessageReader{}, new(MockWaitlistProcessor))
+		err := consumer.HandleMessage(ctx, []byte(`not json`))
+		assert.Error(t, err)
+	})
+}
+
+func TestWaitlistConsumer_RunsUntilCancelled(t *testing.T) {
+	logger.Init("info")
+
+	processor := new(MockWaitlistProcessor)
+	processor.On("ProcessBookingCancelled", mock.Anything, mock.Anything).Return(nil)
+	reader := &MockMessageReader{messages: []string{`{"booking_id":"booking-123","event_type":"booking.cancelled"}`}}
+	consumer := NewWaitlistConsumer(reader, processor)
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	consumer.Run(ctx)
+
+	assert.Equal(t, []error{nil}, reader.errs)
+	processor.AssertNumberOfCalls(t, "ProcessBookingCancelled", 1)
+}
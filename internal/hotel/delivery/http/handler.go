@@ -1,7 +1,9 @@
 package http
 
 import (
+	"database/sql"
 	"encoding/json"
+	"errors"
 	"net/http"
 	"strconv"
 	"time"
@@ -13,6 +15,8 @@ import (
 	"github.com/go-chi/chi/v5"
 )
 
+const dateLayout = "2006-01-02"
+
 type HotelHandler struct {
 	useCase domain.HotelUseCase
 }
@@ -37,8 +41,9 @@ func (h *HotelHandler) CreateHotel(w http.ResponseWriter, r *http.Request) {
 
 	if err := h.useCase.CreateHotel(r.Context(), &hotel); err != nil {
 		logger.GetLogger().WithError(err).Error("failed to create hotel")
-		metrics.HTTPRequestsTotal.WithLabelValues(r.Method, "/api/hotels", "500").Inc()
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		status := errorStatus(err)
+		metrics.HTTPRequestsTotal.WithLabelValues(r.Method, "/api/hotels", strconv.Itoa(status)).Inc()
+		http.Error(w, err.Error(), status)
 		return
 	}
 
@@ -110,6 +115,48 @@ func (h *HotelHandler) GetHotelWithRooms(w http.ResponseWriter, r *http.Request)
 	json.NewEncoder(w).Encode(hotelWithRooms)
 }
 
+func (h *HotelHandler) GetAvailableRooms(w http.ResponseWriter, r *http.Request) {
+	start := time.Now()
+	defer func() {
+		metrics.HTTPRequestDuration.WithLabelValues(r.Method, "/api/hotels/{id}/availability").Observe(time.Since(start).Seconds())
+	}()
+
+	id := chi.URLParam(r, "id")
+	query := r.URL.Query()
+
+	checkIn, errIn := time.Parse(dateLayout, query.Get("check_in"))
+	checkOut, errOut := time.Parse(dateLayout, query.Get("check_out"))
+	if errIn != nil || errOut != nil {
+		metrics.HTTPRequestsTotal.WithLabelValues(r.Method, "/api/hotels/{id}/availability", "400").Inc()
+		http.Error(w, "check_in and check_out must be dates in YYYY-MM-DD format", http.StatusBadRequest)
+		return
+	}
+
+	guests := 1
+	if raw := query.Get("guests"); raw != "" {
+		parsed, err := strconv.Atoi(raw)
+		if err != nil {
+			metrics.HTTPRequestsTotal.WithLabelValues(r.Method, "/api/hotels/{id}/availability", "400").Inc()
+			http.Error(w, "guests must be a number", http.StatusBadRequest)
+			return
+		}
+		guests = parsed
+	}
+
+	rooms, err := h.useCase.GetAvailableRooms(r.Context(), id, checkIn, checkOut, guests)
+	if err != nil {
+		logger.GetLogger().WithError(err).Error("failed to get available rooms")
+		status := errorStatus(err)
+		metrics.HTTPRequestsTotal.WithLabelValues(r.Method, "/api/hotels/{id}/availability", strconv.Itoa(status)).Inc()
+		http.Error(w, err.Error(), status)
+		return
+	}
+
+	metrics.HTTPRequestsTotal.WithLabelValues(r.Method, "/api/hotels/{id}/availability", "200").Inc()
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(rooms)
+}
+
 func (h *HotelHandler) UpdateHotel(w http.ResponseWriter, r *http.Request) {
 	start := time.Now()
 	defer func() {
@@ -154,8 +201,9 @@ func (h *HotelHandler) CreateRoom(w http.ResponseWriter, r *http.Request) {
 
 	if err := h.useCase.CreateRoom(r.Context(), &room); err != nil {
 		logger.GetLogger().WithError(err).Error("failed to create room")
-		metrics.HTTPRequestsTotal.WithLabelValues(r.Method, "/api/rooms", "500").Inc()
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		status := errorStatus(err)
+		metrics.HTTPRequestsTotal.WithLabelValues(r.Method, "/api/rooms", strconv.Itoa(status)).Inc()
+		http.Error(w, err.Error(), status)
 		return
 	}
 
@@ -164,3 +212,218 @@ func (h *HotelHandler) CreateRoom(w http.ResponseWriter, r *http.Request) {
 	w.WriteHeader(http.StatusCreated)
 	json.NewEncoder(w).Encode(room)
 }
+
+func (h *HotelHandler) CreateRatePlan(w http.ResponseWriter, r *http.Request) {
+	start := time.Now()
+	defer func() {
+		metrics.HTTPRequestDuration.WithLabelValues(r.Method, "/api/hotels/{id}/rooms/{roomId}/rate-plans").Observe(time.Since(start).Seconds())
+	}()
+
+	var plan domain.RatePlan
+	if err := json.NewDecoder(r.Body).Decode(&plan); err != nil {
+		logger.GetLogger().WithError(err).Error("failed to decode request")
+		metrics.HTTPRequestsTotal.WithLabelValues(r.Method, "/api/hotels/{id}/rooms/{roomId}/rate-plans", "400").Inc()
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	plan.RoomID = chi.URLParam(r, "roomId")
+
+	if err := h.useCase.CreateRatePlan(r.Context(), chi.URLParam(r, "id"), &plan); err != nil {
+		logger.GetLogger().WithError(err).Error("failed to create rate plan")
+		status := errorStatus(err)
+		metrics.HTTPRequestsTotal.WithLabelValues(r.Method, "/api/hotels/{id}/rooms/{roomId}/rate-plans", strconv.Itoa(status)).Inc()
+		http.Error(w, err.Error(), status)
+		return
+	}
+
+	metrics.HTTPRequestsTotal.WithLabelValues(r.Method, "/api/hotels/{id}/rooms/{roomId}/rate-plans", "201").Inc()
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(plan)
+}
+
+func (h *HotelHandler) GetRatePlans(w http.ResponseWriter, r *http.Request) {
+	start := time.Now()
+	defer func() {
+		metrics.HTTPRequestDuration.WithLabelValues(r.Method, "/api/hotels/{id}/rooms/{roomId}/rate-plans").Observe(time.Since(start).Seconds())
+	}()
+
+	plans, err := h.useCase.GetRatePlans(r.Context(), chi.URLParam(r, "id"), chi.URLParam(r, "roomId"))
+	if err != nil {
+		logger.GetLogger().WithError(err).Error("failed to get rate plans")
+		status := errorStatus(err)
+		metrics.HTTPRequestsTotal.WithLabelValues(r.Method, "/api/hotels/{id}/rooms/{roomId}/rate-plans", strconv.Itoa(status)).Inc()
+		http.Error(w, err.Error(), status)
+		return
+	}
+	if plans == nil {
+		plans = []domain.RatePlan{}
+	}
+
+	metrics.HTTPRequestsTotal.WithLabelValues(r.Method, "/api/hotels/{id}/rooms/{roomId}/rate-plans", "200").Inc()
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(plans)
+}
+
+func (h *HotelHandler) CreateFeeRule(w http.ResponseWriter, r *http.Request) {
+	start := time.Now()
+	defer func() {
+		metrics.HTTPRequestDuration.WithLabelValues(r.Method, "/api/hotels/{id}/fees").Observe(time.Since(start).Seconds())
+	}()
+
+	var rule domain.FeeRule
+	if err := json.NewDecoder(r.Body).Decode(&rule); err != nil {
+		logger.GetLogger().WithError(err).Error("failed to decode request")
+		metrics.HTTPRequestsTotal.WithLabelValues(r.Method, "/api/hotels/{id}/fees", "400").Inc()
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	rule.HotelID = chi.URLParam(r, "id")
+
+	if err := h.useCase.CreateFeeRule(r.Context(), &rule); err != nil {
+		logger.GetLogger().WithError(err).Error("failed to create fee rule")
+		status := errorStatus(err)
+		metrics.HTTPRequestsTotal.WithLabelValues(r.Method, "/api/hotels/{id}/fees", strconv.Itoa(status)).Inc()
+		http.Error(w, err.Error(), status)
+		return
+	}
+
+	metrics.HTTPRequestsTotal.WithLabelValues(r.Method, "/api/hotels/{id}/fees", "201").Inc()
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(rule)
+}
+
+func (h *HotelHandler) GetFeeRules(w http.ResponseWriter, r *http.Request) {
+	start := time.Now()
+	defer func() {
+		metrics.HTTPRequestDuration.WithLabelValues(r.Method, "/api/hotels/{id}/fees").Observe(time.Since(start).Seconds())
+	}()
+
+	rules, err := h.useCase.GetFeeRules(r.Context(), chi.URLParam(r, "id"))
+	if err != nil {
+		logger.GetLogger().WithError(err).Error("failed to get fee rules")
+		status := errorStatus(err)
+		metrics.HTTPRequestsTotal.WithLabelValues(r.Method, "/api/hotels/{id}/fees", strconv.Itoa(status)).Inc()
+		http.Error(w, err.Error(), status)
+		return
+	}
+	if rules == nil {
+		rules = []domain.FeeRule{}
+	}
+
+	metrics.HTTPRequestsTotal.WithLabelValues(r.Method, "/api/hotels/{id}/fees", "200").Inc()
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(rules)
+}
+
+func (h *HotelHandler) CreateCancellationPolicy(w http.ResponseWriter, r *http.Request) {
+	start := time.Now()
+	defer func() {
+		metrics.HTTPRequestDuration.WithLabelValues(r.Method, "/api/hotels/{id}/cancellation-policies").Observe(time.Since(start).Seconds())
+	}()
+
+	var policy domain.CancellationPolicy
+	if err := json.NewDecoder(r.Body).Decode(&policy); err != nil {
+		logger.GetLogger().WithError(err).Error("failed to decode request")
+		metrics.HTTPRequestsTotal.WithLabelValues(r.Method, "/api/hotels/{id}/cancellation-policies", "400").Inc()
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	policy.HotelID = chi.URLParam(r, "id")
+
+	if err := h.useCase.CreateCancellationPolicy(r.Context(), &policy); err != nil {
+		logger.GetLogger().WithError(err).Error("failed to create cancellation policy")
+		status := errorStatus(err)
+		metrics.HTTPRequestsTotal.WithLabelValues(r.Method, "/api/hotels/{id}/cancellation-policies", strconv.Itoa(status)).Inc()
+		http.Error(w, err.Error(), status)
+		return
+	}
+
+	metrics.HTTPRequestsTotal.WithLabelValues(r.Method, "/api/hotels/{id}/cancellation-policies", "201").Inc()
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(policy)
+}
+
+func (h *HotelHandler) GetCancellationPolicies(w http.ResponseWriter, r *http.Request) {
+	start := time.Now()
+	defer func() {
+		metrics.HTTPRequestDuration.WithLabelValues(r.Method, "/api/hotels/{id}/cancellation-policies").Observe(time.Since(start).Seconds())
+	}()
+
+	policies, err := h.useCase.GetCancellationPolicies(r.Context(), chi.URLParam(r, "id"))
+	if err != nil {
+		logger.GetLogger().WithError(err).Error("failed to get cancellation policies")
+		status := errorStatus(err)
+		metrics.HTTPRequestsTotal.WithLabelValues(r.Method, "/api/hotels/{id}/cancellation-policies", strconv.Itoa(status)).Inc()
+		http.Error(w, err.Error(), status)
+		return
+	}
+	if policies == nil {
+		policies = []domain.CancellationPolicy{}
+	}
+
+	metrics.HTTPRequestsTotal.WithLabelValues(r.Method, "/api/hotels/{id}/cancellation-policies", "200").Inc()
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(policies)
+}
+
+// quoteRequest prices the stay for one adult when Adults is omitted. Guests is
+// the number of adults in requests made before children were counted.
+type quoteRequest struct {
+	CheckIn  time.Time `json:"check_in"`
+	CheckOut time.Time `json:"check_out"`
+	Adults   int       `json:"adults"`
+	Children int       `json:"children"`
+	Guests   int       `json:"guests"`
+}
+
+func (h *HotelHandler) GetQuote(w http.ResponseWriter, r *http.Request) {
+	start := time.Now()
+	defer func() {
+		metrics.HTTPRequestDuration.WithLabelValues(r.Method, "/api/hotels/{id}/rooms/{roomId}/quote").Observe(time.Since(start).Seconds())
+	}()
+
+	var req quoteRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		logger.GetLogger().WithError(err).Error("failed to decode request")
+		metrics.HTTPRequestsTotal.WithLabelValues(r.Method, "/api/hotels/{id}/rooms/{roomId}/quote", "400").Inc()
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if req.Adults == 0 {
+		req.Adults = max(req.Guests, 1)
+	}
+
+	quote, err := h.useCase.GetQuote(r.Context(), chi.URLParam(r, "id"), chi.URLParam(r, "roomId"), req.CheckIn, req.CheckOut, req.Adults, req.Children)
+	if err != nil {
+		logger.GetLogger().WithError(err).Error("failed to quote room")
+		status := errorStatus(err)
+		metrics.HTTPRequestsTotal.WithLabelValues(r.Method, "/api/hotels/{id}/rooms/{roomId}/quote", strconv.Itoa(status)).Inc()
+		http.Error(w, err.Error(), status)
+		return
+	}
+
+	metrics.HTTPRequestsTotal.WithLabelValues(r.Method, "/api/hotels/{id}/rooms/{roomId}/quote", "200").Inc()
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(quote)
+}
+
+func errorStatus(err error) int {
+	switch {
+	case errors.Is(err, domain.ErrInvalidDateRange), errors.Is(err, domain.ErrInvalidGuests),
+		errors.Is(err, domain.ErrInvalidPrice), errors.Is(err, domain.ErrInvalidCurrency),
+		errors.Is(err, domain.ErrCurrencyMismatch), errors.Is(err, domain.ErrInvalidRatePlan),
+		errors.Is(err, domain.ErrInvalidFeeRule), errors.Is(err, domain.ErrInvalidCancellationPolicy),
+		errors.Is(err, domain.ErrCancellationPolicyNotFound), errors.Is(err, domain.ErrInvalidOccupancy):
+		return http.StatusBadRequest
+	case errors.Is(err, sql.ErrNoRows), errors.Is(err, domain.ErrRoomNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, domain.ErrCapacityExceeded):
+		return http.StatusUnprocessableEntity
+	default:
+		return http.StatusInternalServerError
+	}
+}
@@ -3,13 +3,16 @@ package http
 import (
 	"bytes"
 	"context"
+	"database/sql"
 	"encoding/json"
 	"errors"
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 
 	"hotel-booking-system/internal/hotel/domain"
+	"hotel-booking-system/pkg/money"
 
 	"github.com/go-chi/chi/v5"
 	"github.com/stretchr/testify/assert"
@@ -56,6 +59,61 @@ func (m *MockHotelUseCase) GetHotelWithRooms(ctx context.Context, hotelID string
 	return args.Get(0).(*domain.HotelWithRooms), args.Error(1)
 }
 
+func (m *MockHotelUseCase) GetAvailableRooms(ctx context.Context, hotelID string, checkIn, checkOut time.Time, guests int) ([]domain.Room, error) {
+	args := m.Called(ctx, hotelID, checkIn, checkOut, guests)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]domain.Room), args.Error(1)
+}
+
+func (m *MockHotelUseCase) CreateRatePlan(ctx context.Context, hotelID string, plan *domain.RatePlan) error {
+	args := m.Called(ctx, hotelID, plan)
+	return args.Error(0)
+}
+
+func (m *MockHotelUseCase) GetRatePlans(ctx context.Context, hotelID, roomID string) ([]domain.RatePlan, error) {
+	args := m.Called(ctx, hotelID, roomID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]domain.RatePlan), args.Error(1)
+}
+
+func (m *MockHotelUseCase) CreateFeeRule(ctx context.Context, rule *domain.FeeRule) error {
+	args := m.Called(ctx, rule)
+	return args.Error(0)
+}
+
+func (m *MockHotelUseCase) GetFeeRules(ctx context.Context, hotelID string) ([]domain.FeeRule, error) {
+	args := m.Called(ctx, hotelID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]domain.FeeRule), args.Error(1)
+}
+
+func (m *MockHotelUseCase) CreateCancellationPolicy(ctx context.Context, policy *domain.CancellationPolicy) error {
+	args := m.Called(ctx, policy)
+	return args.Error(0)
+}
+
+func (m *MockHotelUseCase) GetCancellationPolicies(ctx context.Context, hotelID string) ([]domain.CancellationPolicy, error) {
+	args := m.Called(ctx, hotelID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]domain.CancellationPolicy), args.Error(1)
+}
+
+func (m *MockHotelUseCase) GetQuote(ctx context.Context, hotelID, roomID string, checkIn, checkOut time.Time, adults, children int) (*domain.Quote, error) {
+	args := m.Called(ctx, hotelID, roomID, checkIn, checkOut, adults, children)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.Quote), args.Error(1)
+}
+
 func TestCreateHotel_Success(t *testing.T) {
 	mockUC := new(MockHotelUseCase)
 	handler := NewHotelHandler(mockUC)
@@ -158,7 +216,7 @@ func TestCreateRoom_Success(t *testing.T) {
 		HotelID:       "hotel123",
 		RoomNumber:    "101",
 		RoomType:      "Standard",
-		PricePerNight: 5000,
+		PricePerNight: money.New(500000, "RUB"),
 	}
 
 	mockUC.On("CreateRoom", mock.Anything, mock.Anything).Return(nil)
@@ -209,6 +267,23 @@ func TestCreateHotel_UseCaseError(t *testing.T) {
 	mockUC.AssertExpectations(t)
 }
 
+func TestCreateHotel_InvalidCurrency(t *testing.T) {
+	mockUC := new(MockHotelUseCase)
+	handler := NewHotelHandler(mockUC)
+
+	mockUC.On("CreateHotel", mock.Anything, mock.Anything).Return(domain.ErrInvalidCurrency)
+
+	body := []byte(`{"name":"Test Hotel","address":"Test Address","owner_id":"owner123","currency":"euro"}`)
+	req := httptest.NewRequest("POST", "/api/hotels", bytes.NewBuffer(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+
+	handler.CreateHotel(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	mockUC.AssertExpectations(t)
+}
+
 func TestGetHotels_Error(t *testing.T) {
 	mockUC := new(MockHotelUseCase)
 	handler := NewHotelHandler(mockUC)
@@ -255,6 +330,22 @@ func TestCreateRoom_InvalidJSON(t *testing.T) {
 	assert.Equal(t, http.StatusBadRequest, w.Code)
 }
 
+func TestCreateRoom_InvalidPrice(t *testing.T) {
+	mockUC := new(MockHotelUseCase)
+	handler := NewHotelHandler(mockUC)
+
+	mockUC.On("CreateRoom", mock.Anything, mock.Anything).Return(domain.ErrInvalidPrice)
+
+	body := []byte(`{"hotel_id":"hotel123","room_number":"101","price_per_night":{"amount":"-1.00","currency":"RUB"}}`)
+	req := httptest.NewRequest("POST", "/api/rooms", bytes.NewBuffer(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+
+	handler.CreateRoom(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
 func TestCreateRoom_UseCaseError(t *testing.T) {
 	mockUC := new(MockHotelUseCase)
 	handler := NewHotelHandler(mockUC)
@@ -263,7 +354,7 @@ func TestCreateRoom_UseCaseError(t *testing.T) {
 		HotelID:       "hotel123",
 		RoomNumber:    "101",
 		RoomType:      "Standard",
-		PricePerNight: 5000,
+		PricePerNight: money.New(500000, "RUB"),
 	}
 
 	mockUC.On("CreateRoom", mock.Anything, mock.Anything).Return(errors.New("database error"))
@@ -346,3 +437,311 @@ func TestUpdateHotel_UseCaseError(t *testing.T) {
 	assert.Equal(t, http.StatusInternalServerError, w.Code)
 	mockUC.AssertExpectations(t)
 }
+
+func TestGetAvailableRooms(t *testing.T) {
+	checkIn := time.Date(2024, 12, 20, 0, 0, 0, 0, time.UTC)
+	checkOut := time.Date(2024, 12, 25, 0, 0, 0, 0, time.UTC)
+
+	newRequest := func(target string) *http.Request {
+		req := httptest.NewRequest("GET", target, nil)
+		rctx := chi.NewRouteContext()
+		rctx.URLParams.Add("id", "hotel123")
+		return req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, rctx))
+	}
+
+	t.Run("success", func(t *testing.T) {
+		mockUC := new(MockHotelUseCase)
+		handler := NewHotelHandler(mockUC)
+
+		rooms := []domain.Room{{ID: "room1", HotelID: "hotel123", Capacity: 2}}
+		mockUC.On("GetAvailableRooms", mock.Anything, "hotel123", checkIn, checkOut, 2).Return(rooms, nil)
+
+		w := httptest.NewRecorder()
+		handler.GetAvailableRooms(w, newRequest("/api/hotels/hotel123/availability?check_in=2024-12-20&check_out=2024-12-25&guests=2"))
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		var response []domain.Room
+		json.Unmarshal(w.Body.Bytes(), &response)
+		assert.Len(t, response, 1)
+		mockUC.AssertExpectations(t)
+	})
+
+	t.Run("guests default to one", func(t *testing.T) {
+		mockUC := new(MockHotelUseCase)
+		handler := NewHotelHandler(mockUC)
+
+		mockUC.On("GetAvailableRooms", mock.Anything, "hotel123", checkIn, checkOut, 1).Return([]domain.Room{}, nil)
+
+		w := httptest.NewRecorder()
+		handler.GetAvailableRooms(w, newRequest("/api/hotels/hotel123/availability?check_in=2024-12-20&check_out=2024-12-25"))
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		mockUC.AssertExpectations(t)
+	})
+
+	t.Run("invalid dates", func(t *testing.T) {
+		mockUC := new(MockHotelUseCase)
+		handler := NewHotelHandler(mockUC)
+
+		w := httptest.NewRecorder()
+		handler.GetAvailableRooms(w, newRequest("/api/hotels/hotel123/availability?check_in=20.12.2024&check_out=2024-12-25"))
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		mockUC.AssertNotCalled(t, "GetAvailableRooms")
+	})
+
+	t.Run("invalid guests", func(t *testing.T) {
+		mockUC := new(MockHotelUseCase)
+		handler := NewHotelHandler(mockUC)
+
+		w := httptest.NewRecorder()
+		handler.GetAvailableRooms(w, newRequest("/api/hotels/hotel123/availability?check_in=2024-12-20&check_out=2024-12-25&guests=many"))
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		mockUC.AssertNotCalled(t, "GetAvailableRooms")
+	})
+
+	t.Run("validation error", func(t *testing.T) {
+		mockUC := new(MockHotelUseCase)
+		handler := NewHotelHandler(mockUC)
+
+		mockUC.On("GetAvailableRooms", mock.Anything, "hotel123", checkOut, checkIn, 1).Return(nil, domain.ErrInvalidDateRange)
+
+		w := httptest.NewRecorder()
+		handler.GetAvailableRooms(w, newRequest("/api/hotels/hotel123/availability?check_in=2024-12-25&check_out=2024-12-20"))
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		mockUC.AssertExpectations(t)
+	})
+
+	t.Run("hotel not found", func(t *testing.T) {
+		mockUC := new(MockHotelUseCase)
+		handler := NewHotelHandler(mockUC)
+
+		mockUC.On("GetAvailableRooms", mock.Anything, "hotel123", checkIn, checkOut, 1).Return(nil, sql.ErrNoRows)
+
+		w := httptest.NewRecorder()
+		handler.GetAvailableRooms(w, newRequest("/api/hotels/hotel123/availability?check_in=2024-12-20&check_out=2024-12-25"))
+
+		assert.Equal(t, http.StatusNotFound, w.Code)
+		mockUC.AssertExpectations(t)
+	})
+}
+
+func TestGetQuote(t *testing.T) {
+	checkIn := time.Date(2024, 12, 20, 14, 0, 0, 0, time.UTC)
+	checkOut := time.Date(2024, 12, 22, 12, 0, 0, 0, time.UTC)
+
+	newRequest := func(body string) *http.Request {
+		req := httptest.NewRequest("POST", "/api/hotels/hotel123/rooms/room123/quote", bytes.NewBufferString(body))
+		rctx := chi.NewRouteContext()
+		rctx.URLParams.Add("id", "hotel123")
+		rctx.URLParams.Add("roomId", "room123")
+		return req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, rctx))
+	}
+	body := `{"check_in":"2024-12-20T14:00:00Z","check_out":"2024-12-22T12:00:00Z"}`
+
+	t.Run("success", func(t *testing.T) {
+		mockUC := new(MockHotelUseCase)
+		handler := NewHotelHandler(mockUC)
+
+		quote := &domain.Quote{
+			HotelID: "hotel123",
+			RoomID:  "room123",
+			Nights: []domain.NightlyRate{
+				{Date: time.Date(2024, 12, 20, 0, 0, 0, 0, time.UTC), Price: money.New(700000, "RUB"), RatePlanID: "plan123"},
+				{Date: time.Date(2024, 12, 21, 0, 0, 0, 0, time.UTC), Price: money.New(500000, "RUB")},
+			},
+			Subtotal: money.New(1200000, "RUB"),
+			Fees: []domain.Charge{
+				{FeeRuleID: "fee123", Name: "Туристический налог", Kind: domain.FeeKindTax, Amount: money.New(24000, "RUB")},
+			},
+			Total: money.New(1224000, "RUB"),
+		}
+		mockUC.On("GetQuote", mock.Anything, "hotel123", "room123", checkIn, checkOut, 1, 0).Return(quote, nil)
+
+		w := httptest.NewRecorder()
+		handler.GetQuote(w, newRequest(body))
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		var response domain.Quote
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		assert.Len(t, response.Nights, 2)
+		assert.Len(t, response.Fees, 1)
+		assert.Equal(t, money.New(1224000, "RUB"), response.Total)
+		mockUC.AssertExpectations(t)
+	})
+
+	t.Run("guests", func(t *testing.T) {
+		mockUC := new(MockHotelUseCase)
+		handler := NewHotelHandler(mockUC)
+
+		mockUC.On("GetQuote", mock.Anything, "hotel123", "room123", checkIn, checkOut, 3, 0).Return(&domain.Quote{Guests: 3}, nil)
+
+		w := httptest.NewRecorder()
+		handler.GetQuote(w, newRequest(`{"check_in":"2024-12-20T14:00:00Z","check_out":"2024-12-22T12:00:00Z","guests":3}`))
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		mockUC.AssertExpectations(t)
+	})
+
+	t.Run("adults and children", func(t *testing.T) {
+		mockUC := new(MockHotelUseCase)
+		handler := NewHotelHandler(mockUC)
+
+		mockUC.On("GetQuote", mock.Anything, "hotel123", "room123", checkIn, checkOut, 2, 1).Return(&domain.Quote{Adults: 2, Children: 1, Guests: 3}, nil)
+
+		w := httptest.NewRecorder()
+		handler.GetQuote(w, newRequest(`{"check_in":"2024-12-20T14:00:00Z","check_out":"2024-12-22T12:00:00Z","adults":2,"children":1}`))
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		mockUC.AssertExpectations(t)
+	})
+
+	t.Run("more guests than the room holds", func(t *testing.T) {
+		mockUC := new(MockHotelUseCase)
+		handler := NewHotelHandler(mockUC)
+
+		mockUC.On("GetQuote", mock.Anything, "hotel123", "room123", checkIn, checkOut, 4, 2).Return(nil, domain.ErrCapacityExceeded)
+
+		w := httptest.NewRecorder()
+		handler.GetQuote(w, newRequest(`{"check_in":"2024-12-20T14:00:00Z","check_out":"2024-12-22T12:00:00Z","adults":4,"children":2}`))
+
+		assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
+	})
+
+	t.Run("invalid body", func(t *testing.T) {
+		mockUC := new(MockHotelUseCase)
+		handler := NewHotelHandler(mockUC)
+
+		w := httptest.NewRecorder()
+		handler.GetQuote(w, newRequest(`{"check_in":"20.12.2024"}`))
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		mockUC.AssertNotCalled(t, "GetQuote")
+	})
+
+	t.Run("room in another hotel", func(t *testing.T) {
+		mockUC := new(MockHotelUseCase)
+		handler := NewHotelHandler(mockUC)
+
+		mockUC.On("GetQuote", mock.Anything, "hotel123", "room123", checkIn, checkOut, 1, 0).Return(nil, domain.ErrRoomNotFound)
+
+		w := httptest.NewRecorder()
+		handler.GetQuote(w, newRequest(body))
+
+		assert.Equal(t, http.StatusNotFound, w.Code)
+	})
+}
+
+func TestCreateRatePlan(t *testing.T) {
+	newRequest := func(body string) *http.Request {
+		req := httptest.NewRequest("POST", "/api/hotels/hotel123/rooms/room123/rate-plans", bytes.NewBufferString(body))
+		rctx := chi.NewRouteContext()
+		rctx.URLParams.Add("id", "hotel123")
+		rctx.URLParams.Add("roomId", "room123")
+		return req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, rctx))
+	}
+	body := `{"name":"Выходные","price_per_night":{"amount":"7000.00","currency":"RUB"},"days_of_week":[5,6]}`
+
+	t.Run("success", func(t *testing.T) {
+		mockUC := new(MockHotelUseCase)
+		handler := NewHotelHandler(mockUC)
+
+		mockUC.On("CreateRatePlan", mock.Anything, "hotel123", mock.MatchedBy(func(plan *domain.RatePlan) bool {
+			return plan.RoomID == "room123" && plan.PricePerNight == money.New(700000, "RUB") && len(plan.DaysOfWeek) == 2
+		})).Return(nil)
+
+		w := httptest.NewRecorder()
+		handler.CreateRatePlan(w, newRequest(body))
+
+		assert.Equal(t, http.StatusCreated, w.Code)
+		mockUC.AssertExpectations(t)
+	})
+
+	t.Run("invalid plan", func(t *testing.T) {
+		mockUC := new(MockHotelUseCase)
+		handler := NewHotelHandler(mockUC)
+
+		mockUC.On("CreateRatePlan", mock.Anything, "hotel123", mock.Anything).Return(domain.ErrInvalidRatePlan)
+
+		w := httptest.NewRecorder()
+		handler.CreateRatePlan(w, newRequest(body))
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+}
+
+func TestCreateFeeRule(t *testing.T) {
+	newRequest := func(body string) *http.Request {
+		req := httptest.NewRequest("POST", "/api/hotels/hotel123/fees", bytes.NewBufferString(body))
+		rctx := chi.NewRouteContext()
+		rctx.URLParams.Add("id", "hotel123")
+		return req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, rctx))
+	}
+	body := `{"name":"Уборка","kind":"fee","calculation":"fixed","amount":{"amount":"1000.00","currency":"RUB"}}`
+
+	t.Run("success", func(t *testing.T) {
+		mockUC := new(MockHotelUseCase)
+		handler := NewHotelHandler(mockUC)
+
+		mockUC.On("CreateFeeRule", mock.Anything, mock.MatchedBy(func(rule *domain.FeeRule) bool {
+			return rule.HotelID == "hotel123" && rule.Kind == domain.FeeKindFee && *rule.Amount == money.New(100000, "RUB")
+		})).Return(nil)
+
+		w := httptest.NewRecorder()
+		handler.CreateFeeRule(w, newRequest(body))
+
+		assert.Equal(t, http.StatusCreated, w.Code)
+		mockUC.AssertExpectations(t)
+	})
+
+	t.Run("invalid rule", func(t *testing.T) {
+		mockUC := new(MockHotelUseCase)
+		handler := NewHotelHandler(mockUC)
+
+		mockUC.On("CreateFeeRule", mock.Anything, mock.Anything).Return(domain.ErrInvalidFeeRule)
+
+		w := httptest.NewRecorder()
+		handler.CreateFeeRule(w, newRequest(body))
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+}
+
+func TestCreateCancellationPolicy(t *testing.T) {
+	newRequest := func(body string) *http.Request {
+		req := httptest.NewRequest("POST", "/api/hotels/hotel123/cancellation-policies", bytes.NewBufferString(body))
+		rctx := chi.NewRouteContext()
+		rctx.URLParams.Add("id", "hotel123")
+		return req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, rctx))
+	}
+	body := `{"name":"Гибкий","rules":[{"hours_before_check_in":48,"refund_basis_points":10000},{"hours_before_check_in":0,"refund_basis_points":5000}]}`
+
+	t.Run("success", func(t *testing.T) {
+		mockUC := new(MockHotelUseCase)
+		handler := NewHotelHandler(mockUC)
+
+		mockUC.On("CreateCancellationPolicy", mock.Anything, mock.MatchedBy(func(policy *domain.CancellationPolicy) bool {
+			return policy.HotelID == "hotel123" && len(policy.Rules) == 2 && policy.Rules[1].RefundBasisPoints == 5000
+		})).Return(nil)
+
+		w := httptest.NewRecorder()
+		handler.CreateCancellationPolicy(w, newRequest(body))
+
+		assert.Equal(t, http.StatusCreated, w.Code)
+		mockUC.AssertExpectations(t)
+	})
+
+	t.Run("invalid policy", func(t *testing.T) {
+		mockUC := new(MockHotelUseCase)
+		handler := NewHotelHandler(mockUC)
+
+		mockUC.On("CreateCancellationPolicy", mock.Anything, mock.Anything).Return(domain.ErrInvalidCancellationPolicy)
+
+		w := httptest.NewRecorder()
+		handler.CreateCancellationPolicy(w, newRequest(body))
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+}
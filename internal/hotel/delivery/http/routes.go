@@ -19,6 +19,14 @@ func SetupRoutes(handler *HotelHandler) *chi.Mux {
 			r.Get("/{id}", handler.GetHotel)
 			r.Put("/{id}", handler.UpdateHotel)
 			r.Get("/{id}/rooms", handler.GetHotelWithRooms)
+			r.Get("/{id}/availability", handler.GetAvailableRooms)
+			r.Get("/{id}/rooms/{roomId}/rate-plans", handler.GetRatePlans)
+			r.Post("/{id}/rooms/{roomId}/rate-plans", handler.CreateRatePlan)
+			r.Post("/{id}/rooms/{roomId}/quote", handler.GetQuote)
+			r.Get("/{id}/fees", handler.GetFeeRules)
+			r.Post("/{id}/fees", handler.CreateFeeRule)
+			r.Get("/{id}/cancellation-policies", handler.GetCancellationPolicies)
+			r.Post("/{id}/cancellation-policies", handler.CreateCancellationPolicy)
 		})
 
 		r.Route("/rooms", func(r chi.Router) {
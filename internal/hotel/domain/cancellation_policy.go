@@ -0,0 +1,48 @@
+package domain
+
+import (
+	"sort"
+	"time"
+)
+
+// CancellationPolicy decides how much of the price a guest gets back when
+// cancelling. A cancellation made at least HoursBeforeCheckIn hours before
+// check-in is refunded by the rule with the largest such threshold; later
+// cancellations, and every cancellation under a policy without rules, are not
+// refunded. Rooms and rate plans without a policy are fully refundable.
+type CancellationPolicy struct {
+	ID        string             `json:"id"`
+	HotelID   string             `json:"hotel_id"`
+	Name      string             `json:"name"`
+	Rules     []CancellationRule `json:"rules"`
+	CreatedAt time.Time          `json:"created_at"`
+}
+
+// CancellationRule refunds RefundBasisPoints of the price (10000 is 100%).
+type CancellationRule struct {
+	HoursBeforeCheckIn int   `json:"hours_before_check_in"`
+	RefundBasisPoints  int64 `json:"refund_basis_points"`
+}
+
+// Validate checks the rules and sorts them from the earliest cancellation
+// deadline to the latest.
+func (p *CancellationPolicy) Validate() error {
+	if p.Name == "" {
+		return ErrInvalidCancellationPolicy
+	}
+	seen := make(map[int]bool, len(p.Rules))
+	for _, rule := range p.Rules {
+		if rule.HoursBeforeCheckIn < 0 || rule.RefundBasisPoints < 0 || rule.RefundBasisPoints > 10000 ||
+			seen[rule.HoursBeforeCheckIn] {
+			return ErrInvalidCancellationPolicy
+		}
+		seen[rule.HoursBeforeCheckIn] = true
+	}
+	if p.Rules == nil {
+		p.Rules = []CancellationRule{}
+	}
+	sort.Slice(p.Rules, func(i, j int) bool {
+		return p.Rules[i].HoursBeforeCheckIn > p.Rules[j].HoursBeforeCheckIn
+	})
+	return nil
+}
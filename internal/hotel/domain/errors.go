@@ -0,0 +1,19 @@
+package domain
+
+import "errors"
+
+var (
+	ErrInvalidDateRange = errors.New("check-in date must be before check-out date")
+	ErrInvalidGuests    = errors.New("number of guests must be positive")
+	ErrCapacityExceeded = errors.New("number of guests exceeds room capacity")
+	ErrInvalidOccupancy = errors.New("base occupancy must be between 0 and the room capacity")
+	ErrInvalidPrice     = errors.New("price must be non-negative and have a currency")
+	ErrInvalidCurrency  = errors.New("currency must be a three-letter ISO 4217 code")
+	ErrCurrencyMismatch = errors.New("room price must be in the hotel's currency")
+	ErrRoomNotFound     = errors.New("room not found in this hotel")
+	ErrInvalidRatePlan  = errors.New("rate plan must have a name, a valid date range, days of week from 0 to 6 and a non-negative minimum stay")
+	ErrInvalidFeeRule   = errors.New("fee rule must have a name, a kind of tax or fee, and a positive percentage or fixed amount")
+
+	ErrInvalidCancellationPolicy  = errors.New("cancellation policy must have a name and rules with distinct non-negative hours and refunds from 0 to 10000 basis points")
+	ErrCancellationPolicyNotFound = errors.New("cancellation policy not found in this hotel")
+)
@@ -0,0 +1,96 @@
+package domain
+
+import (
+	"time"
+
+	"hotel-booking-system/pkg/money"
+)
+
+type FeeKind string
+
+const (
+	FeeKindTax FeeKind = "tax"
+	FeeKindFee FeeKind = "fee"
+)
+
+type FeeCalculation string
+
+const (
+	FeeCalculationPercentage FeeCalculation = "percentage"
+	FeeCalculationFixed      FeeCalculation = "fixed"
+)
+
+// FeeRule is a tax or fee a hotel adds to the accommodation price. A
+// percentage rule takes BasisPoints of the accommodation price (250 is 2.5%),
+// a fixed rule charges Amount. PerNight charges every night instead of once
+// per stay, PerGuest charges every guest instead of once per room.
+type FeeRule struct {
+	ID          string         `json:"id"`
+	HotelID     string         `json:"hotel_id"`
+	Name        string         `json:"name"`
+	Kind        FeeKind        `json:"kind"`
+	Calculation FeeCalculation `json:"calculation"`
+	BasisPoints int64          `json:"basis_points,omitempty"`
+	Amount      *money.Money   `json:"amount,omitempty"`
+	PerNight    bool           `json:"per_night"`
+	PerGuest    bool           `json:"per_guest"`
+	CreatedAt   time.Time      `json:"created_at"`
+}
+
+// Charge computes the rule for a stay priced by nights.
+func (r *FeeRule) Charge(nights []NightlyRate, guests int) (money.Money, error) {
+	var charge money.Money
+	var err error
+	switch {
+	case r.Calculation == FeeCalculationFixed && r.PerNight:
+		if charge, err = r.Amount.Mul(int64(len(nights))); err != nil {
+			return money.Money{}, err
+		}
+	case r.Calculation == FeeCalculationFixed:
+		charge = *r.Amount
+	case r.PerNight:
+		for _, night := range nights {
+			if charge, err = charge.Add(night.Price.Percent(r.BasisPoints)); err != nil {
+				return money.Money{}, err
+			}
+		}
+	default:
+		var subtotal money.Money
+		for _, night := range nights {
+			if subtotal, err = subtotal.Add(night.Price); err != nil {
+				return money.Money{}, err
+			}
+		}
+		charge = subtotal.Percent(r.BasisPoints)
+	}
+
+	if r.PerGuest {
+		if charge, err = charge.Mul(int64(guests)); err != nil {
+			return money.Money{}, err
+		}
+	}
+	return charge, nil
+}
+
+// EffectiveBasisPoints is the share of the accommodation price a percentage
+// rule takes for the given number of guests; zero for a fixed rule.
+func (r *FeeRule) EffectiveBasisPoints(guests int) int64 {
+	if r.Calculation != FeeCalculationPercentage {
+		return 0
+	}
+	if r.PerGuest {
+		return r.BasisPoints * int64(guests)
+	}
+	return r.BasisPoints
+}
+
+// Charge is a tax or fee line of a quote. BasisPoints is the share of the
+// accommodation price a percentage charge takes, so that a discounted price
+// can be charged again.
+type Charge struct {
+	FeeRuleID   string      `json:"fee_rule_id"`
+	Name        string      `json:"name"`
+	Kind        FeeKind     `json:"kind"`
+	BasisPoints int64       `json:"basis_points,omitempty"`
+	Amount      money.Money `json:"amount"`
+}
@@ -2,6 +2,8 @@ package domain
 
 import (
 	"time"
+
+	"hotel-booking-system/pkg/money"
 )
 
 type Hotel struct {
@@ -10,21 +12,72 @@ type Hotel struct {
 	Description string    `json:"description"`
 	Address     string    `json:"address"`
 	OwnerID     string    `json:"owner_id"`
+	Currency    string    `json:"currency"`
 	CreatedAt   time.Time `json:"created_at"`
 	UpdatedAt   time.Time `json:"updated_at"`
 }
 
+// Room holds up to Capacity guests. The nightly price covers BaseOccupancy
+// of them, or all of them when BaseOccupancy is zero; every further adult or
+// child costs ExtraAdultPrice or ExtraChildPrice a night, nothing when unset.
 type Room struct {
-	ID            string    `json:"id"`
-	HotelID       string    `json:"hotel_id"`
-	RoomNumber    string    `json:"room_number"`
-	RoomType      string    `json:"room_type"`
-	PricePerNight float64   `json:"price_per_night"`
-	Capacity      int       `json:"capacity"`
-	Description   string    `json:"description"`
-	IsAvailable   bool      `json:"is_available"`
-	CreatedAt     time.Time `json:"created_at"`
-	UpdatedAt     time.Time `json:"updated_at"`
+	ID                   string       `json:"id"`
+	HotelID              string       `json:"hotel_id"`
+	RoomNumber           string       `json:"room_number"`
+	RoomType             string       `json:"room_type"`
+	PricePerNight        money.Money  `json:"price_per_night"`
+	Capacity             int          `json:"capacity"`
+	BaseOccupancy        int          `json:"base_occupancy"`
+	ExtraAdultPrice      *money.Money `json:"extra_adult_price,omitempty"`
+	ExtraChildPrice      *money.Money `json:"extra_child_price,omitempty"`
+	Description          string       `json:"description"`
+	IsAvailable          bool         `json:"is_available"`
+	CancellationPolicyID string       `json:"cancellation_policy_id,omitempty"`
+	CreatedAt            time.Time    `json:"created_at"`
+	UpdatedAt            time.Time    `json:"updated_at"`
+}
+
+// ValidateOccupancy checks the guests fit into the room: at least one adult,
+// no negative number of children and no more guests than its capacity.
+func (r *Room) ValidateOccupancy(adults, children int) error {
+	if adults < 1 || children < 0 {
+		return ErrInvalidGuests
+	}
+	if adults+children > r.Capacity {
+		return ErrCapacityExceeded
+	}
+	return nil
+}
+
+// ExtraGuestCharge is the nightly surcharge for the guests above the base
+// occupancy. Adults take the places the price covers first.
+func (r *Room) ExtraGuestCharge(adults, children int) (money.Money, error) {
+	charge := money.New(0, r.PricePerNight.Currency)
+	if r.BaseOccupancy == 0 {
+		return charge, nil
+	}
+
+	extraAdults := max(adults-r.BaseOccupancy, 0)
+	extraChildren := max(children-max(r.BaseOccupancy-adults, 0), 0)
+	if r.ExtraAdultPrice != nil {
+		surcharge, err := r.ExtraAdultPrice.Mul(int64(extraAdults))
+		if err != nil {
+			return money.Money{}, err
+		}
+		if charge, err = charge.Add(surcharge); err != nil {
+			return money.Money{}, err
+		}
+	}
+	if r.ExtraChildPrice != nil {
+		surcharge, err := r.ExtraChildPrice.Mul(int64(extraChildren))
+		if err != nil {
+			return money.Money{}, err
+		}
+		if charge, err = charge.Add(surcharge); err != nil {
+			return money.Money{}, err
+		}
+	}
+	return charge, nil
 }
 
 type HotelWithRooms struct {
@@ -4,6 +4,8 @@ import (
 	"testing"
 	"time"
 
+	"hotel-booking-system/pkg/money"
+
 	"github.com/stretchr/testify/assert"
 )
 
@@ -31,7 +33,7 @@ func TestRoom(t *testing.T) {
 		HotelID:       "hotel123",
 		RoomNumber:    "101",
 		RoomType:      "Standard",
-		PricePerNight: 5000.0,
+		PricePerNight: money.New(500000, "RUB"),
 		Capacity:      2,
 		Description:   "Test Room",
 		IsAvailable:   true,
@@ -43,11 +45,56 @@ func TestRoom(t *testing.T) {
 	assert.Equal(t, "hotel123", room.HotelID)
 	assert.Equal(t, "101", room.RoomNumber)
 	assert.Equal(t, "Standard", room.RoomType)
-	assert.Equal(t, 5000.0, room.PricePerNight)
+	assert.Equal(t, money.New(500000, "RUB"), room.PricePerNight)
 	assert.Equal(t, 2, room.Capacity)
 	assert.True(t, room.IsAvailable)
 }
 
+func TestRoom_ValidateOccupancy(t *testing.T) {
+	room := Room{Capacity: 3}
+
+	assert.NoError(t, room.ValidateOccupancy(2, 1))
+	assert.ErrorIs(t, room.ValidateOccupancy(0, 2), ErrInvalidGuests)
+	assert.ErrorIs(t, room.ValidateOccupancy(1, -1), ErrInvalidGuests)
+	assert.ErrorIs(t, room.ValidateOccupancy(2, 2), ErrCapacityExceeded)
+}
+
+func TestRoom_ExtraGuestCharge(t *testing.T) {
+	extraAdult := money.New(150000, "RUB")
+	extraChild := money.New(50000, "RUB")
+	room := Room{
+		PricePerNight:   money.New(500000, "RUB"),
+		Capacity:        4,
+		BaseOccupancy:   2,
+		ExtraAdultPrice: &extraAdult,
+		ExtraChildPrice: &extraChild,
+	}
+
+	tests := []struct {
+		name             string
+		adults, children int
+		want             money.Money
+	}{
+		{name: "within base occupancy", adults: 1, children: 1, want: money.New(0, "RUB")},
+		{name: "extra adult", adults: 3, children: 0, want: money.New(150000, "RUB")},
+		{name: "children after adults", adults: 2, children: 2, want: money.New(100000, "RUB")},
+		{name: "extra adult and child", adults: 3, children: 1, want: money.New(200000, "RUB")},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			charge, err := room.ExtraGuestCharge(tt.adults, tt.children)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, charge)
+		})
+	}
+
+	room.BaseOccupancy = 0
+	charge, err := room.ExtraGuestCharge(4, 0)
+	assert.NoError(t, err)
+	assert.True(t, charge.IsZero())
+}
+
 func TestHotelWithRooms(t *testing.T) {
 	hotel := Hotel{
 		ID:   "hotel123",
@@ -67,3 +114,90 @@ func TestHotelWithRooms(t *testing.T) {
 	assert.Equal(t, hotel.ID, hotelWithRooms.Hotel.ID)
 	assert.Len(t, hotelWithRooms.Rooms, 2)
 }
+
+func TestRatePlan_AppliesTo(t *testing.T) {
+	start := time.Date(2024, 12, 20, 0, 0, 0, 0, time.UTC)
+	end := time.Date(2024, 12, 31, 0, 0, 0, 0, time.UTC)
+	plan := RatePlan{StartDate: &start, EndDate: &end, DaysOfWeek: []int{int(time.Saturday)}, MinNights: 2}
+
+	saturday := time.Date(2024, 12, 21, 0, 0, 0, 0, time.UTC)
+	assert.True(t, plan.AppliesTo(saturday, 2))
+	assert.False(t, plan.AppliesTo(saturday, 1))
+	assert.False(t, plan.AppliesTo(saturday.AddDate(0, 0, 1), 2))
+	assert.False(t, plan.AppliesTo(saturday.AddDate(0, 0, 14), 2))
+
+	assert.True(t, (&RatePlan{EndDate: &end}).AppliesTo(end, 1))
+}
+
+func TestStayNights(t *testing.T) {
+	nights := StayNights(
+		time.Date(2024, 12, 20, 14, 0, 0, 0, time.UTC),
+		time.Date(2024, 12, 23, 12, 0, 0, 0, time.UTC),
+	)
+	assert.Equal(t, []time.Time{
+		time.Date(2024, 12, 20, 0, 0, 0, 0, time.UTC),
+		time.Date(2024, 12, 21, 0, 0, 0, 0, time.UTC),
+		time.Date(2024, 12, 22, 0, 0, 0, 0, time.UTC),
+	}, nights)
+
+	sameDay := time.Date(2024, 12, 20, 10, 0, 0, 0, time.UTC)
+	assert.Len(t, StayNights(sameDay, sameDay.Add(8*time.Hour)), 1)
+}
+
+func TestFeeRule_Charge(t *testing.T) {
+	nights := []NightlyRate{
+		{Price: money.New(333333, "RUB")},
+		{Price: money.New(333333, "RUB")},
+	}
+	fixed := money.New(10000, "RUB")
+
+	tests := []struct {
+		name string
+		rule FeeRule
+		want money.Money
+	}{
+		{name: "percentage per stay", rule: FeeRule{Calculation: FeeCalculationPercentage, BasisPoints: 250}, want: money.New(16667, "RUB")},
+		{name: "percentage per night", rule: FeeRule{Calculation: FeeCalculationPercentage, BasisPoints: 250, PerNight: true}, want: money.New(16666, "RUB")},
+		{name: "fixed per stay", rule: FeeRule{Calculation: FeeCalculationFixed, Amount: &fixed}, want: money.New(10000, "RUB")},
+		{name: "fixed per night and guest", rule: FeeRule{Calculation: FeeCalculationFixed, Amount: &fixed, PerNight: true, PerGuest: true}, want: money.New(60000, "RUB")},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			charge, err := tt.rule.Charge(nights, 3)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, charge)
+		})
+	}
+}
+
+func TestFeeRule_EffectiveBasisPoints(t *testing.T) {
+	fixed := money.New(10000, "RUB")
+
+	assert.Equal(t, int64(250), (&FeeRule{Calculation: FeeCalculationPercentage, BasisPoints: 250, PerNight: true}).EffectiveBasisPoints(3))
+	assert.Equal(t, int64(750), (&FeeRule{Calculation: FeeCalculationPercentage, BasisPoints: 250, PerGuest: true}).EffectiveBasisPoints(3))
+	assert.Zero(t, (&FeeRule{Calculation: FeeCalculationFixed, Amount: &fixed}).EffectiveBasisPoints(3))
+}
+
+func TestCancellationPolicy_Validate(t *testing.T) {
+	policy := CancellationPolicy{Name: "Гибкий", Rules: []CancellationRule{
+		{HoursBeforeCheckIn: 0, RefundBasisPoints: 5000},
+		{HoursBeforeCheckIn: 48, RefundBasisPoints: 10000},
+	}}
+	assert.NoError(t, policy.Validate())
+	assert.Equal(t, 48, policy.Rules[0].HoursBeforeCheckIn)
+
+	nonRefundable := CancellationPolicy{Name: "Невозвратный"}
+	assert.NoError(t, nonRefundable.Validate())
+	assert.NotNil(t, nonRefundable.Rules)
+
+	invalid := []CancellationPolicy{
+		{Rules: []CancellationRule{{HoursBeforeCheckIn: 48, RefundBasisPoints: 10000}}},
+		{Name: "Сезон", Rules: []CancellationRule{{HoursBeforeCheckIn: -1, RefundBasisPoints: 10000}}},
+		{Name: "Сезон", Rules: []CancellationRule{{HoursBeforeCheckIn: 24, RefundBasisPoints: 10001}}},
+		{Name: "Сезон", Rules: []CancellationRule{{HoursBeforeCheckIn: 24, RefundBasisPoints: 5000}, {HoursBeforeCheckIn: 24}}},
+	}
+	for _, p := range invalid {
+		assert.ErrorIs(t, p.Validate(), ErrInvalidCancellationPolicy)
+	}
+}
@@ -0,0 +1,94 @@
+package domain
+
+import (
+	"time"
+
+	"hotel-booking-system/pkg/money"
+)
+
+// RatePlan overrides a room's base price for the nights it applies to. A night
+// is priced by the applicable plan with the highest priority; nights no plan
+// applies to cost Room.PricePerNight. A plan with a CancellationPolicyID
+// overrides the room's policy for stays whose first night it prices.
+type RatePlan struct {
+	ID                   string      `json:"id"`
+	RoomID               string      `json:"room_id"`
+	Name                 string      `json:"name"`
+	PricePerNight        money.Money `json:"price_per_night"`
+	StartDate            *time.Time  `json:"start_date,omitempty"`
+	EndDate              *time.Time  `json:"end_date,omitempty"`
+	DaysOfWeek           []int       `json:"days_of_week,omitempty"`
+	MinNights            int         `json:"min_nights"`
+	Priority             int         `json:"priority"`
+	CancellationPolicyID string      `json:"cancellation_policy_id,omitempty"`
+	CreatedAt            time.Time   `json:"created_at"`
+}
+
+// AppliesTo reports whether the plan prices the night starting on the given
+// date of a stay of the given length. StartDate and EndDate are inclusive;
+// DaysOfWeek uses time.Weekday numbering (0 is Sunday) and matches every day
+// when empty.
+func (p *RatePlan) AppliesTo(night time.Time, nights int) bool {
+	if nights < p.MinNights {
+		return false
+	}
+	if p.StartDate != nil && night.Before(truncateDay(*p.StartDate)) {
+		return false
+	}
+	if p.EndDate != nil && night.After(truncateDay(*p.EndDate)) {
+		return false
+	}
+	if len(p.DaysOfWeek) == 0 {
+		return true
+	}
+	for _, day := range p.DaysOfWeek {
+		if time.Weekday(day) == night.Weekday() {
+			return true
+		}
+	}
+	return false
+}
+
+// NightlyRate is the price of a night including ExtraGuestCharge, the
+// surcharge for guests above the room's base occupancy.
+type NightlyRate struct {
+	Date             time.Time   `json:"date"`
+	Price            money.Money `json:"price"`
+	ExtraGuestCharge money.Money `json:"extra_guest_charge"`
+	RatePlanID       string      `json:"rate_plan_id,omitempty"`
+}
+
+// Quote prices a stay: Subtotal is the sum of the nights, Total adds the
+// hotel's taxes and fees to it. CancellationPolicy is nil when the stay is
+// fully refundable. Guests is the number of adults and children together.
+type Quote struct {
+	HotelID            string              `json:"hotel_id"`
+	RoomID             string              `json:"room_id"`
+	RoomType           string              `json:"room_type"`
+	Capacity           int                 `json:"capacity"`
+	CheckIn            time.Time           `json:"check_in"`
+	CheckOut           time.Time           `json:"check_out"`
+	Adults             int                 `json:"adults"`
+	Children           int                 `json:"children"`
+	Guests             int                 `json:"guests"`
+	Nights             []NightlyRate       `json:"nights"`
+	Subtotal           money.Money         `json:"subtotal"`
+	Fees               []Charge            `json:"fees"`
+	Total              money.Money         `json:"total"`
+	CancellationPolicy *CancellationPolicy `json:"cancellation_policy,omitempty"`
+}
+
+// StayNights returns the dates of the nights between check-in and check-out.
+// A stay that starts and ends on the same date counts as one night.
+func StayNights(checkIn, checkOut time.Time) []time.Time {
+	first, last := truncateDay(checkIn), truncateDay(checkOut)
+	nights := []time.Time{first}
+	for night := first.AddDate(0, 0, 1); night.Before(last); night = night.AddDate(0, 0, 1) {
+		nights = append(nights, night)
+	}
+	return nights
+}
+
+func truncateDay(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
@@ -1,6 +1,11 @@
 package domain
 
-import "context"
+import (
+	"context"
+	"time"
+
+	"hotel-booking-system/pkg/money"
+)
 
 type HotelRepository interface {
 	CreateHotel(ctx context.Context, hotel *Hotel) error
@@ -17,7 +22,23 @@ type RoomRepository interface {
 	GetRoomsByHotel(ctx context.Context, hotelID string) ([]Room, error)
 	UpdateRoom(ctx context.Context, room *Room) error
 	DeleteRoom(ctx context.Context, id string) error
-	GetRoomPrice(ctx context.Context, hotelID, roomID string) (float64, error)
+	GetRoomPrice(ctx context.Context, hotelID, roomID string) (money.Money, error)
+}
+
+type RatePlanRepository interface {
+	CreateRatePlan(ctx context.Context, plan *RatePlan) error
+	GetRatePlansByRoom(ctx context.Context, roomID string) ([]RatePlan, error)
+}
+
+type FeeRuleRepository interface {
+	CreateFeeRule(ctx context.Context, rule *FeeRule) error
+	GetFeeRulesByHotel(ctx context.Context, hotelID string) ([]FeeRule, error)
+}
+
+type CancellationPolicyRepository interface {
+	CreateCancellationPolicy(ctx context.Context, policy *CancellationPolicy) error
+	GetCancellationPolicyByID(ctx context.Context, id string) (*CancellationPolicy, error)
+	GetCancellationPoliciesByHotel(ctx context.Context, hotelID string) ([]CancellationPolicy, error)
 }
 
 type HotelUseCase interface {
@@ -27,4 +48,12 @@ type HotelUseCase interface {
 	UpdateHotel(ctx context.Context, hotel *Hotel) error
 	CreateRoom(ctx context.Context, room *Room) error
 	GetHotelWithRooms(ctx context.Context, hotelID string) (*HotelWithRooms, error)
+	GetAvailableRooms(ctx context.Context, hotelID string, checkIn, checkOut time.Time, guests int) ([]Room, error)
+	CreateRatePlan(ctx context.Context, hotelID string, plan *RatePlan) error
+	GetRatePlans(ctx context.Context, hotelID, roomID string) ([]RatePlan, error)
+	CreateFeeRule(ctx context.Context, rule *FeeRule) error
+	GetFeeRules(ctx context.Context, hotelID string) ([]FeeRule, error)
+	CreateCancellationPolicy(ctx context.Context, policy *CancellationPolicy) error
+	GetCancellationPolicies(ctx context.Context, hotelID string) ([]CancellationPolicy, error)
+	GetQuote(ctx context.Context, hotelID, roomID string, checkIn, checkOut time.Time, adults, children int) (*Quote, error)
 }
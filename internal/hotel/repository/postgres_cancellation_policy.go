@@ -0,0 +1,68 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+
+	"hotel-booking-system/internal/hotel/domain"
+)
+
+type PostgresCancellationPolicyRepository struct {
+	db *sql.DB
+}
+
+func NewPostgresCancellationPolicyRepository(db *sql.DB) *PostgresCancellationPolicyRepository {
+	return &PostgresCancellationPolicyRepository{db: db}
+}
+
+func (r *PostgresCancellationPolicyRepository) CreateCancellationPolicy(ctx context.Context, policy *domain.CancellationPolicy) error {
+	rules, err := json.Marshal(policy.Rules)
+	if err != nil {
+		return err
+	}
+	query := `INSERT INTO cancellation_policies (id, hotel_id, name, rules)
+			  VALUES ($1, $2, $3, $4)
+			  RETURNING created_at`
+	return r.db.QueryRowContext(ctx, query,
+		policy.ID, policy.HotelID, policy.Name, string(rules),
+	).Scan(&policy.CreatedAt)
+}
+
+const cancellationPolicyColumns = `id, hotel_id, name, rules, created_at`
+
+func scanCancellationPolicy(row rowScanner, policy *domain.CancellationPolicy) error {
+	var rules []byte
+	if err := row.Scan(&policy.ID, &policy.HotelID, &policy.Name, &rules, &policy.CreatedAt); err != nil {
+		return err
+	}
+	return json.Unmarshal(rules, &policy.Rules)
+}
+
+func (r *PostgresCancellationPolicyRepository) GetCancellationPolicyByID(ctx context.Context, id string) (*domain.CancellationPolicy, error) {
+	policy := &domain.CancellationPolicy{}
+	query := `SELECT ` + cancellationPolicyColumns + ` FROM cancellation_policies WHERE id = $1`
+	if err := scanCancellationPolicy(r.db.QueryRowContext(ctx, query, id), policy); err != nil {
+		return nil, err
+	}
+	return policy, nil
+}
+
+func (r *PostgresCancellationPolicyRepository) GetCancellationPoliciesByHotel(ctx context.Context, hotelID string) ([]domain.CancellationPolicy, error) {
+	query := `SELECT ` + cancellationPolicyColumns + ` FROM cancellation_policies WHERE hotel_id = $1 ORDER BY created_at`
+	rows, err := r.db.QueryContext(ctx, query, hotelID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var policies []domain.CancellationPolicy
+	for rows.Next() {
+		var policy domain.CancellationPolicy
+		if err := scanCancellationPolicy(rows, &policy); err != nil {
+			return nil, err
+		}
+		policies = append(policies, policy)
+	}
+	return policies, rows.Err()
+}
@@ -0,0 +1,60 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"hotel-booking-system/internal/hotel/domain"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCreateCancellationPolicy(t *testing.T) {
+	db, mock := setupMockDBForRoom(t)
+	defer db.Close()
+
+	repo := NewPostgresCancellationPolicyRepository(db)
+	policy := &domain.CancellationPolicy{
+		ID:      "policy-123",
+		HotelID: "hotel-123",
+		Name:    "Гибкий",
+		Rules: []domain.CancellationRule{
+			{HoursBeforeCheckIn: 48, RefundBasisPoints: 10000},
+			{HoursBeforeCheckIn: 0, RefundBasisPoints: 5000},
+		},
+	}
+	createdAt := time.Now()
+
+	mock.ExpectQuery(`INSERT INTO cancellation_policies`).
+		WithArgs(policy.ID, policy.HotelID, policy.Name,
+			`[{"hours_before_check_in":48,"refund_basis_points":10000},{"hours_before_check_in":0,"refund_basis_points":5000}]`).
+		WillReturnRows(sqlmock.NewRows([]string{"created_at"}).AddRow(createdAt))
+
+	err := repo.CreateCancellationPolicy(context.Background(), policy)
+	assert.NoError(t, err)
+	assert.Equal(t, createdAt, policy.CreatedAt)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetCancellationPoliciesByHotel(t *testing.T) {
+	db, mock := setupMockDBForRoom(t)
+	defer db.Close()
+
+	repo := NewPostgresCancellationPolicyRepository(db)
+	createdAt := time.Now()
+
+	mock.ExpectQuery(`SELECT .* FROM cancellation_policies WHERE hotel_id = \$1`).
+		WithArgs("hotel-123").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "hotel_id", "name", "rules", "created_at"}).
+			AddRow("policy-1", "hotel-123", "Гибкий", []byte(`[{"hours_before_check_in":48,"refund_basis_points":10000}]`), createdAt).
+			AddRow("policy-2", "hotel-123", "Невозвратный", []byte(`[]`), createdAt))
+
+	policies, err := repo.GetCancellationPoliciesByHotel(context.Background(), "hotel-123")
+	assert.NoError(t, err)
+	assert.Len(t, policies, 2)
+	assert.Equal(t, []domain.CancellationRule{{HoursBeforeCheckIn: 48, RefundBasisPoints: 10000}}, policies[0].Rules)
+	assert.Empty(t, policies[1].Rules)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
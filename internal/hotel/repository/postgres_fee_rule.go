@@ -0,0 +1,64 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+
+	"hotel-booking-system/internal/hotel/domain"
+	"hotel-booking-system/pkg/money"
+)
+
+type PostgresFeeRuleRepository struct {
+	db *sql.DB
+}
+
+func NewPostgresFeeRuleRepository(db *sql.DB) *PostgresFeeRuleRepository {
+	return &PostgresFeeRuleRepository{db: db}
+}
+
+func (r *PostgresFeeRuleRepository) CreateFeeRule(ctx context.Context, rule *domain.FeeRule) error {
+	query := `INSERT INTO fee_rules (id, hotel_id, name, kind, calculation, basis_points, amount, currency,
+			  per_night, per_guest)
+			  VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+			  RETURNING created_at`
+	var amount, currency interface{}
+	if rule.Amount != nil {
+		amount, currency = *rule.Amount, rule.Amount.Currency
+	}
+	return r.db.QueryRowContext(ctx, query,
+		rule.ID, rule.HotelID, rule.Name, rule.Kind, rule.Calculation, rule.BasisPoints, amount, currency,
+		rule.PerNight, rule.PerGuest,
+	).Scan(&rule.CreatedAt)
+}
+
+func (r *PostgresFeeRuleRepository) GetFeeRulesByHotel(ctx context.Context, hotelID string) ([]domain.FeeRule, error) {
+	query := `SELECT id, hotel_id, name, kind, calculation, basis_points, amount, currency,
+			  per_night, per_guest, created_at
+			  FROM fee_rules WHERE hotel_id = $1 ORDER BY created_at`
+	rows, err := r.db.QueryContext(ctx, query, hotelID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var rules []domain.FeeRule
+	for rows.Next() {
+		var rule domain.FeeRule
+		var amount, currency sql.NullString
+		if err := rows.Scan(
+			&rule.ID, &rule.HotelID, &rule.Name, &rule.Kind, &rule.Calculation, &rule.BasisPoints,
+			&amount, &currency, &rule.PerNight, &rule.PerGuest, &rule.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		if amount.Valid {
+			parsed, err := money.Parse(amount.String, currency.String)
+			if err != nil {
+				return nil, err
+			}
+			rule.Amount = &parsed
+		}
+		rules = append(rules, rule)
+	}
+	return rules, rows.Err()
+}
@@ -0,0 +1,66 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"hotel-booking-system/internal/hotel/domain"
+	"hotel-booking-system/pkg/money"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCreateFeeRule(t *testing.T) {
+	db, mock := setupMockDBForRoom(t)
+	defer db.Close()
+
+	repo := NewPostgresFeeRuleRepository(db)
+	amount := money.New(10000, "RUB")
+	rule := &domain.FeeRule{
+		ID:          "fee-123",
+		HotelID:     "hotel-123",
+		Name:        "Уборка",
+		Kind:        domain.FeeKindFee,
+		Calculation: domain.FeeCalculationFixed,
+		Amount:      &amount,
+	}
+	createdAt := time.Now()
+
+	mock.ExpectQuery(`INSERT INTO fee_rules`).
+		WithArgs(rule.ID, rule.HotelID, rule.Name, "fee", "fixed", int64(0), "100.00", "RUB", false, false).
+		WillReturnRows(sqlmock.NewRows([]string{"created_at"}).AddRow(createdAt))
+
+	err := repo.CreateFeeRule(context.Background(), rule)
+	assert.NoError(t, err)
+	assert.Equal(t, createdAt, rule.CreatedAt)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetFeeRulesByHotel(t *testing.T) {
+	db, mock := setupMockDBForRoom(t)
+	defer db.Close()
+
+	repo := NewPostgresFeeRuleRepository(db)
+	createdAt := time.Now()
+
+	mock.ExpectQuery(`SELECT .* FROM fee_rules WHERE hotel_id = \$1`).
+		WithArgs("hotel-123").
+		WillReturnRows(sqlmock.NewRows([]string{
+			"id", "hotel_id", "name", "kind", "calculation", "basis_points", "amount", "currency",
+			"per_night", "per_guest", "created_at",
+		}).
+			AddRow("fee-1", "hotel-123", "Туристический налог", "tax", "percentage", 200, nil, nil, true, false, createdAt).
+			AddRow("fee-2", "hotel-123", "Уборка", "fee", "fixed", 0, "100.00", "RUB", false, false, createdAt))
+
+	rules, err := repo.GetFeeRulesByHotel(context.Background(), "hotel-123")
+	assert.NoError(t, err)
+	assert.Len(t, rules, 2)
+	assert.Equal(t, domain.FeeKindTax, rules[0].Kind)
+	assert.Equal(t, int64(200), rules[0].BasisPoints)
+	assert.Nil(t, rules[0].Amount)
+	assert.True(t, rules[0].PerNight)
+	assert.Equal(t, money.New(10000, "RUB"), *rules[1].Amount)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -16,30 +16,34 @@ func NewPostgresHotelRepository(db *sql.DB) *PostgresHotelRepository {
 }
 
 func (r *PostgresHotelRepository) CreateHotel(ctx context.Context, hotel *domain.Hotel) error {
-	query := `INSERT INTO hotels (id, name, description, address, owner_id) 
-			  VALUES ($1, $2, $3, $4, $5) 
+	query := `INSERT INTO hotels (id, name, description, address, owner_id, currency) 
+			  VALUES ($1, $2, $3, $4, $5, $6) 
 			  RETURNING created_at, updated_at`
 	return r.db.QueryRowContext(ctx, query,
-		hotel.ID, hotel.Name, hotel.Description, hotel.Address, hotel.OwnerID,
+		hotel.ID, hotel.Name, hotel.Description, hotel.Address, hotel.OwnerID, hotel.Currency,
 	).Scan(&hotel.CreatedAt, &hotel.UpdatedAt)
 }
 
-func (r *PostgresHotelRepository) GetHotelByID(ctx context.Context, id string) (*domain.Hotel, error) {
-	hotel := &domain.Hotel{}
-	query := `SELECT id, name, description, address, owner_id, created_at, updated_at 
-			  FROM hotels WHERE id = $1`
-	err := r.db.QueryRowContext(ctx, query, id).Scan(
+const hotelColumns = `id, name, description, address, owner_id, currency, created_at, updated_at`
+
+func scanHotel(row rowScanner, hotel *domain.Hotel) error {
+	return row.Scan(
 		&hotel.ID, &hotel.Name, &hotel.Description, &hotel.Address,
-		&hotel.OwnerID, &hotel.CreatedAt, &hotel.UpdatedAt,
+		&hotel.OwnerID, &hotel.Currency, &hotel.CreatedAt, &hotel.UpdatedAt,
 	)
-	if err != nil {
+}
+
+func (r *PostgresHotelRepository) GetHotelByID(ctx context.Context, id string) (*domain.Hotel, error) {
+	hotel := &domain.Hotel{}
+	query := `SELECT ` + hotelColumns + ` FROM hotels WHERE id = $1`
+	if err := scanHotel(r.db.QueryRowContext(ctx, query, id), hotel); err != nil {
 		return nil, err
 	}
 	return hotel, nil
 }
 
 func (r *PostgresHotelRepository) GetHotels(ctx context.Context, limit, offset int) ([]domain.Hotel, error) {
-	query := `SELECT id, name, description, address, owner_id, created_at, updated_at 
+	query := `SELECT ` + hotelColumns + ` 
 			  FROM hotels ORDER BY created_at DESC LIMIT $1 OFFSET $2`
 	rows, err := r.db.QueryContext(ctx, query, limit, offset)
 	if err != nil {
@@ -50,10 +54,7 @@ func (r *PostgresHotelRepository) GetHotels(ctx context.Context, limit, offset i
 	var hotels []domain.Hotel
 	for rows.Next() {
 		var hotel domain.Hotel
-		if err := rows.Scan(
-			&hotel.ID, &hotel.Name, &hotel.Description, &hotel.Address,
-			&hotel.OwnerID, &hotel.CreatedAt, &hotel.UpdatedAt,
-		); err != nil {
+		if err := scanHotel(rows, &hotel); err != nil {
 			return nil, err
 		}
 		hotels = append(hotels, hotel)
@@ -62,7 +63,7 @@ func (r *PostgresHotelRepository) GetHotels(ctx context.Context, limit, offset i
 }
 
 func (r *PostgresHotelRepository) GetHotelsByOwner(ctx context.Context, ownerID string) ([]domain.Hotel, error) {
-	query := `SELECT id, name, description, address, owner_id, created_at, updated_at 
+	query := `SELECT ` + hotelColumns + ` 
 			  FROM hotels WHERE owner_id = $1 ORDER BY created_at DESC`
 	rows, err := r.db.QueryContext(ctx, query, ownerID)
 	if err != nil {
@@ -73,10 +74,7 @@ func (r *PostgresHotelRepository) GetHotelsByOwner(ctx context.Context, ownerID
 	var hotels []domain.Hotel
 	for rows.Next() {
 		var hotel domain.Hotel
-		if err := rows.Scan(
-			&hotel.ID, &hotel.Name, &hotel.Description, &hotel.Address,
-			&hotel.OwnerID, &hotel.CreatedAt, &hotel.UpdatedAt,
-		); err != nil {
+		if err := scanHotel(rows, &hotel); err != nil {
 			return nil, err
 		}
 		hotels = append(hotels, hotel)
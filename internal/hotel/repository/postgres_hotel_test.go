@@ -41,13 +41,14 @@ func TestCreateHotel_Success(t *testing.T) {
 		Description: "Luxury hotel",
 		Address:     "123 Main St",
 		OwnerID:     "owner-123",
+		Currency:    "RUB",
 	}
 
 	createdAt := time.Now()
 	updatedAt := time.Now()
 
 	mock.ExpectQuery(`INSERT INTO hotels`).
-		WithArgs(hotel.ID, hotel.Name, hotel.Description, hotel.Address, hotel.OwnerID).
+		WithArgs(hotel.ID, hotel.Name, hotel.Description, hotel.Address, hotel.OwnerID, hotel.Currency).
 		WillReturnRows(sqlmock.NewRows([]string{"created_at", "updated_at"}).
 			AddRow(createdAt, updatedAt))
 
@@ -69,10 +70,11 @@ func TestCreateHotel_DatabaseError(t *testing.T) {
 		Description: "Luxury hotel",
 		Address:     "123 Main St",
 		OwnerID:     "owner-123",
+		Currency:    "RUB",
 	}
 
 	mock.ExpectQuery(`INSERT INTO hotels`).
-		WithArgs(hotel.ID, hotel.Name, hotel.Description, hotel.Address, hotel.OwnerID).
+		WithArgs(hotel.ID, hotel.Name, hotel.Description, hotel.Address, hotel.OwnerID, hotel.Currency).
 		WillReturnError(errors.New("duplicate key"))
 
 	err := repo.CreateHotel(context.Background(), hotel)
@@ -93,9 +95,9 @@ func TestGetHotelByID_Success(t *testing.T) {
 	mock.ExpectQuery(`SELECT.*FROM hotels WHERE id`).
 		WithArgs(hotelID).
 		WillReturnRows(sqlmock.NewRows([]string{
-			"id", "name", "description", "address", "owner_id", "created_at", "updated_at",
+			"id", "name", "description", "address", "owner_id", "currency", "created_at", "updated_at",
 		}).AddRow(
-			hotelID, "Grand Hotel", "Luxury hotel", "123 Main St", "owner-123",
+			hotelID, "Grand Hotel", "Luxury hotel", "123 Main St", "owner-123", "EUR",
 			createdAt, updatedAt,
 		))
 
@@ -105,6 +107,7 @@ func TestGetHotelByID_Success(t *testing.T) {
 	assert.Equal(t, hotelID, hotel.ID)
 	assert.Equal(t, "Grand Hotel", hotel.Name)
 	assert.Equal(t, "owner-123", hotel.OwnerID)
+	assert.Equal(t, "EUR", hotel.Currency)
 	assert.NoError(t, mock.ExpectationsWereMet())
 }
 
@@ -155,10 +158,10 @@ func TestGetHotels_Success(t *testing.T) {
 	updatedAt := time.Now()
 
 	rows := sqlmock.NewRows([]string{
-		"id", "name", "description", "address", "owner_id", "created_at", "updated_at",
+		"id", "name", "description", "address", "owner_id", "currency", "created_at", "updated_at",
 	}).
-		AddRow("hotel-1", "Hotel 1", "Desc 1", "Addr 1", "owner-1", createdAt, updatedAt).
-		AddRow("hotel-2", "Hotel 2", "Desc 2", "Addr 2", "owner-2", createdAt, updatedAt)
+		AddRow("hotel-1", "Hotel 1", "Desc 1", "Addr 1", "owner-1", "RUB", createdAt, updatedAt).
+		AddRow("hotel-2", "Hotel 2", "Desc 2", "Addr 2", "owner-2", "RUB", createdAt, updatedAt)
 
 	mock.ExpectQuery(`SELECT.*FROM hotels ORDER BY created_at DESC LIMIT`).
 		WithArgs(limit, offset).
@@ -183,7 +186,7 @@ func TestGetHotels_EmptyResult(t *testing.T) {
 	mock.ExpectQuery(`SELECT.*FROM hotels ORDER BY created_at DESC LIMIT`).
 		WithArgs(limit, offset).
 		WillReturnRows(sqlmock.NewRows([]string{
-			"id", "name", "description", "address", "owner_id", "created_at", "updated_at",
+			"id", "name", "description", "address", "owner_id", "currency", "created_at", "updated_at",
 		}))
 
 	hotels, err := repo.GetHotels(context.Background(), limit, offset)
@@ -203,8 +206,8 @@ func TestGetHotels_WithPagination(t *testing.T) {
 	updatedAt := time.Now()
 
 	rows := sqlmock.NewRows([]string{
-		"id", "name", "description", "address", "owner_id", "created_at", "updated_at",
-	}).AddRow("hotel-11", "Hotel 11", "Desc 11", "Addr 11", "owner-11", createdAt, updatedAt)
+		"id", "name", "description", "address", "owner_id", "currency", "created_at", "updated_at",
+	}).AddRow("hotel-11", "Hotel 11", "Desc 11", "Addr 11", "owner-11", "RUB", createdAt, updatedAt)
 
 	mock.ExpectQuery(`SELECT.*FROM hotels ORDER BY created_at DESC LIMIT`).
 		WithArgs(limit, offset).
@@ -243,8 +246,8 @@ func TestGetHotels_ScanError(t *testing.T) {
 	offset := 0
 
 	rows := sqlmock.NewRows([]string{
-		"id", "name", "description", "address", "owner_id", "created_at", "updated_at",
-	}).AddRow(nil, nil, nil, nil, nil, nil, nil)
+		"id", "name", "description", "address", "owner_id", "currency", "created_at", "updated_at",
+	}).AddRow(nil, nil, nil, nil, nil, nil, nil, nil)
 
 	mock.ExpectQuery(`SELECT.*FROM hotels ORDER BY created_at DESC LIMIT`).
 		WithArgs(limit, offset).
@@ -266,10 +269,10 @@ func TestGetHotelsByOwner_Success(t *testing.T) {
 	updatedAt := time.Now()
 
 	rows := sqlmock.NewRows([]string{
-		"id", "name", "description", "address", "owner_id", "created_at", "updated_at",
+		"id", "name", "description", "address", "owner_id", "currency", "created_at", "updated_at",
 	}).
-		AddRow("hotel-1", "Hotel 1", "Desc 1", "Addr 1", ownerID, createdAt, updatedAt).
-		AddRow("hotel-2", "Hotel 2", "Desc 2", "Addr 2", ownerID, createdAt, updatedAt)
+		AddRow("hotel-1", "Hotel 1", "Desc 1", "Addr 1", ownerID, "RUB", createdAt, updatedAt).
+		AddRow("hotel-2", "Hotel 2", "Desc 2", "Addr 2", ownerID, "RUB", createdAt, updatedAt)
 
 	mock.ExpectQuery(`SELECT.*FROM hotels WHERE owner_id`).
 		WithArgs(ownerID).
@@ -293,7 +296,7 @@ func TestGetHotelsByOwner_EmptyResult(t *testing.T) {
 	mock.ExpectQuery(`SELECT.*FROM hotels WHERE owner_id`).
 		WithArgs(ownerID).
 		WillReturnRows(sqlmock.NewRows([]string{
-			"id", "name", "description", "address", "owner_id", "created_at", "updated_at",
+			"id", "name", "description", "address", "owner_id", "currency", "created_at", "updated_at",
 		}))
 
 	hotels, err := repo.GetHotelsByOwner(context.Background(), ownerID)
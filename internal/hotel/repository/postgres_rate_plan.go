@@ -0,0 +1,74 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+
+	"hotel-booking-system/internal/hotel/domain"
+	"hotel-booking-system/pkg/money"
+
+	"github.com/lib/pq"
+)
+
+type PostgresRatePlanRepository struct {
+	db *sql.DB
+}
+
+func NewPostgresRatePlanRepository(db *sql.DB) *PostgresRatePlanRepository {
+	return &PostgresRatePlanRepository{db: db}
+}
+
+func (r *PostgresRatePlanRepository) CreateRatePlan(ctx context.Context, plan *domain.RatePlan) error {
+	query := `INSERT INTO rate_plans (id, room_id, name, price_per_night, currency, start_date, end_date,
+			  days_of_week, min_nights, priority, cancellation_policy_id)
+			  VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
+			  RETURNING created_at`
+	days := pq.Int64Array{}
+	for _, day := range plan.DaysOfWeek {
+		days = append(days, int64(day))
+	}
+	return r.db.QueryRowContext(ctx, query,
+		plan.ID, plan.RoomID, plan.Name, plan.PricePerNight, plan.PricePerNight.Currency,
+		plan.StartDate, plan.EndDate, days, plan.MinNights, plan.Priority, nullableID(plan.CancellationPolicyID),
+	).Scan(&plan.CreatedAt)
+}
+
+// GetRatePlansByRoom returns the room's plans, highest priority first.
+func (r *PostgresRatePlanRepository) GetRatePlansByRoom(ctx context.Context, roomID string) ([]domain.RatePlan, error) {
+	query := `SELECT id, room_id, name, price_per_night, currency, start_date, end_date,
+			  days_of_week, min_nights, priority, COALESCE(cancellation_policy_id::text, ''), created_at
+			  FROM rate_plans WHERE room_id = $1 ORDER BY priority DESC, created_at DESC`
+	rows, err := r.db.QueryContext(ctx, query, roomID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var plans []domain.RatePlan
+	for rows.Next() {
+		var plan domain.RatePlan
+		var price, currency string
+		var startDate, endDate sql.NullTime
+		var days pq.Int64Array
+		if err := rows.Scan(
+			&plan.ID, &plan.RoomID, &plan.Name, &price, &currency, &startDate, &endDate,
+			&days, &plan.MinNights, &plan.Priority, &plan.CancellationPolicyID, &plan.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		if plan.PricePerNight, err = money.Parse(price, currency); err != nil {
+			return nil, err
+		}
+		if startDate.Valid {
+			plan.StartDate = &startDate.Time
+		}
+		if endDate.Valid {
+			plan.EndDate = &endDate.Time
+		}
+		for _, day := range days {
+			plan.DaysOfWeek = append(plan.DaysOfWeek, int(day))
+		}
+		plans = append(plans, plan)
+	}
+	return plans, rows.Err()
+}
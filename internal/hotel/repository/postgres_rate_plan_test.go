@@ -0,0 +1,74 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"hotel-booking-system/internal/hotel/domain"
+	"hotel-booking-system/pkg/money"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCreateRatePlan(t *testing.T) {
+	db, mock := setupMockDBForRoom(t)
+	defer db.Close()
+
+	repo := NewPostgresRatePlanRepository(db)
+	start := time.Date(2024, 12, 20, 0, 0, 0, 0, time.UTC)
+	plan := &domain.RatePlan{
+		ID:            "plan-123",
+		RoomID:        "room-123",
+		Name:          "Новый год",
+		PricePerNight: money.New(900000, "RUB"),
+		StartDate:     &start,
+		DaysOfWeek:    []int{5, 6},
+		Priority:      10,
+	}
+	createdAt := time.Now()
+
+	mock.ExpectQuery(`INSERT INTO rate_plans`).
+		WithArgs(plan.ID, plan.RoomID, plan.Name, "9000.00", "RUB", plan.StartDate, plan.EndDate,
+			pq.Int64Array{5, 6}, 0, 10, nil).
+		WillReturnRows(sqlmock.NewRows([]string{"created_at"}).AddRow(createdAt))
+
+	err := repo.CreateRatePlan(context.Background(), plan)
+	assert.NoError(t, err)
+	assert.Equal(t, createdAt, plan.CreatedAt)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetRatePlansByRoom(t *testing.T) {
+	db, mock := setupMockDBForRoom(t)
+	defer db.Close()
+
+	repo := NewPostgresRatePlanRepository(db)
+	start := time.Date(2024, 12, 20, 0, 0, 0, 0, time.UTC)
+	end := time.Date(2025, 1, 8, 0, 0, 0, 0, time.UTC)
+	createdAt := time.Now()
+
+	mock.ExpectQuery(`SELECT .* FROM rate_plans WHERE room_id = \$1 ORDER BY priority DESC`).
+		WithArgs("room-123").
+		WillReturnRows(sqlmock.NewRows([]string{
+			"id", "room_id", "name", "price_per_night", "currency", "start_date", "end_date",
+			"days_of_week", "min_nights", "priority", "cancellation_policy_id", "created_at",
+		}).
+			AddRow("plan-1", "room-123", "Новый год", "9000.00", "RUB", start, end, "{}", 0, 10, "policy-1", createdAt).
+			AddRow("plan-2", "room-123", "Выходные", "7000.00", "RUB", nil, nil, "{5,6}", 2, 0, "", createdAt))
+
+	plans, err := repo.GetRatePlansByRoom(context.Background(), "room-123")
+	assert.NoError(t, err)
+	assert.Len(t, plans, 2)
+	assert.Equal(t, money.New(900000, "RUB"), plans[0].PricePerNight)
+	assert.Equal(t, &start, plans[0].StartDate)
+	assert.Empty(t, plans[0].DaysOfWeek)
+	assert.Nil(t, plans[1].StartDate)
+	assert.Equal(t, []int{5, 6}, plans[1].DaysOfWeek)
+	assert.Equal(t, 2, plans[1].MinNights)
+	assert.Equal(t, "policy-1", plans[0].CancellationPolicyID)
+	assert.Empty(t, plans[1].CancellationPolicyID)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
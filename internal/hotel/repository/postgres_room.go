@@ -5,6 +5,7 @@ import (
 	"database/sql"
 
 	"hotel-booking-system/internal/hotel/domain"
+	"hotel-booking-system/pkg/money"
 )
 
 type PostgresRoomRepository struct {
@@ -16,35 +17,86 @@ func NewPostgresRoomRepository(db *sql.DB) *PostgresRoomRepository {
 }
 
 func (r *PostgresRoomRepository) CreateRoom(ctx context.Context, room *domain.Room) error {
-	query := `INSERT INTO rooms (id, hotel_id, room_number, room_type, price_per_night, capacity, description, is_available) 
-			  VALUES ($1, $2, $3, $4, $5, $6, $7, $8) 
+	query := `INSERT INTO rooms (id, hotel_id, room_number, room_type, price_per_night, currency, capacity,
+			  base_occupancy, extra_adult_price, extra_child_price, description, is_available, cancellation_policy_id) 
+			  VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13) 
 			  RETURNING created_at, updated_at`
 	return r.db.QueryRowContext(ctx, query,
 		room.ID, room.HotelID, room.RoomNumber, room.RoomType,
-		room.PricePerNight, room.Capacity, room.Description, room.IsAvailable,
+		room.PricePerNight, room.PricePerNight.Currency, room.Capacity,
+		room.BaseOccupancy, nullableMoney(room.ExtraAdultPrice), nullableMoney(room.ExtraChildPrice),
+		room.Description, room.IsAvailable, nullableID(room.CancellationPolicyID),
 	).Scan(&room.CreatedAt, &room.UpdatedAt)
 }
 
-func (r *PostgresRoomRepository) GetRoomByID(ctx context.Context, id string) (*domain.Room, error) {
-	room := &domain.Room{}
-	query := `SELECT id, hotel_id, room_number, room_type, price_per_night, capacity, 
-			  description, is_available, created_at, updated_at 
-			  FROM rooms WHERE id = $1`
-	err := r.db.QueryRowContext(ctx, query, id).Scan(
+const roomColumns = `id, hotel_id, room_number, room_type, price_per_night, currency, capacity, 
+			  base_occupancy, extra_adult_price, extra_child_price,
+			  description, is_available, COALESCE(cancellation_policy_id::text, ''), created_at, updated_at`
+
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+// nullableID stores an unset optional reference as NULL.
+func nullableID(id string) interface{} {
+	if id == "" {
+		return nil
+	}
+	return id
+}
+
+// nullableMoney stores an unset optional price as NULL; the room's currency
+// column covers it.
+func nullableMoney(m *money.Money) interface{} {
+	if m == nil {
+		return nil
+	}
+	return *m
+}
+
+func scanRoom(row rowScanner, room *domain.Room) error {
+	var price, currency string
+	var extraAdultPrice, extraChildPrice sql.NullString
+	if err := row.Scan(
 		&room.ID, &room.HotelID, &room.RoomNumber, &room.RoomType,
-		&room.PricePerNight, &room.Capacity, &room.Description,
-		&room.IsAvailable, &room.CreatedAt, &room.UpdatedAt,
-	)
+		&price, &currency, &room.Capacity, &room.BaseOccupancy, &extraAdultPrice, &extraChildPrice,
+		&room.Description, &room.IsAvailable, &room.CancellationPolicyID, &room.CreatedAt, &room.UpdatedAt,
+	); err != nil {
+		return err
+	}
+	var err error
+	if room.PricePerNight, err = money.Parse(price, currency); err != nil {
+		return err
+	}
+	if room.ExtraAdultPrice, err = parseOptionalPrice(extraAdultPrice, currency); err != nil {
+		return err
+	}
+	room.ExtraChildPrice, err = parseOptionalPrice(extraChildPrice, currency)
+	return err
+}
+
+func parseOptionalPrice(price sql.NullString, currency string) (*money.Money, error) {
+	if !price.Valid {
+		return nil, nil
+	}
+	parsed, err := money.Parse(price.String, currency)
 	if err != nil {
 		return nil, err
 	}
+	return &parsed, nil
+}
+
+func (r *PostgresRoomRepository) GetRoomByID(ctx context.Context, id string) (*domain.Room, error) {
+	room := &domain.Room{}
+	query := `SELECT ` + roomColumns + ` FROM rooms WHERE id = $1`
+	if err := scanRoom(r.db.QueryRowContext(ctx, query, id), room); err != nil {
+		return nil, err
+	}
 	return room, nil
 }
 
 func (r *PostgresRoomRepository) GetRoomsByHotel(ctx context.Context, hotelID string) ([]domain.Room, error) {
-	query := `SELECT id, hotel_id, room_number, room_type, price_per_night, capacity, 
-			  description, is_available, created_at, updated_at 
-			  FROM rooms WHERE hotel_id = $1 ORDER BY room_number`
+	query := `SELECT ` + roomColumns + ` FROM rooms WHERE hotel_id = $1 ORDER BY room_number`
 	rows, err := r.db.QueryContext(ctx, query, hotelID)
 	if err != nil {
 		return nil, err
@@ -54,11 +106,7 @@ func (r *PostgresRoomRepository) GetRoomsByHotel(ctx context.Context, hotelID st
 	var rooms []domain.Room
 	for rows.Next() {
 		var room domain.Room
-		if err := rows.Scan(
-			&room.ID, &room.HotelID, &room.RoomNumber, &room.RoomType,
-			&room.PricePerNight, &room.Capacity, &room.Description,
-			&room.IsAvailable, &room.CreatedAt, &room.UpdatedAt,
-		); err != nil {
+		if err := scanRoom(rows, &room); err != nil {
 			return nil, err
 		}
 		rooms = append(rooms, room)
@@ -67,12 +115,14 @@ func (r *PostgresRoomRepository) GetRoomsByHotel(ctx context.Context, hotelID st
 }
 
 func (r *PostgresRoomRepository) UpdateRoom(ctx context.Context, room *domain.Room) error {
-	query := `UPDATE rooms SET room_number = $2, room_type = $3, price_per_night = $4, 
-			  capacity = $5, description = $6, is_available = $7, updated_at = CURRENT_TIMESTAMP 
+	query := `UPDATE rooms SET room_number = $2, room_type = $3, price_per_night = $4, currency = $5, 
+			  capacity = $6, base_occupancy = $7, extra_adult_price = $8, extra_child_price = $9,
+			  description = $10, is_available = $11, cancellation_policy_id = $12, updated_at = CURRENT_TIMESTAMP 
 			  WHERE id = $1 RETURNING updated_at`
 	return r.db.QueryRowContext(ctx, query,
-		room.ID, room.RoomNumber, room.RoomType, room.PricePerNight,
-		room.Capacity, room.Description, room.IsAvailable,
+		room.ID, room.RoomNumber, room.RoomType, room.PricePerNight, room.PricePerNight.Currency,
+		room.Capacity, room.BaseOccupancy, nullableMoney(room.ExtraAdultPrice), nullableMoney(room.ExtraChildPrice),
+		room.Description, room.IsAvailable, nullableID(room.CancellationPolicyID),
 	).Scan(&room.UpdatedAt)
 }
 
@@ -82,9 +132,11 @@ func (r *PostgresRoomRepository) DeleteRoom(ctx context.Context, id string) erro
 	return err
 }
 
-func (r *PostgresRoomRepository) GetRoomPrice(ctx context.Context, hotelID, roomID string) (float64, error) {
-	var price float64
-	query := `SELECT price_per_night FROM rooms WHERE id = $1 AND hotel_id = $2`
-	err := r.db.QueryRowContext(ctx, query, roomID, hotelID).Scan(&price)
-	return price, err
+func (r *PostgresRoomRepository) GetRoomPrice(ctx context.Context, hotelID, roomID string) (money.Money, error) {
+	var price, currency string
+	query := `SELECT price_per_night, currency FROM rooms WHERE id = $1 AND hotel_id = $2`
+	if err := r.db.QueryRowContext(ctx, query, roomID, hotelID).Scan(&price, &currency); err != nil {
+		return money.Money{}, err
+	}
+	return money.Parse(price, currency)
 }
@@ -8,6 +8,7 @@ import (
 	"time"
 
 	"hotel-booking-system/internal/hotel/domain"
+	"hotel-booking-system/pkg/money"
 
 	"github.com/DATA-DOG/go-sqlmock"
 	"github.com/stretchr/testify/assert"
@@ -40,7 +41,7 @@ func TestCreateRoom_Success(t *testing.T) {
 		HotelID:       "hotel-123",
 		RoomNumber:    "101",
 		RoomType:      "Standard",
-		PricePerNight: 5000.0,
+		PricePerNight: money.New(500000, "RUB"),
 		Capacity:      2,
 		Description:   "Comfortable room",
 		IsAvailable:   true,
@@ -52,7 +53,7 @@ func TestCreateRoom_Success(t *testing.T) {
 	mock.ExpectQuery(`INSERT INTO rooms`).
 		WithArgs(
 			room.ID, room.HotelID, room.RoomNumber, room.RoomType,
-			room.PricePerNight, room.Capacity, room.Description, room.IsAvailable,
+			room.PricePerNight, room.PricePerNight.Currency, room.Capacity, room.BaseOccupancy, nil, nil, room.Description, room.IsAvailable, nil,
 		).
 		WillReturnRows(sqlmock.NewRows([]string{"created_at", "updated_at"}).
 			AddRow(createdAt, updatedAt))
@@ -74,7 +75,7 @@ func TestCreateRoom_DatabaseError(t *testing.T) {
 		HotelID:       "hotel-123",
 		RoomNumber:    "101",
 		RoomType:      "Standard",
-		PricePerNight: 5000.0,
+		PricePerNight: money.New(500000, "RUB"),
 		Capacity:      2,
 		Description:   "Comfortable room",
 		IsAvailable:   true,
@@ -83,7 +84,7 @@ func TestCreateRoom_DatabaseError(t *testing.T) {
 	mock.ExpectQuery(`INSERT INTO rooms`).
 		WithArgs(
 			room.ID, room.HotelID, room.RoomNumber, room.RoomType,
-			room.PricePerNight, room.Capacity, room.Description, room.IsAvailable,
+			room.PricePerNight, room.PricePerNight.Currency, room.Capacity, room.BaseOccupancy, nil, nil, room.Description, room.IsAvailable, nil,
 		).
 		WillReturnError(errors.New("duplicate key"))
 
@@ -105,11 +106,11 @@ func TestGetRoomByID_Success(t *testing.T) {
 	mock.ExpectQuery(`SELECT.*FROM rooms WHERE id`).
 		WithArgs(roomID).
 		WillReturnRows(sqlmock.NewRows([]string{
-			"id", "hotel_id", "room_number", "room_type", "price_per_night",
-			"capacity", "description", "is_available", "created_at", "updated_at",
+			"id", "hotel_id", "room_number", "room_type", "price_per_night", "currency",
+			"capacity", "base_occupancy", "extra_adult_price", "extra_child_price", "description", "is_available", "cancellation_policy_id", "created_at", "updated_at",
 		}).AddRow(
-			roomID, "hotel-123", "101", "Standard", 5000.0,
-			2, "Comfortable room", true, createdAt, updatedAt,
+			roomID, "hotel-123", "101", "Standard", 5000.0, "RUB",
+			3, 2, []byte("1500.00"), nil, "Comfortable room", true, "", createdAt, updatedAt,
 		))
 
 	room, err := repo.GetRoomByID(context.Background(), roomID)
@@ -117,7 +118,10 @@ func TestGetRoomByID_Success(t *testing.T) {
 	assert.NotNil(t, room)
 	assert.Equal(t, roomID, room.ID)
 	assert.Equal(t, "101", room.RoomNumber)
-	assert.Equal(t, 5000.0, room.PricePerNight)
+	assert.Equal(t, money.New(500000, "RUB"), room.PricePerNight)
+	assert.Equal(t, 2, room.BaseOccupancy)
+	assert.Equal(t, money.New(150000, "RUB"), *room.ExtraAdultPrice)
+	assert.Nil(t, room.ExtraChildPrice)
 	assert.True(t, room.IsAvailable)
 	assert.NoError(t, mock.ExpectationsWereMet())
 }
@@ -168,11 +172,11 @@ func TestGetRoomsByHotel_Success(t *testing.T) {
 	updatedAt := time.Now()
 
 	rows := sqlmock.NewRows([]string{
-		"id", "hotel_id", "room_number", "room_type", "price_per_night",
-		"capacity", "description", "is_available", "created_at", "updated_at",
+		"id", "hotel_id", "room_number", "room_type", "price_per_night", "currency",
+		"capacity", "base_occupancy", "extra_adult_price", "extra_child_price", "description", "is_available", "cancellation_policy_id", "created_at", "updated_at",
 	}).
-		AddRow("room-1", hotelID, "101", "Standard", 5000.0, 2, "Room 1", true, createdAt, updatedAt).
-		AddRow("room-2", hotelID, "102", "Deluxe", 8000.0, 3, "Room 2", true, createdAt, updatedAt)
+		AddRow("room-1", hotelID, "101", "Standard", 5000.0, "RUB", 2, 0, nil, nil, "Room 1", true, "", createdAt, updatedAt).
+		AddRow("room-2", hotelID, "102", "Deluxe", 8000.0, "RUB", 3, 0, nil, nil, "Room 2", true, "", createdAt, updatedAt)
 
 	mock.ExpectQuery(`SELECT.*FROM rooms WHERE hotel_id`).
 		WithArgs(hotelID).
@@ -198,8 +202,8 @@ func TestGetRoomsByHotel_EmptyResult(t *testing.T) {
 	mock.ExpectQuery(`SELECT.*FROM rooms WHERE hotel_id`).
 		WithArgs(hotelID).
 		WillReturnRows(sqlmock.NewRows([]string{
-			"id", "hotel_id", "room_number", "room_type", "price_per_night",
-			"capacity", "description", "is_available", "created_at", "updated_at",
+			"id", "hotel_id", "room_number", "room_type", "price_per_night", "currency",
+			"capacity", "base_occupancy", "extra_adult_price", "extra_child_price", "description", "is_available", "cancellation_policy_id", "created_at", "updated_at",
 		}))
 
 	rooms, err := repo.GetRoomsByHotel(context.Background(), hotelID)
@@ -233,9 +237,9 @@ func TestGetRoomsByHotel_ScanError(t *testing.T) {
 	hotelID := "hotel-123"
 
 	rows := sqlmock.NewRows([]string{
-		"id", "hotel_id", "room_number", "room_type", "price_per_night",
-		"capacity", "description", "is_available", "created_at", "updated_at",
-	}).AddRow(nil, nil, nil, nil, nil, nil, nil, nil, nil, nil)
+		"id", "hotel_id", "room_number", "room_type", "price_per_night", "currency",
+		"capacity", "base_occupancy", "extra_adult_price", "extra_child_price", "description", "is_available", "cancellation_policy_id", "created_at", "updated_at",
+	}).AddRow(nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil)
 
 	mock.ExpectQuery(`SELECT.*FROM rooms WHERE hotel_id`).
 		WithArgs(hotelID).
@@ -257,9 +261,9 @@ func TestGetRoomsByHotel_RowsError(t *testing.T) {
 	updatedAt := time.Now()
 
 	rows := sqlmock.NewRows([]string{
-		"id", "hotel_id", "room_number", "room_type", "price_per_night",
-		"capacity", "description", "is_available", "created_at", "updated_at",
-	}).AddRow("room-1", hotelID, "101", "Standard", 5000.0, 2, "Room 1", true, createdAt, updatedAt).
+		"id", "hotel_id", "room_number", "room_type", "price_per_night", "currency",
+		"capacity", "base_occupancy", "extra_adult_price", "extra_child_price", "description", "is_available", "cancellation_policy_id", "created_at", "updated_at",
+	}).AddRow("room-1", hotelID, "101", "Standard", 5000.0, "RUB", 2, 0, nil, nil, "Room 1", true, "", createdAt, updatedAt).
 		RowError(0, errors.New("row error"))
 
 	mock.ExpectQuery(`SELECT.*FROM rooms WHERE hotel_id`).
@@ -281,7 +285,7 @@ func TestUpdateRoom_Success(t *testing.T) {
 		ID:            "room-123",
 		RoomNumber:    "101",
 		RoomType:      "Deluxe",
-		PricePerNight: 8000.0,
+		PricePerNight: money.New(800000, "RUB"),
 		Capacity:      3,
 		Description:   "Updated room",
 		IsAvailable:   false,
@@ -290,8 +294,8 @@ func TestUpdateRoom_Success(t *testing.T) {
 
 	mock.ExpectQuery(`UPDATE rooms SET`).
 		WithArgs(
-			room.ID, room.RoomNumber, room.RoomType, room.PricePerNight,
-			room.Capacity, room.Description, room.IsAvailable,
+			room.ID, room.RoomNumber, room.RoomType, room.PricePerNight, room.PricePerNight.Currency,
+			room.Capacity, room.BaseOccupancy, nil, nil, room.Description, room.IsAvailable, nil,
 		).
 		WillReturnRows(sqlmock.NewRows([]string{"updated_at"}).AddRow(updatedAt))
 
@@ -310,7 +314,7 @@ func TestUpdateRoom_NotFound(t *testing.T) {
 		ID:            "non-existent",
 		RoomNumber:    "101",
 		RoomType:      "Standard",
-		PricePerNight: 5000.0,
+		PricePerNight: money.New(500000, "RUB"),
 		Capacity:      2,
 		Description:   "Room",
 		IsAvailable:   true,
@@ -318,8 +322,8 @@ func TestUpdateRoom_NotFound(t *testing.T) {
 
 	mock.ExpectQuery(`UPDATE rooms SET`).
 		WithArgs(
-			room.ID, room.RoomNumber, room.RoomType, room.PricePerNight,
-			room.Capacity, room.Description, room.IsAvailable,
+			room.ID, room.RoomNumber, room.RoomType, room.PricePerNight, room.PricePerNight.Currency,
+			room.Capacity, room.BaseOccupancy, nil, nil, room.Description, room.IsAvailable, nil,
 		).
 		WillReturnError(sql.ErrNoRows)
 
@@ -338,7 +342,7 @@ func TestUpdateRoom_DatabaseError(t *testing.T) {
 		ID:            "room-123",
 		RoomNumber:    "101",
 		RoomType:      "Standard",
-		PricePerNight: 5000.0,
+		PricePerNight: money.New(500000, "RUB"),
 		Capacity:      2,
 		Description:   "Room",
 		IsAvailable:   true,
@@ -346,8 +350,8 @@ func TestUpdateRoom_DatabaseError(t *testing.T) {
 
 	mock.ExpectQuery(`UPDATE rooms SET`).
 		WithArgs(
-			room.ID, room.RoomNumber, room.RoomType, room.PricePerNight,
-			room.Capacity, room.Description, room.IsAvailable,
+			room.ID, room.RoomNumber, room.RoomType, room.PricePerNight, room.PricePerNight.Currency,
+			room.Capacity, room.BaseOccupancy, nil, nil, room.Description, room.IsAvailable, nil,
 		).
 		WillReturnError(errors.New("update error"))
 
@@ -413,15 +417,14 @@ func TestGetRoomPrice_Success(t *testing.T) {
 	repo := NewPostgresRoomRepository(db)
 	hotelID := "hotel-123"
 	roomID := "room-123"
-	expectedPrice := 5000.0
 
-	mock.ExpectQuery(`SELECT price_per_night FROM rooms WHERE id`).
+	mock.ExpectQuery(`SELECT price_per_night, currency FROM rooms WHERE id`).
 		WithArgs(roomID, hotelID).
-		WillReturnRows(sqlmock.NewRows([]string{"price_per_night"}).AddRow(expectedPrice))
+		WillReturnRows(sqlmock.NewRows([]string{"price_per_night", "currency"}).AddRow([]byte("3333.33"), "RUB"))
 
 	price, err := repo.GetRoomPrice(context.Background(), hotelID, roomID)
 	assert.NoError(t, err)
-	assert.Equal(t, expectedPrice, price)
+	assert.Equal(t, money.New(333333, "RUB"), price)
 	assert.NoError(t, mock.ExpectationsWereMet())
 }
 
@@ -433,13 +436,13 @@ func TestGetRoomPrice_NotFound(t *testing.T) {
 	hotelID := "hotel-123"
 	roomID := "non-existent"
 
-	mock.ExpectQuery(`SELECT price_per_night FROM rooms WHERE id`).
+	mock.ExpectQuery(`SELECT price_per_night, currency FROM rooms WHERE id`).
 		WithArgs(roomID, hotelID).
 		WillReturnError(sql.ErrNoRows)
 
 	price, err := repo.GetRoomPrice(context.Background(), hotelID, roomID)
 	assert.Error(t, err)
-	assert.Equal(t, 0.0, price)
+	assert.Equal(t, money.Money{}, price)
 	assert.Equal(t, sql.ErrNoRows, err)
 	assert.NoError(t, mock.ExpectationsWereMet())
 }
@@ -452,13 +455,13 @@ func TestGetRoomPrice_DatabaseError(t *testing.T) {
 	hotelID := "hotel-123"
 	roomID := "room-123"
 
-	mock.ExpectQuery(`SELECT price_per_night FROM rooms WHERE id`).
+	mock.ExpectQuery(`SELECT price_per_night, currency FROM rooms WHERE id`).
 		WithArgs(roomID, hotelID).
 		WillReturnError(errors.New("query error"))
 
 	price, err := repo.GetRoomPrice(context.Background(), hotelID, roomID)
 	assert.Error(t, err)
-	assert.Equal(t, 0.0, price)
+	assert.Equal(t, money.Money{}, price)
 	assert.Contains(t, err.Error(), "query error")
 	assert.NoError(t, mock.ExpectationsWereMet())
 }
@@ -471,13 +474,13 @@ func TestGetRoomPrice_WrongHotel(t *testing.T) {
 	hotelID := "wrong-hotel"
 	roomID := "room-123"
 
-	mock.ExpectQuery(`SELECT price_per_night FROM rooms WHERE id`).
+	mock.ExpectQuery(`SELECT price_per_night, currency FROM rooms WHERE id`).
 		WithArgs(roomID, hotelID).
 		WillReturnError(sql.ErrNoRows)
 
 	price, err := repo.GetRoomPrice(context.Background(), hotelID, roomID)
 	assert.Error(t, err)
-	assert.Equal(t, 0.0, price)
+	assert.Equal(t, money.Money{}, price)
 	assert.Equal(t, sql.ErrNoRows, err)
 	assert.NoError(t, mock.ExpectationsWereMet())
 }
@@ -492,7 +495,7 @@ func TestCreateRoom_WithZeroPrice(t *testing.T) {
 		HotelID:       "hotel-123",
 		RoomNumber:    "101",
 		RoomType:      "Standard",
-		PricePerNight: 0.0,
+		PricePerNight: money.New(0, "RUB"),
 		Capacity:      2,
 		Description:   "Free room",
 		IsAvailable:   true,
@@ -504,7 +507,7 @@ func TestCreateRoom_WithZeroPrice(t *testing.T) {
 	mock.ExpectQuery(`INSERT INTO rooms`).
 		WithArgs(
 			room.ID, room.HotelID, room.RoomNumber, room.RoomType,
-			room.PricePerNight, room.Capacity, room.Description, room.IsAvailable,
+			room.PricePerNight, room.PricePerNight.Currency, room.Capacity, room.BaseOccupancy, nil, nil, room.Description, room.IsAvailable, nil,
 		).
 		WillReturnRows(sqlmock.NewRows([]string{"created_at", "updated_at"}).
 			AddRow(createdAt, updatedAt))
@@ -524,12 +527,12 @@ func TestGetRoomsByHotel_OrderedByRoomNumber(t *testing.T) {
 	updatedAt := time.Now()
 
 	rows := sqlmock.NewRows([]string{
-		"id", "hotel_id", "room_number", "room_type", "price_per_night",
-		"capacity", "description", "is_available", "created_at", "updated_at",
+		"id", "hotel_id", "room_number", "room_type", "price_per_night", "currency",
+		"capacity", "base_occupancy", "extra_adult_price", "extra_child_price", "description", "is_available", "cancellation_policy_id", "created_at", "updated_at",
 	}).
-		AddRow("room-3", hotelID, "103", "Standard", 5000.0, 2, "Room 3", true, createdAt, updatedAt).
-		AddRow("room-1", hotelID, "101", "Standard", 5000.0, 2, "Room 1", true, createdAt, updatedAt).
-		AddRow("room-2", hotelID, "102", "Standard", 5000.0, 2, "Room 2", true, createdAt, updatedAt)
+		AddRow("room-3", hotelID, "103", "Standard", 5000.0, "RUB", 2, 0, nil, nil, "Room 3", true, "", createdAt, updatedAt).
+		AddRow("room-1", hotelID, "101", "Standard", 5000.0, "RUB", 2, 0, nil, nil, "Room 1", true, "", createdAt, updatedAt).
+		AddRow("room-2", hotelID, "102", "Standard", 5000.0, "RUB", 2, 0, nil, nil, "Room 2", true, "", createdAt, updatedAt)
 
 	mock.ExpectQuery(`SELECT.*FROM rooms WHERE hotel_id.*ORDER BY room_number`).
 		WithArgs(hotelID).
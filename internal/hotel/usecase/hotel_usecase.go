@@ -2,22 +2,37 @@ package usecase
 
 import (
 	"context"
+	"database/sql"
 	"errors"
+	"time"
 
 	"hotel-booking-system/internal/hotel/domain"
+	"hotel-booking-system/pkg/money"
 
 	"github.com/google/uuid"
 )
 
+type BookingClient interface {
+	GetBookedRoomIDs(ctx context.Context, hotelID string, checkIn, checkOut time.Time) ([]string, error)
+}
+
 type HotelUseCase struct {
-	hotelRepo domain.HotelRepository
-	roomRepo  domain.RoomRepository
+	hotelRepo              domain.HotelRepository
+	roomRepo               domain.RoomRepository
+	ratePlanRepo           domain.RatePlanRepository
+	feeRuleRepo            domain.FeeRuleRepository
+	cancellationPolicyRepo domain.CancellationPolicyRepository
+	bookingClient          BookingClient
 }
 
-func NewHotelUseCase(hotelRepo domain.HotelRepository, roomRepo domain.RoomRepository) *HotelUseCase {
+func NewHotelUseCase(hotelRepo domain.HotelRepository, roomRepo domain.RoomRepository, ratePlanRepo domain.RatePlanRepository, feeRuleRepo domain.FeeRuleRepository, cancellationPolicyRepo domain.CancellationPolicyRepository, bookingClient BookingClient) *HotelUseCase {
 	return &HotelUseCase{
-		hotelRepo: hotelRepo,
-		roomRepo:  roomRepo,
+		hotelRepo:              hotelRepo,
+		roomRepo:               roomRepo,
+		ratePlanRepo:           ratePlanRepo,
+		feeRuleRepo:            feeRuleRepo,
+		cancellationPolicyRepo: cancellationPolicyRepo,
+		bookingClient:          bookingClient,
 	}
 }
 
@@ -25,6 +40,12 @@ func (uc *HotelUseCase) CreateHotel(ctx context.Context, hotel *domain.Hotel) er
 	if hotel.Name == "" || hotel.Address == "" || hotel.OwnerID == "" {
 		return errors.New("invalid hotel data")
 	}
+	if hotel.Currency == "" {
+		hotel.Currency = money.DefaultCurrency
+	}
+	if err := money.ValidateCurrency(hotel.Currency); err != nil {
+		return domain.ErrInvalidCurrency
+	}
 	hotel.ID = uuid.New().String()
 	return uc.hotelRepo.CreateHotel(ctx, hotel)
 }
@@ -52,6 +73,8 @@ func (uc *HotelUseCase) UpdateHotel(ctx context.Context, hotel *domain.Hotel) er
 	if existing.OwnerID != hotel.OwnerID {
 		return errors.New("unauthorized to update this hotel")
 	}
+	// Room prices are in the hotel's currency, so it cannot be changed.
+	hotel.Currency = existing.Currency
 	return uc.hotelRepo.UpdateHotel(ctx, hotel)
 }
 
@@ -67,6 +90,12 @@ func (uc *HotelUseCase) DeleteHotel(ctx context.Context, id, ownerID string) err
 }
 
 func (uc *HotelUseCase) CreateRoom(ctx context.Context, room *domain.Room) error {
+	if err := uc.validatePrice(ctx, room); err != nil {
+		return err
+	}
+	if err := uc.validatePolicy(ctx, room.HotelID, room.CancellationPolicyID); err != nil {
+		return err
+	}
 	room.ID = uuid.New().String()
 	return uc.roomRepo.CreateRoom(ctx, room)
 }
@@ -97,9 +126,288 @@ func (uc *HotelUseCase) GetHotelWithRooms(ctx context.Context, hotelID string) (
 }
 
 func (uc *HotelUseCase) UpdateRoom(ctx context.Context, room *domain.Room) error {
+	if err := uc.validatePrice(ctx, room); err != nil {
+		return err
+	}
+	if err := uc.validatePolicy(ctx, room.HotelID, room.CancellationPolicyID); err != nil {
+		return err
+	}
 	return uc.roomRepo.UpdateRoom(ctx, room)
 }
 
-func (uc *HotelUseCase) GetRoomPrice(ctx context.Context, hotelID, roomID string) (float64, error) {
+// validatePrice rejects negative prices and prices without a currency, which
+// could not be read back from the database, and prices in a currency other
+// than the hotel's. Extra guest prices are held to the same rules, and the
+// base occupancy they start from must fit into the room.
+func (uc *HotelUseCase) validatePrice(ctx context.Context, room *domain.Room) error {
+	if room.BaseOccupancy < 0 || room.BaseOccupancy > room.Capacity {
+		return domain.ErrInvalidOccupancy
+	}
+	prices := []money.Money{room.PricePerNight}
+	for _, extra := range []*money.Money{room.ExtraAdultPrice, room.ExtraChildPrice} {
+		if extra != nil {
+			prices = append(prices, *extra)
+		}
+	}
+	for _, price := range prices {
+		if price.Currency == "" || price.IsNegative() {
+			return domain.ErrInvalidPrice
+		}
+	}
+
+	hotel, err := uc.hotelRepo.GetHotelByID(ctx, room.HotelID)
+	if err != nil {
+		return err
+	}
+	for _, price := range prices {
+		if price.Currency != hotel.Currency {
+			return domain.ErrCurrencyMismatch
+		}
+	}
+	return nil
+}
+
+// validatePolicy rejects references to cancellation policies of other hotels.
+func (uc *HotelUseCase) validatePolicy(ctx context.Context, hotelID, policyID string) error {
+	if policyID == "" {
+		return nil
+	}
+	policy, err := uc.cancellationPolicyRepo.GetCancellationPolicyByID(ctx, policyID)
+	if errors.Is(err, sql.ErrNoRows) || (err == nil && policy.HotelID != hotelID) {
+		return domain.ErrCancellationPolicyNotFound
+	}
+	return err
+}
+
+func (uc *HotelUseCase) GetRoomPrice(ctx context.Context, hotelID, roomID string) (money.Money, error) {
 	return uc.roomRepo.GetRoomPrice(ctx, hotelID, roomID)
 }
+
+func (uc *HotelUseCase) GetAvailableRooms(ctx context.Context, hotelID string, checkIn, checkOut time.Time, guests int) ([]domain.Room, error) {
+	if !checkIn.Before(checkOut) {
+		return nil, domain.ErrInvalidDateRange
+	}
+	if guests < 1 {
+		return nil, domain.ErrInvalidGuests
+	}
+
+	if _, err := uc.hotelRepo.GetHotelByID(ctx, hotelID); err != nil {
+		return nil, err
+	}
+
+	rooms, err := uc.roomRepo.GetRoomsByHotel(ctx, hotelID)
+	if err != nil {
+		return nil, err
+	}
+
+	bookedRoomIDs, err := uc.bookingClient.GetBookedRoomIDs(ctx, hotelID, checkIn, checkOut)
+	if err != nil {
+		return nil, err
+	}
+
+	booked := make(map[string]bool, len(bookedRoomIDs))
+	for _, id := range bookedRoomIDs {
+		booked[id] = true
+	}
+
+	available := []domain.Room{}
+	for _, room := range rooms {
+		if room.IsAvailable && room.Capacity >= guests && !booked[room.ID] {
+			available = append(available, room)
+		}
+	}
+	return available, nil
+}
+
+func (uc *HotelUseCase) CreateRatePlan(ctx context.Context, hotelID string, plan *domain.RatePlan) error {
+	room, err := uc.getHotelRoom(ctx, hotelID, plan.RoomID)
+	if err != nil {
+		return err
+	}
+
+	if plan.Name == "" || plan.MinNights < 0 ||
+		(plan.StartDate != nil && plan.EndDate != nil && plan.EndDate.Before(*plan.StartDate)) {
+		return domain.ErrInvalidRatePlan
+	}
+	for _, day := range plan.DaysOfWeek {
+		if day < int(time.Sunday) || day > int(time.Saturday) {
+			return domain.ErrInvalidRatePlan
+		}
+	}
+	if plan.PricePerNight.Currency == "" || plan.PricePerNight.IsNegative() {
+		return domain.ErrInvalidPrice
+	}
+	if plan.PricePerNight.Currency != room.PricePerNight.Currency {
+		return domain.ErrCurrencyMismatch
+	}
+	if err := uc.validatePolicy(ctx, hotelID, plan.CancellationPolicyID); err != nil {
+		return err
+	}
+
+	plan.ID = uuid.New().String()
+	return uc.ratePlanRepo.CreateRatePlan(ctx, plan)
+}
+
+func (uc *HotelUseCase) GetRatePlans(ctx context.Context, hotelID, roomID string) ([]domain.RatePlan, error) {
+	if _, err := uc.getHotelRoom(ctx, hotelID, roomID); err != nil {
+		return nil, err
+	}
+	return uc.ratePlanRepo.GetRatePlansByRoom(ctx, roomID)
+}
+
+func (uc *HotelUseCase) CreateFeeRule(ctx context.Context, rule *domain.FeeRule) error {
+	hotel, err := uc.hotelRepo.GetHotelByID(ctx, rule.HotelID)
+	if err != nil {
+		return err
+	}
+
+	if rule.Name == "" || (rule.Kind != domain.FeeKindTax && rule.Kind != domain.FeeKindFee) {
+		return domain.ErrInvalidFeeRule
+	}
+	switch rule.Calculation {
+	case domain.FeeCalculationPercentage:
+		if rule.BasisPoints <= 0 || rule.Amount != nil {
+			return domain.ErrInvalidFeeRule
+		}
+	case domain.FeeCalculationFixed:
+		if rule.Amount == nil || !rule.Amount.IsPositive() || rule.BasisPoints != 0 {
+			return domain.ErrInvalidFeeRule
+		}
+		if rule.Amount.Currency != hotel.Currency {
+			return domain.ErrCurrencyMismatch
+		}
+	default:
+		return domain.ErrInvalidFeeRule
+	}
+
+	rule.ID = uuid.New().String()
+	return uc.feeRuleRepo.CreateFeeRule(ctx, rule)
+}
+
+func (uc *HotelUseCase) GetFeeRules(ctx context.Context, hotelID string) ([]domain.FeeRule, error) {
+	if _, err := uc.hotelRepo.GetHotelByID(ctx, hotelID); err != nil {
+		return nil, err
+	}
+	return uc.feeRuleRepo.GetFeeRulesByHotel(ctx, hotelID)
+}
+
+func (uc *HotelUseCase) CreateCancellationPolicy(ctx context.Context, policy *domain.CancellationPolicy) error {
+	if _, err := uc.hotelRepo.GetHotelByID(ctx, policy.HotelID); err != nil {
+		return err
+	}
+	if err := policy.Validate(); err != nil {
+		return err
+	}
+	policy.ID = uuid.New().String()
+	return uc.cancellationPolicyRepo.CreateCancellationPolicy(ctx, policy)
+}
+
+func (uc *HotelUseCase) GetCancellationPolicies(ctx context.Context, hotelID string) ([]domain.CancellationPolicy, error) {
+	if _, err := uc.hotelRepo.GetHotelByID(ctx, hotelID); err != nil {
+		return nil, err
+	}
+	return uc.cancellationPolicyRepo.GetCancellationPoliciesByHotel(ctx, hotelID)
+}
+
+// GetQuote prices every night of the stay by the highest-priority rate plan
+// that applies to it, falling back to the room's base price, and adds the
+// hotel's taxes and fees on top. The stay is cancelled under the policy of the
+// plan pricing its first night, or of the room when that plan has none.
+// Guests above the room's base occupancy add their surcharge to every night.
+func (uc *HotelUseCase) GetQuote(ctx context.Context, hotelID, roomID string, checkIn, checkOut time.Time, adults, children int) (*domain.Quote, error) {
+	if !checkIn.Before(checkOut) {
+		return nil, domain.ErrInvalidDateRange
+	}
+	room, err := uc.getHotelRoom(ctx, hotelID, roomID)
+	if err != nil {
+		return nil, err
+	}
+	if err := room.ValidateOccupancy(adults, children); err != nil {
+		return nil, err
+	}
+	extraGuestCharge, err := room.ExtraGuestCharge(adults, children)
+	if err != nil {
+		return nil, err
+	}
+	plans, err := uc.ratePlanRepo.GetRatePlansByRoom(ctx, roomID)
+	if err != nil {
+		return nil, err
+	}
+
+	rules, err := uc.feeRuleRepo.GetFeeRulesByHotel(ctx, hotelID)
+	if err != nil {
+		return nil, err
+	}
+
+	quote := &domain.Quote{
+		HotelID:  hotelID,
+		RoomID:   roomID,
+		RoomType: room.RoomType,
+		Capacity: room.Capacity,
+		CheckIn:  checkIn,
+		CheckOut: checkOut,
+		Adults:   adults,
+		Children: children,
+		Guests:   adults + children,
+		Fees:     []domain.Charge{},
+	}
+	policyID := room.CancellationPolicyID
+	nights := domain.StayNights(checkIn, checkOut)
+	for i, night := range nights {
+		rate := domain.NightlyRate{Date: night, Price: room.PricePerNight}
+		for _, plan := range plans {
+			if plan.AppliesTo(night, len(nights)) {
+				rate.Price = plan.PricePerNight
+				rate.RatePlanID = plan.ID
+				if i == 0 && plan.CancellationPolicyID != "" {
+					policyID = plan.CancellationPolicyID
+				}
+				break
+			}
+		}
+		rate.ExtraGuestCharge = extraGuestCharge
+		if rate.Price, err = rate.Price.Add(extraGuestCharge); err != nil {
+			return nil, err
+		}
+		if quote.Subtotal, err = quote.Subtotal.Add(rate.Price); err != nil {
+			return nil, err
+		}
+		quote.Nights = append(quote.Nights, rate)
+	}
+
+	quote.Total = quote.Subtotal
+	for _, rule := range rules {
+		amount, err := rule.Charge(quote.Nights, quote.Guests)
+		if err != nil {
+			return nil, err
+		}
+		quote.Fees = append(quote.Fees, domain.Charge{
+			FeeRuleID:   rule.ID,
+			Name:        rule.Name,
+			Kind:        rule.Kind,
+			BasisPoints: rule.EffectiveBasisPoints(quote.Guests),
+			Amount:      amount,
+		})
+		if quote.Total, err = quote.Total.Add(amount); err != nil {
+			return nil, err
+		}
+	}
+
+	if policyID != "" {
+		if quote.CancellationPolicy, err = uc.cancellationPolicyRepo.GetCancellationPolicyByID(ctx, policyID); err != nil {
+			return nil, err
+		}
+	}
+	return quote, nil
+}
+
+func (uc *HotelUseCase) getHotelRoom(ctx context.Context, hotelID, roomID string) (*domain.Room, error) {
+	room, err := uc.roomRepo.GetRoomByID(ctx, roomID)
+	if err != nil {
+		return nil, err
+	}
+	if room.HotelID != hotelID {
+		return nil, domain.ErrRoomNotFound
+	}
+	return room, nil
+}
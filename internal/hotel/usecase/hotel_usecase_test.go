@@ -2,10 +2,13 @@ package usecase
 
 import (
 	"context"
+	"database/sql"
 	"errors"
 	"testing"
+	"time"
 
 	"hotel-booking-system/internal/hotel/domain"
+	"hotel-booking-system/pkg/money"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
@@ -80,15 +83,86 @@ func (m *MockRoomRepository) DeleteRoom(ctx context.Context, id string) error {
 	return args.Error(0)
 }
 
-func (m *MockRoomRepository) GetRoomPrice(ctx context.Context, hotelID, roomID string) (float64, error) {
+func (m *MockRoomRepository) GetRoomPrice(ctx context.Context, hotelID, roomID string) (money.Money, error) {
 	args := m.Called(ctx, hotelID, roomID)
-	return args.Get(0).(float64), args.Error(1)
+	return args.Get(0).(money.Money), args.Error(1)
+}
+
+type MockRatePlanRepository struct {
+	mock.Mock
+}
+
+func (m *MockRatePlanRepository) CreateRatePlan(ctx context.Context, plan *domain.RatePlan) error {
+	args := m.Called(ctx, plan)
+	return args.Error(0)
+}
+
+func (m *MockRatePlanRepository) GetRatePlansByRoom(ctx context.Context, roomID string) ([]domain.RatePlan, error) {
+	args := m.Called(ctx, roomID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]domain.RatePlan), args.Error(1)
+}
+
+type MockFeeRuleRepository struct {
+	mock.Mock
+}
+
+func (m *MockFeeRuleRepository) CreateFeeRule(ctx context.Context, rule *domain.FeeRule) error {
+	args := m.Called(ctx, rule)
+	return args.Error(0)
+}
+
+func (m *MockFeeRuleRepository) GetFeeRulesByHotel(ctx context.Context, hotelID string) ([]domain.FeeRule, error) {
+	args := m.Called(ctx, hotelID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]domain.FeeRule), args.Error(1)
+}
+
+type MockCancellationPolicyRepository struct {
+	mock.Mock
+}
+
+func (m *MockCancellationPolicyRepository) CreateCancellationPolicy(ctx context.Context, policy *domain.CancellationPolicy) error {
+	args := m.Called(ctx, policy)
+	return args.Error(0)
+}
+
+func (m *MockCancellationPolicyRepository) GetCancellationPolicyByID(ctx context.Context, id string) (*domain.CancellationPolicy, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.CancellationPolicy), args.Error(1)
+}
+
+func (m *MockCancellationPolicyRepository) GetCancellationPoliciesByHotel(ctx context.Context, hotelID string) ([]domain.CancellationPolicy, error) {
+	args := m.Called(ctx, hotelID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]domain.CancellationPolicy), args.Error(1)
+}
+
+type MockBookingClient struct {
+	mock.Mock
+}
+
+func (m *MockBookingClient) GetBookedRoomIDs(ctx context.Context, hotelID string, checkIn, checkOut time.Time) ([]string, error) {
+	args := m.Called(ctx, hotelID, checkIn, checkOut)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]string), args.Error(1)
 }
 
 func TestCreateHotel_Success(t *testing.T) {
 	mockHotelRepo := new(MockHotelRepository)
 	mockRoomRepo := new(MockRoomRepository)
-	uc := NewHotelUseCase(mockHotelRepo, mockRoomRepo)
+	uc := NewHotelUseCase(mockHotelRepo, mockRoomRepo, nil, nil, nil, nil)
 
 	hotel := &domain.Hotel{
 		Name:    "Test Hotel",
@@ -101,13 +175,30 @@ func TestCreateHotel_Success(t *testing.T) {
 	err := uc.CreateHotel(context.Background(), hotel)
 	assert.NoError(t, err)
 	assert.NotEmpty(t, hotel.ID)
+	assert.Equal(t, "RUB", hotel.Currency)
 	mockHotelRepo.AssertExpectations(t)
 }
 
+func TestCreateHotel_InvalidCurrency(t *testing.T) {
+	mockHotelRepo := new(MockHotelRepository)
+	uc := NewHotelUseCase(mockHotelRepo, new(MockRoomRepository), nil, nil, nil, nil)
+
+	hotel := &domain.Hotel{
+		Name:     "Test Hotel",
+		Address:  "Test Address",
+		OwnerID:  "owner123",
+		Currency: "euro",
+	}
+
+	err := uc.CreateHotel(context.Background(), hotel)
+	assert.ErrorIs(t, err, domain.ErrInvalidCurrency)
+	mockHotelRepo.AssertNotCalled(t, "CreateHotel", mock.Anything, mock.Anything)
+}
+
 func TestCreateHotel_InvalidData(t *testing.T) {
 	mockHotelRepo := new(MockHotelRepository)
 	mockRoomRepo := new(MockRoomRepository)
-	uc := NewHotelUseCase(mockHotelRepo, mockRoomRepo)
+	uc := NewHotelUseCase(mockHotelRepo, mockRoomRepo, nil, nil, nil, nil)
 
 	hotel := &domain.Hotel{
 		Name: "",
@@ -120,7 +211,7 @@ func TestCreateHotel_InvalidData(t *testing.T) {
 func TestGetHotel_Success(t *testing.T) {
 	mockHotelRepo := new(MockHotelRepository)
 	mockRoomRepo := new(MockRoomRepository)
-	uc := NewHotelUseCase(mockHotelRepo, mockRoomRepo)
+	uc := NewHotelUseCase(mockHotelRepo, mockRoomRepo, nil, nil, nil, nil)
 
 	expectedHotel := &domain.Hotel{
 		ID:      "hotel123",
@@ -139,7 +230,7 @@ func TestGetHotel_Success(t *testing.T) {
 func TestGetHotels_Success(t *testing.T) {
 	mockHotelRepo := new(MockHotelRepository)
 	mockRoomRepo := new(MockRoomRepository)
-	uc := NewHotelUseCase(mockHotelRepo, mockRoomRepo)
+	uc := NewHotelUseCase(mockHotelRepo, mockRoomRepo, nil, nil, nil, nil)
 
 	expectedHotels := []domain.Hotel{
 		{ID: "hotel1", Name: "Hotel 1"},
@@ -157,17 +248,19 @@ func TestGetHotels_Success(t *testing.T) {
 func TestUpdateHotel_Success(t *testing.T) {
 	mockHotelRepo := new(MockHotelRepository)
 	mockRoomRepo := new(MockRoomRepository)
-	uc := NewHotelUseCase(mockHotelRepo, mockRoomRepo)
+	uc := NewHotelUseCase(mockHotelRepo, mockRoomRepo, nil, nil, nil, nil)
 
 	existingHotel := &domain.Hotel{
-		ID:      "hotel123",
-		OwnerID: "owner123",
+		ID:       "hotel123",
+		OwnerID:  "owner123",
+		Currency: "EUR",
 	}
 
 	updateHotel := &domain.Hotel{
-		ID:      "hotel123",
-		OwnerID: "owner123",
-		Name:    "Updated Hotel",
+		ID:       "hotel123",
+		OwnerID:  "owner123",
+		Name:     "Updated Hotel",
+		Currency: "USD",
 	}
 
 	mockHotelRepo.On("GetHotelByID", mock.Anything, "hotel123").Return(existingHotel, nil)
@@ -175,13 +268,14 @@ func TestUpdateHotel_Success(t *testing.T) {
 
 	err := uc.UpdateHotel(context.Background(), updateHotel)
 	assert.NoError(t, err)
+	assert.Equal(t, "EUR", updateHotel.Currency)
 	mockHotelRepo.AssertExpectations(t)
 }
 
 func TestUpdateHotel_Unauthorized(t *testing.T) {
 	mockHotelRepo := new(MockHotelRepository)
 	mockRoomRepo := new(MockRoomRepository)
-	uc := NewHotelUseCase(mockHotelRepo, mockRoomRepo)
+	uc := NewHotelUseCase(mockHotelRepo, mockRoomRepo, nil, nil, nil, nil)
 
 	existingHotel := &domain.Hotel{
 		ID:      "hotel123",
@@ -203,14 +297,15 @@ func TestUpdateHotel_Unauthorized(t *testing.T) {
 func TestCreateRoom_Success(t *testing.T) {
 	mockHotelRepo := new(MockHotelRepository)
 	mockRoomRepo := new(MockRoomRepository)
-	uc := NewHotelUseCase(mockHotelRepo, mockRoomRepo)
+	uc := NewHotelUseCase(mockHotelRepo, mockRoomRepo, nil, nil, nil, nil)
 
 	room := &domain.Room{
 		HotelID:       "hotel123",
 		RoomNumber:    "101",
-		PricePerNight: 5000,
+		PricePerNight: money.New(500000, "RUB"),
 	}
 
+	mockHotelRepo.On("GetHotelByID", mock.Anything, "hotel123").Return(&domain.Hotel{ID: "hotel123", Currency: "RUB"}, nil)
 	mockRoomRepo.On("CreateRoom", mock.Anything, mock.Anything).Return(nil)
 
 	err := uc.CreateRoom(context.Background(), room)
@@ -219,23 +314,80 @@ func TestCreateRoom_Success(t *testing.T) {
 	mockRoomRepo.AssertExpectations(t)
 }
 
+func TestCreateRoom_InvalidPrice(t *testing.T) {
+	mockHotelRepo := new(MockHotelRepository)
+	mockRoomRepo := new(MockRoomRepository)
+	uc := NewHotelUseCase(mockHotelRepo, mockRoomRepo, nil, nil, nil, nil)
+
+	for _, price := range []money.Money{{}, money.New(-100, "RUB")} {
+		err := uc.CreateRoom(context.Background(), &domain.Room{HotelID: "hotel123", RoomNumber: "101", PricePerNight: price})
+		assert.ErrorIs(t, err, domain.ErrInvalidPrice)
+	}
+	mockRoomRepo.AssertNotCalled(t, "CreateRoom", mock.Anything, mock.Anything)
+}
+
+func TestCreateRoom_PriceInOtherCurrency(t *testing.T) {
+	mockHotelRepo := new(MockHotelRepository)
+	mockRoomRepo := new(MockRoomRepository)
+	uc := NewHotelUseCase(mockHotelRepo, mockRoomRepo, nil, nil, nil, nil)
+
+	mockHotelRepo.On("GetHotelByID", mock.Anything, "hotel123").Return(&domain.Hotel{ID: "hotel123", Currency: "EUR"}, nil)
+
+	err := uc.CreateRoom(context.Background(), &domain.Room{HotelID: "hotel123", RoomNumber: "101", PricePerNight: money.New(500000, "RUB")})
+	assert.ErrorIs(t, err, domain.ErrCurrencyMismatch)
+	mockRoomRepo.AssertNotCalled(t, "CreateRoom", mock.Anything, mock.Anything)
+}
+
+func TestCreateRoom_InvalidOccupancyPricing(t *testing.T) {
+	mockHotelRepo := new(MockHotelRepository)
+	mockRoomRepo := new(MockRoomRepository)
+	uc := NewHotelUseCase(mockHotelRepo, mockRoomRepo, nil, nil, nil, nil)
+
+	mockHotelRepo.On("GetHotelByID", mock.Anything, "hotel123").Return(&domain.Hotel{ID: "hotel123", Currency: "RUB"}, nil)
+	negative := money.New(-100, "RUB")
+	usd := money.New(2000, "USD")
+
+	tests := []struct {
+		name string
+		room domain.Room
+		want error
+	}{
+		{name: "base occupancy above capacity", room: domain.Room{Capacity: 2, BaseOccupancy: 3}, want: domain.ErrInvalidOccupancy},
+		{name: "negative extra adult price", room: domain.Room{Capacity: 4, BaseOccupancy: 2, ExtraAdultPrice: &negative}, want: domain.ErrInvalidPrice},
+		{name: "extra child price in other currency", room: domain.Room{Capacity: 4, BaseOccupancy: 2, ExtraChildPrice: &usd}, want: domain.ErrCurrencyMismatch},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			room := tt.room
+			room.HotelID = "hotel123"
+			room.RoomNumber = "101"
+			room.PricePerNight = money.New(500000, "RUB")
+
+			err := uc.CreateRoom(context.Background(), &room)
+			assert.ErrorIs(t, err, tt.want)
+		})
+	}
+	mockRoomRepo.AssertNotCalled(t, "CreateRoom", mock.Anything, mock.Anything)
+}
+
 func TestGetRoomPrice_Success(t *testing.T) {
 	mockHotelRepo := new(MockHotelRepository)
 	mockRoomRepo := new(MockRoomRepository)
-	uc := NewHotelUseCase(mockHotelRepo, mockRoomRepo)
+	uc := NewHotelUseCase(mockHotelRepo, mockRoomRepo, nil, nil, nil, nil)
 
-	mockRoomRepo.On("GetRoomPrice", mock.Anything, "hotel123", "room123").Return(5000.0, nil)
+	mockRoomRepo.On("GetRoomPrice", mock.Anything, "hotel123", "room123").Return(money.New(500000, "RUB"), nil)
 
 	price, err := uc.GetRoomPrice(context.Background(), "hotel123", "room123")
 	assert.NoError(t, err)
-	assert.Equal(t, 5000.0, price)
+	assert.Equal(t, money.New(500000, "RUB"), price)
 	mockRoomRepo.AssertExpectations(t)
 }
 
 func TestGetHotelWithRooms_Success(t *testing.T) {
 	mockHotelRepo := new(MockHotelRepository)
 	mockRoomRepo := new(MockRoomRepository)
-	uc := NewHotelUseCase(mockHotelRepo, mockRoomRepo)
+	uc := NewHotelUseCase(mockHotelRepo, mockRoomRepo, nil, nil, nil, nil)
 
 	hotel := &domain.Hotel{
 		ID:   "hotel123",
@@ -261,7 +413,7 @@ func TestGetHotelWithRooms_Success(t *testing.T) {
 func TestGetHotelWithRooms_HotelNotFound(t *testing.T) {
 	mockHotelRepo := new(MockHotelRepository)
 	mockRoomRepo := new(MockRoomRepository)
-	uc := NewHotelUseCase(mockHotelRepo, mockRoomRepo)
+	uc := NewHotelUseCase(mockHotelRepo, mockRoomRepo, nil, nil, nil, nil)
 
 	mockHotelRepo.On("GetHotelByID", mock.Anything, "hotel123").Return(nil, errors.New("not found"))
 
@@ -274,7 +426,7 @@ func TestGetHotelWithRooms_HotelNotFound(t *testing.T) {
 func TestGetHotelsByOwner_Success(t *testing.T) {
 	mockHotelRepo := new(MockHotelRepository)
 	mockRoomRepo := new(MockRoomRepository)
-	uc := NewHotelUseCase(mockHotelRepo, mockRoomRepo)
+	uc := NewHotelUseCase(mockHotelRepo, mockRoomRepo, nil, nil, nil, nil)
 
 	expectedHotels := []domain.Hotel{
 		{ID: "hotel1", OwnerID: "owner123"},
@@ -292,7 +444,7 @@ func TestGetHotelsByOwner_Success(t *testing.T) {
 func TestDeleteHotel_Success(t *testing.T) {
 	mockHotelRepo := new(MockHotelRepository)
 	mockRoomRepo := new(MockRoomRepository)
-	uc := NewHotelUseCase(mockHotelRepo, mockRoomRepo)
+	uc := NewHotelUseCase(mockHotelRepo, mockRoomRepo, nil, nil, nil, nil)
 
 	hotel := &domain.Hotel{
 		ID:      "hotel123",
@@ -310,7 +462,7 @@ func TestDeleteHotel_Success(t *testing.T) {
 func TestGetRoom_Success(t *testing.T) {
 	mockHotelRepo := new(MockHotelRepository)
 	mockRoomRepo := new(MockRoomRepository)
-	uc := NewHotelUseCase(mockHotelRepo, mockRoomRepo)
+	uc := NewHotelUseCase(mockHotelRepo, mockRoomRepo, nil, nil, nil, nil)
 
 	expectedRoom := &domain.Room{
 		ID:       "room123",
@@ -329,7 +481,7 @@ func TestGetRoom_Success(t *testing.T) {
 func TestGetRoomsByHotel_Success(t *testing.T) {
 	mockHotelRepo := new(MockHotelRepository)
 	mockRoomRepo := new(MockRoomRepository)
-	uc := NewHotelUseCase(mockHotelRepo, mockRoomRepo)
+	uc := NewHotelUseCase(mockHotelRepo, mockRoomRepo, nil, nil, nil, nil)
 
 	expectedRooms := []domain.Room{
 		{ID: "room1", HotelID: "hotel123"},
@@ -347,18 +499,349 @@ func TestGetRoomsByHotel_Success(t *testing.T) {
 func TestUpdateRoom_Success(t *testing.T) {
 	mockHotelRepo := new(MockHotelRepository)
 	mockRoomRepo := new(MockRoomRepository)
-	uc := NewHotelUseCase(mockHotelRepo, mockRoomRepo)
+	uc := NewHotelUseCase(mockHotelRepo, mockRoomRepo, nil, nil, nil, nil)
 
 	room := &domain.Room{
 		ID:            "room123",
 		HotelID:       "hotel123",
 		RoomNumber:    "101",
-		PricePerNight: 6000,
+		PricePerNight: money.New(600000, "RUB"),
 	}
 
+	mockHotelRepo.On("GetHotelByID", mock.Anything, "hotel123").Return(&domain.Hotel{ID: "hotel123", Currency: "RUB"}, nil)
 	mockRoomRepo.On("UpdateRoom", mock.Anything, room).Return(nil)
 
 	err := uc.UpdateRoom(context.Background(), room)
 	assert.NoError(t, err)
 	mockRoomRepo.AssertExpectations(t)
 }
+
+func TestGetAvailableRooms(t *testing.T) {
+	checkIn := time.Date(2024, 12, 20, 0, 0, 0, 0, time.UTC)
+	checkOut := checkIn.AddDate(0, 0, 3)
+
+	rooms := []domain.Room{
+		{ID: "room1", HotelID: "hotel123", Capacity: 2, IsAvailable: true},
+		{ID: "room2", HotelID: "hotel123", Capacity: 4, IsAvailable: true},
+		{ID: "room3", HotelID: "hotel123", Capacity: 4, IsAvailable: false},
+		{ID: "room4", HotelID: "hotel123", Capacity: 4, IsAvailable: true},
+	}
+
+	t.Run("filters booked, disabled and small rooms", func(t *testing.T) {
+		mockHotelRepo := new(MockHotelRepository)
+		mockRoomRepo := new(MockRoomRepository)
+		mockBookingClient := new(MockBookingClient)
+		uc := NewHotelUseCase(mockHotelRepo, mockRoomRepo, nil, nil, nil, mockBookingClient)
+
+		mockHotelRepo.On("GetHotelByID", mock.Anything, "hotel123").Return(&domain.Hotel{ID: "hotel123"}, nil)
+		mockRoomRepo.On("GetRoomsByHotel", mock.Anything, "hotel123").Return(rooms, nil)
+		mockBookingClient.On("GetBookedRoomIDs", mock.Anything, "hotel123", checkIn, checkOut).Return([]string{"room4"}, nil)
+
+		available, err := uc.GetAvailableRooms(context.Background(), "hotel123", checkIn, checkOut, 3)
+		assert.NoError(t, err)
+		assert.Len(t, available, 1)
+		assert.Equal(t, "room2", available[0].ID)
+		mockHotelRepo.AssertExpectations(t)
+		mockRoomRepo.AssertExpectations(t)
+		mockBookingClient.AssertExpectations(t)
+	})
+
+	t.Run("invalid date range", func(t *testing.T) {
+		uc := NewHotelUseCase(new(MockHotelRepository), new(MockRoomRepository), nil, nil, nil, new(MockBookingClient))
+
+		_, err := uc.GetAvailableRooms(context.Background(), "hotel123", checkOut, checkIn, 1)
+		assert.ErrorIs(t, err, domain.ErrInvalidDateRange)
+	})
+
+	t.Run("invalid guests", func(t *testing.T) {
+		uc := NewHotelUseCase(new(MockHotelRepository), new(MockRoomRepository), nil, nil, nil, new(MockBookingClient))
+
+		_, err := uc.GetAvailableRooms(context.Background(), "hotel123", checkIn, checkOut, 0)
+		assert.ErrorIs(t, err, domain.ErrInvalidGuests)
+	})
+
+	t.Run("hotel not found", func(t *testing.T) {
+		mockHotelRepo := new(MockHotelRepository)
+		mockRoomRepo := new(MockRoomRepository)
+		uc := NewHotelUseCase(mockHotelRepo, mockRoomRepo, nil, nil, nil, new(MockBookingClient))
+
+		mockHotelRepo.On("GetHotelByID", mock.Anything, "hotel123").Return(nil, errors.New("not found"))
+
+		_, err := uc.GetAvailableRooms(context.Background(), "hotel123", checkIn, checkOut, 1)
+		assert.Error(t, err)
+		mockRoomRepo.AssertNotCalled(t, "GetRoomsByHotel")
+	})
+
+	t.Run("booking service error", func(t *testing.T) {
+		mockHotelRepo := new(MockHotelRepository)
+		mockRoomRepo := new(MockRoomRepository)
+		mockBookingClient := new(MockBookingClient)
+		uc := NewHotelUseCase(mockHotelRepo, mockRoomRepo, nil, nil, nil, mockBookingClient)
+
+		mockHotelRepo.On("GetHotelByID", mock.Anything, "hotel123").Return(&domain.Hotel{ID: "hotel123"}, nil)
+		mockRoomRepo.On("GetRoomsByHotel", mock.Anything, "hotel123").Return(rooms, nil)
+		mockBookingClient.On("GetBookedRoomIDs", mock.Anything, "hotel123", checkIn, checkOut).Return(nil, errors.New("unavailable"))
+
+		available, err := uc.GetAvailableRooms(context.Background(), "hotel123", checkIn, checkOut, 1)
+		assert.Error(t, err)
+		assert.Nil(t, available)
+	})
+}
+
+func TestGetQuote(t *testing.T) {
+	// Friday 2024-12-20 to Monday 2024-12-23: three nights.
+	checkIn := time.Date(2024, 12, 20, 14, 0, 0, 0, time.UTC)
+	checkOut := time.Date(2024, 12, 23, 12, 0, 0, 0, time.UTC)
+	room := &domain.Room{ID: "room123", HotelID: "hotel123", RoomType: "Standard", PricePerNight: money.New(500000, "RUB"), Capacity: 3}
+	seasonStart := time.Date(2024, 12, 21, 0, 0, 0, 0, time.UTC)
+
+	t.Run("prices each night by the best applicable plan", func(t *testing.T) {
+		mockRoomRepo := new(MockRoomRepository)
+		mockRatePlanRepo := new(MockRatePlanRepository)
+		mockFeeRuleRepo := new(MockFeeRuleRepository)
+		uc := NewHotelUseCase(new(MockHotelRepository), mockRoomRepo, mockRatePlanRepo, mockFeeRuleRepo, nil, nil)
+
+		mockRoomRepo.On("GetRoomByID", mock.Anything, "room123").Return(room, nil)
+		mockRatePlanRepo.On("GetRatePlansByRoom", mock.Anything, "room123").Return([]domain.RatePlan{
+			{ID: "long-stay", PricePerNight: money.New(300000, "RUB"), MinNights: 7, Priority: 10},
+			{ID: "season", PricePerNight: money.New(900000, "RUB"), StartDate: &seasonStart, Priority: 5},
+			{ID: "weekend", PricePerNight: money.New(700000, "RUB"), DaysOfWeek: []int{5, 6}},
+		}, nil)
+		mockFeeRuleRepo.On("GetFeeRulesByHotel", mock.Anything, "hotel123").Return([]domain.FeeRule{}, nil)
+
+		quote, err := uc.GetQuote(context.Background(), "hotel123", "room123", checkIn, checkOut, 1, 0)
+		assert.NoError(t, err)
+		assert.Len(t, quote.Nights, 3)
+		assert.Equal(t, "weekend", quote.Nights[0].RatePlanID)
+		assert.Equal(t, time.Date(2024, 12, 20, 0, 0, 0, 0, time.UTC), quote.Nights[0].Date)
+		assert.Equal(t, "season", quote.Nights[1].RatePlanID)
+		assert.Equal(t, "season", quote.Nights[2].RatePlanID)
+		assert.Equal(t, money.New(2500000, "RUB"), quote.Subtotal)
+		assert.Equal(t, money.New(2500000, "RUB"), quote.Total)
+		assert.Empty(t, quote.Fees)
+	})
+
+	t.Run("falls back to the base price", func(t *testing.T) {
+		mockRoomRepo := new(MockRoomRepository)
+		mockRatePlanRepo := new(MockRatePlanRepository)
+		mockFeeRuleRepo := new(MockFeeRuleRepository)
+		uc := NewHotelUseCase(new(MockHotelRepository), mockRoomRepo, mockRatePlanRepo, mockFeeRuleRepo, nil, nil)
+
+		mockRoomRepo.On("GetRoomByID", mock.Anything, "room123").Return(room, nil)
+		mockRatePlanRepo.On("GetRatePlansByRoom", mock.Anything, "room123").Return([]domain.RatePlan{}, nil)
+		mockFeeRuleRepo.On("GetFeeRulesByHotel", mock.Anything, "hotel123").Return([]domain.FeeRule{}, nil)
+
+		quote, err := uc.GetQuote(context.Background(), "hotel123", "room123", checkIn, checkOut, 1, 0)
+		assert.NoError(t, err)
+		assert.Empty(t, quote.Nights[0].RatePlanID)
+		assert.Equal(t, "Standard", quote.RoomType)
+		assert.Equal(t, money.New(1500000, "RUB"), quote.Total)
+	})
+
+	t.Run("adds taxes and fees", func(t *testing.T) {
+		mockRoomRepo := new(MockRoomRepository)
+		mockRatePlanRepo := new(MockRatePlanRepository)
+		mockFeeRuleRepo := new(MockFeeRuleRepository)
+		uc := NewHotelUseCase(new(MockHotelRepository), mockRoomRepo, mockRatePlanRepo, mockFeeRuleRepo, nil, nil)
+
+		cleaning := money.New(100000, "RUB")
+		mockRoomRepo.On("GetRoomByID", mock.Anything, "room123").Return(room, nil)
+		mockRatePlanRepo.On("GetRatePlansByRoom", mock.Anything, "room123").Return([]domain.RatePlan{}, nil)
+		mockFeeRuleRepo.On("GetFeeRulesByHotel", mock.Anything, "hotel123").Return([]domain.FeeRule{
+			{ID: "tax", Name: "Туристический налог", Kind: domain.FeeKindTax, Calculation: domain.FeeCalculationPercentage, BasisPoints: 200},
+			{ID: "cleaning", Name: "Уборка", Kind: domain.FeeKindFee, Calculation: domain.FeeCalculationFixed, Amount: &cleaning, PerGuest: true},
+		}, nil)
+
+		quote, err := uc.GetQuote(context.Background(), "hotel123", "room123", checkIn, checkOut, 2, 0)
+		assert.NoError(t, err)
+		assert.Equal(t, 2, quote.Guests)
+		assert.Equal(t, money.New(1500000, "RUB"), quote.Subtotal)
+		assert.Len(t, quote.Fees, 2)
+		assert.Equal(t, money.New(30000, "RUB"), quote.Fees[0].Amount)
+		assert.Equal(t, domain.FeeKindTax, quote.Fees[0].Kind)
+		assert.Equal(t, int64(200), quote.Fees[0].BasisPoints)
+		assert.Equal(t, money.New(200000, "RUB"), quote.Fees[1].Amount)
+		assert.Zero(t, quote.Fees[1].BasisPoints)
+		assert.Equal(t, money.New(1730000, "RUB"), quote.Total)
+	})
+
+	t.Run("uses the cancellation policy of the first night's plan", func(t *testing.T) {
+		mockRoomRepo := new(MockRoomRepository)
+		mockRatePlanRepo := new(MockRatePlanRepository)
+		mockFeeRuleRepo := new(MockFeeRuleRepository)
+		mockPolicyRepo := new(MockCancellationPolicyRepository)
+		uc := NewHotelUseCase(new(MockHotelRepository), mockRoomRepo, mockRatePlanRepo, mockFeeRuleRepo, mockPolicyRepo, nil)
+
+		flexible := *room
+		flexible.CancellationPolicyID = "flexible"
+		nonRefundable := &domain.CancellationPolicy{ID: "non-refundable", HotelID: "hotel123", Rules: []domain.CancellationRule{}}
+		mockRoomRepo.On("GetRoomByID", mock.Anything, "room123").Return(&flexible, nil)
+		mockRatePlanRepo.On("GetRatePlansByRoom", mock.Anything, "room123").Return([]domain.RatePlan{
+			{ID: "weekend", PricePerNight: money.New(400000, "RUB"), DaysOfWeek: []int{5}, CancellationPolicyID: "non-refundable"},
+		}, nil)
+		mockFeeRuleRepo.On("GetFeeRulesByHotel", mock.Anything, "hotel123").Return([]domain.FeeRule{}, nil)
+		mockPolicyRepo.On("GetCancellationPolicyByID", mock.Anything, "non-refundable").Return(nonRefundable, nil)
+
+		quote, err := uc.GetQuote(context.Background(), "hotel123", "room123", checkIn, checkOut, 1, 0)
+		assert.NoError(t, err)
+		assert.Equal(t, nonRefundable, quote.CancellationPolicy)
+		mockPolicyRepo.AssertExpectations(t)
+	})
+
+	t.Run("invalid date range", func(t *testing.T) {
+		uc := NewHotelUseCase(new(MockHotelRepository), new(MockRoomRepository), new(MockRatePlanRepository), new(MockFeeRuleRepository), nil, nil)
+
+		_, err := uc.GetQuote(context.Background(), "hotel123", "room123", checkOut, checkIn, 1, 0)
+		assert.ErrorIs(t, err, domain.ErrInvalidDateRange)
+	})
+
+	t.Run("charges guests above the base occupancy", func(t *testing.T) {
+		mockRoomRepo := new(MockRoomRepository)
+		mockRatePlanRepo := new(MockRatePlanRepository)
+		mockFeeRuleRepo := new(MockFeeRuleRepository)
+		uc := NewHotelUseCase(new(MockHotelRepository), mockRoomRepo, mockRatePlanRepo, mockFeeRuleRepo, nil, nil)
+
+		extraAdult := money.New(150000, "RUB")
+		extraChild := money.New(50000, "RUB")
+		family := *room
+		family.BaseOccupancy = 1
+		family.ExtraAdultPrice = &extraAdult
+		family.ExtraChildPrice = &extraChild
+		mockRoomRepo.On("GetRoomByID", mock.Anything, "room123").Return(&family, nil)
+		mockRatePlanRepo.On("GetRatePlansByRoom", mock.Anything, "room123").Return([]domain.RatePlan{
+			{ID: "weekend", PricePerNight: money.New(700000, "RUB"), DaysOfWeek: []int{5}},
+		}, nil)
+		mockFeeRuleRepo.On("GetFeeRulesByHotel", mock.Anything, "hotel123").Return([]domain.FeeRule{}, nil)
+
+		quote, err := uc.GetQuote(context.Background(), "hotel123", "room123", checkIn, checkOut, 2, 1)
+		assert.NoError(t, err)
+		assert.Equal(t, 3, quote.Guests)
+		assert.Equal(t, 3, quote.Capacity)
+		assert.Equal(t, money.New(200000, "RUB"), quote.Nights[0].ExtraGuestCharge)
+		assert.Equal(t, money.New(900000, "RUB"), quote.Nights[0].Price)
+		assert.Equal(t, money.New(700000, "RUB"), quote.Nights[1].Price)
+		assert.Equal(t, money.New(2300000, "RUB"), quote.Total)
+	})
+
+	t.Run("invalid guests", func(t *testing.T) {
+		mockRoomRepo := new(MockRoomRepository)
+		uc := NewHotelUseCase(new(MockHotelRepository), mockRoomRepo, new(MockRatePlanRepository), new(MockFeeRuleRepository), nil, nil)
+
+		mockRoomRepo.On("GetRoomByID", mock.Anything, "room123").Return(room, nil)
+
+		_, err := uc.GetQuote(context.Background(), "hotel123", "room123", checkIn, checkOut, 0, 2)
+		assert.ErrorIs(t, err, domain.ErrInvalidGuests)
+	})
+
+	t.Run("more guests than the room holds", func(t *testing.T) {
+		mockRoomRepo := new(MockRoomRepository)
+		uc := NewHotelUseCase(new(MockHotelRepository), mockRoomRepo, new(MockRatePlanRepository), new(MockFeeRuleRepository), nil, nil)
+
+		mockRoomRepo.On("GetRoomByID", mock.Anything, "room123").Return(room, nil)
+
+		_, err := uc.GetQuote(context.Background(), "hotel123", "room123", checkIn, checkOut, 2, 2)
+		assert.ErrorIs(t, err, domain.ErrCapacityExceeded)
+	})
+
+	t.Run("room in another hotel", func(t *testing.T) {
+		mockRoomRepo := new(MockRoomRepository)
+		uc := NewHotelUseCase(new(MockHotelRepository), mockRoomRepo, new(MockRatePlanRepository), new(MockFeeRuleRepository), nil, nil)
+
+		mockRoomRepo.On("GetRoomByID", mock.Anything, "room123").Return(room, nil)
+
+		_, err := uc.GetQuote(context.Background(), "hotel456", "room123", checkIn, checkOut, 1, 0)
+		assert.ErrorIs(t, err, domain.ErrRoomNotFound)
+	})
+}
+
+func TestCreateFeeRule(t *testing.T) {
+	hotel := &domain.Hotel{ID: "hotel123", Currency: "RUB"}
+	rub := money.New(10000, "RUB")
+	usd := money.New(1000, "USD")
+	zero := money.New(0, "RUB")
+
+	tests := []struct {
+		name    string
+		rule    domain.FeeRule
+		wantErr error
+	}{
+		{name: "percentage", rule: domain.FeeRule{Name: "НДС", Kind: domain.FeeKindTax, Calculation: domain.FeeCalculationPercentage, BasisPoints: 200}},
+		{name: "fixed", rule: domain.FeeRule{Name: "Уборка", Kind: domain.FeeKindFee, Calculation: domain.FeeCalculationFixed, Amount: &rub}},
+		{name: "missing name", rule: domain.FeeRule{Kind: domain.FeeKindTax, Calculation: domain.FeeCalculationPercentage, BasisPoints: 200}, wantErr: domain.ErrInvalidFeeRule},
+		{name: "unknown kind", rule: domain.FeeRule{Name: "Сбор", Kind: "other", Calculation: domain.FeeCalculationPercentage, BasisPoints: 200}, wantErr: domain.ErrInvalidFeeRule},
+		{name: "unknown calculation", rule: domain.FeeRule{Name: "Сбор", Kind: domain.FeeKindFee}, wantErr: domain.ErrInvalidFeeRule},
+		{name: "zero percentage", rule: domain.FeeRule{Name: "Сбор", Kind: domain.FeeKindFee, Calculation: domain.FeeCalculationPercentage}, wantErr: domain.ErrInvalidFeeRule},
+		{name: "zero amount", rule: domain.FeeRule{Name: "Сбор", Kind: domain.FeeKindFee, Calculation: domain.FeeCalculationFixed, Amount: &zero}, wantErr: domain.ErrInvalidFeeRule},
+		{name: "other currency", rule: domain.FeeRule{Name: "Сбор", Kind: domain.FeeKindFee, Calculation: domain.FeeCalculationFixed, Amount: &usd}, wantErr: domain.ErrCurrencyMismatch},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockHotelRepo := new(MockHotelRepository)
+			mockFeeRuleRepo := new(MockFeeRuleRepository)
+			uc := NewHotelUseCase(mockHotelRepo, new(MockRoomRepository), nil, mockFeeRuleRepo, nil, nil)
+
+			mockHotelRepo.On("GetHotelByID", mock.Anything, "hotel123").Return(hotel, nil)
+			mockFeeRuleRepo.On("CreateFeeRule", mock.Anything, mock.Anything).Return(nil)
+
+			rule := tt.rule
+			rule.HotelID = "hotel123"
+			err := uc.CreateFeeRule(context.Background(), &rule)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				mockFeeRuleRepo.AssertNotCalled(t, "CreateFeeRule", mock.Anything, mock.Anything)
+				return
+			}
+			assert.NoError(t, err)
+			assert.NotEmpty(t, rule.ID)
+		})
+	}
+}
+
+func TestCreateRatePlan(t *testing.T) {
+	room := &domain.Room{ID: "room123", HotelID: "hotel123", PricePerNight: money.New(500000, "RUB")}
+	start := time.Date(2024, 12, 31, 0, 0, 0, 0, time.UTC)
+	end := time.Date(2024, 12, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name    string
+		plan    domain.RatePlan
+		wantErr error
+	}{
+		{name: "valid", plan: domain.RatePlan{Name: "Выходные", PricePerNight: money.New(700000, "RUB"), DaysOfWeek: []int{0, 6}}},
+		{name: "missing name", plan: domain.RatePlan{PricePerNight: money.New(700000, "RUB")}, wantErr: domain.ErrInvalidRatePlan},
+		{name: "reversed dates", plan: domain.RatePlan{Name: "Сезон", PricePerNight: money.New(700000, "RUB"), StartDate: &start, EndDate: &end}, wantErr: domain.ErrInvalidRatePlan},
+		{name: "invalid weekday", plan: domain.RatePlan{Name: "Сезон", PricePerNight: money.New(700000, "RUB"), DaysOfWeek: []int{7}}, wantErr: domain.ErrInvalidRatePlan},
+		{name: "negative price", plan: domain.RatePlan{Name: "Сезон", PricePerNight: money.New(-1, "RUB")}, wantErr: domain.ErrInvalidPrice},
+		{name: "other currency", plan: domain.RatePlan{Name: "Сезон", PricePerNight: money.New(7000, "USD")}, wantErr: domain.ErrCurrencyMismatch},
+		{name: "cancellation policy", plan: domain.RatePlan{Name: "Невозвратный", PricePerNight: money.New(400000, "RUB"), CancellationPolicyID: "policy123"}},
+		{name: "cancellation policy of another hotel", plan: domain.RatePlan{Name: "Невозвратный", PricePerNight: money.New(400000, "RUB"), CancellationPolicyID: "policy456"}, wantErr: domain.ErrCancellationPolicyNotFound},
+		{name: "unknown cancellation policy", plan: domain.RatePlan{Name: "Невозвратный", PricePerNight: money.New(400000, "RUB"), CancellationPolicyID: "missing"}, wantErr: domain.ErrCancellationPolicyNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRoomRepo := new(MockRoomRepository)
+			mockRatePlanRepo := new(MockRatePlanRepository)
+			mockPolicyRepo := new(MockCancellationPolicyRepository)
+			uc := NewHotelUseCase(new(MockHotelRepository), mockRoomRepo, mockRatePlanRepo, nil, mockPolicyRepo, nil)
+
+			mockRoomRepo.On("GetRoomByID", mock.Anything, "room123").Return(room, nil)
+			mockRatePlanRepo.On("CreateRatePlan", mock.Anything, mock.Anything).Return(nil)
+			mockPolicyRepo.On("GetCancellationPolicyByID", mock.Anything, "policy123").Return(&domain.CancellationPolicy{ID: "policy123", HotelID: "hotel123"}, nil)
+			mockPolicyRepo.On("GetCancellationPolicyByID", mock.Anything, "policy456").Return(&domain.CancellationPolicy{ID: "policy456", HotelID: "hotel456"}, nil)
+			mockPolicyRepo.On("GetCancellationPolicyByID", mock.Anything, "missing").Return(nil, sql.ErrNoRows)
+
+			plan := tt.plan
+			plan.RoomID = "room123"
+			err := uc.CreateRatePlan(context.Background(), "hotel123", &plan)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				mockRatePlanRepo.AssertNotCalled(t, "CreateRatePlan", mock.Anything, mock.Anything)
+				return
+			}
+			assert.NoError(t, err)
+			assert.NotEmpty(t, plan.ID)
+		})
+	}
+}
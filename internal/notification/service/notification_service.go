@@ -3,9 +3,12 @@ package service
 import (
 	"context"
 	"fmt"
+	"strings"
+
 	"hotel-booking-system/internal/booking/domain"
 	"hotel-booking-system/pkg/httpclient"
 	"hotel-booking-system/pkg/logger"
+	"hotel-booking-system/pkg/money"
 )
 
 type DeliveryClient interface {
@@ -28,60 +31,241 @@ func NewNotificationService(deliveryClient DeliveryClient, hotelClient HotelClie
 	}
 }
 
+// ProcessBookingEvent notifies the guest and the hotelier of a booking being
+// created, cancelled or modified. Stay events are recorded by staff at the desk
+// and need no notification; unknown event types are logged and dropped.
 func (ns *NotificationService) ProcessBookingEvent(ctx context.Context, event domain.BookingEvent) error {
-	clientMessage := FormatBookingNotificationForClient(
-		event.BookingID,
-		event.HotelID,
-		event.TotalPrice,
-		event.CheckInDate,
-		event.CheckOutDate,
+	switch event.EventType {
+	case domain.EventBookingCreated:
+		ns.notifyGuestAndHotelier(ctx, event.UserID, event.HotelID,
+			bookingCreatedSubject(event.Status),
+			FormatBookingNotificationForClient(event.BookingID, event.HotelID, event.Status, event.PriceBreakdown, guestPrice(event.TotalPrice, event.DisplayPrice), event.CheckInDate, event.CheckOutDate),
+			"Новое бронирование в вашем отеле",
+			FormatBookingNotificationForHotelier(event.BookingID, event.UserID, event.HotelID, event.Status, event.PriceBreakdown, event.TotalPrice, event.CheckInDate, event.CheckOutDate),
+		)
+	case domain.EventBookingCancelled:
+		ns.notifyGuestAndHotelier(ctx, event.UserID, event.HotelID,
+			"Бронирование отменено",
+			FormatCancellationNotificationForClient(event.BookingID, event.HotelID, event.RefundAmount, event.CheckInDate, event.CheckOutDate),
+			"Отмена бронирования в вашем отеле",
+			FormatCancellationNotificationForHotelier(event.BookingID, event.UserID, event.HotelID, event.CheckInDate, event.CheckOutDate),
+		)
+	case domain.EventBookingModified:
+		ns.notifyGuestAndHotelier(ctx, event.UserID, event.HotelID,
+			"Бронирование изменено",
+			FormatModificationNotificationForClient(event.BookingID, event.HotelID, event.RoomID, event.PriceBreakdown, guestPrice(event.TotalPrice, event.DisplayPrice), event.AdditionalCharge, event.RefundAmount, event.CheckInDate, event.CheckOutDate),
+			"Изменение бронирования в вашем отеле",
+			FormatModificationNotificationForHotelier(event.BookingID, event.UserID, event.HotelID, event.RoomID, event.PriceBreakdown, event.TotalPrice, event.CheckInDate, event.CheckOutDate),
+		)
+	case domain.EventBookingCheckedIn, domain.EventBookingCheckedOut, domain.EventBookingNoShow:
+	default:
+		logger.GetLogger().WithFields(map[string]interface{}{
+			"booking_id": event.BookingID,
+			"event_type": event.EventType,
+		}).Warn("ignoring unknown booking event")
+	}
+
+	return nil
+}
+
+// ProcessReservationEvent sends one notification to the guest and one to the
+// hotelier for all the rooms of a reservation.
+func (ns *NotificationService) ProcessReservationEvent(ctx context.Context, event domain.ReservationEvent) error {
+	status := reservationStatus(event.Bookings)
+	ns.notifyGuestAndHotelier(ctx, event.UserID, event.HotelID,
+		bookingCreatedSubject(status),
+		FormatReservationNotificationForClient(event.ReservationID, event.HotelID, status, event.Bookings, guestPrice(event.TotalPrice, event.DisplayPrice)),
+		"Новое бронирование в вашем отеле",
+		FormatReservationNotificationForHotelier(event.ReservationID, event.UserID, event.HotelID, status, event.Bookings, event.TotalPrice),
 	)
+	return nil
+}
 
+// reservationStatus is the status the rooms of a reservation were created in;
+// they all share it.
+func reservationStatus(bookings []domain.BookingEvent) domain.BookingStatus {
+	if len(bookings) == 0 {
+		return ""
+	}
+	return bookings[0].Status
+}
+
+// bookingCreatedSubject tells the guest whether their new booking still
+// waits for payment. Events published before the status was recorded were
+// only sent for confirmed bookings.
+func bookingCreatedSubject(status domain.BookingStatus) string {
+	if status == domain.StatusAwaitingPayment {
+		return "Бронирование ожидает оплаты"
+	}
+	return "Бронирование подтверждено"
+}
+
+// bookingCreatedHeadlines opens the guest's and the hotelier's messages about
+// a new booking.
+func bookingCreatedHeadlines(status domain.BookingStatus) (client, hotelier string) {
+	if status == domain.StatusAwaitingPayment {
+		return "Ваше бронирование создано и ожидает оплаты.", "Новое бронирование в вашем отеле ожидает оплаты."
+	}
+	return "Ваше бронирование подтверждено!", "Новое бронирование в вашем отеле!"
+}
+
+// ProcessWaitlistEvent tells the guest that a room was freed for them. The
+// hotelier hears of it only when the guest books the room.
+func (ns *NotificationService) ProcessWaitlistEvent(ctx context.Context, event domain.WaitlistEvent) error {
 	if err := ns.deliveryClient.SendNotification(ctx, &httpclient.SendNotificationRequest{
 		Channel:   "email",
 		Recipient: event.UserID,
-		Subject:   "Бронирование подтверждено",
+		Subject:   "Освободился номер",
+		Message:   FormatWaitlistOfferNotificationForClient(event.HotelID, event.RoomType, event.RoomID, event.HoldID, event.CheckInDate, event.CheckOutDate, event.ExpiresAt),
+	}); err != nil {
+		logger.GetLogger().WithError(err).Error("failed to send notification to client")
+	}
+	return nil
+}
+
+// guestPrice is what the guest pays: the price in their display currency,
+// or the hotel's price for events published before display prices existed.
+func guestPrice(totalPrice, displayPrice money.Money) money.Money {
+	if displayPrice.Currency == "" {
+		return totalPrice
+	}
+	return displayPrice
+}
+
+func (ns *NotificationService) notifyGuestAndHotelier(ctx context.Context, userID, hotelID string, clientSubject, clientMessage, hotelierSubject, hotelierMessage string) {
+	if err := ns.deliveryClient.SendNotification(ctx, &httpclient.SendNotificationRequest{
+		Channel:   "email",
+		Recipient: userID,
+		Subject:   clientSubject,
 		Message:   clientMessage,
 	}); err != nil {
 		logger.GetLogger().WithError(err).Error("failed to send notification to client")
 	}
 
-	ownerID, err := ns.hotelClient.GetHotelOwnerID(ctx, event.HotelID)
+	ownerID, err := ns.hotelClient.GetHotelOwnerID(ctx, hotelID)
 	if err != nil {
 		logger.GetLogger().WithError(err).Error("failed to get hotel owner ID")
-	} else {
-		hotelierMessage := FormatBookingNotificationForHotelier(
-			event.BookingID,
-			event.UserID,
-			event.HotelID,
-			event.TotalPrice,
-			event.CheckInDate,
-			event.CheckOutDate,
-		)
+		return
+	}
 
-		if err := ns.deliveryClient.SendNotification(ctx, &httpclient.SendNotificationRequest{
-			Channel:   "email",
-			Recipient: ownerID,
-			Subject:   "Новое бронирование в вашем отеле",
-			Message:   hotelierMessage,
-		}); err != nil {
-			logger.GetLogger().WithError(err).Error("failed to send notification to hotelier")
+	if err := ns.deliveryClient.SendNotification(ctx, &httpclient.SendNotificationRequest{
+		Channel:   "email",
+		Recipient: ownerID,
+		Subject:   hotelierSubject,
+		Message:   hotelierMessage,
+	}); err != nil {
+		logger.GetLogger().WithError(err).Error("failed to send notification to hotelier")
+	}
+}
+
+func FormatBookingNotificationForClient(bookingID, hotelID string, status domain.BookingStatus, breakdown []domain.PriceItem, totalPrice money.Money, checkIn, checkOut interface{}) string {
+	headline, _ := bookingCreatedHeadlines(status)
+	return fmt.Sprintf(
+		"%s\n\nID бронирования: %s\nОтель: %s\n%sСумма: %s\nДата заезда: %v\nДата выезда: %v\n\nСпасибо за выбор нашего сервиса!",
+		headline, bookingID, hotelID, formatBreakdown(breakdown), totalPrice, checkIn, checkOut,
+	)
+}
+
+func FormatBookingNotificationForHotelier(bookingID, userID, hotelID string, status domain.BookingStatus, breakdown []domain.PriceItem, totalPrice money.Money, checkIn, checkOut interface{}) string {
+	_, headline := bookingCreatedHeadlines(status)
+	return fmt.Sprintf(
+		"%s\n\nID бронирования: %s\nПользователь: %s\nОтель: %s\n%sСумма: %s\nДата заезда: %v\nДата выезда: %v",
+		headline, bookingID, userID, hotelID, formatBreakdown(breakdown), totalPrice, checkIn, checkOut,
+	)
+}
+
+// formatBreakdown lists the price items one per line, in the hotel's currency.
+func formatBreakdown(breakdown []domain.PriceItem) string {
+	var b strings.Builder
+	for _, item := range breakdown {
+		name := item.Name
+		if item.Kind == domain.PriceItemAccommodation {
+			name = "Проживание"
 		}
+		fmt.Fprintf(&b, "%s: %s\n", name, item.Amount)
 	}
+	return b.String()
+}
 
-	return nil
+func FormatCancellationNotificationForClient(bookingID, hotelID string, refundAmount *money.Money, checkIn, checkOut interface{}) string {
+	refund := "Возврат средств не требуется."
+	switch {
+	case refundAmount != nil && refundAmount.IsPositive():
+		refund = fmt.Sprintf("Сумма к возврату: %s", refundAmount)
+	case refundAmount != nil:
+		refund = "По условиям отмены бронирования средства не возвращаются."
+	}
+	return fmt.Sprintf(
+		"Ваше бронирование отменено.\n\nID бронирования: %s\nОтель: %s\nДата заезда: %v\nДата выезда: %v\n\n%s",
+		bookingID, hotelID, checkIn, checkOut, refund,
+	)
+}
+
+func FormatCancellationNotificationForHotelier(bookingID, userID, hotelID string, checkIn, checkOut interface{}) string {
+	return fmt.Sprintf(
+		"Бронирование в вашем отеле отменено.\n\nID бронирования: %s\nПользователь: %s\nОтель: %s\nДата заезда: %v\nДата выезда: %v",
+		bookingID, userID, hotelID, checkIn, checkOut,
+	)
+}
+
+// FormatModificationNotificationForClient shows the new stay and its price
+// together with the extra charge or the refund for the price difference.
+func FormatModificationNotificationForClient(bookingID, hotelID, roomID string, breakdown []domain.PriceItem, totalPrice money.Money, additionalCharge, refundAmount *money.Money, checkIn, checkOut interface{}) string {
+	difference := "Стоимость бронирования не изменилась."
+	switch {
+	case additionalCharge != nil:
+		difference = fmt.Sprintf("Доплата: %s", additionalCharge)
+	case refundAmount != nil:
+		difference = fmt.Sprintf("Сумма к возврату: %s", refundAmount)
+	}
+	return fmt.Sprintf(
+		"Ваше бронирование изменено.\n\nID бронирования: %s\nОтель: %s\nНомер: %s\n%sСумма: %s\nДата заезда: %v\nДата выезда: %v\n\n%s",
+		bookingID, hotelID, roomID, formatBreakdown(breakdown), totalPrice, checkIn, checkOut, difference,
+	)
+}
+
+func FormatModificationNotificationForHotelier(bookingID, userID, hotelID, roomID string, breakdown []domain.PriceItem, totalPrice money.Money, checkIn, checkOut interface{}) string {
+	return fmt.Sprintf(
+		"Бронирование в вашем отеле изменено.\n\nID бронирования: %s\nПользователь: %s\nОтель: %s\nНомер: %s\n%sСумма: %s\nДата заезда: %v\nДата выезда: %v",
+		bookingID, userID, hotelID, roomID, formatBreakdown(breakdown), totalPrice, checkIn, checkOut,
+	)
+}
+
+func FormatReservationNotificationForClient(reservationID, hotelID string, status domain.BookingStatus, bookings []domain.BookingEvent, totalPrice money.Money) string {
+	headline, _ := bookingCreatedHeadlines(status)
+	return fmt.Sprintf(
+		"%s\n\nID бронирования: %s\nОтель: %s\n\n%sСумма: %s\n\nСпасибо за выбор нашего сервиса!",
+		headline, reservationID, hotelID, formatReservationRooms(bookings), totalPrice,
+	)
 }
 
-func FormatBookingNotificationForClient(bookingID, hotelID string, totalPrice float64, checkIn, checkOut interface{}) string {
+func FormatReservationNotificationForHotelier(reservationID, userID, hotelID string, status domain.BookingStatus, bookings []domain.BookingEvent, totalPrice money.Money) string {
+	_, headline := bookingCreatedHeadlines(status)
 	return fmt.Sprintf(
-		"Ваше бронирование подтверждено!\n\nID бронирования: %s\nОтель: %s\nСумма: %.2f руб.\nДата заезда: %v\nДата выезда: %v\n\nСпасибо за выбор нашего сервиса!",
-		bookingID, hotelID, totalPrice, checkIn, checkOut,
+		"%s\n\nID бронирования: %s\nПользователь: %s\nОтель: %s\n\n%sСумма: %s",
+		headline, reservationID, userID, hotelID, formatReservationRooms(bookings), totalPrice,
 	)
 }
 
-func FormatBookingNotificationForHotelier(bookingID, userID, hotelID string, totalPrice float64, checkIn, checkOut interface{}) string {
+// FormatWaitlistOfferNotificationForClient tells the guest until when the room
+// is held for them and which hold to book it with.
+func FormatWaitlistOfferNotificationForClient(hotelID, roomType, roomID, holdID string, checkIn, checkOut, expiresAt interface{}) string {
 	return fmt.Sprintf(
-		"Новое бронирование в вашем отеле!\n\nID бронирования: %s\nПользователь: %s\nОтель: %s\nСумма: %.2f руб.\nДата заезда: %v\nДата выезда: %v",
-		bookingID, userID, hotelID, totalPrice, checkIn, checkOut,
+		"Для вас освободился номер из листа ожидания!\n\nОтель: %s\nТип номера: %s\nНомер: %s\nДата заезда: %v\nДата выезда: %v\n\nНомер удерживается за вами до %v. Чтобы забронировать его, укажите при бронировании удержание %s.",
+		hotelID, roomType, roomID, checkIn, checkOut, expiresAt, holdID,
 	)
 }
+
+// formatReservationRooms lists each room of a reservation with its guest and
+// dates, one block per room.
+func formatReservationRooms(bookings []domain.BookingEvent) string {
+	var b strings.Builder
+	for _, booking := range bookings {
+		fmt.Fprintf(&b, "Номер: %s\n", booking.RoomID)
+		if booking.GuestName != "" {
+			fmt.Fprintf(&b, "Гость: %s\n", booking.GuestName)
+		}
+		fmt.Fprintf(&b, "Дата заезда: %v\nДата выезда: %v\n\n", booking.CheckInDate, booking.CheckOutDate)
+	}
+	return b.String()
+}
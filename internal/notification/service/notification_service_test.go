@@ -3,12 +3,14 @@ package service
 import (
 	"context"
 	"errors"
+	"strings"
 	"testing"
 	"time"
 
 	"hotel-booking-system/internal/booking/domain"
 	"hotel-booking-system/pkg/httpclient"
 	"hotel-booking-system/pkg/logger"
+	"hotel-booking-system/pkg/money"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
@@ -55,7 +57,7 @@ func TestNotificationService_ProcessBookingEvent(t *testing.T) {
 		RoomID:       "room-123",
 		CheckInDate:  time.Now(),
 		CheckOutDate: time.Now().Add(24 * time.Hour),
-		TotalPrice:   5000.0,
+		TotalPrice:   money.New(500000, "RUB"),
 		EventType:    "booking.created",
 		Timestamp:    time.Now(),
 	}
@@ -125,18 +127,210 @@ func TestNotificationService_ProcessBookingEvent(t *testing.T) {
 	})
 }
 
+func TestNotificationService_ProcessBookingEvent_Cancelled(t *testing.T) {
+	logger.Init("info")
+
+	refund := money.New(500000, "RUB")
+	event := domain.BookingEvent{
+		BookingID:    "booking-123",
+		UserID:       "user-123",
+		HotelID:      "hotel-123",
+		CheckInDate:  time.Now(),
+		CheckOutDate: time.Now().Add(24 * time.Hour),
+		TotalPrice:   money.New(500000, "RUB"),
+		RefundAmount: &refund,
+		EventType:    domain.EventBookingCancelled,
+		Timestamp:    time.Now(),
+	}
+
+	mockDeliveryClient := new(MockDeliveryClient)
+	mockDeliveryClient.On("SendNotification", mock.Anything, mock.MatchedBy(func(req *httpclient.SendNotificationRequest) bool {
+		return req.Recipient == "user-123" && req.Subject == "Бронирование отменено"
+	})).Return(nil).Once()
+	mockDeliveryClient.On("SendNotification", mock.Anything, mock.MatchedBy(func(req *httpclient.SendNotificationRequest) bool {
+		return req.Recipient == "owner-123" && req.Subject == "Отмена бронирования в вашем отеле"
+	})).Return(nil).Once()
+
+	mockHotelClient := new(MockHotelClient)
+	mockHotelClient.On("GetHotelOwnerID", mock.Anything, "hotel-123").Return("owner-123", nil)
+
+	service := NewNotificationService(mockDeliveryClient, mockHotelClient)
+
+	err := service.ProcessBookingEvent(context.Background(), event)
+
+	assert.NoError(t, err)
+	mockDeliveryClient.AssertExpectations(t)
+	mockHotelClient.AssertExpectations(t)
+}
+
+func TestNotificationService_ProcessBookingEvent_Modified(t *testing.T) {
+	logger.Init("info")
+
+	charge := money.New(500000, "RUB")
+	event := domain.BookingEvent{
+		BookingID:        "booking-123",
+		UserID:           "user-123",
+		HotelID:          "hotel-123",
+		RoomID:           "room-456",
+		CheckInDate:      time.Now(),
+		CheckOutDate:     time.Now().Add(72 * time.Hour),
+		TotalPrice:       money.New(1500000, "RUB"),
+		DisplayPrice:     money.New(1500000, "RUB"),
+		AdditionalCharge: &charge,
+		EventType:        domain.EventBookingModified,
+		Timestamp:        time.Now(),
+	}
+
+	mockDeliveryClient := new(MockDeliveryClient)
+	mockDeliveryClient.On("SendNotification", mock.Anything, mock.MatchedBy(func(req *httpclient.SendNotificationRequest) bool {
+		return req.Recipient == "user-123" && req.Subject == "Бронирование изменено" &&
+			strings.Contains(req.Message, "Доплата: 5000.00 RUB")
+	})).Return(nil).Once()
+	mockDeliveryClient.On("SendNotification", mock.Anything, mock.MatchedBy(func(req *httpclient.SendNotificationRequest) bool {
+		return req.Recipient == "owner-123" && req.Subject == "Изменение бронирования в вашем отеле" &&
+			strings.Contains(req.Message, "room-456")
+	})).Return(nil).Once()
+
+	mockHotelClient := new(MockHotelClient)
+	mockHotelClient.On("GetHotelOwnerID", mock.Anything, "hotel-123").Return("owner-123", nil)
+
+	service := NewNotificationService(mockDeliveryClient, mockHotelClient)
+
+	err := service.ProcessBookingEvent(context.Background(), event)
+
+	assert.NoError(t, err)
+	mockDeliveryClient.AssertExpectations(t)
+}
+
+func TestNotificationService_ProcessBookingEvent_GuestSeesDisplayPrice(t *testing.T) {
+	logger.Init("info")
+
+	event := domain.BookingEvent{
+		BookingID:    "booking-123",
+		UserID:       "user-123",
+		HotelID:      "hotel-123",
+		CheckInDate:  time.Now(),
+		CheckOutDate: time.Now().Add(24 * time.Hour),
+		TotalPrice:   money.New(500000, "RUB"),
+		DisplayPrice: money.New(5405, "USD"),
+		EventType:    domain.EventBookingCreated,
+		Timestamp:    time.Now(),
+	}
+
+	mockDeliveryClient := new(MockDeliveryClient)
+	mockDeliveryClient.On("SendNotification", mock.Anything, mock.MatchedBy(func(req *httpclient.SendNotificationRequest) bool {
+		return req.Recipient == "user-123" && strings.Contains(req.Message, "54.05 USD")
+	})).Return(nil).Once()
+	mockDeliveryClient.On("SendNotification", mock.Anything, mock.MatchedBy(func(req *httpclient.SendNotificationRequest) bool {
+		return req.Recipient == "owner-123" && strings.Contains(req.Message, "5000.00 RUB")
+	})).Return(nil).Once()
+
+	mockHotelClient := new(MockHotelClient)
+	mockHotelClient.On("GetHotelOwnerID", mock.Anything, "hotel-123").Return("owner-123", nil)
+
+	service := NewNotificationService(mockDeliveryClient, mockHotelClient)
+
+	err := service.ProcessBookingEvent(context.Background(), event)
+
+	assert.NoError(t, err)
+	mockDeliveryClient.AssertExpectations(t)
+}
+
+func TestNotificationService_ProcessBookingEvent_AwaitingPayment(t *testing.T) {
+	logger.Init("info")
+
+	event := domain.BookingEvent{
+		BookingID:    "booking-123",
+		UserID:       "user-123",
+		HotelID:      "hotel-123",
+		CheckInDate:  time.Now(),
+		CheckOutDate: time.Now().Add(24 * time.Hour),
+		TotalPrice:   money.New(500000, "RUB"),
+		Status:       domain.StatusAwaitingPayment,
+		EventType:    domain.EventBookingCreated,
+		Timestamp:    time.Now(),
+	}
+
+	mockDeliveryClient := new(MockDeliveryClient)
+	mockDeliveryClient.On("SendNotification", mock.Anything, mock.MatchedBy(func(req *httpclient.SendNotificationRequest) bool {
+		return req.Recipient == "user-123" && req.Subject == "Бронирование ожидает оплаты" &&
+			strings.Contains(req.Message, "ожидает оплаты") && !strings.Contains(req.Message, "подтверждено")
+	})).Return(nil).Once()
+	mockDeliveryClient.On("SendNotification", mock.Anything, mock.MatchedBy(func(req *httpclient.SendNotificationRequest) bool {
+		return req.Recipient == "owner-123" && strings.Contains(req.Message, "ожидает оплаты")
+	})).Return(nil).Once()
+
+	mockHotelClient := new(MockHotelClient)
+	mockHotelClient.On("GetHotelOwnerID", mock.Anything, "hotel-123").Return("owner-123", nil)
+
+	service := NewNotificationService(mockDeliveryClient, mockHotelClient)
+
+	err := service.ProcessBookingEvent(context.Background(), event)
+
+	assert.NoError(t, err)
+	mockDeliveryClient.AssertExpectations(t)
+}
+
+func TestNotificationService_ProcessBookingEvent_IgnoresOtherEvents(t *testing.T) {
+	logger.Init("info")
+
+	for _, eventType := range []string{domain.EventBookingCheckedIn, domain.EventBookingNoShow, "booking.unknown"} {
+		t.Run(eventType, func(t *testing.T) {
+			mockDeliveryClient := new(MockDeliveryClient)
+			mockHotelClient := new(MockHotelClient)
+			service := NewNotificationService(mockDeliveryClient, mockHotelClient)
+
+			err := service.ProcessBookingEvent(context.Background(), domain.BookingEvent{
+				BookingID: "booking-123",
+				UserID:    "user-123",
+				HotelID:   "hotel-123",
+				EventType: eventType,
+			})
+
+			assert.NoError(t, err)
+			mockDeliveryClient.AssertNotCalled(t, "SendNotification", mock.Anything, mock.Anything)
+			mockHotelClient.AssertNotCalled(t, "GetHotelOwnerID", mock.Anything, mock.Anything)
+		})
+	}
+}
+
+func TestFormatCancellationNotificationForClient(t *testing.T) {
+	checkIn := time.Date(2024, 12, 20, 14, 0, 0, 0, time.UTC)
+	checkOut := time.Date(2024, 12, 25, 12, 0, 0, 0, time.UTC)
+	refund := money.New(500000, "RUB")
+
+	message := FormatCancellationNotificationForClient("booking-123", "hotel-123", &refund, checkIn, checkOut)
+	assert.Contains(t, message, "booking-123")
+	assert.Contains(t, message, "5000.00 RUB")
+
+	message = FormatCancellationNotificationForClient("booking-123", "hotel-123", nil, checkIn, checkOut)
+	assert.Contains(t, message, "Возврат средств не требуется")
+
+	noRefund := money.New(0, "RUB")
+	message = FormatCancellationNotificationForClient("booking-123", "hotel-123", &noRefund, checkIn, checkOut)
+	assert.Contains(t, message, "средства не возвращаются")
+}
+
 func TestFormatBookingNotificationForClient(t *testing.T) {
 	message := FormatBookingNotificationForClient(
 		"booking-123",
 		"hotel-123",
-		5000.0,
+		domain.StatusConfirmed,
+		[]domain.PriceItem{
+			{Kind: domain.PriceItemAccommodation, Amount: money.New(490000, "RUB")},
+			{Kind: domain.PriceItemTax, Name: "Туристический налог", Amount: money.New(10000, "RUB")},
+		},
+		money.New(500000, "RUB"),
 		time.Date(2024, 12, 20, 14, 0, 0, 0, time.UTC),
 		time.Date(2024, 12, 25, 12, 0, 0, 0, time.UTC),
 	)
 
 	assert.Contains(t, message, "booking-123")
 	assert.Contains(t, message, "hotel-123")
-	assert.Contains(t, message, "5000.00")
+	assert.Contains(t, message, "Проживание: 4900.00 RUB")
+	assert.Contains(t, message, "Туристический налог: 100.00 RUB")
+	assert.Contains(t, message, "5000.00 RUB")
+	assert.Contains(t, message, "подтверждено")
 }
 
 func TestFormatBookingNotificationForHotelier(t *testing.T) {
@@ -144,7 +338,9 @@ func TestFormatBookingNotificationForHotelier(t *testing.T) {
 		"booking-123",
 		"user-123",
 		"hotel-123",
-		5000.0,
+		domain.StatusConfirmed,
+		nil,
+		money.New(500000, "RUB"),
 		time.Date(2024, 12, 20, 14, 0, 0, 0, time.UTC),
 		time.Date(2024, 12, 25, 12, 0, 0, 0, time.UTC),
 	)
@@ -152,5 +348,116 @@ func TestFormatBookingNotificationForHotelier(t *testing.T) {
 	assert.Contains(t, message, "booking-123")
 	assert.Contains(t, message, "user-123")
 	assert.Contains(t, message, "hotel-123")
-	assert.Contains(t, message, "5000.00")
+	assert.Contains(t, message, "5000.00 RUB")
+}
+
+func TestNotificationService_ProcessReservationEvent(t *testing.T) {
+	logger.Init("info")
+
+	checkIn := time.Now()
+	event := domain.ReservationEvent{
+		ReservationID: "reservation-123",
+		UserID:        "user-123",
+		HotelID:       "hotel-123",
+		Bookings: []domain.BookingEvent{
+			{BookingID: "booking-1", RoomID: "room-101", GuestName: "Иван Петров", CheckInDate: checkIn, CheckOutDate: checkIn.Add(48 * time.Hour)},
+			{BookingID: "booking-2", RoomID: "room-102", GuestName: "Мария Петрова", CheckInDate: checkIn, CheckOutDate: checkIn.Add(72 * time.Hour)},
+		},
+		TotalPrice:   money.New(2500000, "RUB"),
+		DisplayPrice: money.New(2500000, "RUB"),
+		EventType:    domain.EventReservationCreated,
+		Timestamp:    time.Now(),
+	}
+
+	mockDeliveryClient := new(MockDeliveryClient)
+	mockDeliveryClient.On("SendNotification", mock.Anything, mock.MatchedBy(func(req *httpclient.SendNotificationRequest) bool {
+		return req.Recipient == "user-123" && req.Subject == "Бронирование подтверждено" &&
+			strings.Contains(req.Message, "reservation-123") && strings.Contains(req.Message, "room-102") &&
+			strings.Contains(req.Message, "Мария Петрова") && strings.Contains(req.Message, "Сумма: 25000.00 RUB")
+	})).Return(nil).Once()
+	mockDeliveryClient.On("SendNotification", mock.Anything, mock.MatchedBy(func(req *httpclient.SendNotificationRequest) bool {
+		return req.Recipient == "owner-123" && req.Subject == "Новое бронирование в вашем отеле" &&
+			strings.Contains(req.Message, "room-101") && strings.Contains(req.Message, "Иван Петров")
+	})).Return(nil).Once()
+
+	mockHotelClient := new(MockHotelClient)
+	mockHotelClient.On("GetHotelOwnerID", mock.Anything, "hotel-123").Return("owner-123", nil)
+
+	service := NewNotificationService(mockDeliveryClient, mockHotelClient)
+
+	err := service.ProcessReservationEvent(context.Background(), event)
+
+	assert.NoError(t, err)
+	mockDeliveryClient.AssertExpectations(t)
+}
+
+func TestNotificationService_ProcessReservationEvent_AwaitingPayment(t *testing.T) {
+	logger.Init("info")
+
+	checkIn := time.Now()
+	event := domain.ReservationEvent{
+		ReservationID: "reservation-123",
+		UserID:        "user-123",
+		HotelID:       "hotel-123",
+		Bookings: []domain.BookingEvent{
+			{BookingID: "booking-1", RoomID: "room-101", CheckInDate: checkIn, CheckOutDate: checkIn.Add(48 * time.Hour), Status: domain.StatusAwaitingPayment},
+			{BookingID: "booking-2", RoomID: "room-102", CheckInDate: checkIn, CheckOutDate: checkIn.Add(48 * time.Hour), Status: domain.StatusAwaitingPayment},
+		},
+		TotalPrice: money.New(2500000, "RUB"),
+		EventType:  domain.EventReservationCreated,
+		Timestamp:  time.Now(),
+	}
+
+	mockDeliveryClient := new(MockDeliveryClient)
+	mockDeliveryClient.On("SendNotification", mock.Anything, mock.MatchedBy(func(req *httpclient.SendNotificationRequest) bool {
+		return req.Recipient == "user-123" && req.Subject == "Бронирование ожидает оплаты" &&
+			!strings.Contains(req.Message, "подтверждено")
+	})).Return(nil).Once()
+	mockDeliveryClient.On("SendNotification", mock.Anything, mock.MatchedBy(func(req *httpclient.SendNotificationRequest) bool {
+		return req.Recipient == "owner-123" && strings.Contains(req.Message, "ожидает оплаты")
+	})).Return(nil).Once()
+
+	mockHotelClient := new(MockHotelClient)
+	mockHotelClient.On("GetHotelOwnerID", mock.Anything, "hotel-123").Return("owner-123", nil)
+
+	service := NewNotificationService(mockDeliveryClient, mockHotelClient)
+
+	err := service.ProcessReservationEvent(context.Background(), event)
+
+	assert.NoError(t, err)
+	mockDeliveryClient.AssertExpectations(t)
+}
+
+func TestNotificationService_ProcessWaitlistEvent(t *testing.T) {
+	logger.Init("info")
+
+	event := domain.WaitlistEvent{
+		EntryID:      "entry-123",
+		UserID:       "user-123",
+		HotelID:      "hotel-123",
+		RoomType:     "double",
+		RoomID:       "room-123",
+		HoldID:       "hold-123",
+		CheckInDate:  time.Now(),
+		CheckOutDate: time.Now().Add(24 * time.Hour),
+		ExpiresAt:    time.Now().Add(2 * time.Hour),
+		EventType:    domain.EventWaitlistOffered,
+		Timestamp:    time.Now(),
+	}
+
+	mockDeliveryClient := new(MockDeliveryClient)
+	mockDeliveryClient.On("SendNotification", mock.Anything, mock.MatchedBy(func(req *httpclient.SendNotificationRequest) bool {
+		return req.Recipient == "user-123" && req.Subject == "Освободился номер" &&
+			strings.Contains(req.Message, "Тип номера: double") && strings.Contains(req.Message, "hold-123")
+	})).Return(nil).Once()
+
+	mockHotelClient := new(MockHotelClient)
+
+	service := NewNotificationService(mockDeliveryClient, mockHotelClient)
+
+	err := service.ProcessWaitlistEvent(context.Background(), event)
+
+	assert.NoError(t, err)
+	mockDeliveryClient.AssertExpectations(t)
+	mockHotelClient.AssertNotCalled(t, "GetHotelOwnerID", mock.Anything, mock.Anything)
 }
@@ -2,19 +2,38 @@ package http
 
 import (
 	"context"
+	"database/sql"
 	"encoding/json"
+	"errors"
 	"net/http"
+	"strconv"
 	"time"
 
 	"hotel-booking-system/internal/payment/domain"
 	"hotel-booking-system/pkg/logger"
 	"hotel-booking-system/pkg/metrics"
+	"hotel-booking-system/pkg/money"
+
+	"github.com/go-chi/chi/v5"
 )
 
 type PaymentService interface {
 	ProcessPayment(ctx context.Context, req *domain.PaymentRequest) (*domain.PaymentResponse, error)
+	ProcessRefund(ctx context.Context, req *domain.RefundRequest) (*domain.RefundResponse, error)
+	RefundPayment(ctx context.Context, paymentID string, req *domain.RefundRequest) (*domain.RefundResponse, error)
+	GetPayment(ctx context.Context, id string) (*domain.Payment, error)
+	GetPaymentsByBooking(ctx context.Context, bookingID string) ([]domain.Payment, error)
+	GetDeadLetters(ctx context.Context, limit int) ([]domain.DeadLetter, error)
+	RedeliverWebhook(ctx context.Context, deadLetterID int64) (*domain.WebhookDelivery, error)
+	CapturePayment(ctx context.Context, id string) (*domain.Payment, error)
+	AuthenticatePayment(ctx context.Context, id string) (*domain.Payment, error)
+	VoidPayment(ctx context.Context, id string) (*domain.Payment, error)
+	CaptureBookingPayment(ctx context.Context, bookingID string) (*domain.Payment, error)
+	VoidBookingPayment(ctx context.Context, bookingID string) (*domain.Payment, error)
 }
 
+const defaultDeadLetterLimit = 100
+
 type PaymentHandler struct {
 	paymentService PaymentService
 }
@@ -39,15 +58,12 @@ func (h *PaymentHandler) CreatePayment(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	if req.Currency == "" {
-		req.Currency = "RUB"
-	}
-
 	response, err := h.paymentService.ProcessPayment(r.Context(), &req)
 	if err != nil {
 		logger.GetLogger().WithError(err).Error("failed to process payment")
-		metrics.HTTPRequestsTotal.WithLabelValues(r.Method, "/api/payments", "500").Inc()
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		status := errorStatus(err)
+		metrics.HTTPRequestsTotal.WithLabelValues(r.Method, "/api/payments", strconv.Itoa(status)).Inc()
+		http.Error(w, err.Error(), status)
 		return
 	}
 
@@ -56,3 +72,293 @@ func (h *PaymentHandler) CreatePayment(w http.ResponseWriter, r *http.Request) {
 	w.WriteHeader(http.StatusAccepted)
 	json.NewEncoder(w).Encode(response)
 }
+
+func (h *PaymentHandler) CreateRefund(w http.ResponseWriter, r *http.Request) {
+	start := time.Now()
+	defer func() {
+		metrics.HTTPRequestDuration.WithLabelValues(r.Method, "/api/payments/refunds").Observe(time.Since(start).Seconds())
+	}()
+
+	var req domain.RefundRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		logger.GetLogger().WithError(err).Error("failed to decode request")
+		metrics.HTTPRequestsTotal.WithLabelValues(r.Method, "/api/payments/refunds", "400").Inc()
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	response, err := h.paymentService.ProcessRefund(r.Context(), &req)
+	if err != nil {
+		logger.GetLogger().WithError(err).Error("failed to process refund")
+		status := errorStatus(err)
+		metrics.HTTPRequestsTotal.WithLabelValues(r.Method, "/api/payments/refunds", strconv.Itoa(status)).Inc()
+		http.Error(w, err.Error(), status)
+		return
+	}
+
+	metrics.HTTPRequestsTotal.WithLabelValues(r.Method, "/api/payments/refunds", "202").Inc()
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusAccepted)
+	json.NewEncoder(w).Encode(response)
+}
+
+func (h *PaymentHandler) RefundPayment(w http.ResponseWriter, r *http.Request) {
+	start := time.Now()
+	defer func() {
+		metrics.HTTPRequestDuration.WithLabelValues(r.Method, "/api/payments/{id}/refunds").Observe(time.Since(start).Seconds())
+	}()
+
+	var req domain.RefundRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		logger.GetLogger().WithError(err).Error("failed to decode request")
+		metrics.HTTPRequestsTotal.WithLabelValues(r.Method, "/api/payments/{id}/refunds", "400").Inc()
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	id := chi.URLParam(r, "id")
+	response, err := h.paymentService.RefundPayment(r.Context(), id, &req)
+	if err != nil {
+		logger.GetLogger().WithError(err).Error("failed to refund payment")
+		status := errorStatus(err)
+		metrics.HTTPRequestsTotal.WithLabelValues(r.Method, "/api/payments/{id}/refunds", strconv.Itoa(status)).Inc()
+		http.Error(w, err.Error(), status)
+		return
+	}
+
+	metrics.HTTPRequestsTotal.WithLabelValues(r.Method, "/api/payments/{id}/refunds", "202").Inc()
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusAccepted)
+	json.NewEncoder(w).Encode(response)
+}
+
+func (h *PaymentHandler) GetPayment(w http.ResponseWriter, r *http.Request) {
+	start := time.Now()
+	defer func() {
+		metrics.HTTPRequestDuration.WithLabelValues(r.Method, "/api/payments/{id}").Observe(time.Since(start).Seconds())
+	}()
+
+	id := chi.URLParam(r, "id")
+	payment, err := h.paymentService.GetPayment(r.Context(), id)
+	if err != nil {
+		logger.GetLogger().WithError(err).Error("failed to get payment")
+		status := errorStatus(err)
+		metrics.HTTPRequestsTotal.WithLabelValues(r.Method, "/api/payments/{id}", strconv.Itoa(status)).Inc()
+		http.Error(w, err.Error(), status)
+		return
+	}
+
+	metrics.HTTPRequestsTotal.WithLabelValues(r.Method, "/api/payments/{id}", "200").Inc()
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(payment)
+}
+
+func (h *PaymentHandler) GetPaymentsByBooking(w http.ResponseWriter, r *http.Request) {
+	start := time.Now()
+	defer func() {
+		metrics.HTTPRequestDuration.WithLabelValues(r.Method, "/api/payments/booking/{bookingId}").Observe(time.Since(start).Seconds())
+	}()
+
+	bookingID := chi.URLParam(r, "bookingId")
+	payments, err := h.paymentService.GetPaymentsByBooking(r.Context(), bookingID)
+	if err != nil {
+		logger.GetLogger().WithError(err).Error("failed to get payments by booking")
+		status := errorStatus(err)
+		metrics.HTTPRequestsTotal.WithLabelValues(r.Method, "/api/payments/booking/{bookingId}", strconv.Itoa(status)).Inc()
+		http.Error(w, err.Error(), status)
+		return
+	}
+
+	metrics.HTTPRequestsTotal.WithLabelValues(r.Method, "/api/payments/booking/{bookingId}", "200").Inc()
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(payments)
+}
+
+func (h *PaymentHandler) CapturePayment(w http.ResponseWriter, r *http.Request) {
+	start := time.Now()
+	defer func() {
+		metrics.HTTPRequestDuration.WithLabelValues(r.Method, "/api/payments/{id}/capture").Observe(time.Since(start).Seconds())
+	}()
+
+	id := chi.URLParam(r, "id")
+	payment, err := h.paymentService.CapturePayment(r.Context(), id)
+	if err != nil {
+		logger.GetLogger().WithError(err).Error("failed to capture payment")
+		status := errorStatus(err)
+		metrics.HTTPRequestsTotal.WithLabelValues(r.Method, "/api/payments/{id}/capture", strconv.Itoa(status)).Inc()
+		http.Error(w, err.Error(), status)
+		return
+	}
+
+	metrics.HTTPRequestsTotal.WithLabelValues(r.Method, "/api/payments/{id}/capture", "200").Inc()
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(payment)
+}
+
+// AuthenticatePayment is called once the guest has passed the 3-D Secure
+// challenge of a payment.
+func (h *PaymentHandler) AuthenticatePayment(w http.ResponseWriter, r *http.Request) {
+	start := time.Now()
+	defer func() {
+		metrics.HTTPRequestDuration.WithLabelValues(r.Method, "/api/payments/{id}/authenticate").Observe(time.Since(start).Seconds())
+	}()
+
+	id := chi.URLParam(r, "id")
+	payment, err := h.paymentService.AuthenticatePayment(r.Context(), id)
+	if err != nil {
+		logger.GetLogger().WithError(err).Error("failed to authenticate payment")
+		status := errorStatus(err)
+		metrics.HTTPRequestsTotal.WithLabelValues(r.Method, "/api/payments/{id}/authenticate", strconv.Itoa(status)).Inc()
+		http.Error(w, err.Error(), status)
+		return
+	}
+
+	metrics.HTTPRequestsTotal.WithLabelValues(r.Method, "/api/payments/{id}/authenticate", "200").Inc()
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(payment)
+}
+
+func (h *PaymentHandler) VoidPayment(w http.ResponseWriter, r *http.Request) {
+	start := time.Now()
+	defer func() {
+		metrics.HTTPRequestDuration.WithLabelValues(r.Method, "/api/payments/{id}/void").Observe(time.Since(start).Seconds())
+	}()
+
+	id := chi.URLParam(r, "id")
+	payment, err := h.paymentService.VoidPayment(r.Context(), id)
+	if err != nil {
+		logger.GetLogger().WithError(err).Error("failed to void payment")
+		status := errorStatus(err)
+		metrics.HTTPRequestsTotal.WithLabelValues(r.Method, "/api/payments/{id}/void", strconv.Itoa(status)).Inc()
+		http.Error(w, err.Error(), status)
+		return
+	}
+
+	metrics.HTTPRequestsTotal.WithLabelValues(r.Method, "/api/payments/{id}/void", "200").Inc()
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(payment)
+}
+
+func (h *PaymentHandler) CaptureBookingPayment(w http.ResponseWriter, r *http.Request) {
+	start := time.Now()
+	defer func() {
+		metrics.HTTPRequestDuration.WithLabelValues(r.Method, "/api/payments/booking/{bookingId}/capture").Observe(time.Since(start).Seconds())
+	}()
+
+	bookingId := chi.URLParam(r, "bookingId")
+	payment, err := h.paymentService.CaptureBookingPayment(r.Context(), bookingId)
+	if err != nil {
+		logger.GetLogger().WithError(err).Error("failed to capture booking payment")
+		status := errorStatus(err)
+		metrics.HTTPRequestsTotal.WithLabelValues(r.Method, "/api/payments/booking/{bookingId}/capture", strconv.Itoa(status)).Inc()
+		http.Error(w, err.Error(), status)
+		return
+	}
+
+	metrics.HTTPRequestsTotal.WithLabelValues(r.Method, "/api/payments/booking/{bookingId}/capture", "200").Inc()
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(payment)
+}
+
+func (h *PaymentHandler) VoidBookingPayment(w http.ResponseWriter, r *http.Request) {
+	start := time.Now()
+	defer func() {
+		metrics.HTTPRequestDuration.WithLabelValues(r.Method, "/api/payments/booking/{bookingId}/void").Observe(time.Since(start).Seconds())
+	}()
+
+	bookingId := chi.URLParam(r, "bookingId")
+	payment, err := h.paymentService.VoidBookingPayment(r.Context(), bookingId)
+	if err != nil {
+		logger.GetLogger().WithError(err).Error("failed to void booking payment")
+		status := errorStatus(err)
+		metrics.HTTPRequestsTotal.WithLabelValues(r.Method, "/api/payments/booking/{bookingId}/void", strconv.Itoa(status)).Inc()
+		http.Error(w, err.Error(), status)
+		return
+	}
+
+	metrics.HTTPRequestsTotal.WithLabelValues(r.Method, "/api/payments/booking/{bookingId}/void", "200").Inc()
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(payment)
+}
+
+func (h *PaymentHandler) GetDeadLetters(w http.ResponseWriter, r *http.Request) {
+	start := time.Now()
+	defer func() {
+		metrics.HTTPRequestDuration.WithLabelValues(r.Method, "/api/admin/webhooks/dead-letters").Observe(time.Since(start).Seconds())
+	}()
+
+	limit := defaultDeadLetterLimit
+	if value := r.URL.Query().Get("limit"); value != "" {
+		parsed, err := strconv.Atoi(value)
+		if err != nil || parsed < 1 {
+			metrics.HTTPRequestsTotal.WithLabelValues(r.Method, "/api/admin/webhooks/dead-letters", "400").Inc()
+			http.Error(w, "limit must be a positive integer", http.StatusBadRequest)
+			return
+		}
+		limit = parsed
+	}
+
+	deadLetters, err := h.paymentService.GetDeadLetters(r.Context(), limit)
+	if err != nil {
+		logger.GetLogger().WithError(err).Error("failed to get dead letters")
+		status := errorStatus(err)
+		metrics.HTTPRequestsTotal.WithLabelValues(r.Method, "/api/admin/webhooks/dead-letters", strconv.Itoa(status)).Inc()
+		http.Error(w, err.Error(), status)
+		return
+	}
+
+	metrics.HTTPRequestsTotal.WithLabelValues(r.Method, "/api/admin/webhooks/dead-letters", "200").Inc()
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(deadLetters)
+}
+
+func (h *PaymentHandler) RedeliverWebhook(w http.ResponseWriter, r *http.Request) {
+	start := time.Now()
+	defer func() {
+		metrics.HTTPRequestDuration.WithLabelValues(r.Method, "/api/admin/webhooks/dead-letters/{id}/redeliver").Observe(time.Since(start).Seconds())
+	}()
+
+	id, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		metrics.HTTPRequestsTotal.WithLabelValues(r.Method, "/api/admin/webhooks/dead-letters/{id}/redeliver", "400").Inc()
+		http.Error(w, "invalid dead letter id", http.StatusBadRequest)
+		return
+	}
+
+	delivery, err := h.paymentService.RedeliverWebhook(r.Context(), id)
+	if err != nil {
+		logger.GetLogger().WithError(err).Error("failed to redeliver webhook")
+		status := errorStatus(err)
+		metrics.HTTPRequestsTotal.WithLabelValues(r.Method, "/api/admin/webhooks/dead-letters/{id}/redeliver", strconv.Itoa(status)).Inc()
+		http.Error(w, err.Error(), status)
+		return
+	}
+
+	metrics.HTTPRequestsTotal.WithLabelValues(r.Method, "/api/admin/webhooks/dead-letters/{id}/redeliver", "202").Inc()
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusAccepted)
+	json.NewEncoder(w).Encode(delivery)
+}
+
+func errorStatus(err error) int {
+	switch {
+	case errors.Is(err, domain.ErrInvalidRefundAmount), errors.Is(err, domain.ErrInvalidCaptureMode),
+		errors.Is(err, money.ErrCurrencyMismatch):
+		return http.StatusBadRequest
+	case errors.Is(err, sql.ErrNoRows):
+		return http.StatusNotFound
+	case errors.Is(err, domain.ErrPaymentNotRefundable), errors.Is(err, domain.ErrPaymentNotAuthorized),
+		errors.Is(err, domain.ErrAuthorizationExpired), errors.Is(err, domain.ErrStatusChanged),
+		errors.Is(err, domain.ErrNoActionRequired):
+		return http.StatusConflict
+	case errors.Is(err, domain.ErrRefundExceedsPayment):
+		return http.StatusUnprocessableEntity
+	case errors.Is(err, domain.ErrTransactionNotFound), errors.Is(err, domain.ErrInvalidTransaction),
+		errors.Is(err, domain.ErrGatewayAmountExceeded):
+		return http.StatusBadGateway
+	case errors.Is(err, domain.ErrGatewayTimeout), errors.Is(err, context.DeadlineExceeded):
+		return http.StatusGatewayTimeout
+	default:
+		return http.StatusInternalServerError
+	}
+}
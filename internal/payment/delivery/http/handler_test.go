@@ -3,6 +3,7 @@ package http
 import (
 	"bytes"
 	"context"
+	"database/sql"
 	"encoding/json"
 	"net/http"
 	"net/http/httptest"
@@ -10,7 +11,9 @@ import (
 
 	"hotel-booking-system/internal/payment/domain"
 	"hotel-booking-system/pkg/logger"
+	"hotel-booking-system/pkg/money"
 
+	"github.com/go-chi/chi/v5"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 )
@@ -27,6 +30,100 @@ func (m *MockPaymentService) ProcessPayment(ctx context.Context, req *domain.Pay
 	return args.Get(0).(*domain.PaymentResponse), args.Error(1)
 }
 
+func (m *MockPaymentService) ProcessRefund(ctx context.Context, req *domain.RefundRequest) (*domain.RefundResponse, error) {
+	args := m.Called(ctx, req)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.RefundResponse), args.Error(1)
+}
+
+func (m *MockPaymentService) RefundPayment(ctx context.Context, paymentID string, req *domain.RefundRequest) (*domain.RefundResponse, error) {
+	args := m.Called(ctx, paymentID, req)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.RefundResponse), args.Error(1)
+}
+
+func (m *MockPaymentService) GetPayment(ctx context.Context, id string) (*domain.Payment, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.Payment), args.Error(1)
+}
+
+func (m *MockPaymentService) GetPaymentsByBooking(ctx context.Context, bookingID string) ([]domain.Payment, error) {
+	args := m.Called(ctx, bookingID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]domain.Payment), args.Error(1)
+}
+
+func (m *MockPaymentService) CapturePayment(ctx context.Context, id string) (*domain.Payment, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.Payment), args.Error(1)
+}
+
+func (m *MockPaymentService) AuthenticatePayment(ctx context.Context, id string) (*domain.Payment, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.Payment), args.Error(1)
+}
+
+func (m *MockPaymentService) VoidPayment(ctx context.Context, id string) (*domain.Payment, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.Payment), args.Error(1)
+}
+
+func (m *MockPaymentService) CaptureBookingPayment(ctx context.Context, bookingID string) (*domain.Payment, error) {
+	args := m.Called(ctx, bookingID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.Payment), args.Error(1)
+}
+
+func (m *MockPaymentService) VoidBookingPayment(ctx context.Context, bookingID string) (*domain.Payment, error) {
+	args := m.Called(ctx, bookingID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.Payment), args.Error(1)
+}
+
+func (m *MockPaymentService) GetDeadLetters(ctx context.Context, limit int) ([]domain.DeadLetter, error) {
+	args := m.Called(ctx, limit)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]domain.DeadLetter), args.Error(1)
+}
+
+func (m *MockPaymentService) RedeliverWebhook(ctx context.Context, deadLetterID int64) (*domain.WebhookDelivery, error) {
+	args := m.Called(ctx, deadLetterID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.WebhookDelivery), args.Error(1)
+}
+
+func withURLParam(req *http.Request, key, value string) *http.Request {
+	rctx := chi.NewRouteContext()
+	rctx.URLParams.Add(key, value)
+	return req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, rctx))
+}
+
 func TestPaymentHandler_CreatePayment(t *testing.T) {
 	logger.Init("info")
 
@@ -45,8 +142,7 @@ func TestPaymentHandler_CreatePayment(t *testing.T) {
 
 		reqBody := domain.PaymentRequest{
 			BookingID: "booking-123",
-			Amount:    1000.0,
-			Currency:  "RUB",
+			Amount:    money.New(100000, "RUB"),
 		}
 
 		body, _ := json.Marshal(reqBody)
@@ -68,7 +164,7 @@ func TestPaymentHandler_CreatePayment(t *testing.T) {
 	t.Run("success without currency", func(t *testing.T) {
 		mockService := new(MockPaymentService)
 		mockService.On("ProcessPayment", mock.Anything, mock.MatchedBy(func(req *domain.PaymentRequest) bool {
-			return req.Currency == "RUB"
+			return req.Amount == money.New(200050, "RUB")
 		})).Return(
 			&domain.PaymentResponse{
 				PaymentID: "payment-456",
@@ -79,13 +175,8 @@ func TestPaymentHandler_CreatePayment(t *testing.T) {
 
 		handler := NewPaymentHandler(mockService)
 
-		reqBody := domain.PaymentRequest{
-			BookingID: "booking-456",
-			Amount:    2000.0,
-		}
-
-		body, _ := json.Marshal(reqBody)
-		req := httptest.NewRequest("POST", "/api/payments", bytes.NewBuffer(body))
+		body := `{"booking_id":"booking-456","amount":{"amount":"2000.50"}}`
+		req := httptest.NewRequest("POST", "/api/payments", bytes.NewBufferString(body))
 		req.Header.Set("Content-Type", "application/json")
 		w := httptest.NewRecorder()
 
@@ -109,6 +200,21 @@ func TestPaymentHandler_CreatePayment(t *testing.T) {
 		mockService.AssertNotCalled(t, "ProcessPayment")
 	})
 
+	t.Run("amount with fractions of a kopeck", func(t *testing.T) {
+		mockService := new(MockPaymentService)
+		handler := NewPaymentHandler(mockService)
+
+		body := `{"booking_id":"booking-456","amount":{"amount":"2000.505","currency":"RUB"}}`
+		req := httptest.NewRequest("POST", "/api/payments", bytes.NewBufferString(body))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+
+		handler.CreatePayment(w, req)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		mockService.AssertNotCalled(t, "ProcessPayment")
+	})
+
 	t.Run("service error", func(t *testing.T) {
 		mockService := new(MockPaymentService)
 		mockService.On("ProcessPayment", mock.Anything, mock.AnythingOfType("*domain.PaymentRequest")).Return(
@@ -120,7 +226,7 @@ func TestPaymentHandler_CreatePayment(t *testing.T) {
 
 		reqBody := domain.PaymentRequest{
 			BookingID: "booking-789",
-			Amount:    3000.0,
+			Amount:    money.New(300000, "RUB"),
 		}
 
 		body, _ := json.Marshal(reqBody)
@@ -135,6 +241,441 @@ func TestPaymentHandler_CreatePayment(t *testing.T) {
 	})
 }
 
+func TestPaymentHandler_CreateRefund(t *testing.T) {
+	logger.Init("info")
+
+	t.Run("success", func(t *testing.T) {
+		mockService := new(MockPaymentService)
+		mockService.On("ProcessRefund", mock.Anything, mock.MatchedBy(func(req *domain.RefundRequest) bool {
+			return req.BookingID == "booking-123" && req.Amount == money.New(100000, "RUB") && req.Amount.Currency == "RUB"
+		})).Return(
+			&domain.RefundResponse{
+				RefundID: "refund-123",
+				Status:   "processing",
+			},
+			nil,
+		)
+
+		handler := NewPaymentHandler(mockService)
+
+		body, _ := json.Marshal(domain.RefundRequest{BookingID: "booking-123", Amount: money.New(100000, "RUB")})
+		req := httptest.NewRequest("POST", "/api/payments/refunds", bytes.NewBuffer(body))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+
+		handler.CreateRefund(w, req)
+
+		assert.Equal(t, http.StatusAccepted, w.Code)
+		mockService.AssertExpectations(t)
+
+		var response domain.RefundResponse
+		json.Unmarshal(w.Body.Bytes(), &response)
+		assert.Equal(t, "refund-123", response.RefundID)
+	})
+
+	t.Run("invalid JSON", func(t *testing.T) {
+		mockService := new(MockPaymentService)
+		handler := NewPaymentHandler(mockService)
+
+		req := httptest.NewRequest("POST", "/api/payments/refunds", bytes.NewBufferString("invalid json"))
+		w := httptest.NewRecorder()
+
+		handler.CreateRefund(w, req)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		mockService.AssertNotCalled(t, "ProcessRefund")
+	})
+
+	t.Run("service error", func(t *testing.T) {
+		mockService := new(MockPaymentService)
+		mockService.On("ProcessRefund", mock.Anything, mock.Anything).Return(nil, assert.AnError)
+
+		handler := NewPaymentHandler(mockService)
+
+		body, _ := json.Marshal(domain.RefundRequest{BookingID: "booking-123", Amount: money.New(100000, "RUB")})
+		req := httptest.NewRequest("POST", "/api/payments/refunds", bytes.NewBuffer(body))
+		w := httptest.NewRecorder()
+
+		handler.CreateRefund(w, req)
+
+		assert.Equal(t, http.StatusInternalServerError, w.Code)
+		mockService.AssertExpectations(t)
+	})
+}
+
+func TestPaymentHandler_RefundPayment(t *testing.T) {
+	logger.Init("info")
+
+	t.Run("accepted", func(t *testing.T) {
+		mockService := new(MockPaymentService)
+		mockService.On("RefundPayment", mock.Anything, "payment-123", mock.MatchedBy(func(req *domain.RefundRequest) bool {
+			return req.Amount == money.New(30000, "RUB")
+		})).Return(&domain.RefundResponse{
+			RefundID:  "refund-123",
+			PaymentID: "payment-123",
+			Amount:    money.New(30000, "RUB"),
+			Status:    "processing",
+		}, nil)
+
+		handler := NewPaymentHandler(mockService)
+
+		body, _ := json.Marshal(domain.RefundRequest{Amount: money.New(30000, "RUB")})
+		req := withURLParam(httptest.NewRequest("POST", "/api/payments/payment-123/refunds", bytes.NewBuffer(body)), "id", "payment-123")
+		w := httptest.NewRecorder()
+
+		handler.RefundPayment(w, req)
+
+		assert.Equal(t, http.StatusAccepted, w.Code)
+		var response domain.RefundResponse
+		json.Unmarshal(w.Body.Bytes(), &response)
+		assert.Equal(t, "refund-123", response.RefundID)
+		mockService.AssertExpectations(t)
+	})
+
+	errorCases := []struct {
+		name   string
+		err    error
+		status int
+	}{
+		{"invalid amount", domain.ErrInvalidRefundAmount, http.StatusBadRequest},
+		{"payment not found", sql.ErrNoRows, http.StatusNotFound},
+		{"payment not captured", domain.ErrPaymentNotRefundable, http.StatusConflict},
+		{"exceeds balance", domain.ErrRefundExceedsPayment, http.StatusUnprocessableEntity},
+	}
+	for _, tc := range errorCases {
+		t.Run(tc.name, func(t *testing.T) {
+			mockService := new(MockPaymentService)
+			mockService.On("RefundPayment", mock.Anything, "payment-123", mock.Anything).Return(nil, tc.err)
+
+			handler := NewPaymentHandler(mockService)
+
+			body, _ := json.Marshal(domain.RefundRequest{Amount: money.New(30000, "RUB")})
+			req := withURLParam(httptest.NewRequest("POST", "/api/payments/payment-123/refunds", bytes.NewBuffer(body)), "id", "payment-123")
+			w := httptest.NewRecorder()
+
+			handler.RefundPayment(w, req)
+
+			assert.Equal(t, tc.status, w.Code)
+		})
+	}
+}
+
+func TestPaymentHandler_GetPayment(t *testing.T) {
+	logger.Init("info")
+
+	t.Run("success", func(t *testing.T) {
+		mockService := new(MockPaymentService)
+		mockService.On("GetPayment", mock.Anything, "payment-123").Return(&domain.Payment{
+			ID:        "payment-123",
+			BookingID: "booking-123",
+			Amount:    money.New(100000, "RUB"),
+			Status:    domain.PaymentPaid,
+		}, nil)
+
+		handler := NewPaymentHandler(mockService)
+
+		req := withURLParam(httptest.NewRequest("GET", "/api/payments/payment-123", nil), "id", "payment-123")
+		w := httptest.NewRecorder()
+
+		handler.GetPayment(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		var response domain.Payment
+		json.Unmarshal(w.Body.Bytes(), &response)
+		assert.Equal(t, domain.PaymentPaid, response.Status)
+		mockService.AssertExpectations(t)
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		mockService := new(MockPaymentService)
+		mockService.On("GetPayment", mock.Anything, "missing").Return(nil, sql.ErrNoRows)
+
+		handler := NewPaymentHandler(mockService)
+
+		req := withURLParam(httptest.NewRequest("GET", "/api/payments/missing", nil), "id", "missing")
+		w := httptest.NewRecorder()
+
+		handler.GetPayment(w, req)
+
+		assert.Equal(t, http.StatusNotFound, w.Code)
+	})
+}
+
+func TestPaymentHandler_GetPaymentsByBooking(t *testing.T) {
+	logger.Init("info")
+
+	mockService := new(MockPaymentService)
+	mockService.On("GetPaymentsByBooking", mock.Anything, "booking-123").Return([]domain.Payment{
+		{ID: "payment-2", BookingID: "booking-123", Status: domain.PaymentPaid},
+		{ID: "payment-1", BookingID: "booking-123", Status: domain.PaymentFailed},
+	}, nil)
+
+	handler := NewPaymentHandler(mockService)
+
+	req := withURLParam(httptest.NewRequest("GET", "/api/payments/booking/booking-123", nil), "bookingId", "booking-123")
+	w := httptest.NewRecorder()
+
+	handler.GetPaymentsByBooking(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	var response []domain.Payment
+	json.Unmarshal(w.Body.Bytes(), &response)
+	assert.Len(t, response, 2)
+	mockService.AssertExpectations(t)
+}
+
+func TestPaymentHandler_AuthenticatePayment(t *testing.T) {
+	logger.Init("info")
+
+	t.Run("success", func(t *testing.T) {
+		mockService := new(MockPaymentService)
+		mockService.On("AuthenticatePayment", mock.Anything, "payment-123").Return(&domain.Payment{
+			ID:     "payment-123",
+			Status: domain.PaymentPaid,
+		}, nil)
+
+		handler := NewPaymentHandler(mockService)
+
+		req := withURLParam(httptest.NewRequest("POST", "/api/payments/payment-123/authenticate", nil), "id", "payment-123")
+		w := httptest.NewRecorder()
+
+		handler.AuthenticatePayment(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		var response domain.Payment
+		json.Unmarshal(w.Body.Bytes(), &response)
+		assert.Equal(t, domain.PaymentPaid, response.Status)
+		mockService.AssertExpectations(t)
+	})
+
+	t.Run("not waiting for authentication", func(t *testing.T) {
+		mockService := new(MockPaymentService)
+		mockService.On("AuthenticatePayment", mock.Anything, "payment-123").Return(nil, domain.ErrNoActionRequired)
+
+		handler := NewPaymentHandler(mockService)
+
+		req := withURLParam(httptest.NewRequest("POST", "/api/payments/payment-123/authenticate", nil), "id", "payment-123")
+		w := httptest.NewRecorder()
+
+		handler.AuthenticatePayment(w, req)
+
+		assert.Equal(t, http.StatusConflict, w.Code)
+	})
+}
+
+func TestPaymentHandler_CapturePayment(t *testing.T) {
+	logger.Init("info")
+
+	t.Run("success", func(t *testing.T) {
+		mockService := new(MockPaymentService)
+		mockService.On("CapturePayment", mock.Anything, "payment-123").Return(&domain.Payment{
+			ID:     "payment-123",
+			Status: domain.PaymentPaid,
+		}, nil)
+
+		handler := NewPaymentHandler(mockService)
+
+		req := withURLParam(httptest.NewRequest("POST", "/api/payments/payment-123/capture", nil), "id", "payment-123")
+		w := httptest.NewRecorder()
+
+		handler.CapturePayment(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		var response domain.Payment
+		json.Unmarshal(w.Body.Bytes(), &response)
+		assert.Equal(t, domain.PaymentPaid, response.Status)
+		mockService.AssertExpectations(t)
+	})
+
+	t.Run("not authorized", func(t *testing.T) {
+		mockService := new(MockPaymentService)
+		mockService.On("CapturePayment", mock.Anything, "payment-123").Return(nil, domain.ErrPaymentNotAuthorized)
+
+		handler := NewPaymentHandler(mockService)
+
+		req := withURLParam(httptest.NewRequest("POST", "/api/payments/payment-123/capture", nil), "id", "payment-123")
+		w := httptest.NewRecorder()
+
+		handler.CapturePayment(w, req)
+
+		assert.Equal(t, http.StatusConflict, w.Code)
+	})
+
+	t.Run("authorization expired", func(t *testing.T) {
+		mockService := new(MockPaymentService)
+		mockService.On("CaptureBookingPayment", mock.Anything, "booking-123").Return(nil, domain.ErrAuthorizationExpired)
+
+		handler := NewPaymentHandler(mockService)
+
+		req := withURLParam(httptest.NewRequest("POST", "/api/payments/booking/booking-123/capture", nil), "bookingId", "booking-123")
+		w := httptest.NewRecorder()
+
+		handler.CaptureBookingPayment(w, req)
+
+		assert.Equal(t, http.StatusConflict, w.Code)
+	})
+
+	t.Run("gateway timeout", func(t *testing.T) {
+		mockService := new(MockPaymentService)
+		mockService.On("CapturePayment", mock.Anything, "payment-123").Return(nil, domain.ErrGatewayTimeout)
+
+		handler := NewPaymentHandler(mockService)
+
+		req := withURLParam(httptest.NewRequest("POST", "/api/payments/payment-123/capture", nil), "id", "payment-123")
+		w := httptest.NewRecorder()
+
+		handler.CapturePayment(w, req)
+
+		assert.Equal(t, http.StatusGatewayTimeout, w.Code)
+	})
+}
+
+func TestPaymentHandler_VoidPayment(t *testing.T) {
+	logger.Init("info")
+
+	t.Run("success", func(t *testing.T) {
+		mockService := new(MockPaymentService)
+		mockService.On("VoidBookingPayment", mock.Anything, "booking-123").Return(&domain.Payment{
+			ID:        "payment-123",
+			BookingID: "booking-123",
+			Status:    domain.PaymentVoided,
+		}, nil)
+
+		handler := NewPaymentHandler(mockService)
+
+		req := withURLParam(httptest.NewRequest("POST", "/api/payments/booking/booking-123/void", nil), "bookingId", "booking-123")
+		w := httptest.NewRecorder()
+
+		handler.VoidBookingPayment(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		var response domain.Payment
+		json.Unmarshal(w.Body.Bytes(), &response)
+		assert.Equal(t, domain.PaymentVoided, response.Status)
+		mockService.AssertExpectations(t)
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		mockService := new(MockPaymentService)
+		mockService.On("VoidPayment", mock.Anything, "missing").Return(nil, sql.ErrNoRows)
+
+		handler := NewPaymentHandler(mockService)
+
+		req := withURLParam(httptest.NewRequest("POST", "/api/payments/missing/void", nil), "id", "missing")
+		w := httptest.NewRecorder()
+
+		handler.VoidPayment(w, req)
+
+		assert.Equal(t, http.StatusNotFound, w.Code)
+	})
+}
+
+func TestPaymentHandler_GetDeadLetters(t *testing.T) {
+	logger.Init("info")
+
+	t.Run("default limit", func(t *testing.T) {
+		mockService := new(MockPaymentService)
+		mockService.On("GetDeadLetters", mock.Anything, 100).Return([]domain.DeadLetter{
+			{ID: 7, PaymentID: "payment-123", BookingID: "booking-123", Attempts: 10, LastError: "webhook returned status 503"},
+		}, nil)
+
+		handler := NewPaymentHandler(mockService)
+
+		req := httptest.NewRequest("GET", "/api/admin/webhooks/dead-letters", nil)
+		w := httptest.NewRecorder()
+
+		handler.GetDeadLetters(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		var response []domain.DeadLetter
+		json.Unmarshal(w.Body.Bytes(), &response)
+		assert.Len(t, response, 1)
+		assert.Equal(t, int64(7), response[0].ID)
+		mockService.AssertExpectations(t)
+	})
+
+	t.Run("custom limit", func(t *testing.T) {
+		mockService := new(MockPaymentService)
+		mockService.On("GetDeadLetters", mock.Anything, 5).Return([]domain.DeadLetter{}, nil)
+
+		handler := NewPaymentHandler(mockService)
+
+		req := httptest.NewRequest("GET", "/api/admin/webhooks/dead-letters?limit=5", nil)
+		w := httptest.NewRecorder()
+
+		handler.GetDeadLetters(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		mockService.AssertExpectations(t)
+	})
+
+	t.Run("invalid limit", func(t *testing.T) {
+		mockService := new(MockPaymentService)
+		handler := NewPaymentHandler(mockService)
+
+		req := httptest.NewRequest("GET", "/api/admin/webhooks/dead-letters?limit=0", nil)
+		w := httptest.NewRecorder()
+
+		handler.GetDeadLetters(w, req)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		mockService.AssertNotCalled(t, "GetDeadLetters", mock.Anything, mock.Anything)
+	})
+}
+
+func TestPaymentHandler_RedeliverWebhook(t *testing.T) {
+	logger.Init("info")
+
+	t.Run("success", func(t *testing.T) {
+		mockService := new(MockPaymentService)
+		mockService.On("RedeliverWebhook", mock.Anything, int64(7)).Return(&domain.WebhookDelivery{
+			ID:        42,
+			PaymentID: "payment-123",
+			BookingID: "booking-123",
+		}, nil)
+
+		handler := NewPaymentHandler(mockService)
+
+		req := withURLParam(httptest.NewRequest("POST", "/api/admin/webhooks/dead-letters/7/redeliver", nil), "id", "7")
+		w := httptest.NewRecorder()
+
+		handler.RedeliverWebhook(w, req)
+
+		assert.Equal(t, http.StatusAccepted, w.Code)
+		var response domain.WebhookDelivery
+		json.Unmarshal(w.Body.Bytes(), &response)
+		assert.Equal(t, int64(42), response.ID)
+		mockService.AssertExpectations(t)
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		mockService := new(MockPaymentService)
+		mockService.On("RedeliverWebhook", mock.Anything, int64(8)).Return(nil, sql.ErrNoRows)
+
+		handler := NewPaymentHandler(mockService)
+
+		req := withURLParam(httptest.NewRequest("POST", "/api/admin/webhooks/dead-letters/8/redeliver", nil), "id", "8")
+		w := httptest.NewRecorder()
+
+		handler.RedeliverWebhook(w, req)
+
+		assert.Equal(t, http.StatusNotFound, w.Code)
+	})
+
+	t.Run("invalid id", func(t *testing.T) {
+		mockService := new(MockPaymentService)
+		handler := NewPaymentHandler(mockService)
+
+		req := withURLParam(httptest.NewRequest("POST", "/api/admin/webhooks/dead-letters/abc/redeliver", nil), "id", "abc")
+		w := httptest.NewRecorder()
+
+		handler.RedeliverWebhook(w, req)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		mockService.AssertNotCalled(t, "RedeliverWebhook", mock.Anything, mock.Anything)
+	})
+}
+
 func TestNewPaymentHandler(t *testing.T) {
 	mockService := new(MockPaymentService)
 	handler := NewPaymentHandler(mockService)
@@ -1,20 +1,40 @@
 package http
 
 import (
+	"hotel-booking-system/pkg/idempotency"
+
 	"github.com/go-chi/chi/v5"
 	"github.com/go-chi/chi/v5/middleware"
 )
 
-func SetupRoutes(handler *PaymentHandler) *chi.Mux {
+func SetupRoutes(handler *PaymentHandler, idempotencyStore idempotency.Store) *chi.Mux {
 	r := chi.NewRouter()
 
 	r.Use(middleware.Logger)
 	r.Use(middleware.Recoverer)
 	r.Use(middleware.RequestID)
 
+	// Requests that create something or move money are replayed from the
+	// idempotency store; status transitions reject a repeat by themselves.
+	idempotent := idempotency.Middleware(idempotencyStore)
+
 	r.Route("/api", func(r chi.Router) {
 		r.Route("/payments", func(r chi.Router) {
-			r.Post("/", handler.CreatePayment)
+			r.With(idempotent).Post("/", handler.CreatePayment)
+			r.With(idempotent).Post("/refunds", handler.CreateRefund)
+			r.Get("/{id}", handler.GetPayment)
+			r.With(idempotent).Post("/{id}/refunds", handler.RefundPayment)
+			r.Post("/{id}/capture", handler.CapturePayment)
+			r.Post("/{id}/authenticate", handler.AuthenticatePayment)
+			r.Post("/{id}/void", handler.VoidPayment)
+			r.Get("/booking/{bookingId}", handler.GetPaymentsByBooking)
+			r.Post("/booking/{bookingId}/capture", handler.CaptureBookingPayment)
+			r.Post("/booking/{bookingId}/void", handler.VoidBookingPayment)
+		})
+
+		r.Route("/admin/webhooks/dead-letters", func(r chi.Router) {
+			r.Get("/", handler.GetDeadLetters)
+			r.Post("/{id}/redeliver", handler.RedeliverWebhook)
 		})
 	})
 
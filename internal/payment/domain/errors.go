@@ -0,0 +1,19 @@
+package domain
+
+import "errors"
+
+var (
+	ErrStatusChanged         = errors.New("payment status was changed concurrently")
+	ErrInvalidRefundAmount   = errors.New("refund amount must be positive")
+	ErrRefundExceedsPayment  = errors.New("refund amount exceeds the refundable balance of the payment")
+	ErrPaymentNotRefundable  = errors.New("payment cannot be refunded in its current status")
+	ErrRefundAlreadyComplete = errors.New("refund was already processed")
+	ErrGatewayTimeout        = errors.New("payment gateway timed out")
+	ErrTransactionNotFound   = errors.New("gateway transaction not found")
+	ErrInvalidTransaction    = errors.New("gateway transaction is not in a state that allows this operation")
+	ErrGatewayAmountExceeded = errors.New("amount exceeds the gateway transaction balance")
+	ErrInvalidCaptureMode    = errors.New("capture mode must be automatic or manual")
+	ErrPaymentNotAuthorized  = errors.New("payment is not authorized")
+	ErrAuthorizationExpired  = errors.New("payment authorization has expired")
+	ErrNoActionRequired      = errors.New("payment is not waiting for 3-D Secure authentication")
+)
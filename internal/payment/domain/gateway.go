@@ -0,0 +1,48 @@
+package domain
+
+import (
+	"context"
+
+	"hotel-booking-system/pkg/money"
+)
+
+type GatewayStatus string
+
+const (
+	GatewayApproved       GatewayStatus = "approved"
+	GatewayDeclined       GatewayStatus = "declined"
+	GatewayRequiresAction GatewayStatus = "requires_action"
+)
+
+// AuthorizeRequest asks the provider to authorize a payment. PaymentID is the
+// idempotency key of the authorization: authorizing the same payment again
+// returns the transaction of the first attempt instead of charging the card
+// twice.
+type AuthorizeRequest struct {
+	PaymentID string
+	Amount    money.Money
+	CardToken string
+}
+
+// GatewayResult is the provider's answer. A decline or a required customer
+// action is a result, not an error; errors mean the outcome is unknown or the
+// operation was rejected as invalid.
+type GatewayResult struct {
+	TransactionID string
+	Status        GatewayStatus
+	DeclineCode   string
+	ActionURL     string
+}
+
+// Gateway is a card payment provider. Capture, Void and Refund take the
+// transaction ID returned by Authorize, or by Authenticate for a transaction
+// that required a 3-D Secure challenge.
+type Gateway interface {
+	Authorize(ctx context.Context, req AuthorizeRequest) (*GatewayResult, error)
+	// Authenticate finishes the authorization of a transaction once the
+	// customer has completed its challenge.
+	Authenticate(ctx context.Context, transactionID string) (*GatewayResult, error)
+	Capture(ctx context.Context, transactionID string, amount money.Money) (*GatewayResult, error)
+	Void(ctx context.Context, transactionID string) (*GatewayResult, error)
+	Refund(ctx context.Context, transactionID string, amount money.Money) (*GatewayResult, error)
+}
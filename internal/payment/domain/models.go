@@ -1,9 +1,79 @@
 package domain
 
+import (
+	"time"
+
+	"hotel-booking-system/pkg/money"
+)
+
+type PaymentStatus string
+
+const (
+	PaymentProcessing        PaymentStatus = "processing"
+	PaymentPaid              PaymentStatus = "paid"
+	PaymentFailed            PaymentStatus = "failed"
+	PaymentPartiallyRefunded PaymentStatus = "partially_refunded"
+	PaymentRefunded          PaymentStatus = "refunded"
+	PaymentAuthorized        PaymentStatus = "authorized"
+	PaymentVoided            PaymentStatus = "voided"
+	PaymentExpired           PaymentStatus = "expired"
+	// PaymentRequiresAction waits for the guest to pass the 3-D Secure
+	// challenge at ActionURL.
+	PaymentRequiresAction PaymentStatus = "requires_action"
+)
+
+// CaptureMode selects whether a payment is captured right after authorization
+// or only authorized, leaving the capture to a later request.
+type CaptureMode string
+
+const (
+	CaptureAutomatic CaptureMode = "automatic"
+	CaptureManual    CaptureMode = "manual"
+)
+
+type RefundStatus string
+
+const (
+	RefundProcessing RefundStatus = "processing"
+	RefundSucceeded  RefundStatus = "succeeded"
+	RefundFailed     RefundStatus = "failed"
+)
+
+type Payment struct {
+	ID          string        `json:"id"`
+	BookingID   string        `json:"booking_id"`
+	Amount      money.Money   `json:"amount"`
+	Status      PaymentStatus `json:"status"`
+	CaptureMode CaptureMode   `json:"capture_mode"`
+	// CardToken is kept only while the payment is processing, so that a
+	// charge cut short by a restart can be retried.
+	CardToken              string      `json:"-"`
+	RefundedAmount         money.Money `json:"refunded_amount"`
+	GatewayTransactionID   string      `json:"gateway_transaction_id,omitempty"`
+	FailureReason          string      `json:"failure_reason,omitempty"`
+	ActionURL              string      `json:"action_url,omitempty"`
+	AuthorizationExpiresAt *time.Time  `json:"authorization_expires_at,omitempty"`
+	CreatedAt              time.Time   `json:"created_at"`
+	UpdatedAt              time.Time   `json:"updated_at"`
+	ProcessedAt            *time.Time  `json:"processed_at,omitempty"`
+}
+
+type Refund struct {
+	ID            string       `json:"id"`
+	PaymentID     string       `json:"payment_id"`
+	BookingID     string       `json:"booking_id"`
+	Amount        money.Money  `json:"amount"`
+	Status        RefundStatus `json:"status"`
+	FailureReason string       `json:"failure_reason,omitempty"`
+	CreatedAt     time.Time    `json:"created_at"`
+	ProcessedAt   *time.Time   `json:"processed_at,omitempty"`
+}
+
 type PaymentRequest struct {
-	BookingID string  `json:"booking_id"`
-	Amount    float64 `json:"amount"`
-	Currency  string  `json:"currency,omitempty"`
+	BookingID   string      `json:"booking_id"`
+	Amount      money.Money `json:"amount"`
+	CardToken   string      `json:"card_token,omitempty"`
+	CaptureMode CaptureMode `json:"capture_mode,omitempty"`
 }
 
 type PaymentResponse struct {
@@ -13,9 +83,23 @@ type PaymentResponse struct {
 }
 
 type PaymentWebhook struct {
-	PaymentID   string  `json:"payment_id"`
-	BookingID   string  `json:"booking_id"`
-	Status      string  `json:"status"`
-	Amount      float64 `json:"amount"`
-	ProcessedAt string  `json:"processed_at,omitempty"`
+	PaymentID   string      `json:"payment_id"`
+	RefundID    string      `json:"refund_id,omitempty"`
+	BookingID   string      `json:"booking_id"`
+	Status      string      `json:"status"`
+	Amount      money.Money `json:"amount"`
+	ProcessedAt string      `json:"processed_at,omitempty"`
+}
+
+type RefundRequest struct {
+	BookingID string      `json:"booking_id"`
+	Amount    money.Money `json:"amount"`
+}
+
+type RefundResponse struct {
+	RefundID  string      `json:"refund_id"`
+	PaymentID string      `json:"payment_id"`
+	Amount    money.Money `json:"amount"`
+	Status    string      `json:"status"`
+	Message   string      `json:"message,omitempty"`
 }
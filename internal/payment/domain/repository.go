@@ -0,0 +1,30 @@
+package domain
+
+import (
+	"context"
+	"time"
+
+	"hotel-booking-system/pkg/money"
+)
+
+type PaymentRepository interface {
+	CreatePayment(ctx context.Context, payment *Payment) error
+	GetPaymentByID(ctx context.Context, id string) (*Payment, error)
+	GetPaymentsByBooking(ctx context.Context, bookingID string) ([]Payment, error)
+	GetExpiredAuthorizations(ctx context.Context, limit int) ([]Payment, error)
+	ClaimStalePayments(ctx context.Context, updatedBefore time.Time, limit int) ([]Payment, error)
+	UpdatePaymentStatus(ctx context.Context, payment *Payment, from PaymentStatus, delivery *WebhookDelivery) error
+	CreateRefund(ctx context.Context, refund *Refund) error
+	CreateBookingRefunds(ctx context.Context, bookingID string, amount money.Money) ([]Refund, error)
+	CompleteRefund(ctx context.Context, refundID string) (*Payment, error)
+	FailRefund(ctx context.Context, refundID, reason string) error
+}
+
+type WebhookRepository interface {
+	ClaimDueDeliveries(ctx context.Context, limit int, claimFor time.Duration) ([]WebhookDelivery, error)
+	MarkDelivered(ctx context.Context, id int64) error
+	ScheduleRetry(ctx context.Context, id int64, reason string, nextAttemptAt time.Time) error
+	MoveToDeadLetter(ctx context.Context, id int64, reason string) error
+	GetDeadLetters(ctx context.Context, limit int) ([]DeadLetter, error)
+	Redeliver(ctx context.Context, deadLetterID int64) (*WebhookDelivery, error)
+}
@@ -0,0 +1,40 @@
+package domain
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// WebhookDelivery is a queued webhook to booking-service. It is written in
+// the same transaction as the payment change it reports, so the notification
+// survives a crash or an unavailable receiver.
+type WebhookDelivery struct {
+	ID            int64           `json:"id"`
+	PaymentID     string          `json:"payment_id"`
+	BookingID     string          `json:"booking_id"`
+	Payload       json.RawMessage `json:"payload"`
+	Attempts      int             `json:"attempts"`
+	LastError     string          `json:"last_error,omitempty"`
+	NextAttemptAt time.Time       `json:"next_attempt_at"`
+	CreatedAt     time.Time       `json:"created_at"`
+}
+
+// DeadLetter is a webhook that exhausted its delivery attempts.
+type DeadLetter struct {
+	ID        int64           `json:"id"`
+	PaymentID string          `json:"payment_id"`
+	BookingID string          `json:"booking_id"`
+	Payload   json.RawMessage `json:"payload"`
+	Attempts  int             `json:"attempts"`
+	LastError string          `json:"last_error"`
+	CreatedAt time.Time       `json:"created_at"`
+	FailedAt  time.Time       `json:"failed_at"`
+}
+
+func NewWebhookDelivery(webhook PaymentWebhook) (*WebhookDelivery, error) {
+	payload, err := json.Marshal(webhook)
+	if err != nil {
+		return nil, err
+	}
+	return &WebhookDelivery{PaymentID: webhook.PaymentID, BookingID: webhook.BookingID, Payload: payload}, nil
+}
@@ -0,0 +1,236 @@
+package gateway
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"hotel-booking-system/internal/payment/domain"
+	"hotel-booking-system/pkg/money"
+
+	"github.com/google/uuid"
+)
+
+// Card tokens understood by the fake gateway. Matching is by prefix, so
+// "tok_decline_stolen" is declined like "tok_decline". Any other token,
+// including an empty one, is approved.
+const (
+	TokenInsufficientFunds = "tok_decline_insufficient_funds"
+	TokenDecline           = "tok_decline"
+	TokenThreeDSecureFail  = "tok_3ds_fail"
+	TokenThreeDSecure      = "tok_3ds"
+	TokenTimeout           = "tok_timeout"
+	TokenDelayed           = "tok_delayed"
+)
+
+type FakeConfig struct {
+	// Latency is added to every call.
+	Latency time.Duration
+	// Timeout is how long a tok_timeout call hangs before failing with
+	// domain.ErrGatewayTimeout.
+	Timeout time.Duration
+	// SettlementDelay is how long captures and refunds of tok_delayed
+	// transactions take to settle.
+	SettlementDelay time.Duration
+}
+
+// Kinds of transactions, kept in the transaction ID.
+const (
+	kindCard    = "card"
+	kindDelayed = "delayed"
+	kind3DS     = "3ds"
+	kind3DSFail = "3dsfail"
+)
+
+// transaction is what the fake gateway knows about a transaction. All of it is
+// encoded in the transaction ID, so the gateway keeps no state and accepts
+// transactions authorized before a restart. It does not track captures, voids
+// and refunds; the payment service keeps the payment state and allows each
+// operation only once.
+type transaction struct {
+	kind     string
+	currency string
+	// amount is the authorized amount in minor units of currency.
+	amount int64
+}
+
+func (tx transaction) requiresAction() bool {
+	return tx.kind == kind3DS || tx.kind == kind3DSFail
+}
+
+// id returns the ID of the transaction made for the given reference, which is
+// the same for every attempt with that reference; an empty reference gets a
+// new ID.
+func (tx transaction) id(reference string) string {
+	if reference == "" {
+		reference = uuid.New().String()
+	}
+	return fmt.Sprintf("fake_%s_%s_%d_%s", tx.kind, tx.currency, tx.amount, reference)
+}
+
+func parseTransactionID(id string) (transaction, error) {
+	parts := strings.Split(id, "_")
+	if len(parts) != 5 || parts[0] != "fake" {
+		return transaction{}, domain.ErrTransactionNotFound
+	}
+	amount, err := strconv.ParseInt(parts[3], 10, 64)
+	if err != nil {
+		return transaction{}, domain.ErrTransactionNotFound
+	}
+	switch parts[1] {
+	case kindCard, kindDelayed, kind3DS, kind3DSFail:
+	default:
+		return transaction{}, domain.ErrTransactionNotFound
+	}
+	return transaction{kind: parts[1], currency: parts[2], amount: amount}, nil
+}
+
+// FakeGateway is a stateless gateway whose behaviour is driven by the card
+// token, so every payment path can be exercised without a real provider.
+type FakeGateway struct {
+	cfg FakeConfig
+}
+
+func NewFakeGateway(cfg FakeConfig) *FakeGateway {
+	return &FakeGateway{cfg: cfg}
+}
+
+func (g *FakeGateway) Authorize(ctx context.Context, req domain.AuthorizeRequest) (*domain.GatewayResult, error) {
+	if err := g.wait(ctx, g.cfg.Latency); err != nil {
+		return nil, err
+	}
+
+	switch {
+	case strings.HasPrefix(req.CardToken, TokenTimeout):
+		if err := g.wait(ctx, g.cfg.Timeout); err != nil {
+			return nil, err
+		}
+		return nil, domain.ErrGatewayTimeout
+	case strings.HasPrefix(req.CardToken, TokenInsufficientFunds):
+		return &domain.GatewayResult{Status: domain.GatewayDeclined, DeclineCode: "insufficient_funds"}, nil
+	case strings.HasPrefix(req.CardToken, TokenDecline):
+		return &domain.GatewayResult{Status: domain.GatewayDeclined, DeclineCode: "card_declined"}, nil
+	case !req.Amount.IsPositive():
+		return &domain.GatewayResult{Status: domain.GatewayDeclined, DeclineCode: "invalid_amount"}, nil
+	}
+
+	tx := transaction{kind: kindCard, currency: req.Amount.Currency, amount: req.Amount.Amount}
+	switch {
+	case strings.HasPrefix(req.CardToken, TokenThreeDSecureFail):
+		tx.kind = kind3DSFail
+	case strings.HasPrefix(req.CardToken, TokenThreeDSecure):
+		tx.kind = kind3DS
+	case strings.HasPrefix(req.CardToken, TokenDelayed):
+		tx.kind = kindDelayed
+	}
+	id := tx.id(req.PaymentID)
+	if tx.requiresAction() {
+		return &domain.GatewayResult{
+			TransactionID: id,
+			Status:        domain.GatewayRequiresAction,
+			ActionURL:     "https://fake-gateway.local/3ds/" + id,
+		}, nil
+	}
+	return &domain.GatewayResult{TransactionID: id, Status: domain.GatewayApproved}, nil
+}
+
+// Authenticate completes the 3-D Secure challenge of a tok_3ds transaction
+// and authorizes it under a new transaction ID; the challenge of a
+// tok_3ds_fail transaction fails.
+func (g *FakeGateway) Authenticate(ctx context.Context, transactionID string) (*domain.GatewayResult, error) {
+	tx, err := parseTransactionID(transactionID)
+	if err != nil {
+		return nil, err
+	}
+	if !tx.requiresAction() {
+		return nil, domain.ErrInvalidTransaction
+	}
+	if err := g.wait(ctx, g.cfg.Latency); err != nil {
+		return nil, err
+	}
+
+	if tx.kind == kind3DSFail {
+		return &domain.GatewayResult{Status: domain.GatewayDeclined, DeclineCode: "authentication_failed"}, nil
+	}
+	tx.kind = kindCard
+	return &domain.GatewayResult{TransactionID: tx.id(""), Status: domain.GatewayApproved}, nil
+}
+
+func (g *FakeGateway) Capture(ctx context.Context, transactionID string, amount money.Money) (*domain.GatewayResult, error) {
+	tx, err := g.settle(ctx, transactionID)
+	if err != nil {
+		return nil, err
+	}
+	if !amount.IsPositive() || amount.Currency != tx.currency || amount.Amount > tx.amount {
+		return nil, domain.ErrGatewayAmountExceeded
+	}
+	return &domain.GatewayResult{TransactionID: transactionID, Status: domain.GatewayApproved}, nil
+}
+
+func (g *FakeGateway) Void(ctx context.Context, transactionID string) (*domain.GatewayResult, error) {
+	if _, err := g.transaction(transactionID); err != nil {
+		return nil, err
+	}
+	if err := g.wait(ctx, g.cfg.Latency); err != nil {
+		return nil, err
+	}
+	return &domain.GatewayResult{TransactionID: transactionID, Status: domain.GatewayApproved}, nil
+}
+
+func (g *FakeGateway) Refund(ctx context.Context, transactionID string, amount money.Money) (*domain.GatewayResult, error) {
+	tx, err := g.settle(ctx, transactionID)
+	if err != nil {
+		return nil, err
+	}
+	if !amount.IsPositive() || amount.Currency != tx.currency || amount.Amount > tx.amount {
+		return nil, domain.ErrGatewayAmountExceeded
+	}
+	return &domain.GatewayResult{TransactionID: transactionID, Status: domain.GatewayApproved}, nil
+}
+
+// transaction decodes the transaction ID. A 3-D Secure transaction is not
+// authorized until its challenge is completed, so it allows no operations.
+func (g *FakeGateway) transaction(transactionID string) (transaction, error) {
+	tx, err := parseTransactionID(transactionID)
+	if err != nil {
+		return transaction{}, err
+	}
+	if tx.requiresAction() {
+		return transaction{}, domain.ErrInvalidTransaction
+	}
+	return tx, nil
+}
+
+// settle decodes the transaction and waits the call latency plus, for
+// tok_delayed transactions, the settlement delay.
+func (g *FakeGateway) settle(ctx context.Context, transactionID string) (transaction, error) {
+	tx, err := g.transaction(transactionID)
+	if err != nil {
+		return transaction{}, err
+	}
+
+	delay := g.cfg.Latency
+	if tx.kind == kindDelayed {
+		delay += g.cfg.SettlementDelay
+	}
+	if err := g.wait(ctx, delay); err != nil {
+		return transaction{}, err
+	}
+	return tx, nil
+}
+
+func (g *FakeGateway) wait(ctx context.Context, d time.Duration) error {
+	if d <= 0 {
+		return ctx.Err()
+	}
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
@@ -0,0 +1,181 @@
+package gateway
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"hotel-booking-system/internal/payment/domain"
+	"hotel-booking-system/pkg/money"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func rub(kopecks int64) money.Money {
+	return money.New(kopecks, "RUB")
+}
+
+func authorize(t *testing.T, g *FakeGateway, token string, amount money.Money) *domain.GatewayResult {
+	result, err := g.Authorize(context.Background(), domain.AuthorizeRequest{PaymentID: "payment-123", Amount: amount, CardToken: token})
+	require.NoError(t, err)
+	return result
+}
+
+func TestFakeGateway_Authorize(t *testing.T) {
+	g := NewFakeGateway(FakeConfig{})
+
+	t.Run("approved", func(t *testing.T) {
+		result := authorize(t, g, "tok_visa", rub(100000))
+		assert.Equal(t, domain.GatewayApproved, result.Status)
+		assert.NotEmpty(t, result.TransactionID)
+	})
+
+	t.Run("retried authorization is the same transaction", func(t *testing.T) {
+		first := authorize(t, g, "tok_visa", rub(100000))
+		second := authorize(t, g, "tok_visa", rub(100000))
+		assert.Equal(t, first.TransactionID, second.TransactionID)
+
+		other, err := g.Authorize(context.Background(), domain.AuthorizeRequest{PaymentID: "payment-456", Amount: rub(100000), CardToken: "tok_visa"})
+		require.NoError(t, err)
+		assert.NotEqual(t, first.TransactionID, other.TransactionID)
+	})
+
+	t.Run("declined", func(t *testing.T) {
+		result := authorize(t, g, "tok_decline_stolen_card", rub(100000))
+		assert.Equal(t, domain.GatewayDeclined, result.Status)
+		assert.Equal(t, "card_declined", result.DeclineCode)
+		assert.Empty(t, result.TransactionID)
+	})
+
+	t.Run("insufficient funds", func(t *testing.T) {
+		result := authorize(t, g, TokenInsufficientFunds, rub(100000))
+		assert.Equal(t, domain.GatewayDeclined, result.Status)
+		assert.Equal(t, "insufficient_funds", result.DeclineCode)
+	})
+
+	t.Run("3-D Secure challenge", func(t *testing.T) {
+		result := authorize(t, g, TokenThreeDSecure, rub(100000))
+		assert.Equal(t, domain.GatewayRequiresAction, result.Status)
+		assert.Contains(t, result.ActionURL, result.TransactionID)
+
+		_, err := g.Capture(context.Background(), result.TransactionID, rub(100000))
+		assert.ErrorIs(t, err, domain.ErrInvalidTransaction)
+	})
+
+	t.Run("3-D Secure challenge fails", func(t *testing.T) {
+		result := authorize(t, g, TokenThreeDSecureFail, rub(100000))
+		assert.Equal(t, domain.GatewayRequiresAction, result.Status)
+	})
+
+	t.Run("timeout", func(t *testing.T) {
+		g := NewFakeGateway(FakeConfig{Timeout: 10 * time.Millisecond})
+		_, err := g.Authorize(context.Background(), domain.AuthorizeRequest{Amount: rub(100000), CardToken: TokenTimeout})
+		assert.ErrorIs(t, err, domain.ErrGatewayTimeout)
+	})
+
+	t.Run("caller deadline wins over gateway timeout", func(t *testing.T) {
+		g := NewFakeGateway(FakeConfig{Timeout: time.Hour})
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		defer cancel()
+		_, err := g.Authorize(ctx, domain.AuthorizeRequest{Amount: rub(100000), CardToken: TokenTimeout})
+		assert.ErrorIs(t, err, context.DeadlineExceeded)
+	})
+}
+
+func TestFakeGateway_Authenticate(t *testing.T) {
+	ctx := context.Background()
+	g := NewFakeGateway(FakeConfig{})
+
+	t.Run("passed challenge authorizes the transaction", func(t *testing.T) {
+		challenge := authorize(t, g, TokenThreeDSecure, rub(100000)).TransactionID
+
+		result, err := g.Authenticate(ctx, challenge)
+		require.NoError(t, err)
+		assert.Equal(t, domain.GatewayApproved, result.Status)
+		assert.NotEqual(t, challenge, result.TransactionID)
+
+		_, err = g.Capture(ctx, result.TransactionID, rub(100000))
+		assert.NoError(t, err)
+		_, err = g.Authenticate(ctx, result.TransactionID)
+		assert.ErrorIs(t, err, domain.ErrInvalidTransaction)
+	})
+
+	t.Run("failed challenge", func(t *testing.T) {
+		challenge := authorize(t, g, TokenThreeDSecureFail, rub(100000)).TransactionID
+
+		result, err := g.Authenticate(ctx, challenge)
+		require.NoError(t, err)
+		assert.Equal(t, domain.GatewayDeclined, result.Status)
+		assert.Equal(t, "authentication_failed", result.DeclineCode)
+	})
+
+	t.Run("unknown transaction", func(t *testing.T) {
+		_, err := g.Authenticate(ctx, "missing")
+		assert.ErrorIs(t, err, domain.ErrTransactionNotFound)
+	})
+}
+
+func TestFakeGateway_CaptureVoidRefund(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("capture and refunds", func(t *testing.T) {
+		g := NewFakeGateway(FakeConfig{})
+		id := authorize(t, g, "tok_visa", rub(100000)).TransactionID
+
+		_, err := g.Capture(ctx, id, rub(100001))
+		assert.ErrorIs(t, err, domain.ErrGatewayAmountExceeded)
+		_, err = g.Capture(ctx, id, money.New(100000, "USD"))
+		assert.ErrorIs(t, err, domain.ErrGatewayAmountExceeded)
+
+		result, err := g.Capture(ctx, id, rub(100000))
+		require.NoError(t, err)
+		assert.Equal(t, domain.GatewayApproved, result.Status)
+
+		_, err = g.Refund(ctx, id, rub(100001))
+		assert.ErrorIs(t, err, domain.ErrGatewayAmountExceeded)
+		_, err = g.Refund(ctx, id, rub(60000))
+		assert.NoError(t, err)
+	})
+
+	t.Run("void", func(t *testing.T) {
+		g := NewFakeGateway(FakeConfig{})
+		id := authorize(t, g, "tok_visa", rub(100000)).TransactionID
+
+		result, err := g.Void(ctx, id)
+		require.NoError(t, err)
+		assert.Equal(t, domain.GatewayApproved, result.Status)
+	})
+
+	t.Run("survives restart", func(t *testing.T) {
+		id := authorize(t, NewFakeGateway(FakeConfig{}), "tok_visa", rub(100000)).TransactionID
+
+		restarted := NewFakeGateway(FakeConfig{})
+		_, err := restarted.Capture(ctx, id, rub(100000))
+		require.NoError(t, err)
+		_, err = restarted.Refund(ctx, id, rub(100000))
+		assert.NoError(t, err)
+	})
+
+	t.Run("delayed settlement", func(t *testing.T) {
+		g := NewFakeGateway(FakeConfig{SettlementDelay: 20 * time.Millisecond})
+		id := authorize(t, g, TokenDelayed, rub(100000)).TransactionID
+
+		start := time.Now()
+		_, err := g.Capture(ctx, id, rub(100000))
+		require.NoError(t, err)
+		assert.GreaterOrEqual(t, time.Since(start), 20*time.Millisecond)
+	})
+
+	t.Run("unknown transaction", func(t *testing.T) {
+		g := NewFakeGateway(FakeConfig{})
+		_, err := g.Capture(ctx, "missing", rub(10000))
+		assert.ErrorIs(t, err, domain.ErrTransactionNotFound)
+		_, err = g.Capture(ctx, "fake_card_RUB_abc_123", rub(10000))
+		assert.ErrorIs(t, err, domain.ErrTransactionNotFound)
+		_, err = g.Void(ctx, "missing")
+		assert.ErrorIs(t, err, domain.ErrTransactionNotFound)
+		_, err = g.Refund(ctx, "missing", rub(10000))
+		assert.ErrorIs(t, err, domain.ErrTransactionNotFound)
+	})
+}
@@ -0,0 +1,402 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"hotel-booking-system/internal/payment/domain"
+	"hotel-booking-system/pkg/money"
+)
+
+type PostgresPaymentRepository struct {
+	db *sql.DB
+}
+
+func NewPostgresPaymentRepository(db *sql.DB) *PostgresPaymentRepository {
+	return &PostgresPaymentRepository{db: db}
+}
+
+func (r *PostgresPaymentRepository) CreatePayment(ctx context.Context, payment *domain.Payment) error {
+	query := `INSERT INTO payments (id, booking_id, amount, currency, status, capture_mode, card_token) 
+			  VALUES ($1, $2, $3, $4, $5, $6, NULLIF($7, '')) 
+			  RETURNING created_at, updated_at`
+	return r.db.QueryRowContext(ctx, query,
+		payment.ID, payment.BookingID, payment.Amount, payment.Amount.Currency, payment.Status, payment.CaptureMode,
+		payment.CardToken,
+	).Scan(&payment.CreatedAt, &payment.UpdatedAt)
+}
+
+const paymentSelectColumns = `id, booking_id, amount, currency, status, refunded_amount, 
+			  COALESCE(gateway_transaction_id, ''), COALESCE(failure_reason, ''), authorization_expires_at, 
+			  created_at, updated_at, processed_at, capture_mode, COALESCE(action_url, '')`
+
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+// scanPayment scans the paymentSelectColumns of a row into the payment,
+// followed by any extra columns the query selects.
+func scanPayment(row rowScanner, payment *domain.Payment, extra ...interface{}) error {
+	var amount, currency, refundedAmount string
+	var authorizationExpiresAt, processedAt sql.NullTime
+	if err := row.Scan(append([]interface{}{
+		&payment.ID, &payment.BookingID, &amount, &currency,
+		&payment.Status, &refundedAmount, &payment.GatewayTransactionID, &payment.FailureReason,
+		&authorizationExpiresAt, &payment.CreatedAt, &payment.UpdatedAt, &processedAt,
+		&payment.CaptureMode, &payment.ActionURL,
+	}, extra...)...); err != nil {
+		return err
+	}
+	var err error
+	if payment.Amount, err = money.Parse(amount, currency); err != nil {
+		return err
+	}
+	if payment.RefundedAmount, err = money.Parse(refundedAmount, currency); err != nil {
+		return err
+	}
+	if authorizationExpiresAt.Valid {
+		payment.AuthorizationExpiresAt = &authorizationExpiresAt.Time
+	}
+	if processedAt.Valid {
+		payment.ProcessedAt = &processedAt.Time
+	}
+	return nil
+}
+
+func (r *PostgresPaymentRepository) GetPaymentByID(ctx context.Context, id string) (*domain.Payment, error) {
+	payment := &domain.Payment{}
+	query := `SELECT ` + paymentSelectColumns + ` FROM payments WHERE id = $1`
+	if err := scanPayment(r.db.QueryRowContext(ctx, query, id), payment); err != nil {
+		return nil, err
+	}
+	return payment, nil
+}
+
+func (r *PostgresPaymentRepository) GetPaymentsByBooking(ctx context.Context, bookingID string) ([]domain.Payment, error) {
+	query := `SELECT ` + paymentSelectColumns + ` FROM payments WHERE booking_id = $1 ORDER BY created_at DESC`
+	return r.queryPayments(ctx, query, bookingID)
+}
+
+func (r *PostgresPaymentRepository) GetExpiredAuthorizations(ctx context.Context, limit int) ([]domain.Payment, error) {
+	query := `SELECT ` + paymentSelectColumns + ` FROM payments 
+			  WHERE status = $1 AND authorization_expires_at <= CURRENT_TIMESTAMP 
+			  ORDER BY authorization_expires_at LIMIT $2`
+	return r.queryPayments(ctx, query, domain.PaymentAuthorized, limit)
+}
+
+// ClaimStalePayments picks the payments that have been processing since
+// before the given time, whose charge was cut short, and touches them so that
+// other instances skip them while they are retried.
+func (r *PostgresPaymentRepository) ClaimStalePayments(ctx context.Context, updatedBefore time.Time, limit int) ([]domain.Payment, error) {
+	query := `UPDATE payments SET updated_at = CURRENT_TIMESTAMP 
+			  WHERE id IN (
+				SELECT id FROM payments 
+				WHERE status = $1 AND updated_at <= $2 
+				ORDER BY updated_at LIMIT $3 
+				FOR UPDATE SKIP LOCKED
+			  ) 
+			  RETURNING ` + paymentSelectColumns + `, COALESCE(card_token, '')`
+	rows, err := r.db.QueryContext(ctx, query, domain.PaymentProcessing, updatedBefore, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	payments := []domain.Payment{}
+	for rows.Next() {
+		var payment domain.Payment
+		if err := scanPayment(rows, &payment, &payment.CardToken); err != nil {
+			return nil, err
+		}
+		payments = append(payments, payment)
+	}
+	return payments, rows.Err()
+}
+
+func (r *PostgresPaymentRepository) queryPayments(ctx context.Context, query string, args ...interface{}) ([]domain.Payment, error) {
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	payments := []domain.Payment{}
+	for rows.Next() {
+		var payment domain.Payment
+		if err := scanPayment(rows, &payment); err != nil {
+			return nil, err
+		}
+		payments = append(payments, payment)
+	}
+	return payments, rows.Err()
+}
+
+// UpdatePaymentStatus stores the payment's new status and gateway outcome and
+// queues the delivery, if any, in the same transaction. The card token is no
+// longer needed once the payment has left processing and is dropped.
+func (r *PostgresPaymentRepository) UpdatePaymentStatus(ctx context.Context, payment *domain.Payment, from domain.PaymentStatus, delivery *domain.WebhookDelivery) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	query := `UPDATE payments SET status = $3, gateway_transaction_id = NULLIF($4, ''), failure_reason = NULLIF($5, ''), 
+			  authorization_expires_at = $6, action_url = NULLIF($7, ''), card_token = NULL, 
+			  updated_at = CURRENT_TIMESTAMP, processed_at = CURRENT_TIMESTAMP 
+			  WHERE id = $1 AND status = $2`
+	result, err := tx.ExecContext(ctx, query, payment.ID, from, payment.Status,
+		payment.GatewayTransactionID, payment.FailureReason, payment.AuthorizationExpiresAt, payment.ActionURL)
+	if err != nil {
+		return err
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return domain.ErrStatusChanged
+	}
+
+	if err := insertWebhookDelivery(ctx, tx, delivery); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// CreateRefund locks the payment row so that concurrent refunds cannot
+// together exceed the captured amount; refunds still being processed count
+// against the balance.
+func (r *PostgresPaymentRepository) CreateRefund(ctx context.Context, refund *domain.Refund) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	var amount, currency, reserved string
+	var status domain.PaymentStatus
+	lockQuery := `SELECT booking_id, amount, currency, status, refunded_amount + COALESCE(
+			  (SELECT SUM(amount) FROM refunds WHERE payment_id = payments.id AND status = $2), 0) 
+			  FROM payments WHERE id = $1 FOR UPDATE`
+	if err := tx.QueryRowContext(ctx, lockQuery, refund.PaymentID, domain.RefundProcessing).
+		Scan(&refund.BookingID, &amount, &currency, &status, &reserved); err != nil {
+		return err
+	}
+	if status != domain.PaymentPaid && status != domain.PaymentPartiallyRefunded {
+		return domain.ErrPaymentNotRefundable
+	}
+	paid, err := money.Parse(amount, currency)
+	if err != nil {
+		return err
+	}
+	reservedAmount, err := money.Parse(reserved, currency)
+	if err != nil {
+		return err
+	}
+	total, err := reservedAmount.Add(refund.Amount)
+	if err != nil {
+		return err
+	}
+	if total.Amount > paid.Amount {
+		return domain.ErrRefundExceedsPayment
+	}
+
+	query := `INSERT INTO refunds (id, payment_id, amount, status) VALUES ($1, $2, $3, $4) 
+			  RETURNING created_at`
+	if err := tx.QueryRowContext(ctx, query, refund.ID, refund.PaymentID, refund.Amount, refund.Status).
+		Scan(&refund.CreatedAt); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// CreateBookingRefunds spreads an amount over the captured payments of a
+// booking, from the latest one, and inserts one refund per payment touched.
+// The payment rows stay locked until every refund is inserted, so concurrent
+// refunds of the booking cannot together exceed what was captured; refunds
+// still being processed count against the balance.
+func (r *PostgresPaymentRepository) CreateBookingRefunds(ctx context.Context, bookingID string, amount money.Money) ([]domain.Refund, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	lockQuery := `SELECT id, amount, currency, refunded_amount + COALESCE(
+			  (SELECT SUM(amount) FROM refunds WHERE payment_id = payments.id AND status = $4), 0) 
+			  FROM payments WHERE booking_id = $1 AND status IN ($2, $3) 
+			  ORDER BY created_at DESC FOR UPDATE`
+	rows, err := tx.QueryContext(ctx, lockQuery, bookingID,
+		domain.PaymentPaid, domain.PaymentPartiallyRefunded, domain.RefundProcessing)
+	if err != nil {
+		return nil, err
+	}
+	var refunds []domain.Refund
+	left := amount
+	for rows.Next() {
+		var paymentID, paid, currency, reserved string
+		if err := rows.Scan(&paymentID, &paid, &currency, &reserved); err != nil {
+			rows.Close()
+			return nil, err
+		}
+		if !left.IsPositive() {
+			continue
+		}
+		remaining, err := refundableBalance(paid, reserved, currency)
+		if err != nil {
+			rows.Close()
+			return nil, err
+		}
+		if !remaining.IsPositive() {
+			continue
+		}
+		part := left
+		if remaining.Amount < left.Amount {
+			part = remaining
+		}
+		if left, err = left.Sub(part); err != nil {
+			rows.Close()
+			return nil, err
+		}
+		refunds = append(refunds, domain.Refund{
+			PaymentID: paymentID,
+			BookingID: bookingID,
+			Amount:    part,
+			Status:    domain.RefundProcessing,
+		})
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	if len(refunds) == 0 {
+		return nil, domain.ErrPaymentNotRefundable
+	}
+	if left.IsPositive() {
+		return nil, domain.ErrRefundExceedsPayment
+	}
+
+	query := `INSERT INTO refunds (payment_id, amount, status) VALUES ($1, $2, $3) 
+			  RETURNING id, created_at`
+	for i := range refunds {
+		refund := &refunds[i]
+		if err := tx.QueryRowContext(ctx, query, refund.PaymentID, refund.Amount, refund.Status).
+			Scan(&refund.ID, &refund.CreatedAt); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	return refunds, nil
+}
+
+// refundableBalance is what is left to refund of a payment once its refunds,
+// done or still processing, are taken off.
+func refundableBalance(paid, reserved, currency string) (money.Money, error) {
+	paidAmount, err := money.Parse(paid, currency)
+	if err != nil {
+		return money.Money{}, err
+	}
+	reservedAmount, err := money.Parse(reserved, currency)
+	if err != nil {
+		return money.Money{}, err
+	}
+	return paidAmount.Sub(reservedAmount)
+}
+
+// CompleteRefund applies a processed refund to its payment and queues the
+// refund webhook carrying the resulting refund status of the booking.
+func (r *PostgresPaymentRepository) CompleteRefund(ctx context.Context, refundID string) (*domain.Payment, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	var paymentID, amount string
+	refundQuery := `UPDATE refunds SET status = $2, processed_at = CURRENT_TIMESTAMP 
+			  WHERE id = $1 AND status = $3 RETURNING payment_id, amount`
+	err = tx.QueryRowContext(ctx, refundQuery, refundID, domain.RefundSucceeded, domain.RefundProcessing).
+		Scan(&paymentID, &amount)
+	if err == sql.ErrNoRows {
+		return nil, domain.ErrRefundAlreadyComplete
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	payment := &domain.Payment{}
+	paymentQuery := `UPDATE payments SET refunded_amount = refunded_amount + $2, 
+			  status = CASE WHEN refunded_amount + $2 >= amount THEN $3 ELSE $4 END, 
+			  updated_at = CURRENT_TIMESTAMP 
+			  WHERE id = $1 
+			  RETURNING ` + paymentSelectColumns
+	if err := scanPayment(tx.QueryRowContext(ctx, paymentQuery, paymentID, amount,
+		domain.PaymentRefunded, domain.PaymentPartiallyRefunded,
+	), payment); err != nil {
+		return nil, err
+	}
+	refunded, err := money.Parse(amount, payment.Amount.Currency)
+	if err != nil {
+		return nil, err
+	}
+
+	// The webhook reports the refund state of the whole booking, which is
+	// only refunded once none of its payments has captured money left.
+	status := payment.Status
+	if status == domain.PaymentRefunded {
+		var captured bool
+		capturedQuery := `SELECT EXISTS (SELECT 1 FROM payments WHERE booking_id = $1 AND id <> $2 AND status IN ($3, $4))`
+		if err := tx.QueryRowContext(ctx, capturedQuery, payment.BookingID, payment.ID,
+			domain.PaymentPaid, domain.PaymentPartiallyRefunded,
+		).Scan(&captured); err != nil {
+			return nil, err
+		}
+		if captured {
+			status = domain.PaymentPartiallyRefunded
+		}
+	}
+
+	delivery, err := domain.NewWebhookDelivery(domain.PaymentWebhook{
+		PaymentID:   payment.ID,
+		RefundID:    refundID,
+		BookingID:   payment.BookingID,
+		Status:      string(status),
+		Amount:      refunded,
+		ProcessedAt: time.Now().Format(time.RFC3339),
+	})
+	if err != nil {
+		return nil, err
+	}
+	if err := insertWebhookDelivery(ctx, tx, delivery); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	return payment, nil
+}
+
+func (r *PostgresPaymentRepository) FailRefund(ctx context.Context, refundID, reason string) error {
+	query := `UPDATE refunds SET status = $2, failure_reason = $3, processed_at = CURRENT_TIMESTAMP 
+			  WHERE id = $1 AND status = $4`
+	result, err := r.db.ExecContext(ctx, query, refundID, domain.RefundFailed, reason, domain.RefundProcessing)
+	if err != nil {
+		return err
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return domain.ErrRefundAlreadyComplete
+	}
+	return nil
+}
@@ -0,0 +1,575 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"hotel-booking-system/internal/payment/domain"
+	"hotel-booking-system/pkg/money"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+)
+
+var paymentColumns = []string{
+	"id", "booking_id", "amount", "currency", "status", "refunded_amount",
+	"gateway_transaction_id", "failure_reason", "authorization_expires_at", "created_at", "updated_at", "processed_at",
+	"capture_mode", "action_url",
+}
+
+func setupMockDB(t *testing.T) (*sql.DB, sqlmock.Sqlmock) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("failed to create mock db: %v", err)
+	}
+	return db, mock
+}
+
+func TestCreatePayment(t *testing.T) {
+	db, mock := setupMockDB(t)
+	defer db.Close()
+
+	repo := NewPostgresPaymentRepository(db)
+	payment := &domain.Payment{
+		ID:          "payment-123",
+		BookingID:   "booking-123",
+		Amount:      money.New(100000, "RUB"),
+		Status:      domain.PaymentProcessing,
+		CaptureMode: domain.CaptureManual,
+		CardToken:   "tok_visa",
+	}
+	now := time.Now()
+
+	mock.ExpectQuery(`INSERT INTO payments`).
+		WithArgs("payment-123", "booking-123", payment.Amount, "RUB", domain.PaymentProcessing, domain.CaptureManual, "tok_visa").
+		WillReturnRows(sqlmock.NewRows([]string{"created_at", "updated_at"}).AddRow(now, now))
+
+	err := repo.CreatePayment(context.Background(), payment)
+	assert.NoError(t, err)
+	assert.Equal(t, now, payment.CreatedAt)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetPaymentByID(t *testing.T) {
+	t.Run("payment waiting for 3-D Secure", func(t *testing.T) {
+		db, mock := setupMockDB(t)
+		defer db.Close()
+
+		repo := NewPostgresPaymentRepository(db)
+		now := time.Now()
+
+		mock.ExpectQuery(`SELECT .* FROM payments WHERE id = \$1`).
+			WithArgs("payment-123").
+			WillReturnRows(sqlmock.NewRows(paymentColumns).
+				AddRow("payment-123", "booking-123", "1000.00", "RUB", "requires_action", "0.00", "fake_txn", "", nil, now, now, now, "manual", "https://fake-gateway.local/3ds/fake_txn"))
+
+		payment, err := repo.GetPaymentByID(context.Background(), "payment-123")
+		assert.NoError(t, err)
+		assert.Equal(t, domain.PaymentRequiresAction, payment.Status)
+		assert.Equal(t, domain.CaptureManual, payment.CaptureMode)
+		assert.Equal(t, "https://fake-gateway.local/3ds/fake_txn", payment.ActionURL)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("processed payment", func(t *testing.T) {
+		db, mock := setupMockDB(t)
+		defer db.Close()
+
+		repo := NewPostgresPaymentRepository(db)
+		now := time.Now()
+
+		mock.ExpectQuery(`SELECT .* FROM payments WHERE id = \$1`).
+			WithArgs("payment-123").
+			WillReturnRows(sqlmock.NewRows(paymentColumns).
+				AddRow("payment-123", "booking-123", "1000.00", "RUB", "paid", "0.00", "fake_txn", "", nil, now, now, now, "automatic", ""))
+
+		payment, err := repo.GetPaymentByID(context.Background(), "payment-123")
+		assert.NoError(t, err)
+		assert.Equal(t, domain.PaymentPaid, payment.Status)
+		assert.Equal(t, "fake_txn", payment.GatewayTransactionID)
+		if assert.NotNil(t, payment.ProcessedAt) {
+			assert.Equal(t, now, *payment.ProcessedAt)
+		}
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("processing payment", func(t *testing.T) {
+		db, mock := setupMockDB(t)
+		defer db.Close()
+
+		repo := NewPostgresPaymentRepository(db)
+		now := time.Now()
+
+		mock.ExpectQuery(`SELECT .* FROM payments WHERE id = \$1`).
+			WithArgs("payment-123").
+			WillReturnRows(sqlmock.NewRows(paymentColumns).
+				AddRow("payment-123", "booking-123", "1000.00", "RUB", "processing", "0.00", "", "", nil, now, now, nil, "automatic", ""))
+
+		payment, err := repo.GetPaymentByID(context.Background(), "payment-123")
+		assert.NoError(t, err)
+		assert.Nil(t, payment.ProcessedAt)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		db, mock := setupMockDB(t)
+		defer db.Close()
+
+		repo := NewPostgresPaymentRepository(db)
+
+		mock.ExpectQuery(`SELECT .* FROM payments WHERE id = \$1`).
+			WithArgs("missing").
+			WillReturnError(sql.ErrNoRows)
+
+		payment, err := repo.GetPaymentByID(context.Background(), "missing")
+		assert.ErrorIs(t, err, sql.ErrNoRows)
+		assert.Nil(t, payment)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestGetPaymentsByBooking(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		db, mock := setupMockDB(t)
+		defer db.Close()
+
+		repo := NewPostgresPaymentRepository(db)
+		now := time.Now()
+
+		mock.ExpectQuery(`SELECT .* FROM payments WHERE booking_id = \$1`).
+			WithArgs("booking-123").
+			WillReturnRows(sqlmock.NewRows(paymentColumns).
+				AddRow("payment-2", "booking-123", "1000.00", "RUB", "paid", "0.00", "fake_txn", "", nil, now, now, now, "automatic", "").
+				AddRow("payment-1", "booking-123", "1000.00", "RUB", "failed", "0.00", "", "card_declined", nil, now, now, now, "automatic", ""))
+
+		payments, err := repo.GetPaymentsByBooking(context.Background(), "booking-123")
+		assert.NoError(t, err)
+		assert.Len(t, payments, 2)
+		assert.Equal(t, domain.PaymentFailed, payments[1].Status)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("empty result", func(t *testing.T) {
+		db, mock := setupMockDB(t)
+		defer db.Close()
+
+		repo := NewPostgresPaymentRepository(db)
+
+		mock.ExpectQuery(`SELECT .* FROM payments WHERE booking_id = \$1`).
+			WithArgs("booking-123").
+			WillReturnRows(sqlmock.NewRows(paymentColumns))
+
+		payments, err := repo.GetPaymentsByBooking(context.Background(), "booking-123")
+		assert.NoError(t, err)
+		assert.NotNil(t, payments)
+		assert.Empty(t, payments)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("database error", func(t *testing.T) {
+		db, mock := setupMockDB(t)
+		defer db.Close()
+
+		repo := NewPostgresPaymentRepository(db)
+
+		mock.ExpectQuery(`SELECT .* FROM payments`).
+			WillReturnError(errors.New("query error"))
+
+		payments, err := repo.GetPaymentsByBooking(context.Background(), "booking-123")
+		assert.Error(t, err)
+		assert.Nil(t, payments)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestGetExpiredAuthorizations(t *testing.T) {
+	db, mock := setupMockDB(t)
+	defer db.Close()
+
+	repo := NewPostgresPaymentRepository(db)
+	now := time.Now()
+	expiredAt := now.Add(-time.Minute)
+
+	mock.ExpectQuery(`SELECT .* FROM payments WHERE status = \$1 AND authorization_expires_at <= CURRENT_TIMESTAMP`).
+		WithArgs(domain.PaymentAuthorized, 100).
+		WillReturnRows(sqlmock.NewRows(paymentColumns).
+			AddRow("payment-123", "booking-123", "1000.00", "RUB", "authorized", "0.00", "fake_txn", "", expiredAt, now, now, now, "automatic", ""))
+
+	payments, err := repo.GetExpiredAuthorizations(context.Background(), 100)
+	assert.NoError(t, err)
+	if assert.Len(t, payments, 1) && assert.NotNil(t, payments[0].AuthorizationExpiresAt) {
+		assert.Equal(t, expiredAt, *payments[0].AuthorizationExpiresAt)
+	}
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestClaimStalePayments(t *testing.T) {
+	db, mock := setupMockDB(t)
+	defer db.Close()
+
+	repo := NewPostgresPaymentRepository(db)
+	now := time.Now()
+	updatedBefore := now.Add(-5 * time.Minute)
+
+	mock.ExpectQuery(`UPDATE payments SET updated_at = CURRENT_TIMESTAMP\s+WHERE id IN \(\s+SELECT id FROM payments\s+WHERE status = \$1 AND updated_at <= \$2\s+ORDER BY updated_at LIMIT \$3\s+FOR UPDATE SKIP LOCKED`).
+		WithArgs(domain.PaymentProcessing, updatedBefore, 50).
+		WillReturnRows(sqlmock.NewRows(append(paymentColumns, "card_token")).
+			AddRow("payment-123", "booking-123", "1000.00", "RUB", "processing", "0.00", "", "", nil, now, now, nil, "automatic", "", "tok_visa"))
+
+	payments, err := repo.ClaimStalePayments(context.Background(), updatedBefore, 50)
+	assert.NoError(t, err)
+	if assert.Len(t, payments, 1) {
+		assert.Equal(t, domain.PaymentProcessing, payments[0].Status)
+		assert.Equal(t, "tok_visa", payments[0].CardToken)
+	}
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestUpdatePaymentStatus(t *testing.T) {
+	t.Run("success queues webhook", func(t *testing.T) {
+		db, mock := setupMockDB(t)
+		defer db.Close()
+
+		repo := NewPostgresPaymentRepository(db)
+		delivery := &domain.WebhookDelivery{
+			PaymentID: "payment-123",
+			BookingID: "booking-123",
+			Payload:   []byte(`{"payment_id":"payment-123","booking_id":"booking-123","status":"paid"}`),
+		}
+		now := time.Now()
+
+		mock.ExpectBegin()
+		mock.ExpectExec(`UPDATE payments SET status = \$3, gateway_transaction_id = NULLIF\(\$4, ''\), failure_reason = NULLIF\(\$5, ''\), .* WHERE id = \$1 AND status = \$2`).
+			WithArgs("payment-123", domain.PaymentProcessing, domain.PaymentPaid, "fake_txn", "", nil, "").
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectQuery(`INSERT INTO webhook_deliveries`).
+			WithArgs("payment-123", "booking-123", string(delivery.Payload)).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "next_attempt_at", "created_at"}).AddRow(int64(1), now, now))
+		mock.ExpectCommit()
+
+		payment := &domain.Payment{ID: "payment-123", Status: domain.PaymentPaid, GatewayTransactionID: "fake_txn"}
+		err := repo.UpdatePaymentStatus(context.Background(), payment, domain.PaymentProcessing, delivery)
+		assert.NoError(t, err)
+		assert.Equal(t, int64(1), delivery.ID)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("status changed", func(t *testing.T) {
+		db, mock := setupMockDB(t)
+		defer db.Close()
+
+		repo := NewPostgresPaymentRepository(db)
+
+		mock.ExpectBegin()
+		mock.ExpectExec(`UPDATE payments`).
+			WithArgs("payment-123", domain.PaymentProcessing, domain.PaymentFailed, "", "card_declined", nil, "").
+			WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectRollback()
+
+		payment := &domain.Payment{ID: "payment-123", Status: domain.PaymentFailed, FailureReason: "card_declined"}
+		err := repo.UpdatePaymentStatus(context.Background(), payment, domain.PaymentProcessing,
+			&domain.WebhookDelivery{PaymentID: "payment-123"})
+		assert.ErrorIs(t, err, domain.ErrStatusChanged)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestCreateRefund(t *testing.T) {
+	lockColumns := []string{"booking_id", "amount", "currency", "status", "reserved"}
+	newRefund := func(amount money.Money) *domain.Refund {
+		return &domain.Refund{ID: "refund-123", PaymentID: "payment-123", Amount: amount, Status: domain.RefundProcessing}
+	}
+
+	t.Run("partial refund", func(t *testing.T) {
+		db, mock := setupMockDB(t)
+		defer db.Close()
+
+		repo := NewPostgresPaymentRepository(db)
+		refund := newRefund(money.New(30000, "RUB"))
+		now := time.Now()
+
+		mock.ExpectBegin()
+		mock.ExpectQuery(`SELECT booking_id, amount, currency, status, refunded_amount .* FROM payments WHERE id = \$1 FOR UPDATE`).
+			WithArgs("payment-123", domain.RefundProcessing).
+			WillReturnRows(sqlmock.NewRows(lockColumns).AddRow("booking-123", "1000.00", "RUB", "partially_refunded", "700.00"))
+		mock.ExpectQuery(`INSERT INTO refunds`).
+			WithArgs("refund-123", "payment-123", refund.Amount, domain.RefundProcessing).
+			WillReturnRows(sqlmock.NewRows([]string{"created_at"}).AddRow(now))
+		mock.ExpectCommit()
+
+		err := repo.CreateRefund(context.Background(), refund)
+		assert.NoError(t, err)
+		assert.Equal(t, "booking-123", refund.BookingID)
+		assert.Equal(t, now, refund.CreatedAt)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("exceeds refundable balance", func(t *testing.T) {
+		db, mock := setupMockDB(t)
+		defer db.Close()
+
+		repo := NewPostgresPaymentRepository(db)
+
+		mock.ExpectBegin()
+		mock.ExpectQuery(`SELECT booking_id, amount, currency, status, refunded_amount`).
+			WithArgs("payment-123", domain.RefundProcessing).
+			WillReturnRows(sqlmock.NewRows(lockColumns).AddRow("booking-123", "1000.00", "RUB", "paid", "700.00"))
+		mock.ExpectRollback()
+
+		err := repo.CreateRefund(context.Background(), newRefund(money.New(30001, "RUB")))
+		assert.ErrorIs(t, err, domain.ErrRefundExceedsPayment)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("currency mismatch", func(t *testing.T) {
+		db, mock := setupMockDB(t)
+		defer db.Close()
+
+		repo := NewPostgresPaymentRepository(db)
+
+		mock.ExpectBegin()
+		mock.ExpectQuery(`SELECT booking_id, amount, currency, status, refunded_amount`).
+			WithArgs("payment-123", domain.RefundProcessing).
+			WillReturnRows(sqlmock.NewRows(lockColumns).AddRow("booking-123", "1000.00", "RUB", "paid", "0.00"))
+		mock.ExpectRollback()
+
+		err := repo.CreateRefund(context.Background(), newRefund(money.New(10000, "USD")))
+		assert.ErrorIs(t, err, money.ErrCurrencyMismatch)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("payment not captured", func(t *testing.T) {
+		db, mock := setupMockDB(t)
+		defer db.Close()
+
+		repo := NewPostgresPaymentRepository(db)
+
+		mock.ExpectBegin()
+		mock.ExpectQuery(`SELECT booking_id, amount, currency, status, refunded_amount`).
+			WithArgs("payment-123", domain.RefundProcessing).
+			WillReturnRows(sqlmock.NewRows(lockColumns).AddRow("booking-123", "1000.00", "RUB", "processing", "0.00"))
+		mock.ExpectRollback()
+
+		err := repo.CreateRefund(context.Background(), newRefund(money.New(10000, "RUB")))
+		assert.ErrorIs(t, err, domain.ErrPaymentNotRefundable)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("payment not found", func(t *testing.T) {
+		db, mock := setupMockDB(t)
+		defer db.Close()
+
+		repo := NewPostgresPaymentRepository(db)
+
+		mock.ExpectBegin()
+		mock.ExpectQuery(`SELECT booking_id, amount, currency, status, refunded_amount`).
+			WillReturnError(sql.ErrNoRows)
+		mock.ExpectRollback()
+
+		err := repo.CreateRefund(context.Background(), newRefund(money.New(10000, "RUB")))
+		assert.ErrorIs(t, err, sql.ErrNoRows)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestCreateBookingRefunds(t *testing.T) {
+	lockColumns := []string{"id", "amount", "currency", "reserved"}
+	lockQuery := `SELECT id, amount, currency, refunded_amount .* FROM payments WHERE booking_id = \$1 AND status IN \(\$2, \$3\) ORDER BY created_at DESC FOR UPDATE`
+
+	t.Run("spreads amount from the latest payment", func(t *testing.T) {
+		db, mock := setupMockDB(t)
+		defer db.Close()
+
+		repo := NewPostgresPaymentRepository(db)
+		now := time.Now()
+
+		mock.ExpectBegin()
+		mock.ExpectQuery(lockQuery).
+			WithArgs("booking-123", domain.PaymentPaid, domain.PaymentPartiallyRefunded, domain.RefundProcessing).
+			WillReturnRows(sqlmock.NewRows(lockColumns).
+				AddRow("payment-3", "100.00", "RUB", "100.00").
+				AddRow("payment-2", "200.00", "RUB", "0.00").
+				AddRow("payment-1", "1000.00", "RUB", "300.00"))
+		mock.ExpectQuery(`INSERT INTO refunds`).
+			WithArgs("payment-2", money.New(20000, "RUB"), domain.RefundProcessing).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "created_at"}).AddRow("refund-2", now))
+		mock.ExpectQuery(`INSERT INTO refunds`).
+			WithArgs("payment-1", money.New(50000, "RUB"), domain.RefundProcessing).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "created_at"}).AddRow("refund-1", now))
+		mock.ExpectCommit()
+
+		refunds, err := repo.CreateBookingRefunds(context.Background(), "booking-123", money.New(70000, "RUB"))
+		assert.NoError(t, err)
+		assert.Equal(t, []domain.Refund{
+			{ID: "refund-2", PaymentID: "payment-2", BookingID: "booking-123", Amount: money.New(20000, "RUB"), Status: domain.RefundProcessing, CreatedAt: now},
+			{ID: "refund-1", PaymentID: "payment-1", BookingID: "booking-123", Amount: money.New(50000, "RUB"), Status: domain.RefundProcessing, CreatedAt: now},
+		}, refunds)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("exceeds refundable balance", func(t *testing.T) {
+		db, mock := setupMockDB(t)
+		defer db.Close()
+
+		repo := NewPostgresPaymentRepository(db)
+
+		mock.ExpectBegin()
+		mock.ExpectQuery(lockQuery).
+			WillReturnRows(sqlmock.NewRows(lockColumns).
+				AddRow("payment-2", "200.00", "RUB", "0.00").
+				AddRow("payment-1", "1000.00", "RUB", "900.00"))
+		mock.ExpectRollback()
+
+		refunds, err := repo.CreateBookingRefunds(context.Background(), "booking-123", money.New(30001, "RUB"))
+		assert.ErrorIs(t, err, domain.ErrRefundExceedsPayment)
+		assert.Nil(t, refunds)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("nothing left to refund", func(t *testing.T) {
+		db, mock := setupMockDB(t)
+		defer db.Close()
+
+		repo := NewPostgresPaymentRepository(db)
+
+		mock.ExpectBegin()
+		mock.ExpectQuery(lockQuery).
+			WillReturnRows(sqlmock.NewRows(lockColumns).AddRow("payment-1", "1000.00", "RUB", "1000.00"))
+		mock.ExpectRollback()
+
+		refunds, err := repo.CreateBookingRefunds(context.Background(), "booking-123", money.New(10000, "RUB"))
+		assert.ErrorIs(t, err, domain.ErrPaymentNotRefundable)
+		assert.Nil(t, refunds)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+// refundWebhook matches the JSON payload of a queued refund webhook.
+type refundWebhook struct {
+	refundID string
+	status   string
+	amount   money.Money
+}
+
+func (m refundWebhook) Match(v driver.Value) bool {
+	payload, ok := v.(string)
+	if !ok {
+		return false
+	}
+	var webhook domain.PaymentWebhook
+	if err := json.Unmarshal([]byte(payload), &webhook); err != nil {
+		return false
+	}
+	return webhook.RefundID == m.refundID && webhook.Status == m.status && webhook.Amount == m.amount
+}
+
+func TestFailRefund(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		db, mock := setupMockDB(t)
+		defer db.Close()
+
+		repo := NewPostgresPaymentRepository(db)
+
+		mock.ExpectExec(`UPDATE refunds SET status = \$2, failure_reason = \$3, processed_at = CURRENT_TIMESTAMP WHERE id = \$1 AND status = \$4`).
+			WithArgs("refund-123", domain.RefundFailed, "card_declined", domain.RefundProcessing).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+
+		assert.NoError(t, repo.FailRefund(context.Background(), "refund-123", "card_declined"))
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("already processed", func(t *testing.T) {
+		db, mock := setupMockDB(t)
+		defer db.Close()
+
+		repo := NewPostgresPaymentRepository(db)
+
+		mock.ExpectExec(`UPDATE refunds`).
+			WillReturnResult(sqlmock.NewResult(0, 0))
+
+		err := repo.FailRefund(context.Background(), "refund-123", "card_declined")
+		assert.ErrorIs(t, err, domain.ErrRefundAlreadyComplete)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestCompleteRefund(t *testing.T) {
+	t.Run("updates running refunded amount", func(t *testing.T) {
+		db, mock := setupMockDB(t)
+		defer db.Close()
+
+		repo := NewPostgresPaymentRepository(db)
+		now := time.Now()
+
+		mock.ExpectBegin()
+		mock.ExpectQuery(`UPDATE refunds SET status = \$2, processed_at = CURRENT_TIMESTAMP WHERE id = \$1 AND status = \$3`).
+			WithArgs("refund-123", domain.RefundSucceeded, domain.RefundProcessing).
+			WillReturnRows(sqlmock.NewRows([]string{"payment_id", "amount"}).AddRow("payment-123", "300.00"))
+		mock.ExpectQuery(`UPDATE payments SET refunded_amount = refunded_amount \+ \$2`).
+			WithArgs("payment-123", "300.00", domain.PaymentRefunded, domain.PaymentPartiallyRefunded).
+			WillReturnRows(sqlmock.NewRows(paymentColumns).
+				AddRow("payment-123", "booking-123", "1000.00", "RUB", "partially_refunded", "300.00", "fake_txn", "", nil, now, now, now, "automatic", ""))
+		mock.ExpectQuery(`INSERT INTO webhook_deliveries`).
+			WithArgs("payment-123", "booking-123", refundWebhook{refundID: "refund-123", status: "partially_refunded", amount: money.New(30000, "RUB")}).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "next_attempt_at", "created_at"}).AddRow(int64(1), now, now))
+		mock.ExpectCommit()
+
+		payment, err := repo.CompleteRefund(context.Background(), "refund-123")
+		assert.NoError(t, err)
+		assert.Equal(t, domain.PaymentPartiallyRefunded, payment.Status)
+		assert.Equal(t, money.New(30000, "RUB"), payment.RefundedAmount)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("booking with other captured payments stays partially refunded", func(t *testing.T) {
+		db, mock := setupMockDB(t)
+		defer db.Close()
+
+		repo := NewPostgresPaymentRepository(db)
+		now := time.Now()
+
+		mock.ExpectBegin()
+		mock.ExpectQuery(`UPDATE refunds SET status = \$2`).
+			WithArgs("refund-123", domain.RefundSucceeded, domain.RefundProcessing).
+			WillReturnRows(sqlmock.NewRows([]string{"payment_id", "amount"}).AddRow("payment-456", "200.00"))
+		mock.ExpectQuery(`UPDATE payments SET refunded_amount = refunded_amount \+ \$2`).
+			WithArgs("payment-456", "200.00", domain.PaymentRefunded, domain.PaymentPartiallyRefunded).
+			WillReturnRows(sqlmock.NewRows(paymentColumns).
+				AddRow("payment-456", "booking-123", "200.00", "RUB", "refunded", "200.00", "fake_txn", "", nil, now, now, now, "automatic", ""))
+		mock.ExpectQuery(`SELECT EXISTS \(SELECT 1 FROM payments WHERE booking_id = \$1 AND id <> \$2`).
+			WithArgs("booking-123", "payment-456", domain.PaymentPaid, domain.PaymentPartiallyRefunded).
+			WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(true))
+		mock.ExpectQuery(`INSERT INTO webhook_deliveries`).
+			WithArgs("payment-456", "booking-123", refundWebhook{refundID: "refund-123", status: "partially_refunded", amount: money.New(20000, "RUB")}).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "next_attempt_at", "created_at"}).AddRow(int64(1), now, now))
+		mock.ExpectCommit()
+
+		payment, err := repo.CompleteRefund(context.Background(), "refund-123")
+		assert.NoError(t, err)
+		assert.Equal(t, domain.PaymentRefunded, payment.Status)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("already processed", func(t *testing.T) {
+		db, mock := setupMockDB(t)
+		defer db.Close()
+
+		repo := NewPostgresPaymentRepository(db)
+
+		mock.ExpectBegin()
+		mock.ExpectQuery(`UPDATE refunds`).
+			WithArgs("refund-123", domain.RefundSucceeded, domain.RefundProcessing).
+			WillReturnError(sql.ErrNoRows)
+		mock.ExpectRollback()
+
+		payment, err := repo.CompleteRefund(context.Background(), "refund-123")
+		assert.ErrorIs(t, err, domain.ErrRefundAlreadyComplete)
+		assert.Nil(t, payment)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
@@ -0,0 +1,138 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"hotel-booking-system/internal/payment/domain"
+)
+
+type PostgresWebhookRepository struct {
+	db *sql.DB
+}
+
+func NewPostgresWebhookRepository(db *sql.DB) *PostgresWebhookRepository {
+	return &PostgresWebhookRepository{db: db}
+}
+
+// ClaimDueDeliveries picks the deliveries that are due and pushes their next
+// attempt claimFor into the future, so that other dispatchers skip them while
+// they are being sent. Only the oldest undelivered delivery of each booking is
+// picked, so a booking's webhooks go out in the order they were queued.
+func (r *PostgresWebhookRepository) ClaimDueDeliveries(ctx context.Context, limit int, claimFor time.Duration) ([]domain.WebhookDelivery, error) {
+	query := `UPDATE webhook_deliveries SET next_attempt_at = CURRENT_TIMESTAMP + $2 * INTERVAL '1 second' 
+			  WHERE id IN (
+				SELECT id FROM webhook_deliveries due 
+				WHERE delivered_at IS NULL AND next_attempt_at <= CURRENT_TIMESTAMP 
+				AND NOT EXISTS (
+					SELECT 1 FROM webhook_deliveries earlier 
+					WHERE earlier.booking_id = due.booking_id AND earlier.delivered_at IS NULL AND earlier.id < due.id
+				) 
+				ORDER BY next_attempt_at, id LIMIT $1 
+				FOR UPDATE SKIP LOCKED
+			  ) 
+			  RETURNING id, payment_id, booking_id, payload, attempts, COALESCE(last_error, ''), next_attempt_at, created_at`
+	rows, err := r.db.QueryContext(ctx, query, limit, claimFor.Seconds())
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var deliveries []domain.WebhookDelivery
+	for rows.Next() {
+		var delivery domain.WebhookDelivery
+		if err := rows.Scan(
+			&delivery.ID, &delivery.PaymentID, &delivery.BookingID, &delivery.Payload,
+			&delivery.Attempts, &delivery.LastError, &delivery.NextAttemptAt, &delivery.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		deliveries = append(deliveries, delivery)
+	}
+	return deliveries, rows.Err()
+}
+
+func (r *PostgresWebhookRepository) MarkDelivered(ctx context.Context, id int64) error {
+	query := `UPDATE webhook_deliveries SET delivered_at = CURRENT_TIMESTAMP, attempts = attempts + 1, last_error = NULL 
+			  WHERE id = $1`
+	_, err := r.db.ExecContext(ctx, query, id)
+	return err
+}
+
+func (r *PostgresWebhookRepository) ScheduleRetry(ctx context.Context, id int64, reason string, nextAttemptAt time.Time) error {
+	query := `UPDATE webhook_deliveries SET attempts = attempts + 1, last_error = $2, next_attempt_at = $3 
+			  WHERE id = $1`
+	_, err := r.db.ExecContext(ctx, query, id, reason, nextAttemptAt)
+	return err
+}
+
+func (r *PostgresWebhookRepository) MoveToDeadLetter(ctx context.Context, id int64, reason string) error {
+	query := `WITH failed AS (
+				DELETE FROM webhook_deliveries WHERE id = $1 AND delivered_at IS NULL 
+				RETURNING payment_id, booking_id, payload, attempts, created_at
+			  ) 
+			  INSERT INTO webhook_dead_letters (payment_id, booking_id, payload, attempts, last_error, created_at) 
+			  SELECT payment_id, booking_id, payload, attempts + 1, $2, created_at FROM failed`
+	_, err := r.db.ExecContext(ctx, query, id, reason)
+	return err
+}
+
+func (r *PostgresWebhookRepository) GetDeadLetters(ctx context.Context, limit int) ([]domain.DeadLetter, error) {
+	query := `SELECT id, payment_id, booking_id, payload, attempts, last_error, created_at, failed_at 
+			  FROM webhook_dead_letters ORDER BY failed_at DESC LIMIT $1`
+	rows, err := r.db.QueryContext(ctx, query, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	deadLetters := []domain.DeadLetter{}
+	for rows.Next() {
+		var deadLetter domain.DeadLetter
+		if err := rows.Scan(
+			&deadLetter.ID, &deadLetter.PaymentID, &deadLetter.BookingID, &deadLetter.Payload,
+			&deadLetter.Attempts, &deadLetter.LastError, &deadLetter.CreatedAt, &deadLetter.FailedAt,
+		); err != nil {
+			return nil, err
+		}
+		deadLetters = append(deadLetters, deadLetter)
+	}
+	return deadLetters, rows.Err()
+}
+
+// Redeliver moves a dead letter back into the queue with a fresh attempt
+// budget. It returns sql.ErrNoRows if the dead letter does not exist.
+func (r *PostgresWebhookRepository) Redeliver(ctx context.Context, deadLetterID int64) (*domain.WebhookDelivery, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	delivery := &domain.WebhookDelivery{}
+	deleteQuery := `DELETE FROM webhook_dead_letters WHERE id = $1 RETURNING payment_id, booking_id, payload`
+	if err := tx.QueryRowContext(ctx, deleteQuery, deadLetterID).
+		Scan(&delivery.PaymentID, &delivery.BookingID, &delivery.Payload); err != nil {
+		return nil, err
+	}
+
+	if err := insertWebhookDelivery(ctx, tx, delivery); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	return delivery, nil
+}
+
+func insertWebhookDelivery(ctx context.Context, tx *sql.Tx, delivery *domain.WebhookDelivery) error {
+	if delivery == nil {
+		return nil
+	}
+	query := `INSERT INTO webhook_deliveries (payment_id, booking_id, payload) VALUES ($1, $2, $3) 
+			  RETURNING id, next_attempt_at, created_at`
+	return tx.QueryRowContext(ctx, query, delivery.PaymentID, delivery.BookingID, string(delivery.Payload)).
+		Scan(&delivery.ID, &delivery.NextAttemptAt, &delivery.CreatedAt)
+}
@@ -0,0 +1,138 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestClaimDueDeliveries(t *testing.T) {
+	db, mock := setupMockDB(t)
+	defer db.Close()
+
+	repo := NewPostgresWebhookRepository(db)
+	now := time.Now()
+
+	mock.ExpectQuery(`UPDATE webhook_deliveries SET next_attempt_at = .* WHERE id IN \( SELECT id FROM webhook_deliveries due WHERE delivered_at IS NULL AND next_attempt_at <= CURRENT_TIMESTAMP AND NOT EXISTS \(.*earlier.booking_id = due.booking_id.*earlier.id < due.id.*FOR UPDATE SKIP LOCKED`).
+		WithArgs(10, float64(300)).
+		WillReturnRows(sqlmock.NewRows([]string{
+			"id", "payment_id", "booking_id", "payload", "attempts", "last_error", "next_attempt_at", "created_at",
+		}).AddRow(int64(1), "payment-123", "booking-123", []byte(`{"status":"paid"}`), 2, "webhook returned status 503", now, now))
+
+	deliveries, err := repo.ClaimDueDeliveries(context.Background(), 10, 5*time.Minute)
+	assert.NoError(t, err)
+	assert.Len(t, deliveries, 1)
+	assert.Equal(t, 2, deliveries[0].Attempts)
+	assert.JSONEq(t, `{"status":"paid"}`, string(deliveries[0].Payload))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestMarkDelivered(t *testing.T) {
+	db, mock := setupMockDB(t)
+	defer db.Close()
+
+	repo := NewPostgresWebhookRepository(db)
+
+	mock.ExpectExec(`UPDATE webhook_deliveries SET delivered_at = CURRENT_TIMESTAMP, attempts = attempts \+ 1`).
+		WithArgs(int64(1)).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	assert.NoError(t, repo.MarkDelivered(context.Background(), 1))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestScheduleRetry(t *testing.T) {
+	db, mock := setupMockDB(t)
+	defer db.Close()
+
+	repo := NewPostgresWebhookRepository(db)
+	next := time.Now().Add(time.Minute)
+
+	mock.ExpectExec(`UPDATE webhook_deliveries SET attempts = attempts \+ 1, last_error = \$2, next_attempt_at = \$3`).
+		WithArgs(int64(1), "webhook returned status 503", next).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	assert.NoError(t, repo.ScheduleRetry(context.Background(), 1, "webhook returned status 503", next))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestMoveToDeadLetter(t *testing.T) {
+	db, mock := setupMockDB(t)
+	defer db.Close()
+
+	repo := NewPostgresWebhookRepository(db)
+
+	mock.ExpectExec(`DELETE FROM webhook_deliveries WHERE id = \$1 .* INSERT INTO webhook_dead_letters`).
+		WithArgs(int64(1), "webhook returned status 503").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	assert.NoError(t, repo.MoveToDeadLetter(context.Background(), 1, "webhook returned status 503"))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetDeadLetters(t *testing.T) {
+	db, mock := setupMockDB(t)
+	defer db.Close()
+
+	repo := NewPostgresWebhookRepository(db)
+
+	mock.ExpectQuery(`SELECT .* FROM webhook_dead_letters ORDER BY failed_at DESC LIMIT \$1`).
+		WithArgs(100).
+		WillReturnRows(sqlmock.NewRows([]string{
+			"id", "payment_id", "booking_id", "payload", "attempts", "last_error", "created_at", "failed_at",
+		}))
+
+	deadLetters, err := repo.GetDeadLetters(context.Background(), 100)
+	assert.NoError(t, err)
+	assert.NotNil(t, deadLetters)
+	assert.Empty(t, deadLetters)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestRedeliver(t *testing.T) {
+	t.Run("requeues dead letter", func(t *testing.T) {
+		db, mock := setupMockDB(t)
+		defer db.Close()
+
+		repo := NewPostgresWebhookRepository(db)
+		now := time.Now()
+
+		mock.ExpectBegin()
+		mock.ExpectQuery(`DELETE FROM webhook_dead_letters WHERE id = \$1 RETURNING payment_id, booking_id, payload`).
+			WithArgs(int64(7)).
+			WillReturnRows(sqlmock.NewRows([]string{"payment_id", "booking_id", "payload"}).
+				AddRow("payment-123", "booking-123", []byte(`{"status":"paid"}`)))
+		mock.ExpectQuery(`INSERT INTO webhook_deliveries`).
+			WithArgs("payment-123", "booking-123", `{"status":"paid"}`).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "next_attempt_at", "created_at"}).AddRow(int64(42), now, now))
+		mock.ExpectCommit()
+
+		delivery, err := repo.Redeliver(context.Background(), 7)
+		assert.NoError(t, err)
+		assert.Equal(t, int64(42), delivery.ID)
+		assert.Equal(t, 0, delivery.Attempts)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		db, mock := setupMockDB(t)
+		defer db.Close()
+
+		repo := NewPostgresWebhookRepository(db)
+
+		mock.ExpectBegin()
+		mock.ExpectQuery(`DELETE FROM webhook_dead_letters`).
+			WithArgs(int64(7)).
+			WillReturnError(sql.ErrNoRows)
+		mock.ExpectRollback()
+
+		delivery, err := repo.Redeliver(context.Background(), 7)
+		assert.ErrorIs(t, err, sql.ErrNoRows)
+		assert.Nil(t, delivery)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
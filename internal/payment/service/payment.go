@@ -1,11 +1,8 @@
 package service
 
 import (
-	"bytes"
 	"context"
-	"encoding/json"
-	"fmt"
-	"net/http"
+	"errors"
 	"time"
 
 	"hotel-booking-system/internal/payment/domain"
@@ -14,83 +11,419 @@ import (
 	"github.com/google/uuid"
 )
 
+// gatewayTimeout bounds a whole gateway operation, so a hanging provider
+// cannot leave a payment in processing forever.
+const gatewayTimeout = 30 * time.Second
+
 type PaymentService struct {
-	webhookURL string
+	repo             domain.PaymentRepository
+	webhooks         domain.WebhookRepository
+	gateway          domain.Gateway
+	authorizationTTL time.Duration
 }
 
-func NewPaymentService(webhookURL string) *PaymentService {
+func NewPaymentService(repo domain.PaymentRepository, webhooks domain.WebhookRepository, gateway domain.Gateway, authorizationTTL time.Duration) *PaymentService {
 	return &PaymentService{
-		webhookURL: webhookURL,
+		repo:             repo,
+		webhooks:         webhooks,
+		gateway:          gateway,
+		authorizationTTL: authorizationTTL,
 	}
 }
 
 func (ps *PaymentService) ProcessPayment(ctx context.Context, req *domain.PaymentRequest) (*domain.PaymentResponse, error) {
-	paymentID := uuid.New().String()
+	switch req.CaptureMode {
+	case "":
+		req.CaptureMode = domain.CaptureAutomatic
+	case domain.CaptureAutomatic, domain.CaptureManual:
+	default:
+		return nil, domain.ErrInvalidCaptureMode
+	}
+
+	payment := &domain.Payment{
+		ID:          uuid.New().String(),
+		BookingID:   req.BookingID,
+		Amount:      req.Amount,
+		Status:      domain.PaymentProcessing,
+		CaptureMode: req.CaptureMode,
+		CardToken:   req.CardToken,
+	}
+	if err := ps.repo.CreatePayment(ctx, payment); err != nil {
+		return nil, err
+	}
 
 	response := &domain.PaymentResponse{
-		PaymentID: paymentID,
-		Status:    "processing",
+		PaymentID: payment.ID,
+		Status:    string(payment.Status),
 		Message:   "payment is being processed",
 	}
 
-	go ps.processPaymentAsync(ctx, paymentID, req)
+	go ps.processPaymentAsync(context.WithoutCancel(ctx), payment)
 
 	return response, nil
 }
 
-func (ps *PaymentService) processPaymentAsync(ctx context.Context, paymentID string, req *domain.PaymentRequest) {
-	time.Sleep(2 * time.Second)
+func (ps *PaymentService) GetPayment(ctx context.Context, id string) (*domain.Payment, error) {
+	return ps.repo.GetPaymentByID(ctx, id)
+}
+
+func (ps *PaymentService) GetPaymentsByBooking(ctx context.Context, bookingID string) ([]domain.Payment, error) {
+	return ps.repo.GetPaymentsByBooking(ctx, bookingID)
+}
 
-	status := "paid"
-	if req.Amount <= 0 {
-		status = "failed"
+// ProcessRefund refunds an amount of a booking, spread over its captured
+// payments from the latest one, so that a booking paid in several charges can
+// be refunded more than any one of them. Every part is recorded before any
+// reaches the gateway. The response describes the first of the refunds made,
+// with the whole refunded amount.
+func (ps *PaymentService) ProcessRefund(ctx context.Context, req *domain.RefundRequest) (*domain.RefundResponse, error) {
+	if !req.Amount.IsPositive() {
+		return nil, domain.ErrInvalidRefundAmount
 	}
 
-	webhook := domain.PaymentWebhook{
-		PaymentID:   paymentID,
-		BookingID:   req.BookingID,
-		Status:      status,
-		Amount:      req.Amount,
-		ProcessedAt: time.Now().Format(time.RFC3339),
+	refunds, err := ps.repo.CreateBookingRefunds(ctx, req.BookingID, req.Amount)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range refunds {
+		go ps.processRefundAsync(context.WithoutCancel(ctx), &refunds[i])
+	}
+
+	return &domain.RefundResponse{
+		RefundID:  refunds[0].ID,
+		PaymentID: refunds[0].PaymentID,
+		Amount:    req.Amount,
+		Status:    string(refunds[0].Status),
+		Message:   "refund is being processed",
+	}, nil
+}
+
+func (ps *PaymentService) RefundPayment(ctx context.Context, paymentID string, req *domain.RefundRequest) (*domain.RefundResponse, error) {
+	if !req.Amount.IsPositive() {
+		return nil, domain.ErrInvalidRefundAmount
+	}
+
+	refund := &domain.Refund{
+		ID:        uuid.New().String(),
+		PaymentID: paymentID,
+		Amount:    req.Amount,
+		Status:    domain.RefundProcessing,
+	}
+	if err := ps.repo.CreateRefund(ctx, refund); err != nil {
+		return nil, err
+	}
+
+	response := &domain.RefundResponse{
+		RefundID:  refund.ID,
+		PaymentID: refund.PaymentID,
+		Amount:    refund.Amount,
+		Status:    string(refund.Status),
+		Message:   "refund is being processed",
+	}
+
+	go ps.processRefundAsync(context.WithoutCancel(ctx), refund)
+
+	return response, nil
+}
+
+func (ps *PaymentService) GetDeadLetters(ctx context.Context, limit int) ([]domain.DeadLetter, error) {
+	return ps.webhooks.GetDeadLetters(ctx, limit)
+}
+
+func (ps *PaymentService) RedeliverWebhook(ctx context.Context, deadLetterID int64) (*domain.WebhookDelivery, error) {
+	return ps.webhooks.Redeliver(ctx, deadLetterID)
+}
+
+// processRefundAsync refunds through the gateway and completes the refund;
+// the repository queues the refund webhook together with the updated payment.
+func (ps *PaymentService) processRefundAsync(ctx context.Context, refund *domain.Refund) {
+	ctx, cancel := context.WithTimeout(ctx, gatewayTimeout)
+	defer cancel()
+
+	payment, err := ps.repo.GetPaymentByID(ctx, refund.PaymentID)
+	if err != nil {
+		logger.GetLogger().WithError(err).Error("failed to load payment for refund")
+		return
+	}
+
+	result, err := ps.gateway.Refund(ctx, payment.GatewayTransactionID, refund.Amount)
+	reason := ""
+	switch {
+	case err != nil:
+		reason = err.Error()
+	case result.Status != domain.GatewayApproved:
+		reason = result.DeclineCode
+	}
+	if reason != "" {
+		logger.GetLogger().WithField("refund_id", refund.ID).WithField("reason", reason).Error("gateway refund failed")
+		if err := ps.repo.FailRefund(context.WithoutCancel(ctx), refund.ID, reason); err != nil {
+			logger.GetLogger().WithError(err).Error("failed to mark refund as failed")
+		}
+		return
+	}
+
+	if _, err := ps.repo.CompleteRefund(context.WithoutCancel(ctx), refund.ID); err != nil {
+		logger.GetLogger().WithError(err).Error("failed to complete refund")
+	}
+}
+
+func (ps *PaymentService) processPaymentAsync(ctx context.Context, payment *domain.Payment) {
+	if err := ps.processPayment(ctx, payment); err != nil {
+		logger.GetLogger().WithError(err).Error("failed to update payment status")
+	}
+}
+
+func (ps *PaymentService) processPayment(ctx context.Context, payment *domain.Payment) error {
+	from := payment.Status
+	ps.charge(ctx, payment, payment.CardToken)
+	return ps.updateStatus(ctx, payment, from)
+}
+
+// ResumePayments charges again the payments left processing for longer than
+// staleAfter, as happens when the service stops in the middle of a charge.
+// The payment ID keys the authorization at the gateway, so a card charged
+// before the stop is not charged twice.
+func (ps *PaymentService) ResumePayments(ctx context.Context, staleAfter time.Duration, limit int) (int, error) {
+	payments, err := ps.repo.ClaimStalePayments(ctx, time.Now().Add(-staleAfter), limit)
+	if err != nil {
+		return 0, err
+	}
+
+	resumed := 0
+	for i := range payments {
+		payment := &payments[i]
+		err := ps.processPayment(ctx, payment)
+		if errors.Is(err, domain.ErrStatusChanged) {
+			continue
+		}
+		if err != nil {
+			logger.GetLogger().WithError(err).WithField("payment_id", payment.ID).Error("failed to resume payment")
+			continue
+		}
+		resumed++
+	}
+	return resumed, nil
+}
+
+// charge authorizes the payment and, in automatic mode, captures it right
+// away, setting its resulting status. A 3-D Secure challenge leaves the
+// payment waiting for the guest to pass it at the action URL.
+func (ps *PaymentService) charge(ctx context.Context, payment *domain.Payment, cardToken string) {
+	payment.Status = domain.PaymentFailed
+	if !payment.Amount.IsPositive() {
+		payment.FailureReason = "invalid_amount"
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, gatewayTimeout)
+	defer cancel()
+
+	result, err := ps.gateway.Authorize(ctx, domain.AuthorizeRequest{
+		PaymentID: payment.ID,
+		Amount:    payment.Amount,
+		CardToken: cardToken,
+	})
+	if err != nil {
+		payment.FailureReason = err.Error()
+		return
 	}
+	payment.GatewayTransactionID = result.TransactionID
 
-	if err := ps.sendWebhook(ctx, webhook); err != nil {
-		logger.GetLogger().WithError(err).Error("failed to send payment webhook")
+	switch result.Status {
+	case domain.GatewayDeclined:
+		payment.FailureReason = result.DeclineCode
+		return
+	case domain.GatewayRequiresAction:
+		payment.Status = domain.PaymentRequiresAction
+		payment.ActionURL = result.ActionURL
+		return
 	}
+	ps.completeAuthorization(ctx, payment)
 }
 
-func (ps *PaymentService) sendWebhook(ctx context.Context, webhook domain.PaymentWebhook) error {
-	data, err := json.Marshal(webhook)
+// completeAuthorization finishes a payment the gateway has authorized: in
+// manual mode it is left authorized, otherwise it is captured right away.
+func (ps *PaymentService) completeAuthorization(ctx context.Context, payment *domain.Payment) {
+	if payment.CaptureMode == domain.CaptureManual {
+		expiresAt := time.Now().Add(ps.authorizationTTL)
+		payment.Status = domain.PaymentAuthorized
+		payment.AuthorizationExpiresAt = &expiresAt
+		return
+	}
+
+	if _, err := ps.gateway.Capture(ctx, payment.GatewayTransactionID, payment.Amount); err != nil {
+		payment.Status = domain.PaymentFailed
+		payment.FailureReason = err.Error()
+		if _, voidErr := ps.gateway.Void(ctx, payment.GatewayTransactionID); voidErr != nil {
+			logger.GetLogger().WithError(voidErr).Error("failed to void authorization after failed capture")
+		}
+		return
+	}
+	payment.Status = domain.PaymentPaid
+}
+
+// AuthenticatePayment completes a payment whose 3-D Secure challenge the
+// guest has passed: it is authorized and, in automatic mode, captured. A
+// failed challenge fails the payment.
+func (ps *PaymentService) AuthenticatePayment(ctx context.Context, id string) (*domain.Payment, error) {
+	payment, err := ps.repo.GetPaymentByID(ctx, id)
 	if err != nil {
-		return fmt.Errorf("failed to marshal webhook: %w", err)
+		return nil, err
+	}
+	if payment.Status != domain.PaymentRequiresAction {
+		return nil, domain.ErrNoActionRequired
+	}
+
+	gatewayCtx, cancel := context.WithTimeout(ctx, gatewayTimeout)
+	defer cancel()
+	result, err := ps.gateway.Authenticate(gatewayCtx, payment.GatewayTransactionID)
+	if err != nil {
+		return nil, err
+	}
+
+	payment.ActionURL = ""
+	if result.Status == domain.GatewayApproved {
+		payment.GatewayTransactionID = result.TransactionID
+		ps.completeAuthorization(gatewayCtx, payment)
+	} else {
+		payment.Status = domain.PaymentFailed
+		payment.FailureReason = result.DeclineCode
+	}
+
+	if err := ps.updateStatus(ctx, payment, domain.PaymentRequiresAction); err != nil {
+		return nil, err
+	}
+	return payment, nil
+}
+
+// CapturePayment captures the full amount of an authorized payment.
+func (ps *PaymentService) CapturePayment(ctx context.Context, id string) (*domain.Payment, error) {
+	payment, err := ps.repo.GetPaymentByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if payment.Status != domain.PaymentAuthorized {
+		return nil, domain.ErrPaymentNotAuthorized
+	}
+	if payment.AuthorizationExpiresAt != nil && !time.Now().Before(*payment.AuthorizationExpiresAt) {
+		return nil, domain.ErrAuthorizationExpired
 	}
 
-	req, err := http.NewRequestWithContext(ctx, "POST", ps.webhookURL, bytes.NewBuffer(data))
+	gatewayCtx, cancel := context.WithTimeout(ctx, gatewayTimeout)
+	defer cancel()
+	if _, err := ps.gateway.Capture(gatewayCtx, payment.GatewayTransactionID, payment.Amount); err != nil {
+		return nil, err
+	}
+
+	payment.Status = domain.PaymentPaid
+	if err := ps.updateStatus(ctx, payment, domain.PaymentAuthorized); err != nil {
+		return nil, err
+	}
+	return payment, nil
+}
+
+// VoidPayment releases the authorization hold of a payment that was not
+// captured.
+func (ps *PaymentService) VoidPayment(ctx context.Context, id string) (*domain.Payment, error) {
+	payment, err := ps.repo.GetPaymentByID(ctx, id)
 	if err != nil {
-		return fmt.Errorf("failed to create webhook request: %w", err)
+		return nil, err
+	}
+	if payment.Status != domain.PaymentAuthorized {
+		return nil, domain.ErrPaymentNotAuthorized
 	}
 
-	req.Header.Set("Content-Type", "application/json")
+	gatewayCtx, cancel := context.WithTimeout(ctx, gatewayTimeout)
+	defer cancel()
+	if _, err := ps.gateway.Void(gatewayCtx, payment.GatewayTransactionID); err != nil {
+		return nil, err
+	}
 
-	client := &http.Client{
-		Timeout: 10 * time.Second,
+	payment.Status = domain.PaymentVoided
+	if err := ps.updateStatus(ctx, payment, domain.PaymentAuthorized); err != nil {
+		return nil, err
 	}
+	return payment, nil
+}
 
-	resp, err := client.Do(req)
+func (ps *PaymentService) CaptureBookingPayment(ctx context.Context, bookingID string) (*domain.Payment, error) {
+	id, err := ps.authorizedPaymentID(ctx, bookingID)
 	if err != nil {
-		return fmt.Errorf("failed to send webhook: %w", err)
+		return nil, err
 	}
-	defer resp.Body.Close()
+	return ps.CapturePayment(ctx, id)
+}
 
-	if resp.StatusCode != http.StatusOK {
-		return fmt.Errorf("webhook returned status %d", resp.StatusCode)
+func (ps *PaymentService) VoidBookingPayment(ctx context.Context, bookingID string) (*domain.Payment, error) {
+	id, err := ps.authorizedPaymentID(ctx, bookingID)
+	if err != nil {
+		return nil, err
 	}
+	return ps.VoidPayment(ctx, id)
+}
 
-	logger.GetLogger().WithFields(map[string]interface{}{
-		"payment_id": webhook.PaymentID,
-		"booking_id": webhook.BookingID,
-		"status":     webhook.Status,
-	}).Info("payment webhook sent successfully")
+func (ps *PaymentService) authorizedPaymentID(ctx context.Context, bookingID string) (string, error) {
+	payments, err := ps.repo.GetPaymentsByBooking(ctx, bookingID)
+	if err != nil {
+		return "", err
+	}
+	for _, payment := range payments {
+		if payment.Status == domain.PaymentAuthorized {
+			return payment.ID, nil
+		}
+	}
+	return "", domain.ErrPaymentNotAuthorized
+}
+
+// ExpireAuthorizations voids authorizations that were not captured in time.
+// The payment is marked expired even if the gateway void fails, since the
+// provider releases a stale hold on its own.
+func (ps *PaymentService) ExpireAuthorizations(ctx context.Context, limit int) (int, error) {
+	payments, err := ps.repo.GetExpiredAuthorizations(ctx, limit)
+	if err != nil {
+		return 0, err
+	}
 
-	return nil
+	expired := 0
+	for i := range payments {
+		payment := &payments[i]
+
+		gatewayCtx, cancel := context.WithTimeout(ctx, gatewayTimeout)
+		if _, err := ps.gateway.Void(gatewayCtx, payment.GatewayTransactionID); err != nil {
+			logger.GetLogger().WithError(err).WithField("payment_id", payment.ID).Warn("failed to void expired authorization")
+		}
+		cancel()
+
+		payment.Status = domain.PaymentExpired
+		err := ps.updateStatus(ctx, payment, domain.PaymentAuthorized)
+		if errors.Is(err, domain.ErrStatusChanged) {
+			continue
+		}
+		if err != nil {
+			return expired, err
+		}
+		expired++
+	}
+	return expired, nil
+}
+
+// updateStatus stores the payment's new status together with the webhook
+// that reports it to booking-service. A payment waiting for 3-D Secure is not
+// reported; booking-service learns the outcome once the challenge is done.
+func (ps *PaymentService) updateStatus(ctx context.Context, payment *domain.Payment, from domain.PaymentStatus) error {
+	if payment.Status == domain.PaymentRequiresAction {
+		return ps.repo.UpdatePaymentStatus(ctx, payment, from, nil)
+	}
+	delivery, err := domain.NewWebhookDelivery(domain.PaymentWebhook{
+		PaymentID:   payment.ID,
+		BookingID:   payment.BookingID,
+		Status:      string(payment.Status),
+		Amount:      payment.Amount,
+		ProcessedAt: time.Now().Format(time.RFC3339),
+	})
+	if err != nil {
+		return err
+	}
+	return ps.repo.UpdatePaymentStatus(ctx, payment, from, delivery)
 }
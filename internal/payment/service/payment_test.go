@@ -2,162 +2,783 @@ package service
 
 import (
 	"context"
-	"net/http"
-	"net/http/httptest"
+	"encoding/json"
+	"errors"
 	"testing"
 	"time"
 
 	"hotel-booking-system/internal/payment/domain"
+	"hotel-booking-system/internal/payment/gateway"
 	"hotel-booking-system/pkg/logger"
+	"hotel-booking-system/pkg/money"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
 )
 
+type MockPaymentRepository struct {
+	mock.Mock
+}
+
+func (m *MockPaymentRepository) CreatePayment(ctx context.Context, payment *domain.Payment) error {
+	args := m.Called(ctx, payment)
+	return args.Error(0)
+}
+
+func (m *MockPaymentRepository) GetPaymentByID(ctx context.Context, id string) (*domain.Payment, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.Payment), args.Error(1)
+}
+
+func (m *MockPaymentRepository) GetPaymentsByBooking(ctx context.Context, bookingID string) ([]domain.Payment, error) {
+	args := m.Called(ctx, bookingID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]domain.Payment), args.Error(1)
+}
+
+func (m *MockPaymentRepository) UpdatePaymentStatus(ctx context.Context, payment *domain.Payment, from domain.PaymentStatus, delivery *domain.WebhookDelivery) error {
+	args := m.Called(ctx, payment, from, delivery)
+	return args.Error(0)
+}
+
+func (m *MockPaymentRepository) GetExpiredAuthorizations(ctx context.Context, limit int) ([]domain.Payment, error) {
+	args := m.Called(ctx, limit)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]domain.Payment), args.Error(1)
+}
+
+func (m *MockPaymentRepository) ClaimStalePayments(ctx context.Context, updatedBefore time.Time, limit int) ([]domain.Payment, error) {
+	args := m.Called(ctx, updatedBefore, limit)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]domain.Payment), args.Error(1)
+}
+
+func (m *MockPaymentRepository) CreateRefund(ctx context.Context, refund *domain.Refund) error {
+	args := m.Called(ctx, refund)
+	return args.Error(0)
+}
+
+func (m *MockPaymentRepository) CreateBookingRefunds(ctx context.Context, bookingID string, amount money.Money) ([]domain.Refund, error) {
+	args := m.Called(ctx, bookingID, amount)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]domain.Refund), args.Error(1)
+}
+
+func (m *MockPaymentRepository) CompleteRefund(ctx context.Context, refundID string) (*domain.Payment, error) {
+	args := m.Called(ctx, refundID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.Payment), args.Error(1)
+}
+
+func (m *MockPaymentRepository) FailRefund(ctx context.Context, refundID, reason string) error {
+	args := m.Called(ctx, refundID, reason)
+	return args.Error(0)
+}
+
+type MockWebhookRepository struct {
+	mock.Mock
+}
+
+func (m *MockWebhookRepository) ClaimDueDeliveries(ctx context.Context, limit int, claimFor time.Duration) ([]domain.WebhookDelivery, error) {
+	args := m.Called(ctx, limit, claimFor)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]domain.WebhookDelivery), args.Error(1)
+}
+
+func (m *MockWebhookRepository) MarkDelivered(ctx context.Context, id int64) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func (m *MockWebhookRepository) ScheduleRetry(ctx context.Context, id int64, reason string, nextAttemptAt time.Time) error {
+	args := m.Called(ctx, id, reason, nextAttemptAt)
+	return args.Error(0)
+}
+
+func (m *MockWebhookRepository) MoveToDeadLetter(ctx context.Context, id int64, reason string) error {
+	args := m.Called(ctx, id, reason)
+	return args.Error(0)
+}
+
+func (m *MockWebhookRepository) GetDeadLetters(ctx context.Context, limit int) ([]domain.DeadLetter, error) {
+	args := m.Called(ctx, limit)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]domain.DeadLetter), args.Error(1)
+}
+
+func (m *MockWebhookRepository) Redeliver(ctx context.Context, deadLetterID int64) (*domain.WebhookDelivery, error) {
+	args := m.Called(ctx, deadLetterID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.WebhookDelivery), args.Error(1)
+}
+
+// webhookWithStatus matches a queued delivery whose payload reports the status.
+func webhookWithStatus(bookingID, status string) interface{} {
+	return mock.MatchedBy(func(delivery *domain.WebhookDelivery) bool {
+		var webhook domain.PaymentWebhook
+		if err := json.Unmarshal(delivery.Payload, &webhook); err != nil {
+			return false
+		}
+		return delivery.BookingID == bookingID && webhook.BookingID == bookingID && webhook.Status == status
+	})
+}
+
 func TestNewPaymentService(t *testing.T) {
 	logger.Init("info")
 
-	webhookURL := "http://example.com/webhook"
-	service := NewPaymentService(webhookURL)
+	repo := new(MockPaymentRepository)
+	webhooks := new(MockWebhookRepository)
+	fake := gateway.NewFakeGateway(gateway.FakeConfig{})
+	service := NewPaymentService(repo, webhooks, fake, time.Hour)
 
 	assert.NotNil(t, service)
-	assert.Equal(t, webhookURL, service.webhookURL)
+	assert.Equal(t, repo, service.repo)
+	assert.Equal(t, webhooks, service.webhooks)
+	assert.Equal(t, fake, service.gateway)
+	assert.Equal(t, time.Hour, service.authorizationTTL)
+}
+
+// capturedPayment authorizes and captures a payment in the fake gateway and
+// returns its transaction ID.
+func capturedPayment(t *testing.T, fake *gateway.FakeGateway, amount money.Money) string {
+	result, err := fake.Authorize(context.Background(), domain.AuthorizeRequest{PaymentID: "payment-1", Amount: amount})
+	require.NoError(t, err)
+	_, err = fake.Capture(context.Background(), result.TransactionID, amount)
+	require.NoError(t, err)
+	return result.TransactionID
 }
 
 func TestPaymentService_ProcessPayment(t *testing.T) {
 	logger.Init("info")
 
-	t.Run("success with positive amount", func(t *testing.T) {
-		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-			w.WriteHeader(http.StatusOK)
-		}))
-		defer server.Close()
+	tests := []struct {
+		name          string
+		amount        money.Money
+		cardToken     string
+		status        domain.PaymentStatus
+		failureReason string
+	}{
+		{name: "approved", amount: money.New(100000, "RUB"), cardToken: "tok_visa", status: domain.PaymentPaid},
+		{name: "no card token", amount: money.New(100000, "RUB"), status: domain.PaymentPaid},
+		{name: "delayed settlement", amount: money.New(100000, "RUB"), cardToken: gateway.TokenDelayed, status: domain.PaymentPaid},
+		{name: "declined", amount: money.New(100000, "RUB"), cardToken: gateway.TokenDecline, status: domain.PaymentFailed, failureReason: "card_declined"},
+		{name: "insufficient funds", amount: money.New(100000, "RUB"), cardToken: gateway.TokenInsufficientFunds, status: domain.PaymentFailed, failureReason: "insufficient_funds"},
+		{name: "gateway timeout", amount: money.New(100000, "RUB"), cardToken: gateway.TokenTimeout, status: domain.PaymentFailed, failureReason: domain.ErrGatewayTimeout.Error()},
+		{name: "zero amount", amount: money.New(0, "RUB"), status: domain.PaymentFailed, failureReason: "invalid_amount"},
+		{name: "negative amount", amount: money.New(-10000, "RUB"), status: domain.PaymentFailed, failureReason: "invalid_amount"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fake := gateway.NewFakeGateway(gateway.FakeConfig{
+				Timeout:         10 * time.Millisecond,
+				SettlementDelay: 10 * time.Millisecond,
+			})
+			updated := make(chan *domain.Payment, 1)
+
+			repo := new(MockPaymentRepository)
+			repo.On("CreatePayment", mock.Anything, mock.MatchedBy(func(p *domain.Payment) bool {
+				return p.CardToken == tt.cardToken
+			})).Return(nil)
+			repo.On("UpdatePaymentStatus", mock.Anything, mock.Anything, domain.PaymentProcessing,
+				webhookWithStatus("booking-123", string(tt.status))).Run(func(args mock.Arguments) {
+				updated <- args.Get(1).(*domain.Payment)
+			}).Return(nil)
+			service := NewPaymentService(repo, nil, fake, time.Hour)
+
+			response, err := service.ProcessPayment(context.Background(), &domain.PaymentRequest{
+				BookingID: "booking-123",
+				Amount:    tt.amount,
+				CardToken: tt.cardToken,
+			})
+
+			require.NoError(t, err)
+			assert.NotEmpty(t, response.PaymentID)
+			assert.Equal(t, "processing", response.Status)
+
+			select {
+			case payment := <-updated:
+				assert.Equal(t, tt.status, payment.Status)
+				assert.Equal(t, tt.failureReason, payment.FailureReason)
+				if tt.status == domain.PaymentPaid {
+					assert.NotEmpty(t, payment.GatewayTransactionID)
+				}
+			case <-time.After(5 * time.Second):
+				t.Fatal("payment was not processed")
+			}
+			repo.AssertExpectations(t)
+		})
+	}
+
+	t.Run("store error", func(t *testing.T) {
+		repo := new(MockPaymentRepository)
+		repo.On("CreatePayment", mock.Anything, mock.Anything).Return(errors.New("database error"))
+		service := NewPaymentService(repo, nil, gateway.NewFakeGateway(gateway.FakeConfig{}), time.Hour)
+
+		response, err := service.ProcessPayment(context.Background(), &domain.PaymentRequest{
+			BookingID: "booking-123",
+			Amount:    money.New(100000, "RUB"),
+		})
+
+		assert.Error(t, err)
+		assert.Nil(t, response)
+		repo.AssertNotCalled(t, "UpdatePaymentStatus", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
 
-		service := NewPaymentService(server.URL + "/webhook")
+	t.Run("3-D Secure challenge waits for the guest", func(t *testing.T) {
+		fake := gateway.NewFakeGateway(gateway.FakeConfig{})
+		updated := make(chan *domain.Payment, 1)
 
-		req := &domain.PaymentRequest{
+		repo := new(MockPaymentRepository)
+		repo.On("CreatePayment", mock.Anything, mock.AnythingOfType("*domain.Payment")).Return(nil)
+		repo.On("UpdatePaymentStatus", mock.Anything, mock.Anything, domain.PaymentProcessing,
+			(*domain.WebhookDelivery)(nil)).Run(func(args mock.Arguments) {
+			updated <- args.Get(1).(*domain.Payment)
+		}).Return(nil)
+		service := NewPaymentService(repo, nil, fake, time.Hour)
+
+		_, err := service.ProcessPayment(context.Background(), &domain.PaymentRequest{
 			BookingID: "booking-123",
-			Amount:    1000.0,
-			Currency:  "RUB",
+			Amount:    money.New(100000, "RUB"),
+			CardToken: gateway.TokenThreeDSecure,
+		})
+		require.NoError(t, err)
+
+		select {
+		case payment := <-updated:
+			assert.Equal(t, domain.PaymentRequiresAction, payment.Status)
+			assert.Contains(t, payment.ActionURL, payment.GatewayTransactionID)
+		case <-time.After(5 * time.Second):
+			t.Fatal("payment was not processed")
 		}
+		repo.AssertExpectations(t)
+	})
 
-		response, err := service.ProcessPayment(context.Background(), req)
+	t.Run("manual capture authorizes only", func(t *testing.T) {
+		fake := gateway.NewFakeGateway(gateway.FakeConfig{})
+		updated := make(chan *domain.Payment, 1)
 
+		repo := new(MockPaymentRepository)
+		repo.On("CreatePayment", mock.Anything, mock.AnythingOfType("*domain.Payment")).Return(nil)
+		repo.On("UpdatePaymentStatus", mock.Anything, mock.Anything, domain.PaymentProcessing,
+			webhookWithStatus("booking-123", "authorized")).Run(func(args mock.Arguments) {
+			updated <- args.Get(1).(*domain.Payment)
+		}).Return(nil)
+		service := NewPaymentService(repo, nil, fake, time.Hour)
+
+		_, err := service.ProcessPayment(context.Background(), &domain.PaymentRequest{
+			BookingID:   "booking-123",
+			Amount:      money.New(100000, "RUB"),
+			CaptureMode: domain.CaptureManual,
+		})
 		require.NoError(t, err)
-		assert.NotNil(t, response)
-		assert.NotEmpty(t, response.PaymentID)
-		assert.Equal(t, "processing", response.Status)
 
-		time.Sleep(3 * time.Second)
+		select {
+		case payment := <-updated:
+			assert.Equal(t, domain.PaymentAuthorized, payment.Status)
+			assert.NotEmpty(t, payment.GatewayTransactionID)
+			if assert.NotNil(t, payment.AuthorizationExpiresAt) {
+				assert.WithinDuration(t, time.Now().Add(time.Hour), *payment.AuthorizationExpiresAt, time.Minute)
+			}
+		case <-time.After(5 * time.Second):
+			t.Fatal("payment was not processed")
+		}
 	})
 
-	t.Run("success with zero amount", func(t *testing.T) {
-		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-			w.WriteHeader(http.StatusOK)
-		}))
-		defer server.Close()
+	t.Run("invalid capture mode", func(t *testing.T) {
+		repo := new(MockPaymentRepository)
+		service := NewPaymentService(repo, nil, nil, time.Hour)
 
-		service := NewPaymentService(server.URL + "/webhook")
+		response, err := service.ProcessPayment(context.Background(), &domain.PaymentRequest{
+			BookingID:   "booking-123",
+			Amount:      money.New(100000, "RUB"),
+			CaptureMode: "later",
+		})
 
-		req := &domain.PaymentRequest{
-			BookingID: "booking-456",
-			Amount:    0.0,
-			Currency:  "RUB",
-		}
+		assert.ErrorIs(t, err, domain.ErrInvalidCaptureMode)
+		assert.Nil(t, response)
+		repo.AssertNotCalled(t, "CreatePayment", mock.Anything, mock.Anything)
+	})
+}
+
+// authorizedPayment authorizes a payment in the fake gateway and returns it as
+// stored after a manual-capture charge.
+func authorizedPayment(t *testing.T, fake *gateway.FakeGateway, expiresAt time.Time) *domain.Payment {
+	result, err := fake.Authorize(context.Background(), domain.AuthorizeRequest{PaymentID: "payment-123", Amount: money.New(100000, "RUB")})
+	require.NoError(t, err)
+	return &domain.Payment{
+		ID:                     "payment-123",
+		BookingID:              "booking-123",
+		Amount:                 money.New(100000, "RUB"),
+		Status:                 domain.PaymentAuthorized,
+		GatewayTransactionID:   result.TransactionID,
+		AuthorizationExpiresAt: &expiresAt,
+	}
+}
 
-		response, err := service.ProcessPayment(context.Background(), req)
+// challengedPayment authorizes a payment whose card requires 3-D Secure in
+// the fake gateway.
+func challengedPayment(t *testing.T, fake *gateway.FakeGateway, cardToken string, mode domain.CaptureMode) *domain.Payment {
+	result, err := fake.Authorize(context.Background(), domain.AuthorizeRequest{PaymentID: "payment-123", Amount: money.New(100000, "RUB"), CardToken: cardToken})
+	require.NoError(t, err)
+	return &domain.Payment{
+		ID:                   "payment-123",
+		BookingID:            "booking-123",
+		Amount:               money.New(100000, "RUB"),
+		Status:               domain.PaymentRequiresAction,
+		CaptureMode:          mode,
+		GatewayTransactionID: result.TransactionID,
+		ActionURL:            result.ActionURL,
+	}
+}
+
+func TestPaymentService_AuthenticatePayment(t *testing.T) {
+	tests := []struct {
+		name          string
+		cardToken     string
+		mode          domain.CaptureMode
+		status        domain.PaymentStatus
+		failureReason string
+	}{
+		{name: "captures in automatic mode", cardToken: gateway.TokenThreeDSecure, mode: domain.CaptureAutomatic, status: domain.PaymentPaid},
+		{name: "authorizes in manual mode", cardToken: gateway.TokenThreeDSecure, mode: domain.CaptureManual, status: domain.PaymentAuthorized},
+		{name: "failed challenge", cardToken: gateway.TokenThreeDSecureFail, mode: domain.CaptureAutomatic, status: domain.PaymentFailed, failureReason: "authentication_failed"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fake := gateway.NewFakeGateway(gateway.FakeConfig{})
+			challenged := challengedPayment(t, fake, tt.cardToken, tt.mode)
+			challengeID := challenged.GatewayTransactionID
+			repo := new(MockPaymentRepository)
+			repo.On("GetPaymentByID", mock.Anything, "payment-123").Return(challenged, nil)
+			repo.On("UpdatePaymentStatus", mock.Anything, mock.Anything, domain.PaymentRequiresAction,
+				webhookWithStatus("booking-123", string(tt.status))).Return(nil)
+			service := NewPaymentService(repo, nil, fake, time.Hour)
+
+			payment, err := service.AuthenticatePayment(context.Background(), "payment-123")
+			require.NoError(t, err)
+			assert.Equal(t, tt.status, payment.Status)
+			assert.Equal(t, tt.failureReason, payment.FailureReason)
+			assert.Empty(t, payment.ActionURL)
+			if tt.status != domain.PaymentFailed {
+				assert.NotEqual(t, challengeID, payment.GatewayTransactionID)
+			}
+			repo.AssertExpectations(t)
+		})
+	}
+
+	t.Run("payment not waiting for authentication", func(t *testing.T) {
+		fake := gateway.NewFakeGateway(gateway.FakeConfig{})
+		repo := new(MockPaymentRepository)
+		repo.On("GetPaymentByID", mock.Anything, "payment-123").Return(authorizedPayment(t, fake, time.Now().Add(time.Hour)), nil)
+		service := NewPaymentService(repo, nil, fake, time.Hour)
+
+		payment, err := service.AuthenticatePayment(context.Background(), "payment-123")
+		assert.ErrorIs(t, err, domain.ErrNoActionRequired)
+		assert.Nil(t, payment)
+		repo.AssertNotCalled(t, "UpdatePaymentStatus", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+}
 
+func TestPaymentService_CapturePayment(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		fake := gateway.NewFakeGateway(gateway.FakeConfig{})
+		repo := new(MockPaymentRepository)
+		repo.On("GetPaymentByID", mock.Anything, "payment-123").Return(authorizedPayment(t, fake, time.Now().Add(time.Hour)), nil)
+		repo.On("UpdatePaymentStatus", mock.Anything, mock.Anything, domain.PaymentAuthorized,
+			webhookWithStatus("booking-123", "paid")).Return(nil)
+		service := NewPaymentService(repo, nil, fake, time.Hour)
+
+		payment, err := service.CapturePayment(context.Background(), "payment-123")
 		require.NoError(t, err)
-		assert.NotNil(t, response)
-		assert.Equal(t, "processing", response.Status)
+		assert.Equal(t, domain.PaymentPaid, payment.Status)
+		repo.AssertExpectations(t)
+	})
 
-		time.Sleep(3 * time.Second)
+	t.Run("by booking", func(t *testing.T) {
+		fake := gateway.NewFakeGateway(gateway.FakeConfig{})
+		payment := authorizedPayment(t, fake, time.Now().Add(time.Hour))
+		repo := new(MockPaymentRepository)
+		repo.On("GetPaymentsByBooking", mock.Anything, "booking-123").Return([]domain.Payment{
+			{ID: "payment-1", Status: domain.PaymentFailed},
+			*payment,
+		}, nil)
+		repo.On("GetPaymentByID", mock.Anything, "payment-123").Return(payment, nil)
+		repo.On("UpdatePaymentStatus", mock.Anything, mock.Anything, domain.PaymentAuthorized, mock.Anything).Return(nil)
+		service := NewPaymentService(repo, nil, fake, time.Hour)
+
+		result, err := service.CaptureBookingPayment(context.Background(), "booking-123")
+		require.NoError(t, err)
+		assert.Equal(t, "payment-123", result.ID)
+		assert.Equal(t, domain.PaymentPaid, result.Status)
 	})
 
-	t.Run("success with negative amount", func(t *testing.T) {
-		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-			w.WriteHeader(http.StatusOK)
-		}))
-		defer server.Close()
+	t.Run("not authorized", func(t *testing.T) {
+		repo := new(MockPaymentRepository)
+		repo.On("GetPaymentByID", mock.Anything, "payment-123").Return(&domain.Payment{ID: "payment-123", Status: domain.PaymentPaid}, nil)
+		service := NewPaymentService(repo, nil, nil, time.Hour)
 
-		service := NewPaymentService(server.URL + "/webhook")
+		payment, err := service.CapturePayment(context.Background(), "payment-123")
+		assert.ErrorIs(t, err, domain.ErrPaymentNotAuthorized)
+		assert.Nil(t, payment)
+	})
 
-		req := &domain.PaymentRequest{
-			BookingID: "booking-789",
-			Amount:    -100.0,
-			Currency:  "RUB",
-		}
+	t.Run("no authorized payment for booking", func(t *testing.T) {
+		repo := new(MockPaymentRepository)
+		repo.On("GetPaymentsByBooking", mock.Anything, "booking-123").Return([]domain.Payment{{ID: "payment-1", Status: domain.PaymentPaid}}, nil)
+		service := NewPaymentService(repo, nil, nil, time.Hour)
 
-		response, err := service.ProcessPayment(context.Background(), req)
+		payment, err := service.CaptureBookingPayment(context.Background(), "booking-123")
+		assert.ErrorIs(t, err, domain.ErrPaymentNotAuthorized)
+		assert.Nil(t, payment)
+	})
 
-		require.NoError(t, err)
-		assert.NotNil(t, response)
+	t.Run("authorization expired", func(t *testing.T) {
+		fake := gateway.NewFakeGateway(gateway.FakeConfig{})
+		repo := new(MockPaymentRepository)
+		repo.On("GetPaymentByID", mock.Anything, "payment-123").Return(authorizedPayment(t, fake, time.Now().Add(-time.Minute)), nil)
+		service := NewPaymentService(repo, nil, fake, time.Hour)
 
-		time.Sleep(3 * time.Second)
+		payment, err := service.CapturePayment(context.Background(), "payment-123")
+		assert.ErrorIs(t, err, domain.ErrAuthorizationExpired)
+		assert.Nil(t, payment)
+		repo.AssertNotCalled(t, "UpdatePaymentStatus", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
 	})
 }
 
-func TestPaymentService_sendWebhook(t *testing.T) {
+func TestPaymentService_VoidPayment(t *testing.T) {
+	fake := gateway.NewFakeGateway(gateway.FakeConfig{})
+	payment := authorizedPayment(t, fake, time.Now().Add(time.Hour))
+	repo := new(MockPaymentRepository)
+	repo.On("GetPaymentsByBooking", mock.Anything, "booking-123").Return([]domain.Payment{*payment}, nil)
+	repo.On("GetPaymentByID", mock.Anything, "payment-123").Return(payment, nil)
+	repo.On("UpdatePaymentStatus", mock.Anything, mock.Anything, domain.PaymentAuthorized,
+		webhookWithStatus("booking-123", "voided")).Return(nil)
+	service := NewPaymentService(repo, nil, fake, time.Hour)
+
+	result, err := service.VoidBookingPayment(context.Background(), "booking-123")
+	require.NoError(t, err)
+	assert.Equal(t, domain.PaymentVoided, result.Status)
+	repo.AssertExpectations(t)
+}
+
+func TestPaymentService_ExpireAuthorizations(t *testing.T) {
 	logger.Init("info")
 
-	t.Run("success", func(t *testing.T) {
-		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-			assert.Equal(t, "POST", r.Method)
-			assert.Equal(t, "application/json", r.Header.Get("Content-Type"))
-			w.WriteHeader(http.StatusOK)
-		}))
-		defer server.Close()
+	fake := gateway.NewFakeGateway(gateway.FakeConfig{})
+	expired := authorizedPayment(t, fake, time.Now().Add(-time.Minute))
+	raced := *expired
+	raced.ID = "payment-456"
+
+	repo := new(MockPaymentRepository)
+	repo.On("GetExpiredAuthorizations", mock.Anything, 100).Return([]domain.Payment{*expired, raced}, nil)
+	repo.On("UpdatePaymentStatus", mock.Anything, mock.MatchedBy(func(p *domain.Payment) bool {
+		return p.ID == "payment-123" && p.Status == domain.PaymentExpired
+	}), domain.PaymentAuthorized, webhookWithStatus("booking-123", "expired")).Return(nil)
+	repo.On("UpdatePaymentStatus", mock.Anything, mock.MatchedBy(func(p *domain.Payment) bool {
+		return p.ID == "payment-456"
+	}), domain.PaymentAuthorized, mock.Anything).Return(domain.ErrStatusChanged)
+	service := NewPaymentService(repo, nil, fake, time.Hour)
+
+	count, err := service.ExpireAuthorizations(context.Background(), 100)
+	require.NoError(t, err)
+	assert.Equal(t, 1, count)
+	repo.AssertExpectations(t)
+}
 
-		service := NewPaymentService(server.URL + "/webhook")
+func TestPaymentService_ResumePayments(t *testing.T) {
+	logger.Init("info")
 
-		webhook := domain.PaymentWebhook{
-			PaymentID:   "payment-123",
+	stale := func(id, cardToken string) domain.Payment {
+		return domain.Payment{
+			ID:          id,
 			BookingID:   "booking-123",
-			Status:      "paid",
-			Amount:      1000.0,
-			ProcessedAt: time.Now().Format(time.RFC3339),
+			Amount:      money.New(100000, "RUB"),
+			Status:      domain.PaymentProcessing,
+			CaptureMode: domain.CaptureAutomatic,
+			CardToken:   cardToken,
 		}
+	}
+
+	repo := new(MockPaymentRepository)
+	var updatedBefore time.Time
+	repo.On("ClaimStalePayments", mock.Anything, mock.Anything, 50).
+		Run(func(args mock.Arguments) { updatedBefore = args.Get(1).(time.Time) }).
+		Return([]domain.Payment{
+			stale("payment-123", "tok_visa"),
+			stale("payment-456", gateway.TokenDecline),
+			stale("payment-789", "tok_visa"),
+		}, nil)
+	repo.On("UpdatePaymentStatus", mock.Anything, mock.MatchedBy(func(p *domain.Payment) bool {
+		return p.ID == "payment-123" && p.Status == domain.PaymentPaid && p.GatewayTransactionID != ""
+	}), domain.PaymentProcessing, webhookWithStatus("booking-123", "paid")).Return(nil)
+	repo.On("UpdatePaymentStatus", mock.Anything, mock.MatchedBy(func(p *domain.Payment) bool {
+		return p.ID == "payment-456" && p.FailureReason == "card_declined"
+	}), domain.PaymentProcessing, webhookWithStatus("booking-123", "failed")).Return(nil)
+	repo.On("UpdatePaymentStatus", mock.Anything, mock.MatchedBy(func(p *domain.Payment) bool {
+		return p.ID == "payment-789"
+	}), domain.PaymentProcessing, mock.Anything).Return(domain.ErrStatusChanged)
+	service := NewPaymentService(repo, nil, gateway.NewFakeGateway(gateway.FakeConfig{}), time.Hour)
+
+	count, err := service.ResumePayments(context.Background(), 5*time.Minute, 50)
+	require.NoError(t, err)
+	assert.Equal(t, 2, count)
+	assert.WithinDuration(t, time.Now().Add(-5*time.Minute), updatedBefore, time.Minute)
+	repo.AssertExpectations(t)
+}
 
-		err := service.sendWebhook(context.Background(), webhook)
-		assert.NoError(t, err)
-	})
+func TestPaymentService_GetPayment(t *testing.T) {
+	repo := new(MockPaymentRepository)
+	payment := &domain.Payment{ID: "payment-123", BookingID: "booking-123", Status: domain.PaymentPaid}
+	repo.On("GetPaymentByID", mock.Anything, "payment-123").Return(payment, nil)
+	repo.On("GetPaymentsByBooking", mock.Anything, "booking-123").Return([]domain.Payment{*payment}, nil)
+	service := NewPaymentService(repo, nil, nil, time.Hour)
+
+	result, err := service.GetPayment(context.Background(), "payment-123")
+	require.NoError(t, err)
+	assert.Equal(t, payment, result)
 
-	t.Run("webhook returns error status", func(t *testing.T) {
-		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-			w.WriteHeader(http.StatusInternalServerError)
-		}))
-		defer server.Close()
+	payments, err := service.GetPaymentsByBooking(context.Background(), "booking-123")
+	require.NoError(t, err)
+	assert.Len(t, payments, 1)
+}
 
-		service := NewPaymentService(server.URL + "/webhook")
+func TestPaymentService_ProcessRefund(t *testing.T) {
+	logger.Init("info")
 
-		webhook := domain.PaymentWebhook{
-			PaymentID:   "payment-123",
-			BookingID:   "booking-123",
-			Status:      "paid",
-			Amount:      1000.0,
-			ProcessedAt: time.Now().Format(time.RFC3339),
+	t.Run("completes refund of the captured payment", func(t *testing.T) {
+		fake := gateway.NewFakeGateway(gateway.FakeConfig{})
+		transactionID := capturedPayment(t, fake, money.New(50000, "RUB"))
+		completed := make(chan string, 1)
+		repo := new(MockPaymentRepository)
+		repo.On("CreateBookingRefunds", mock.Anything, "booking-123", money.New(50000, "RUB")).Return([]domain.Refund{
+			{ID: "refund-1", PaymentID: "payment-1", BookingID: "booking-123", Amount: money.New(50000, "RUB"), Status: domain.RefundProcessing},
+		}, nil)
+		repo.On("GetPaymentByID", mock.Anything, "payment-1").Return(&domain.Payment{
+			ID: "payment-1", BookingID: "booking-123", Amount: money.New(50000, "RUB"), Status: domain.PaymentPaid, GatewayTransactionID: transactionID,
+		}, nil)
+		repo.On("CompleteRefund", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+			completed <- args.String(1)
+		}).Return(&domain.Payment{
+			ID:             "payment-1",
+			BookingID:      "booking-123",
+			Amount:         money.New(50000, "RUB"),
+			Status:         domain.PaymentRefunded,
+			RefundedAmount: money.New(50000, "RUB"),
+		}, nil)
+		service := NewPaymentService(repo, nil, fake, time.Hour)
+
+		ctx, cancel := context.WithCancel(context.Background())
+		response, err := service.ProcessRefund(ctx, &domain.RefundRequest{
+			BookingID: "booking-123",
+			Amount:    money.New(50000, "RUB"),
+		})
+		cancel()
+
+		require.NoError(t, err)
+		assert.NotEmpty(t, response.RefundID)
+		assert.Equal(t, "processing", response.Status)
+
+		select {
+		case refundID := <-completed:
+			assert.Equal(t, response.RefundID, refundID)
+		case <-time.After(5 * time.Second):
+			t.Fatal("refund was not completed")
 		}
+	})
+
+	t.Run("processes every part of the split", func(t *testing.T) {
+		loaded := make(chan string, 2)
+		repo := new(MockPaymentRepository)
+		repo.On("CreateBookingRefunds", mock.Anything, "booking-123", money.New(70000, "RUB")).Return([]domain.Refund{
+			{ID: "refund-2", PaymentID: "payment-2", BookingID: "booking-123", Amount: money.New(20000, "RUB"), Status: domain.RefundProcessing},
+			{ID: "refund-1", PaymentID: "payment-1", BookingID: "booking-123", Amount: money.New(50000, "RUB"), Status: domain.RefundProcessing},
+		}, nil)
+		repo.On("GetPaymentByID", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+			loaded <- args.String(1)
+		}).Return(nil, errors.New("stop"))
+		service := NewPaymentService(repo, nil, nil, time.Hour)
+
+		response, err := service.ProcessRefund(context.Background(), &domain.RefundRequest{
+			BookingID: "booking-123",
+			Amount:    money.New(70000, "RUB"),
+		})
+		require.NoError(t, err)
+		assert.Equal(t, "refund-2", response.RefundID)
+		assert.Equal(t, "payment-2", response.PaymentID)
+		assert.Equal(t, money.New(70000, "RUB"), response.Amount)
+
+		var processed []string
+		for i := 0; i < 2; i++ {
+			select {
+			case paymentID := <-loaded:
+				processed = append(processed, paymentID)
+			case <-time.After(5 * time.Second):
+				t.Fatal("refund was not processed")
+			}
+		}
+		assert.ElementsMatch(t, []string{"payment-1", "payment-2"}, processed)
+	})
+
+	t.Run("exceeds captured payments", func(t *testing.T) {
+		repo := new(MockPaymentRepository)
+		repo.On("CreateBookingRefunds", mock.Anything, "booking-123", money.New(50000, "RUB")).
+			Return(nil, domain.ErrRefundExceedsPayment)
+		service := NewPaymentService(repo, nil, nil, time.Hour)
+
+		response, err := service.ProcessRefund(context.Background(), &domain.RefundRequest{
+			BookingID: "booking-123",
+			Amount:    money.New(50000, "RUB"),
+		})
+		assert.ErrorIs(t, err, domain.ErrRefundExceedsPayment)
+		assert.Nil(t, response)
+		repo.AssertNotCalled(t, "GetPaymentByID", mock.Anything, mock.Anything)
+	})
 
-		err := service.sendWebhook(context.Background(), webhook)
+	t.Run("non-positive amount", func(t *testing.T) {
+		service := NewPaymentService(nil, nil, nil, time.Hour)
+
+		response, err := service.ProcessRefund(context.Background(), &domain.RefundRequest{
+			BookingID: "booking-123",
+			Amount:    money.New(0, "RUB"),
+		})
 		assert.Error(t, err)
-		assert.Contains(t, err.Error(), "status 500")
+		assert.Nil(t, response)
 	})
 
-	t.Run("invalid webhook URL", func(t *testing.T) {
-		service := NewPaymentService("http://invalid-url-that-does-not-exist:9999/webhook")
+	t.Run("no captured payment", func(t *testing.T) {
+		repo := new(MockPaymentRepository)
+		repo.On("CreateBookingRefunds", mock.Anything, "booking-123", money.New(50000, "RUB")).
+			Return(nil, domain.ErrPaymentNotRefundable)
+		service := NewPaymentService(repo, nil, nil, time.Hour)
 
-		webhook := domain.PaymentWebhook{
-			PaymentID:   "payment-123",
-			BookingID:   "booking-123",
-			Status:      "paid",
-			Amount:      1000.0,
-			ProcessedAt: time.Now().Format(time.RFC3339),
+		response, err := service.ProcessRefund(context.Background(), &domain.RefundRequest{
+			BookingID: "booking-123",
+			Amount:    money.New(50000, "RUB"),
+		})
+		assert.ErrorIs(t, err, domain.ErrPaymentNotRefundable)
+		assert.Nil(t, response)
+	})
+}
+
+func TestPaymentService_RefundPayment(t *testing.T) {
+	logger.Init("info")
+
+	t.Run("partial refund", func(t *testing.T) {
+		fake := gateway.NewFakeGateway(gateway.FakeConfig{})
+		transactionID := capturedPayment(t, fake, money.New(100000, "RUB"))
+		completed := make(chan string, 1)
+
+		repo := new(MockPaymentRepository)
+		repo.On("CreateRefund", mock.Anything, mock.AnythingOfType("*domain.Refund")).Return(nil)
+		repo.On("GetPaymentByID", mock.Anything, "payment-123").Return(&domain.Payment{
+			ID: "payment-123", BookingID: "booking-123", Amount: money.New(100000, "RUB"), Status: domain.PaymentPaid, GatewayTransactionID: transactionID,
+		}, nil)
+		repo.On("CompleteRefund", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+			completed <- args.String(1)
+		}).Return(&domain.Payment{
+			ID:             "payment-123",
+			BookingID:      "booking-123",
+			Amount:         money.New(100000, "RUB"),
+			Status:         domain.PaymentPartiallyRefunded,
+			RefundedAmount: money.New(30000, "RUB"),
+		}, nil)
+		service := NewPaymentService(repo, nil, fake, time.Hour)
+
+		response, err := service.RefundPayment(context.Background(), "payment-123", &domain.RefundRequest{Amount: money.New(30000, "RUB")})
+
+		require.NoError(t, err)
+		assert.Equal(t, "payment-123", response.PaymentID)
+		assert.Equal(t, money.New(30000, "RUB"), response.Amount)
+		assert.Equal(t, "processing", response.Status)
+
+		select {
+		case refundID := <-completed:
+			assert.Equal(t, response.RefundID, refundID)
+		case <-time.After(5 * time.Second):
+			t.Fatal("refund was not completed")
+		}
+	})
+
+	t.Run("gateway rejects refund", func(t *testing.T) {
+		failed := make(chan string, 1)
+
+		repo := new(MockPaymentRepository)
+		repo.On("CreateRefund", mock.Anything, mock.AnythingOfType("*domain.Refund")).Return(nil)
+		repo.On("GetPaymentByID", mock.Anything, "payment-123").Return(&domain.Payment{
+			ID: "payment-123", BookingID: "booking-123", Amount: money.New(100000, "RUB"), Status: domain.PaymentPaid, GatewayTransactionID: "unknown",
+		}, nil)
+		repo.On("FailRefund", mock.Anything, mock.Anything, domain.ErrTransactionNotFound.Error()).Run(func(args mock.Arguments) {
+			failed <- args.String(1)
+		}).Return(nil)
+		service := NewPaymentService(repo, nil, gateway.NewFakeGateway(gateway.FakeConfig{}), time.Hour)
+
+		response, err := service.RefundPayment(context.Background(), "payment-123", &domain.RefundRequest{Amount: money.New(30000, "RUB")})
+		require.NoError(t, err)
+
+		select {
+		case refundID := <-failed:
+			assert.Equal(t, response.RefundID, refundID)
+		case <-time.After(5 * time.Second):
+			t.Fatal("refund was not marked as failed")
 		}
+		repo.AssertNotCalled(t, "CompleteRefund", mock.Anything, mock.Anything)
+	})
 
-		ctx, cancel := context.WithTimeout(context.Background(), 1*time.Second)
-		defer cancel()
+	t.Run("exceeds refundable balance", func(t *testing.T) {
+		repo := new(MockPaymentRepository)
+		repo.On("CreateRefund", mock.Anything, mock.Anything).Return(domain.ErrRefundExceedsPayment)
+		service := NewPaymentService(repo, nil, nil, time.Hour)
 
-		err := service.sendWebhook(ctx, webhook)
-		assert.Error(t, err)
+		response, err := service.RefundPayment(context.Background(), "payment-123", &domain.RefundRequest{Amount: money.New(500000, "RUB")})
+
+		assert.ErrorIs(t, err, domain.ErrRefundExceedsPayment)
+		assert.Nil(t, response)
+		repo.AssertNotCalled(t, "CompleteRefund", mock.Anything, mock.Anything)
+	})
+
+	t.Run("non-positive amount", func(t *testing.T) {
+		service := NewPaymentService(new(MockPaymentRepository), nil, nil, time.Hour)
+
+		response, err := service.RefundPayment(context.Background(), "payment-123", &domain.RefundRequest{Amount: money.New(-100, "RUB")})
+		assert.ErrorIs(t, err, domain.ErrInvalidRefundAmount)
+		assert.Nil(t, response)
 	})
 }
+
+func TestPaymentService_DeadLetters(t *testing.T) {
+	webhooks := new(MockWebhookRepository)
+	webhooks.On("GetDeadLetters", mock.Anything, 50).Return([]domain.DeadLetter{{ID: 7, PaymentID: "payment-123"}}, nil)
+	webhooks.On("Redeliver", mock.Anything, int64(7)).Return(&domain.WebhookDelivery{ID: 42, PaymentID: "payment-123"}, nil)
+	service := NewPaymentService(new(MockPaymentRepository), webhooks, nil, time.Hour)
+
+	deadLetters, err := service.GetDeadLetters(context.Background(), 50)
+	require.NoError(t, err)
+	assert.Len(t, deadLetters, 1)
+
+	delivery, err := service.RedeliverWebhook(context.Background(), 7)
+	require.NoError(t, err)
+	assert.Equal(t, int64(42), delivery.ID)
+	webhooks.AssertExpectations(t)
+}
@@ -0,0 +1,40 @@
+package worker
+
+import (
+	"context"
+	"time"
+
+	"hotel-booking-system/pkg/logger"
+	"hotel-booking-system/pkg/periodic"
+)
+
+type AuthorizationExpirer interface {
+	ExpireAuthorizations(ctx context.Context, limit int) (int, error)
+}
+
+type AuthorizationReaper struct {
+	expirer   AuthorizationExpirer
+	interval  time.Duration
+	batchSize int
+}
+
+func NewAuthorizationReaper(expirer AuthorizationExpirer, interval time.Duration, batchSize int) *AuthorizationReaper {
+	return &AuthorizationReaper{
+		expirer:   expirer,
+		interval:  interval,
+		batchSize: batchSize,
+	}
+}
+
+func (r *AuthorizationReaper) Run(ctx context.Context) {
+	periodic.Run(ctx, r.interval, "expire payment authorizations", func(ctx context.Context) error {
+		expired, err := r.expirer.ExpireAuthorizations(ctx, r.batchSize)
+		if err != nil {
+			return err
+		}
+		if expired > 0 {
+			logger.GetLogger().Infof("expired %d payment authorizations", expired)
+		}
+		return nil
+	})
+}
@@ -0,0 +1,44 @@
+package worker
+
+import (
+	"context"
+	"time"
+
+	"hotel-booking-system/pkg/logger"
+	"hotel-booking-system/pkg/periodic"
+)
+
+type PaymentRunner interface {
+	ResumePayments(ctx context.Context, staleAfter time.Duration, limit int) (int, error)
+}
+
+// PaymentResumer retries the charges of the payments that have been
+// processing for longer than staleAfter.
+type PaymentResumer struct {
+	runner     PaymentRunner
+	interval   time.Duration
+	staleAfter time.Duration
+	batchSize  int
+}
+
+func NewPaymentResumer(runner PaymentRunner, interval, staleAfter time.Duration, batchSize int) *PaymentResumer {
+	return &PaymentResumer{
+		runner:     runner,
+		interval:   interval,
+		staleAfter: staleAfter,
+		batchSize:  batchSize,
+	}
+}
+
+func (r *PaymentResumer) Run(ctx context.Context) {
+	periodic.Run(ctx, r.interval, "resume stale payments", func(ctx context.Context) error {
+		resumed, err := r.runner.ResumePayments(ctx, r.staleAfter, r.batchSize)
+		if err != nil {
+			return err
+		}
+		if resumed > 0 {
+			logger.GetLogger().Infof("resumed %d stale payments", resumed)
+		}
+		return nil
+	})
+}
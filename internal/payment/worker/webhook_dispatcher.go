@@ -0,0 +1,107 @@
+package worker
+
+import (
+	"context"
+	"math/rand/v2"
+	"time"
+
+	"hotel-booking-system/internal/payment/domain"
+	"hotel-booking-system/pkg/logger"
+	"hotel-booking-system/pkg/periodic"
+)
+
+type WebhookSender interface {
+	Send(ctx context.Context, body []byte) error
+}
+
+type RetryPolicy struct {
+	MaxAttempts int
+	BaseDelay   time.Duration
+	MaxDelay    time.Duration
+	// ClaimTimeout is how long a delivery picked for sending is hidden from
+	// other dispatchers; if the dispatcher dies mid-batch, the delivery is
+	// retried after it.
+	ClaimTimeout time.Duration
+}
+
+// Backoff returns the delay before the attempt following the given number of
+// failed attempts: the base delay doubled per failure, capped at MaxDelay, with
+// a random jitter of up to half the delay so that deliveries queued together
+// do not retry in lockstep.
+func (p RetryPolicy) Backoff(failedAttempts int) time.Duration {
+	delay := p.BaseDelay
+	for i := 1; i < failedAttempts && delay < p.MaxDelay; i++ {
+		delay *= 2
+	}
+	if delay > p.MaxDelay {
+		delay = p.MaxDelay
+	}
+	half := delay / 2
+	return half + rand.N(half+1)
+}
+
+type WebhookDispatcher struct {
+	repo      domain.WebhookRepository
+	sender    WebhookSender
+	policy    RetryPolicy
+	interval  time.Duration
+	batchSize int
+}
+
+func NewWebhookDispatcher(repo domain.WebhookRepository, sender WebhookSender, policy RetryPolicy, interval time.Duration, batchSize int) *WebhookDispatcher {
+	return &WebhookDispatcher{
+		repo:      repo,
+		sender:    sender,
+		policy:    policy,
+		interval:  interval,
+		batchSize: batchSize,
+	}
+}
+
+func (d *WebhookDispatcher) Run(ctx context.Context) {
+	periodic.Run(ctx, d.interval, "dispatch webhooks", func(ctx context.Context) error {
+		_, err := d.ProcessBatch(ctx)
+		return err
+	})
+}
+
+// ProcessBatch sends the deliveries that are due, at most one per booking. A
+// failed delivery is rescheduled with backoff, holding back the later
+// deliveries of its booking, until it reaches the attempt limit and is moved
+// to the dead-letter table.
+func (d *WebhookDispatcher) ProcessBatch(ctx context.Context) (int, error) {
+	deliveries, err := d.repo.ClaimDueDeliveries(ctx, d.batchSize, d.policy.ClaimTimeout)
+	if err != nil {
+		return 0, err
+	}
+
+	delivered := 0
+	for _, delivery := range deliveries {
+		sendErr := d.sender.Send(ctx, delivery.Payload)
+		if sendErr == nil {
+			if err := d.repo.MarkDelivered(ctx, delivery.ID); err != nil {
+				return delivered, err
+			}
+			delivered++
+			continue
+		}
+
+		log := logger.GetLogger().WithError(sendErr).WithFields(map[string]interface{}{
+			"delivery_id": delivery.ID,
+			"payment_id":  delivery.PaymentID,
+			"attempt":     delivery.Attempts + 1,
+		})
+		failedAttempts := delivery.Attempts + 1
+		if failedAttempts >= d.policy.MaxAttempts {
+			log.Error("webhook delivery exhausted its attempts, moving to dead letters")
+			err = d.repo.MoveToDeadLetter(ctx, delivery.ID, sendErr.Error())
+		} else {
+			log.Warn("webhook delivery failed, scheduling retry")
+			err = d.repo.ScheduleRetry(ctx, delivery.ID, sendErr.Error(), time.Now().Add(d.policy.Backoff(failedAttempts)))
+		}
+		if err != nil {
+			return delivered, err
+		}
+	}
+	return delivered, nil
+}
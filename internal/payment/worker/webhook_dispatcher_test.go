@@ -0,0 +1,169 @@
+package worker
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"hotel-booking-system/internal/payment/domain"
+	"hotel-booking-system/pkg/logger"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockWebhookRepository struct {
+	mock.Mock
+}
+
+func (m *MockWebhookRepository) ClaimDueDeliveries(ctx context.Context, limit int, claimFor time.Duration) ([]domain.WebhookDelivery, error) {
+	args := m.Called(ctx, limit, claimFor)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]domain.WebhookDelivery), args.Error(1)
+}
+
+func (m *MockWebhookRepository) MarkDelivered(ctx context.Context, id int64) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func (m *MockWebhookRepository) ScheduleRetry(ctx context.Context, id int64, reason string, nextAttemptAt time.Time) error {
+	args := m.Called(ctx, id, reason, nextAttemptAt)
+	return args.Error(0)
+}
+
+func (m *MockWebhookRepository) MoveToDeadLetter(ctx context.Context, id int64, reason string) error {
+	args := m.Called(ctx, id, reason)
+	return args.Error(0)
+}
+
+func (m *MockWebhookRepository) GetDeadLetters(ctx context.Context, limit int) ([]domain.DeadLetter, error) {
+	args := m.Called(ctx, limit)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]domain.DeadLetter), args.Error(1)
+}
+
+func (m *MockWebhookRepository) Redeliver(ctx context.Context, deadLetterID int64) (*domain.WebhookDelivery, error) {
+	args := m.Called(ctx, deadLetterID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.WebhookDelivery), args.Error(1)
+}
+
+type MockSender struct {
+	sent     []string
+	failBody string
+}
+
+func (m *MockSender) Send(ctx context.Context, body []byte) error {
+	if string(body) == m.failBody {
+		return errors.New("webhook returned status 503")
+	}
+	m.sent = append(m.sent, string(body))
+	return nil
+}
+
+var testPolicy = RetryPolicy{MaxAttempts: 3, BaseDelay: time.Second, MaxDelay: time.Minute, ClaimTimeout: time.Minute}
+
+func TestRetryPolicy_Backoff(t *testing.T) {
+	policy := RetryPolicy{MaxAttempts: 10, BaseDelay: time.Second, MaxDelay: 10 * time.Second}
+
+	tests := []struct {
+		failedAttempts int
+		max            time.Duration
+	}{
+		{1, time.Second},
+		{2, 2 * time.Second},
+		{3, 4 * time.Second},
+		{4, 8 * time.Second},
+		{5, 10 * time.Second},
+		{50, 10 * time.Second},
+	}
+
+	for _, tt := range tests {
+		for i := 0; i < 20; i++ {
+			delay := policy.Backoff(tt.failedAttempts)
+			assert.GreaterOrEqual(t, delay, tt.max/2)
+			assert.LessOrEqual(t, delay, tt.max)
+		}
+	}
+}
+
+func TestWebhookDispatcher_ProcessBatch(t *testing.T) {
+	logger.Init("info")
+
+	t.Run("marks sent deliveries", func(t *testing.T) {
+		repo := new(MockWebhookRepository)
+		sender := &MockSender{}
+		repo.On("ClaimDueDeliveries", mock.Anything, 10, time.Minute).Return([]domain.WebhookDelivery{
+			{ID: 1, PaymentID: "payment-1", Payload: []byte(`{"payment_id":"payment-1"}`)},
+			{ID: 2, PaymentID: "payment-2", Payload: []byte(`{"payment_id":"payment-2"}`)},
+		}, nil)
+		repo.On("MarkDelivered", mock.Anything, int64(1)).Return(nil)
+		repo.On("MarkDelivered", mock.Anything, int64(2)).Return(nil)
+
+		dispatcher := NewWebhookDispatcher(repo, sender, testPolicy, time.Second, 10)
+		delivered, err := dispatcher.ProcessBatch(context.Background())
+
+		assert.NoError(t, err)
+		assert.Equal(t, 2, delivered)
+		assert.Equal(t, []string{`{"payment_id":"payment-1"}`, `{"payment_id":"payment-2"}`}, sender.sent)
+		repo.AssertExpectations(t)
+	})
+
+	t.Run("schedules retry with backoff and continues", func(t *testing.T) {
+		repo := new(MockWebhookRepository)
+		sender := &MockSender{failBody: `{"payment_id":"payment-1"}`}
+		repo.On("ClaimDueDeliveries", mock.Anything, 10, time.Minute).Return([]domain.WebhookDelivery{
+			{ID: 1, PaymentID: "payment-1", Attempts: 1, Payload: []byte(`{"payment_id":"payment-1"}`)},
+			{ID: 2, PaymentID: "payment-2", Payload: []byte(`{"payment_id":"payment-2"}`)},
+		}, nil)
+		before := time.Now()
+		repo.On("ScheduleRetry", mock.Anything, int64(1), "webhook returned status 503", mock.MatchedBy(func(next time.Time) bool {
+			delay := next.Sub(before)
+			return delay >= time.Second && delay <= 2*time.Second+100*time.Millisecond
+		})).Return(nil)
+		repo.On("MarkDelivered", mock.Anything, int64(2)).Return(nil)
+
+		dispatcher := NewWebhookDispatcher(repo, sender, testPolicy, time.Second, 10)
+		delivered, err := dispatcher.ProcessBatch(context.Background())
+
+		assert.NoError(t, err)
+		assert.Equal(t, 1, delivered)
+		repo.AssertExpectations(t)
+	})
+
+	t.Run("moves delivery to dead letters after the last attempt", func(t *testing.T) {
+		repo := new(MockWebhookRepository)
+		sender := &MockSender{failBody: `{"payment_id":"payment-1"}`}
+		repo.On("ClaimDueDeliveries", mock.Anything, 10, time.Minute).Return([]domain.WebhookDelivery{
+			{ID: 1, PaymentID: "payment-1", Attempts: 2, Payload: []byte(`{"payment_id":"payment-1"}`)},
+		}, nil)
+		repo.On("MoveToDeadLetter", mock.Anything, int64(1), "webhook returned status 503").Return(nil)
+
+		dispatcher := NewWebhookDispatcher(repo, sender, testPolicy, time.Second, 10)
+		delivered, err := dispatcher.ProcessBatch(context.Background())
+
+		assert.NoError(t, err)
+		assert.Equal(t, 0, delivered)
+		repo.AssertNotCalled(t, "ScheduleRetry", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+		repo.AssertExpectations(t)
+	})
+
+	t.Run("repository error", func(t *testing.T) {
+		repo := new(MockWebhookRepository)
+		repo.On("ClaimDueDeliveries", mock.Anything, 10, time.Minute).Return(nil, errors.New("database error"))
+
+		dispatcher := NewWebhookDispatcher(repo, &MockSender{}, testPolicy, time.Second, 10)
+		delivered, err := dispatcher.ProcessBatch(context.Background())
+
+		assert.Error(t, err)
+		assert.Equal(t, 0, delivered)
+	})
+}
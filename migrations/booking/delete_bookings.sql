@@ -1 +1,11 @@
+DROP TABLE IF EXISTS waitlist_entries;
+DROP TABLE IF EXISTS promotions;
+DROP TABLE IF EXISTS exchange_rates;
+DROP TABLE IF EXISTS room_holds;
+DROP TABLE IF EXISTS idempotency_keys;
+DROP TABLE IF EXISTS booking_sagas;
+DROP TABLE IF EXISTS booking_outbox;
+DROP TABLE IF EXISTS booking_modifications;
+DROP TABLE IF EXISTS booking_status_history;
 DROP TABLE IF EXISTS bookings;
+DROP TABLE IF EXISTS reservations;
@@ -1,20 +1,178 @@
+CREATE EXTENSION IF NOT EXISTS btree_gist;
+
+CREATE TABLE IF NOT EXISTS reservations (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    user_id VARCHAR(255) NOT NULL,
+    hotel_id UUID NOT NULL,
+    total_price DECIMAL(10, 2) NOT NULL,
+    currency VARCHAR(3) NOT NULL DEFAULT 'RUB',
+    display_price DECIMAL(10, 2) NOT NULL,
+    display_currency VARCHAR(3) NOT NULL DEFAULT 'RUB',
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
 CREATE TABLE IF NOT EXISTS bookings (
     id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
     user_id VARCHAR(255) NOT NULL,
     hotel_id UUID NOT NULL,
     room_id UUID NOT NULL,
+    room_type VARCHAR(100) NOT NULL DEFAULT '',
+    reservation_id UUID REFERENCES reservations(id),
+    guest_name VARCHAR(255) NOT NULL DEFAULT '',
+    adults INT NOT NULL DEFAULT 1,
+    children INT NOT NULL DEFAULT 0,
     check_in_date DATE NOT NULL,
     check_out_date DATE NOT NULL,
     total_price DECIMAL(10, 2) NOT NULL,
+    currency VARCHAR(3) NOT NULL DEFAULT 'RUB',
+    price_breakdown JSONB NOT NULL DEFAULT '[]',
+    display_price DECIMAL(10, 2) NOT NULL,
+    display_currency VARCHAR(3) NOT NULL DEFAULT 'RUB',
+    exchange_rate DECIMAL(18, 8) NOT NULL DEFAULT 1,
+    promo_code VARCHAR(50),
+    cancellation_policy JSONB,
+    checked_in_at TIMESTAMP,
+    checked_in_by VARCHAR(255),
+    checked_out_at TIMESTAMP,
+    checked_out_by VARCHAR(255),
+    no_show_at TIMESTAMP,
+    no_show_by VARCHAR(255),
     status VARCHAR(50) DEFAULT 'pending',
     payment_status VARCHAR(50) DEFAULT 'pending',
     created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT bookings_no_overlap EXCLUDE USING gist (
+        room_id WITH =,
+        daterange(check_in_date, check_out_date) WITH &&
+    ) WHERE (status NOT IN ('cancelled', 'expired'))
+);
+
+CREATE TABLE IF NOT EXISTS booking_status_history (
+    id BIGSERIAL PRIMARY KEY,
+    booking_id UUID NOT NULL REFERENCES bookings(id) ON DELETE CASCADE,
+    field VARCHAR(50) NOT NULL,
+    from_status VARCHAR(50) NOT NULL,
+    to_status VARCHAR(50) NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE IF NOT EXISTS booking_modifications (
+    id UUID PRIMARY KEY,
+    booking_id UUID NOT NULL REFERENCES bookings(id) ON DELETE CASCADE,
+    previous JSONB NOT NULL,
+    additional_charge DECIMAL(10, 2),
+    refund_amount DECIMAL(10, 2),
+    currency VARCHAR(3) NOT NULL,
+    payment_id VARCHAR(255),
+    status VARCHAR(50) NOT NULL DEFAULT 'pending',
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
     updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
 );
 
+CREATE TABLE IF NOT EXISTS booking_outbox (
+    id BIGSERIAL PRIMARY KEY,
+    topic VARCHAR(255) NOT NULL,
+    key VARCHAR(255) NOT NULL,
+    payload JSONB NOT NULL,
+    attempts INTEGER NOT NULL DEFAULT 0,
+    last_error TEXT,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    sent_at TIMESTAMP
+);
+
+CREATE TABLE IF NOT EXISTS booking_sagas (
+    booking_id UUID PRIMARY KEY,
+    step VARCHAR(50) NOT NULL,
+    status VARCHAR(50) NOT NULL,
+    last_error TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE IF NOT EXISTS idempotency_keys (
+    key VARCHAR(255) PRIMARY KEY,
+    fingerprint VARCHAR(64) NOT NULL,
+    status_code INTEGER NOT NULL DEFAULT 0,
+    content_type VARCHAR(255) NOT NULL DEFAULT '',
+    response_body BYTEA NOT NULL DEFAULT '',
+    completed BOOLEAN NOT NULL DEFAULT FALSE,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE IF NOT EXISTS room_holds (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    user_id VARCHAR(255) NOT NULL,
+    hotel_id UUID NOT NULL,
+    room_id UUID NOT NULL,
+    check_in_date DATE NOT NULL,
+    check_out_date DATE NOT NULL,
+    status VARCHAR(50) NOT NULL DEFAULT 'active',
+    booking_id UUID,
+    expires_at TIMESTAMP NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT room_holds_no_overlap EXCLUDE USING gist (
+        room_id WITH =,
+        daterange(check_in_date, check_out_date) WITH &&
+    ) WHERE (status = 'active')
+);
+
+CREATE TABLE IF NOT EXISTS exchange_rates (
+    base_currency VARCHAR(3) NOT NULL,
+    quote_currency VARCHAR(3) NOT NULL,
+    rate DECIMAL(18, 8) NOT NULL CHECK (rate > 0),
+    effective_from TIMESTAMP NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (base_currency, quote_currency, effective_from)
+);
+
+CREATE TABLE IF NOT EXISTS promotions (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    code VARCHAR(50) NOT NULL UNIQUE,
+    description TEXT NOT NULL DEFAULT '',
+    type VARCHAR(20) NOT NULL,
+    basis_points BIGINT NOT NULL DEFAULT 0,
+    amount DECIMAL(10, 2),
+    currency VARCHAR(3),
+    stay_nights INT NOT NULL DEFAULT 0,
+    pay_nights INT NOT NULL DEFAULT 0,
+    hotel_id UUID,
+    room_types TEXT[] NOT NULL DEFAULT '{}',
+    valid_from TIMESTAMP,
+    valid_until TIMESTAMP,
+    max_uses INT NOT NULL DEFAULT 0,
+    max_uses_per_user INT NOT NULL DEFAULT 0,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE IF NOT EXISTS waitlist_entries (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    user_id VARCHAR(255) NOT NULL,
+    hotel_id UUID NOT NULL,
+    room_type VARCHAR(100) NOT NULL,
+    check_in_date DATE NOT NULL,
+    check_out_date DATE NOT NULL,
+    status VARCHAR(50) NOT NULL DEFAULT 'waiting',
+    room_id UUID,
+    hold_id UUID REFERENCES room_holds(id),
+    offer_expires_at TIMESTAMP,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
 CREATE INDEX IF NOT EXISTS idx_bookings_user_id ON bookings(user_id);
 CREATE INDEX IF NOT EXISTS idx_bookings_hotel_id ON bookings(hotel_id);
 CREATE INDEX IF NOT EXISTS idx_bookings_room_id ON bookings(room_id);
+CREATE INDEX IF NOT EXISTS idx_idempotency_keys_created_at ON idempotency_keys(created_at);
 CREATE INDEX IF NOT EXISTS idx_bookings_status ON bookings(status);
 CREATE INDEX IF NOT EXISTS idx_bookings_dates ON bookings(check_in_date, check_out_date);
-
+CREATE INDEX IF NOT EXISTS idx_booking_status_history_booking_id ON booking_status_history(booking_id);
+CREATE INDEX IF NOT EXISTS idx_booking_outbox_pending ON booking_outbox(id) WHERE sent_at IS NULL;
+CREATE INDEX IF NOT EXISTS idx_booking_sagas_running ON booking_sagas(updated_at) WHERE status = 'running';
+CREATE INDEX IF NOT EXISTS idx_room_holds_room_id ON room_holds(room_id);
+CREATE INDEX IF NOT EXISTS idx_room_holds_expiring ON room_holds(expires_at) WHERE status = 'active';
+CREATE INDEX IF NOT EXISTS idx_bookings_promo_code ON bookings(promo_code) WHERE promo_code IS NOT NULL;
+CREATE INDEX IF NOT EXISTS idx_bookings_reservation_id ON bookings(reservation_id) WHERE reservation_id IS NOT NULL;
+CREATE INDEX IF NOT EXISTS idx_bookings_awaiting_arrival ON bookings(check_in_date) WHERE status = 'confirmed';
+CREATE INDEX IF NOT EXISTS idx_bookings_awaiting_payment ON bookings(updated_at) WHERE status = 'awaiting_payment';
+CREATE INDEX IF NOT EXISTS idx_waitlist_entries_waiting ON waitlist_entries(hotel_id, room_type, created_at) WHERE status = 'waiting';
+CREATE UNIQUE INDEX IF NOT EXISTS idx_waitlist_entries_hold_id ON waitlist_entries(hold_id) WHERE hold_id IS NOT NULL;
+CREATE UNIQUE INDEX IF NOT EXISTS idx_booking_modifications_pending ON booking_modifications(booking_id) WHERE status = 'pending';
@@ -1,2 +1,5 @@
+DROP TABLE IF EXISTS fee_rules;
+DROP TABLE IF EXISTS rate_plans;
 DROP TABLE IF EXISTS rooms;
+DROP TABLE IF EXISTS cancellation_policies;
 DROP TABLE IF EXISTS hotels;
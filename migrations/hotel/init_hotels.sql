@@ -4,24 +4,71 @@ CREATE TABLE IF NOT EXISTS hotels (
     description TEXT,
     address TEXT NOT NULL,
     owner_id UUID NOT NULL,
+    currency VARCHAR(3) NOT NULL DEFAULT 'RUB',
     created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
     updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
 );
 
+CREATE TABLE IF NOT EXISTS cancellation_policies (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    hotel_id UUID NOT NULL REFERENCES hotels(id) ON DELETE CASCADE,
+    name VARCHAR(255) NOT NULL,
+    rules JSONB NOT NULL DEFAULT '[]',
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
 CREATE TABLE IF NOT EXISTS rooms (
     id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
     hotel_id UUID NOT NULL REFERENCES hotels(id) ON DELETE CASCADE,
     room_number VARCHAR(50) NOT NULL,
     room_type VARCHAR(100) NOT NULL,
     price_per_night DECIMAL(10, 2) NOT NULL,
+    currency VARCHAR(3) NOT NULL DEFAULT 'RUB',
     capacity INT NOT NULL,
+    base_occupancy INT NOT NULL DEFAULT 0,
+    extra_adult_price DECIMAL(10, 2),
+    extra_child_price DECIMAL(10, 2),
     description TEXT,
     is_available BOOLEAN DEFAULT TRUE,
+    cancellation_policy_id UUID REFERENCES cancellation_policies(id),
     created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
     updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
     UNIQUE(hotel_id, room_number)
 );
 
+CREATE TABLE IF NOT EXISTS rate_plans (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    room_id UUID NOT NULL REFERENCES rooms(id) ON DELETE CASCADE,
+    name VARCHAR(255) NOT NULL,
+    price_per_night DECIMAL(10, 2) NOT NULL,
+    currency VARCHAR(3) NOT NULL DEFAULT 'RUB',
+    start_date DATE,
+    end_date DATE,
+    days_of_week SMALLINT[] NOT NULL DEFAULT '{}',
+    min_nights INT NOT NULL DEFAULT 0,
+    priority INT NOT NULL DEFAULT 0,
+    cancellation_policy_id UUID REFERENCES cancellation_policies(id),
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    CHECK (start_date IS NULL OR end_date IS NULL OR start_date <= end_date)
+);
+
+CREATE TABLE IF NOT EXISTS fee_rules (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    hotel_id UUID NOT NULL REFERENCES hotels(id) ON DELETE CASCADE,
+    name VARCHAR(255) NOT NULL,
+    kind VARCHAR(20) NOT NULL,
+    calculation VARCHAR(20) NOT NULL,
+    basis_points BIGINT NOT NULL DEFAULT 0,
+    amount DECIMAL(10, 2),
+    currency VARCHAR(3),
+    per_night BOOLEAN NOT NULL DEFAULT FALSE,
+    per_guest BOOLEAN NOT NULL DEFAULT FALSE,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
 CREATE INDEX IF NOT EXISTS idx_hotels_owner_id ON hotels(owner_id);
 CREATE INDEX IF NOT EXISTS idx_rooms_hotel_id ON rooms(hotel_id);
 CREATE INDEX IF NOT EXISTS idx_rooms_is_available ON rooms(is_available);
+CREATE INDEX IF NOT EXISTS idx_rate_plans_room_id ON rate_plans(room_id);
+CREATE INDEX IF NOT EXISTS idx_fee_rules_hotel_id ON fee_rules(hotel_id);
+CREATE INDEX IF NOT EXISTS idx_cancellation_policies_hotel_id ON cancellation_policies(hotel_id);
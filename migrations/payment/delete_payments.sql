@@ -0,0 +1,5 @@
+DROP TABLE IF EXISTS webhook_dead_letters;
+DROP TABLE IF EXISTS webhook_deliveries;
+DROP TABLE IF EXISTS idempotency_keys;
+DROP TABLE IF EXISTS refunds;
+DROP TABLE IF EXISTS payments;
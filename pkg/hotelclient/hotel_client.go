@@ -1,13 +1,22 @@
 package hotelclient
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
+	"time"
+
+	"hotel-booking-system/pkg/money"
 )
 
+// ErrCapacityExceeded is returned by GetQuote when the guests do not fit into
+// the room.
+var ErrCapacityExceeded = errors.New("number of guests exceeds room capacity")
+
 type HotelClient struct {
 	baseURL string
 }
@@ -18,48 +27,133 @@ func NewHotelClient(addr string) (*HotelClient, error) {
 	}, nil
 }
 
-func (c *HotelClient) GetRoomPrice(ctx context.Context, hotelID, roomID string) (float64, error) {
-	url := fmt.Sprintf("%s/api/hotels/%s/rooms", c.baseURL, hotelID)
-	
-	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+// NightlyRate is the price of a night including ExtraGuestCharge, the
+// surcharge for guests above the room's base occupancy.
+type NightlyRate struct {
+	Date             time.Time   `json:"date"`
+	Price            money.Money `json:"price"`
+	ExtraGuestCharge money.Money `json:"extra_guest_charge"`
+	RatePlanID       string      `json:"rate_plan_id,omitempty"`
+}
+
+// Fee is a tax or fee the hotel adds to the accommodation price; Kind is "tax"
+// or "fee". A percentage fee takes BasisPoints of the accommodation price.
+type Fee struct {
+	Name        string      `json:"name"`
+	Kind        string      `json:"kind"`
+	BasisPoints int64       `json:"basis_points,omitempty"`
+	Amount      money.Money `json:"amount"`
+}
+
+type CancellationRule struct {
+	HoursBeforeCheckIn int   `json:"hours_before_check_in"`
+	RefundBasisPoints  int64 `json:"refund_basis_points"`
+}
+
+// CancellationPolicy is nil in a quote for a fully refundable stay.
+type CancellationPolicy struct {
+	ID    string             `json:"id"`
+	Name  string             `json:"name"`
+	Rules []CancellationRule `json:"rules"`
+}
+
+type Quote struct {
+	RoomType           string              `json:"room_type"`
+	Capacity           int                 `json:"capacity"`
+	Nights             []NightlyRate       `json:"nights"`
+	Subtotal           money.Money         `json:"subtotal"`
+	Fees               []Fee               `json:"fees"`
+	Total              money.Money         `json:"total"`
+	CancellationPolicy *CancellationPolicy `json:"cancellation_policy,omitempty"`
+}
+
+type quoteRequest struct {
+	CheckIn  time.Time `json:"check_in"`
+	CheckOut time.Time `json:"check_out"`
+	Adults   int       `json:"adults"`
+	Children int       `json:"children"`
+}
+
+// GetQuote asks the hotel service to price every night of the stay for the
+// given guests, taxes and fees included.
+func (c *HotelClient) GetQuote(ctx context.Context, hotelID, roomID string, checkIn, checkOut time.Time, adults, children int) (*Quote, error) {
+	url := fmt.Sprintf("%s/api/hotels/%s/rooms/%s/quote", c.baseURL, hotelID, roomID)
+
+	payload, err := json.Marshal(quoteRequest{CheckIn: checkIn, CheckOut: checkOut, Adults: adults, Children: children})
 	if err != nil {
-		return 0, err
+		return nil, err
 	}
-	
+
+	req, err := http.NewRequestWithContext(ctx, "POST", url, bytes.NewReader(payload))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
 	resp, err := http.DefaultClient.Do(req)
 	if err != nil {
-		return 0, err
+		return nil, err
 	}
 	defer resp.Body.Close()
 
 	body, err := io.ReadAll(resp.Body)
 	if err != nil {
-		return 0, err
+		return nil, err
 	}
 
+	if resp.StatusCode == http.StatusUnprocessableEntity {
+		return nil, fmt.Errorf("%w: %s", ErrCapacityExceeded, string(body))
+	}
 	if resp.StatusCode != http.StatusOK {
-		return 0, fmt.Errorf("hotel service returned status %d: %s", resp.StatusCode, string(body))
+		return nil, fmt.Errorf("hotel service returned status %d: %s", resp.StatusCode, string(body))
+	}
+
+	var quote Quote
+	if err := json.Unmarshal(body, &quote); err != nil {
+		return nil, fmt.Errorf("failed to parse hotel service response: %w", err)
+	}
+	return &quote, nil
+}
+
+// Room is a room of a hotel as the hotel service lists it. A room that is not
+// available is not let at all.
+type Room struct {
+	ID          string `json:"id"`
+	RoomType    string `json:"room_type"`
+	IsAvailable bool   `json:"is_available"`
+}
+
+// GetRooms lists all the rooms of the hotel.
+func (c *HotelClient) GetRooms(ctx context.Context, hotelID string) ([]Room, error) {
+	url := fmt.Sprintf("%s/api/hotels/%s/rooms", c.baseURL, hotelID)
+
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+	if err != nil {
+		return nil, err
 	}
 
-	var result struct {
-		Hotel struct{} `json:"hotel"`
-		Rooms []struct {
-			ID            string  `json:"id"`
-			PricePerNight float64 `json:"price_per_night"`
-		} `json:"rooms"`
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, err
 	}
+	defer resp.Body.Close()
 
-	if err := json.Unmarshal(body, &result); err != nil {
-		return 0, fmt.Errorf("failed to parse hotel service response: %w", err)
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
 	}
 
-	for _, room := range result.Rooms {
-		if room.ID == roomID {
-			return room.PricePerNight, nil
-		}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("hotel service returned status %d: %s", resp.StatusCode, string(body))
 	}
 
-	return 0, fmt.Errorf("room %s not found in hotel %s", roomID, hotelID)
+	var hotel struct {
+		Rooms []Room `json:"rooms"`
+	}
+	if err := json.Unmarshal(body, &hotel); err != nil {
+		return nil, fmt.Errorf("failed to parse hotel service response: %w", err)
+	}
+	return hotel.Rooms, nil
 }
 
 func (c *HotelClient) Close() error {
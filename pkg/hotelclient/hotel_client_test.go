@@ -2,9 +2,17 @@ package hotelclient
 
 import (
 	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
 	"testing"
+	"time"
+
+	"hotel-booking-system/pkg/money"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestNewHotelClient(t *testing.T) {
@@ -14,16 +22,113 @@ func TestNewHotelClient(t *testing.T) {
 	client.Close()
 }
 
-func TestHotelClient_GetRoomPrice(t *testing.T) {
+func TestHotelClient_GetQuote(t *testing.T) {
 	client, err := NewHotelClient("localhost:8081")
 	assert.NoError(t, err)
 
-	_, err = client.GetRoomPrice(context.Background(), "hotel-id", "room-id")
+	_, err = client.GetQuote(context.Background(), "hotel-id", "room-id", time.Now(), time.Now().AddDate(0, 0, 1), 1, 0)
 	assert.Error(t, err)
 
 	client.Close()
 }
 
+func TestHotelClient_GetQuoteFromResponse(t *testing.T) {
+	checkIn := time.Date(2024, 12, 20, 14, 0, 0, 0, time.UTC)
+	checkOut := time.Date(2024, 12, 22, 12, 0, 0, 0, time.UTC)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "POST", r.Method)
+		assert.Equal(t, "/api/hotels/hotel-id/rooms/room-id/quote", r.URL.Path)
+		body, _ := io.ReadAll(r.Body)
+		assert.JSONEq(t, `{"check_in":"2024-12-20T14:00:00Z","check_out":"2024-12-22T12:00:00Z","adults":2,"children":1}`, string(body))
+		w.Write([]byte(`{"room_type":"Deluxe","capacity":3,"nights":[
+			{"date":"2024-12-20T00:00:00Z","price":{"amount":"7000.00","currency":"RUB"},"extra_guest_charge":{"amount":"500.00","currency":"RUB"},"rate_plan_id":"weekend"},
+			{"date":"2024-12-21T00:00:00Z","price":{"amount":"3333.33","currency":"RUB"}}
+		],"subtotal":{"amount":"10333.33","currency":"RUB"},
+		"fees":[{"fee_rule_id":"tax","name":"Туристический налог","kind":"tax","amount":{"amount":"206.67","currency":"RUB"}}],
+		"total":{"amount":"10540.00","currency":"RUB"}}`))
+	}))
+	defer server.Close()
+
+	client, err := NewHotelClient(strings.TrimPrefix(server.URL, "http://"))
+	require.NoError(t, err)
+
+	quote, err := client.GetQuote(context.Background(), "hotel-id", "room-id", checkIn, checkOut, 2, 1)
+	require.NoError(t, err)
+	assert.Equal(t, "Deluxe", quote.RoomType)
+	assert.Equal(t, 3, quote.Capacity)
+	require.Len(t, quote.Nights, 2)
+	assert.Equal(t, "weekend", quote.Nights[0].RatePlanID)
+	assert.Equal(t, money.New(50000, "RUB"), quote.Nights[0].ExtraGuestCharge)
+	assert.Equal(t, money.New(333333, "RUB"), quote.Nights[1].Price)
+	assert.Equal(t, money.New(1033333, "RUB"), quote.Subtotal)
+	require.Len(t, quote.Fees, 1)
+	assert.Equal(t, "tax", quote.Fees[0].Kind)
+	assert.Equal(t, money.New(20667, "RUB"), quote.Fees[0].Amount)
+	assert.Equal(t, money.New(1054000, "RUB"), quote.Total)
+}
+
+func TestHotelClient_GetQuoteError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "room not found in this hotel", http.StatusNotFound)
+	}))
+	defer server.Close()
+
+	client, err := NewHotelClient(strings.TrimPrefix(server.URL, "http://"))
+	require.NoError(t, err)
+
+	_, err = client.GetQuote(context.Background(), "hotel-id", "room-id", time.Now(), time.Now().AddDate(0, 0, 1), 1, 0)
+	assert.ErrorContains(t, err, "404")
+}
+
+func TestHotelClient_GetQuoteCapacityExceeded(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "number of guests exceeds room capacity", http.StatusUnprocessableEntity)
+	}))
+	defer server.Close()
+
+	client, err := NewHotelClient(strings.TrimPrefix(server.URL, "http://"))
+	require.NoError(t, err)
+
+	_, err = client.GetQuote(context.Background(), "hotel-id", "room-id", time.Now(), time.Now().AddDate(0, 0, 1), 6, 0)
+	assert.ErrorIs(t, err, ErrCapacityExceeded)
+}
+
+func TestHotelClient_GetRooms(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "GET", r.Method)
+		assert.Equal(t, "/api/hotels/hotel-id/rooms", r.URL.Path)
+		w.Write([]byte(`{"hotel":{"id":"hotel-id","name":"Гранд"},"rooms":[
+			{"id":"room-1","hotel_id":"hotel-id","room_type":"Deluxe","is_available":true},
+			{"id":"room-2","hotel_id":"hotel-id","room_type":"Standard","is_available":false}
+		]}`))
+	}))
+	defer server.Close()
+
+	client, err := NewHotelClient(strings.TrimPrefix(server.URL, "http://"))
+	require.NoError(t, err)
+
+	rooms, err := client.GetRooms(context.Background(), "hotel-id")
+	require.NoError(t, err)
+	assert.Equal(t, []Room{
+		{ID: "room-1", RoomType: "Deluxe", IsAvailable: true},
+		{ID: "room-2", RoomType: "Standard", IsAvailable: false},
+	}, rooms)
+}
+
+func TestHotelClient_GetRoomsError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "hotel not found", http.StatusNotFound)
+	}))
+	defer server.Close()
+
+	client, err := NewHotelClient(strings.TrimPrefix(server.URL, "http://"))
+	require.NoError(t, err)
+
+	_, err = client.GetRooms(context.Background(), "hotel-id")
+	assert.ErrorContains(t, err, "404")
+}
+
 func TestHotelClient_Close(t *testing.T) {
 	client, err := NewHotelClient("localhost:8081")
 	assert.NoError(t, err)
@@ -0,0 +1,59 @@
+package httpclient
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+type BookingHTTPClient struct {
+	baseURL string
+	client  *http.Client
+}
+
+func NewBookingHTTPClient(baseURL string) *BookingHTTPClient {
+	return &BookingHTTPClient{
+		baseURL: baseURL,
+		client: &http.Client{
+			Timeout: 10 * time.Second,
+		},
+	}
+}
+
+type BookedRoomsResponse struct {
+	RoomIDs []string `json:"room_ids"`
+}
+
+func (c *BookingHTTPClient) GetBookedRoomIDs(ctx context.Context, hotelID string, checkIn, checkOut time.Time) ([]string, error) {
+	params := url.Values{}
+	params.Set("check_in", checkIn.Format("2006-01-02"))
+	params.Set("check_out", checkOut.Format("2006-01-02"))
+	url := fmt.Sprintf("%s/api/bookings/hotel/%s/booked-rooms?%s", c.baseURL, hotelID, params.Encode())
+
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get booked rooms: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("booking service returned status %d: %s", resp.StatusCode, string(body))
+	}
+
+	var result BookedRoomsResponse
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, fmt.Errorf("failed to decode booked rooms: %w", err)
+	}
+
+	return result.RoomIDs, nil
+}
@@ -0,0 +1,78 @@
+package httpclient
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"hotel-booking-system/pkg/logger"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewBookingHTTPClient(t *testing.T) {
+	logger.Init("info")
+
+	client := NewBookingHTTPClient("http://example.com")
+	assert.NotNil(t, client)
+	assert.Equal(t, "http://example.com", client.baseURL)
+}
+
+func TestBookingHTTPClient_GetBookedRoomIDs(t *testing.T) {
+	logger.Init("info")
+
+	checkIn := time.Date(2024, 12, 20, 14, 0, 0, 0, time.UTC)
+	checkOut := time.Date(2024, 12, 25, 12, 0, 0, 0, time.UTC)
+
+	t.Run("success", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			assert.Equal(t, "GET", r.Method)
+			assert.Equal(t, "/api/bookings/hotel/hotel-123/booked-rooms", r.URL.Path)
+			assert.Equal(t, "2024-12-20", r.URL.Query().Get("check_in"))
+			assert.Equal(t, "2024-12-25", r.URL.Query().Get("check_out"))
+
+			w.WriteHeader(http.StatusOK)
+			json.NewEncoder(w).Encode(BookedRoomsResponse{RoomIDs: []string{"room-1", "room-2"}})
+		}))
+		defer server.Close()
+
+		client := NewBookingHTTPClient(server.URL)
+
+		roomIDs, err := client.GetBookedRoomIDs(context.Background(), "hotel-123", checkIn, checkOut)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"room-1", "room-2"}, roomIDs)
+	})
+
+	t.Run("service error", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusInternalServerError)
+			w.Write([]byte("database unavailable"))
+		}))
+		defer server.Close()
+
+		client := NewBookingHTTPClient(server.URL)
+
+		roomIDs, err := client.GetBookedRoomIDs(context.Background(), "hotel-123", checkIn, checkOut)
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "status 500")
+		assert.Nil(t, roomIDs)
+	})
+
+	t.Run("invalid JSON response", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusOK)
+			w.Write([]byte("invalid json"))
+		}))
+		defer server.Close()
+
+		client := NewBookingHTTPClient(server.URL)
+
+		roomIDs, err := client.GetBookedRoomIDs(context.Background(), "hotel-123", checkIn, checkOut)
+		assert.Error(t, err)
+		assert.Nil(t, roomIDs)
+	})
+}
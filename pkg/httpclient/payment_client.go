@@ -9,6 +9,7 @@ import (
 	"time"
 
 	"hotel-booking-system/pkg/logger"
+	"hotel-booking-system/pkg/money"
 )
 
 type PaymentClient struct {
@@ -26,9 +27,12 @@ func NewPaymentClient(baseURL string) *PaymentClient {
 }
 
 type PaymentRequest struct {
-	BookingID string  `json:"booking_id"`
-	Amount    float64 `json:"amount"`
-	Currency  string  `json:"currency,omitempty"`
+	BookingID   string      `json:"booking_id"`
+	Amount      money.Money `json:"amount"`
+	CaptureMode string      `json:"capture_mode,omitempty"`
+	// IdempotencyKey identifies the charge; it defaults to one key per
+	// booking, so extra charges of a booking must set their own.
+	IdempotencyKey string `json:"-"`
 }
 
 type PaymentResponse struct {
@@ -51,6 +55,11 @@ func (c *PaymentClient) CreatePayment(ctx context.Context, req *PaymentRequest)
 	}
 
 	httpReq.Header.Set("Content-Type", "application/json")
+	idempotencyKey := req.IdempotencyKey
+	if idempotencyKey == "" {
+		idempotencyKey = "payment-" + req.BookingID
+	}
+	httpReq.Header.Set("Idempotency-Key", idempotencyKey)
 
 	resp, err := c.client.Do(httpReq)
 	if err != nil {
@@ -70,3 +79,79 @@ func (c *PaymentClient) CreatePayment(ctx context.Context, req *PaymentRequest)
 
 	return &paymentResp, nil
 }
+
+type RefundRequest struct {
+	BookingID string      `json:"booking_id"`
+	Amount    money.Money `json:"amount"`
+}
+
+type RefundResponse struct {
+	RefundID string `json:"refund_id"`
+	Status   string `json:"status"`
+	Message  string `json:"message,omitempty"`
+}
+
+func (c *PaymentClient) RefundPayment(ctx context.Context, req *RefundRequest) (*RefundResponse, error) {
+	url := fmt.Sprintf("%s/api/payments/refunds", c.baseURL)
+
+	data, err := json.Marshal(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal request: %w", err)
+	}
+
+	httpReq, err := http.NewRequestWithContext(ctx, "POST", url, bytes.NewBuffer(data))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	httpReq.Header.Set("Content-Type", "application/json")
+
+	resp, err := c.client.Do(httpReq)
+	if err != nil {
+		logger.GetLogger().WithError(err).Error("failed to request refund")
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusAccepted {
+		return nil, fmt.Errorf("payment service returned status %d", resp.StatusCode)
+	}
+
+	var refundResp RefundResponse
+	if err := json.NewDecoder(resp.Body).Decode(&refundResp); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+
+	return &refundResp, nil
+}
+
+// CapturePayment captures the authorized payment of a booking.
+func (c *PaymentClient) CapturePayment(ctx context.Context, bookingID string) error {
+	return c.bookingPaymentAction(ctx, bookingID, "capture")
+}
+
+// VoidPayment releases the authorized payment of a booking.
+func (c *PaymentClient) VoidPayment(ctx context.Context, bookingID string) error {
+	return c.bookingPaymentAction(ctx, bookingID, "void")
+}
+
+func (c *PaymentClient) bookingPaymentAction(ctx context.Context, bookingID, action string) error {
+	url := fmt.Sprintf("%s/api/payments/booking/%s/%s", c.baseURL, bookingID, action)
+
+	httpReq, err := http.NewRequestWithContext(ctx, "POST", url, nil)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+
+	resp, err := c.client.Do(httpReq)
+	if err != nil {
+		logger.GetLogger().WithError(err).Errorf("failed to %s payment", action)
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("payment service returned status %d", resp.StatusCode)
+	}
+	return nil
+}
@@ -8,6 +8,7 @@ import (
 	"testing"
 
 	"hotel-booking-system/pkg/logger"
+	"hotel-booking-system/pkg/money"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -29,11 +30,12 @@ func TestPaymentClient_CreatePayment(t *testing.T) {
 			assert.Equal(t, "POST", r.Method)
 			assert.Equal(t, "/api/payments", r.URL.Path)
 			assert.Equal(t, "application/json", r.Header.Get("Content-Type"))
+			assert.Equal(t, "payment-booking-123", r.Header.Get("Idempotency-Key"))
 
 			var req PaymentRequest
 			json.NewDecoder(r.Body).Decode(&req)
 			assert.Equal(t, "booking-123", req.BookingID)
-			assert.Equal(t, 1000.0, req.Amount)
+			assert.Equal(t, money.New(100000, "RUB"), req.Amount)
 
 			w.WriteHeader(http.StatusAccepted)
 			json.NewEncoder(w).Encode(PaymentResponse{
@@ -48,8 +50,7 @@ func TestPaymentClient_CreatePayment(t *testing.T) {
 
 		req := &PaymentRequest{
 			BookingID: "booking-123",
-			Amount:    1000.0,
-			Currency:  "RUB",
+			Amount:    money.New(100000, "RUB"),
 		}
 
 		response, err := client.CreatePayment(context.Background(), req)
@@ -68,7 +69,7 @@ func TestPaymentClient_CreatePayment(t *testing.T) {
 
 		req := &PaymentRequest{
 			BookingID: "booking-123",
-			Amount:    1000.0,
+			Amount:    money.New(100000, "RUB"),
 		}
 
 		response, err := client.CreatePayment(context.Background(), req)
@@ -87,7 +88,7 @@ func TestPaymentClient_CreatePayment(t *testing.T) {
 
 		req := &PaymentRequest{
 			BookingID: "booking-123",
-			Amount:    1000.0,
+			Amount:    money.New(100000, "RUB"),
 		}
 
 		response, err := client.CreatePayment(context.Background(), req)
@@ -101,7 +102,7 @@ func TestPaymentClient_CreatePayment(t *testing.T) {
 
 		req := &PaymentRequest{
 			BookingID: "booking-123",
-			Amount:    1000.0,
+			Amount:    money.New(100000, "RUB"),
 		}
 
 		ctx, cancel := context.WithTimeout(context.Background(), 1)
@@ -123,7 +124,7 @@ func TestPaymentClient_CreatePayment(t *testing.T) {
 
 		req := &PaymentRequest{
 			BookingID: "booking-123",
-			Amount:    1000.0,
+			Amount:    money.New(100000, "RUB"),
 		}
 
 		response, err := client.CreatePayment(context.Background(), req)
@@ -135,8 +136,7 @@ func TestPaymentClient_CreatePayment(t *testing.T) {
 func TestPaymentRequest(t *testing.T) {
 	req := &PaymentRequest{
 		BookingID: "booking-123",
-		Amount:    1000.0,
-		Currency:  "RUB",
+		Amount:    money.New(100000, "RUB"),
 	}
 
 	data, err := json.Marshal(req)
@@ -148,5 +148,95 @@ func TestPaymentRequest(t *testing.T) {
 
 	assert.Equal(t, req.BookingID, unmarshaled.BookingID)
 	assert.Equal(t, req.Amount, unmarshaled.Amount)
-	assert.Equal(t, req.Currency, unmarshaled.Currency)
+}
+
+func TestPaymentClient_RefundPayment(t *testing.T) {
+	logger.Init("info")
+
+	t.Run("success", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			assert.Equal(t, "POST", r.Method)
+			assert.Equal(t, "/api/payments/refunds", r.URL.Path)
+
+			var req RefundRequest
+			json.NewDecoder(r.Body).Decode(&req)
+			assert.Equal(t, "booking-123", req.BookingID)
+			assert.Equal(t, money.New(100000, "RUB"), req.Amount)
+
+			w.WriteHeader(http.StatusAccepted)
+			json.NewEncoder(w).Encode(RefundResponse{
+				RefundID: "refund-123",
+				Status:   "processing",
+			})
+		}))
+		defer server.Close()
+
+		client := NewPaymentClient(server.URL)
+
+		response, err := client.RefundPayment(context.Background(), &RefundRequest{
+			BookingID: "booking-123",
+			Amount:    money.New(100000, "RUB"),
+		})
+		require.NoError(t, err)
+		assert.Equal(t, "refund-123", response.RefundID)
+	})
+
+	t.Run("server error", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusInternalServerError)
+		}))
+		defer server.Close()
+
+		client := NewPaymentClient(server.URL)
+
+		response, err := client.RefundPayment(context.Background(), &RefundRequest{BookingID: "booking-123", Amount: money.New(100000, "RUB")})
+		assert.Error(t, err)
+		assert.Nil(t, response)
+		assert.Contains(t, err.Error(), "status 500")
+	})
+}
+
+func TestPaymentClient_CapturePayment(t *testing.T) {
+	logger.Init("info")
+
+	t.Run("success", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			assert.Equal(t, "POST", r.Method)
+			assert.Equal(t, "/api/payments/booking/booking-123/capture", r.URL.Path)
+			w.WriteHeader(http.StatusOK)
+		}))
+		defer server.Close()
+
+		client := NewPaymentClient(server.URL)
+
+		assert.NoError(t, client.CapturePayment(context.Background(), "booking-123"))
+	})
+
+	t.Run("conflict", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusConflict)
+		}))
+		defer server.Close()
+
+		client := NewPaymentClient(server.URL)
+
+		err := client.CapturePayment(context.Background(), "booking-123")
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "status 409")
+	})
+}
+
+func TestPaymentClient_VoidPayment(t *testing.T) {
+	logger.Init("info")
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "POST", r.Method)
+		assert.Equal(t, "/api/payments/booking/booking-123/void", r.URL.Path)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	client := NewPaymentClient(server.URL)
+
+	assert.NoError(t, client.VoidPayment(context.Background(), "booking-123"))
 }
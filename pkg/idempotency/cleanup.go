@@ -0,0 +1,24 @@
+package idempotency
+
+import (
+	"context"
+	"time"
+
+	"hotel-booking-system/pkg/logger"
+	"hotel-booking-system/pkg/periodic"
+)
+
+// Cleanup deletes the keys older than ttl every interval until ctx is done.
+// After that a request with the same key is handled as a new one.
+func Cleanup(ctx context.Context, store Store, interval, ttl time.Duration) {
+	periodic.Run(ctx, interval, "delete expired idempotency keys", func(ctx context.Context) error {
+		deleted, err := store.DeleteExpired(ctx, ttl)
+		if err != nil {
+			return err
+		}
+		if deleted > 0 {
+			logger.GetLogger().Infof("deleted %d expired idempotency keys", deleted)
+		}
+		return nil
+	})
+}
@@ -0,0 +1,74 @@
+package idempotency
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+type MemoryStore struct {
+	mu          sync.Mutex
+	records     map[string]*Record
+	lockTimeout time.Duration
+}
+
+func NewMemoryStore(lockTimeout time.Duration) *MemoryStore {
+	return &MemoryStore{
+		records:     make(map[string]*Record),
+		lockTimeout: lockTimeout,
+	}
+}
+
+func (s *MemoryStore) Reserve(ctx context.Context, key, fingerprint string) (*Record, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if existing, ok := s.records[key]; ok {
+		abandoned := !existing.Completed && existing.Fingerprint == fingerprint &&
+			time.Since(existing.CreatedAt) > s.lockTimeout
+		if !abandoned {
+			copied := *existing
+			return &copied, nil
+		}
+	}
+
+	s.records[key] = &Record{Key: key, Fingerprint: fingerprint, CreatedAt: time.Now()}
+	return nil, nil
+}
+
+func (s *MemoryStore) Complete(ctx context.Context, key string, statusCode int, contentType string, body []byte) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	record, ok := s.records[key]
+	if !ok {
+		return nil
+	}
+	record.StatusCode = statusCode
+	record.ContentType = contentType
+	record.Body = append([]byte(nil), body...)
+	record.Completed = true
+	return nil
+}
+
+func (s *MemoryStore) Release(ctx context.Context, key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.records, key)
+	return nil
+}
+
+func (s *MemoryStore) DeleteExpired(ctx context.Context, ttl time.Duration) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	deleted := 0
+	for key, record := range s.records {
+		if time.Since(record.CreatedAt) > ttl {
+			delete(s.records, key)
+			deleted++
+		}
+	}
+	return deleted, nil
+}
@@ -0,0 +1,132 @@
+package idempotency
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"net/http"
+
+	"hotel-booking-system/pkg/logger"
+
+	"github.com/go-chi/chi/v5"
+)
+
+const (
+	HeaderKey      = "Idempotency-Key"
+	HeaderReplayed = "Idempotent-Replayed"
+	maxKeyLength   = 255
+)
+
+type callerKey struct{}
+
+// WithCaller records the authenticated caller of a request. Their keys are
+// kept apart from those of other callers; without a caller keys are shared by
+// everyone sending requests to the route.
+func WithCaller(ctx context.Context, caller string) context.Context {
+	return context.WithValue(ctx, callerKey{}, caller)
+}
+
+func Middleware(store Store) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			key := r.Header.Get(HeaderKey)
+			if key == "" || r.Method == http.MethodGet || r.Method == http.MethodHead || r.Method == http.MethodOptions {
+				next.ServeHTTP(w, r)
+				return
+			}
+			if len(key) > maxKeyLength {
+				http.Error(w, "Idempotency-Key is too long", http.StatusBadRequest)
+				return
+			}
+
+			body, err := io.ReadAll(r.Body)
+			if err != nil {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+			r.Body = io.NopCloser(bytes.NewReader(body))
+
+			key = scopedKey(r, key)
+			fingerprint := Fingerprint(r.Method, r.URL.Path, body)
+			existing, err := store.Reserve(r.Context(), key, fingerprint)
+			if err != nil {
+				logger.GetLogger().WithError(err).Error("failed to reserve idempotency key")
+				http.Error(w, err.Error(), http.StatusInternalServerError)
+				return
+			}
+
+			if existing != nil {
+				switch {
+				case existing.Fingerprint != fingerprint:
+					http.Error(w, "Idempotency-Key was already used with a different request", http.StatusUnprocessableEntity)
+				case !existing.Completed:
+					http.Error(w, "request with this Idempotency-Key is still being processed", http.StatusConflict)
+				default:
+					if existing.ContentType != "" {
+						w.Header().Set("Content-Type", existing.ContentType)
+					}
+					w.Header().Set(HeaderReplayed, "true")
+					w.WriteHeader(existing.StatusCode)
+					w.Write(existing.Body)
+				}
+				return
+			}
+
+			recorder := &responseRecorder{ResponseWriter: w, statusCode: http.StatusOK}
+			next.ServeHTTP(recorder, r)
+
+			// Server errors are not cached so that the client can retry with the same key.
+			ctx := context.WithoutCancel(r.Context())
+			if recorder.statusCode >= http.StatusInternalServerError {
+				if err := store.Release(ctx, key); err != nil {
+					logger.GetLogger().WithError(err).Error("failed to release idempotency key")
+				}
+				return
+			}
+			if err := store.Complete(ctx, key, recorder.statusCode, w.Header().Get("Content-Type"), recorder.body.Bytes()); err != nil {
+				logger.GetLogger().WithError(err).Error("failed to store idempotent response")
+			}
+		})
+	}
+}
+
+// scopedKey confines the key to the route it was sent to and to the
+// authenticated caller, if any. The client's address is left out so that a
+// retry from another address or through a proxy finds the first response.
+func scopedKey(r *http.Request, key string) string {
+	route := r.URL.Path
+	if rctx := chi.RouteContext(r.Context()); rctx != nil && rctx.RoutePattern() != "" {
+		route = rctx.RoutePattern()
+	}
+	caller, _ := r.Context().Value(callerKey{}).(string)
+
+	hash := sha256.New()
+	for _, part := range []string{caller, r.Method, route, key} {
+		hash.Write([]byte(part))
+		hash.Write([]byte{0})
+	}
+	return hex.EncodeToString(hash.Sum(nil))
+}
+
+type responseRecorder struct {
+	http.ResponseWriter
+	statusCode  int
+	body        bytes.Buffer
+	wroteHeader bool
+}
+
+func (r *responseRecorder) WriteHeader(statusCode int) {
+	if !r.wroteHeader {
+		r.statusCode = statusCode
+		r.wroteHeader = true
+	}
+	r.ResponseWriter.WriteHeader(statusCode)
+}
+
+func (r *responseRecorder) Write(data []byte) (int, error) {
+	r.wroteHeader = true
+	r.body.Write(data)
+	return r.ResponseWriter.Write(data)
+}
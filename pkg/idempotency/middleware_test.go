@@ -0,0 +1,241 @@
+package idempotency
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"hotel-booking-system/pkg/logger"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newCountingHandler(statusCode int, calls *int) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		*calls++
+		var body bytes.Buffer
+		body.ReadFrom(r.Body)
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(statusCode)
+		fmt.Fprintf(w, `{"call":%d,"echo":%s}`, *calls, body.String())
+	})
+}
+
+func doRequest(handler http.Handler, method, key, body string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, "/api/bookings", strings.NewReader(body))
+	if key != "" {
+		req.Header.Set(HeaderKey, key)
+	}
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, req)
+	return w
+}
+
+func TestMiddleware(t *testing.T) {
+	logger.Init("info")
+
+	t.Run("replays stored response for the same key", func(t *testing.T) {
+		calls := 0
+		handler := Middleware(NewMemoryStore(time.Minute))(newCountingHandler(http.StatusCreated, &calls))
+
+		first := doRequest(handler, "POST", "key-1", `{"room_id":"room-1"}`)
+		second := doRequest(handler, "POST", "key-1", `{"room_id":"room-1"}`)
+
+		assert.Equal(t, 1, calls)
+		assert.Equal(t, http.StatusCreated, first.Code)
+		assert.Equal(t, http.StatusCreated, second.Code)
+		assert.Equal(t, first.Body.String(), second.Body.String())
+		assert.Equal(t, "application/json", second.Header().Get("Content-Type"))
+		assert.Equal(t, "true", second.Header().Get(HeaderReplayed))
+		assert.Empty(t, first.Header().Get(HeaderReplayed))
+	})
+
+	t.Run("rejects key reuse with a different body", func(t *testing.T) {
+		calls := 0
+		handler := Middleware(NewMemoryStore(time.Minute))(newCountingHandler(http.StatusCreated, &calls))
+
+		doRequest(handler, "POST", "key-1", `{"room_id":"room-1"}`)
+		w := doRequest(handler, "POST", "key-1", `{"room_id":"room-2"}`)
+
+		assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
+		assert.Equal(t, 1, calls)
+	})
+
+	t.Run("does not cache server errors", func(t *testing.T) {
+		calls := 0
+		handler := Middleware(NewMemoryStore(time.Minute))(newCountingHandler(http.StatusInternalServerError, &calls))
+
+		doRequest(handler, "POST", "key-1", `{}`)
+		doRequest(handler, "POST", "key-1", `{}`)
+
+		assert.Equal(t, 2, calls)
+	})
+
+	t.Run("caches client errors", func(t *testing.T) {
+		calls := 0
+		handler := Middleware(NewMemoryStore(time.Minute))(newCountingHandler(http.StatusConflict, &calls))
+
+		doRequest(handler, "POST", "key-1", `{}`)
+		w := doRequest(handler, "POST", "key-1", `{}`)
+
+		assert.Equal(t, 1, calls)
+		assert.Equal(t, http.StatusConflict, w.Code)
+	})
+
+	t.Run("passes through requests without key", func(t *testing.T) {
+		calls := 0
+		handler := Middleware(NewMemoryStore(time.Minute))(newCountingHandler(http.StatusCreated, &calls))
+
+		doRequest(handler, "POST", "", `{}`)
+		doRequest(handler, "POST", "", `{}`)
+
+		assert.Equal(t, 2, calls)
+	})
+
+	t.Run("ignores safe methods", func(t *testing.T) {
+		calls := 0
+		handler := Middleware(NewMemoryStore(time.Minute))(newCountingHandler(http.StatusOK, &calls))
+
+		doRequest(handler, "GET", "key-1", "")
+		doRequest(handler, "GET", "key-1", "")
+
+		assert.Equal(t, 2, calls)
+	})
+
+	t.Run("rejects too long key", func(t *testing.T) {
+		calls := 0
+		handler := Middleware(NewMemoryStore(time.Minute))(newCountingHandler(http.StatusCreated, &calls))
+
+		w := doRequest(handler, "POST", strings.Repeat("k", maxKeyLength+1), `{}`)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Equal(t, 0, calls)
+	})
+
+	t.Run("reports request in progress", func(t *testing.T) {
+		store := NewMemoryStore(time.Minute)
+		req := httptest.NewRequest("POST", "/api/bookings", nil)
+		_, err := store.Reserve(context.Background(), scopedKey(req, "key-1"), Fingerprint("POST", "/api/bookings", []byte(`{}`)))
+		require.NoError(t, err)
+
+		calls := 0
+		handler := Middleware(store)(newCountingHandler(http.StatusCreated, &calls))
+		w := doRequest(handler, "POST", "key-1", `{}`)
+
+		assert.Equal(t, http.StatusConflict, w.Code)
+		assert.Equal(t, 0, calls)
+	})
+}
+
+func TestMiddleware_ScopesKeys(t *testing.T) {
+	logger.Init("info")
+
+	send := func(handler http.Handler, remoteAddr, caller, path string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest("POST", path, strings.NewReader(`{}`))
+		req.RemoteAddr = remoteAddr
+		req.Header.Set(HeaderKey, "key-1")
+		if caller != "" {
+			req = req.WithContext(WithCaller(req.Context(), caller))
+		}
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, req)
+		return w
+	}
+
+	t.Run("retry from another address gets the cached response", func(t *testing.T) {
+		calls := 0
+		handler := Middleware(NewMemoryStore(time.Minute))(newCountingHandler(http.StatusCreated, &calls))
+
+		first := send(handler, "10.0.0.1:1000", "", "/api/bookings")
+		retry := send(handler, "192.168.1.7:2000", "", "/api/bookings")
+
+		assert.Equal(t, 1, calls)
+		assert.Equal(t, http.StatusCreated, retry.Code)
+		assert.Equal(t, "true", retry.Header().Get(HeaderReplayed))
+		assert.Equal(t, first.Body.String(), retry.Body.String())
+	})
+
+	t.Run("routes do not share keys", func(t *testing.T) {
+		calls := 0
+		handler := Middleware(NewMemoryStore(time.Minute))(newCountingHandler(http.StatusCreated, &calls))
+
+		send(handler, "10.0.0.1:1000", "", "/api/bookings")
+		send(handler, "10.0.0.1:1000", "", "/api/payments")
+
+		assert.Equal(t, 2, calls)
+	})
+
+	t.Run("authenticated callers do not share keys", func(t *testing.T) {
+		calls := 0
+		handler := Middleware(NewMemoryStore(time.Minute))(newCountingHandler(http.StatusCreated, &calls))
+
+		send(handler, "10.0.0.1:1000", "user-1", "/api/bookings")
+		w := send(handler, "10.0.0.1:1000", "user-2", "/api/bookings")
+
+		assert.Equal(t, 2, calls)
+		assert.Empty(t, w.Header().Get(HeaderReplayed))
+	})
+
+	t.Run("keys are scoped to the route pattern", func(t *testing.T) {
+		calls := 0
+		router := chi.NewRouter()
+		router.With(Middleware(NewMemoryStore(time.Minute))).Post("/api/bookings/{id}/cancel", newCountingHandler(http.StatusOK, &calls).ServeHTTP)
+
+		send(router, "10.0.0.1:1000", "", "/api/bookings/booking-1/cancel")
+		w := send(router, "10.0.0.1:1000", "", "/api/bookings/booking-2/cancel")
+
+		assert.Equal(t, 1, calls)
+		assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
+	})
+}
+
+func TestFingerprint(t *testing.T) {
+	base := Fingerprint("POST", "/api/bookings", []byte(`{}`))
+	assert.Equal(t, base, Fingerprint("POST", "/api/bookings", []byte(`{}`)))
+	assert.NotEqual(t, base, Fingerprint("POST", "/api/payments", []byte(`{}`)))
+	assert.NotEqual(t, base, Fingerprint("POST", "/api/bookings", []byte(`{"a":1}`)))
+}
+
+func TestMemoryStore_TakesOverAbandonedKey(t *testing.T) {
+	store := NewMemoryStore(10 * time.Millisecond)
+	ctx := context.Background()
+
+	existing, err := store.Reserve(ctx, "key-1", "fp")
+	require.NoError(t, err)
+	assert.Nil(t, existing)
+
+	existing, err = store.Reserve(ctx, "key-1", "fp")
+	require.NoError(t, err)
+	require.NotNil(t, existing)
+	assert.False(t, existing.Completed)
+
+	time.Sleep(20 * time.Millisecond)
+
+	existing, err = store.Reserve(ctx, "key-1", "fp")
+	require.NoError(t, err)
+	assert.Nil(t, existing)
+}
+
+func TestMemoryStore_DeleteExpired(t *testing.T) {
+	store := NewMemoryStore(time.Minute)
+	ctx := context.Background()
+
+	_, err := store.Reserve(ctx, "old", "fp")
+	require.NoError(t, err)
+	store.records["old"].CreatedAt = time.Now().Add(-2 * time.Hour)
+	_, err = store.Reserve(ctx, "new", "fp")
+	require.NoError(t, err)
+
+	deleted, err := store.DeleteExpired(ctx, time.Hour)
+	require.NoError(t, err)
+	assert.Equal(t, 1, deleted)
+	assert.NotContains(t, store.records, "old")
+	assert.Contains(t, store.records, "new")
+}
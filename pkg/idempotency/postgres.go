@@ -0,0 +1,71 @@
+package idempotency
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+)
+
+type PostgresStore struct {
+	db          *sql.DB
+	lockTimeout time.Duration
+}
+
+func NewPostgresStore(db *sql.DB, lockTimeout time.Duration) *PostgresStore {
+	return &PostgresStore{db: db, lockTimeout: lockTimeout}
+}
+
+func (s *PostgresStore) Reserve(ctx context.Context, key, fingerprint string) (*Record, error) {
+	// An unfinished record older than lockTimeout belongs to a request that died
+	// mid-flight, so the same request is allowed to take it over.
+	query := `INSERT INTO idempotency_keys (key, fingerprint) VALUES ($1, $2) 
+			  ON CONFLICT (key) DO UPDATE SET created_at = CURRENT_TIMESTAMP 
+			  WHERE idempotency_keys.completed = FALSE 
+			  AND idempotency_keys.fingerprint = EXCLUDED.fingerprint 
+			  AND idempotency_keys.created_at < CURRENT_TIMESTAMP - $3 * INTERVAL '1 second' 
+			  RETURNING key`
+	var claimed string
+	err := s.db.QueryRowContext(ctx, query, key, fingerprint, s.lockTimeout.Seconds()).Scan(&claimed)
+	if err == nil {
+		return nil, nil
+	}
+	if !errors.Is(err, sql.ErrNoRows) {
+		return nil, err
+	}
+
+	record := &Record{}
+	query = `SELECT key, fingerprint, status_code, content_type, response_body, completed, created_at 
+			 FROM idempotency_keys WHERE key = $1`
+	err = s.db.QueryRowContext(ctx, query, key).Scan(
+		&record.Key, &record.Fingerprint, &record.StatusCode, &record.ContentType,
+		&record.Body, &record.Completed, &record.CreatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return record, nil
+}
+
+func (s *PostgresStore) Complete(ctx context.Context, key string, statusCode int, contentType string, body []byte) error {
+	query := `UPDATE idempotency_keys SET status_code = $2, content_type = $3, response_body = $4, completed = TRUE 
+			  WHERE key = $1`
+	_, err := s.db.ExecContext(ctx, query, key, statusCode, contentType, body)
+	return err
+}
+
+func (s *PostgresStore) Release(ctx context.Context, key string) error {
+	query := `DELETE FROM idempotency_keys WHERE key = $1 AND completed = FALSE`
+	_, err := s.db.ExecContext(ctx, query, key)
+	return err
+}
+
+func (s *PostgresStore) DeleteExpired(ctx context.Context, ttl time.Duration) (int, error) {
+	query := `DELETE FROM idempotency_keys WHERE created_at < CURRENT_TIMESTAMP - $1 * INTERVAL '1 second'`
+	result, err := s.db.ExecContext(ctx, query, ttl.Seconds())
+	if err != nil {
+		return 0, err
+	}
+	deleted, err := result.RowsAffected()
+	return int(deleted), err
+}
@@ -0,0 +1,122 @@
+package idempotency
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func setupMockDB(t *testing.T) (*sql.DB, sqlmock.Sqlmock) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("failed to create mock db: %v", err)
+	}
+	return db, mock
+}
+
+func TestPostgresStore_Reserve(t *testing.T) {
+	t.Run("claims new key", func(t *testing.T) {
+		db, mock := setupMockDB(t)
+		defer db.Close()
+
+		store := NewPostgresStore(db, time.Minute)
+
+		mock.ExpectQuery(`INSERT INTO idempotency_keys`).
+			WithArgs("key-1", "fp", float64(60)).
+			WillReturnRows(sqlmock.NewRows([]string{"key"}).AddRow("key-1"))
+
+		existing, err := store.Reserve(context.Background(), "key-1", "fp")
+		assert.NoError(t, err)
+		assert.Nil(t, existing)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("returns existing record", func(t *testing.T) {
+		db, mock := setupMockDB(t)
+		defer db.Close()
+
+		store := NewPostgresStore(db, time.Minute)
+		now := time.Now()
+
+		mock.ExpectQuery(`INSERT INTO idempotency_keys`).
+			WillReturnError(sql.ErrNoRows)
+		mock.ExpectQuery(`SELECT key, fingerprint, status_code, content_type, response_body, completed, created_at FROM idempotency_keys`).
+			WithArgs("key-1").
+			WillReturnRows(sqlmock.NewRows([]string{"key", "fingerprint", "status_code", "content_type", "response_body", "completed", "created_at"}).
+				AddRow("key-1", "fp", 201, "application/json", []byte(`{"id":"1"}`), true, now))
+
+		existing, err := store.Reserve(context.Background(), "key-1", "fp")
+		require.NoError(t, err)
+		require.NotNil(t, existing)
+		assert.Equal(t, 201, existing.StatusCode)
+		assert.Equal(t, `{"id":"1"}`, string(existing.Body))
+		assert.True(t, existing.Completed)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("database error", func(t *testing.T) {
+		db, mock := setupMockDB(t)
+		defer db.Close()
+
+		store := NewPostgresStore(db, time.Minute)
+
+		mock.ExpectQuery(`INSERT INTO idempotency_keys`).
+			WillReturnError(errors.New("connection refused"))
+
+		existing, err := store.Reserve(context.Background(), "key-1", "fp")
+		assert.Error(t, err)
+		assert.Nil(t, existing)
+	})
+}
+
+func TestPostgresStore_Complete(t *testing.T) {
+	db, mock := setupMockDB(t)
+	defer db.Close()
+
+	store := NewPostgresStore(db, time.Minute)
+
+	mock.ExpectExec(`UPDATE idempotency_keys SET status_code`).
+		WithArgs("key-1", 201, "application/json", []byte(`{}`)).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	err := store.Complete(context.Background(), "key-1", 201, "application/json", []byte(`{}`))
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestPostgresStore_Release(t *testing.T) {
+	db, mock := setupMockDB(t)
+	defer db.Close()
+
+	store := NewPostgresStore(db, time.Minute)
+
+	mock.ExpectExec(`DELETE FROM idempotency_keys`).
+		WithArgs("key-1").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	err := store.Release(context.Background(), "key-1")
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestPostgresStore_DeleteExpired(t *testing.T) {
+	db, mock := setupMockDB(t)
+	defer db.Close()
+
+	store := NewPostgresStore(db, time.Minute)
+
+	mock.ExpectExec(`DELETE FROM idempotency_keys WHERE created_at < CURRENT_TIMESTAMP`).
+		WithArgs(float64(86400)).
+		WillReturnResult(sqlmock.NewResult(0, 3))
+
+	deleted, err := store.DeleteExpired(context.Background(), 24*time.Hour)
+	assert.NoError(t, err)
+	assert.Equal(t, 3, deleted)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
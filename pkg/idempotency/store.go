@@ -0,0 +1,39 @@
+package idempotency
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"time"
+)
+
+type Record struct {
+	Key         string
+	Fingerprint string
+	StatusCode  int
+	ContentType string
+	Body        []byte
+	Completed   bool
+	CreatedAt   time.Time
+}
+
+type Store interface {
+	// Reserve claims the key for a new request. If the key is already taken,
+	// the stored record is returned and the caller must not run the request.
+	Reserve(ctx context.Context, key, fingerprint string) (*Record, error)
+	Complete(ctx context.Context, key string, statusCode int, contentType string, body []byte) error
+	Release(ctx context.Context, key string) error
+	// DeleteExpired removes the records created more than ttl ago and returns
+	// how many were removed.
+	DeleteExpired(ctx context.Context, ttl time.Duration) (int, error)
+}
+
+func Fingerprint(method, path string, body []byte) string {
+	hash := sha256.New()
+	hash.Write([]byte(method))
+	hash.Write([]byte{0})
+	hash.Write([]byte(path))
+	hash.Write([]byte{0})
+	hash.Write(body)
+	return hex.EncodeToString(hash.Sum(nil))
+}
@@ -26,6 +26,18 @@ func NewConsumer(brokers []string, topic, groupID string) *Consumer {
 	}
 }
 
+func NewGroupConsumer(brokers []string, topics []string, groupID string) *Consumer {
+	return &Consumer{
+		reader: kafka.NewReader(kafka.ReaderConfig{
+			Brokers:     brokers,
+			GroupTopics: topics,
+			GroupID:     groupID,
+			MinBytes:    10e3,
+			MaxBytes:    10e6,
+		}),
+	}
+}
+
 func (c *Consumer) ReadMessage(ctx context.Context, handler func([]byte) error) error {
 	for {
 		msg, err := c.reader.ReadMessage(ctx)
@@ -12,6 +12,13 @@ func TestNewConsumer(t *testing.T) {
 	assert.NotNil(t, consumer.reader)
 }
 
+func TestNewGroupConsumer(t *testing.T) {
+	consumer := NewGroupConsumer([]string{"localhost:9092"}, []string{"topic-a", "topic-b"}, "test-group")
+	assert.NotNil(t, consumer)
+	assert.Equal(t, []string{"topic-a", "topic-b"}, consumer.reader.Config().GroupTopics)
+	consumer.Close()
+}
+
 func TestUnmarshalMessage(t *testing.T) {
 	data := []byte(`{"test": "value"}`)
 	var result map[string]string
@@ -12,25 +12,31 @@ import (
 
 type Producer struct {
 	writer *kafka.Writer
+	topic  string
 }
 
 func NewProducer(brokers []string, topic string) *Producer {
 	return &Producer{
 		writer: &kafka.Writer{
 			Addr:     kafka.TCP(brokers...),
-			Topic:    topic,
 			Balancer: &kafka.LeastBytes{},
 		},
+		topic: topic,
 	}
 }
 
 func (p *Producer) SendMessage(ctx context.Context, key string, value interface{}) error {
+	return p.SendMessageToTopic(ctx, p.topic, key, value)
+}
+
+func (p *Producer) SendMessageToTopic(ctx context.Context, topic, key string, value interface{}) error {
 	data, err := json.Marshal(value)
 	if err != nil {
 		return err
 	}
 
 	msg := kafka.Message{
+		Topic: topic,
 		Key:   []byte(key),
 		Value: data,
 	}
@@ -41,7 +47,10 @@ func (p *Producer) SendMessage(ctx context.Context, key string, value interface{
 	}
 
 	metrics.KafkaMessagesProduced.Inc()
-	logger.GetLogger().WithField("key", key).Info("kafka message sent")
+	logger.GetLogger().WithFields(map[string]interface{}{
+		"topic": topic,
+		"key":   key,
+	}).Info("kafka message sent")
 	return nil
 }
 
@@ -11,6 +11,7 @@ func TestNewProducer(t *testing.T) {
 	producer := NewProducer([]string{"localhost:9092"}, "test-topic")
 	assert.NotNil(t, producer)
 	assert.NotNil(t, producer.writer)
+	assert.Equal(t, "test-topic", producer.topic)
 }
 
 func TestProducer_SendMessage_SerializationError(t *testing.T) {
@@ -23,3 +24,10 @@ func TestProducer_SendMessage_SerializationError(t *testing.T) {
 	err := producer.SendMessage(context.Background(), "key", InvalidType{Channel: make(chan int)})
 	assert.Error(t, err)
 }
+
+func TestProducer_SendMessageToTopic_SerializationError(t *testing.T) {
+	producer := NewProducer([]string{"localhost:9092"}, "test-topic")
+
+	err := producer.SendMessageToTopic(context.Background(), "other-topic", "key", make(chan int))
+	assert.Error(t, err)
+}
@@ -0,0 +1,222 @@
+package money
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
+	"strconv"
+	"strings"
+)
+
+// DefaultCurrency is assumed when a JSON amount omits its currency.
+const DefaultCurrency = "RUB"
+
+var (
+	ErrInvalidAmount    = errors.New("invalid money amount")
+	ErrInvalidCurrency  = errors.New("invalid currency code")
+	ErrCurrencyMismatch = errors.New("currencies do not match")
+)
+
+// exponents lists currencies whose minor unit is not 1/100 of the major one.
+var exponents = map[string]int{
+	"JPY": 0,
+	"KRW": 0,
+}
+
+// Money is an amount in integer minor units (kopecks, cents) of an ISO 4217
+// currency, so arithmetic on prices never loses a fraction of a minor unit.
+type Money struct {
+	Amount   int64
+	Currency string
+}
+
+func New(amount int64, currency string) Money {
+	return Money{Amount: amount, Currency: currency}
+}
+
+// Parse converts a decimal string such as "1000.50" into minor units of the
+// currency. Digits beyond the currency's precision must be zeros, so the
+// conversion is exact: "10.00" is valid for JPY, "10.5" is not.
+func Parse(amount, currency string) (Money, error) {
+	if err := ValidateCurrency(currency); err != nil {
+		return Money{}, err
+	}
+	exp := exponent(currency)
+
+	s := strings.TrimSpace(amount)
+	negative := strings.HasPrefix(s, "-")
+	s = strings.TrimPrefix(strings.TrimPrefix(s, "-"), "+")
+	whole, frac, _ := strings.Cut(s, ".")
+	if whole == "" && frac == "" {
+		return Money{}, fmt.Errorf("%w: %q", ErrInvalidAmount, amount)
+	}
+	if len(frac) > exp {
+		if strings.Trim(frac[exp:], "0") != "" {
+			return Money{}, fmt.Errorf("%w: %q has more than %d decimal places", ErrInvalidAmount, amount, exp)
+		}
+		frac = frac[:exp]
+	}
+	frac += strings.Repeat("0", exp-len(frac))
+
+	digits := whole + frac
+	if digits == "" {
+		digits = "0"
+	}
+	for _, c := range digits {
+		if c < '0' || c > '9' {
+			return Money{}, fmt.Errorf("%w: %q", ErrInvalidAmount, amount)
+		}
+	}
+	minor, err := strconv.ParseInt(digits, 10, 64)
+	if err != nil {
+		return Money{}, fmt.Errorf("%w: %q", ErrInvalidAmount, amount)
+	}
+	if negative {
+		minor = -minor
+	}
+	return Money{Amount: minor, Currency: currency}, nil
+}
+
+func (m Money) Add(other Money) (Money, error) {
+	currency, err := m.commonCurrency(other)
+	if err != nil {
+		return Money{}, err
+	}
+	if (other.Amount > 0 && m.Amount > math.MaxInt64-other.Amount) ||
+		(other.Amount < 0 && m.Amount < math.MinInt64-other.Amount) {
+		return Money{}, fmt.Errorf("%w: overflow", ErrInvalidAmount)
+	}
+	return Money{Amount: m.Amount + other.Amount, Currency: currency}, nil
+}
+
+func (m Money) Sub(other Money) (Money, error) {
+	return m.Add(other.Neg())
+}
+
+func (m Money) Neg() Money {
+	return Money{Amount: -m.Amount, Currency: m.Currency}
+}
+
+// Mul multiplies the amount by a whole quantity, such as a number of nights.
+func (m Money) Mul(n int64) (Money, error) {
+	product := m.Amount * n
+	if m.Amount != 0 && (product/m.Amount != n || (m.Amount == -1 && n == math.MinInt64)) {
+		return Money{}, fmt.Errorf("%w: overflow", ErrInvalidAmount)
+	}
+	return Money{Amount: product, Currency: m.Currency}, nil
+}
+
+// Percent returns the given share of the amount in basis points (1/100 of a
+// percent, so 250 is 2.5%), rounded half away from zero to the minor unit.
+func (m Money) Percent(basisPoints int64) Money {
+	num := new(big.Int).Mul(big.NewInt(m.Amount), big.NewInt(basisPoints))
+	return Money{Amount: divRound(num, big.NewInt(10000)).Int64(), Currency: m.Currency}
+}
+
+func (m Money) IsZero() bool     { return m.Amount == 0 }
+func (m Money) IsPositive() bool { return m.Amount > 0 }
+func (m Money) IsNegative() bool { return m.Amount < 0 }
+
+// Decimal formats the amount in major units with the currency's precision,
+// e.g. "1000.50".
+func (m Money) Decimal() string {
+	exp := exponent(m.Currency)
+	abs := m.Amount
+	sign := ""
+	if abs < 0 {
+		sign = "-"
+	}
+	digits := strconv.FormatUint(absUint(abs), 10)
+	if exp == 0 {
+		return sign + digits
+	}
+	if len(digits) <= exp {
+		digits = strings.Repeat("0", exp-len(digits)+1) + digits
+	}
+	return sign + digits[:len(digits)-exp] + "." + digits[len(digits)-exp:]
+}
+
+func (m Money) String() string {
+	return m.Decimal() + " " + m.Currency
+}
+
+type jsonMoney struct {
+	Amount   json.Number `json:"amount"`
+	Currency string      `json:"currency"`
+}
+
+// MarshalJSON encodes the amount as a decimal string, which unlike a JSON
+// number survives clients that parse numbers into floats.
+func (m Money) MarshalJSON() ([]byte, error) {
+	return json.Marshal(struct {
+		Amount   string `json:"amount"`
+		Currency string `json:"currency"`
+	}{Amount: m.Decimal(), Currency: m.Currency})
+}
+
+// UnmarshalJSON accepts the amount as a decimal string or a JSON number; the
+// currency defaults to DefaultCurrency.
+func (m *Money) UnmarshalJSON(data []byte) error {
+	var raw jsonMoney
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidAmount, err)
+	}
+	if raw.Currency == "" {
+		raw.Currency = DefaultCurrency
+	}
+	parsed, err := Parse(raw.Amount.String(), raw.Currency)
+	if err != nil {
+		return err
+	}
+	*m = parsed
+	return nil
+}
+
+// Value stores the amount in a DECIMAL column as exact decimal text; the
+// currency lives in its own column.
+func (m Money) Value() (driver.Value, error) {
+	return m.Decimal(), nil
+}
+
+func (m Money) commonCurrency(other Money) (string, error) {
+	switch {
+	case m.Currency == other.Currency:
+		return m.Currency, nil
+	case m.Currency == "" && m.Amount == 0:
+		return other.Currency, nil
+	case other.Currency == "" && other.Amount == 0:
+		return m.Currency, nil
+	default:
+		return "", fmt.Errorf("%w: %s and %s", ErrCurrencyMismatch, m.Currency, other.Currency)
+	}
+}
+
+// ValidateCurrency checks that currency looks like an ISO 4217 code.
+func ValidateCurrency(currency string) error {
+	if len(currency) != 3 {
+		return fmt.Errorf("%w: %q", ErrInvalidCurrency, currency)
+	}
+	for _, c := range currency {
+		if c < 'A' || c > 'Z' {
+			return fmt.Errorf("%w: %q", ErrInvalidCurrency, currency)
+		}
+	}
+	return nil
+}
+
+func exponent(currency string) int {
+	if exp, ok := exponents[currency]; ok {
+		return exp
+	}
+	return 2
+}
+
+func absUint(n int64) uint64 {
+	if n < 0 {
+		return uint64(-(n + 1)) + 1
+	}
+	return uint64(n)
+}
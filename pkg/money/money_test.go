@@ -0,0 +1,121 @@
+package money
+
+import (
+	"encoding/json"
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		amount   string
+		currency string
+		want     int64
+		wantErr  error
+	}{
+		{amount: "1000.50", currency: "RUB", want: 100050},
+		{amount: "1000.5", currency: "RUB", want: 100050},
+		{amount: "1000", currency: "RUB", want: 100000},
+		{amount: "0.01", currency: "RUB", want: 1},
+		{amount: ".5", currency: "USD", want: 50},
+		{amount: "-12.34", currency: "EUR", want: -1234},
+		{amount: "1000.500", currency: "RUB", want: 100050},
+		{amount: "1000.00", currency: "JPY", want: 1000},
+		{amount: "1000.005", currency: "RUB", wantErr: ErrInvalidAmount},
+		{amount: "10.5", currency: "JPY", wantErr: ErrInvalidAmount},
+		{amount: "1e3", currency: "RUB", wantErr: ErrInvalidAmount},
+		{amount: "", currency: "RUB", wantErr: ErrInvalidAmount},
+		{amount: "99999999999999999999", currency: "RUB", wantErr: ErrInvalidAmount},
+		{amount: "10", currency: "rub", wantErr: ErrInvalidCurrency},
+		{amount: "10", currency: "", wantErr: ErrInvalidCurrency},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.amount+" "+tt.currency, func(t *testing.T) {
+			m, err := Parse(tt.amount, tt.currency)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, New(tt.want, tt.currency), m)
+		})
+	}
+}
+
+func TestMoney_Decimal(t *testing.T) {
+	assert.Equal(t, "1000.50", New(100050, "RUB").Decimal())
+	assert.Equal(t, "0.05", New(5, "RUB").Decimal())
+	assert.Equal(t, "0.00", New(0, "RUB").Decimal())
+	assert.Equal(t, "-12.34", New(-1234, "USD").Decimal())
+	assert.Equal(t, "1000", New(1000, "JPY").Decimal())
+	assert.Equal(t, "1000.50 RUB", New(100050, "RUB").String())
+}
+
+func TestMoney_Arithmetic(t *testing.T) {
+	price := New(333333, "RUB")
+
+	product, err := price.Mul(3)
+	require.NoError(t, err)
+	assert.Equal(t, New(999999, "RUB"), product)
+
+	_, err = New(math.MaxInt64/2+1, "RUB").Mul(2)
+	assert.ErrorIs(t, err, ErrInvalidAmount)
+	_, err = New(-1, "RUB").Mul(math.MinInt64)
+	assert.ErrorIs(t, err, ErrInvalidAmount)
+
+	sum, err := price.Add(New(1, "RUB"))
+	require.NoError(t, err)
+	assert.Equal(t, New(333334, "RUB"), sum)
+
+	diff, err := price.Sub(New(333334, "RUB"))
+	require.NoError(t, err)
+	assert.True(t, diff.IsNegative())
+
+	total, err := Money{}.Add(price)
+	require.NoError(t, err)
+	assert.Equal(t, price, total)
+
+	_, err = price.Add(New(100, "USD"))
+	assert.ErrorIs(t, err, ErrCurrencyMismatch)
+}
+
+func TestMoney_Percent(t *testing.T) {
+	assert.Equal(t, New(200, "RUB"), New(10000, "RUB").Percent(200))
+	assert.Equal(t, New(8333, "RUB"), New(333333, "RUB").Percent(250))
+	assert.Equal(t, New(-8333, "RUB"), New(-333333, "RUB").Percent(250))
+	assert.Equal(t, New(1, "RUB"), New(50, "RUB").Percent(100))
+	assert.Equal(t, New(3, "JPY"), New(150, "JPY").Percent(200))
+}
+
+func TestMoney_JSON(t *testing.T) {
+	data, err := json.Marshal(New(100050, "RUB"))
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"amount":"1000.50","currency":"RUB"}`, string(data))
+
+	var m Money
+	require.NoError(t, json.Unmarshal(data, &m))
+	assert.Equal(t, New(100050, "RUB"), m)
+
+	require.NoError(t, json.Unmarshal([]byte(`{"amount":0.1,"currency":"USD"}`), &m))
+	assert.Equal(t, New(10, "USD"), m)
+
+	require.NoError(t, json.Unmarshal([]byte(`{"amount":"250"}`), &m))
+	assert.Equal(t, New(25000, DefaultCurrency), m)
+
+	assert.ErrorIs(t, json.Unmarshal([]byte(`{"amount":"abc"}`), &m), ErrInvalidAmount)
+	assert.ErrorIs(t, json.Unmarshal([]byte(`{"amount":"1.001","currency":"RUB"}`), &m), ErrInvalidAmount)
+}
+
+func TestMoney_Value(t *testing.T) {
+	value, err := New(2500099, "RUB").Value()
+	require.NoError(t, err)
+	assert.Equal(t, "25000.99", value)
+
+	parsed, err := Parse(value.(string), "RUB")
+	require.NoError(t, err)
+	assert.Equal(t, New(2500099, "RUB"), parsed)
+}
@@ -0,0 +1,169 @@
+package money
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
+	"strconv"
+	"strings"
+)
+
+// rateScale is the number of decimal places kept in a rate, matching the
+// DECIMAL(18, 8) columns rates are stored in.
+const rateScale = 8
+
+var ErrInvalidRate = errors.New("invalid exchange rate")
+
+// Rate is the price of one unit of From expressed in To, e.g. 1 USD = 92.5 RUB.
+// The value is kept as a fixed-point integer with rateScale decimal places.
+type Rate struct {
+	From  string
+	To    string
+	value int64
+}
+
+// IdentityRate converts a currency to itself.
+func IdentityRate(currency string) Rate {
+	return Rate{From: currency, To: currency, value: pow10(rateScale)}
+}
+
+// ParseRate parses a positive decimal rate such as "92.5" with at most
+// rateScale decimal places.
+func ParseRate(from, to, value string) (Rate, error) {
+	if err := ValidateCurrency(from); err != nil {
+		return Rate{}, err
+	}
+	if err := ValidateCurrency(to); err != nil {
+		return Rate{}, err
+	}
+
+	whole, frac, _ := strings.Cut(strings.TrimSpace(value), ".")
+	if len(frac) > rateScale {
+		if strings.Trim(frac[rateScale:], "0") != "" {
+			return Rate{}, fmt.Errorf("%w: %q has more than %d decimal places", ErrInvalidRate, value, rateScale)
+		}
+		frac = frac[:rateScale]
+	}
+	digits := whole + frac + strings.Repeat("0", rateScale-len(frac))
+	for _, c := range digits {
+		if c < '0' || c > '9' {
+			return Rate{}, fmt.Errorf("%w: %q", ErrInvalidRate, value)
+		}
+	}
+	v, err := strconv.ParseInt(digits, 10, 64)
+	if err != nil || v <= 0 {
+		return Rate{}, fmt.Errorf("%w: %q", ErrInvalidRate, value)
+	}
+	return Rate{From: from, To: to, value: v}, nil
+}
+
+func (r Rate) IsZero() bool { return r.value == 0 }
+
+// Inverse returns the rate of the opposite direction, rounded to rateScale
+// decimal places.
+func (r Rate) Inverse() Rate {
+	if r.value == 0 {
+		return Rate{From: r.To, To: r.From}
+	}
+	scale := pow10(rateScale)
+	return Rate{From: r.To, To: r.From, value: divRound(big.NewInt(scale*scale), big.NewInt(r.value)).Int64()}
+}
+
+// Decimal formats the rate without trailing zeros, e.g. "92.5".
+func (r Rate) Decimal() string {
+	s := strconv.FormatInt(r.value, 10)
+	if len(s) <= rateScale {
+		s = strings.Repeat("0", rateScale-len(s)+1) + s
+	}
+	whole, frac := s[:len(s)-rateScale], strings.TrimRight(s[len(s)-rateScale:], "0")
+	if frac == "" {
+		return whole
+	}
+	return whole + "." + frac
+}
+
+func (r Rate) String() string {
+	return fmt.Sprintf("1 %s = %s %s", r.From, r.Decimal(), r.To)
+}
+
+// Convert converts m into r.To, rounding half away from zero to the minor
+// unit of the target currency.
+func (m Money) Convert(r Rate) (Money, error) {
+	if m.Currency != r.From {
+		return Money{}, fmt.Errorf("%w: cannot convert %s with a %s rate", ErrCurrencyMismatch, m.Currency, r.From)
+	}
+	if r.value == 0 {
+		return Money{}, ErrInvalidRate
+	}
+
+	num := new(big.Int).Mul(big.NewInt(m.Amount), big.NewInt(r.value))
+	den := big.NewInt(pow10(rateScale))
+	if diff := exponent(r.To) - exponent(r.From); diff > 0 {
+		num.Mul(num, big.NewInt(pow10(diff)))
+	} else if diff < 0 {
+		den.Mul(den, big.NewInt(pow10(-diff)))
+	}
+
+	amount := divRound(num, den)
+	if !amount.IsInt64() {
+		return Money{}, fmt.Errorf("%w: overflow", ErrInvalidAmount)
+	}
+	return Money{Amount: amount.Int64(), Currency: r.To}, nil
+}
+
+type jsonRate struct {
+	From string `json:"from"`
+	To   string `json:"to"`
+	Rate string `json:"rate"`
+}
+
+// MarshalJSON encodes the rate as {"from":"USD","to":"RUB","rate":"92.5"}, or
+// null when no rate is set.
+func (r Rate) MarshalJSON() ([]byte, error) {
+	if r.IsZero() {
+		return []byte("null"), nil
+	}
+	return json.Marshal(jsonRate{From: r.From, To: r.To, Rate: r.Decimal()})
+}
+
+func (r *Rate) UnmarshalJSON(data []byte) error {
+	if string(data) == "null" {
+		return nil
+	}
+	var raw jsonRate
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidRate, err)
+	}
+	parsed, err := ParseRate(raw.From, raw.To, raw.Rate)
+	if err != nil {
+		return err
+	}
+	*r = parsed
+	return nil
+}
+
+// Value stores the rate in a DECIMAL column; the currencies live in their
+// own columns.
+func (r Rate) Value() (driver.Value, error) {
+	return r.Decimal(), nil
+}
+
+// divRound divides rounding half away from zero.
+func divRound(num, den *big.Int) *big.Int {
+	quo, rem := new(big.Int).QuoRem(num, den, new(big.Int))
+	if new(big.Int).Mul(new(big.Int).Abs(rem), big.NewInt(2)).Cmp(new(big.Int).Abs(den)) >= 0 {
+		if num.Sign()*den.Sign() < 0 {
+			quo.Sub(quo, big.NewInt(1))
+		} else {
+			quo.Add(quo, big.NewInt(1))
+		}
+	}
+	return quo
+}
+
+func pow10(n int) int64 {
+	return int64(math.Pow10(n))
+}
@@ -0,0 +1,79 @@
+package money
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseRate(t *testing.T) {
+	rate, err := ParseRate("USD", "RUB", "92.5")
+	require.NoError(t, err)
+	assert.Equal(t, "92.5", rate.Decimal())
+	assert.Equal(t, "1 USD = 92.5 RUB", rate.String())
+
+	rate, err = ParseRate("RUB", "USD", "0.010811000")
+	require.NoError(t, err)
+	assert.Equal(t, "0.010811", rate.Decimal())
+
+	for _, value := range []string{"", "0", "-1", "abc", "0.000000001"} {
+		_, err := ParseRate("USD", "RUB", value)
+		assert.ErrorIs(t, err, ErrInvalidRate, value)
+	}
+	_, err = ParseRate("usd", "RUB", "92.5")
+	assert.ErrorIs(t, err, ErrInvalidCurrency)
+}
+
+func TestMoney_Convert(t *testing.T) {
+	usdToRub, err := ParseRate("USD", "RUB", "92.5")
+	require.NoError(t, err)
+
+	converted, err := New(10001, "USD").Convert(usdToRub)
+	require.NoError(t, err)
+	assert.Equal(t, New(925093, "RUB"), converted)
+
+	rubToUsd := usdToRub.Inverse()
+	assert.Equal(t, "0.01081081", rubToUsd.Decimal())
+	converted, err = New(2500000, "RUB").Convert(rubToUsd)
+	require.NoError(t, err)
+	assert.Equal(t, New(27027, "USD"), converted)
+
+	rubToJpy, err := ParseRate("RUB", "JPY", "1.6")
+	require.NoError(t, err)
+	converted, err = New(100050, "RUB").Convert(rubToJpy)
+	require.NoError(t, err)
+	assert.Equal(t, New(1601, "JPY"), converted)
+
+	converted, err = New(-150, "RUB").Convert(IdentityRate("RUB"))
+	require.NoError(t, err)
+	assert.Equal(t, New(-150, "RUB"), converted)
+
+	_, err = New(100, "EUR").Convert(usdToRub)
+	assert.ErrorIs(t, err, ErrCurrencyMismatch)
+	_, err = New(100, "USD").Convert(Rate{From: "USD", To: "RUB"})
+	assert.ErrorIs(t, err, ErrInvalidRate)
+}
+
+func TestRate_JSON(t *testing.T) {
+	rate, err := ParseRate("EUR", "RUB", "100.25")
+	require.NoError(t, err)
+
+	data, err := json.Marshal(rate)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"from":"EUR","to":"RUB","rate":"100.25"}`, string(data))
+
+	var decoded Rate
+	require.NoError(t, json.Unmarshal(data, &decoded))
+	assert.Equal(t, rate, decoded)
+
+	data, err = json.Marshal(Rate{})
+	require.NoError(t, err)
+	assert.Equal(t, "null", string(data))
+	require.NoError(t, json.Unmarshal(data, &decoded))
+
+	value, err := rate.Value()
+	require.NoError(t, err)
+	assert.Equal(t, "100.25", value)
+}
@@ -0,0 +1,31 @@
+package periodic
+
+import (
+	"context"
+	"time"
+
+	"hotel-booking-system/pkg/logger"
+)
+
+// Run calls work right away and then every interval until ctx is done. A
+// failed round is logged as "failed to <what>" and retried on the next tick.
+func Run(ctx context.Context, interval time.Duration, what string, work func(ctx context.Context) error) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	run(ctx, ticker.C, what, work)
+}
+
+func run(ctx context.Context, ticks <-chan time.Time, what string, work func(ctx context.Context) error) {
+	for {
+		if err := work(ctx); err != nil && ctx.Err() == nil {
+			logger.GetLogger().WithError(err).Error("failed to " + what)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticks:
+		}
+	}
+}
@@ -0,0 +1,45 @@
+package periodic
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"hotel-booking-system/pkg/logger"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRun_WorksOnEveryTickUntilCancelled(t *testing.T) {
+	logger.Init("info")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	ticks := make(chan time.Time)
+	rounds := make(chan int)
+	calls := 0
+	work := func(ctx context.Context) error {
+		calls++
+		rounds <- calls
+		if calls == 2 {
+			return errors.New("database unavailable")
+		}
+		return nil
+	}
+
+	done := make(chan struct{})
+	go func() {
+		run(ctx, ticks, "do work", work)
+		close(done)
+	}()
+
+	assert.Equal(t, 1, <-rounds, "works right away")
+	ticks <- time.Now()
+	assert.Equal(t, 2, <-rounds)
+	ticks <- time.Now()
+	assert.Equal(t, 3, <-rounds, "keeps working after a failed round")
+
+	cancel()
+	<-done
+	assert.Equal(t, 3, calls)
+}
@@ -0,0 +1,30 @@
+package webhook
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+
+	"hotel-booking-system/pkg/logger"
+)
+
+func Middleware(verifier *Verifier) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			body, err := io.ReadAll(r.Body)
+			if err != nil {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+
+			if err := verifier.Verify(r.Header, body); err != nil {
+				logger.GetLogger().WithError(err).Warn("rejected webhook")
+				http.Error(w, err.Error(), http.StatusUnauthorized)
+				return
+			}
+
+			r.Body = io.NopCloser(bytes.NewReader(body))
+			next.ServeHTTP(w, r)
+		})
+	}
+}
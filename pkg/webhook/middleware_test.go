@@ -0,0 +1,48 @@
+package webhook
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"hotel-booking-system/pkg/logger"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMiddleware(t *testing.T) {
+	logger.Init("info")
+
+	body := `{"booking_id":"booking-123","status":"paid"}`
+	var received string
+	handler := Middleware(NewVerifier([]string{"secret"}, 5*time.Minute))(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		data, _ := io.ReadAll(r.Body)
+		received = string(data)
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	t.Run("signed request reaches handler with body intact", func(t *testing.T) {
+		req := httptest.NewRequest("POST", "/api/webhooks/payment", strings.NewReader(body))
+		NewSigner("secret").SignRequest(req.Header, []byte(body))
+		w := httptest.NewRecorder()
+
+		handler.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, body, received)
+	})
+
+	t.Run("unsigned request is rejected", func(t *testing.T) {
+		received = ""
+		req := httptest.NewRequest("POST", "/api/webhooks/payment", strings.NewReader(body))
+		w := httptest.NewRecorder()
+
+		handler.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusUnauthorized, w.Code)
+		assert.Empty(t, received)
+	})
+}
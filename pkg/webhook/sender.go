@@ -0,0 +1,46 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// Sender posts signed webhook bodies. Any response other than 200 OK is
+// reported as an error so the caller can retry.
+type Sender struct {
+	url    string
+	signer *Signer
+	client *http.Client
+}
+
+func NewSender(url string, signer *Signer, timeout time.Duration) *Sender {
+	return &Sender{
+		url:    url,
+		signer: signer,
+		client: &http.Client{Timeout: timeout},
+	}
+}
+
+func (s *Sender) Send(ctx context.Context, body []byte) error {
+	req, err := http.NewRequestWithContext(ctx, "POST", s.url, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to create webhook request: %w", err)
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	s.signer.SignRequest(req.Header, body)
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to send webhook: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("webhook returned status %d", resp.StatusCode)
+	}
+	return nil
+}
@@ -0,0 +1,48 @@
+package webhook
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSender_Send(t *testing.T) {
+	body := []byte(`{"payment_id":"payment-123","booking_id":"booking-123","status":"paid"}`)
+
+	t.Run("success", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			assert.Equal(t, "POST", r.Method)
+			assert.Equal(t, "application/json", r.Header.Get("Content-Type"))
+			received, _ := io.ReadAll(r.Body)
+			assert.Equal(t, body, received)
+			assert.NoError(t, NewVerifier([]string{"test-secret"}, time.Minute).Verify(r.Header, received))
+			w.WriteHeader(http.StatusOK)
+		}))
+		defer server.Close()
+
+		sender := NewSender(server.URL+"/webhook", NewSigner("test-secret"), time.Second)
+		assert.NoError(t, sender.Send(context.Background(), body))
+	})
+
+	t.Run("error status", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusInternalServerError)
+		}))
+		defer server.Close()
+
+		sender := NewSender(server.URL+"/webhook", NewSigner("test-secret"), time.Second)
+		err := sender.Send(context.Background(), body)
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "status 500")
+	})
+
+	t.Run("unreachable receiver", func(t *testing.T) {
+		sender := NewSender("http://invalid-url-that-does-not-exist:9999/webhook", NewSigner("test-secret"), time.Second)
+		assert.Error(t, sender.Send(context.Background(), body))
+	})
+}
@@ -0,0 +1,91 @@
+package webhook
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	HeaderSignature = "X-Webhook-Signature"
+	HeaderTimestamp = "X-Webhook-Timestamp"
+	signaturePrefix = "sha256="
+)
+
+var (
+	ErrMissingSignature = errors.New("webhook signature or timestamp is missing")
+	ErrInvalidSignature = errors.New("webhook signature is invalid")
+	ErrStaleTimestamp   = errors.New("webhook timestamp is outside the allowed window")
+)
+
+// Sign returns the HMAC-SHA256 of "<timestamp>.<body>", so a captured
+// signature cannot be reused with a different timestamp.
+func Sign(secret []byte, timestamp int64, body []byte) string {
+	mac := hmac.New(sha256.New, secret)
+	mac.Write([]byte(strconv.FormatInt(timestamp, 10)))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return signaturePrefix + hex.EncodeToString(mac.Sum(nil))
+}
+
+type Signer struct {
+	secret []byte
+	now    func() time.Time
+}
+
+func NewSigner(secret string) *Signer {
+	return &Signer{secret: []byte(secret), now: time.Now}
+}
+
+func (s *Signer) SignRequest(header http.Header, body []byte) {
+	timestamp := s.now().Unix()
+	header.Set(HeaderTimestamp, strconv.FormatInt(timestamp, 10))
+	header.Set(HeaderSignature, Sign(s.secret, timestamp, body))
+}
+
+// Verifier accepts a signature made with any of its secrets, which lets the
+// sender switch to a new secret while the old one is still configured here.
+type Verifier struct {
+	secrets   [][]byte
+	tolerance time.Duration
+	now       func() time.Time
+}
+
+func NewVerifier(secrets []string, tolerance time.Duration) *Verifier {
+	v := &Verifier{tolerance: tolerance, now: time.Now}
+	for _, secret := range secrets {
+		if secret = strings.TrimSpace(secret); secret != "" {
+			v.secrets = append(v.secrets, []byte(secret))
+		}
+	}
+	return v
+}
+
+func (v *Verifier) Verify(header http.Header, body []byte) error {
+	signature := header.Get(HeaderSignature)
+	rawTimestamp := header.Get(HeaderTimestamp)
+	if signature == "" || rawTimestamp == "" {
+		return ErrMissingSignature
+	}
+
+	timestamp, err := strconv.ParseInt(rawTimestamp, 10, 64)
+	if err != nil {
+		return ErrInvalidSignature
+	}
+	age := v.now().Sub(time.Unix(timestamp, 0))
+	if age > v.tolerance || age < -v.tolerance {
+		return ErrStaleTimestamp
+	}
+
+	for _, secret := range v.secrets {
+		if hmac.Equal([]byte(signature), []byte(Sign(secret, timestamp, body))) {
+			return nil
+		}
+	}
+	return ErrInvalidSignature
+}
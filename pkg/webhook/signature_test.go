@@ -0,0 +1,77 @@
+package webhook
+
+import (
+	"net/http"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func signedHeader(secret string, at time.Time, body []byte) http.Header {
+	signer := NewSigner(secret)
+	signer.now = func() time.Time { return at }
+	header := http.Header{}
+	signer.SignRequest(header, body)
+	return header
+}
+
+func TestVerify(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	body := []byte(`{"booking_id":"booking-123","status":"paid"}`)
+
+	newVerifier := func(secrets ...string) *Verifier {
+		v := NewVerifier(secrets, 5*time.Minute)
+		v.now = func() time.Time { return now }
+		return v
+	}
+
+	t.Run("valid signature", func(t *testing.T) {
+		header := signedHeader("current", now, body)
+		assert.Equal(t, strconv.FormatInt(now.Unix(), 10), header.Get(HeaderTimestamp))
+		assert.NoError(t, newVerifier("current").Verify(header, body))
+	})
+
+	t.Run("any active secret is accepted during rotation", func(t *testing.T) {
+		verifier := newVerifier("next", "current")
+		assert.NoError(t, verifier.Verify(signedHeader("current", now, body), body))
+		assert.NoError(t, verifier.Verify(signedHeader("next", now, body), body))
+	})
+
+	t.Run("retired secret", func(t *testing.T) {
+		err := newVerifier("next").Verify(signedHeader("current", now, body), body)
+		assert.ErrorIs(t, err, ErrInvalidSignature)
+	})
+
+	t.Run("tampered body", func(t *testing.T) {
+		header := signedHeader("current", now, body)
+		err := newVerifier("current").Verify(header, []byte(`{"booking_id":"booking-999","status":"paid"}`))
+		assert.ErrorIs(t, err, ErrInvalidSignature)
+	})
+
+	t.Run("timestamp swapped after signing", func(t *testing.T) {
+		header := signedHeader("current", now.Add(-time.Minute), body)
+		header.Set(HeaderTimestamp, strconv.FormatInt(now.Unix(), 10))
+		assert.ErrorIs(t, newVerifier("current").Verify(header, body), ErrInvalidSignature)
+	})
+
+	t.Run("stale timestamp", func(t *testing.T) {
+		header := signedHeader("current", now.Add(-6*time.Minute), body)
+		assert.ErrorIs(t, newVerifier("current").Verify(header, body), ErrStaleTimestamp)
+	})
+
+	t.Run("timestamp in the future", func(t *testing.T) {
+		header := signedHeader("current", now.Add(6*time.Minute), body)
+		assert.ErrorIs(t, newVerifier("current").Verify(header, body), ErrStaleTimestamp)
+	})
+
+	t.Run("missing headers", func(t *testing.T) {
+		assert.ErrorIs(t, newVerifier("current").Verify(http.Header{}, body), ErrMissingSignature)
+	})
+
+	t.Run("no secrets configured", func(t *testing.T) {
+		header := signedHeader("", now, body)
+		assert.ErrorIs(t, newVerifier(" ", "").Verify(header, body), ErrInvalidSignature)
+	})
+}